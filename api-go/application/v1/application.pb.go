@@ -469,6 +469,86 @@ func (*SetSortOrderResponse) Descriptor() ([]byte, []int) {
 	return file_application_v1_application_proto_rawDescGZIP(), []int{9}
 }
 
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_application_v1_application_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_application_v1_application_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_application_v1_application_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_application_v1_application_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_application_v1_application_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_application_v1_application_proto_rawDescGZIP(), []int{11}
+}
+
 var File_application_v1_application_proto protoreflect.FileDescriptor
 
 const file_application_v1_application_proto_rawDesc = "" +
@@ -499,12 +579,16 @@ const file_application_v1_application_proto_rawDesc = "" +
 	"\x13SetSortOrderRequest\x12 \n" +
 	"\fids_in_order\x18\x01 \x03(\x05R\n" +
 	"idsInOrder\"\x16\n" +
-	"\x14SetSortOrderResponse2\xc4\x02\n" +
+	"\x14SetSortOrderResponse\"\x1f\n" +
+	"\rDeleteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x10\n" +
+	"\x0eDeleteResponse2\x8d\x03\n" +
 	"\x12ApplicationService\x12G\n" +
 	"\x06Create\x12\x1d.application.v1.CreateRequest\x1a\x1e.application.v1.CreateResponse\x12A\n" +
 	"\x04List\x12\x1b.application.v1.ListRequest\x1a\x1c.application.v1.ListResponse\x12G\n" +
 	"\x06Update\x12\x1d.application.v1.UpdateRequest\x1a\x1e.application.v1.UpdateResponse\x12Y\n" +
-	"\fSetSortOrder\x12#.application.v1.SetSortOrderRequest\x1a$.application.v1.SetSortOrderResponseB\xbf\x01\n" +
+	"\fSetSortOrder\x12#.application.v1.SetSortOrderRequest\x1a$.application.v1.SetSortOrderResponse\x12G\n" +
+	"\x06Delete\x12\x1d.application.v1.DeleteRequest\x1a\x1e.application.v1.DeleteResponseB\xbf\x01\n" +
 	"\x12com.application.v1B\x10ApplicationProtoP\x01Z>github.com/theleeeo/overseer/api-go/application/v1;application\xa2\x02\x03AXX\xaa\x02\x0eApplication.V1\xca\x02\x0eApplication\\V1\xe2\x02\x1aApplication\\V1\\GPBMetadata\xea\x02\x0fApplication::V1b\x06proto3"
 
 var (
@@ -519,7 +603,7 @@ func file_application_v1_application_proto_rawDescGZIP() []byte {
 	return file_application_v1_application_proto_rawDescData
 }
 
-var file_application_v1_application_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_application_v1_application_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_application_v1_application_proto_goTypes = []any{
 	(*Application)(nil),          // 0: application.v1.Application
 	(*ResponsePagination)(nil),   // 1: application.v1.ResponsePagination
@@ -531,23 +615,27 @@ var file_application_v1_application_proto_goTypes = []any{
 	(*UpdateResponse)(nil),       // 7: application.v1.UpdateResponse
 	(*SetSortOrderRequest)(nil),  // 8: application.v1.SetSortOrderRequest
 	(*SetSortOrderResponse)(nil), // 9: application.v1.SetSortOrderResponse
+	(*DeleteRequest)(nil),        // 10: application.v1.DeleteRequest
+	(*DeleteResponse)(nil),       // 11: application.v1.DeleteResponse
 }
 var file_application_v1_application_proto_depIdxs = []int32{
-	0, // 0: application.v1.ListResponse.applications:type_name -> application.v1.Application
-	1, // 1: application.v1.ListResponse.pagination:type_name -> application.v1.ResponsePagination
-	2, // 2: application.v1.ApplicationService.Create:input_type -> application.v1.CreateRequest
-	4, // 3: application.v1.ApplicationService.List:input_type -> application.v1.ListRequest
-	6, // 4: application.v1.ApplicationService.Update:input_type -> application.v1.UpdateRequest
-	8, // 5: application.v1.ApplicationService.SetSortOrder:input_type -> application.v1.SetSortOrderRequest
-	3, // 6: application.v1.ApplicationService.Create:output_type -> application.v1.CreateResponse
-	5, // 7: application.v1.ApplicationService.List:output_type -> application.v1.ListResponse
-	7, // 8: application.v1.ApplicationService.Update:output_type -> application.v1.UpdateResponse
-	9, // 9: application.v1.ApplicationService.SetSortOrder:output_type -> application.v1.SetSortOrderResponse
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: application.v1.ListResponse.applications:type_name -> application.v1.Application
+	1,  // 1: application.v1.ListResponse.pagination:type_name -> application.v1.ResponsePagination
+	2,  // 2: application.v1.ApplicationService.Create:input_type -> application.v1.CreateRequest
+	4,  // 3: application.v1.ApplicationService.List:input_type -> application.v1.ListRequest
+	6,  // 4: application.v1.ApplicationService.Update:input_type -> application.v1.UpdateRequest
+	8,  // 5: application.v1.ApplicationService.SetSortOrder:input_type -> application.v1.SetSortOrderRequest
+	10, // 6: application.v1.ApplicationService.Delete:input_type -> application.v1.DeleteRequest
+	3,  // 7: application.v1.ApplicationService.Create:output_type -> application.v1.CreateResponse
+	5,  // 8: application.v1.ApplicationService.List:output_type -> application.v1.ListResponse
+	7,  // 9: application.v1.ApplicationService.Update:output_type -> application.v1.UpdateResponse
+	9,  // 10: application.v1.ApplicationService.SetSortOrder:output_type -> application.v1.SetSortOrderResponse
+	11, // 11: application.v1.ApplicationService.Delete:output_type -> application.v1.DeleteResponse
+	7,  // [7:12] is the sub-list for method output_type
+	2,  // [2:7] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_application_v1_application_proto_init() }
@@ -562,7 +650,7 @@ func file_application_v1_application_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_application_v1_application_proto_rawDesc), len(file_application_v1_application_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -23,6 +23,7 @@ const (
 	ApplicationService_List_FullMethodName         = "/application.v1.ApplicationService/List"
 	ApplicationService_Update_FullMethodName       = "/application.v1.ApplicationService/Update"
 	ApplicationService_SetSortOrder_FullMethodName = "/application.v1.ApplicationService/SetSortOrder"
+	ApplicationService_Delete_FullMethodName       = "/application.v1.ApplicationService/Delete"
 )
 
 // ApplicationServiceClient is the client API for ApplicationService service.
@@ -33,6 +34,7 @@ type ApplicationServiceClient interface {
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
 	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
 	SetSortOrder(ctx context.Context, in *SetSortOrderRequest, opts ...grpc.CallOption) (*SetSortOrderResponse, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
 }
 
 type applicationServiceClient struct {
@@ -83,6 +85,16 @@ func (c *applicationServiceClient) SetSortOrder(ctx context.Context, in *SetSort
 	return out, nil
 }
 
+func (c *applicationServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, ApplicationService_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ApplicationServiceServer is the server API for ApplicationService service.
 // All implementations should embed UnimplementedApplicationServiceServer
 // for forward compatibility.
@@ -91,6 +103,7 @@ type ApplicationServiceServer interface {
 	List(context.Context, *ListRequest) (*ListResponse, error)
 	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
 	SetSortOrder(context.Context, *SetSortOrderRequest) (*SetSortOrderResponse, error)
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
 }
 
 // UnimplementedApplicationServiceServer should be embedded to have
@@ -112,6 +125,9 @@ func (UnimplementedApplicationServiceServer) Update(context.Context, *UpdateRequ
 func (UnimplementedApplicationServiceServer) SetSortOrder(context.Context, *SetSortOrderRequest) (*SetSortOrderResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetSortOrder not implemented")
 }
+func (UnimplementedApplicationServiceServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
 func (UnimplementedApplicationServiceServer) testEmbeddedByValue() {}
 
 // UnsafeApplicationServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -204,6 +220,24 @@ func _ApplicationService_SetSortOrder_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ApplicationService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApplicationServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ApplicationService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApplicationServiceServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ApplicationService_ServiceDesc is the grpc.ServiceDesc for ApplicationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -227,6 +261,10 @@ var ApplicationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetSortOrder",
 			Handler:    _ApplicationService_SetSortOrder_Handler,
 		},
+		{
+			MethodName: "Delete",
+			Handler:    _ApplicationService_Delete_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "application/v1/application.proto",
@@ -0,0 +1,588 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: datasource/v1/datasource.proto
+
+package datasource
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A source of deployment events, like a Nomad cluster or a Docker host.
+type Datasource struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The kind of source, like nomad or docker.
+	Type    string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Enabled bool   `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// The events of the source are being streamed.
+	Running bool `protobuf:"varint,4,opt,name=running,proto3" json:"running,omitempty"`
+	// Why the source last stopped, it is restarted after a delay.
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// When the source was last started.
+	StartedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	// The number of events received since the server started.
+	Events        int64                  `protobuf:"varint,7,opt,name=events,proto3" json:"events,omitempty"`
+	LastEventAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_event_at,json=lastEventAt,proto3" json:"last_event_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Datasource) Reset() {
+	*x = Datasource{}
+	mi := &file_datasource_v1_datasource_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Datasource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Datasource) ProtoMessage() {}
+
+func (x *Datasource) ProtoReflect() protoreflect.Message {
+	mi := &file_datasource_v1_datasource_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Datasource.ProtoReflect.Descriptor instead.
+func (*Datasource) Descriptor() ([]byte, []int) {
+	return file_datasource_v1_datasource_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Datasource) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Datasource) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Datasource) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Datasource) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *Datasource) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Datasource) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Datasource) GetEvents() int64 {
+	if x != nil {
+		return x.Events
+	}
+	return 0
+}
+
+func (x *Datasource) GetLastEventAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastEventAt
+	}
+	return nil
+}
+
+type ListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_datasource_v1_datasource_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_datasource_v1_datasource_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_datasource_v1_datasource_proto_rawDescGZIP(), []int{1}
+}
+
+type ListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Datasources   []*Datasource          `protobuf:"bytes,1,rep,name=datasources,proto3" json:"datasources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_datasource_v1_datasource_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_datasource_v1_datasource_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_datasource_v1_datasource_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListResponse) GetDatasources() []*Datasource {
+	if x != nil {
+		return x.Datasources
+	}
+	return nil
+}
+
+type EnableRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableRequest) Reset() {
+	*x = EnableRequest{}
+	mi := &file_datasource_v1_datasource_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableRequest) ProtoMessage() {}
+
+func (x *EnableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_datasource_v1_datasource_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableRequest.ProtoReflect.Descriptor instead.
+func (*EnableRequest) Descriptor() ([]byte, []int) {
+	return file_datasource_v1_datasource_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EnableRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type EnableResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Datasource    *Datasource            `protobuf:"bytes,1,opt,name=datasource,proto3" json:"datasource,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableResponse) Reset() {
+	*x = EnableResponse{}
+	mi := &file_datasource_v1_datasource_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableResponse) ProtoMessage() {}
+
+func (x *EnableResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_datasource_v1_datasource_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableResponse.ProtoReflect.Descriptor instead.
+func (*EnableResponse) Descriptor() ([]byte, []int) {
+	return file_datasource_v1_datasource_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EnableResponse) GetDatasource() *Datasource {
+	if x != nil {
+		return x.Datasource
+	}
+	return nil
+}
+
+type DisableRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableRequest) Reset() {
+	*x = DisableRequest{}
+	mi := &file_datasource_v1_datasource_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableRequest) ProtoMessage() {}
+
+func (x *DisableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_datasource_v1_datasource_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableRequest.ProtoReflect.Descriptor instead.
+func (*DisableRequest) Descriptor() ([]byte, []int) {
+	return file_datasource_v1_datasource_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DisableRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DisableResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Datasource    *Datasource            `protobuf:"bytes,1,opt,name=datasource,proto3" json:"datasource,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableResponse) Reset() {
+	*x = DisableResponse{}
+	mi := &file_datasource_v1_datasource_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableResponse) ProtoMessage() {}
+
+func (x *DisableResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_datasource_v1_datasource_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableResponse.ProtoReflect.Descriptor instead.
+func (*DisableResponse) Descriptor() ([]byte, []int) {
+	return file_datasource_v1_datasource_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DisableResponse) GetDatasource() *Datasource {
+	if x != nil {
+		return x.Datasource
+	}
+	return nil
+}
+
+type RestartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestartRequest) Reset() {
+	*x = RestartRequest{}
+	mi := &file_datasource_v1_datasource_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartRequest) ProtoMessage() {}
+
+func (x *RestartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_datasource_v1_datasource_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartRequest.ProtoReflect.Descriptor instead.
+func (*RestartRequest) Descriptor() ([]byte, []int) {
+	return file_datasource_v1_datasource_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RestartRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RestartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Datasource    *Datasource            `protobuf:"bytes,1,opt,name=datasource,proto3" json:"datasource,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestartResponse) Reset() {
+	*x = RestartResponse{}
+	mi := &file_datasource_v1_datasource_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartResponse) ProtoMessage() {}
+
+func (x *RestartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_datasource_v1_datasource_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartResponse.ProtoReflect.Descriptor instead.
+func (*RestartResponse) Descriptor() ([]byte, []int) {
+	return file_datasource_v1_datasource_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RestartResponse) GetDatasource() *Datasource {
+	if x != nil {
+		return x.Datasource
+	}
+	return nil
+}
+
+var File_datasource_v1_datasource_proto protoreflect.FileDescriptor
+
+const file_datasource_v1_datasource_proto_rawDesc = "" +
+	"\n" +
+	"\x1edatasource/v1/datasource.proto\x12\rdatasource.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x91\x02\n" +
+	"\n" +
+	"Datasource\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x18\n" +
+	"\aenabled\x18\x03 \x01(\bR\aenabled\x12\x18\n" +
+	"\arunning\x18\x04 \x01(\bR\arunning\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"started_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12\x16\n" +
+	"\x06events\x18\a \x01(\x03R\x06events\x12>\n" +
+	"\rlast_event_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vlastEventAt\"\r\n" +
+	"\vListRequest\"K\n" +
+	"\fListResponse\x12;\n" +
+	"\vdatasources\x18\x01 \x03(\v2\x19.datasource.v1.DatasourceR\vdatasources\"#\n" +
+	"\rEnableRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"K\n" +
+	"\x0eEnableResponse\x129\n" +
+	"\n" +
+	"datasource\x18\x01 \x01(\v2\x19.datasource.v1.DatasourceR\n" +
+	"datasource\"$\n" +
+	"\x0eDisableRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"L\n" +
+	"\x0fDisableResponse\x129\n" +
+	"\n" +
+	"datasource\x18\x01 \x01(\v2\x19.datasource.v1.DatasourceR\n" +
+	"datasource\"$\n" +
+	"\x0eRestartRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"L\n" +
+	"\x0fRestartResponse\x129\n" +
+	"\n" +
+	"datasource\x18\x01 \x01(\v2\x19.datasource.v1.DatasourceR\n" +
+	"datasource2\xaf\x02\n" +
+	"\x11DatasourceService\x12?\n" +
+	"\x04List\x12\x1a.datasource.v1.ListRequest\x1a\x1b.datasource.v1.ListResponse\x12E\n" +
+	"\x06Enable\x12\x1c.datasource.v1.EnableRequest\x1a\x1d.datasource.v1.EnableResponse\x12H\n" +
+	"\aDisable\x12\x1d.datasource.v1.DisableRequest\x1a\x1e.datasource.v1.DisableResponse\x12H\n" +
+	"\aRestart\x12\x1d.datasource.v1.RestartRequest\x1a\x1e.datasource.v1.RestartResponseB\xb7\x01\n" +
+	"\x11com.datasource.v1B\x0fDatasourceProtoP\x01Z<github.com/theleeeo/overseer/api-go/datasource/v1;datasource\xa2\x02\x03DXX\xaa\x02\rDatasource.V1\xca\x02\rDatasource\\V1\xe2\x02\x19Datasource\\V1\\GPBMetadata\xea\x02\x0eDatasource::V1b\x06proto3"
+
+var (
+	file_datasource_v1_datasource_proto_rawDescOnce sync.Once
+	file_datasource_v1_datasource_proto_rawDescData []byte
+)
+
+func file_datasource_v1_datasource_proto_rawDescGZIP() []byte {
+	file_datasource_v1_datasource_proto_rawDescOnce.Do(func() {
+		file_datasource_v1_datasource_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_datasource_v1_datasource_proto_rawDesc), len(file_datasource_v1_datasource_proto_rawDesc)))
+	})
+	return file_datasource_v1_datasource_proto_rawDescData
+}
+
+var file_datasource_v1_datasource_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_datasource_v1_datasource_proto_goTypes = []any{
+	(*Datasource)(nil),            // 0: datasource.v1.Datasource
+	(*ListRequest)(nil),           // 1: datasource.v1.ListRequest
+	(*ListResponse)(nil),          // 2: datasource.v1.ListResponse
+	(*EnableRequest)(nil),         // 3: datasource.v1.EnableRequest
+	(*EnableResponse)(nil),        // 4: datasource.v1.EnableResponse
+	(*DisableRequest)(nil),        // 5: datasource.v1.DisableRequest
+	(*DisableResponse)(nil),       // 6: datasource.v1.DisableResponse
+	(*RestartRequest)(nil),        // 7: datasource.v1.RestartRequest
+	(*RestartResponse)(nil),       // 8: datasource.v1.RestartResponse
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_datasource_v1_datasource_proto_depIdxs = []int32{
+	9,  // 0: datasource.v1.Datasource.started_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: datasource.v1.Datasource.last_event_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: datasource.v1.ListResponse.datasources:type_name -> datasource.v1.Datasource
+	0,  // 3: datasource.v1.EnableResponse.datasource:type_name -> datasource.v1.Datasource
+	0,  // 4: datasource.v1.DisableResponse.datasource:type_name -> datasource.v1.Datasource
+	0,  // 5: datasource.v1.RestartResponse.datasource:type_name -> datasource.v1.Datasource
+	1,  // 6: datasource.v1.DatasourceService.List:input_type -> datasource.v1.ListRequest
+	3,  // 7: datasource.v1.DatasourceService.Enable:input_type -> datasource.v1.EnableRequest
+	5,  // 8: datasource.v1.DatasourceService.Disable:input_type -> datasource.v1.DisableRequest
+	7,  // 9: datasource.v1.DatasourceService.Restart:input_type -> datasource.v1.RestartRequest
+	2,  // 10: datasource.v1.DatasourceService.List:output_type -> datasource.v1.ListResponse
+	4,  // 11: datasource.v1.DatasourceService.Enable:output_type -> datasource.v1.EnableResponse
+	6,  // 12: datasource.v1.DatasourceService.Disable:output_type -> datasource.v1.DisableResponse
+	8,  // 13: datasource.v1.DatasourceService.Restart:output_type -> datasource.v1.RestartResponse
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_datasource_v1_datasource_proto_init() }
+func file_datasource_v1_datasource_proto_init() {
+	if File_datasource_v1_datasource_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_datasource_v1_datasource_proto_rawDesc), len(file_datasource_v1_datasource_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_datasource_v1_datasource_proto_goTypes,
+		DependencyIndexes: file_datasource_v1_datasource_proto_depIdxs,
+		MessageInfos:      file_datasource_v1_datasource_proto_msgTypes,
+	}.Build()
+	File_datasource_v1_datasource_proto = out.File
+	file_datasource_v1_datasource_proto_goTypes = nil
+	file_datasource_v1_datasource_proto_depIdxs = nil
+}
@@ -23,10 +23,31 @@ const (
 )
 
 type Deployment struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	InstanceId    int32                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
-	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	DeployedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=deployed_at,json=deployedAt,proto3" json:"deployed_at,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId int32                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Version    string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// The time of the deployment as reported by the source.
+	DeployedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=deployed_at,json=deployedAt,proto3" json:"deployed_at,omitempty"`
+	// The time the deployment was registered.
+	ReceivedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=received_at,json=receivedAt,proto3" json:"received_at,omitempty"`
+	// The monotonic sequence number supplied by the source, 0 if it has none.
+	Sequence int64 `protobuf:"varint,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// A deployment ordered after this one was already current when it was registered.
+	Late bool `protobuf:"varint,6,opt,name=late,proto3" json:"late,omitempty"`
+	// The reported deployment time was too far ahead of the time it was received.
+	ClockSkew bool `protobuf:"varint,7,opt,name=clock_skew,json=clockSkew,proto3" json:"clock_skew,omitempty"`
+	// This is the current deployment of the instance.
+	Current  bool                `protobuf:"varint,8,opt,name=current,proto3" json:"current,omitempty"`
+	Id       string              `protobuf:"bytes,9,opt,name=id,proto3" json:"id,omitempty"`
+	Metadata *DeploymentMetadata `protobuf:"bytes,10,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// The component of the instance that was deployed, like main, sidecar or migrator.
+	Component string `protobuf:"bytes,11,opt,name=component,proto3" json:"component,omitempty"`
+	// The component was stopped or removed, the version is empty.
+	Undeployed bool `protobuf:"varint,12,opt,name=undeployed,proto3" json:"undeployed,omitempty"`
+	// The name of the source that reported the deployment, empty if it was registered through the API.
+	Source string `protobuf:"bytes,13,opt,name=source,proto3" json:"source,omitempty"`
+	// The name of the freeze window the deployment was made in, empty if there was none.
+	Freeze        string `protobuf:"bytes,14,opt,name=freeze,proto3" json:"freeze,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -82,6 +103,172 @@ func (x *Deployment) GetDeployedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Deployment) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *Deployment) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *Deployment) GetLate() bool {
+	if x != nil {
+		return x.Late
+	}
+	return false
+}
+
+func (x *Deployment) GetClockSkew() bool {
+	if x != nil {
+		return x.ClockSkew
+	}
+	return false
+}
+
+func (x *Deployment) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+func (x *Deployment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Deployment) GetMetadata() *DeploymentMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Deployment) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *Deployment) GetUndeployed() bool {
+	if x != nil {
+		return x.Undeployed
+	}
+	return false
+}
+
+func (x *Deployment) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Deployment) GetFreeze() string {
+	if x != nil {
+		return x.Freeze
+	}
+	return ""
+}
+
+// Describes what was deployed and who deployed it, every field is optional.
+type DeploymentMetadata struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The reference of the deployed container image, without the digest.
+	Image       string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	ImageDigest string `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	GitCommit   string `protobuf:"bytes,3,opt,name=git_commit,json=gitCommit,proto3" json:"git_commit,omitempty"`
+	// Links to the CI build that produced the deployment.
+	BuildUrl string `protobuf:"bytes,4,opt,name=build_url,json=buildUrl,proto3" json:"build_url,omitempty"`
+	// The person or pipeline that made the deployment.
+	Deployer string `protobuf:"bytes,5,opt,name=deployer,proto3" json:"deployer,omitempty"`
+	// Free-form metadata from the source.
+	Labels        map[string]string `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeploymentMetadata) Reset() {
+	*x = DeploymentMetadata{}
+	mi := &file_deployment_v1_deployment_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeploymentMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeploymentMetadata) ProtoMessage() {}
+
+func (x *DeploymentMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_deployment_v1_deployment_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeploymentMetadata.ProtoReflect.Descriptor instead.
+func (*DeploymentMetadata) Descriptor() ([]byte, []int) {
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DeploymentMetadata) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *DeploymentMetadata) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+func (x *DeploymentMetadata) GetGitCommit() string {
+	if x != nil {
+		return x.GitCommit
+	}
+	return ""
+}
+
+func (x *DeploymentMetadata) GetBuildUrl() string {
+	if x != nil {
+		return x.BuildUrl
+	}
+	return ""
+}
+
+func (x *DeploymentMetadata) GetDeployer() string {
+	if x != nil {
+		return x.Deployer
+	}
+	return ""
+}
+
+func (x *DeploymentMetadata) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
 type ResponsePagination struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Total         int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
@@ -91,7 +278,7 @@ type ResponsePagination struct {
 
 func (x *ResponsePagination) Reset() {
 	*x = ResponsePagination{}
-	mi := &file_deployment_v1_deployment_proto_msgTypes[1]
+	mi := &file_deployment_v1_deployment_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -103,7 +290,7 @@ func (x *ResponsePagination) String() string {
 func (*ResponsePagination) ProtoMessage() {}
 
 func (x *ResponsePagination) ProtoReflect() protoreflect.Message {
-	mi := &file_deployment_v1_deployment_proto_msgTypes[1]
+	mi := &file_deployment_v1_deployment_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -116,7 +303,7 @@ func (x *ResponsePagination) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResponsePagination.ProtoReflect.Descriptor instead.
 func (*ResponsePagination) Descriptor() ([]byte, []int) {
-	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{1}
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ResponsePagination) GetTotal() int32 {
@@ -130,16 +317,26 @@ type RegisterRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// TODO: Instance Selector? Allowing it to be selected using name, id or
 	// env-app combo.
-	InstanceId    int32                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
-	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	DeployedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=deployed_at,json=deployedAt,proto3" json:"deployed_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	InstanceId int32                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Version    string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	DeployedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=deployed_at,json=deployedAt,proto3" json:"deployed_at,omitempty"`
+	// An optional monotonic sequence number from the source, like the Nomad raft index.
+	// When both deployments have one, it takes precedence over deployed_at for ordering.
+	Sequence int64               `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Metadata *DeploymentMetadata `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// Defaults to the primary component of the instance.
+	Component string `protobuf:"bytes,6,opt,name=component,proto3" json:"component,omitempty"`
+	// Registers that the component was stopped or removed instead of a version.
+	Undeployed bool `protobuf:"varint,7,opt,name=undeployed,proto3" json:"undeployed,omitempty"`
+	// Registers the deployment even if it is made during a freeze window that rejects deployments.
+	OverrideFreeze bool `protobuf:"varint,8,opt,name=override_freeze,json=overrideFreeze,proto3" json:"override_freeze,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *RegisterRequest) Reset() {
 	*x = RegisterRequest{}
-	mi := &file_deployment_v1_deployment_proto_msgTypes[2]
+	mi := &file_deployment_v1_deployment_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -151,7 +348,7 @@ func (x *RegisterRequest) String() string {
 func (*RegisterRequest) ProtoMessage() {}
 
 func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_deployment_v1_deployment_proto_msgTypes[2]
+	mi := &file_deployment_v1_deployment_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -164,7 +361,7 @@ func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
 func (*RegisterRequest) Descriptor() ([]byte, []int) {
-	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{2}
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *RegisterRequest) GetInstanceId() int32 {
@@ -188,15 +385,51 @@ func (x *RegisterRequest) GetDeployedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *RegisterRequest) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *RegisterRequest) GetMetadata() *DeploymentMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RegisterRequest) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetUndeployed() bool {
+	if x != nil {
+		return x.Undeployed
+	}
+	return false
+}
+
+func (x *RegisterRequest) GetOverrideFreeze() bool {
+	if x != nil {
+		return x.OverrideFreeze
+	}
+	return false
+}
+
 type RegisterResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deployment    *Deployment            `protobuf:"bytes,1,opt,name=deployment,proto3" json:"deployment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RegisterResponse) Reset() {
 	*x = RegisterResponse{}
-	mi := &file_deployment_v1_deployment_proto_msgTypes[3]
+	mi := &file_deployment_v1_deployment_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -208,7 +441,7 @@ func (x *RegisterResponse) String() string {
 func (*RegisterResponse) ProtoMessage() {}
 
 func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_deployment_v1_deployment_proto_msgTypes[3]
+	mi := &file_deployment_v1_deployment_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -221,7 +454,14 @@ func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
 func (*RegisterResponse) Descriptor() ([]byte, []int) {
-	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{3}
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RegisterResponse) GetDeployment() *Deployment {
+	if x != nil {
+		return x.Deployment
+	}
+	return nil
 }
 
 type ListRequest struct {
@@ -232,7 +472,7 @@ type ListRequest struct {
 
 func (x *ListRequest) Reset() {
 	*x = ListRequest{}
-	mi := &file_deployment_v1_deployment_proto_msgTypes[4]
+	mi := &file_deployment_v1_deployment_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -244,7 +484,7 @@ func (x *ListRequest) String() string {
 func (*ListRequest) ProtoMessage() {}
 
 func (x *ListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_deployment_v1_deployment_proto_msgTypes[4]
+	mi := &file_deployment_v1_deployment_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -257,7 +497,7 @@ func (x *ListRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
 func (*ListRequest) Descriptor() ([]byte, []int) {
-	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{4}
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{5}
 }
 
 type ListResponse struct {
@@ -270,7 +510,7 @@ type ListResponse struct {
 
 func (x *ListResponse) Reset() {
 	*x = ListResponse{}
-	mi := &file_deployment_v1_deployment_proto_msgTypes[5]
+	mi := &file_deployment_v1_deployment_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -282,7 +522,7 @@ func (x *ListResponse) String() string {
 func (*ListResponse) ProtoMessage() {}
 
 func (x *ListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_deployment_v1_deployment_proto_msgTypes[5]
+	mi := &file_deployment_v1_deployment_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -295,7 +535,7 @@ func (x *ListResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
 func (*ListResponse) Descriptor() ([]byte, []int) {
-	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{5}
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ListResponse) GetDeployments() []*Deployment {
@@ -312,36 +552,625 @@ func (x *ListResponse) GetPagination() *ResponsePagination {
 	return nil
 }
 
+// The progress of rolling out a version of an instance component.
+// The current deployment is the desired version, the rollout tells whether it is actually running.
+type Rollout struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId int32                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Component  string                 `protobuf:"bytes,2,opt,name=component,proto3" json:"component,omitempty"`
+	// The version being rolled out.
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// One of pending, running, paused, successful, failed or cancelled.
+	Status string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	// Explains the status, like why the rollout failed.
+	Description string `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	// The number of healthy and desired replicas, both 0 if unknown.
+	Healthy int32 `protobuf:"varint,6,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	Desired int32 `protobuf:"varint,7,opt,name=desired,proto3" json:"desired,omitempty"`
+	// The last version that was successfully rolled out, empty if unknown.
+	RunningVersion string                 `protobuf:"bytes,8,opt,name=running_version,json=runningVersion,proto3" json:"running_version,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Rollout) Reset() {
+	*x = Rollout{}
+	mi := &file_deployment_v1_deployment_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rollout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rollout) ProtoMessage() {}
+
+func (x *Rollout) ProtoReflect() protoreflect.Message {
+	mi := &file_deployment_v1_deployment_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rollout.ProtoReflect.Descriptor instead.
+func (*Rollout) Descriptor() ([]byte, []int) {
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Rollout) GetInstanceId() int32 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *Rollout) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *Rollout) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Rollout) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Rollout) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Rollout) GetHealthy() int32 {
+	if x != nil {
+		return x.Healthy
+	}
+	return 0
+}
+
+func (x *Rollout) GetDesired() int32 {
+	if x != nil {
+		return x.Desired
+	}
+	return 0
+}
+
+func (x *Rollout) GetRunningVersion() string {
+	if x != nil {
+		return x.RunningVersion
+	}
+	return ""
+}
+
+func (x *Rollout) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListRolloutsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolloutsRequest) Reset() {
+	*x = ListRolloutsRequest{}
+	mi := &file_deployment_v1_deployment_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolloutsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolloutsRequest) ProtoMessage() {}
+
+func (x *ListRolloutsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_deployment_v1_deployment_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolloutsRequest.ProtoReflect.Descriptor instead.
+func (*ListRolloutsRequest) Descriptor() ([]byte, []int) {
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{8}
+}
+
+type ListRolloutsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rollouts      []*Rollout             `protobuf:"bytes,1,rep,name=rollouts,proto3" json:"rollouts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolloutsResponse) Reset() {
+	*x = ListRolloutsResponse{}
+	mi := &file_deployment_v1_deployment_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolloutsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolloutsResponse) ProtoMessage() {}
+
+func (x *ListRolloutsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_deployment_v1_deployment_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolloutsResponse.ProtoReflect.Descriptor instead.
+func (*ListRolloutsResponse) Descriptor() ([]byte, []int) {
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListRolloutsResponse) GetRollouts() []*Rollout {
+	if x != nil {
+		return x.Rollouts
+	}
+	return nil
+}
+
+type ChangelogRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApplicationId int32                  `protobuf:"varint,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	// The versions to compare, usually deployed ones.
+	From string `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To   string `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	// The maximum number of commits to list, defaults to 250.
+	MaxCommits    int32 `protobuf:"varint,4,opt,name=max_commits,json=maxCommits,proto3" json:"max_commits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangelogRequest) Reset() {
+	*x = ChangelogRequest{}
+	mi := &file_deployment_v1_deployment_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangelogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangelogRequest) ProtoMessage() {}
+
+func (x *ChangelogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_deployment_v1_deployment_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangelogRequest.ProtoReflect.Descriptor instead.
+func (*ChangelogRequest) Descriptor() ([]byte, []int) {
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ChangelogRequest) GetApplicationId() int32 {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return 0
+}
+
+func (x *ChangelogRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ChangelogRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ChangelogRequest) GetMaxCommits() int32 {
+	if x != nil {
+		return x.MaxCommits
+	}
+	return 0
+}
+
+type ChangelogResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The commits the versions were resolved to.
+	FromCommit string `protobuf:"bytes,1,opt,name=from_commit,json=fromCommit,proto3" json:"from_commit,omitempty"`
+	ToCommit   string `protobuf:"bytes,2,opt,name=to_commit,json=toCommit,proto3" json:"to_commit,omitempty"`
+	// The commits in the to version but not in the from version, newest first.
+	Commits []*Commit `protobuf:"bytes,3,rep,name=commits,proto3" json:"commits,omitempty"`
+	// There were more commits than the ones listed.
+	Truncated bool          `protobuf:"varint,4,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	Files     []*FileChange `protobuf:"bytes,5,rep,name=files,proto3" json:"files,omitempty"`
+	// The total number of changed lines.
+	Additions     int32 `protobuf:"varint,6,opt,name=additions,proto3" json:"additions,omitempty"`
+	Deletions     int32 `protobuf:"varint,7,opt,name=deletions,proto3" json:"deletions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangelogResponse) Reset() {
+	*x = ChangelogResponse{}
+	mi := &file_deployment_v1_deployment_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangelogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangelogResponse) ProtoMessage() {}
+
+func (x *ChangelogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_deployment_v1_deployment_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangelogResponse.ProtoReflect.Descriptor instead.
+func (*ChangelogResponse) Descriptor() ([]byte, []int) {
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ChangelogResponse) GetFromCommit() string {
+	if x != nil {
+		return x.FromCommit
+	}
+	return ""
+}
+
+func (x *ChangelogResponse) GetToCommit() string {
+	if x != nil {
+		return x.ToCommit
+	}
+	return ""
+}
+
+func (x *ChangelogResponse) GetCommits() []*Commit {
+	if x != nil {
+		return x.Commits
+	}
+	return nil
+}
+
+func (x *ChangelogResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *ChangelogResponse) GetFiles() []*FileChange {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ChangelogResponse) GetAdditions() int32 {
+	if x != nil {
+		return x.Additions
+	}
+	return 0
+}
+
+func (x *ChangelogResponse) GetDeletions() int32 {
+	if x != nil {
+		return x.Deletions
+	}
+	return 0
+}
+
+type Commit struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Hash        string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Author      string                 `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	AuthorEmail string                 `protobuf:"bytes,3,opt,name=author_email,json=authorEmail,proto3" json:"author_email,omitempty"`
+	// When the commit was authored.
+	Date          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=date,proto3" json:"date,omitempty"`
+	Subject       string                 `protobuf:"bytes,5,opt,name=subject,proto3" json:"subject,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Commit) Reset() {
+	*x = Commit{}
+	mi := &file_deployment_v1_deployment_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Commit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Commit) ProtoMessage() {}
+
+func (x *Commit) ProtoReflect() protoreflect.Message {
+	mi := &file_deployment_v1_deployment_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Commit.ProtoReflect.Descriptor instead.
+func (*Commit) Descriptor() ([]byte, []int) {
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Commit) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Commit) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Commit) GetAuthorEmail() string {
+	if x != nil {
+		return x.AuthorEmail
+	}
+	return ""
+}
+
+func (x *Commit) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *Commit) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+// The changes to a file.
+type FileChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Path  string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// The number of changed lines, both 0 for binary files.
+	Additions     int32 `protobuf:"varint,2,opt,name=additions,proto3" json:"additions,omitempty"`
+	Deletions     int32 `protobuf:"varint,3,opt,name=deletions,proto3" json:"deletions,omitempty"`
+	Binary        bool  `protobuf:"varint,4,opt,name=binary,proto3" json:"binary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileChange) Reset() {
+	*x = FileChange{}
+	mi := &file_deployment_v1_deployment_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChange) ProtoMessage() {}
+
+func (x *FileChange) ProtoReflect() protoreflect.Message {
+	mi := &file_deployment_v1_deployment_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChange.ProtoReflect.Descriptor instead.
+func (*FileChange) Descriptor() ([]byte, []int) {
+	return file_deployment_v1_deployment_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *FileChange) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileChange) GetAdditions() int32 {
+	if x != nil {
+		return x.Additions
+	}
+	return 0
+}
+
+func (x *FileChange) GetDeletions() int32 {
+	if x != nil {
+		return x.Deletions
+	}
+	return 0
+}
+
+func (x *FileChange) GetBinary() bool {
+	if x != nil {
+		return x.Binary
+	}
+	return false
+}
+
 var File_deployment_v1_deployment_proto protoreflect.FileDescriptor
 
 const file_deployment_v1_deployment_proto_rawDesc = "" +
 	"\n" +
-	"\x1edeployment/v1/deployment.proto\x12\rdeployment.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x84\x01\n" +
+	"\x1edeployment/v1/deployment.proto\x12\rdeployment.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe7\x03\n" +
 	"\n" +
 	"Deployment\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x05R\n" +
 	"instanceId\x12\x18\n" +
 	"\aversion\x18\x02 \x01(\tR\aversion\x12;\n" +
 	"\vdeployed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
-	"deployedAt\"*\n" +
+	"deployedAt\x12;\n" +
+	"\vreceived_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"receivedAt\x12\x1a\n" +
+	"\bsequence\x18\x05 \x01(\x03R\bsequence\x12\x12\n" +
+	"\x04late\x18\x06 \x01(\bR\x04late\x12\x1d\n" +
+	"\n" +
+	"clock_skew\x18\a \x01(\bR\tclockSkew\x12\x18\n" +
+	"\acurrent\x18\b \x01(\bR\acurrent\x12\x0e\n" +
+	"\x02id\x18\t \x01(\tR\x02id\x12=\n" +
+	"\bmetadata\x18\n" +
+	" \x01(\v2!.deployment.v1.DeploymentMetadataR\bmetadata\x12\x1c\n" +
+	"\tcomponent\x18\v \x01(\tR\tcomponent\x12\x1e\n" +
+	"\n" +
+	"undeployed\x18\f \x01(\bR\n" +
+	"undeployed\x12\x16\n" +
+	"\x06source\x18\r \x01(\tR\x06source\x12\x16\n" +
+	"\x06freeze\x18\x0e \x01(\tR\x06freeze\"\xa7\x02\n" +
+	"\x12DeploymentMetadata\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\tR\x05image\x12!\n" +
+	"\fimage_digest\x18\x02 \x01(\tR\vimageDigest\x12\x1d\n" +
+	"\n" +
+	"git_commit\x18\x03 \x01(\tR\tgitCommit\x12\x1b\n" +
+	"\tbuild_url\x18\x04 \x01(\tR\bbuildUrl\x12\x1a\n" +
+	"\bdeployer\x18\x05 \x01(\tR\bdeployer\x12E\n" +
+	"\x06labels\x18\x06 \x03(\v2-.deployment.v1.DeploymentMetadata.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"*\n" +
 	"\x12ResponsePagination\x12\x14\n" +
-	"\x05total\x18\x01 \x01(\x05R\x05total\"\x89\x01\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\"\xcb\x02\n" +
 	"\x0fRegisterRequest\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x05R\n" +
 	"instanceId\x12\x18\n" +
 	"\aversion\x18\x02 \x01(\tR\aversion\x12;\n" +
 	"\vdeployed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
-	"deployedAt\"\x12\n" +
-	"\x10RegisterResponse\"\r\n" +
+	"deployedAt\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x03R\bsequence\x12=\n" +
+	"\bmetadata\x18\x05 \x01(\v2!.deployment.v1.DeploymentMetadataR\bmetadata\x12\x1c\n" +
+	"\tcomponent\x18\x06 \x01(\tR\tcomponent\x12\x1e\n" +
+	"\n" +
+	"undeployed\x18\a \x01(\bR\n" +
+	"undeployed\x12'\n" +
+	"\x0foverride_freeze\x18\b \x01(\bR\x0eoverrideFreeze\"M\n" +
+	"\x10RegisterResponse\x129\n" +
+	"\n" +
+	"deployment\x18\x01 \x01(\v2\x19.deployment.v1.DeploymentR\n" +
+	"deployment\"\r\n" +
 	"\vListRequest\"\x8e\x01\n" +
 	"\fListResponse\x12;\n" +
 	"\vdeployments\x18\x01 \x03(\v2\x19.deployment.v1.DeploymentR\vdeployments\x12A\n" +
 	"\n" +
 	"pagination\x18\x02 \x01(\v2!.deployment.v1.ResponsePaginationR\n" +
-	"pagination2\xa1\x01\n" +
+	"pagination\"\xb4\x02\n" +
+	"\aRollout\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x05R\n" +
+	"instanceId\x12\x1c\n" +
+	"\tcomponent\x18\x02 \x01(\tR\tcomponent\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x18\n" +
+	"\ahealthy\x18\x06 \x01(\x05R\ahealthy\x12\x18\n" +
+	"\adesired\x18\a \x01(\x05R\adesired\x12'\n" +
+	"\x0frunning_version\x18\b \x01(\tR\x0erunningVersion\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x15\n" +
+	"\x13ListRolloutsRequest\"J\n" +
+	"\x14ListRolloutsResponse\x122\n" +
+	"\brollouts\x18\x01 \x03(\v2\x16.deployment.v1.RolloutR\brollouts\"~\n" +
+	"\x10ChangelogRequest\x12%\n" +
+	"\x0eapplication_id\x18\x01 \x01(\x05R\rapplicationId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12\x1f\n" +
+	"\vmax_commits\x18\x04 \x01(\x05R\n" +
+	"maxCommits\"\x8d\x02\n" +
+	"\x11ChangelogResponse\x12\x1f\n" +
+	"\vfrom_commit\x18\x01 \x01(\tR\n" +
+	"fromCommit\x12\x1b\n" +
+	"\tto_commit\x18\x02 \x01(\tR\btoCommit\x12/\n" +
+	"\acommits\x18\x03 \x03(\v2\x15.deployment.v1.CommitR\acommits\x12\x1c\n" +
+	"\ttruncated\x18\x04 \x01(\bR\ttruncated\x12/\n" +
+	"\x05files\x18\x05 \x03(\v2\x19.deployment.v1.FileChangeR\x05files\x12\x1c\n" +
+	"\tadditions\x18\x06 \x01(\x05R\tadditions\x12\x1c\n" +
+	"\tdeletions\x18\a \x01(\x05R\tdeletions\"\xa1\x01\n" +
+	"\x06Commit\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x16\n" +
+	"\x06author\x18\x02 \x01(\tR\x06author\x12!\n" +
+	"\fauthor_email\x18\x03 \x01(\tR\vauthorEmail\x12.\n" +
+	"\x04date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x18\n" +
+	"\asubject\x18\x05 \x01(\tR\asubject\"t\n" +
+	"\n" +
+	"FileChange\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1c\n" +
+	"\tadditions\x18\x02 \x01(\x05R\tadditions\x12\x1c\n" +
+	"\tdeletions\x18\x03 \x01(\x05R\tdeletions\x12\x16\n" +
+	"\x06binary\x18\x04 \x01(\bR\x06binary2\xca\x02\n" +
 	"\x11DeploymentService\x12K\n" +
 	"\bRegister\x12\x1e.deployment.v1.RegisterRequest\x1a\x1f.deployment.v1.RegisterResponse\x12?\n" +
-	"\x04List\x12\x1a.deployment.v1.ListRequest\x1a\x1b.deployment.v1.ListResponseB\xb7\x01\n" +
+	"\x04List\x12\x1a.deployment.v1.ListRequest\x1a\x1b.deployment.v1.ListResponse\x12W\n" +
+	"\fListRollouts\x12\".deployment.v1.ListRolloutsRequest\x1a#.deployment.v1.ListRolloutsResponse\x12N\n" +
+	"\tChangelog\x12\x1f.deployment.v1.ChangelogRequest\x1a .deployment.v1.ChangelogResponseB\xb7\x01\n" +
 	"\x11com.deployment.v1B\x0fDeploymentProtoP\x01Z<github.com/theleeeo/overseer/api-go/deployment/v1;deployment\xa2\x02\x03DXX\xaa\x02\rDeployment.V1\xca\x02\rDeployment\\V1\xe2\x02\x19Deployment\\V1\\GPBMetadata\xea\x02\x0eDeployment::V1b\x06proto3"
 
 var (
@@ -356,30 +1185,53 @@ func file_deployment_v1_deployment_proto_rawDescGZIP() []byte {
 	return file_deployment_v1_deployment_proto_rawDescData
 }
 
-var file_deployment_v1_deployment_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_deployment_v1_deployment_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_deployment_v1_deployment_proto_goTypes = []any{
 	(*Deployment)(nil),            // 0: deployment.v1.Deployment
-	(*ResponsePagination)(nil),    // 1: deployment.v1.ResponsePagination
-	(*RegisterRequest)(nil),       // 2: deployment.v1.RegisterRequest
-	(*RegisterResponse)(nil),      // 3: deployment.v1.RegisterResponse
-	(*ListRequest)(nil),           // 4: deployment.v1.ListRequest
-	(*ListResponse)(nil),          // 5: deployment.v1.ListResponse
-	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+	(*DeploymentMetadata)(nil),    // 1: deployment.v1.DeploymentMetadata
+	(*ResponsePagination)(nil),    // 2: deployment.v1.ResponsePagination
+	(*RegisterRequest)(nil),       // 3: deployment.v1.RegisterRequest
+	(*RegisterResponse)(nil),      // 4: deployment.v1.RegisterResponse
+	(*ListRequest)(nil),           // 5: deployment.v1.ListRequest
+	(*ListResponse)(nil),          // 6: deployment.v1.ListResponse
+	(*Rollout)(nil),               // 7: deployment.v1.Rollout
+	(*ListRolloutsRequest)(nil),   // 8: deployment.v1.ListRolloutsRequest
+	(*ListRolloutsResponse)(nil),  // 9: deployment.v1.ListRolloutsResponse
+	(*ChangelogRequest)(nil),      // 10: deployment.v1.ChangelogRequest
+	(*ChangelogResponse)(nil),     // 11: deployment.v1.ChangelogResponse
+	(*Commit)(nil),                // 12: deployment.v1.Commit
+	(*FileChange)(nil),            // 13: deployment.v1.FileChange
+	nil,                           // 14: deployment.v1.DeploymentMetadata.LabelsEntry
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
 }
 var file_deployment_v1_deployment_proto_depIdxs = []int32{
-	6, // 0: deployment.v1.Deployment.deployed_at:type_name -> google.protobuf.Timestamp
-	6, // 1: deployment.v1.RegisterRequest.deployed_at:type_name -> google.protobuf.Timestamp
-	0, // 2: deployment.v1.ListResponse.deployments:type_name -> deployment.v1.Deployment
-	1, // 3: deployment.v1.ListResponse.pagination:type_name -> deployment.v1.ResponsePagination
-	2, // 4: deployment.v1.DeploymentService.Register:input_type -> deployment.v1.RegisterRequest
-	4, // 5: deployment.v1.DeploymentService.List:input_type -> deployment.v1.ListRequest
-	3, // 6: deployment.v1.DeploymentService.Register:output_type -> deployment.v1.RegisterResponse
-	5, // 7: deployment.v1.DeploymentService.List:output_type -> deployment.v1.ListResponse
-	6, // [6:8] is the sub-list for method output_type
-	4, // [4:6] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	15, // 0: deployment.v1.Deployment.deployed_at:type_name -> google.protobuf.Timestamp
+	15, // 1: deployment.v1.Deployment.received_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: deployment.v1.Deployment.metadata:type_name -> deployment.v1.DeploymentMetadata
+	14, // 3: deployment.v1.DeploymentMetadata.labels:type_name -> deployment.v1.DeploymentMetadata.LabelsEntry
+	15, // 4: deployment.v1.RegisterRequest.deployed_at:type_name -> google.protobuf.Timestamp
+	1,  // 5: deployment.v1.RegisterRequest.metadata:type_name -> deployment.v1.DeploymentMetadata
+	0,  // 6: deployment.v1.RegisterResponse.deployment:type_name -> deployment.v1.Deployment
+	0,  // 7: deployment.v1.ListResponse.deployments:type_name -> deployment.v1.Deployment
+	2,  // 8: deployment.v1.ListResponse.pagination:type_name -> deployment.v1.ResponsePagination
+	15, // 9: deployment.v1.Rollout.updated_at:type_name -> google.protobuf.Timestamp
+	7,  // 10: deployment.v1.ListRolloutsResponse.rollouts:type_name -> deployment.v1.Rollout
+	12, // 11: deployment.v1.ChangelogResponse.commits:type_name -> deployment.v1.Commit
+	13, // 12: deployment.v1.ChangelogResponse.files:type_name -> deployment.v1.FileChange
+	15, // 13: deployment.v1.Commit.date:type_name -> google.protobuf.Timestamp
+	3,  // 14: deployment.v1.DeploymentService.Register:input_type -> deployment.v1.RegisterRequest
+	5,  // 15: deployment.v1.DeploymentService.List:input_type -> deployment.v1.ListRequest
+	8,  // 16: deployment.v1.DeploymentService.ListRollouts:input_type -> deployment.v1.ListRolloutsRequest
+	10, // 17: deployment.v1.DeploymentService.Changelog:input_type -> deployment.v1.ChangelogRequest
+	4,  // 18: deployment.v1.DeploymentService.Register:output_type -> deployment.v1.RegisterResponse
+	6,  // 19: deployment.v1.DeploymentService.List:output_type -> deployment.v1.ListResponse
+	9,  // 20: deployment.v1.DeploymentService.ListRollouts:output_type -> deployment.v1.ListRolloutsResponse
+	11, // 21: deployment.v1.DeploymentService.Changelog:output_type -> deployment.v1.ChangelogResponse
+	18, // [18:22] is the sub-list for method output_type
+	14, // [14:18] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_deployment_v1_deployment_proto_init() }
@@ -393,7 +1245,7 @@ func file_deployment_v1_deployment_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_deployment_v1_deployment_proto_rawDesc), len(file_deployment_v1_deployment_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,16 +19,26 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	DeploymentService_Register_FullMethodName = "/deployment.v1.DeploymentService/Register"
-	DeploymentService_List_FullMethodName     = "/deployment.v1.DeploymentService/List"
+	DeploymentService_Register_FullMethodName     = "/deployment.v1.DeploymentService/Register"
+	DeploymentService_List_FullMethodName         = "/deployment.v1.DeploymentService/List"
+	DeploymentService_ListRollouts_FullMethodName = "/deployment.v1.DeploymentService/ListRollouts"
+	DeploymentService_Changelog_FullMethodName    = "/deployment.v1.DeploymentService/Changelog"
 )
 
 // DeploymentServiceClient is the client API for DeploymentService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type DeploymentServiceClient interface {
+	// Fails with FAILED_PRECONDITION during a freeze window that rejects deployments, unless the freeze is overridden.
+	// Deployments during other freeze windows are registered and flagged with the window.
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	// Lists the rollout of every instance component, as reported by the sources.
+	ListRollouts(ctx context.Context, in *ListRolloutsRequest, opts ...grpc.CallOption) (*ListRolloutsResponse, error)
+	// Lists the commits and changed files between two versions of an application, read from its git repository.
+	// A version is resolved to the tag named after it, with or without a v prefix, otherwise to the git commit
+	// recorded with its deployments, or otherwise to the commit if it is a commit hash.
+	Changelog(ctx context.Context, in *ChangelogRequest, opts ...grpc.CallOption) (*ChangelogResponse, error)
 }
 
 type deploymentServiceClient struct {
@@ -59,12 +69,40 @@ func (c *deploymentServiceClient) List(ctx context.Context, in *ListRequest, opt
 	return out, nil
 }
 
+func (c *deploymentServiceClient) ListRollouts(ctx context.Context, in *ListRolloutsRequest, opts ...grpc.CallOption) (*ListRolloutsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRolloutsResponse)
+	err := c.cc.Invoke(ctx, DeploymentService_ListRollouts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deploymentServiceClient) Changelog(ctx context.Context, in *ChangelogRequest, opts ...grpc.CallOption) (*ChangelogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangelogResponse)
+	err := c.cc.Invoke(ctx, DeploymentService_Changelog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DeploymentServiceServer is the server API for DeploymentService service.
 // All implementations should embed UnimplementedDeploymentServiceServer
 // for forward compatibility.
 type DeploymentServiceServer interface {
+	// Fails with FAILED_PRECONDITION during a freeze window that rejects deployments, unless the freeze is overridden.
+	// Deployments during other freeze windows are registered and flagged with the window.
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
 	List(context.Context, *ListRequest) (*ListResponse, error)
+	// Lists the rollout of every instance component, as reported by the sources.
+	ListRollouts(context.Context, *ListRolloutsRequest) (*ListRolloutsResponse, error)
+	// Lists the commits and changed files between two versions of an application, read from its git repository.
+	// A version is resolved to the tag named after it, with or without a v prefix, otherwise to the git commit
+	// recorded with its deployments, or otherwise to the commit if it is a commit hash.
+	Changelog(context.Context, *ChangelogRequest) (*ChangelogResponse, error)
 }
 
 // UnimplementedDeploymentServiceServer should be embedded to have
@@ -80,6 +118,12 @@ func (UnimplementedDeploymentServiceServer) Register(context.Context, *RegisterR
 func (UnimplementedDeploymentServiceServer) List(context.Context, *ListRequest) (*ListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedDeploymentServiceServer) ListRollouts(context.Context, *ListRolloutsRequest) (*ListRolloutsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRollouts not implemented")
+}
+func (UnimplementedDeploymentServiceServer) Changelog(context.Context, *ChangelogRequest) (*ChangelogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Changelog not implemented")
+}
 func (UnimplementedDeploymentServiceServer) testEmbeddedByValue() {}
 
 // UnsafeDeploymentServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -136,6 +180,42 @@ func _DeploymentService_List_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DeploymentService_ListRollouts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRolloutsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeploymentServiceServer).ListRollouts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeploymentService_ListRollouts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeploymentServiceServer).ListRollouts(ctx, req.(*ListRolloutsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeploymentService_Changelog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangelogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeploymentServiceServer).Changelog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeploymentService_Changelog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeploymentServiceServer).Changelog(ctx, req.(*ChangelogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DeploymentService_ServiceDesc is the grpc.ServiceDesc for DeploymentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -151,6 +231,14 @@ var DeploymentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "List",
 			Handler:    _DeploymentService_List_Handler,
 		},
+		{
+			MethodName: "ListRollouts",
+			Handler:    _DeploymentService_ListRollouts_Handler,
+		},
+		{
+			MethodName: "Changelog",
+			Handler:    _DeploymentService_Changelog_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "deployment/v1/deployment.proto",
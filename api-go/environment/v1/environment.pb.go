@@ -469,6 +469,86 @@ func (*SetSortOrderResponse) Descriptor() ([]byte, []int) {
 	return file_environment_v1_environment_proto_rawDescGZIP(), []int{9}
 }
 
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_environment_v1_environment_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_environment_v1_environment_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_environment_v1_environment_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_environment_v1_environment_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_environment_v1_environment_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_environment_v1_environment_proto_rawDescGZIP(), []int{11}
+}
+
 var File_environment_v1_environment_proto protoreflect.FileDescriptor
 
 const file_environment_v1_environment_proto_rawDesc = "" +
@@ -499,12 +579,16 @@ const file_environment_v1_environment_proto_rawDesc = "" +
 	"\x13SetSortOrderRequest\x12 \n" +
 	"\fids_in_order\x18\x01 \x03(\x05R\n" +
 	"idsInOrder\"\x16\n" +
-	"\x14SetSortOrderResponse2\xc4\x02\n" +
+	"\x14SetSortOrderResponse\"\x1f\n" +
+	"\rDeleteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x10\n" +
+	"\x0eDeleteResponse2\x8d\x03\n" +
 	"\x12EnvironmentService\x12G\n" +
 	"\x06Create\x12\x1d.environment.v1.CreateRequest\x1a\x1e.environment.v1.CreateResponse\x12A\n" +
 	"\x04List\x12\x1b.environment.v1.ListRequest\x1a\x1c.environment.v1.ListResponse\x12G\n" +
 	"\x06Update\x12\x1d.environment.v1.UpdateRequest\x1a\x1e.environment.v1.UpdateResponse\x12Y\n" +
-	"\fSetSortOrder\x12#.environment.v1.SetSortOrderRequest\x1a$.environment.v1.SetSortOrderResponseB\xbf\x01\n" +
+	"\fSetSortOrder\x12#.environment.v1.SetSortOrderRequest\x1a$.environment.v1.SetSortOrderResponse\x12G\n" +
+	"\x06Delete\x12\x1d.environment.v1.DeleteRequest\x1a\x1e.environment.v1.DeleteResponseB\xbf\x01\n" +
 	"\x12com.environment.v1B\x10EnvironmentProtoP\x01Z>github.com/theleeeo/overseer/api-go/environment/v1;environment\xa2\x02\x03EXX\xaa\x02\x0eEnvironment.V1\xca\x02\x0eEnvironment\\V1\xe2\x02\x1aEnvironment\\V1\\GPBMetadata\xea\x02\x0fEnvironment::V1b\x06proto3"
 
 var (
@@ -519,7 +603,7 @@ func file_environment_v1_environment_proto_rawDescGZIP() []byte {
 	return file_environment_v1_environment_proto_rawDescData
 }
 
-var file_environment_v1_environment_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_environment_v1_environment_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_environment_v1_environment_proto_goTypes = []any{
 	(*Environment)(nil),          // 0: environment.v1.Environment
 	(*ResponsePagination)(nil),   // 1: environment.v1.ResponsePagination
@@ -531,23 +615,27 @@ var file_environment_v1_environment_proto_goTypes = []any{
 	(*UpdateResponse)(nil),       // 7: environment.v1.UpdateResponse
 	(*SetSortOrderRequest)(nil),  // 8: environment.v1.SetSortOrderRequest
 	(*SetSortOrderResponse)(nil), // 9: environment.v1.SetSortOrderResponse
+	(*DeleteRequest)(nil),        // 10: environment.v1.DeleteRequest
+	(*DeleteResponse)(nil),       // 11: environment.v1.DeleteResponse
 }
 var file_environment_v1_environment_proto_depIdxs = []int32{
-	0, // 0: environment.v1.ListResponse.environments:type_name -> environment.v1.Environment
-	1, // 1: environment.v1.ListResponse.pagination:type_name -> environment.v1.ResponsePagination
-	2, // 2: environment.v1.EnvironmentService.Create:input_type -> environment.v1.CreateRequest
-	4, // 3: environment.v1.EnvironmentService.List:input_type -> environment.v1.ListRequest
-	6, // 4: environment.v1.EnvironmentService.Update:input_type -> environment.v1.UpdateRequest
-	8, // 5: environment.v1.EnvironmentService.SetSortOrder:input_type -> environment.v1.SetSortOrderRequest
-	3, // 6: environment.v1.EnvironmentService.Create:output_type -> environment.v1.CreateResponse
-	5, // 7: environment.v1.EnvironmentService.List:output_type -> environment.v1.ListResponse
-	7, // 8: environment.v1.EnvironmentService.Update:output_type -> environment.v1.UpdateResponse
-	9, // 9: environment.v1.EnvironmentService.SetSortOrder:output_type -> environment.v1.SetSortOrderResponse
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: environment.v1.ListResponse.environments:type_name -> environment.v1.Environment
+	1,  // 1: environment.v1.ListResponse.pagination:type_name -> environment.v1.ResponsePagination
+	2,  // 2: environment.v1.EnvironmentService.Create:input_type -> environment.v1.CreateRequest
+	4,  // 3: environment.v1.EnvironmentService.List:input_type -> environment.v1.ListRequest
+	6,  // 4: environment.v1.EnvironmentService.Update:input_type -> environment.v1.UpdateRequest
+	8,  // 5: environment.v1.EnvironmentService.SetSortOrder:input_type -> environment.v1.SetSortOrderRequest
+	10, // 6: environment.v1.EnvironmentService.Delete:input_type -> environment.v1.DeleteRequest
+	3,  // 7: environment.v1.EnvironmentService.Create:output_type -> environment.v1.CreateResponse
+	5,  // 8: environment.v1.EnvironmentService.List:output_type -> environment.v1.ListResponse
+	7,  // 9: environment.v1.EnvironmentService.Update:output_type -> environment.v1.UpdateResponse
+	9,  // 10: environment.v1.EnvironmentService.SetSortOrder:output_type -> environment.v1.SetSortOrderResponse
+	11, // 11: environment.v1.EnvironmentService.Delete:output_type -> environment.v1.DeleteResponse
+	7,  // [7:12] is the sub-list for method output_type
+	2,  // [2:7] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_environment_v1_environment_proto_init() }
@@ -562,7 +650,7 @@ func file_environment_v1_environment_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_environment_v1_environment_proto_rawDesc), len(file_environment_v1_environment_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -23,6 +23,7 @@ const (
 	EnvironmentService_List_FullMethodName         = "/environment.v1.EnvironmentService/List"
 	EnvironmentService_Update_FullMethodName       = "/environment.v1.EnvironmentService/Update"
 	EnvironmentService_SetSortOrder_FullMethodName = "/environment.v1.EnvironmentService/SetSortOrder"
+	EnvironmentService_Delete_FullMethodName       = "/environment.v1.EnvironmentService/Delete"
 )
 
 // EnvironmentServiceClient is the client API for EnvironmentService service.
@@ -33,6 +34,7 @@ type EnvironmentServiceClient interface {
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
 	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
 	SetSortOrder(ctx context.Context, in *SetSortOrderRequest, opts ...grpc.CallOption) (*SetSortOrderResponse, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
 }
 
 type environmentServiceClient struct {
@@ -83,6 +85,16 @@ func (c *environmentServiceClient) SetSortOrder(ctx context.Context, in *SetSort
 	return out, nil
 }
 
+func (c *environmentServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, EnvironmentService_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EnvironmentServiceServer is the server API for EnvironmentService service.
 // All implementations should embed UnimplementedEnvironmentServiceServer
 // for forward compatibility.
@@ -91,6 +103,7 @@ type EnvironmentServiceServer interface {
 	List(context.Context, *ListRequest) (*ListResponse, error)
 	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
 	SetSortOrder(context.Context, *SetSortOrderRequest) (*SetSortOrderResponse, error)
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
 }
 
 // UnimplementedEnvironmentServiceServer should be embedded to have
@@ -112,6 +125,9 @@ func (UnimplementedEnvironmentServiceServer) Update(context.Context, *UpdateRequ
 func (UnimplementedEnvironmentServiceServer) SetSortOrder(context.Context, *SetSortOrderRequest) (*SetSortOrderResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetSortOrder not implemented")
 }
+func (UnimplementedEnvironmentServiceServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
 func (UnimplementedEnvironmentServiceServer) testEmbeddedByValue() {}
 
 // UnsafeEnvironmentServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -204,6 +220,24 @@ func _EnvironmentService_SetSortOrder_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EnvironmentService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnvironmentServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EnvironmentService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnvironmentServiceServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // EnvironmentService_ServiceDesc is the grpc.ServiceDesc for EnvironmentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -227,6 +261,10 @@ var EnvironmentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetSortOrder",
 			Handler:    _EnvironmentService_SetSortOrder_Handler,
 		},
+		{
+			MethodName: "Delete",
+			Handler:    _EnvironmentService_Delete_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "environment/v1/environment.proto",
@@ -0,0 +1,636 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: freeze/v1/freeze.proto
+
+package freeze
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A period in which deployments are frozen, like around holidays or a big launch.
+// A window is either one-off, from starts_at to ends_at, or recurring, starting every time the schedule matches
+// in the time zone and lasting for duration_seconds.
+type FreezeWindow struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name   string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Reason string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Limits the freeze to the environment, 0 for all environments.
+	EnvironmentId int32 `protobuf:"varint,4,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`
+	// Limits the freeze to the application, 0 for all applications.
+	ApplicationId int32                  `protobuf:"varint,5,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	StartsAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	// A cron expression like "0 18 * * FRI" for the starts of a recurring window.
+	Schedule        string `protobuf:"bytes,8,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	DurationSeconds int64  `protobuf:"varint,9,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	// The IANA time zone the schedule is evaluated in, like Europe/Stockholm. Defaults to UTC.
+	TimeZone string `protobuf:"bytes,10,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Rejects deployments registered through the API during the freeze, unless they override it.
+	// Deployments are otherwise only flagged.
+	Reject bool `protobuf:"varint,11,opt,name=reject,proto3" json:"reject,omitempty"`
+	// The window is active now, until active_until.
+	Active      bool                   `protobuf:"varint,12,opt,name=active,proto3" json:"active,omitempty"`
+	ActiveUntil *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=active_until,json=activeUntil,proto3" json:"active_until,omitempty"`
+	// When the window next starts, unset if it never does.
+	NextStart     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=next_start,json=nextStart,proto3" json:"next_start,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreezeWindow) Reset() {
+	*x = FreezeWindow{}
+	mi := &file_freeze_v1_freeze_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreezeWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeWindow) ProtoMessage() {}
+
+func (x *FreezeWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_freeze_v1_freeze_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeWindow.ProtoReflect.Descriptor instead.
+func (*FreezeWindow) Descriptor() ([]byte, []int) {
+	return file_freeze_v1_freeze_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FreezeWindow) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *FreezeWindow) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FreezeWindow) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *FreezeWindow) GetEnvironmentId() int32 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+func (x *FreezeWindow) GetApplicationId() int32 {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return 0
+}
+
+func (x *FreezeWindow) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *FreezeWindow) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *FreezeWindow) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *FreezeWindow) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *FreezeWindow) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *FreezeWindow) GetReject() bool {
+	if x != nil {
+		return x.Reject
+	}
+	return false
+}
+
+func (x *FreezeWindow) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *FreezeWindow) GetActiveUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveUntil
+	}
+	return nil
+}
+
+func (x *FreezeWindow) GetNextStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextStart
+	}
+	return nil
+}
+
+type ListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_freeze_v1_freeze_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_freeze_v1_freeze_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_freeze_v1_freeze_proto_rawDescGZIP(), []int{1}
+}
+
+type ListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Windows       []*FreezeWindow        `protobuf:"bytes,1,rep,name=windows,proto3" json:"windows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_freeze_v1_freeze_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_freeze_v1_freeze_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_freeze_v1_freeze_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListResponse) GetWindows() []*FreezeWindow {
+	if x != nil {
+		return x.Windows
+	}
+	return nil
+}
+
+type CreateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        *FreezeWindow          `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
+	mi := &file_freeze_v1_freeze_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequest) ProtoMessage() {}
+
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_freeze_v1_freeze_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_freeze_v1_freeze_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateRequest) GetWindow() *FreezeWindow {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+type CreateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        *FreezeWindow          `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateResponse) Reset() {
+	*x = CreateResponse{}
+	mi := &file_freeze_v1_freeze_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResponse) ProtoMessage() {}
+
+func (x *CreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_freeze_v1_freeze_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResponse.ProtoReflect.Descriptor instead.
+func (*CreateResponse) Descriptor() ([]byte, []int) {
+	return file_freeze_v1_freeze_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateResponse) GetWindow() *FreezeWindow {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+type UpdateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        *FreezeWindow          `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRequest) Reset() {
+	*x = UpdateRequest{}
+	mi := &file_freeze_v1_freeze_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRequest) ProtoMessage() {}
+
+func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_freeze_v1_freeze_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRequest) Descriptor() ([]byte, []int) {
+	return file_freeze_v1_freeze_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateRequest) GetWindow() *FreezeWindow {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+type UpdateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        *FreezeWindow          `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateResponse) Reset() {
+	*x = UpdateResponse{}
+	mi := &file_freeze_v1_freeze_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateResponse) ProtoMessage() {}
+
+func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_freeze_v1_freeze_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateResponse.ProtoReflect.Descriptor instead.
+func (*UpdateResponse) Descriptor() ([]byte, []int) {
+	return file_freeze_v1_freeze_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateResponse) GetWindow() *FreezeWindow {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_freeze_v1_freeze_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_freeze_v1_freeze_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_freeze_v1_freeze_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_freeze_v1_freeze_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_freeze_v1_freeze_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_freeze_v1_freeze_proto_rawDescGZIP(), []int{8}
+}
+
+var File_freeze_v1_freeze_proto protoreflect.FileDescriptor
+
+const file_freeze_v1_freeze_proto_rawDesc = "" +
+	"\n" +
+	"\x16freeze/v1/freeze.proto\x12\tfreeze.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x94\x04\n" +
+	"\fFreezeWindow\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12%\n" +
+	"\x0eenvironment_id\x18\x04 \x01(\x05R\renvironmentId\x12%\n" +
+	"\x0eapplication_id\x18\x05 \x01(\x05R\rapplicationId\x127\n" +
+	"\tstarts_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x12\x1a\n" +
+	"\bschedule\x18\b \x01(\tR\bschedule\x12)\n" +
+	"\x10duration_seconds\x18\t \x01(\x03R\x0fdurationSeconds\x12\x1b\n" +
+	"\ttime_zone\x18\n" +
+	" \x01(\tR\btimeZone\x12\x16\n" +
+	"\x06reject\x18\v \x01(\bR\x06reject\x12\x16\n" +
+	"\x06active\x18\f \x01(\bR\x06active\x12=\n" +
+	"\factive_until\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\vactiveUntil\x129\n" +
+	"\n" +
+	"next_start\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tnextStart\"\r\n" +
+	"\vListRequest\"A\n" +
+	"\fListResponse\x121\n" +
+	"\awindows\x18\x01 \x03(\v2\x17.freeze.v1.FreezeWindowR\awindows\"@\n" +
+	"\rCreateRequest\x12/\n" +
+	"\x06window\x18\x01 \x01(\v2\x17.freeze.v1.FreezeWindowR\x06window\"A\n" +
+	"\x0eCreateResponse\x12/\n" +
+	"\x06window\x18\x01 \x01(\v2\x17.freeze.v1.FreezeWindowR\x06window\"@\n" +
+	"\rUpdateRequest\x12/\n" +
+	"\x06window\x18\x01 \x01(\v2\x17.freeze.v1.FreezeWindowR\x06window\"A\n" +
+	"\x0eUpdateResponse\x12/\n" +
+	"\x06window\x18\x01 \x01(\v2\x17.freeze.v1.FreezeWindowR\x06window\"\x1f\n" +
+	"\rDeleteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"\x10\n" +
+	"\x0eDeleteResponse2\x85\x02\n" +
+	"\rFreezeService\x127\n" +
+	"\x04List\x12\x16.freeze.v1.ListRequest\x1a\x17.freeze.v1.ListResponse\x12=\n" +
+	"\x06Create\x12\x18.freeze.v1.CreateRequest\x1a\x19.freeze.v1.CreateResponse\x12=\n" +
+	"\x06Update\x12\x18.freeze.v1.UpdateRequest\x1a\x19.freeze.v1.UpdateResponse\x12=\n" +
+	"\x06Delete\x12\x18.freeze.v1.DeleteRequest\x1a\x19.freeze.v1.DeleteResponseB\x97\x01\n" +
+	"\rcom.freeze.v1B\vFreezeProtoP\x01Z4github.com/theleeeo/overseer/api-go/freeze/v1;freeze\xa2\x02\x03FXX\xaa\x02\tFreeze.V1\xca\x02\tFreeze\\V1\xe2\x02\x15Freeze\\V1\\GPBMetadata\xea\x02\n" +
+	"Freeze::V1b\x06proto3"
+
+var (
+	file_freeze_v1_freeze_proto_rawDescOnce sync.Once
+	file_freeze_v1_freeze_proto_rawDescData []byte
+)
+
+func file_freeze_v1_freeze_proto_rawDescGZIP() []byte {
+	file_freeze_v1_freeze_proto_rawDescOnce.Do(func() {
+		file_freeze_v1_freeze_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_freeze_v1_freeze_proto_rawDesc), len(file_freeze_v1_freeze_proto_rawDesc)))
+	})
+	return file_freeze_v1_freeze_proto_rawDescData
+}
+
+var file_freeze_v1_freeze_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_freeze_v1_freeze_proto_goTypes = []any{
+	(*FreezeWindow)(nil),          // 0: freeze.v1.FreezeWindow
+	(*ListRequest)(nil),           // 1: freeze.v1.ListRequest
+	(*ListResponse)(nil),          // 2: freeze.v1.ListResponse
+	(*CreateRequest)(nil),         // 3: freeze.v1.CreateRequest
+	(*CreateResponse)(nil),        // 4: freeze.v1.CreateResponse
+	(*UpdateRequest)(nil),         // 5: freeze.v1.UpdateRequest
+	(*UpdateResponse)(nil),        // 6: freeze.v1.UpdateResponse
+	(*DeleteRequest)(nil),         // 7: freeze.v1.DeleteRequest
+	(*DeleteResponse)(nil),        // 8: freeze.v1.DeleteResponse
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_freeze_v1_freeze_proto_depIdxs = []int32{
+	9,  // 0: freeze.v1.FreezeWindow.starts_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: freeze.v1.FreezeWindow.ends_at:type_name -> google.protobuf.Timestamp
+	9,  // 2: freeze.v1.FreezeWindow.active_until:type_name -> google.protobuf.Timestamp
+	9,  // 3: freeze.v1.FreezeWindow.next_start:type_name -> google.protobuf.Timestamp
+	0,  // 4: freeze.v1.ListResponse.windows:type_name -> freeze.v1.FreezeWindow
+	0,  // 5: freeze.v1.CreateRequest.window:type_name -> freeze.v1.FreezeWindow
+	0,  // 6: freeze.v1.CreateResponse.window:type_name -> freeze.v1.FreezeWindow
+	0,  // 7: freeze.v1.UpdateRequest.window:type_name -> freeze.v1.FreezeWindow
+	0,  // 8: freeze.v1.UpdateResponse.window:type_name -> freeze.v1.FreezeWindow
+	1,  // 9: freeze.v1.FreezeService.List:input_type -> freeze.v1.ListRequest
+	3,  // 10: freeze.v1.FreezeService.Create:input_type -> freeze.v1.CreateRequest
+	5,  // 11: freeze.v1.FreezeService.Update:input_type -> freeze.v1.UpdateRequest
+	7,  // 12: freeze.v1.FreezeService.Delete:input_type -> freeze.v1.DeleteRequest
+	2,  // 13: freeze.v1.FreezeService.List:output_type -> freeze.v1.ListResponse
+	4,  // 14: freeze.v1.FreezeService.Create:output_type -> freeze.v1.CreateResponse
+	6,  // 15: freeze.v1.FreezeService.Update:output_type -> freeze.v1.UpdateResponse
+	8,  // 16: freeze.v1.FreezeService.Delete:output_type -> freeze.v1.DeleteResponse
+	13, // [13:17] is the sub-list for method output_type
+	9,  // [9:13] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_freeze_v1_freeze_proto_init() }
+func file_freeze_v1_freeze_proto_init() {
+	if File_freeze_v1_freeze_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_freeze_v1_freeze_proto_rawDesc), len(file_freeze_v1_freeze_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_freeze_v1_freeze_proto_goTypes,
+		DependencyIndexes: file_freeze_v1_freeze_proto_depIdxs,
+		MessageInfos:      file_freeze_v1_freeze_proto_msgTypes,
+	}.Build()
+	File_freeze_v1_freeze_proto = out.File
+	file_freeze_v1_freeze_proto_goTypes = nil
+	file_freeze_v1_freeze_proto_depIdxs = nil
+}
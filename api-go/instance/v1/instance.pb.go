@@ -27,8 +27,10 @@ type Instance struct {
 	EnvironmentId int32                  `protobuf:"varint,2,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`
 	ApplicationId int32                  `protobuf:"varint,3,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
 	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// The component whose version is shown for the instance in the version matrix.
+	PrimaryComponent string `protobuf:"bytes,5,opt,name=primary_component,json=primaryComponent,proto3" json:"primary_component,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *Instance) Reset() {
@@ -89,6 +91,13 @@ func (x *Instance) GetName() string {
 	return ""
 }
 
+func (x *Instance) GetPrimaryComponent() string {
+	if x != nil {
+		return x.PrimaryComponent
+	}
+	return ""
+}
+
 type ResponsePagination struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Total         int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
@@ -138,8 +147,10 @@ type CreateRequest struct {
 	EnvironmentId int32                  `protobuf:"varint,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`
 	ApplicationId int32                  `protobuf:"varint,2,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
 	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// Defaults to "main".
+	PrimaryComponent string `protobuf:"bytes,4,opt,name=primary_component,json=primaryComponent,proto3" json:"primary_component,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *CreateRequest) Reset() {
@@ -193,6 +204,13 @@ func (x *CreateRequest) GetName() string {
 	return ""
 }
 
+func (x *CreateRequest) GetPrimaryComponent() string {
+	if x != nil {
+		return x.PrimaryComponent
+	}
+	return ""
+}
+
 type CreateResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -238,11 +256,13 @@ func (x *CreateResponse) GetId() int32 {
 }
 
 type UpdateRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Left unchanged if empty.
+	PrimaryComponent string `protobuf:"bytes,3,opt,name=primary_component,json=primaryComponent,proto3" json:"primary_component,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *UpdateRequest) Reset() {
@@ -289,6 +309,13 @@ func (x *UpdateRequest) GetName() string {
 	return ""
 }
 
+func (x *UpdateRequest) GetPrimaryComponent() string {
+	if x != nil {
+		return x.PrimaryComponent
+	}
+	return ""
+}
+
 type UpdateResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -505,23 +532,26 @@ var File_instance_v1_instance_proto protoreflect.FileDescriptor
 
 const file_instance_v1_instance_proto_rawDesc = "" +
 	"\n" +
-	"\x1ainstance/v1/instance.proto\x12\vinstance.v1\"|\n" +
+	"\x1ainstance/v1/instance.proto\x12\vinstance.v1\"\xa9\x01\n" +
 	"\bInstance\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12%\n" +
 	"\x0eenvironment_id\x18\x02 \x01(\x05R\renvironmentId\x12%\n" +
 	"\x0eapplication_id\x18\x03 \x01(\x05R\rapplicationId\x12\x12\n" +
-	"\x04name\x18\x04 \x01(\tR\x04name\"*\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12+\n" +
+	"\x11primary_component\x18\x05 \x01(\tR\x10primaryComponent\"*\n" +
 	"\x12ResponsePagination\x12\x14\n" +
-	"\x05total\x18\x01 \x01(\x05R\x05total\"q\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\"\x9e\x01\n" +
 	"\rCreateRequest\x12%\n" +
 	"\x0eenvironment_id\x18\x01 \x01(\x05R\renvironmentId\x12%\n" +
 	"\x0eapplication_id\x18\x02 \x01(\x05R\rapplicationId\x12\x12\n" +
-	"\x04name\x18\x03 \x01(\tR\x04name\" \n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12+\n" +
+	"\x11primary_component\x18\x04 \x01(\tR\x10primaryComponent\" \n" +
 	"\x0eCreateResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\"3\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"`\n" +
 	"\rUpdateRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\"\x10\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12+\n" +
+	"\x11primary_component\x18\x03 \x01(\tR\x10primaryComponent\"\x10\n" +
 	"\x0eUpdateResponse\"!\n" +
 	"\vListRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\"\x84\x01\n" +
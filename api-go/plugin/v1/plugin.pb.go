@@ -0,0 +1,896 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: plugin/v1/plugin.proto
+
+package plugin
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// An event of a source, like a new deployment or the progress of rolling it out.
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Orders the events of the source, it must increase with every event.
+	// Events at or before the last cursor Overseer received are dropped as duplicates.
+	Cursor int64 `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Identifies the event in the logs.
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// The name of the deployment, matched against the names of the instances.
+	DeploymentName string `protobuf:"bytes,3,opt,name=deployment_name,json=deploymentName,proto3" json:"deployment_name,omitempty"`
+	// The component of the instance that was deployed, empty for the primary component.
+	Component  string                 `protobuf:"bytes,4,opt,name=component,proto3" json:"component,omitempty"`
+	DeployedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=deployed_at,json=deployedAt,proto3" json:"deployed_at,omitempty"`
+	// Required unless the deployment was undeployed.
+	Version string `protobuf:"bytes,6,opt,name=version,proto3" json:"version,omitempty"`
+	// An optional monotonic number ordering the deployments when their times can't be trusted.
+	Sequence int64     `protobuf:"varint,7,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Metadata *Metadata `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// The deployment was stopped or removed, the version is empty.
+	Undeployed bool `protobuf:"varint,9,opt,name=undeployed,proto3" json:"undeployed,omitempty"`
+	// Set if the event reports the progress of rolling out the version instead of a new deployment,
+	// deployed_at is then the time of the progress update.
+	Rollout       *Rollout `protobuf:"bytes,10,opt,name=rollout,proto3" json:"rollout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Event) GetCursor() int64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+func (x *Event) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Event) GetDeploymentName() string {
+	if x != nil {
+		return x.DeploymentName
+	}
+	return ""
+}
+
+func (x *Event) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *Event) GetDeployedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeployedAt
+	}
+	return nil
+}
+
+func (x *Event) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Event) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *Event) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Event) GetUndeployed() bool {
+	if x != nil {
+		return x.Undeployed
+	}
+	return false
+}
+
+func (x *Event) GetRollout() *Rollout {
+	if x != nil {
+		return x.Rollout
+	}
+	return nil
+}
+
+// Describes what was deployed and who deployed it, every field is optional.
+type Metadata struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The reference of the deployed container image, without the digest.
+	Image       string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	ImageDigest string `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	GitCommit   string `protobuf:"bytes,3,opt,name=git_commit,json=gitCommit,proto3" json:"git_commit,omitempty"`
+	BuildUrl    string `protobuf:"bytes,4,opt,name=build_url,json=buildUrl,proto3" json:"build_url,omitempty"`
+	// The person or pipeline that made the deployment.
+	Deployer      string            `protobuf:"bytes,5,opt,name=deployer,proto3" json:"deployer,omitempty"`
+	Labels        map[string]string `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metadata) ProtoMessage() {}
+
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Metadata) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *Metadata) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+func (x *Metadata) GetGitCommit() string {
+	if x != nil {
+		return x.GitCommit
+	}
+	return ""
+}
+
+func (x *Metadata) GetBuildUrl() string {
+	if x != nil {
+		return x.BuildUrl
+	}
+	return ""
+}
+
+func (x *Metadata) GetDeployer() string {
+	if x != nil {
+		return x.Deployer
+	}
+	return ""
+}
+
+func (x *Metadata) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type Rollout struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of pending, running, paused, successful, failed or cancelled.
+	Status      string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// The number of healthy and desired replicas, both 0 if unknown.
+	Healthy       int32 `protobuf:"varint,3,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	Desired       int32 `protobuf:"varint,4,opt,name=desired,proto3" json:"desired,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rollout) Reset() {
+	*x = Rollout{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rollout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rollout) ProtoMessage() {}
+
+func (x *Rollout) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rollout.ProtoReflect.Descriptor instead.
+func (*Rollout) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Rollout) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Rollout) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Rollout) GetHealthy() int32 {
+	if x != nil {
+		return x.Healthy
+	}
+	return 0
+}
+
+func (x *Rollout) GetDesired() int32 {
+	if x != nil {
+		return x.Desired
+	}
+	return 0
+}
+
+// Sent by Overseer before it receives any events.
+type Subscribe struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The cursor of the last event Overseer received, the source sends the events after it.
+	// It is 0 when Overseer has received nothing yet, like after it restarted,
+	// and the source should then send the current deployments.
+	Cursor        int64 `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Subscribe) Reset() {
+	*x = Subscribe{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscribe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscribe) ProtoMessage() {}
+
+func (x *Subscribe) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscribe.ProtoReflect.Descriptor instead.
+func (*Subscribe) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Subscribe) GetCursor() int64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+// Acknowledges that the events up to and including the cursor are handled,
+// so the source doesn't need to send them again.
+type Ack struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cursor        int64                  `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ack) Reset() {
+	*x = Ack{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ack) ProtoMessage() {}
+
+func (x *Ack) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ack.ProtoReflect.Descriptor instead.
+func (*Ack) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Ack) GetCursor() int64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+type StreamEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*StreamEventsRequest_Subscribe
+	//	*StreamEventsRequest_Ack
+	Message       isStreamEventsRequest_Message `protobuf_oneof:"message"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamEventsRequest) GetMessage() isStreamEventsRequest_Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *StreamEventsRequest) GetSubscribe() *Subscribe {
+	if x != nil {
+		if x, ok := x.Message.(*StreamEventsRequest_Subscribe); ok {
+			return x.Subscribe
+		}
+	}
+	return nil
+}
+
+func (x *StreamEventsRequest) GetAck() *Ack {
+	if x != nil {
+		if x, ok := x.Message.(*StreamEventsRequest_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
+}
+
+type isStreamEventsRequest_Message interface {
+	isStreamEventsRequest_Message()
+}
+
+type StreamEventsRequest_Subscribe struct {
+	Subscribe *Subscribe `protobuf:"bytes,1,opt,name=subscribe,proto3,oneof"`
+}
+
+type StreamEventsRequest_Ack struct {
+	Ack *Ack `protobuf:"bytes,2,opt,name=ack,proto3,oneof"`
+}
+
+func (*StreamEventsRequest_Subscribe) isStreamEventsRequest_Message() {}
+
+func (*StreamEventsRequest_Ack) isStreamEventsRequest_Message() {}
+
+type StreamEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsResponse) Reset() {
+	*x = StreamEventsResponse{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsResponse) ProtoMessage() {}
+
+func (x *StreamEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsResponse.ProtoReflect.Descriptor instead.
+func (*StreamEventsResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StreamEventsResponse) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type PublishRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*PublishRequest_Hello
+	//	*PublishRequest_Event
+	Message       isPublishRequest_Message `protobuf_oneof:"message"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishRequest) Reset() {
+	*x = PublishRequest{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishRequest) ProtoMessage() {}
+
+func (x *PublishRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishRequest.ProtoReflect.Descriptor instead.
+func (*PublishRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PublishRequest) GetMessage() isPublishRequest_Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *PublishRequest) GetHello() *Hello {
+	if x != nil {
+		if x, ok := x.Message.(*PublishRequest_Hello); ok {
+			return x.Hello
+		}
+	}
+	return nil
+}
+
+func (x *PublishRequest) GetEvent() *Event {
+	if x != nil {
+		if x, ok := x.Message.(*PublishRequest_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+type isPublishRequest_Message interface {
+	isPublishRequest_Message()
+}
+
+type PublishRequest_Hello struct {
+	Hello *Hello `protobuf:"bytes,1,opt,name=hello,proto3,oneof"`
+}
+
+type PublishRequest_Event struct {
+	Event *Event `protobuf:"bytes,2,opt,name=event,proto3,oneof"`
+}
+
+func (*PublishRequest_Hello) isPublishRequest_Message() {}
+
+func (*PublishRequest_Event) isPublishRequest_Message() {}
+
+// Identifies the source that connects.
+type Hello struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the source as configured in Overseer.
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// The token of the source, if it is configured with one.
+	Token         string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Hello) Reset() {
+	*x = Hello{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hello) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hello) ProtoMessage() {}
+
+func (x *Hello) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hello.ProtoReflect.Descriptor instead.
+func (*Hello) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Hello) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Hello) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type PublishResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*PublishResponse_Subscribe
+	//	*PublishResponse_Ack
+	Message       isPublishResponse_Message `protobuf_oneof:"message"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishResponse) Reset() {
+	*x = PublishResponse{}
+	mi := &file_plugin_v1_plugin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishResponse) ProtoMessage() {}
+
+func (x *PublishResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_plugin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishResponse.ProtoReflect.Descriptor instead.
+func (*PublishResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_plugin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PublishResponse) GetMessage() isPublishResponse_Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *PublishResponse) GetSubscribe() *Subscribe {
+	if x != nil {
+		if x, ok := x.Message.(*PublishResponse_Subscribe); ok {
+			return x.Subscribe
+		}
+	}
+	return nil
+}
+
+func (x *PublishResponse) GetAck() *Ack {
+	if x != nil {
+		if x, ok := x.Message.(*PublishResponse_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
+}
+
+type isPublishResponse_Message interface {
+	isPublishResponse_Message()
+}
+
+type PublishResponse_Subscribe struct {
+	Subscribe *Subscribe `protobuf:"bytes,1,opt,name=subscribe,proto3,oneof"`
+}
+
+type PublishResponse_Ack struct {
+	Ack *Ack `protobuf:"bytes,2,opt,name=ack,proto3,oneof"`
+}
+
+func (*PublishResponse_Subscribe) isPublishResponse_Message() {}
+
+func (*PublishResponse_Ack) isPublishResponse_Message() {}
+
+var File_plugin_v1_plugin_proto protoreflect.FileDescriptor
+
+const file_plugin_v1_plugin_proto_rawDesc = "" +
+	"\n" +
+	"\x16plugin/v1/plugin.proto\x12\tplugin.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe8\x02\n" +
+	"\x05Event\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\x03R\x06cursor\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12'\n" +
+	"\x0fdeployment_name\x18\x03 \x01(\tR\x0edeploymentName\x12\x1c\n" +
+	"\tcomponent\x18\x04 \x01(\tR\tcomponent\x12;\n" +
+	"\vdeployed_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"deployedAt\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\tR\aversion\x12\x1a\n" +
+	"\bsequence\x18\a \x01(\x03R\bsequence\x12/\n" +
+	"\bmetadata\x18\b \x01(\v2\x13.plugin.v1.MetadataR\bmetadata\x12\x1e\n" +
+	"\n" +
+	"undeployed\x18\t \x01(\bR\n" +
+	"undeployed\x12,\n" +
+	"\arollout\x18\n" +
+	" \x01(\v2\x12.plugin.v1.RolloutR\arollout\"\x8f\x02\n" +
+	"\bMetadata\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\tR\x05image\x12!\n" +
+	"\fimage_digest\x18\x02 \x01(\tR\vimageDigest\x12\x1d\n" +
+	"\n" +
+	"git_commit\x18\x03 \x01(\tR\tgitCommit\x12\x1b\n" +
+	"\tbuild_url\x18\x04 \x01(\tR\bbuildUrl\x12\x1a\n" +
+	"\bdeployer\x18\x05 \x01(\tR\bdeployer\x127\n" +
+	"\x06labels\x18\x06 \x03(\v2\x1f.plugin.v1.Metadata.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"w\n" +
+	"\aRollout\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x18\n" +
+	"\ahealthy\x18\x03 \x01(\x05R\ahealthy\x12\x18\n" +
+	"\adesired\x18\x04 \x01(\x05R\adesired\"#\n" +
+	"\tSubscribe\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\x03R\x06cursor\"\x1d\n" +
+	"\x03Ack\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\x03R\x06cursor\"z\n" +
+	"\x13StreamEventsRequest\x124\n" +
+	"\tsubscribe\x18\x01 \x01(\v2\x14.plugin.v1.SubscribeH\x00R\tsubscribe\x12\"\n" +
+	"\x03ack\x18\x02 \x01(\v2\x0e.plugin.v1.AckH\x00R\x03ackB\t\n" +
+	"\amessage\">\n" +
+	"\x14StreamEventsResponse\x12&\n" +
+	"\x05event\x18\x01 \x01(\v2\x10.plugin.v1.EventR\x05event\"o\n" +
+	"\x0ePublishRequest\x12(\n" +
+	"\x05hello\x18\x01 \x01(\v2\x10.plugin.v1.HelloH\x00R\x05hello\x12(\n" +
+	"\x05event\x18\x02 \x01(\v2\x10.plugin.v1.EventH\x00R\x05eventB\t\n" +
+	"\amessage\"5\n" +
+	"\x05Hello\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"v\n" +
+	"\x0fPublishResponse\x124\n" +
+	"\tsubscribe\x18\x01 \x01(\v2\x14.plugin.v1.SubscribeH\x00R\tsubscribe\x12\"\n" +
+	"\x03ack\x18\x02 \x01(\v2\x0e.plugin.v1.AckH\x00R\x03ackB\t\n" +
+	"\amessage2d\n" +
+	"\rSourceService\x12S\n" +
+	"\fStreamEvents\x12\x1e.plugin.v1.StreamEventsRequest\x1a\x1f.plugin.v1.StreamEventsResponse(\x010\x012V\n" +
+	"\x0ePublishService\x12D\n" +
+	"\aPublish\x12\x19.plugin.v1.PublishRequest\x1a\x1a.plugin.v1.PublishResponse(\x010\x01B\x97\x01\n" +
+	"\rcom.plugin.v1B\vPluginProtoP\x01Z4github.com/theleeeo/overseer/api-go/plugin/v1;plugin\xa2\x02\x03PXX\xaa\x02\tPlugin.V1\xca\x02\tPlugin\\V1\xe2\x02\x15Plugin\\V1\\GPBMetadata\xea\x02\n" +
+	"Plugin::V1b\x06proto3"
+
+var (
+	file_plugin_v1_plugin_proto_rawDescOnce sync.Once
+	file_plugin_v1_plugin_proto_rawDescData []byte
+)
+
+func file_plugin_v1_plugin_proto_rawDescGZIP() []byte {
+	file_plugin_v1_plugin_proto_rawDescOnce.Do(func() {
+		file_plugin_v1_plugin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_plugin_v1_plugin_proto_rawDesc), len(file_plugin_v1_plugin_proto_rawDesc)))
+	})
+	return file_plugin_v1_plugin_proto_rawDescData
+}
+
+var file_plugin_v1_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_plugin_v1_plugin_proto_goTypes = []any{
+	(*Event)(nil),                 // 0: plugin.v1.Event
+	(*Metadata)(nil),              // 1: plugin.v1.Metadata
+	(*Rollout)(nil),               // 2: plugin.v1.Rollout
+	(*Subscribe)(nil),             // 3: plugin.v1.Subscribe
+	(*Ack)(nil),                   // 4: plugin.v1.Ack
+	(*StreamEventsRequest)(nil),   // 5: plugin.v1.StreamEventsRequest
+	(*StreamEventsResponse)(nil),  // 6: plugin.v1.StreamEventsResponse
+	(*PublishRequest)(nil),        // 7: plugin.v1.PublishRequest
+	(*Hello)(nil),                 // 8: plugin.v1.Hello
+	(*PublishResponse)(nil),       // 9: plugin.v1.PublishResponse
+	nil,                           // 10: plugin.v1.Metadata.LabelsEntry
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+}
+var file_plugin_v1_plugin_proto_depIdxs = []int32{
+	11, // 0: plugin.v1.Event.deployed_at:type_name -> google.protobuf.Timestamp
+	1,  // 1: plugin.v1.Event.metadata:type_name -> plugin.v1.Metadata
+	2,  // 2: plugin.v1.Event.rollout:type_name -> plugin.v1.Rollout
+	10, // 3: plugin.v1.Metadata.labels:type_name -> plugin.v1.Metadata.LabelsEntry
+	3,  // 4: plugin.v1.StreamEventsRequest.subscribe:type_name -> plugin.v1.Subscribe
+	4,  // 5: plugin.v1.StreamEventsRequest.ack:type_name -> plugin.v1.Ack
+	0,  // 6: plugin.v1.StreamEventsResponse.event:type_name -> plugin.v1.Event
+	8,  // 7: plugin.v1.PublishRequest.hello:type_name -> plugin.v1.Hello
+	0,  // 8: plugin.v1.PublishRequest.event:type_name -> plugin.v1.Event
+	3,  // 9: plugin.v1.PublishResponse.subscribe:type_name -> plugin.v1.Subscribe
+	4,  // 10: plugin.v1.PublishResponse.ack:type_name -> plugin.v1.Ack
+	5,  // 11: plugin.v1.SourceService.StreamEvents:input_type -> plugin.v1.StreamEventsRequest
+	7,  // 12: plugin.v1.PublishService.Publish:input_type -> plugin.v1.PublishRequest
+	6,  // 13: plugin.v1.SourceService.StreamEvents:output_type -> plugin.v1.StreamEventsResponse
+	9,  // 14: plugin.v1.PublishService.Publish:output_type -> plugin.v1.PublishResponse
+	13, // [13:15] is the sub-list for method output_type
+	11, // [11:13] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_plugin_v1_plugin_proto_init() }
+func file_plugin_v1_plugin_proto_init() {
+	if File_plugin_v1_plugin_proto != nil {
+		return
+	}
+	file_plugin_v1_plugin_proto_msgTypes[5].OneofWrappers = []any{
+		(*StreamEventsRequest_Subscribe)(nil),
+		(*StreamEventsRequest_Ack)(nil),
+	}
+	file_plugin_v1_plugin_proto_msgTypes[7].OneofWrappers = []any{
+		(*PublishRequest_Hello)(nil),
+		(*PublishRequest_Event)(nil),
+	}
+	file_plugin_v1_plugin_proto_msgTypes[9].OneofWrappers = []any{
+		(*PublishResponse_Subscribe)(nil),
+		(*PublishResponse_Ack)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_v1_plugin_proto_rawDesc), len(file_plugin_v1_plugin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_plugin_v1_plugin_proto_goTypes,
+		DependencyIndexes: file_plugin_v1_plugin_proto_depIdxs,
+		MessageInfos:      file_plugin_v1_plugin_proto_msgTypes,
+	}.Build()
+	File_plugin_v1_plugin_proto = out.File
+	file_plugin_v1_plugin_proto_goTypes = nil
+	file_plugin_v1_plugin_proto_depIdxs = nil
+}
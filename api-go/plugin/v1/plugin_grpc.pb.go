@@ -0,0 +1,223 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: plugin/v1/plugin.proto
+
+package plugin
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SourceService_StreamEvents_FullMethodName = "/plugin.v1.SourceService/StreamEvents"
+)
+
+// SourceServiceClient is the client API for SourceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Implemented by a source that Overseer connects to.
+type SourceServiceClient interface {
+	// Overseer sends a subscribe message first, then an ack as the events are handled.
+	// Overseer reconnects if the stream ends.
+	StreamEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamEventsRequest, StreamEventsResponse], error)
+}
+
+type sourceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSourceServiceClient(cc grpc.ClientConnInterface) SourceServiceClient {
+	return &sourceServiceClient{cc}
+}
+
+func (c *sourceServiceClient) StreamEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamEventsRequest, StreamEventsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SourceService_ServiceDesc.Streams[0], SourceService_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamEventsRequest, StreamEventsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SourceService_StreamEventsClient = grpc.BidiStreamingClient[StreamEventsRequest, StreamEventsResponse]
+
+// SourceServiceServer is the server API for SourceService service.
+// All implementations should embed UnimplementedSourceServiceServer
+// for forward compatibility.
+//
+// Implemented by a source that Overseer connects to.
+type SourceServiceServer interface {
+	// Overseer sends a subscribe message first, then an ack as the events are handled.
+	// Overseer reconnects if the stream ends.
+	StreamEvents(grpc.BidiStreamingServer[StreamEventsRequest, StreamEventsResponse]) error
+}
+
+// UnimplementedSourceServiceServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSourceServiceServer struct{}
+
+func (UnimplementedSourceServiceServer) StreamEvents(grpc.BidiStreamingServer[StreamEventsRequest, StreamEventsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedSourceServiceServer) testEmbeddedByValue() {}
+
+// UnsafeSourceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SourceServiceServer will
+// result in compilation errors.
+type UnsafeSourceServiceServer interface {
+	mustEmbedUnimplementedSourceServiceServer()
+}
+
+func RegisterSourceServiceServer(s grpc.ServiceRegistrar, srv SourceServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSourceServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SourceService_ServiceDesc, srv)
+}
+
+func _SourceService_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SourceServiceServer).StreamEvents(&grpc.GenericServerStream[StreamEventsRequest, StreamEventsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SourceService_StreamEventsServer = grpc.BidiStreamingServer[StreamEventsRequest, StreamEventsResponse]
+
+// SourceService_ServiceDesc is the grpc.ServiceDesc for SourceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SourceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.v1.SourceService",
+	HandlerType: (*SourceServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _SourceService_StreamEvents_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "plugin/v1/plugin.proto",
+}
+
+const (
+	PublishService_Publish_FullMethodName = "/plugin.v1.PublishService/Publish"
+)
+
+// PublishServiceClient is the client API for PublishService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Implemented by Overseer for the sources that connect to it.
+type PublishServiceClient interface {
+	// The source sends a hello message first and waits for the subscribe message before it sends the events.
+	// The source should reconnect if the stream ends.
+	Publish(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PublishRequest, PublishResponse], error)
+}
+
+type publishServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPublishServiceClient(cc grpc.ClientConnInterface) PublishServiceClient {
+	return &publishServiceClient{cc}
+}
+
+func (c *publishServiceClient) Publish(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PublishRequest, PublishResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PublishService_ServiceDesc.Streams[0], PublishService_Publish_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PublishRequest, PublishResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PublishService_PublishClient = grpc.BidiStreamingClient[PublishRequest, PublishResponse]
+
+// PublishServiceServer is the server API for PublishService service.
+// All implementations should embed UnimplementedPublishServiceServer
+// for forward compatibility.
+//
+// Implemented by Overseer for the sources that connect to it.
+type PublishServiceServer interface {
+	// The source sends a hello message first and waits for the subscribe message before it sends the events.
+	// The source should reconnect if the stream ends.
+	Publish(grpc.BidiStreamingServer[PublishRequest, PublishResponse]) error
+}
+
+// UnimplementedPublishServiceServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPublishServiceServer struct{}
+
+func (UnimplementedPublishServiceServer) Publish(grpc.BidiStreamingServer[PublishRequest, PublishResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Publish not implemented")
+}
+func (UnimplementedPublishServiceServer) testEmbeddedByValue() {}
+
+// UnsafePublishServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PublishServiceServer will
+// result in compilation errors.
+type UnsafePublishServiceServer interface {
+	mustEmbedUnimplementedPublishServiceServer()
+}
+
+func RegisterPublishServiceServer(s grpc.ServiceRegistrar, srv PublishServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPublishServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PublishService_ServiceDesc, srv)
+}
+
+func _PublishService_Publish_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PublishServiceServer).Publish(&grpc.GenericServerStream[PublishRequest, PublishResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PublishService_PublishServer = grpc.BidiStreamingServer[PublishRequest, PublishResponse]
+
+// PublishService_ServiceDesc is the grpc.ServiceDesc for PublishService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PublishService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.v1.PublishService",
+	HandlerType: (*PublishServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Publish",
+			Handler:       _PublishService_Publish_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "plugin/v1/plugin.proto",
+}
@@ -0,0 +1,677 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: policy/v1/policy.proto
+
+package policy
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Declares which versions should be running on the instances of an application, or on a single instance.
+// Every rule is optional. The pinned version and allowed range of an instance replace the ones of its application,
+// the forbidden versions and the upstream rule of both apply.
+type VersionPolicy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApplicationId int32                  `protobuf:"varint,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	// 0 for the policy of the application, which applies to all of its instances.
+	InstanceId int32 `protobuf:"varint,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	// The version that is expected to be running.
+	Pinned string `protobuf:"bytes,3,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	// A range of versions like ">=1.2.0, <2.0.0", "^1.2", "~1.2.3" or "1.x", several ranges can be combined with ||.
+	Allowed string `protobuf:"bytes,4,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	// Versions that must not be running.
+	Forbidden []string `protobuf:"bytes,5,rep,name=forbidden,proto3" json:"forbidden,omitempty"`
+	// Forbids versions that have not yet been deployed to an environment the environment of the instance is promoted from.
+	NotAheadOfUpstream bool `protobuf:"varint,6,opt,name=not_ahead_of_upstream,json=notAheadOfUpstream,proto3" json:"not_ahead_of_upstream,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *VersionPolicy) Reset() {
+	*x = VersionPolicy{}
+	mi := &file_policy_v1_policy_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionPolicy) ProtoMessage() {}
+
+func (x *VersionPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_policy_v1_policy_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionPolicy.ProtoReflect.Descriptor instead.
+func (*VersionPolicy) Descriptor() ([]byte, []int) {
+	return file_policy_v1_policy_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VersionPolicy) GetApplicationId() int32 {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return 0
+}
+
+func (x *VersionPolicy) GetInstanceId() int32 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *VersionPolicy) GetPinned() string {
+	if x != nil {
+		return x.Pinned
+	}
+	return ""
+}
+
+func (x *VersionPolicy) GetAllowed() string {
+	if x != nil {
+		return x.Allowed
+	}
+	return ""
+}
+
+func (x *VersionPolicy) GetForbidden() []string {
+	if x != nil {
+		return x.Forbidden
+	}
+	return nil
+}
+
+func (x *VersionPolicy) GetNotAheadOfUpstream() bool {
+	if x != nil {
+		return x.NotAheadOfUpstream
+	}
+	return false
+}
+
+// A rule of a version policy broken by a deployment.
+type Violation struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	DeploymentId string                 `protobuf:"bytes,1,opt,name=deployment_id,json=deploymentId,proto3" json:"deployment_id,omitempty"`
+	InstanceId   int32                  `protobuf:"varint,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Version      string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// One of pinned, allowed, forbidden or ahead_of_upstream.
+	Rule       string                 `protobuf:"bytes,4,opt,name=rule,proto3" json:"rule,omitempty"`
+	Message    string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	DetectedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=detected_at,json=detectedAt,proto3" json:"detected_at,omitempty"`
+	// The deployment is still the current one of its instance component.
+	Current       bool `protobuf:"varint,7,opt,name=current,proto3" json:"current,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Violation) Reset() {
+	*x = Violation{}
+	mi := &file_policy_v1_policy_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Violation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Violation) ProtoMessage() {}
+
+func (x *Violation) ProtoReflect() protoreflect.Message {
+	mi := &file_policy_v1_policy_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Violation.ProtoReflect.Descriptor instead.
+func (*Violation) Descriptor() ([]byte, []int) {
+	return file_policy_v1_policy_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Violation) GetDeploymentId() string {
+	if x != nil {
+		return x.DeploymentId
+	}
+	return ""
+}
+
+func (x *Violation) GetInstanceId() int32 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *Violation) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Violation) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *Violation) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Violation) GetDetectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DetectedAt
+	}
+	return nil
+}
+
+func (x *Violation) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+type ListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_policy_v1_policy_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_policy_v1_policy_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_policy_v1_policy_proto_rawDescGZIP(), []int{2}
+}
+
+type ListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policies      []*VersionPolicy       `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_policy_v1_policy_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_policy_v1_policy_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_policy_v1_policy_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListResponse) GetPolicies() []*VersionPolicy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+type SetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policy        *VersionPolicy         `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRequest) Reset() {
+	*x = SetRequest{}
+	mi := &file_policy_v1_policy_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRequest) ProtoMessage() {}
+
+func (x *SetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_policy_v1_policy_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRequest.ProtoReflect.Descriptor instead.
+func (*SetRequest) Descriptor() ([]byte, []int) {
+	return file_policy_v1_policy_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SetRequest) GetPolicy() *VersionPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type SetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetResponse) Reset() {
+	*x = SetResponse{}
+	mi := &file_policy_v1_policy_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetResponse) ProtoMessage() {}
+
+func (x *SetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_policy_v1_policy_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetResponse.ProtoReflect.Descriptor instead.
+func (*SetResponse) Descriptor() ([]byte, []int) {
+	return file_policy_v1_policy_proto_rawDescGZIP(), []int{5}
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApplicationId int32                  `protobuf:"varint,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	InstanceId    int32                  `protobuf:"varint,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_policy_v1_policy_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_policy_v1_policy_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_policy_v1_policy_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteRequest) GetApplicationId() int32 {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return 0
+}
+
+func (x *DeleteRequest) GetInstanceId() int32 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_policy_v1_policy_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_policy_v1_policy_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_policy_v1_policy_proto_rawDescGZIP(), []int{7}
+}
+
+type ListViolationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only lists the violations of the instance if it is set.
+	InstanceId int32 `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	// Only lists the violations of the current deployments.
+	CurrentOnly   bool `protobuf:"varint,2,opt,name=current_only,json=currentOnly,proto3" json:"current_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListViolationsRequest) Reset() {
+	*x = ListViolationsRequest{}
+	mi := &file_policy_v1_policy_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListViolationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListViolationsRequest) ProtoMessage() {}
+
+func (x *ListViolationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_policy_v1_policy_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListViolationsRequest.ProtoReflect.Descriptor instead.
+func (*ListViolationsRequest) Descriptor() ([]byte, []int) {
+	return file_policy_v1_policy_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListViolationsRequest) GetInstanceId() int32 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *ListViolationsRequest) GetCurrentOnly() bool {
+	if x != nil {
+		return x.CurrentOnly
+	}
+	return false
+}
+
+type ListViolationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Violations    []*Violation           `protobuf:"bytes,1,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListViolationsResponse) Reset() {
+	*x = ListViolationsResponse{}
+	mi := &file_policy_v1_policy_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListViolationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListViolationsResponse) ProtoMessage() {}
+
+func (x *ListViolationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_policy_v1_policy_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListViolationsResponse.ProtoReflect.Descriptor instead.
+func (*ListViolationsResponse) Descriptor() ([]byte, []int) {
+	return file_policy_v1_policy_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListViolationsResponse) GetViolations() []*Violation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+var File_policy_v1_policy_proto protoreflect.FileDescriptor
+
+const file_policy_v1_policy_proto_rawDesc = "" +
+	"\n" +
+	"\x16policy/v1/policy.proto\x12\tpolicy.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xda\x01\n" +
+	"\rVersionPolicy\x12%\n" +
+	"\x0eapplication_id\x18\x01 \x01(\x05R\rapplicationId\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\x05R\n" +
+	"instanceId\x12\x16\n" +
+	"\x06pinned\x18\x03 \x01(\tR\x06pinned\x12\x18\n" +
+	"\aallowed\x18\x04 \x01(\tR\aallowed\x12\x1c\n" +
+	"\tforbidden\x18\x05 \x03(\tR\tforbidden\x121\n" +
+	"\x15not_ahead_of_upstream\x18\x06 \x01(\bR\x12notAheadOfUpstream\"\xf0\x01\n" +
+	"\tViolation\x12#\n" +
+	"\rdeployment_id\x18\x01 \x01(\tR\fdeploymentId\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\x05R\n" +
+	"instanceId\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x12\n" +
+	"\x04rule\x18\x04 \x01(\tR\x04rule\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12;\n" +
+	"\vdetected_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"detectedAt\x12\x18\n" +
+	"\acurrent\x18\a \x01(\bR\acurrent\"\r\n" +
+	"\vListRequest\"D\n" +
+	"\fListResponse\x124\n" +
+	"\bpolicies\x18\x01 \x03(\v2\x18.policy.v1.VersionPolicyR\bpolicies\">\n" +
+	"\n" +
+	"SetRequest\x120\n" +
+	"\x06policy\x18\x01 \x01(\v2\x18.policy.v1.VersionPolicyR\x06policy\"\r\n" +
+	"\vSetResponse\"W\n" +
+	"\rDeleteRequest\x12%\n" +
+	"\x0eapplication_id\x18\x01 \x01(\x05R\rapplicationId\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\x05R\n" +
+	"instanceId\"\x10\n" +
+	"\x0eDeleteResponse\"[\n" +
+	"\x15ListViolationsRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x05R\n" +
+	"instanceId\x12!\n" +
+	"\fcurrent_only\x18\x02 \x01(\bR\vcurrentOnly\"N\n" +
+	"\x16ListViolationsResponse\x124\n" +
+	"\n" +
+	"violations\x18\x01 \x03(\v2\x14.policy.v1.ViolationR\n" +
+	"violations2\x94\x02\n" +
+	"\rPolicyService\x127\n" +
+	"\x04List\x12\x16.policy.v1.ListRequest\x1a\x17.policy.v1.ListResponse\x124\n" +
+	"\x03Set\x12\x15.policy.v1.SetRequest\x1a\x16.policy.v1.SetResponse\x12=\n" +
+	"\x06Delete\x12\x18.policy.v1.DeleteRequest\x1a\x19.policy.v1.DeleteResponse\x12U\n" +
+	"\x0eListViolations\x12 .policy.v1.ListViolationsRequest\x1a!.policy.v1.ListViolationsResponseB\x97\x01\n" +
+	"\rcom.policy.v1B\vPolicyProtoP\x01Z4github.com/theleeeo/overseer/api-go/policy/v1;policy\xa2\x02\x03PXX\xaa\x02\tPolicy.V1\xca\x02\tPolicy\\V1\xe2\x02\x15Policy\\V1\\GPBMetadata\xea\x02\n" +
+	"Policy::V1b\x06proto3"
+
+var (
+	file_policy_v1_policy_proto_rawDescOnce sync.Once
+	file_policy_v1_policy_proto_rawDescData []byte
+)
+
+func file_policy_v1_policy_proto_rawDescGZIP() []byte {
+	file_policy_v1_policy_proto_rawDescOnce.Do(func() {
+		file_policy_v1_policy_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_policy_v1_policy_proto_rawDesc), len(file_policy_v1_policy_proto_rawDesc)))
+	})
+	return file_policy_v1_policy_proto_rawDescData
+}
+
+var file_policy_v1_policy_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_policy_v1_policy_proto_goTypes = []any{
+	(*VersionPolicy)(nil),          // 0: policy.v1.VersionPolicy
+	(*Violation)(nil),              // 1: policy.v1.Violation
+	(*ListRequest)(nil),            // 2: policy.v1.ListRequest
+	(*ListResponse)(nil),           // 3: policy.v1.ListResponse
+	(*SetRequest)(nil),             // 4: policy.v1.SetRequest
+	(*SetResponse)(nil),            // 5: policy.v1.SetResponse
+	(*DeleteRequest)(nil),          // 6: policy.v1.DeleteRequest
+	(*DeleteResponse)(nil),         // 7: policy.v1.DeleteResponse
+	(*ListViolationsRequest)(nil),  // 8: policy.v1.ListViolationsRequest
+	(*ListViolationsResponse)(nil), // 9: policy.v1.ListViolationsResponse
+	(*timestamppb.Timestamp)(nil),  // 10: google.protobuf.Timestamp
+}
+var file_policy_v1_policy_proto_depIdxs = []int32{
+	10, // 0: policy.v1.Violation.detected_at:type_name -> google.protobuf.Timestamp
+	0,  // 1: policy.v1.ListResponse.policies:type_name -> policy.v1.VersionPolicy
+	0,  // 2: policy.v1.SetRequest.policy:type_name -> policy.v1.VersionPolicy
+	1,  // 3: policy.v1.ListViolationsResponse.violations:type_name -> policy.v1.Violation
+	2,  // 4: policy.v1.PolicyService.List:input_type -> policy.v1.ListRequest
+	4,  // 5: policy.v1.PolicyService.Set:input_type -> policy.v1.SetRequest
+	6,  // 6: policy.v1.PolicyService.Delete:input_type -> policy.v1.DeleteRequest
+	8,  // 7: policy.v1.PolicyService.ListViolations:input_type -> policy.v1.ListViolationsRequest
+	3,  // 8: policy.v1.PolicyService.List:output_type -> policy.v1.ListResponse
+	5,  // 9: policy.v1.PolicyService.Set:output_type -> policy.v1.SetResponse
+	7,  // 10: policy.v1.PolicyService.Delete:output_type -> policy.v1.DeleteResponse
+	9,  // 11: policy.v1.PolicyService.ListViolations:output_type -> policy.v1.ListViolationsResponse
+	8,  // [8:12] is the sub-list for method output_type
+	4,  // [4:8] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_policy_v1_policy_proto_init() }
+func file_policy_v1_policy_proto_init() {
+	if File_policy_v1_policy_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_policy_v1_policy_proto_rawDesc), len(file_policy_v1_policy_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_policy_v1_policy_proto_goTypes,
+		DependencyIndexes: file_policy_v1_policy_proto_depIdxs,
+		MessageInfos:      file_policy_v1_policy_proto_msgTypes,
+	}.Build()
+	File_policy_v1_policy_proto = out.File
+	file_policy_v1_policy_proto_goTypes = nil
+	file_policy_v1_policy_proto_depIdxs = nil
+}
@@ -0,0 +1,756 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: promotion/v1/promotion.proto
+
+package promotion
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// An edge of the promotion graph, versions are promoted from one environment to the other.
+type Edge struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	FromEnvironmentId int32                  `protobuf:"varint,1,opt,name=from_environment_id,json=fromEnvironmentId,proto3" json:"from_environment_id,omitempty"`
+	ToEnvironmentId   int32                  `protobuf:"varint,2,opt,name=to_environment_id,json=toEnvironmentId,proto3" json:"to_environment_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Edge) Reset() {
+	*x = Edge{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Edge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Edge) ProtoMessage() {}
+
+func (x *Edge) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Edge.ProtoReflect.Descriptor instead.
+func (*Edge) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Edge) GetFromEnvironmentId() int32 {
+	if x != nil {
+		return x.FromEnvironmentId
+	}
+	return 0
+}
+
+func (x *Edge) GetToEnvironmentId() int32 {
+	if x != nil {
+		return x.ToEnvironmentId
+	}
+	return 0
+}
+
+// The versions of an application tracked through the promotion graph.
+type ApplicationPromotions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApplicationId int32                  `protobuf:"varint,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	// The versions deployed to any environment, the most recently deployed first.
+	Versions []*VersionPromotion `protobuf:"bytes,2,rep,name=versions,proto3" json:"versions,omitempty"`
+	// The versions awaiting promotion, ordered by when they entered the environment they are promoted from.
+	Pending       []*PendingPromotion `protobuf:"bytes,3,rep,name=pending,proto3" json:"pending,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplicationPromotions) Reset() {
+	*x = ApplicationPromotions{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplicationPromotions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplicationPromotions) ProtoMessage() {}
+
+func (x *ApplicationPromotions) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplicationPromotions.ProtoReflect.Descriptor instead.
+func (*ApplicationPromotions) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ApplicationPromotions) GetApplicationId() int32 {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return 0
+}
+
+func (x *ApplicationPromotions) GetVersions() []*VersionPromotion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *ApplicationPromotions) GetPending() []*PendingPromotion {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+type VersionPromotion struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// The environments the version was deployed to, in the order it entered them.
+	Stages []*StageEntry `protobuf:"bytes,2,rep,name=stages,proto3" json:"stages,omitempty"`
+	// The stages the version was deployed past without first being deployed to them.
+	Skipped       []*SkippedStage `protobuf:"bytes,3,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VersionPromotion) Reset() {
+	*x = VersionPromotion{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionPromotion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionPromotion) ProtoMessage() {}
+
+func (x *VersionPromotion) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionPromotion.ProtoReflect.Descriptor instead.
+func (*VersionPromotion) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VersionPromotion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *VersionPromotion) GetStages() []*StageEntry {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+func (x *VersionPromotion) GetSkipped() []*SkippedStage {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+// When a version entered an environment, the first time it was deployed to an instance there.
+type StageEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvironmentId int32                  `protobuf:"varint,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`
+	EnteredAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=entered_at,json=enteredAt,proto3" json:"entered_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StageEntry) Reset() {
+	*x = StageEntry{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StageEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StageEntry) ProtoMessage() {}
+
+func (x *StageEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StageEntry.ProtoReflect.Descriptor instead.
+func (*StageEntry) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StageEntry) GetEnvironmentId() int32 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+func (x *StageEntry) GetEnteredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EnteredAt
+	}
+	return nil
+}
+
+// The version entered to_environment_id without having been in environment_id,
+// or in any of the other environments to_environment_id is promoted from.
+type SkippedStage struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	EnvironmentId   int32                  `protobuf:"varint,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`
+	ToEnvironmentId int32                  `protobuf:"varint,2,opt,name=to_environment_id,json=toEnvironmentId,proto3" json:"to_environment_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SkippedStage) Reset() {
+	*x = SkippedStage{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SkippedStage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkippedStage) ProtoMessage() {}
+
+func (x *SkippedStage) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkippedStage.ProtoReflect.Descriptor instead.
+func (*SkippedStage) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SkippedStage) GetEnvironmentId() int32 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+func (x *SkippedStage) GetToEnvironmentId() int32 {
+	if x != nil {
+		return x.ToEnvironmentId
+	}
+	return 0
+}
+
+// A version deployed to an environment but not yet to an environment it is promoted to.
+type PendingPromotion struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Version           string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	FromEnvironmentId int32                  `protobuf:"varint,2,opt,name=from_environment_id,json=fromEnvironmentId,proto3" json:"from_environment_id,omitempty"`
+	ToEnvironmentId   int32                  `protobuf:"varint,3,opt,name=to_environment_id,json=toEnvironmentId,proto3" json:"to_environment_id,omitempty"`
+	// The versions currently deployed to the environment it is promoted to.
+	Deployed []string `protobuf:"bytes,4,rep,name=deployed,proto3" json:"deployed,omitempty"`
+	// When the version entered the environment it is promoted from.
+	Since         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PendingPromotion) Reset() {
+	*x = PendingPromotion{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingPromotion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingPromotion) ProtoMessage() {}
+
+func (x *PendingPromotion) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingPromotion.ProtoReflect.Descriptor instead.
+func (*PendingPromotion) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PendingPromotion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *PendingPromotion) GetFromEnvironmentId() int32 {
+	if x != nil {
+		return x.FromEnvironmentId
+	}
+	return 0
+}
+
+func (x *PendingPromotion) GetToEnvironmentId() int32 {
+	if x != nil {
+		return x.ToEnvironmentId
+	}
+	return 0
+}
+
+func (x *PendingPromotion) GetDeployed() []string {
+	if x != nil {
+		return x.Deployed
+	}
+	return nil
+}
+
+func (x *PendingPromotion) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+type ListEdgesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEdgesRequest) Reset() {
+	*x = ListEdgesRequest{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEdgesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEdgesRequest) ProtoMessage() {}
+
+func (x *ListEdgesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEdgesRequest.ProtoReflect.Descriptor instead.
+func (*ListEdgesRequest) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{6}
+}
+
+type ListEdgesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Edges         []*Edge                `protobuf:"bytes,1,rep,name=edges,proto3" json:"edges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEdgesResponse) Reset() {
+	*x = ListEdgesResponse{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEdgesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEdgesResponse) ProtoMessage() {}
+
+func (x *ListEdgesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEdgesResponse.ProtoReflect.Descriptor instead.
+func (*ListEdgesResponse) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListEdgesResponse) GetEdges() []*Edge {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+type SetSourcesRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	EnvironmentId        int32                  `protobuf:"varint,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`
+	SourceEnvironmentIds []int32                `protobuf:"varint,2,rep,packed,name=source_environment_ids,json=sourceEnvironmentIds,proto3" json:"source_environment_ids,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *SetSourcesRequest) Reset() {
+	*x = SetSourcesRequest{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSourcesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSourcesRequest) ProtoMessage() {}
+
+func (x *SetSourcesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSourcesRequest.ProtoReflect.Descriptor instead.
+func (*SetSourcesRequest) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SetSourcesRequest) GetEnvironmentId() int32 {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return 0
+}
+
+func (x *SetSourcesRequest) GetSourceEnvironmentIds() []int32 {
+	if x != nil {
+		return x.SourceEnvironmentIds
+	}
+	return nil
+}
+
+type SetSourcesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSourcesResponse) Reset() {
+	*x = SetSourcesResponse{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSourcesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSourcesResponse) ProtoMessage() {}
+
+func (x *SetSourcesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSourcesResponse.ProtoReflect.Descriptor instead.
+func (*SetSourcesResponse) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{9}
+}
+
+type ListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{10}
+}
+
+type ListResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Applications  []*ApplicationPromotions `protobuf:"bytes,1,rep,name=applications,proto3" json:"applications,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListResponse) GetApplications() []*ApplicationPromotions {
+	if x != nil {
+		return x.Applications
+	}
+	return nil
+}
+
+var File_promotion_v1_promotion_proto protoreflect.FileDescriptor
+
+const file_promotion_v1_promotion_proto_rawDesc = "" +
+	"\n" +
+	"\x1cpromotion/v1/promotion.proto\x12\fpromotion.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"b\n" +
+	"\x04Edge\x12.\n" +
+	"\x13from_environment_id\x18\x01 \x01(\x05R\x11fromEnvironmentId\x12*\n" +
+	"\x11to_environment_id\x18\x02 \x01(\x05R\x0ftoEnvironmentId\"\xb4\x01\n" +
+	"\x15ApplicationPromotions\x12%\n" +
+	"\x0eapplication_id\x18\x01 \x01(\x05R\rapplicationId\x12:\n" +
+	"\bversions\x18\x02 \x03(\v2\x1e.promotion.v1.VersionPromotionR\bversions\x128\n" +
+	"\apending\x18\x03 \x03(\v2\x1e.promotion.v1.PendingPromotionR\apending\"\x94\x01\n" +
+	"\x10VersionPromotion\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x120\n" +
+	"\x06stages\x18\x02 \x03(\v2\x18.promotion.v1.StageEntryR\x06stages\x124\n" +
+	"\askipped\x18\x03 \x03(\v2\x1a.promotion.v1.SkippedStageR\askipped\"n\n" +
+	"\n" +
+	"StageEntry\x12%\n" +
+	"\x0eenvironment_id\x18\x01 \x01(\x05R\renvironmentId\x129\n" +
+	"\n" +
+	"entered_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tenteredAt\"a\n" +
+	"\fSkippedStage\x12%\n" +
+	"\x0eenvironment_id\x18\x01 \x01(\x05R\renvironmentId\x12*\n" +
+	"\x11to_environment_id\x18\x02 \x01(\x05R\x0ftoEnvironmentId\"\xd6\x01\n" +
+	"\x10PendingPromotion\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12.\n" +
+	"\x13from_environment_id\x18\x02 \x01(\x05R\x11fromEnvironmentId\x12*\n" +
+	"\x11to_environment_id\x18\x03 \x01(\x05R\x0ftoEnvironmentId\x12\x1a\n" +
+	"\bdeployed\x18\x04 \x03(\tR\bdeployed\x120\n" +
+	"\x05since\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\"\x12\n" +
+	"\x10ListEdgesRequest\"=\n" +
+	"\x11ListEdgesResponse\x12(\n" +
+	"\x05edges\x18\x01 \x03(\v2\x12.promotion.v1.EdgeR\x05edges\"p\n" +
+	"\x11SetSourcesRequest\x12%\n" +
+	"\x0eenvironment_id\x18\x01 \x01(\x05R\renvironmentId\x124\n" +
+	"\x16source_environment_ids\x18\x02 \x03(\x05R\x14sourceEnvironmentIds\"\x14\n" +
+	"\x12SetSourcesResponse\"\r\n" +
+	"\vListRequest\"W\n" +
+	"\fListResponse\x12G\n" +
+	"\fapplications\x18\x01 \x03(\v2#.promotion.v1.ApplicationPromotionsR\fapplications2\xf0\x01\n" +
+	"\x10PromotionService\x12L\n" +
+	"\tListEdges\x12\x1e.promotion.v1.ListEdgesRequest\x1a\x1f.promotion.v1.ListEdgesResponse\x12O\n" +
+	"\n" +
+	"SetSources\x12\x1f.promotion.v1.SetSourcesRequest\x1a .promotion.v1.SetSourcesResponse\x12=\n" +
+	"\x04List\x12\x19.promotion.v1.ListRequest\x1a\x1a.promotion.v1.ListResponseB\xaf\x01\n" +
+	"\x10com.promotion.v1B\x0ePromotionProtoP\x01Z:github.com/theleeeo/overseer/api-go/promotion/v1;promotion\xa2\x02\x03PXX\xaa\x02\fPromotion.V1\xca\x02\fPromotion\\V1\xe2\x02\x18Promotion\\V1\\GPBMetadata\xea\x02\rPromotion::V1b\x06proto3"
+
+var (
+	file_promotion_v1_promotion_proto_rawDescOnce sync.Once
+	file_promotion_v1_promotion_proto_rawDescData []byte
+)
+
+func file_promotion_v1_promotion_proto_rawDescGZIP() []byte {
+	file_promotion_v1_promotion_proto_rawDescOnce.Do(func() {
+		file_promotion_v1_promotion_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_promotion_v1_promotion_proto_rawDesc), len(file_promotion_v1_promotion_proto_rawDesc)))
+	})
+	return file_promotion_v1_promotion_proto_rawDescData
+}
+
+var file_promotion_v1_promotion_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_promotion_v1_promotion_proto_goTypes = []any{
+	(*Edge)(nil),                  // 0: promotion.v1.Edge
+	(*ApplicationPromotions)(nil), // 1: promotion.v1.ApplicationPromotions
+	(*VersionPromotion)(nil),      // 2: promotion.v1.VersionPromotion
+	(*StageEntry)(nil),            // 3: promotion.v1.StageEntry
+	(*SkippedStage)(nil),          // 4: promotion.v1.SkippedStage
+	(*PendingPromotion)(nil),      // 5: promotion.v1.PendingPromotion
+	(*ListEdgesRequest)(nil),      // 6: promotion.v1.ListEdgesRequest
+	(*ListEdgesResponse)(nil),     // 7: promotion.v1.ListEdgesResponse
+	(*SetSourcesRequest)(nil),     // 8: promotion.v1.SetSourcesRequest
+	(*SetSourcesResponse)(nil),    // 9: promotion.v1.SetSourcesResponse
+	(*ListRequest)(nil),           // 10: promotion.v1.ListRequest
+	(*ListResponse)(nil),          // 11: promotion.v1.ListResponse
+	(*timestamppb.Timestamp)(nil), // 12: google.protobuf.Timestamp
+}
+var file_promotion_v1_promotion_proto_depIdxs = []int32{
+	2,  // 0: promotion.v1.ApplicationPromotions.versions:type_name -> promotion.v1.VersionPromotion
+	5,  // 1: promotion.v1.ApplicationPromotions.pending:type_name -> promotion.v1.PendingPromotion
+	3,  // 2: promotion.v1.VersionPromotion.stages:type_name -> promotion.v1.StageEntry
+	4,  // 3: promotion.v1.VersionPromotion.skipped:type_name -> promotion.v1.SkippedStage
+	12, // 4: promotion.v1.StageEntry.entered_at:type_name -> google.protobuf.Timestamp
+	12, // 5: promotion.v1.PendingPromotion.since:type_name -> google.protobuf.Timestamp
+	0,  // 6: promotion.v1.ListEdgesResponse.edges:type_name -> promotion.v1.Edge
+	1,  // 7: promotion.v1.ListResponse.applications:type_name -> promotion.v1.ApplicationPromotions
+	6,  // 8: promotion.v1.PromotionService.ListEdges:input_type -> promotion.v1.ListEdgesRequest
+	8,  // 9: promotion.v1.PromotionService.SetSources:input_type -> promotion.v1.SetSourcesRequest
+	10, // 10: promotion.v1.PromotionService.List:input_type -> promotion.v1.ListRequest
+	7,  // 11: promotion.v1.PromotionService.ListEdges:output_type -> promotion.v1.ListEdgesResponse
+	9,  // 12: promotion.v1.PromotionService.SetSources:output_type -> promotion.v1.SetSourcesResponse
+	11, // 13: promotion.v1.PromotionService.List:output_type -> promotion.v1.ListResponse
+	11, // [11:14] is the sub-list for method output_type
+	8,  // [8:11] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_promotion_v1_promotion_proto_init() }
+func file_promotion_v1_promotion_proto_init() {
+	if File_promotion_v1_promotion_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_promotion_v1_promotion_proto_rawDesc), len(file_promotion_v1_promotion_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_promotion_v1_promotion_proto_goTypes,
+		DependencyIndexes: file_promotion_v1_promotion_proto_depIdxs,
+		MessageInfos:      file_promotion_v1_promotion_proto_msgTypes,
+	}.Build()
+	File_promotion_v1_promotion_proto = out.File
+	file_promotion_v1_promotion_proto_goTypes = nil
+	file_promotion_v1_promotion_proto_depIdxs = nil
+}
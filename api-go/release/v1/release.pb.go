@@ -0,0 +1,430 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: release/v1/release.proto
+
+package release
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// The latest version of an application available in its image repository.
+type Release struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApplicationId int32                  `protobuf:"varint,1,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	// The image repository the version was found in, like registry.example.com/team/api.
+	Repository string `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`
+	Version    string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// When the tags of the repository were last listed.
+	CheckedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Release) Reset() {
+	*x = Release{}
+	mi := &file_release_v1_release_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Release) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Release) ProtoMessage() {}
+
+func (x *Release) ProtoReflect() protoreflect.Message {
+	mi := &file_release_v1_release_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Release.ProtoReflect.Descriptor instead.
+func (*Release) Descriptor() ([]byte, []int) {
+	return file_release_v1_release_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Release) GetApplicationId() int32 {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return 0
+}
+
+func (x *Release) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *Release) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Release) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+// The version deployed to an instance compared with the latest release of its application.
+type InstanceRelease struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int32                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	ApplicationId int32                  `protobuf:"varint,2,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	// The current version of the primary component, empty if nothing is deployed.
+	Deployed string `protobuf:"bytes,3,opt,name=deployed,proto3" json:"deployed,omitempty"`
+	// The latest release of the application, empty if it is unknown.
+	Available string `protobuf:"bytes,4,opt,name=available,proto3" json:"available,omitempty"`
+	// The available version is later than the deployed one.
+	// It is never set if either of them is not a version, like latest or a commit hash.
+	UpdateAvailable bool `protobuf:"varint,5,opt,name=update_available,json=updateAvailable,proto3" json:"update_available,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *InstanceRelease) Reset() {
+	*x = InstanceRelease{}
+	mi := &file_release_v1_release_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstanceRelease) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceRelease) ProtoMessage() {}
+
+func (x *InstanceRelease) ProtoReflect() protoreflect.Message {
+	mi := &file_release_v1_release_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceRelease.ProtoReflect.Descriptor instead.
+func (*InstanceRelease) Descriptor() ([]byte, []int) {
+	return file_release_v1_release_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *InstanceRelease) GetInstanceId() int32 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *InstanceRelease) GetApplicationId() int32 {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return 0
+}
+
+func (x *InstanceRelease) GetDeployed() string {
+	if x != nil {
+		return x.Deployed
+	}
+	return ""
+}
+
+func (x *InstanceRelease) GetAvailable() string {
+	if x != nil {
+		return x.Available
+	}
+	return ""
+}
+
+func (x *InstanceRelease) GetUpdateAvailable() bool {
+	if x != nil {
+		return x.UpdateAvailable
+	}
+	return false
+}
+
+type ListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_release_v1_release_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_release_v1_release_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_release_v1_release_proto_rawDescGZIP(), []int{2}
+}
+
+type ListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Releases      []*Release             `protobuf:"bytes,1,rep,name=releases,proto3" json:"releases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_release_v1_release_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_release_v1_release_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_release_v1_release_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListResponse) GetReleases() []*Release {
+	if x != nil {
+		return x.Releases
+	}
+	return nil
+}
+
+type ListInstancesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInstancesRequest) Reset() {
+	*x = ListInstancesRequest{}
+	mi := &file_release_v1_release_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInstancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInstancesRequest) ProtoMessage() {}
+
+func (x *ListInstancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_release_v1_release_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInstancesRequest.ProtoReflect.Descriptor instead.
+func (*ListInstancesRequest) Descriptor() ([]byte, []int) {
+	return file_release_v1_release_proto_rawDescGZIP(), []int{4}
+}
+
+type ListInstancesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instances     []*InstanceRelease     `protobuf:"bytes,1,rep,name=instances,proto3" json:"instances,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInstancesResponse) Reset() {
+	*x = ListInstancesResponse{}
+	mi := &file_release_v1_release_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInstancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInstancesResponse) ProtoMessage() {}
+
+func (x *ListInstancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_release_v1_release_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInstancesResponse.ProtoReflect.Descriptor instead.
+func (*ListInstancesResponse) Descriptor() ([]byte, []int) {
+	return file_release_v1_release_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListInstancesResponse) GetInstances() []*InstanceRelease {
+	if x != nil {
+		return x.Instances
+	}
+	return nil
+}
+
+var File_release_v1_release_proto protoreflect.FileDescriptor
+
+const file_release_v1_release_proto_rawDesc = "" +
+	"\n" +
+	"\x18release/v1/release.proto\x12\n" +
+	"release.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa5\x01\n" +
+	"\aRelease\x12%\n" +
+	"\x0eapplication_id\x18\x01 \x01(\x05R\rapplicationId\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x02 \x01(\tR\n" +
+	"repository\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x129\n" +
+	"\n" +
+	"checked_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\"\xbe\x01\n" +
+	"\x0fInstanceRelease\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x05R\n" +
+	"instanceId\x12%\n" +
+	"\x0eapplication_id\x18\x02 \x01(\x05R\rapplicationId\x12\x1a\n" +
+	"\bdeployed\x18\x03 \x01(\tR\bdeployed\x12\x1c\n" +
+	"\tavailable\x18\x04 \x01(\tR\tavailable\x12)\n" +
+	"\x10update_available\x18\x05 \x01(\bR\x0fupdateAvailable\"\r\n" +
+	"\vListRequest\"?\n" +
+	"\fListResponse\x12/\n" +
+	"\breleases\x18\x01 \x03(\v2\x13.release.v1.ReleaseR\breleases\"\x16\n" +
+	"\x14ListInstancesRequest\"R\n" +
+	"\x15ListInstancesResponse\x129\n" +
+	"\tinstances\x18\x01 \x03(\v2\x1b.release.v1.InstanceReleaseR\tinstances2\xa1\x01\n" +
+	"\x0eReleaseService\x129\n" +
+	"\x04List\x12\x17.release.v1.ListRequest\x1a\x18.release.v1.ListResponse\x12T\n" +
+	"\rListInstances\x12 .release.v1.ListInstancesRequest\x1a!.release.v1.ListInstancesResponseB\x9f\x01\n" +
+	"\x0ecom.release.v1B\fReleaseProtoP\x01Z6github.com/theleeeo/overseer/api-go/release/v1;release\xa2\x02\x03RXX\xaa\x02\n" +
+	"Release.V1\xca\x02\n" +
+	"Release\\V1\xe2\x02\x16Release\\V1\\GPBMetadata\xea\x02\vRelease::V1b\x06proto3"
+
+var (
+	file_release_v1_release_proto_rawDescOnce sync.Once
+	file_release_v1_release_proto_rawDescData []byte
+)
+
+func file_release_v1_release_proto_rawDescGZIP() []byte {
+	file_release_v1_release_proto_rawDescOnce.Do(func() {
+		file_release_v1_release_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_release_v1_release_proto_rawDesc), len(file_release_v1_release_proto_rawDesc)))
+	})
+	return file_release_v1_release_proto_rawDescData
+}
+
+var file_release_v1_release_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_release_v1_release_proto_goTypes = []any{
+	(*Release)(nil),               // 0: release.v1.Release
+	(*InstanceRelease)(nil),       // 1: release.v1.InstanceRelease
+	(*ListRequest)(nil),           // 2: release.v1.ListRequest
+	(*ListResponse)(nil),          // 3: release.v1.ListResponse
+	(*ListInstancesRequest)(nil),  // 4: release.v1.ListInstancesRequest
+	(*ListInstancesResponse)(nil), // 5: release.v1.ListInstancesResponse
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+}
+var file_release_v1_release_proto_depIdxs = []int32{
+	6, // 0: release.v1.Release.checked_at:type_name -> google.protobuf.Timestamp
+	0, // 1: release.v1.ListResponse.releases:type_name -> release.v1.Release
+	1, // 2: release.v1.ListInstancesResponse.instances:type_name -> release.v1.InstanceRelease
+	2, // 3: release.v1.ReleaseService.List:input_type -> release.v1.ListRequest
+	4, // 4: release.v1.ReleaseService.ListInstances:input_type -> release.v1.ListInstancesRequest
+	3, // 5: release.v1.ReleaseService.List:output_type -> release.v1.ListResponse
+	5, // 6: release.v1.ReleaseService.ListInstances:output_type -> release.v1.ListInstancesResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_release_v1_release_proto_init() }
+func file_release_v1_release_proto_init() {
+	if File_release_v1_release_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_release_v1_release_proto_rawDesc), len(file_release_v1_release_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_release_v1_release_proto_goTypes,
+		DependencyIndexes: file_release_v1_release_proto_depIdxs,
+		MessageInfos:      file_release_v1_release_proto_msgTypes,
+	}.Build()
+	File_release_v1_release_proto = out.File
+	file_release_v1_release_proto_goTypes = nil
+	file_release_v1_release_proto_depIdxs = nil
+}
@@ -0,0 +1,165 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: release/v1/release.proto
+
+package release
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ReleaseService_List_FullMethodName          = "/release.v1.ReleaseService/List"
+	ReleaseService_ListInstances_FullMethodName = "/release.v1.ReleaseService/ListInstances"
+)
+
+// ReleaseServiceClient is the client API for ReleaseService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Tells which versions could be deployed, as discovered in the image repositories of the applications.
+type ReleaseServiceClient interface {
+	// Lists the latest release of every application that has one.
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	// Compares the version deployed to every instance with the latest release of its application.
+	ListInstances(ctx context.Context, in *ListInstancesRequest, opts ...grpc.CallOption) (*ListInstancesResponse, error)
+}
+
+type releaseServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReleaseServiceClient(cc grpc.ClientConnInterface) ReleaseServiceClient {
+	return &releaseServiceClient{cc}
+}
+
+func (c *releaseServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, ReleaseService_List_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) ListInstances(ctx context.Context, in *ListInstancesRequest, opts ...grpc.CallOption) (*ListInstancesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInstancesResponse)
+	err := c.cc.Invoke(ctx, ReleaseService_ListInstances_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReleaseServiceServer is the server API for ReleaseService service.
+// All implementations should embed UnimplementedReleaseServiceServer
+// for forward compatibility.
+//
+// Tells which versions could be deployed, as discovered in the image repositories of the applications.
+type ReleaseServiceServer interface {
+	// Lists the latest release of every application that has one.
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	// Compares the version deployed to every instance with the latest release of its application.
+	ListInstances(context.Context, *ListInstancesRequest) (*ListInstancesResponse, error)
+}
+
+// UnimplementedReleaseServiceServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedReleaseServiceServer struct{}
+
+func (UnimplementedReleaseServiceServer) List(context.Context, *ListRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedReleaseServiceServer) ListInstances(context.Context, *ListInstancesRequest) (*ListInstancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInstances not implemented")
+}
+func (UnimplementedReleaseServiceServer) testEmbeddedByValue() {}
+
+// UnsafeReleaseServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReleaseServiceServer will
+// result in compilation errors.
+type UnsafeReleaseServiceServer interface {
+	mustEmbedUnimplementedReleaseServiceServer()
+}
+
+func RegisterReleaseServiceServer(s grpc.ServiceRegistrar, srv ReleaseServiceServer) {
+	// If the following call pancis, it indicates UnimplementedReleaseServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ReleaseService_ServiceDesc, srv)
+}
+
+func _ReleaseService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReleaseService_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_ListInstances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInstancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).ListInstances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReleaseService_ListInstances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).ListInstances(ctx, req.(*ListInstancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ReleaseService_ServiceDesc is the grpc.ServiceDesc for ReleaseService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReleaseService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "release.v1.ReleaseService",
+	HandlerType: (*ReleaseServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _ReleaseService_List_Handler,
+		},
+		{
+			MethodName: "ListInstances",
+			Handler:    _ReleaseService_ListInstances_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "release/v1/release.proto",
+}
@@ -2,12 +2,8 @@ package app
 
 import (
 	"context"
-	"errors"
-	"overseer/repo"
+	"log/slog"
 	"time"
-
-	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Environment struct {
@@ -27,73 +23,169 @@ type Instance struct {
 	EnvironmentId int32  `json:"environment_id"`
 	ApplicationId int32  `json:"application_id"`
 	Name          string `json:"name"`
+	// PrimaryComponent is the component whose version is shown for the instance in the version matrix.
+	PrimaryComponent string `json:"primary_component"`
 }
 
+// DefaultComponent is the primary component of instances created without one.
+const DefaultComponent = "main"
+
 type Deployment struct {
-	InstanceId int32     `json:"instance_id"`
-	Version    string    `json:"version"`
+	Id         string `json:"id"`
+	InstanceId int32  `json:"instance_id"`
+	// Component is the part of the instance that was deployed, like main, sidecar or migrator.
+	// Every component has its own current deployment.
+	Component string `json:"component"`
+	Version   string `json:"version"`
+	// DeployedAt is the time of the deployment as reported by the source.
 	DeployedAt time.Time `json:"deployed_at"`
+	// ReceivedAt is the time the deployment was registered.
+	ReceivedAt time.Time `json:"received_at"`
+	// Sequence is a monotonic number supplied by the source, like the Nomad raft index, or zero if it has none.
+	Sequence int64 `json:"sequence,omitempty"`
+	// Late is set if a deployment ordered after this one was already current when it was registered.
+	Late bool `json:"late"`
+	// ClockSkew is set if the source reported a deployment time too far after the time it was received.
+	ClockSkew bool `json:"clock_skew"`
+	// Undeployed is set if the component was stopped or removed at DeployedAt, the version is then empty.
+	// An instance without deployments has never been seen, which is not the same as undeployed.
+	Undeployed bool `json:"undeployed"`
+	// Source is the name of the source that reported the deployment, empty if it was registered through the API.
+	Source string `json:"source,omitempty"`
+	// Freeze is the name of the freeze window the deployment was made in, empty if there was none.
+	Freeze   string             `json:"freeze,omitempty"`
+	Metadata DeploymentMetadata `json:"metadata"`
 }
 
-type App struct {
-	db *repo.Queries
+// DeploymentMetadata describes what was deployed and who deployed it, every field is optional.
+type DeploymentMetadata struct {
+	// Image is the reference of the deployed container image, without the digest.
+	Image       string `json:"image,omitempty"`
+	ImageDigest string `json:"image_digest,omitempty"`
+	GitCommit   string `json:"git_commit,omitempty"`
+	// BuildURL links to the CI build that produced the deployment.
+	BuildURL string `json:"build_url,omitempty"`
+	// Deployer is the person or pipeline that made the deployment.
+	Deployer string `json:"deployer,omitempty"`
+	// Labels are free-form metadata from the source.
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
-func New(db *repo.Queries) *App {
-	return &App{db: db}
+// OrderedAt is the time used to order the deployment, the deployment time capped at the time it was received,
+// so that a source with a clock running ahead can't hide the deployments registered after it.
+func (d Deployment) OrderedAt() time.Time {
+	if !d.ReceivedAt.IsZero() && d.DeployedAt.After(d.ReceivedAt) {
+		return d.ReceivedAt
+	}
+	return d.DeployedAt
 }
 
-func (a *App) ListApplications(ctx context.Context) ([]Application, error) {
-	apps, err := a.db.ListApplications(ctx)
-	if err != nil {
-		return nil, err
+// Supersedes reports whether d is ordered after, or at the same position as, other.
+// Deployments are ordered by their sequence if both have one and they are of the same source,
+// as the sequences of different sources can't be compared, otherwise by OrderedAt.
+func (d Deployment) Supersedes(other Deployment) bool {
+	if d.Sequence != 0 && other.Sequence != 0 && d.Source == other.Source {
+		return d.Sequence >= other.Sequence
 	}
+	return !d.OrderedAt().Before(other.OrderedAt())
+}
 
-	var result []Application
-	for _, app := range apps {
-		result = append(result, Application{
-			Id:    app.ID,
-			Name:  app.Name,
-			Order: app.SortOrder,
-		})
+// DefaultMaxClockSkew is used when Options.MaxClockSkew is not set.
+const DefaultMaxClockSkew = time.Minute
+
+// DefaultSourceRestartDelay is used when Options.SourceRestartDelay is not set.
+const DefaultSourceRestartDelay = 5 * time.Second
+
+type Options struct {
+	// Retention decides how long the deployment history of each environment is kept.
+	Retention RetentionPolicies
+	// MaxClockSkew is how far after the time a deployment is received its reported deployment time may be,
+	// before it is flagged as clock skewed.
+	MaxClockSkew time.Duration
+	// SourceRestartDelay is how long a failed source waits before it is started again.
+	SourceRestartDelay time.Duration
+	// ComponentRules route the events of the sources to instance components, the first matching rule is used.
+	ComponentRules []ComponentRule
+	// Repositories maps application names to the image repositories their releases are discovered in,
+	// the other applications use the repository of their deployed images.
+	Repositories map[string]string
+	// GitRepositories maps application names to local clones or mirrors of their git repositories,
+	// which are read by Git to tell the changes between versions.
+	GitRepositories map[string]string
+	Git             GitReader
+	// Notifier sends the messages to the notification channels, which are not sent any if it is nil.
+	Notifier WebhookSender
+}
+
+type App struct {
+	store          Store
+	retention      RetentionPolicies
+	maxClockSkew   time.Duration
+	componentRules []ComponentRule
+	sources        *sourceRegistry
+	// repositoryOverrides are the image repositories of Options.Repositories.
+	repositoryOverrides map[string]string
+	gitRepositories     map[string]string
+	git                 GitReader
+	// events are the published events waiting to be sent to the webhooks and notification channels.
+	events *eventQueue
+	// webhookWake wakes RunWebhooks when deliveries are queued.
+	webhookWake   chan struct{}
+	notifier      WebhookSender
+	notifications *notifications
+}
+
+func New(store Store, opts Options) *App {
+	if opts.MaxClockSkew == 0 {
+		opts.MaxClockSkew = DefaultMaxClockSkew
+	}
+	if opts.SourceRestartDelay == 0 {
+		opts.SourceRestartDelay = DefaultSourceRestartDelay
 	}
 
-	return result, nil
+	return &App{
+		store:          store,
+		retention:      opts.Retention,
+		maxClockSkew:   opts.MaxClockSkew,
+		componentRules: opts.ComponentRules,
+		sources:        &sourceRegistry{restartDelay: opts.SourceRestartDelay},
+
+		repositoryOverrides: opts.Repositories,
+		gitRepositories:     opts.GitRepositories,
+		git:                 opts.Git,
+		events:              newEventQueue(maxQueuedEvents),
+		webhookWake:         make(chan struct{}, 1),
+		notifier:            opts.Notifier,
+		notifications:       &notifications{batches: map[int32]*notificationBatch{}, templates: map[int32]channelTemplate{}},
+	}
+}
+
+func (a *App) ListApplications(ctx context.Context) ([]Application, error) {
+	return a.store.ListApplications(ctx)
 }
 
 func (a *App) CreateApplication(ctx context.Context, name string) (Application, error) {
-	app, err := a.db.CreateApplication(ctx, name)
-	if err != nil {
-		return Application{}, err
+	if name == "" {
+		return Application{}, invalid("name is required")
 	}
-	return Application{
-		Id:    app.ID,
-		Name:  app.Name,
-		Order: app.SortOrder,
-	}, nil
+
+	return a.store.CreateApplication(ctx, name)
 }
 
 func (a *App) UpdateApplication(ctx context.Context, id int32, name string) (Application, error) {
-	app, err := a.db.UpdateApplication(ctx, repo.UpdateApplicationParams{
-		ID:   id,
-		Name: name,
-	})
-	if err != nil {
-		return Application{}, err
+	if name == "" {
+		return Application{}, invalid("name is required")
 	}
-	return Application{
-		Id:    app.ID,
-		Name:  app.Name,
-		Order: app.SortOrder,
-	}, nil
+
+	return a.store.UpdateApplication(ctx, id, name)
 }
 
 func (a *App) DeleteApplication(ctx context.Context, id int32) error {
-	return a.db.DeleteApplication(ctx, id)
+	return a.store.DeleteApplication(ctx, id)
 }
 
 func (a *App) ReorderApplications(ctx context.Context, ids []int32) error {
-	if err := a.db.ReorderApplications(ctx, ids); err != nil {
+	if err := a.store.ReorderApplications(ctx, ids); err != nil {
 		return err
 	}
 
@@ -101,56 +193,31 @@ func (a *App) ReorderApplications(ctx context.Context, ids []int32) error {
 }
 
 func (a *App) ListEnvironments(ctx context.Context) ([]Environment, error) {
-	envs, err := a.db.ListEnvironments(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	var result []Environment
-	for _, env := range envs {
-		result = append(result, Environment{
-			Id:    env.ID,
-			Name:  env.Name,
-			Order: env.SortOrder,
-		})
-	}
-
-	return result, nil
+	return a.store.ListEnvironments(ctx)
 }
 
 func (a *App) CreateEnvironment(ctx context.Context, name string) (Environment, error) {
-	env, err := a.db.CreateEnvironment(ctx, name)
-	if err != nil {
-		return Environment{}, err
+	if name == "" {
+		return Environment{}, invalid("name is required")
 	}
-	return Environment{
-		Id:    env.ID,
-		Name:  env.Name,
-		Order: env.SortOrder,
-	}, nil
+
+	return a.store.CreateEnvironment(ctx, name)
 }
 
 func (a *App) UpdateEnvironment(ctx context.Context, id int32, name string) (Environment, error) {
-	env, err := a.db.UpdateEnvironment(ctx, repo.UpdateEnvironmentParams{
-		ID:   id,
-		Name: name,
-	})
-	if err != nil {
-		return Environment{}, err
+	if name == "" {
+		return Environment{}, invalid("name is required")
 	}
-	return Environment{
-		Id:    env.ID,
-		Name:  env.Name,
-		Order: env.SortOrder,
-	}, nil
+
+	return a.store.UpdateEnvironment(ctx, id, name)
 }
 
 func (a *App) DeleteEnvironment(ctx context.Context, id int32) error {
-	return a.db.DeleteEnvironment(ctx, id)
+	return a.store.DeleteEnvironment(ctx, id)
 }
 
 func (a *App) ReorderEnvironments(ctx context.Context, ids []int32) error {
-	if err := a.db.ReorderEnvironments(ctx, ids); err != nil {
+	if err := a.store.ReorderEnvironments(ctx, ids); err != nil {
 		return err
 	}
 
@@ -161,46 +228,55 @@ type CreateInstanceParameters struct {
 	EnvironmentId int32
 	ApplicationId int32
 	Name          string
+	// PrimaryComponent defaults to DefaultComponent.
+	PrimaryComponent string
 }
 
 func (a *App) CreateInstance(ctx context.Context, params CreateInstanceParameters) (int32, error) {
 	if params.EnvironmentId == 0 {
-		return 0, errors.New("environment id is required")
+		return 0, invalid("environment id is required")
 	}
 
 	if params.ApplicationId == 0 {
-		return 0, errors.New("application id is required")
+		return 0, invalid("application id is required")
 	}
 
 	if params.Name == "" {
-		return 0, errors.New("name is required")
+		return 0, invalid("name is required")
 	}
 
-	return a.db.CreateInstance(ctx, repo.CreateInstanceParams{
-		EnvironmentID: params.EnvironmentId,
-		ApplicationID: params.ApplicationId,
-		Name:          params.Name,
-	})
+	if params.PrimaryComponent == "" {
+		params.PrimaryComponent = DefaultComponent
+	}
+
+	return a.store.CreateInstance(ctx, params)
 }
 
 type UpdateInstanceParameters struct {
 	Id   int32
 	Name string
+	// PrimaryComponent is left unchanged if it is empty.
+	PrimaryComponent string
 }
 
 func (a *App) UpdateInstance(ctx context.Context, params UpdateInstanceParameters) error {
 	if params.Id == 0 {
-		return errors.New("instance id is required")
+		return invalid("instance id is required")
 	}
 
 	if params.Name == "" {
-		return errors.New("name is required")
+		return invalid("name is required")
 	}
 
-	return a.db.UpdateInstance(ctx, repo.UpdateInstanceParams{
-		ID:   params.Id,
-		Name: params.Name,
-	})
+	if params.PrimaryComponent == "" {
+		instance, err := a.store.GetInstance(ctx, params.Id)
+		if err != nil {
+			return err
+		}
+		params.PrimaryComponent = instance.PrimaryComponent
+	}
+
+	return a.store.UpdateInstance(ctx, params)
 }
 
 type ListInstancesParameters struct {
@@ -208,66 +284,43 @@ type ListInstancesParameters struct {
 }
 
 func (a *App) ListInstances(ctx context.Context, params ListInstancesParameters) ([]Instance, error) {
-	instances, err := a.db.ListInstances(ctx, params.Name)
-	if err != nil {
-		return nil, err
-	}
-
-	var result []Instance
-	for _, i := range instances {
-		result = append(result, Instance{
-			Id:            i.ID,
-			EnvironmentId: i.EnvironmentID,
-			ApplicationId: i.ApplicationID,
-			Name:          i.Name,
-		})
-	}
-
-	return result, nil
+	return a.store.ListInstances(ctx, params.Name)
 }
 
 type InstanceAndDeploymentResult struct {
-	Instance   Instance    `json:"instance"`
+	Instance Instance `json:"instance"`
+	// Deployment is the current deployment of the primary component of the instance.
 	Deployment *Deployment `json:"deployment,omitempty"`
+	// Rollout is the rollout of the primary component, nil if the source doesn't report rollouts.
+	Rollout *Rollout `json:"rollout,omitempty"`
 }
 
 func (a *App) ListInstancesAndDeployment(ctx context.Context) ([]InstanceAndDeploymentResult, error) {
-	rows, err := a.db.ListInstancesAndDeployment(ctx)
+	results, err := a.store.ListInstancesAndDeployment(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var result []InstanceAndDeploymentResult
-	for _, r := range rows {
-		result = append(result, InstanceAndDeploymentResult{
-			Instance: Instance{
-				Id:            r.ID,
-				EnvironmentId: r.EnvironmentID,
-				ApplicationId: r.ApplicationID,
-				Name:          r.Name,
-			},
-			Deployment: func() *Deployment {
-				if r.DeployedAt.Valid {
-					return &Deployment{
-						InstanceId: r.ID,
-						Version:    r.Version.String,
-						DeployedAt: r.DeployedAt.Time,
-					}
-				}
-				return nil
-			}(),
-		})
+	rollouts, err := a.rolloutsOf(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	for i, r := range results {
+		if rollout, ok := rollouts[componentKey{r.Instance.Id, r.Instance.PrimaryComponent}]; ok {
+			results[i].Rollout = &rollout
+		}
+	}
+
+	return results, nil
 }
 
 func (a *App) DeleteInstance(ctx context.Context, id int32) error {
 	if id == 0 {
-		return errors.New("instance id is required")
+		return invalid("instance id is required")
 	}
 
-	return a.db.DeleteInstance(ctx, id)
+	return a.store.DeleteInstance(ctx, id)
 }
 
 type GetInstanceParameters struct {
@@ -276,65 +329,133 @@ type GetInstanceParameters struct {
 
 func (a *App) GetInstance(ctx context.Context, params GetInstanceParameters) (Instance, error) {
 	if params.Id == 0 {
-		return Instance{}, errors.New("instance id is required")
-	}
-
-	i, err := a.db.GetInstance(ctx, params.Id)
-	if err != nil {
-		return Instance{}, err
+		return Instance{}, invalid("instance id is required")
 	}
 
-	return Instance{
-		Id:            i.ID,
-		EnvironmentId: i.EnvironmentID,
-		ApplicationId: i.ApplicationID,
-		Name:          i.Name,
-	}, nil
+	return a.store.GetInstance(ctx, params.Id)
 }
 
 type ListDeploymentsParameters struct{}
 
 func (a *App) ListDeployments(ctx context.Context, params ListDeploymentsParameters) ([]Deployment, error) {
-	deployments, err := a.db.ListDeployments(ctx)
+	return a.store.ListDeployments(ctx)
+}
+
+// ListCurrentDeployments lists the current deployment of every component of every instance.
+func (a *App) ListCurrentDeployments(ctx context.Context) ([]Deployment, error) {
+	return a.store.ListCurrentDeployments(ctx)
+}
+
+// ListComponents lists the current deployment of every component of the instance.
+func (a *App) ListComponents(ctx context.Context, instanceId int32) ([]Deployment, error) {
+	if _, err := a.store.GetInstance(ctx, instanceId); err != nil {
+		return nil, err
+	}
+
+	deployments, err := a.store.ListCurrentDeployments(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var result []Deployment
 	for _, d := range deployments {
-		result = append(result, Deployment{
-			InstanceId: d.InstanceID,
-			Version:    d.Version,
-			DeployedAt: d.DeployedAt.Time,
-		})
+		if d.InstanceId == instanceId {
+			result = append(result, d)
+		}
 	}
-
 	return result, nil
 }
 
 type RegisterDeploymentParams struct {
 	InstanceId int32
+	// Component defaults to the primary component of the instance.
+	Component  string
 	Version    string
 	DeployedAt time.Time
+	// Sequence is an optional monotonic number from the source that takes precedence over DeployedAt for ordering.
+	Sequence int64
+	Metadata DeploymentMetadata
+	// Undeployed registers that the component was stopped or removed, instead of a version.
+	Undeployed bool
+	// Source is the name of the source that reported the deployment.
+	Source string
+	// OverrideFreeze registers the deployment even if it is registered during a freeze window that rejects deployments.
+	OverrideFreeze bool
 }
 
-func (a *App) RegisterDeployment(ctx context.Context, params RegisterDeploymentParams) error {
+// RegisterDeployment registers the deployment and makes it the current deployment of the instance,
+// unless it is late and a deployment ordered after it is already current.
+// The deployment is evaluated against the version policies of the instance and the violations are recorded.
+// A deployment registered during a freeze window is flagged with it, or rejected with ErrFrozen if the window rejects it,
+// and a deployment reported by a source is flagged with the window it was made in.
+func (a *App) RegisterDeployment(ctx context.Context, params RegisterDeploymentParams) (Deployment, error) {
 	if params.InstanceId == 0 {
-		return errors.New("instance id is required")
+		return Deployment{}, invalid("instance id is required")
+	}
+
+	if params.Undeployed {
+		if params.Version != "" {
+			return Deployment{}, invalid("an undeployment has no version")
+		}
+	} else if params.Version == "" {
+		return Deployment{}, invalid("version is required")
 	}
 
-	if params.Version == "" {
-		return errors.New("version is required")
+	if params.Sequence < 0 {
+		return Deployment{}, invalid("sequence can not be negative")
 	}
 
+	if params.Component == "" {
+		instance, err := a.store.GetInstance(ctx, params.InstanceId)
+		if err != nil {
+			return Deployment{}, err
+		}
+		params.Component = instance.PrimaryComponent
+	}
+
+	now := time.Now().UTC()
 	if params.DeployedAt.IsZero() {
-		params.DeployedAt = time.Now().UTC()
+		params.DeployedAt = now
+	}
+
+	freeze, err := a.checkFreeze(ctx, params, now)
+	if err != nil {
+		return Deployment{}, err
 	}
 
-	return a.db.RegisterDeployment(ctx, repo.RegisterDeploymentParams{
-		ID:         pgtype.UUID{Bytes: uuid.New(), Valid: true},
-		InstanceID: params.InstanceId,
+	d, previous, err := a.store.RegisterDeployment(ctx, Deployment{
+		InstanceId: params.InstanceId,
+		Component:  params.Component,
 		Version:    params.Version,
-		DeployedAt: pgtype.Timestamptz{Time: params.DeployedAt, Valid: true},
+		DeployedAt: params.DeployedAt,
+		ReceivedAt: now,
+		Sequence:   params.Sequence,
+		ClockSkew:  params.DeployedAt.Sub(now) > a.maxClockSkew,
+		Undeployed: params.Undeployed,
+		Source:     params.Source,
+		Freeze:     freeze,
+		Metadata:   params.Metadata,
 	})
+	if err != nil {
+		return Deployment{}, err
+	}
+
+	if d.ClockSkew {
+		slog.Warn("deployment time is ahead of the time it was received", "instance", d.InstanceId, "version", d.Version, "deployedAt", d.DeployedAt, "receivedAt", d.ReceivedAt)
+	}
+	if d.Late {
+		slog.Warn("late deployment, a later one is already current", "instance", d.InstanceId, "version", d.Version, "deployedAt", d.DeployedAt)
+	}
+
+	// The deployment is registered even if it can't be evaluated, the policies only describe what should be running.
+	if err := a.checkPolicies(ctx, d); err != nil {
+		slog.Error("evaluating the version policies", "instance", d.InstanceId, "version", d.Version, "error", err)
+	}
+
+	// A late deployment does not change what is running, so there is nothing to tell.
+	if !d.Late {
+		a.publish(ctx, Event{Type: EventDeployment, Deployment: &d, PreviousVersion: previous.Version}, d.InstanceId, 0)
+	}
+
+	return d, nil
 }
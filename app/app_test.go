@@ -0,0 +1,430 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"overseer/app"
+	"overseer/storage/memory"
+)
+
+// fixture is an App on an in-memory store with the instance api-prod of the application api in the environment prod.
+type fixture struct {
+	app      *app.App
+	store    app.Store
+	env      app.Environment
+	appl     app.Application
+	instance int32
+}
+
+func newFixture(t *testing.T, opts app.Options) fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	store := memory.New()
+	a := app.New(store, opts)
+
+	env, err := a.CreateEnvironment(ctx, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	appl, err := a.CreateApplication(ctx, "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance, err := a.CreateInstance(ctx, app.CreateInstanceParameters{
+		EnvironmentId: env.Id,
+		ApplicationId: appl.Id,
+		Name:          "api-prod",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fixture{app: a, store: store, env: env, appl: appl, instance: instance}
+}
+
+// current returns the current deployment of the component of the fixture instance.
+func (f fixture) current(t *testing.T, component string) app.Deployment {
+	t.Helper()
+
+	components, err := f.app.ListComponents(context.Background(), f.instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range components {
+		if d.Component == component {
+			return d
+		}
+	}
+	t.Fatalf("no current deployment of %s", component)
+	return app.Deployment{}
+}
+
+func TestRegisterDeploymentValidation(t *testing.T) {
+	f := newFixture(t, app.Options{})
+
+	tests := []struct {
+		name   string
+		params app.RegisterDeploymentParams
+		target error
+	}{
+		{"no instance", app.RegisterDeploymentParams{Version: "1.0.0"}, nil},
+		{"no version", app.RegisterDeploymentParams{InstanceId: f.instance}, nil},
+		{"undeployment with a version", app.RegisterDeploymentParams{InstanceId: f.instance, Version: "1.0.0", Undeployed: true}, nil},
+		{"negative sequence", app.RegisterDeploymentParams{InstanceId: f.instance, Version: "1.0.0", Sequence: -1}, nil},
+		{"unknown instance", app.RegisterDeploymentParams{InstanceId: 9999, Version: "1.0.0"}, app.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.app.RegisterDeployment(context.Background(), tt.params)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.target != nil && !errors.Is(err, tt.target) {
+				t.Fatalf("expected error %v, got %v", tt.target, err)
+			}
+		})
+	}
+}
+
+func TestRegisterDeployment(t *testing.T) {
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	type step struct {
+		params        app.RegisterDeploymentParams
+		wantLate      bool
+		wantClockSkew bool
+	}
+
+	tests := []struct {
+		name        string
+		steps       []step
+		wantCurrent string
+	}{
+		{
+			name: "newer deployment becomes current",
+			steps: []step{
+				{params: app.RegisterDeploymentParams{Version: "1.0.0", DeployedAt: base}},
+				{params: app.RegisterDeploymentParams{Version: "1.1.0", DeployedAt: base.Add(time.Minute)}},
+			},
+			wantCurrent: "1.1.0",
+		},
+		{
+			name: "older deployment is late",
+			steps: []step{
+				{params: app.RegisterDeploymentParams{Version: "1.1.0", DeployedAt: base.Add(time.Minute)}},
+				{params: app.RegisterDeploymentParams{Version: "1.0.0", DeployedAt: base}, wantLate: true},
+			},
+			wantCurrent: "1.1.0",
+		},
+		{
+			name: "sequence takes precedence over the deployment time",
+			steps: []step{
+				{params: app.RegisterDeploymentParams{Version: "1.1.0", DeployedAt: base, Sequence: 2}},
+				{params: app.RegisterDeploymentParams{Version: "1.0.0", DeployedAt: base.Add(time.Minute), Sequence: 1}, wantLate: true},
+			},
+			wantCurrent: "1.1.0",
+		},
+		{
+			name: "sequences of different sources are ordered by time",
+			steps: []step{
+				{params: app.RegisterDeploymentParams{Version: "1.0.0", DeployedAt: base, Sequence: 500, Source: "nomad"}},
+				{params: app.RegisterDeploymentParams{Version: "1.1.0", DeployedAt: base.Add(time.Minute), Sequence: 3, Source: "docker"}},
+			},
+			wantCurrent: "1.1.0",
+		},
+		{
+			name: "deployment at the same time replaces the current one",
+			steps: []step{
+				{params: app.RegisterDeploymentParams{Version: "1.0.0", DeployedAt: base}},
+				{params: app.RegisterDeploymentParams{Version: "1.0.1", DeployedAt: base}},
+			},
+			wantCurrent: "1.0.1",
+		},
+		{
+			name: "deployment ahead of the clock is flagged and ordered at the time it was received",
+			steps: []step{
+				{params: app.RegisterDeploymentParams{Version: "1.0.0", DeployedAt: time.Now().Add(24 * time.Hour)}, wantClockSkew: true},
+				{params: app.RegisterDeploymentParams{Version: "1.1.0"}},
+			},
+			wantCurrent: "1.1.0",
+		},
+		{
+			name: "undeployment",
+			steps: []step{
+				{params: app.RegisterDeploymentParams{Version: "1.0.0", DeployedAt: base}},
+				{params: app.RegisterDeploymentParams{Undeployed: true, DeployedAt: base.Add(time.Minute)}},
+			},
+			wantCurrent: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, app.Options{})
+			ctx := context.Background()
+
+			for i, s := range tt.steps {
+				s.params.InstanceId = f.instance
+				d, err := f.app.RegisterDeployment(ctx, s.params)
+				if err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+				if d.Component != app.DefaultComponent {
+					t.Fatalf("step %d: expected the primary component, got %q", i, d.Component)
+				}
+				if d.Late != s.wantLate || d.ClockSkew != s.wantClockSkew {
+					t.Fatalf("step %d: expected late %t and clock skew %t, got %+v", i, s.wantLate, s.wantClockSkew, d)
+				}
+			}
+
+			if current := f.current(t, app.DefaultComponent); current.Version != tt.wantCurrent {
+				t.Fatalf("expected the current version %q, got %+v", tt.wantCurrent, current)
+			}
+		})
+	}
+}
+
+func TestDeploymentSupersedes(t *testing.T) {
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		d     app.Deployment
+		other app.Deployment
+		want  bool
+	}{
+		{
+			name:  "later deployment",
+			d:     app.Deployment{DeployedAt: t0.Add(time.Minute)},
+			other: app.Deployment{DeployedAt: t0},
+			want:  true,
+		},
+		{
+			name:  "earlier deployment",
+			d:     app.Deployment{DeployedAt: t0},
+			other: app.Deployment{DeployedAt: t0.Add(time.Minute)},
+			want:  false,
+		},
+		{
+			name:  "same time",
+			d:     app.Deployment{DeployedAt: t0},
+			other: app.Deployment{DeployedAt: t0},
+			want:  true,
+		},
+		{
+			name:  "higher sequence deployed earlier",
+			d:     app.Deployment{DeployedAt: t0, Sequence: 2},
+			other: app.Deployment{DeployedAt: t0.Add(time.Minute), Sequence: 1},
+			want:  true,
+		},
+		{
+			name:  "lower sequence deployed later",
+			d:     app.Deployment{DeployedAt: t0.Add(time.Minute), Sequence: 1},
+			other: app.Deployment{DeployedAt: t0, Sequence: 2},
+			want:  false,
+		},
+		{
+			name:  "only one has a sequence",
+			d:     app.Deployment{DeployedAt: t0.Add(time.Minute), Sequence: 1},
+			other: app.Deployment{DeployedAt: t0},
+			want:  true,
+		},
+		{
+			name:  "higher sequence of another source deployed earlier",
+			d:     app.Deployment{DeployedAt: t0, Sequence: 500, Source: "nomad"},
+			other: app.Deployment{DeployedAt: t0.Add(time.Minute), Sequence: 3, Source: "docker"},
+			want:  false,
+		},
+		{
+			name:  "lower sequence of another source deployed later",
+			d:     app.Deployment{DeployedAt: t0.Add(time.Minute), Sequence: 3, Source: "docker"},
+			other: app.Deployment{DeployedAt: t0, Sequence: 500, Source: "nomad"},
+			want:  true,
+		},
+		{
+			name:  "sequences of the same source",
+			d:     app.Deployment{DeployedAt: t0, Sequence: 500, Source: "nomad"},
+			other: app.Deployment{DeployedAt: t0.Add(time.Minute), Sequence: 3, Source: "nomad"},
+			want:  true,
+		},
+		{
+			name:  "deployment time capped at the time it was received",
+			d:     app.Deployment{DeployedAt: t0.Add(time.Hour), ReceivedAt: t0},
+			other: app.Deployment{DeployedAt: t0.Add(time.Minute)},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.Supersedes(tt.other); got != tt.want {
+				t.Fatalf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// The versions deployed to the instance, one a day until now.
+	versions := []string{"1.0.0", "1.0.0", "1.1.0", "1.1.0", "1.1.0", "1.2.0"}
+
+	tests := []struct {
+		name      string
+		retention app.RetentionPolicies
+		// wantPruned maps the indexes of the pruned deployments in versions to their reasons.
+		wantPruned map[int]string
+	}{
+		{
+			name:       "no policy",
+			wantPruned: map[int]string{},
+		},
+		{
+			name:       "max age",
+			retention:  app.RetentionPolicies{Default: app.RetentionPolicy{MaxAge: 60 * time.Hour}},
+			wantPruned: map[int]string{0: app.PruneReasonExpired, 1: app.PruneReasonExpired, 2: app.PruneReasonExpired},
+		},
+		{
+			name:       "compact",
+			retention:  app.RetentionPolicies{Default: app.RetentionPolicy{Compact: true}},
+			wantPruned: map[int]string{1: app.PruneReasonCompacted, 3: app.PruneReasonCompacted, 4: app.PruneReasonCompacted},
+		},
+		{
+			name: "environment policy",
+			retention: app.RetentionPolicies{
+				Default:      app.RetentionPolicy{Compact: true},
+				Environments: map[string]app.RetentionPolicy{"prod": {}},
+			},
+			wantPruned: map[int]string{},
+		},
+		{
+			name: "current deployment is kept",
+			retention: app.RetentionPolicies{
+				Default: app.RetentionPolicy{MaxAge: time.Minute},
+			},
+			wantPruned: map[int]string{0: app.PruneReasonExpired, 1: app.PruneReasonExpired, 2: app.PruneReasonExpired, 3: app.PruneReasonExpired, 4: app.PruneReasonExpired},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, app.Options{Retention: tt.retention})
+			ctx := context.Background()
+
+			ids := map[string]int{}
+			for i, v := range versions {
+				d, err := f.app.RegisterDeployment(ctx, app.RegisterDeploymentParams{
+					InstanceId: f.instance,
+					Version:    v,
+					DeployedAt: now.Add(time.Duration(i-len(versions)+1) * 24 * time.Hour),
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids[d.Id] = i
+			}
+
+			plan, err := f.app.PlanPrune(ctx, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plan.Total != len(versions) {
+				t.Fatalf("expected %d deployments in the history, got %d", len(versions), plan.Total)
+			}
+
+			got := map[int]string{}
+			for _, d := range plan.Pruned {
+				if d.Environment != "prod" {
+					t.Fatalf("expected the environment prod, got %q", d.Environment)
+				}
+				got[ids[d.Id]] = d.Reason
+			}
+			if len(got) != len(tt.wantPruned) {
+				t.Fatalf("expected %v to be pruned, got %v", tt.wantPruned, got)
+			}
+			for i, reason := range tt.wantPruned {
+				if got[i] != reason {
+					t.Fatalf("expected %v to be pruned, got %v", tt.wantPruned, got)
+				}
+			}
+
+			deleted, err := f.app.Prune(ctx, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if deleted != int64(len(tt.wantPruned)) {
+				t.Fatalf("expected %d deleted deployments, got %d", len(tt.wantPruned), deleted)
+			}
+
+			history, err := f.app.ListDeployments(ctx, app.ListDeploymentsParameters{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) != len(versions)-len(tt.wantPruned) {
+				t.Fatalf("expected %d deployments left, got %d", len(versions)-len(tt.wantPruned), len(history))
+			}
+			if current := f.current(t, app.DefaultComponent); current.Version != "1.2.0" {
+				t.Fatalf("expected the current deployment to be kept, got %+v", current)
+			}
+		})
+	}
+}
+
+func TestInvalidArguments(t *testing.T) {
+	f := newFixture(t, app.Options{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"empty environment name", func() error { _, err := f.app.CreateEnvironment(ctx, ""); return err }, "name is required"},
+		{"empty application name", func() error { _, err := f.app.UpdateApplication(ctx, f.appl.Id, ""); return err }, "name is required"},
+		{"empty instance name", func() error {
+			_, err := f.app.CreateInstance(ctx, app.CreateInstanceParameters{EnvironmentId: f.env.Id, ApplicationId: f.appl.Id})
+			return err
+		}, "name is required"},
+		{"webhook url", func() error {
+			_, err := f.app.CreateWebhook(ctx, app.Webhook{Name: "ci", URL: "ftp://example.com"})
+			return err
+		}, `invalid webhook url "ftp://example.com"`},
+		{"notification template", func() error {
+			_, err := f.app.CreateNotificationChannel(ctx, app.NotificationChannel{Name: "ops", Kind: app.ChannelSlack, URL: "https://example.com", Template: "{{.Nope"})
+			return err
+		}, "invalid template: "},
+		{"cron schedule", func() error {
+			_, err := f.app.CreateFreezeWindow(ctx, app.FreezeWindow{Name: "weekends", Schedule: "0 18 * * FRIDAY", DurationSeconds: 3600})
+			return err
+		}, `invalid day of week in schedule "0 18 * * FRIDAY": `},
+		{"version range", func() error {
+			return f.app.SetVersionPolicy(ctx, app.VersionPolicy{ApplicationId: f.appl.Id, Allowed: ">=1.x"})
+		}, `invalid version range ">=1.x": `},
+		{"promoted from itself", func() error { return f.app.SetPromotionSources(ctx, f.env.Id, []int32{f.env.Id}) }, "an environment can not be promoted from itself"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, app.ErrInvalid) {
+				t.Fatalf("expected an error matching ErrInvalid, got %v", err)
+			}
+			// The message is the one of the error, without the one of ErrInvalid.
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Fatalf("expected the message to start with %q, got %q", tt.want, err.Error())
+			}
+		})
+	}
+
+	// Errors that are not about the arguments don't match.
+	if _, err := f.app.CreateEnvironment(ctx, f.env.Name); errors.Is(err, app.ErrInvalid) || !errors.Is(err, app.ErrAlreadyExists) {
+		t.Fatalf("expected only ErrAlreadyExists for a taken name, got %v", err)
+	}
+}
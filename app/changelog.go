@@ -0,0 +1,230 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultMaxCommits is the number of commits listed in a changelog when ChangelogParams.MaxCommits is not set.
+const DefaultMaxCommits = 250
+
+type Commit struct {
+	Hash        string `json:"hash"`
+	Author      string `json:"author"`
+	AuthorEmail string `json:"author_email"`
+	// Date is when the commit was authored.
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+}
+
+// FileChange summarizes the changes to a file.
+type FileChange struct {
+	Path string `json:"path"`
+	// Additions and Deletions are the number of changed lines, both zero for binary files.
+	Additions int  `json:"additions"`
+	Deletions int  `json:"deletions"`
+	Binary    bool `json:"binary,omitempty"`
+}
+
+// Changelog is what changed in the git repository of an application between two of its versions.
+type Changelog struct {
+	ApplicationId int32  `json:"application_id"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	// FromCommit and ToCommit are the commits the versions were resolved to.
+	FromCommit string `json:"from_commit"`
+	ToCommit   string `json:"to_commit"`
+	// Commits are the commits in To but not in From, newest first.
+	Commits []Commit `json:"commits"`
+	// Truncated is set if there were more commits than the ones listed.
+	Truncated bool         `json:"truncated"`
+	Files     []FileChange `json:"files"`
+	// Additions and Deletions are the total number of changed lines.
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+// GitReader reads the history of local clones or mirrors of git repositories.
+type GitReader interface {
+	// Resolve returns the commit a revision, like a tag or a commit hash, points to, false if there is none.
+	Resolve(ctx context.Context, repository, revision string) (string, bool, error)
+	// Log lists the commits reachable from to but not from, newest first, at most limit of them.
+	Log(ctx context.Context, repository, from, to string, limit int) ([]Commit, error)
+	// Diff summarizes the changes to the files between two commits.
+	Diff(ctx context.Context, repository, from, to string) ([]FileChange, error)
+}
+
+type ChangelogParams struct {
+	ApplicationId int32
+	// From and To are versions of the application, usually deployed ones.
+	From string
+	To   string
+	// MaxCommits defaults to DefaultMaxCommits.
+	MaxCommits int
+}
+
+// Changelog lists the commits and changed files between two versions of an application.
+//
+// A version is resolved to the tag named after it, with or without a v prefix, otherwise to the git commit
+// recorded with the deployments of the version, or otherwise to the commit if the version is a commit hash.
+func (a *App) Changelog(ctx context.Context, params ChangelogParams) (Changelog, error) {
+	if params.ApplicationId == 0 {
+		return Changelog{}, invalid("application id is required")
+	}
+	if params.From == "" || params.To == "" {
+		return Changelog{}, invalid("the versions to compare are required")
+	}
+	if params.MaxCommits < 0 {
+		return Changelog{}, invalid("max commits can not be negative")
+	}
+	if params.MaxCommits == 0 {
+		params.MaxCommits = DefaultMaxCommits
+	}
+
+	apps, err := a.store.ListApplications(ctx)
+	if err != nil {
+		return Changelog{}, err
+	}
+
+	var application *Application
+	for i := range apps {
+		if apps[i].Id == params.ApplicationId {
+			application = &apps[i]
+		}
+	}
+	if application == nil {
+		return Changelog{}, fmt.Errorf("application %d: %w", params.ApplicationId, ErrNotFound)
+	}
+
+	repository, ok := a.gitRepositories[application.Name]
+	if !ok || a.git == nil {
+		return Changelog{}, fmt.Errorf("git repository of application %s: %w", application.Name, ErrNotFound)
+	}
+
+	from, err := a.resolveVersion(ctx, repository, application.Id, params.From)
+	if err != nil {
+		return Changelog{}, err
+	}
+	to, err := a.resolveVersion(ctx, repository, application.Id, params.To)
+	if err != nil {
+		return Changelog{}, err
+	}
+
+	// One more commit is listed to tell whether there are more than the ones returned.
+	commits, err := a.git.Log(ctx, repository, from, to, params.MaxCommits+1)
+	if err != nil {
+		return Changelog{}, fmt.Errorf("listing the commits: %w", err)
+	}
+
+	files, err := a.git.Diff(ctx, repository, from, to)
+	if err != nil {
+		return Changelog{}, fmt.Errorf("listing the changed files: %w", err)
+	}
+
+	changelog := Changelog{
+		ApplicationId: application.Id,
+		From:          params.From,
+		To:            params.To,
+		FromCommit:    from,
+		ToCommit:      to,
+		Commits:       commits,
+		Files:         files,
+	}
+	if changelog.Commits == nil {
+		changelog.Commits = []Commit{}
+	}
+	if changelog.Files == nil {
+		changelog.Files = []FileChange{}
+	}
+	if len(commits) > params.MaxCommits {
+		changelog.Commits = commits[:params.MaxCommits]
+		changelog.Truncated = true
+	}
+	for _, f := range files {
+		changelog.Additions += f.Additions
+		changelog.Deletions += f.Deletions
+	}
+
+	return changelog, nil
+}
+
+// resolveVersion returns the commit of a version of the application, see Changelog.
+func (a *App) resolveVersion(ctx context.Context, repository string, applicationId int32, version string) (string, error) {
+	if strings.HasPrefix(version, "-") {
+		return "", invalid("invalid version %q", version)
+	}
+
+	revisions := []string{"refs/tags/" + version}
+	if trimmed, ok := strings.CutPrefix(version, "v"); ok {
+		revisions = append(revisions, "refs/tags/"+trimmed)
+	} else {
+		revisions = append(revisions, "refs/tags/v"+version)
+	}
+
+	commits, err := a.deployedCommits(ctx, applicationId, version)
+	if err != nil {
+		return "", err
+	}
+	revisions = append(revisions, commits...)
+
+	if isCommitHash(version) {
+		revisions = append(revisions, version)
+	}
+
+	for _, revision := range revisions {
+		commit, ok, err := a.git.Resolve(ctx, repository, revision)
+		if err != nil {
+			return "", fmt.Errorf("resolving %s: %w", revision, err)
+		}
+		if ok {
+			return commit, nil
+		}
+	}
+
+	return "", fmt.Errorf("version %s in the git repository: %w", version, ErrNotFound)
+}
+
+// deployedCommits returns the git commits recorded with the deployments of the version of the application.
+func (a *App) deployedCommits(ctx context.Context, applicationId int32, version string) ([]string, error) {
+	instances, err := a.store.ListInstances(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ofApplication := map[int32]bool{}
+	for _, i := range instances {
+		ofApplication[i.Id] = i.ApplicationId == applicationId
+	}
+
+	deployments, err := a.store.ListDeployments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []string
+	seen := map[string]bool{}
+	for _, d := range deployments {
+		commit := d.Metadata.GitCommit
+		if !ofApplication[d.InstanceId] || d.Version != version || !isCommitHash(commit) || seen[commit] {
+			continue
+		}
+		seen[commit] = true
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// isCommitHash reports whether s looks like a full or abbreviated git commit hash.
+func isCommitHash(s string) bool {
+	if len(s) < 7 || len(s) > 64 {
+		return false
+	}
+	for _, r := range s {
+		if !('0' <= r && r <= '9' || 'a' <= r && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
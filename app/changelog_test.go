@@ -0,0 +1,197 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"overseer/app"
+	"overseer/gitrepo"
+)
+
+// gitRepo is a git repository in a temporary directory.
+type gitRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newGitRepo(t *testing.T) gitRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	r := gitRepo{t: t, dir: t.TempDir()}
+	r.git("init", "--quiet", "--initial-branch=main")
+	return r
+}
+
+// git runs the git command in the repository and returns its trimmed output.
+func (r gitRepo) git(args ...string) string {
+	r.t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=Ada Lovelace",
+		"GIT_AUTHOR_EMAIL=ada@example.com",
+		"GIT_COMMITTER_NAME=Ada Lovelace",
+		"GIT_COMMITTER_EMAIL=ada@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes the file and commits it with the subject, and returns the hash of the commit.
+func (r gitRepo) commit(subject, file, content string) string {
+	r.t.Helper()
+
+	if err := os.WriteFile(filepath.Join(r.dir, file), []byte(content), 0o644); err != nil {
+		r.t.Fatal(err)
+	}
+	r.git("add", "--all")
+	r.git("commit", "--quiet", "--message", subject)
+	return r.git("rev-parse", "HEAD")
+}
+
+func TestChangelog(t *testing.T) {
+	repo := newGitRepo(t)
+	initial := repo.commit("Initial commit", "main.go", "package main\n")
+	repo.git("tag", "1.0.0")
+	second := repo.commit("Add the handler", "handler.go", "package main\n\nfunc handle() {}\n")
+	repo.git("tag", "--annotate", "--message", "Release 1.1.0", "v1.1.0")
+	third := repo.commit("Fix the handler", "handler.go", "package main\n\nfunc handle() error { return nil }\n")
+	fourth := repo.commit("Add the logo", "logo.png", "\x89PNG\x00\x01")
+	fifth := repo.commit("Add the main function", "main.go", "package main\n\nfunc main() {}\n")
+
+	f := newFixture(t, app.Options{Git: gitrepo.NewReader(), GitRepositories: map[string]string{"api": repo.dir}})
+	ctx := context.Background()
+
+	// The version 2.0.0 is not tagged, but its deployment recorded the commit.
+	if _, err := f.app.RegisterDeployment(ctx, app.RegisterDeploymentParams{
+		InstanceId: f.instance,
+		Version:    "2.0.0",
+		DeployedAt: time.Now(),
+		Metadata:   app.DeploymentMetadata{GitCommit: fourth},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("versions", func(t *testing.T) {
+		tests := []struct {
+			version string
+			want    string
+		}{
+			{"1.0.0", initial},
+			// The tag has a v prefix and the version doesn't.
+			{"1.1.0", second},
+			{"v1.1.0", second},
+			// The tag has no v prefix and the version does.
+			{"v1.0.0", initial},
+			{"2.0.0", fourth},
+			{third, third},
+			{third[:10], third},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.version, func(t *testing.T) {
+				changelog, err := f.app.Changelog(ctx, app.ChangelogParams{ApplicationId: f.appl.Id, From: tt.version, To: fifth})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if changelog.FromCommit != tt.want || changelog.ToCommit != fifth {
+					t.Fatalf("expected %s to resolve to %s, got %s", tt.version, tt.want, changelog.FromCommit)
+				}
+			})
+		}
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		changelog, err := f.app.Changelog(ctx, app.ChangelogParams{ApplicationId: f.appl.Id, From: "1.1.0", To: "2.0.0"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var subjects []string
+		for _, c := range changelog.Commits {
+			subjects = append(subjects, c.Subject)
+		}
+		if strings.Join(subjects, "; ") != "Add the logo; Fix the handler" || changelog.Truncated {
+			t.Fatalf("expected the commits after 1.1.0, newest first, got %q (truncated=%t)", subjects, changelog.Truncated)
+		}
+		if c := changelog.Commits[0]; c.Hash != fourth || c.Author != "Ada Lovelace" || c.AuthorEmail != "ada@example.com" || c.Date.IsZero() {
+			t.Fatalf("expected the commit %s by Ada Lovelace, got %+v", fourth, c)
+		}
+
+		want := []app.FileChange{
+			{Path: "handler.go", Additions: 1, Deletions: 1},
+			{Path: "logo.png", Binary: true},
+		}
+		if len(changelog.Files) != len(want) || changelog.Files[0] != want[0] || changelog.Files[1] != want[1] {
+			t.Fatalf("expected the changed files %+v, got %+v", want, changelog.Files)
+		}
+		if changelog.Additions != 1 || changelog.Deletions != 1 {
+			t.Fatalf("expected the binary file not to be counted in the totals, got +%d -%d", changelog.Additions, changelog.Deletions)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		changelog, err := f.app.Changelog(ctx, app.ChangelogParams{ApplicationId: f.appl.Id, From: "1.0.0", To: fifth, MaxCommits: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changelog.Commits) != 2 || changelog.Commits[0].Hash != fifth || !changelog.Truncated {
+			t.Fatalf("expected the 2 newest of 4 commits, got %+v (truncated=%t)", changelog.Commits, changelog.Truncated)
+		}
+	})
+
+	t.Run("same version", func(t *testing.T) {
+		changelog, err := f.app.Changelog(ctx, app.ChangelogParams{ApplicationId: f.appl.Id, From: "1.1.0", To: "v1.1.0"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changelog.Commits) != 0 || len(changelog.Files) != 0 || changelog.Commits == nil || changelog.Files == nil {
+			t.Fatalf("expected empty lists of commits and files, got %+v", changelog)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			params app.ChangelogParams
+			target error
+		}{
+			{"no versions", app.ChangelogParams{ApplicationId: f.appl.Id}, nil},
+			{"negative max commits", app.ChangelogParams{ApplicationId: f.appl.Id, From: "1.0.0", To: "1.1.0", MaxCommits: -1}, nil},
+			{"option", app.ChangelogParams{ApplicationId: f.appl.Id, From: "--output=/tmp/x", To: "1.1.0"}, nil},
+			{"unknown version", app.ChangelogParams{ApplicationId: f.appl.Id, From: "3.0.0", To: "1.1.0"}, app.ErrNotFound},
+			{"unknown commit", app.ChangelogParams{ApplicationId: f.appl.Id, From: "0123456789abcdef", To: "1.1.0"}, app.ErrNotFound},
+			{"unknown application", app.ChangelogParams{ApplicationId: 9999, From: "1.0.0", To: "1.1.0"}, app.ErrNotFound},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := f.app.Changelog(ctx, tt.params)
+				if err == nil || (tt.target != nil && !errors.Is(err, tt.target)) {
+					t.Fatalf("expected an error matching %v, got %v", tt.target, err)
+				}
+			})
+		}
+	})
+
+	t.Run("no repository", func(t *testing.T) {
+		other := newFixture(t, app.Options{Git: gitrepo.NewReader()})
+		if _, err := other.app.Changelog(ctx, app.ChangelogParams{ApplicationId: other.appl.Id, From: "1.0.0", To: "1.1.0"}); !errors.Is(err, app.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound without a repository, got %v", err)
+		}
+	})
+}
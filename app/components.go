@@ -0,0 +1,36 @@
+package app
+
+import (
+	"regexp"
+)
+
+// ComponentRule routes the events of a source to a component of an instance.
+type ComponentRule struct {
+	// Match is matched against the deployment name of the events.
+	Match *regexp.Regexp
+	// Instance is the name of the instance, expanded with the submatches of Match like regexp.Regexp.Expand.
+	// If it is empty the deployment name is used as the instance name.
+	Instance string
+	// Component is expanded like Instance, if it is empty the primary component of the instance is used.
+	Component string
+}
+
+// routeEvent returns the instance name and component of an event with the given deployment name,
+// using the first rule that matches. Without a matching rule the event is routed by its own name and component.
+func (a *App) routeEvent(deploymentName, component string) (string, string) {
+	for _, rule := range a.componentRules {
+		submatches := rule.Match.FindStringSubmatchIndex(deploymentName)
+		if submatches == nil {
+			continue
+		}
+
+		instance := deploymentName
+		if rule.Instance != "" {
+			instance = string(rule.Match.ExpandString(nil, rule.Instance, deploymentName, submatches))
+		}
+
+		return instance, string(rule.Match.ExpandString(nil, rule.Component, deploymentName, submatches))
+	}
+
+	return deploymentName, component
+}
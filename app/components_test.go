@@ -0,0 +1,43 @@
+package app
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestRouteEvent(t *testing.T) {
+	a := New(nil, Options{
+		ComponentRules: []ComponentRule{
+			{Match: regexp.MustCompile(`^(.+)-migrate$`), Instance: "$1", Component: "migrator"},
+			{Match: regexp.MustCompile(`^(?P<app>\w+)-(?P<env>\w+)-sidecar$`), Instance: "${app}-${env}", Component: "sidecar"},
+			{Match: regexp.MustCompile(`^worker-`), Component: "worker"},
+			{Match: regexp.MustCompile(`^legacy-`)},
+		},
+	})
+
+	tests := []struct {
+		name           string
+		deploymentName string
+		component      string
+		wantInstance   string
+		wantComponent  string
+	}{
+		{"no matching rule", "api-prod", "", "api-prod", ""},
+		{"no matching rule keeps the component of the event", "api-prod", "web", "api-prod", "web"},
+		{"numbered submatch", "api-prod-migrate", "", "api-prod", "migrator"},
+		{"named submatches", "api-prod-sidecar", "", "api-prod", "sidecar"},
+		{"first matching rule wins", "api-prod-sidecar-migrate", "", "api-prod-sidecar", "migrator"},
+		{"rule without an instance keeps the name", "worker-prod", "", "worker-prod", "worker"},
+		{"rule overrides the component of the event", "worker-prod", "main", "worker-prod", "worker"},
+		{"rule without a component routes to the primary component", "legacy-prod", "web", "legacy-prod", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance, component := a.routeEvent(tt.deploymentName, tt.component)
+			if instance != tt.wantInstance || component != tt.wantComponent {
+				t.Fatalf("expected %q and %q, got %q and %q", tt.wantInstance, tt.wantComponent, instance, component)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package app
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schedule is a parsed cron expression with the fields minute, hour, day of month, month and day of week.
+type schedule struct {
+	minutes, hours, days, months, weekdays uint64
+	// anyDay and anyWeekday are set if the field is unrestricted, a time then has to match both day fields.
+	anyDay, anyWeekday bool
+}
+
+var (
+	monthNames   = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// parseSchedule parses a standard cron expression like "0 18 * * FRI" or "*/15 9-17 * * MON-FRI".
+//
+// Fields are lists of values, ranges and steps like "1,15", "1-5" and "*/2". Months and days of the week may be
+// given by their three letter names, Sunday is 0 or 7 and ends a range as 7, like MON-SUN. Like cron, if both the
+// day of month and day of week are restricted, a time matches if either does. A field starting with *, like */2,
+// or matching every value, like 1-31, is unrestricted.
+func parseSchedule(expr string) (schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return schedule{}, invalid("invalid schedule %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	var (
+		s   schedule
+		err error
+	)
+	if s.minutes, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return schedule{}, invalid("invalid minute in schedule %q: %w", expr, err)
+	}
+	if s.hours, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return schedule{}, invalid("invalid hour in schedule %q: %w", expr, err)
+	}
+	if s.days, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return schedule{}, invalid("invalid day of month in schedule %q: %w", expr, err)
+	}
+	if s.months, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return schedule{}, invalid("invalid month in schedule %q: %w", expr, err)
+	}
+	if s.weekdays, err = parseField(fields[4], 0, 7, weekdayNames); err != nil {
+		return schedule{}, invalid("invalid day of week in schedule %q: %w", expr, err)
+	}
+	// Sunday is both 0 and 7.
+	if s.weekdays&(1<<7) != 0 {
+		s.weekdays |= 1
+	}
+
+	s.anyDay = strings.HasPrefix(fields[2], "*") || s.days == allValues(1, 31)
+	s.anyWeekday = strings.HasPrefix(fields[4], "*") || s.weekdays&allValues(0, 6) == allValues(0, 6)
+	return s, nil
+}
+
+// allValues returns the bit set of the values from min to max.
+func allValues(min, max int) uint64 {
+	return (1<<(max+1) - 1) &^ (1<<min - 1)
+}
+
+// parseField parses a field of a cron expression into a bit set of the values it matches.
+// The names, if any, are the names of the values from min.
+func parseField(field string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			first, last, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+			if lo, err = parseValue(first, min, max, names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = parseValue(last, min, max, names); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// A step from a single value runs to the end of the range, like 5/15.
+				hi = max
+			}
+			if hi < lo && hi == 0 && max == 7 {
+				// Sunday ends a range of days of the week as 7, like MON-SUN.
+				hi = 7
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return min + i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// matches reports whether the minute of t matches the schedule, in the location of t.
+func (s schedule) matches(t time.Time) bool {
+	return s.minutes&(1<<t.Minute()) != 0 && s.hours&(1<<t.Hour()) != 0 && s.dayMatches(t)
+}
+
+// dayMatches reports whether any minute of the day of t can match the schedule.
+func (s schedule) dayMatches(t time.Time) bool {
+	if s.months&(1<<int(t.Month())) == 0 {
+		return false
+	}
+
+	day := s.days&(1<<t.Day()) != 0
+	weekday := s.weekdays&(1<<int(t.Weekday())) != 0
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// lastStart returns the latest time within the duration before t, or t itself, at which the schedule matches,
+// false if there is none. The schedule is evaluated in the location of t.
+//
+// The days and hours that can't match are skipped, then the latest matching minute of the hour is read from the
+// minutes of the schedule.
+func (s schedule) lastStart(t time.Time, within time.Duration) (time.Time, bool) {
+	t = t.Truncate(time.Minute)
+	earliest := t.Add(-within)
+
+	for start := t; !start.Before(earliest); {
+		year, month, day := start.Date()
+		hour, minute := start.Hour(), start.Minute()
+
+		// prev is the last minute before the day or the hour of start.
+		var prev time.Time
+		if !s.dayMatches(start) {
+			prev = time.Date(year, month, day, 0, 0, 0, 0, start.Location()).Add(-time.Minute)
+		} else {
+			if s.hours&(1<<hour) != 0 {
+				if minutes := s.minutes & (1<<(minute+1) - 1); minutes != 0 {
+					start = start.Add(-time.Duration(minute-(bits.Len64(minutes)-1)) * time.Minute)
+					return start, !start.Before(earliest)
+				}
+			}
+			prev = time.Date(year, month, day, hour, 0, 0, 0, start.Location()).Add(-time.Minute)
+		}
+
+		// The start of a day or an hour may be ambiguous when the clocks are turned back, always go back in time.
+		if !prev.Before(start) {
+			prev = start.Add(-time.Minute)
+		}
+		start = prev
+	}
+	return time.Time{}, false
+}
+
+// next returns the first time after t at which the schedule matches, searching at most a year ahead.
+func (s schedule) next(t time.Time) (time.Time, bool) {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(1, 0, 0)
+	for ; t.Before(end); t = t.Add(time.Minute) {
+		if !s.hourMatches(t) {
+			// Skip to the last minute of the hour, in the time zone of t.
+			t = t.Add(time.Duration(59-t.Minute()) * time.Minute)
+			continue
+		}
+		if s.matches(t) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// hourMatches reports whether any minute of the hour of t can match the schedule.
+func (s schedule) hourMatches(t time.Time) bool {
+	return s.hours&(1<<t.Hour()) != 0 && s.months&(1<<int(t.Month())) != 0
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"0 18 * *",
+		"0 18 * * FRI *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"* * * FOO *",
+		"* * * * FRI-MON",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"1-2-3 * * * *",
+		"1,,2 * * * *",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := parseSchedule(expr); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestScheduleMatches(t *testing.T) {
+	// June 1st 2025 is a Sunday.
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expr string
+		t    time.Time
+		want bool
+	}{
+		{"0 18 * * FRI", at(6, 6, 18, 0), true},
+		{"0 18 * * FRI", at(6, 6, 18, 1), false},
+		{"0 18 * * FRI", at(6, 5, 18, 0), false},
+		{"0 18 * * fri", at(6, 6, 18, 0), true},
+		{"*/15 9-17 * * MON-FRI", at(6, 2, 9, 45), true},
+		{"*/15 9-17 * * MON-FRI", at(6, 2, 9, 50), false},
+		{"*/15 9-17 * * MON-FRI", at(6, 2, 18, 0), false},
+		{"*/15 9-17 * * MON-FRI", at(6, 7, 10, 0), false},
+		{"5/20 * * * *", at(6, 1, 0, 45), true},
+		{"5/20 * * * *", at(6, 1, 0, 0), false},
+		{"0 12 * JAN,jul *", at(7, 1, 12, 0), true},
+		{"0 12 * JAN,jul *", at(6, 1, 12, 0), false},
+		{"0 0 1,15 * *", at(6, 15, 0, 0), true},
+		{"0 0 1,15 * *", at(6, 16, 0, 0), false},
+
+		// Sunday is 0 or 7, and ends a range as 7.
+		{"0 0 * * 0", at(6, 1, 0, 0), true},
+		{"0 0 * * 7", at(6, 1, 0, 0), true},
+		{"0 0 * * MON-SUN", at(6, 1, 0, 0), true},
+		{"0 0 * * MON-SUN", at(6, 4, 0, 0), true},
+		{"0 0 * * FRI-SUN", at(6, 7, 0, 0), true},
+		{"0 0 * * FRI-SUN", at(6, 2, 0, 0), false},
+		{"0 0 * * SUN-SUN", at(6, 1, 0, 0), true},
+		{"0 0 * * SUN-SUN", at(6, 2, 0, 0), false},
+		{"0 0 * * 5-7", at(6, 1, 0, 0), true},
+
+		// Either day field matches if both are restricted.
+		{"0 0 13 * FRI", at(6, 13, 0, 0), true},
+		{"0 0 13 * FRI", at(6, 6, 0, 0), true},
+		{"0 0 13 * FRI", at(7, 13, 0, 0), true},
+		{"0 0 13 * FRI", at(6, 7, 0, 0), false},
+
+		// Both day fields have to match if either is unrestricted.
+		{"0 0 */2 * FRI", at(6, 13, 0, 0), true},
+		{"0 0 */2 * FRI", at(6, 6, 0, 0), false},
+		{"0 0 */2 * FRI", at(6, 15, 0, 0), false},
+		{"0 0 1-31 * MON", at(6, 2, 0, 0), true},
+		{"0 0 1-31 * MON", at(6, 3, 0, 0), false},
+		{"0 0 1 * MON-SUN", at(6, 1, 0, 0), true},
+		{"0 0 1 * MON-SUN", at(6, 2, 0, 0), false},
+		{"0 0 1 * 0-7", at(6, 2, 0, 0), false},
+		{"0 0 1 * */1", at(6, 2, 0, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr+" at "+tt.t.Format(time.DateTime), func(t *testing.T) {
+			s, err := parseSchedule(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.matches(tt.t); got != tt.want {
+				t.Fatalf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScheduleLastStart(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	local := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, stockholm)
+	}
+
+	tests := []struct {
+		name   string
+		expr   string
+		t      time.Time
+		within time.Duration
+		want   time.Time
+		wantOk bool
+	}{
+		{"previous day", "0 18 * * FRI", utc(6, 7, 10, 0), 24 * time.Hour, utc(6, 6, 18, 0), true},
+		{"before the duration", "0 18 * * FRI", utc(6, 7, 10, 0), 12 * time.Hour, time.Time{}, false},
+		{"at the start of the duration", "0 18 * * FRI", utc(6, 7, 10, 0), 16 * time.Hour, utc(6, 6, 18, 0), true},
+		{"the minute of t", "0 18 * * FRI", utc(6, 6, 18, 0).Add(30 * time.Second), time.Minute, utc(6, 6, 18, 0), true},
+		{"earlier in the hour", "*/15 9-17 * * MON-FRI", utc(6, 2, 12, 7), time.Hour, utc(6, 2, 12, 0), true},
+		{"previous hour", "50 * * * *", utc(6, 2, 12, 7), time.Hour, utc(6, 2, 11, 50), true},
+		{"over a weekend", "*/15 9-17 * * MON-FRI", utc(6, 2, 8, 30), 72 * time.Hour, utc(5, 30, 17, 45), true},
+		{"previous month", "0 0 1 * *", utc(6, 20, 0, 0), 31 * 24 * time.Hour, utc(6, 1, 0, 0), true},
+		{"never within the duration", "30 23 31 12 *", utc(6, 1, 0, 0), maxFreezeDuration, time.Time{}, false},
+		{"time zone", "0 18 * * FRI", local(6, 6, 19, 30), 2 * time.Hour, utc(6, 6, 16, 0), true},
+		{"skipped by daylight saving time", "30 2 * * *", local(3, 30, 10, 0), 48 * time.Hour, local(3, 29, 2, 30), true},
+		{"repeated by daylight saving time", "30 2 * * *", local(10, 26, 4, 0), 6 * time.Hour, utc(10, 26, 1, 30), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseSchedule(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := s.lastStart(tt.t, tt.within)
+			if ok != tt.wantOk || !got.Equal(tt.want) {
+				t.Fatalf("expected %s and %t, got %s and %t", tt.want, tt.wantOk, got, ok)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	tests := []struct {
+		expr   string
+		t      time.Time
+		want   time.Time
+		wantOk bool
+	}{
+		{"0 18 * * FRI", time.Date(2025, 6, 7, 10, 0, 0, 0, time.UTC), time.Date(2025, 6, 13, 18, 0, 0, 0, time.UTC), true},
+		{"0 18 * * FRI", time.Date(2025, 6, 6, 18, 0, 0, 0, time.UTC), time.Date(2025, 6, 13, 18, 0, 0, 0, time.UTC), true},
+		{"0 0 * * MON-SUN", time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 29 2 *", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := parseSchedule(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := s.next(tt.t)
+			if ok != tt.wantOk || !got.Equal(tt.want) {
+				t.Fatalf("expected %s and %t, got %s and %t", tt.want, tt.wantOk, got, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalid is matched by the errors returned for invalid arguments, like an empty name, a malformed URL or template,
+// or a cron schedule or version range that can't be parsed.
+var ErrInvalid = errors.New("invalid argument")
+
+// invalidError is an invalid argument error, it matches ErrInvalid without adding it to the message.
+type invalidError struct {
+	err error
+}
+
+func (e invalidError) Error() string {
+	return e.err.Error()
+}
+
+func (e invalidError) Unwrap() []error {
+	return []error{ErrInvalid, e.err}
+}
+
+// invalid formats an error matching ErrInvalid, like fmt.Errorf.
+func invalid(format string, args ...any) error {
+	return invalidError{fmt.Errorf(format, args...)}
+}
@@ -0,0 +1,213 @@
+package app_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"overseer/app"
+	"overseer/notify"
+)
+
+// waitForDeliveries waits until n webhook deliveries are queued.
+func waitForDeliveries(t *testing.T, a *app.App, n int) []app.WebhookDelivery {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		deliveries, err := a.ListWebhookDeliveries(context.Background(), app.ListWebhookDeliveriesParams{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(deliveries) >= n {
+			return deliveries
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d webhook deliveries, got %d", n, len(deliveries))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPublishDeployments(t *testing.T) {
+	f := newFixture(t, app.Options{})
+	ctx := context.Background()
+
+	var received []app.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write(body)
+		if r.Header.Get(app.HeaderWebhookSignature) != fmt.Sprintf("sha256=%x", mac.Sum(nil)) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		var e app.Event
+		if err := json.Unmarshal(body, &e); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		received = append(received, e)
+	}))
+	defer server.Close()
+
+	if _, err := f.app.CreateWebhook(ctx, app.Webhook{Name: "smoke tests", URL: server.URL, Secret: "s3cret", Events: []string{app.EventDeployment}}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	for _, params := range []app.RegisterDeploymentParams{
+		{InstanceId: f.instance, Version: "1.0.0", DeployedAt: now.Add(-time.Hour)},
+		{InstanceId: f.instance, Version: "1.1.0", DeployedAt: now},
+		// A late deployment does not change what is running and is not published.
+		{InstanceId: f.instance, Version: "0.9.0", DeployedAt: now.Add(-2 * time.Hour)},
+	} {
+		if _, err := f.app.RegisterDeployment(ctx, params); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The events are published after the deployments are registered.
+	waitForDeliveries(t, f.app, 2)
+	if err := f.app.DeliverWebhooks(ctx, notify.NewClient(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	deliveries, err := f.app.ListWebhookDeliveries(ctx, app.ListWebhookDeliveriesParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range deliveries {
+		if d.Status != app.DeliverySucceeded {
+			t.Fatalf("expected the deliveries to succeed, got %+v", d)
+		}
+	}
+
+	// The deliveries are not attempted in order, the events are compared in the order they happened.
+	slices.SortFunc(received, func(a, b app.Event) int { return a.OccurredAt.Compare(b.OccurredAt) })
+	var got []string
+	for _, e := range received {
+		if e.Environment == nil || e.Application == nil || e.Instance == nil || e.Deployment == nil {
+			t.Fatalf("expected the event to be described, got %+v", e)
+		}
+		got = append(got, fmt.Sprintf("%s %s/%s/%s %s->%s", e.Type, e.Environment.Name, e.Application.Name, e.Instance.Name, e.PreviousVersion, e.Deployment.Version))
+	}
+	want := []string{"deployment prod/api/api-prod ->1.0.0", "deployment prod/api/api-prod 1.0.0->1.1.0"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected the events %q, got %q", want, got)
+	}
+}
+
+// addDeliveries queues n deliveries that are due to the webhook.
+func addDeliveries(t *testing.T, f fixture, webhookId int32, n int) {
+	t.Helper()
+
+	now := time.Now().UTC()
+	var deliveries []app.WebhookDelivery
+	for i := range n {
+		deliveries = append(deliveries, app.WebhookDelivery{
+			Id:            fmt.Sprintf("%d-%d", webhookId, i),
+			WebhookId:     webhookId,
+			EventId:       fmt.Sprintf("event-%d", i),
+			Event:         app.EventDeployment,
+			Payload:       json.RawMessage(`{}`),
+			Status:        app.DeliveryPending,
+			CreatedAt:     now,
+			NextAttemptAt: now.Add(-time.Duration(n-i) * time.Second),
+		})
+	}
+	if err := f.store.AddWebhookDeliveries(context.Background(), deliveries); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeliverWebhooksConcurrently(t *testing.T) {
+	f := newFixture(t, app.Options{})
+	ctx := context.Background()
+
+	// The slow webhook answers once the fast one has received all its deliveries.
+	fastDone := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-fastDone:
+		case <-time.After(5 * time.Second):
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}
+	}))
+	defer slow.Close()
+
+	var fastRequests atomic.Int32
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fastRequests.Add(1) == 3 {
+			close(fastDone)
+		}
+	}))
+	defer fast.Close()
+
+	for name, url := range map[string]string{"slow": slow.URL, "fast": fast.URL} {
+		w, err := f.app.CreateWebhook(ctx, app.Webhook{Name: name, URL: url})
+		if err != nil {
+			t.Fatal(err)
+		}
+		addDeliveries(t, f, w.Id, 3)
+	}
+
+	if err := f.app.DeliverWebhooks(ctx, notify.NewClient(10*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	deliveries, err := f.app.ListWebhookDeliveries(ctx, app.ListWebhookDeliveriesParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range deliveries {
+		if d.Status != app.DeliverySucceeded {
+			t.Fatalf("expected the slow webhook not to hold up the fast one, got %+v", d)
+		}
+	}
+}
+
+func TestDeliverWebhooksUnreachable(t *testing.T) {
+	f := newFixture(t, app.Options{})
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	w, err := f.app.CreateWebhook(ctx, app.Webhook{Name: "gone", URL: url})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addDeliveries(t, f, w.Id, 3)
+
+	if err := f.app.DeliverWebhooks(ctx, notify.NewClient(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the oldest delivery is attempted, the others wait for the next round.
+	deliveries, err := f.app.ListWebhookDeliveries(ctx, app.ListWebhookDeliveriesParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	attempts := map[string]int32{}
+	for _, d := range deliveries {
+		attempts[d.Id] = d.Attempts
+	}
+	want := map[string]int32{fmt.Sprintf("%d-0", w.Id): 1, fmt.Sprintf("%d-1", w.Id): 0, fmt.Sprintf("%d-2", w.Id): 0}
+	if !maps.Equal(attempts, want) {
+		t.Fatalf("expected the attempts %v, got %v", want, attempts)
+	}
+}
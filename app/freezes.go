@@ -0,0 +1,256 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// ErrFrozen is returned when a deployment is registered during a freeze window that rejects deployments.
+var ErrFrozen = errors.New("deployments are frozen")
+
+// maxFreezeDuration is how long a recurring freeze window can last.
+const maxFreezeDuration = 31 * 24 * time.Hour
+
+// FreezeWindow is a period in which deployments are frozen, like around holidays or a big launch.
+//
+// A window is either one-off, from StartsAt to EndsAt, or recurring, starting every time the Schedule matches
+// in the TimeZone and lasting for DurationSeconds.
+type FreezeWindow struct {
+	Id   int32  `json:"id"`
+	Name string `json:"name"`
+	// Reason is shown to whoever deploys during the freeze.
+	Reason string `json:"reason,omitempty"`
+	// EnvironmentId limits the freeze to the environment, it applies to all environments if it is zero.
+	EnvironmentId int32 `json:"environment_id,omitempty"`
+	// ApplicationId limits the freeze to the application, it applies to all applications if it is zero.
+	ApplicationId int32     `json:"application_id,omitempty"`
+	StartsAt      time.Time `json:"starts_at,omitzero"`
+	EndsAt        time.Time `json:"ends_at,omitzero"`
+	// Schedule is a cron expression like "0 18 * * FRI" for the starts of a recurring window.
+	Schedule        string `json:"schedule,omitempty"`
+	DurationSeconds int64  `json:"duration_seconds,omitempty"`
+	// TimeZone is the IANA time zone the schedule is evaluated in, like Europe/Stockholm. It defaults to UTC.
+	TimeZone string `json:"time_zone,omitempty"`
+	// Reject rejects deployments registered through the API during the freeze, unless they override it.
+	// Deployments are otherwise only flagged, as are the deployments reported by sources, which have already happened.
+	Reject bool `json:"reject"`
+
+	// Active is set if the window is active now, it is then active until ActiveUntil.
+	Active      bool      `json:"active"`
+	ActiveUntil time.Time `json:"active_until,omitzero"`
+	// NextStart is when the window next starts, zero if it never does.
+	NextStart time.Time `json:"next_start,omitzero"`
+}
+
+func (w FreezeWindow) recurring() bool {
+	return w.Schedule != ""
+}
+
+func (w FreezeWindow) duration() time.Duration {
+	return time.Duration(w.DurationSeconds) * time.Second
+}
+
+// validate normalizes the window and checks that it is either a valid one-off or recurring window.
+func (w *FreezeWindow) validate() error {
+	w.Name = strings.TrimSpace(w.Name)
+	if w.Name == "" {
+		return invalid("name is required")
+	}
+
+	w.Schedule = strings.TrimSpace(w.Schedule)
+	if w.TimeZone == "" {
+		w.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(w.TimeZone); err != nil {
+		return invalid("invalid time zone %q", w.TimeZone)
+	}
+
+	if !w.recurring() {
+		if w.StartsAt.IsZero() || w.EndsAt.IsZero() {
+			return invalid("a freeze window needs a start and an end, or a schedule")
+		}
+		if !w.EndsAt.After(w.StartsAt) {
+			return invalid("a freeze window must end after it starts")
+		}
+		if w.DurationSeconds != 0 {
+			return invalid("only a recurring freeze window has a duration")
+		}
+		return nil
+	}
+
+	if !w.StartsAt.IsZero() || !w.EndsAt.IsZero() {
+		return invalid("a recurring freeze window has no start and end")
+	}
+	if _, err := parseSchedule(w.Schedule); err != nil {
+		return err
+	}
+	if w.DurationSeconds <= 0 {
+		return invalid("a recurring freeze window needs a duration")
+	}
+	// The start of an active window is searched for over its duration, a long window is better made one-off.
+	if w.duration() > maxFreezeDuration {
+		return invalid("a recurring freeze window can last at most %s", maxFreezeDuration)
+	}
+	return nil
+}
+
+// activeAt returns when the window is active until if it is active at t.
+func (w FreezeWindow) activeAt(t time.Time) (time.Time, bool) {
+	if !w.recurring() {
+		if !t.Before(w.StartsAt) && t.Before(w.EndsAt) {
+			return w.EndsAt, true
+		}
+		return time.Time{}, false
+	}
+
+	s, loc, err := w.parse()
+	if err != nil {
+		return time.Time{}, false
+	}
+	start, ok := s.lastStart(t.In(loc), w.duration())
+	if !ok || !start.Add(w.duration()).After(t) {
+		return time.Time{}, false
+	}
+	return start.Add(w.duration()).UTC(), true
+}
+
+// withStatus returns the window with whether it is active at t and when it next starts after t.
+func (w FreezeWindow) withStatus(t time.Time) FreezeWindow {
+	w.ActiveUntil, w.Active = w.activeAt(t)
+	if next, ok := w.nextStart(t); ok {
+		w.NextStart = next
+	}
+	return w
+}
+
+// nextStart returns when the window next starts after t.
+func (w FreezeWindow) nextStart(t time.Time) (time.Time, bool) {
+	if !w.recurring() {
+		return w.StartsAt, w.StartsAt.After(t)
+	}
+
+	s, loc, err := w.parse()
+	if err != nil {
+		return time.Time{}, false
+	}
+	next, ok := s.next(t.In(loc))
+	return next.UTC(), ok
+}
+
+func (w FreezeWindow) parse() (schedule, *time.Location, error) {
+	s, err := parseSchedule(w.Schedule)
+	if err != nil {
+		return schedule{}, nil, err
+	}
+	loc, err := time.LoadLocation(w.TimeZone)
+	if err != nil {
+		return schedule{}, nil, err
+	}
+	return s, loc, nil
+}
+
+// ListFreezeWindows lists the freeze windows and whether they are active now.
+func (a *App) ListFreezeWindows(ctx context.Context) ([]FreezeWindow, error) {
+	windows, err := a.store.ListFreezeWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	for i, w := range windows {
+		windows[i] = w.withStatus(now)
+	}
+	return windows, nil
+}
+
+func (a *App) CreateFreezeWindow(ctx context.Context, window FreezeWindow) (FreezeWindow, error) {
+	if err := window.validate(); err != nil {
+		return FreezeWindow{}, err
+	}
+
+	id, err := a.store.CreateFreezeWindow(ctx, window)
+	if err != nil {
+		return FreezeWindow{}, err
+	}
+	window.Id = id
+
+	return window.withStatus(time.Now().UTC()), nil
+}
+
+// UpdateFreezeWindow replaces the freeze window with the id of the given one.
+func (a *App) UpdateFreezeWindow(ctx context.Context, window FreezeWindow) (FreezeWindow, error) {
+	if window.Id == 0 {
+		return FreezeWindow{}, invalid("id is required")
+	}
+	if err := window.validate(); err != nil {
+		return FreezeWindow{}, err
+	}
+
+	if err := a.store.UpdateFreezeWindow(ctx, window); err != nil {
+		return FreezeWindow{}, err
+	}
+
+	return window.withStatus(time.Now().UTC()), nil
+}
+
+func (a *App) DeleteFreezeWindow(ctx context.Context, id int32) error {
+	return a.store.DeleteFreezeWindow(ctx, id)
+}
+
+// activeFreeze returns the freeze window the instance is in at t, nil if there is none.
+// A window that rejects deployments is returned before one that does not.
+func (a *App) activeFreeze(ctx context.Context, instance Instance, t time.Time) (*FreezeWindow, error) {
+	windows, err := a.store.ListFreezeWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var active *FreezeWindow
+	for _, w := range windows {
+		if (w.EnvironmentId != 0 && w.EnvironmentId != instance.EnvironmentId) || (w.ApplicationId != 0 && w.ApplicationId != instance.ApplicationId) {
+			continue
+		}
+		if _, ok := w.activeAt(t); !ok {
+			continue
+		}
+		if active == nil || (w.Reject && !active.Reject) {
+			active = &w
+		}
+	}
+	return active, nil
+}
+
+// checkFreeze returns the name of the freeze window the deployment is made in, empty if there is none,
+// or ErrFrozen if the window rejects it. A deployment registered through the API is checked at the time it is
+// received, as its reported deployment time could place it outside of the window. A deployment reported by
+// a source has already happened, it is flagged with the window active at its deployment time.
+func (a *App) checkFreeze(ctx context.Context, params RegisterDeploymentParams, receivedAt time.Time) (string, error) {
+	instance, err := a.store.GetInstance(ctx, params.InstanceId)
+	if err != nil {
+		return "", err
+	}
+
+	at := receivedAt
+	if params.Source != "" {
+		at = params.DeployedAt
+	}
+
+	freeze, err := a.activeFreeze(ctx, instance, at)
+	if err != nil || freeze == nil {
+		return "", err
+	}
+
+	if freeze.Reject && params.Source == "" && !params.OverrideFreeze {
+		if freeze.Reason != "" {
+			return "", fmt.Errorf("%w by %s: %s", ErrFrozen, freeze.Name, freeze.Reason)
+		}
+		return "", fmt.Errorf("%w by %s", ErrFrozen, freeze.Name)
+	}
+
+	slog.Warn("deployment during a freeze", "instance", params.InstanceId, "version", params.Version, "freeze", freeze.Name)
+	return freeze.Name, nil
+}
@@ -0,0 +1,81 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"overseer/app"
+)
+
+func TestRegisterDeploymentFreeze(t *testing.T) {
+	f := newFixture(t, app.Options{})
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for _, w := range []app.FreezeWindow{
+		{Name: "launch", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Reject: true},
+		{Name: "holidays", StartsAt: now.Add(-72 * time.Hour), EndsAt: now.Add(-48 * time.Hour), Reject: true},
+	} {
+		if _, err := f.app.CreateFreezeWindow(ctx, w); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		params     app.RegisterDeploymentParams
+		wantFrozen bool
+		wantFreeze string
+	}{
+		{
+			name:       "registered during a freeze",
+			params:     app.RegisterDeploymentParams{Version: "1.0.0"},
+			wantFrozen: true,
+		},
+		{
+			name:       "registered during a freeze with an earlier deployment time",
+			params:     app.RegisterDeploymentParams{Version: "1.0.0", DeployedAt: now.Add(-96 * time.Hour)},
+			wantFrozen: true,
+		},
+		{
+			name:       "registered during a freeze overriding it",
+			params:     app.RegisterDeploymentParams{Version: "1.0.0", DeployedAt: now.Add(-60 * time.Hour), OverrideFreeze: true},
+			wantFreeze: "launch",
+		},
+		{
+			name:       "reported by a source during a freeze",
+			params:     app.RegisterDeploymentParams{Version: "1.0.0", DeployedAt: now, Source: "nomad"},
+			wantFreeze: "launch",
+		},
+		{
+			name:       "reported by a source as made during a past freeze",
+			params:     app.RegisterDeploymentParams{Version: "1.0.0", DeployedAt: now.Add(-60 * time.Hour), Source: "nomad"},
+			wantFreeze: "holidays",
+		},
+		{
+			name:   "reported by a source as made outside of a freeze",
+			params: app.RegisterDeploymentParams{Version: "1.0.0", DeployedAt: now.Add(-96 * time.Hour), Source: "nomad"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.InstanceId = f.instance
+			d, err := f.app.RegisterDeployment(ctx, tt.params)
+			if tt.wantFrozen {
+				if !errors.Is(err, app.ErrFrozen) {
+					t.Fatalf("expected ErrFrozen, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Freeze != tt.wantFreeze {
+				t.Fatalf("expected the freeze %q, got %q", tt.wantFreeze, d.Freeze)
+			}
+		})
+	}
+}
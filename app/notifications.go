@@ -0,0 +1,513 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// The kinds of notification channels, which decide the format of the messages.
+const (
+	// ChannelSlack posts to a Slack incoming webhook, or to one of the chats with Slack compatible webhooks like Mattermost.
+	ChannelSlack = "slack"
+	// ChannelTeams posts an adaptive card to a Microsoft Teams incoming webhook or workflow.
+	ChannelTeams = "teams"
+	// ChannelGeneric posts the message together with the events it is about as JSON.
+	ChannelGeneric = "generic"
+)
+
+const (
+	// maxNotificationBatch is the longest a channel can batch the events for.
+	maxNotificationBatch = time.Hour
+	// maxNotificationLines is how many events are listed in a message, the rest are only counted.
+	maxNotificationLines = 30
+	// maxNotificationAttempts is how many times a message is attempted before it is dropped.
+	maxNotificationAttempts = 3
+	// notificationRetryDelay is the delay before a failed message is attempted again.
+	notificationRetryDelay = 10 * time.Second
+	// notificationTick is how often the batches are checked for messages that are due.
+	notificationTick = time.Second
+)
+
+// defaultNotificationTemplate describes the events of all types, it is used for the channels without a template
+// and for the events a template fails on.
+const defaultNotificationTemplate = `
+{{- if eq .Type "deployment" -}}
+	{{- if .Deployment.Undeployed -}}
+		{{.Application.Name}} was undeployed from {{.Instance.Name}} in {{.Environment.Name}}
+	{{- else -}}
+		{{.Application.Name}} {{.Deployment.Version}} was deployed to {{.Instance.Name}} in {{.Environment.Name}}
+		{{- with .PreviousVersion}}, replacing {{.}}{{end}}
+	{{- end -}}
+	{{- with .Deployment.Freeze}} during the freeze {{.}}{{end}}
+{{- else if eq .Type "rollout" -}}
+	The rollout of {{.Application.Name}} {{.Rollout.Version}} to {{.Instance.Name}} in {{.Environment.Name}} is {{.Rollout.Status}}
+	{{- with .Rollout.Description}}: {{.}}{{end}}
+{{- else if eq .Type "policy_violation" -}}
+	{{.Application.Name}} {{.Deployment.Version}} on {{.Instance.Name}} in {{.Environment.Name}} violates the version policy:
+	{{- range $i, $v := .Violations}}{{if $i}};{{end}} {{$v.Message}}{{end}}
+{{- else if eq .Type "release" -}}
+	{{.Application.Name}} {{.Release.Version}} was released
+{{- else -}}
+	{{.Type}}
+{{- end -}}`
+
+var defaultNotification = template.Must(template.New("default").Parse(defaultNotificationTemplate))
+
+// NotificationChannel is a chat channel, like the channel of an on-call team, that is sent messages about events.
+type NotificationChannel struct {
+	Id   int32  `json:"id"`
+	Name string `json:"name"`
+	// Kind is one of the Channel kinds.
+	Kind string `json:"kind"`
+	// URL is the incoming webhook of the channel.
+	URL string `json:"url"`
+	// Template is a text/template rendering an Event as the message about it, the default message is used if it is empty.
+	Template string `json:"template,omitempty"`
+	// BatchSeconds is how long the events are collected for after the first one before they are sent as one message,
+	// like during a large rollout. If it is zero the events are sent within a second, together with the ones arriving
+	// in the same second.
+	BatchSeconds int32 `json:"batch_seconds,omitempty"`
+	// Routes are the events the channel is sent, it is sent the events matching any of them and no events without them.
+	Routes []NotificationRoute `json:"routes"`
+	// Disabled channels are not sent any events.
+	Disabled bool `json:"disabled"`
+}
+
+// NotificationRoute routes the events of an environment and application to a channel.
+type NotificationRoute struct {
+	// EnvironmentId only routes the events of the environment if it is set.
+	// Events without an environment, like releases, are then not routed.
+	EnvironmentId int32 `json:"environment_id,omitempty"`
+	// ApplicationId only routes the events of the application if it is set.
+	ApplicationId int32 `json:"application_id,omitempty"`
+	// Events are the routed event types, all events are routed if it is empty.
+	Events []string `json:"events,omitempty"`
+	// Redeploys also routes the deployments of the version that was already deployed, like a restart
+	// or a rescheduling, which are not routed otherwise.
+	Redeploys bool `json:"redeploys,omitempty"`
+}
+
+// Notification is a message posted to a channel.
+type Notification struct {
+	// Text is the message, describing every event on its own line if there are several.
+	Text   string  `json:"text"`
+	Events []Event `json:"events"`
+}
+
+// notificationFormats encode the notifications in the format of every kind of channel.
+var notificationFormats = map[string]func(Notification) ([]byte, error){
+	ChannelSlack: func(n Notification) ([]byte, error) {
+		return json.Marshal(map[string]string{"text": n.Text})
+	},
+	ChannelTeams: func(n Notification) ([]byte, error) {
+		return json.Marshal(map[string]any{
+			"type": "message",
+			"attachments": []any{map[string]any{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]any{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body": []any{map[string]any{
+						"type": "TextBlock",
+						"text": n.Text,
+						"wrap": true,
+					}},
+				},
+			}},
+		})
+	},
+	ChannelGeneric: func(n Notification) ([]byte, error) {
+		return json.Marshal(n)
+	},
+}
+
+func (r NotificationRoute) matches(e Event) bool {
+	if len(r.Events) > 0 && !slices.Contains(r.Events, e.Type) {
+		return false
+	}
+	if r.EnvironmentId != 0 && (e.Environment == nil || e.Environment.Id != r.EnvironmentId) {
+		return false
+	}
+	if r.ApplicationId != 0 && (e.Application == nil || e.Application.Id != r.ApplicationId) {
+		return false
+	}
+	if !r.Redeploys && e.redeploy() {
+		return false
+	}
+	return true
+}
+
+// redeploy reports whether the event is the deployment of the version that was already deployed.
+func (e Event) redeploy() bool {
+	return e.Type == EventDeployment && e.Deployment != nil && !e.Deployment.Undeployed && e.Deployment.Version == e.PreviousVersion
+}
+
+// routes reports whether the channel is sent the event.
+func (c NotificationChannel) routes(e Event) bool {
+	return !c.Disabled && slices.ContainsFunc(c.Routes, func(r NotificationRoute) bool { return r.matches(e) })
+}
+
+func (c *NotificationChannel) validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return invalid("name is required")
+	}
+
+	if _, ok := notificationFormats[c.Kind]; !ok {
+		return invalid("unknown channel kind %q, expected one of %s, %s or %s", c.Kind, ChannelSlack, ChannelTeams, ChannelGeneric)
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return invalid("invalid channel url %q", c.URL)
+	}
+
+	if _, err := c.parseTemplate(); err != nil {
+		return invalid("invalid template: %w", err)
+	}
+
+	if c.BatchSeconds < 0 || time.Duration(c.BatchSeconds)*time.Second > maxNotificationBatch {
+		return invalid("the events can be batched for at most %s", maxNotificationBatch)
+	}
+
+	for i, r := range c.Routes {
+		var events []string
+		for _, e := range r.Events {
+			if !slices.Contains(eventTypes, e) {
+				return invalid("unknown event type %q, expected one of %s", e, strings.Join(eventTypes, ", "))
+			}
+			if !slices.Contains(events, e) {
+				events = append(events, e)
+			}
+		}
+		c.Routes[i].Events = events
+	}
+	return nil
+}
+
+// parseTemplate parses the template of the channel, it returns nil if the channel uses the default message.
+func (c NotificationChannel) parseTemplate() (*template.Template, error) {
+	if c.Template == "" {
+		return nil, nil
+	}
+	return template.New(c.Name).Parse(c.Template)
+}
+
+// render returns the message about the event with the parsed template of the channel,
+// falling back to the default message if there is none or it fails.
+func (c NotificationChannel) render(t *template.Template, e Event) string {
+	if t != nil {
+		var b strings.Builder
+		err := t.Execute(&b, e)
+		if err == nil {
+			return strings.TrimSpace(b.String())
+		}
+		slog.Warn("rendering the notification template", "channel", c.Name, "event", e.Type, "error", err)
+	}
+
+	var b strings.Builder
+	if err := defaultNotification.Execute(&b, e); err != nil {
+		// Like a deployment of an instance that was deleted before it was described.
+		return e.Type
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func (a *App) ListNotificationChannels(ctx context.Context) ([]NotificationChannel, error) {
+	return a.store.ListNotificationChannels(ctx)
+}
+
+func (a *App) CreateNotificationChannel(ctx context.Context, channel NotificationChannel) (NotificationChannel, error) {
+	if err := channel.validate(); err != nil {
+		return NotificationChannel{}, err
+	}
+
+	id, err := a.store.CreateNotificationChannel(ctx, channel)
+	if err != nil {
+		return NotificationChannel{}, err
+	}
+	channel.Id = id
+
+	return channel, nil
+}
+
+// UpdateNotificationChannel replaces the channel with the id of the given one, including its routes.
+func (a *App) UpdateNotificationChannel(ctx context.Context, channel NotificationChannel) (NotificationChannel, error) {
+	if channel.Id == 0 {
+		return NotificationChannel{}, invalid("id is required")
+	}
+	if err := channel.validate(); err != nil {
+		return NotificationChannel{}, err
+	}
+
+	if err := a.store.UpdateNotificationChannel(ctx, channel); err != nil {
+		return NotificationChannel{}, err
+	}
+
+	return channel, nil
+}
+
+// DeleteNotificationChannel deletes the channel, the events that are batched for it are not sent.
+func (a *App) DeleteNotificationChannel(ctx context.Context, id int32) error {
+	return a.store.DeleteNotificationChannel(ctx, id)
+}
+
+// TestNotificationChannel sends the message about an example deployment to the channel right away,
+// whether it is disabled or not, and returns the error if it could not be sent.
+func (a *App) TestNotificationChannel(ctx context.Context, id int32) (Notification, error) {
+	if a.notifier == nil {
+		return Notification{}, errors.New("notifications are not configured")
+	}
+
+	channels, err := a.store.ListNotificationChannels(ctx)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	i := slices.IndexFunc(channels, func(c NotificationChannel) bool { return c.Id == id })
+	if i == -1 {
+		return Notification{}, fmt.Errorf("%w: notification channel %d", ErrNotFound, id)
+	}
+	channel := channels[i]
+
+	now := time.Now().UTC()
+	event := Event{
+		Id:          "00000000-0000-0000-0000-000000000000",
+		Type:        EventDeployment,
+		OccurredAt:  now,
+		Environment: &EventEntity{Name: "production"},
+		Application: &EventEntity{Name: "example"},
+		Instance:    &EventEntity{Name: "example-production"},
+		Deployment: &Deployment{
+			Component:  DefaultComponent,
+			Version:    "1.2.0",
+			DeployedAt: now,
+			ReceivedAt: now,
+		},
+		PreviousVersion: "1.1.0",
+	}
+
+	a.notifications.mu.Lock()
+	t := a.notifications.template(channel)
+	a.notifications.mu.Unlock()
+
+	n := Notification{
+		Text:   "Test message from Overseer: " + channel.render(t, event),
+		Events: []Event{event},
+	}
+	if err := a.sendNotification(ctx, channel, n); err != nil {
+		return Notification{}, fmt.Errorf("sending the test message to %s: %w", channel.Name, err)
+	}
+	return n, nil
+}
+
+// notificationBatch collects the messages about the events of a channel until they are sent.
+type notificationBatch struct {
+	channelId int32
+	lines     []string
+	events    []Event
+	sendAt    time.Time
+	attempts  int
+}
+
+// notifications holds the batches that are waiting to be sent.
+type notifications struct {
+	mu sync.Mutex
+	// batches collect the events of every channel.
+	batches map[int32]*notificationBatch
+	// retries are the batches that failed to be sent and are attempted again.
+	retries []*notificationBatch
+	// templates are the parsed templates of the channels.
+	templates map[int32]channelTemplate
+}
+
+// channelTemplate is the parsed template of a channel and the text it was parsed from, to parse it again when it changes.
+type channelTemplate struct {
+	text     string
+	template *template.Template
+}
+
+// template returns the parsed template of the channel, nil for the default message, parsing it only when it changed.
+// The caller must hold n.mu.
+func (n *notifications) template(c NotificationChannel) *template.Template {
+	if cached, ok := n.templates[c.Id]; ok && cached.text == c.Template {
+		return cached.template
+	}
+
+	t, err := c.parseTemplate()
+	if err != nil {
+		// The templates are validated when the channels are saved, the default message is used instead.
+		slog.Warn("parsing the notification template", "channel", c.Name, "error", err)
+	}
+	n.templates[c.Id] = channelTemplate{text: c.Template, template: t}
+	return t
+}
+
+// notify adds the event to the batches of the channels it is routed to.
+func (a *App) notify(ctx context.Context, event Event) error {
+	if a.notifier == nil {
+		return nil
+	}
+
+	channels, err := a.store.ListNotificationChannels(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.notifications.mu.Lock()
+	defer a.notifications.mu.Unlock()
+
+	// Forget the templates of the deleted channels.
+	maps.DeleteFunc(a.notifications.templates, func(id int32, _ channelTemplate) bool {
+		return !slices.ContainsFunc(channels, func(c NotificationChannel) bool { return c.Id == id })
+	})
+
+	for _, c := range channels {
+		if !c.routes(event) {
+			continue
+		}
+
+		b, ok := a.notifications.batches[c.Id]
+		if !ok {
+			b = &notificationBatch{
+				channelId: c.Id,
+				sendAt:    time.Now().Add(time.Duration(c.BatchSeconds) * time.Second),
+			}
+			a.notifications.batches[c.Id] = b
+		}
+		b.lines = append(b.lines, c.render(a.notifications.template(c), event))
+		b.events = append(b.events, event)
+	}
+	return nil
+}
+
+// RunNotifications sends the batched messages to the notification channels when they are due,
+// until the context is canceled. The events that are still being published and the messages that are batched are then sent right away.
+func (a *App) RunNotifications(ctx context.Context) error {
+	if a.notifier == nil {
+		return errors.New("notifications are not configured")
+	}
+
+	ticker := time.NewTicker(notificationTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.waitForEvents()
+			a.sendNotifications(context.WithoutCancel(ctx), time.Time{})
+			return nil
+		case <-ticker.C:
+			a.sendNotifications(ctx, time.Now())
+		}
+	}
+}
+
+// sendNotifications sends the batches that are due at t, or all batches if t is zero.
+// A batch that fails to be sent is attempted again later, until it runs out of attempts.
+func (a *App) sendNotifications(ctx context.Context, t time.Time) {
+	a.notifications.mu.Lock()
+	var due []*notificationBatch
+	for id, b := range a.notifications.batches {
+		if t.IsZero() || !b.sendAt.After(t) {
+			due = append(due, b)
+			delete(a.notifications.batches, id)
+		}
+	}
+	a.notifications.retries = slices.DeleteFunc(a.notifications.retries, func(b *notificationBatch) bool {
+		if t.IsZero() || !b.sendAt.After(t) {
+			due = append(due, b)
+			return true
+		}
+		return false
+	})
+	a.notifications.mu.Unlock()
+
+	if len(due) == 0 {
+		return
+	}
+
+	channels, err := a.store.ListNotificationChannels(ctx)
+	if err != nil {
+		slog.Error("listing the notification channels", "error", err)
+		a.retryNotifications(due...)
+		return
+	}
+
+	for _, b := range due {
+		// The channel could have been deleted or disabled since the events were batched.
+		i := slices.IndexFunc(channels, func(c NotificationChannel) bool { return c.Id == b.channelId })
+		if i == -1 || channels[i].Disabled {
+			continue
+		}
+
+		if err := a.sendNotification(ctx, channels[i], b.notification()); err != nil {
+			slog.Error("sending the notification", "channel", channels[i].Name, "events", len(b.events), "attempt", b.attempts+1, "error", err)
+			a.retryNotifications(b)
+		}
+	}
+}
+
+// retryNotifications schedules the batches to be attempted again, the ones that ran out of attempts are dropped.
+func (a *App) retryNotifications(batches ...*notificationBatch) {
+	a.notifications.mu.Lock()
+	defer a.notifications.mu.Unlock()
+
+	for _, b := range batches {
+		b.attempts++
+		if b.attempts >= maxNotificationAttempts {
+			slog.Warn("dropping the notification", "channel_id", b.channelId, "events", len(b.events), "attempts", b.attempts)
+			continue
+		}
+		b.sendAt = time.Now().Add(notificationRetryDelay)
+		a.notifications.retries = append(a.notifications.retries, b)
+	}
+}
+
+// notification returns the message about the events of the batch, listing them if there are several.
+func (b *notificationBatch) notification() Notification {
+	n := Notification{Events: b.events}
+	if len(b.lines) == 1 {
+		n.Text = b.lines[0]
+		return n
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%d events:", len(b.lines))
+	for i, line := range b.lines {
+		if i == maxNotificationLines {
+			fmt.Fprintf(&text, "\n… and %d more", len(b.lines)-maxNotificationLines)
+			break
+		}
+		text.WriteString("\n• " + line)
+	}
+	n.Text = text.String()
+	return n
+}
+
+func (a *App) sendNotification(ctx context.Context, channel NotificationChannel, n Notification) error {
+	body, err := notificationFormats[channel.Kind](n)
+	if err != nil {
+		return err
+	}
+
+	status, err := a.notifier.Post(ctx, channel.URL, map[string]string{"Content-Type": "application/json"}, body)
+	if err != nil {
+		return err
+	}
+	if status < 200 || status > 299 {
+		return fmt.Errorf("unexpected response status %d", status)
+	}
+	return nil
+}
@@ -0,0 +1,520 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"overseer/notify"
+)
+
+// channelStore only stores the notification channels, the other methods of the Store are not used by the notifications.
+type channelStore struct {
+	Store
+	channels []NotificationChannel
+}
+
+func (s *channelStore) ListNotificationChannels(ctx context.Context) ([]NotificationChannel, error) {
+	return slices.Clone(s.channels), nil
+}
+
+// fakeChat records the messages posted to its channels, the path of a request being the name of the channel.
+// The channels that are failing answer with an error.
+type fakeChat struct {
+	mu       sync.Mutex
+	messages map[string][]string
+	failing  map[string]bool
+}
+
+func newFakeChat() *fakeChat {
+	return &fakeChat{messages: map[string][]string{}, failing: map[string]bool{}}
+}
+
+func (c *fakeChat) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	channel := strings.TrimPrefix(r.URL.Path, "/")
+	if c.failing[channel] {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "not json", http.StatusUnsupportedMediaType)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	c.messages[channel] = append(c.messages[channel], string(body))
+}
+
+// received returns the bodies posted to the channel and forgets them.
+func (c *fakeChat) received(channel string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	messages := c.messages[channel]
+	delete(c.messages, channel)
+	return messages
+}
+
+func (c *fakeChat) fail(channel string, failing bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failing[channel] = failing
+}
+
+// newNotificationApp returns an App sending the notifications of the channels to the chat, with the URL of every channel
+// pointing to the chat channel of its name.
+func newNotificationApp(t *testing.T, chat *fakeChat, channels ...NotificationChannel) (*App, *channelStore) {
+	t.Helper()
+
+	server := httptest.NewServer(chat)
+	t.Cleanup(server.Close)
+
+	for i := range channels {
+		channels[i].Id = int32(i + 1)
+		channels[i].URL = server.URL + "/" + channels[i].Name
+		if err := channels[i].validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := &channelStore{channels: channels}
+	return New(store, Options{Notifier: notify.NewClient(time.Second)}), store
+}
+
+// slackText returns the text of a Slack message.
+func slackText(t *testing.T, body string) string {
+	t.Helper()
+
+	var m struct{ Text string }
+	if err := json.Unmarshal([]byte(body), &m); err != nil {
+		t.Fatal(err)
+	}
+	return m.Text
+}
+
+func deploymentEvent(env, application, version, previous string) Event {
+	return Event{
+		Type:            EventDeployment,
+		Environment:     &EventEntity{Id: 1, Name: env},
+		Application:     &EventEntity{Id: 2, Name: application},
+		Instance:        &EventEntity{Id: 3, Name: application + "-" + env},
+		Deployment:      &Deployment{Version: version},
+		PreviousVersion: previous,
+	}
+}
+
+func TestRenderNotification(t *testing.T) {
+	deployed := deploymentEvent("prod", "api", "1.2.0", "1.1.0")
+	undeployed := deploymentEvent("prod", "api", "", "1.1.0")
+	undeployed.Deployment.Undeployed = true
+	frozen := deploymentEvent("prod", "api", "1.2.0", "")
+	frozen.Deployment.Freeze = "holidays"
+	rollout := Event{
+		Type:        EventRollout,
+		Environment: deployed.Environment,
+		Application: deployed.Application,
+		Instance:    deployed.Instance,
+		Rollout:     &Rollout{Version: "1.2.0", Status: RolloutFailed, Description: "2 replicas crashed"},
+	}
+	violation := Event{
+		Type:        EventPolicyViolation,
+		Environment: deployed.Environment,
+		Application: deployed.Application,
+		Instance:    deployed.Instance,
+		Deployment:  deployed.Deployment,
+		Violations:  []PolicyViolation{{Message: "version 1.2.0 is forbidden"}, {Message: "version has not been deployed to staging"}},
+	}
+	release := Event{Type: EventRelease, Application: deployed.Application, Release: &Release{Version: "1.3.0"}}
+
+	tests := []struct {
+		name     string
+		template string
+		event    Event
+		want     string
+	}{
+		{name: "deployment", event: deployed, want: "api 1.2.0 was deployed to api-prod in prod, replacing 1.1.0"},
+		{name: "first deployment", event: deploymentEvent("prod", "api", "1.0.0", ""), want: "api 1.0.0 was deployed to api-prod in prod"},
+		{name: "undeployment", event: undeployed, want: "api was undeployed from api-prod in prod"},
+		{name: "deployment during a freeze", event: frozen, want: "api 1.2.0 was deployed to api-prod in prod during the freeze holidays"},
+		{name: "rollout", event: rollout, want: "The rollout of api 1.2.0 to api-prod in prod is failed: 2 replicas crashed"},
+		{name: "policy violation", event: violation, want: "api 1.2.0 on api-prod in prod violates the version policy: version 1.2.0 is forbidden; version has not been deployed to staging"},
+		{name: "release", event: release, want: "api 1.3.0 was released"},
+		{name: "unknown type", event: Event{Type: "audit"}, want: "audit"},
+		{name: "event that can not be described", event: Event{Type: EventDeployment, Deployment: deployed.Deployment}, want: "deployment"},
+		{
+			name:     "template",
+			template: "  :rocket: {{.Instance.Name}} is now running {{.Deployment.Version}}\n",
+			event:    deployed,
+			want:     ":rocket: api-prod is now running 1.2.0",
+		},
+		{
+			name:     "template falling back to the default message",
+			template: "{{.Release.Version}} is out",
+			event:    deployed,
+			want:     "api 1.2.0 was deployed to api-prod in prod, replacing 1.1.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NotificationChannel{Name: "ops", Template: tt.template}
+			tmpl, err := c.parseTemplate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.render(tmpl, tt.event); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNotificationRoutes(t *testing.T) {
+	prodApi := deploymentEvent("prod", "api", "1.0.0", "")
+	devApi := deploymentEvent("dev", "api", "1.0.0", "")
+	devApi.Environment.Id = 4
+	release := Event{Type: EventRelease, Application: prodApi.Application, Release: &Release{Version: "1.0.0"}}
+	redeploy := deploymentEvent("prod", "api", "1.0.0", "1.0.0")
+	undeployed := deploymentEvent("prod", "api", "", "")
+	undeployed.Deployment.Undeployed = true
+
+	tests := []struct {
+		name     string
+		routes   []NotificationRoute
+		disabled bool
+		routed   []Event
+		dropped  []Event
+	}{
+		{name: "no routes", dropped: []Event{prodApi, release}},
+		{name: "all events", routes: []NotificationRoute{{}}, routed: []Event{prodApi, devApi, release, undeployed}, dropped: []Event{redeploy}},
+		{name: "redeploys", routes: []NotificationRoute{{Redeploys: true}}, routed: []Event{prodApi, redeploy}},
+		{name: "disabled", routes: []NotificationRoute{{}}, disabled: true, dropped: []Event{prodApi, release}},
+		{name: "environment", routes: []NotificationRoute{{EnvironmentId: 1}}, routed: []Event{prodApi}, dropped: []Event{devApi, release}},
+		{name: "application", routes: []NotificationRoute{{ApplicationId: 2}}, routed: []Event{prodApi, devApi, release}},
+		{name: "other application", routes: []NotificationRoute{{ApplicationId: 5}}, dropped: []Event{prodApi, release}},
+		{name: "event types", routes: []NotificationRoute{{Events: []string{EventRelease}}}, routed: []Event{release}, dropped: []Event{prodApi}},
+		{
+			name:    "any of the routes",
+			routes:  []NotificationRoute{{EnvironmentId: 4, Events: []string{EventDeployment}}, {Events: []string{EventRelease}}},
+			routed:  []Event{devApi, release},
+			dropped: []Event{prodApi},
+		},
+		{
+			name:    "redeploys of an environment",
+			routes:  []NotificationRoute{{}, {EnvironmentId: 4, Redeploys: true}},
+			routed:  []Event{prodApi},
+			dropped: []Event{redeploy},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NotificationChannel{Name: "ops", Routes: tt.routes, Disabled: tt.disabled}
+			for _, e := range tt.routed {
+				if !c.routes(e) {
+					t.Errorf("expected the %s event of %s to be routed", e.Type, e.Application.Name)
+				}
+			}
+			for _, e := range tt.dropped {
+				if c.routes(e) {
+					t.Errorf("expected the %s event of %s not to be routed", e.Type, e.Application.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestNotificationBatching(t *testing.T) {
+	chat := newFakeChat()
+	a, _ := newNotificationApp(t, chat,
+		NotificationChannel{Name: "ops", Kind: ChannelSlack, BatchSeconds: 60, Routes: []NotificationRoute{{}}},
+		NotificationChannel{Name: "prod", Kind: ChannelGeneric, Routes: []NotificationRoute{{EnvironmentId: 1}}},
+		NotificationChannel{Name: "releases", Kind: ChannelTeams, Routes: []NotificationRoute{{Events: []string{EventRelease}}}},
+	)
+	ctx := context.Background()
+
+	events := []Event{
+		deploymentEvent("prod", "api", "1.1.0", "1.0.0"),
+		deploymentEvent("prod", "web", "2.0.0", ""),
+	}
+	for _, e := range events {
+		if err := a.notify(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now()
+
+	// The channel without batching is sent the events together right away.
+	a.sendNotifications(ctx, start)
+	got := chat.received("prod")
+	if len(got) != 1 {
+		t.Fatalf("expected one message to prod, got %q", got)
+	}
+	var n Notification
+	if err := json.Unmarshal([]byte(got[0]), &n); err != nil {
+		t.Fatal(err)
+	}
+	wantText := "2 events:\n• api 1.1.0 was deployed to api-prod in prod, replacing 1.0.0\n• web 2.0.0 was deployed to web-prod in prod"
+	if n.Text != wantText || len(n.Events) != 2 || n.Events[1].Deployment.Version != "2.0.0" {
+		t.Fatalf("expected the message %q about both events, got %+v", wantText, n)
+	}
+	if got := chat.received("ops"); len(got) != 0 {
+		t.Fatalf("expected the batch of ops to wait, got %q", got)
+	}
+
+	// The batch of ops collects the events until it is due.
+	if err := a.notify(ctx, Event{Type: EventRelease, Application: events[0].Application, Release: &Release{Version: "1.2.0"}}); err != nil {
+		t.Fatal(err)
+	}
+	a.sendNotifications(ctx, start.Add(30*time.Second))
+	if got := chat.received("ops"); len(got) != 0 {
+		t.Fatalf("expected the batch of ops to wait, got %q", got)
+	}
+	if got := chat.received("prod"); len(got) != 0 {
+		t.Fatalf("expected the release not to be routed to prod, got %q", got)
+	}
+
+	var card struct {
+		Attachments []struct {
+			Content struct {
+				Body []struct{ Text string }
+			}
+		}
+	}
+	got = chat.received("releases")
+	if len(got) != 1 {
+		t.Fatalf("expected one message to releases, got %q", got)
+	}
+	if err := json.Unmarshal([]byte(got[0]), &card); err != nil {
+		t.Fatal(err)
+	}
+	if len(card.Attachments) != 1 || len(card.Attachments[0].Content.Body) != 1 || card.Attachments[0].Content.Body[0].Text != "api 1.2.0 was released" {
+		t.Fatalf("expected an adaptive card about the release, got %s", got[0])
+	}
+
+	a.sendNotifications(ctx, start.Add(61*time.Second))
+	got = chat.received("ops")
+	if len(got) != 1 {
+		t.Fatalf("expected one message to ops, got %q", got)
+	}
+	wantText = "3 events:\n• api 1.1.0 was deployed to api-prod in prod, replacing 1.0.0\n• web 2.0.0 was deployed to web-prod in prod\n• api 1.2.0 was released"
+	if text := slackText(t, got[0]); text != wantText {
+		t.Fatalf("expected %q, got %q", wantText, text)
+	}
+
+	// A new batch starts with the next event.
+	a.sendNotifications(ctx, start.Add(time.Hour))
+	if got := chat.received("ops"); len(got) != 0 {
+		t.Fatalf("expected no message without events, got %q", got)
+	}
+}
+
+func TestNotificationLines(t *testing.T) {
+	chat := newFakeChat()
+	a, _ := newNotificationApp(t, chat, NotificationChannel{Name: "ops", Kind: ChannelSlack, Routes: []NotificationRoute{{}}})
+	ctx := context.Background()
+
+	for i := range maxNotificationLines + 5 {
+		if err := a.notify(ctx, deploymentEvent("prod", "api", fmt.Sprintf("1.%d.0", i), "")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a.sendNotifications(ctx, time.Time{})
+
+	got := chat.received("ops")
+	if len(got) != 1 {
+		t.Fatalf("expected one message, got %d", len(got))
+	}
+	lines := strings.Split(slackText(t, got[0]), "\n")
+	if len(lines) != maxNotificationLines+2 || lines[0] != fmt.Sprintf("%d events:", maxNotificationLines+5) || lines[len(lines)-1] != "… and 5 more" {
+		t.Fatalf("expected %d events to be listed and 5 to be counted, got %q", maxNotificationLines, lines)
+	}
+}
+
+func TestNotificationRetries(t *testing.T) {
+	chat := newFakeChat()
+	a, store := newNotificationApp(t, chat,
+		NotificationChannel{Name: "ops", Kind: ChannelSlack, Routes: []NotificationRoute{{}}},
+		NotificationChannel{Name: "prod", Kind: ChannelSlack, Routes: []NotificationRoute{{}}},
+		NotificationChannel{Name: "dev", Kind: ChannelSlack, Routes: []NotificationRoute{{}}},
+	)
+	ctx := context.Background()
+
+	if err := a.notify(ctx, deploymentEvent("prod", "api", "1.1.0", "1.0.0")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The channels could be deleted or disabled after the events were batched.
+	store.channels[1].Disabled = true
+	store.channels = slices.Delete(store.channels, 2, 3)
+
+	// The message is attempted again after the retry delay until it runs out of attempts.
+	chat.fail("ops", true)
+	a.sendNotifications(ctx, time.Now())
+	for range maxNotificationAttempts - 2 {
+		a.sendNotifications(ctx, time.Now().Add(notificationRetryDelay))
+	}
+	if got := chat.received("ops"); len(got) != 0 {
+		t.Fatalf("expected no message while the channel fails, got %q", got)
+	}
+
+	chat.fail("ops", false)
+	a.sendNotifications(ctx, time.Now())
+	if got := chat.received("ops"); len(got) != 0 {
+		t.Fatalf("expected the message to wait for the retry delay, got %q", got)
+	}
+	a.sendNotifications(ctx, time.Now().Add(notificationRetryDelay))
+	if got := chat.received("ops"); len(got) != 1 {
+		t.Fatalf("expected the message to be sent on the last attempt, got %q", got)
+	}
+
+	for _, channel := range []string{"prod", "dev"} {
+		if got := chat.received(channel); len(got) != 0 {
+			t.Fatalf("expected no message to %s, got %q", channel, got)
+		}
+	}
+
+	// A message is dropped once it runs out of attempts.
+	chat.fail("ops", true)
+	if err := a.notify(ctx, deploymentEvent("prod", "api", "1.2.0", "1.1.0")); err != nil {
+		t.Fatal(err)
+	}
+	for range maxNotificationAttempts {
+		a.sendNotifications(ctx, time.Time{})
+	}
+	chat.fail("ops", false)
+	a.sendNotifications(ctx, time.Time{})
+	if got := chat.received("ops"); len(got) != 0 {
+		t.Fatalf("expected the message to be dropped, got %q", got)
+	}
+}
+
+func TestTestNotificationChannel(t *testing.T) {
+	chat := newFakeChat()
+	a, _ := newNotificationApp(t, chat,
+		NotificationChannel{Name: "ops", Kind: ChannelSlack, Template: "{{.Deployment.Version}} is live", Disabled: true},
+		NotificationChannel{Name: "prod", Kind: ChannelGeneric},
+	)
+	ctx := context.Background()
+
+	// Disabled channels are sent the test message too.
+	n, err := a.TestNotificationChannel(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Test message from Overseer: 1.2.0 is live"
+	if n.Text != want || len(n.Events) != 1 {
+		t.Fatalf("expected the test message %q, got %+v", want, n)
+	}
+	if got := chat.received("ops"); len(got) != 1 || slackText(t, got[0]) != want {
+		t.Fatalf("expected the test message to be posted, got %q", got)
+	}
+
+	if _, err := a.TestNotificationChannel(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	got := chat.received("prod")
+	if len(got) != 1 || !strings.Contains(got[0], `"text":"Test message from Overseer: example 1.2.0 was deployed to example-production in production, replacing 1.1.0"`) {
+		t.Fatalf("expected the test message with the default template, got %q", got)
+	}
+
+	chat.fail("prod", true)
+	if _, err := a.TestNotificationChannel(ctx, 2); err == nil || !strings.Contains(err.Error(), "unexpected response status 503") {
+		t.Fatalf("expected the error of the channel, got %v", err)
+	}
+
+	if _, err := a.TestNotificationChannel(ctx, 3); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if _, err := New(&channelStore{}, Options{}).TestNotificationChannel(ctx, 1); err == nil {
+		t.Fatal("expected an error without a notifier")
+	}
+}
+
+func TestNotificationRedeploys(t *testing.T) {
+	chat := newFakeChat()
+	a, _ := newNotificationApp(t, chat,
+		NotificationChannel{Name: "ops", Kind: ChannelSlack, Routes: []NotificationRoute{{}}},
+		NotificationChannel{Name: "restarts", Kind: ChannelSlack, Routes: []NotificationRoute{{Events: []string{EventDeployment}, Redeploys: true}}},
+	)
+	ctx := context.Background()
+
+	for _, e := range []Event{
+		deploymentEvent("prod", "api", "1.1.0", "1.0.0"),
+		// A restart or rescheduling of the version that is already running.
+		deploymentEvent("prod", "api", "1.1.0", "1.1.0"),
+	} {
+		if err := a.notify(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a.sendNotifications(ctx, time.Time{})
+
+	want := "api 1.1.0 was deployed to api-prod in prod, replacing 1.0.0"
+	if got := chat.received("ops"); len(got) != 1 || slackText(t, got[0]) != want {
+		t.Fatalf("expected only the message %q, got %q", want, got)
+	}
+	want = "2 events:\n• api 1.1.0 was deployed to api-prod in prod, replacing 1.0.0\n• api 1.1.0 was deployed to api-prod in prod, replacing 1.1.0"
+	if got := chat.received("restarts"); len(got) != 1 || slackText(t, got[0]) != want {
+		t.Fatalf("expected the message %q about the redeploy, got %q", want, got)
+	}
+}
+
+func TestNotificationTemplates(t *testing.T) {
+	chat := newFakeChat()
+	a, store := newNotificationApp(t, chat,
+		NotificationChannel{Name: "ops", Kind: ChannelSlack, Template: "{{.Deployment.Version}} is live", Routes: []NotificationRoute{{}}},
+		NotificationChannel{Name: "prod", Kind: ChannelSlack, Routes: []NotificationRoute{{}}},
+	)
+	ctx := context.Background()
+
+	notify := func(version string) {
+		t.Helper()
+		if err := a.notify(ctx, deploymentEvent("prod", "api", version, "")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The template is parsed once for the events of the channel.
+	notify("1.0.0")
+	parsed := a.notifications.templates[1].template
+	notify("1.1.0")
+	if parsed == nil || a.notifications.templates[1].template != parsed {
+		t.Fatalf("expected the template to be parsed once, got %+v", a.notifications.templates[1])
+	}
+	if a.notifications.templates[2].template != nil {
+		t.Fatalf("expected no template for the default message, got %+v", a.notifications.templates[2])
+	}
+
+	// A changed template is parsed again.
+	store.channels[0].Template = "{{.Deployment.Version}} is out"
+	notify("1.2.0")
+	if a.notifications.templates[1].template == parsed {
+		t.Fatal("expected the changed template to be parsed again")
+	}
+
+	a.sendNotifications(ctx, time.Time{})
+	want := "3 events:\n• 1.0.0 is live\n• 1.1.0 is live\n• 1.2.0 is out"
+	if got := chat.received("ops"); len(got) != 1 || slackText(t, got[0]) != want {
+		t.Fatalf("expected the message %q, got %q", want, got)
+	}
+
+	// The templates of deleted channels are forgotten.
+	store.channels = store.channels[1:]
+	notify("1.3.0")
+	if _, ok := a.notifications.templates[1]; ok {
+		t.Fatal("expected the template of the deleted channel to be forgotten")
+	}
+}
@@ -0,0 +1,304 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// VersionPolicy declares which versions should be running on the instances of an application, or on a single instance.
+// Every rule is optional.
+//
+// The policy of an instance is combined with the one of its application: its pinned version and allowed range
+// replace the ones of the application if they are set, and the forbidden versions and the upstream rule of both apply.
+type VersionPolicy struct {
+	ApplicationId int32 `json:"application_id"`
+	// InstanceId is zero for the policy of the application, which applies to all of its instances.
+	InstanceId int32 `json:"instance_id,omitempty"`
+	// Pinned is the version that is expected to be running.
+	Pinned string `json:"pinned,omitempty"`
+	// Allowed is a range of versions like ">=1.2.0, <2.0.0", "^1.2", "~1.2.3" or "1.x",
+	// several ranges can be combined with ||. Prereleases are only allowed by ranges that mention a prerelease.
+	Allowed string `json:"allowed,omitempty"`
+	// Forbidden are versions that must not be running, like versions with known defects.
+	Forbidden []string `json:"forbidden,omitempty"`
+	// NotAheadOfUpstream forbids versions that have not yet been deployed to an environment
+	// the environment of the instance is promoted from.
+	NotAheadOfUpstream bool `json:"not_ahead_of_upstream,omitempty"`
+}
+
+// The rules of a VersionPolicy that can be violated.
+const (
+	RulePinned          = "pinned"
+	RuleAllowed         = "allowed"
+	RuleForbidden       = "forbidden"
+	RuleAheadOfUpstream = "ahead_of_upstream"
+)
+
+// PolicyViolation is a rule of a VersionPolicy broken by a deployment.
+type PolicyViolation struct {
+	DeploymentId string `json:"deployment_id"`
+	InstanceId   int32  `json:"instance_id"`
+	Version      string `json:"version"`
+	// Rule is one of RulePinned, RuleAllowed, RuleForbidden or RuleAheadOfUpstream.
+	Rule string `json:"rule"`
+	// Message explains the violation, like the version that was expected instead.
+	Message    string    `json:"message"`
+	DetectedAt time.Time `json:"detected_at"`
+	// Current is set if the deployment is still the current one of its instance component.
+	Current bool `json:"current"`
+}
+
+func (a *App) ListVersionPolicies(ctx context.Context) ([]VersionPolicy, error) {
+	return a.store.ListVersionPolicies(ctx)
+}
+
+// SetVersionPolicy replaces the policy of the instance, or of the application if the instance id is zero.
+// Deployments are evaluated against the policy when they are registered, the recorded violations are not re-evaluated.
+func (a *App) SetVersionPolicy(ctx context.Context, policy VersionPolicy) error {
+	if policy.InstanceId != 0 {
+		instance, err := a.store.GetInstance(ctx, policy.InstanceId)
+		if err != nil {
+			return err
+		}
+		policy.ApplicationId = instance.ApplicationId
+	} else if policy.ApplicationId == 0 {
+		return invalid("application id or instance id is required")
+	}
+
+	if policy.Allowed != "" {
+		if _, err := parseConstraint(policy.Allowed); err != nil {
+			return err
+		}
+	}
+
+	var forbidden []string
+	for _, v := range policy.Forbidden {
+		if v = strings.TrimSpace(v); v != "" && !slices.Contains(forbidden, v) {
+			forbidden = append(forbidden, v)
+		}
+	}
+	policy.Forbidden = forbidden
+	policy.Pinned = strings.TrimSpace(policy.Pinned)
+
+	return a.store.SaveVersionPolicy(ctx, policy)
+}
+
+// DeleteVersionPolicy deletes the policy of the instance, or of the application if the instance id is zero.
+func (a *App) DeleteVersionPolicy(ctx context.Context, applicationId, instanceId int32) error {
+	if applicationId == 0 && instanceId == 0 {
+		return invalid("application id or instance id is required")
+	}
+
+	return a.store.DeleteVersionPolicy(ctx, applicationId, instanceId)
+}
+
+type ListPolicyViolationsParams struct {
+	// InstanceId only lists the violations of the instance if it is set.
+	InstanceId int32
+	// CurrentOnly only lists the violations of the current deployments.
+	CurrentOnly bool
+}
+
+// ListPolicyViolations lists the recorded policy violations, ordered by when they were detected.
+func (a *App) ListPolicyViolations(ctx context.Context, params ListPolicyViolationsParams) ([]PolicyViolation, error) {
+	violations, err := a.store.ListPolicyViolations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := a.store.ListCurrentDeployments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]bool{}
+	for _, d := range deployments {
+		current[d.Id] = true
+	}
+
+	result := []PolicyViolation{}
+	for _, v := range violations {
+		v.Current = current[v.DeploymentId]
+		if (params.InstanceId != 0 && v.InstanceId != params.InstanceId) || (params.CurrentOnly && !v.Current) {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// checkPolicies evaluates a registered deployment against the policies of its instance and records the violations.
+// Only versions deployed to the primary component are evaluated, undeployments and late deployments are not.
+func (a *App) checkPolicies(ctx context.Context, d Deployment) error {
+	if d.Undeployed || d.Late {
+		return nil
+	}
+
+	instance, err := a.store.GetInstance(ctx, d.InstanceId)
+	if err != nil {
+		return err
+	}
+	if d.Component != instance.PrimaryComponent {
+		return nil
+	}
+
+	policies, err := a.store.ListVersionPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		policy VersionPolicy
+		found  bool
+	)
+	for _, p := range policies {
+		if p.ApplicationId != instance.ApplicationId || (p.InstanceId != 0 && p.InstanceId != instance.Id) {
+			continue
+		}
+		found = true
+		if p.InstanceId == 0 || p.Pinned != "" {
+			policy.Pinned = p.Pinned
+		}
+		if p.InstanceId == 0 || p.Allowed != "" {
+			policy.Allowed = p.Allowed
+		}
+		policy.Forbidden = append(policy.Forbidden, p.Forbidden...)
+		policy.NotAheadOfUpstream = policy.NotAheadOfUpstream || p.NotAheadOfUpstream
+	}
+	if !found {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	var violations []PolicyViolation
+	violate := func(rule, format string, args ...any) {
+		violations = append(violations, PolicyViolation{
+			DeploymentId: d.Id,
+			InstanceId:   d.InstanceId,
+			Version:      d.Version,
+			Rule:         rule,
+			Message:      fmt.Sprintf(format, args...),
+			DetectedAt:   now,
+		})
+	}
+
+	if policy.Pinned != "" && !sameVersion(d.Version, policy.Pinned) {
+		violate(RulePinned, "expected version %s", policy.Pinned)
+	}
+
+	if policy.Allowed != "" {
+		c, err := parseConstraint(policy.Allowed)
+		if err != nil {
+			return err
+		}
+		if !c.allows(d.Version) {
+			violate(RuleAllowed, "version is not in the allowed range %s", policy.Allowed)
+		}
+	}
+
+	if i := slices.IndexFunc(policy.Forbidden, func(f string) bool { return sameVersion(d.Version, f) }); i != -1 {
+		violate(RuleForbidden, "version %s is forbidden", policy.Forbidden[i])
+	}
+
+	if policy.NotAheadOfUpstream {
+		upstream, err := a.aheadOfUpstream(ctx, instance, d.Version)
+		if err != nil {
+			return err
+		}
+		if len(upstream) > 0 {
+			violate(RuleAheadOfUpstream, "version has not been deployed to %s", strings.Join(upstream, " or "))
+		}
+	}
+
+	for _, v := range violations {
+		slog.Warn("deployment violates the version policy", "instance", v.InstanceId, "version", v.Version, "rule", v.Rule, "message", v.Message)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	if err := a.store.AddPolicyViolations(ctx, violations); err != nil {
+		return err
+	}
+
+	a.publish(ctx, Event{Type: EventPolicyViolation, Deployment: &d, Violations: violations}, d.InstanceId, 0)
+	return nil
+}
+
+// aheadOfUpstream returns the names of the environments the environment of the instance is promoted from,
+// if the version is ahead of all of them. A version is ahead of an environment with an instance of the application
+// if it has never been deployed there and is not older than a version running there. Environments without an instance
+// of the application are ignored, the result is empty if there are none.
+func (a *App) aheadOfUpstream(ctx context.Context, instance Instance, version string) ([]string, error) {
+	edges, err := a.store.ListPromotions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []int32
+	for _, e := range edges {
+		if e.ToEnvironmentId == instance.EnvironmentId {
+			sources = append(sources, e.FromEnvironmentId)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	instances, err := a.store.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// upstream holds the instances of the application in the source environments.
+	upstream := map[int32]InstanceAndDeploymentResult{}
+	for _, i := range instances {
+		if i.Instance.ApplicationId == instance.ApplicationId && slices.Contains(sources, i.Instance.EnvironmentId) {
+			upstream[i.Instance.Id] = i
+		}
+	}
+	if len(upstream) == 0 {
+		return nil, nil
+	}
+
+	for _, u := range upstream {
+		versions, err := a.store.ListDeployedVersions(ctx, u.Instance.Id, u.Instance.PrimaryComponent)
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(versions, func(v string) bool { return sameVersion(v, version) }) {
+			return nil, nil
+		}
+	}
+
+	for _, u := range upstream {
+		if u.Deployment == nil || u.Deployment.Undeployed {
+			continue
+		}
+		if c, ok := compareVersions(version, u.Deployment.Version); ok && c < 0 {
+			return nil, nil
+		}
+	}
+
+	environments, err := a.store.ListEnvironments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deployedTo := map[int32]bool{}
+	for _, u := range upstream {
+		deployedTo[u.Instance.EnvironmentId] = true
+	}
+
+	var names []string
+	for _, e := range environments {
+		if deployedTo[e.Id] {
+			names = append(names, e.Name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,87 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+
+	"overseer/app"
+)
+
+func TestNotAheadOfUpstream(t *testing.T) {
+	f := newFixture(t, app.Options{})
+	ctx := context.Background()
+
+	// prod is promoted from staging, and the dev environment has no instance of api.
+	staging, err := f.app.CreateEnvironment(ctx, "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev, err := f.app.CreateEnvironment(ctx, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream, err := f.app.CreateInstance(ctx, app.CreateInstanceParameters{EnvironmentId: staging.Id, ApplicationId: f.appl.Id, Name: "api-staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.app.SetPromotionSources(ctx, f.env.Id, []int32{staging.Id, dev.Id}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.app.SetVersionPolicy(ctx, app.VersionPolicy{ApplicationId: f.appl.Id, NotAheadOfUpstream: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		instance  int32
+		component string
+		version   string
+		undeploy  bool
+		wantAhead bool
+	}{
+		{name: "first deployment upstream", instance: upstream, version: "v1.0.0"},
+		{name: "version deployed upstream", instance: f.instance, version: "1.0.0"},
+		{name: "version not deployed upstream", instance: f.instance, version: "1.1.0", wantAhead: true},
+		{name: "version older than upstream", instance: f.instance, version: "0.9.0"},
+		{name: "upstream deployment", instance: upstream, version: "1.2.0"},
+		{name: "upstream undeployment", instance: upstream, undeploy: true},
+		{name: "version once deployed upstream", instance: f.instance, version: "1.2"},
+		{name: "other component upstream", instance: upstream, component: "sidecar", version: "1.3.0"},
+		{name: "version only deployed to another component upstream", instance: f.instance, version: "1.3.0", wantAhead: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := f.app.RegisterDeployment(ctx, app.RegisterDeploymentParams{
+				InstanceId: tt.instance,
+				Component:  tt.component,
+				Version:    tt.version,
+				Undeployed: tt.undeploy,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			violations, err := f.app.ListPolicyViolations(ctx, app.ListPolicyViolationsParams{InstanceId: tt.instance})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ahead []app.PolicyViolation
+			for _, v := range violations {
+				if v.DeploymentId == d.Id && v.Rule == app.RuleAheadOfUpstream {
+					ahead = append(ahead, v)
+				}
+			}
+
+			if !tt.wantAhead {
+				if len(ahead) != 0 {
+					t.Fatalf("expected no violation, got %+v", ahead)
+				}
+				return
+			}
+			if len(ahead) != 1 || ahead[0].Message != "version has not been deployed to staging" {
+				t.Fatalf("expected the version to be ahead of staging, got %+v", ahead)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// PromotionEdge is an edge of the promotion graph, versions are promoted from one environment to the other,
+// like from staging to prod. An environment may be promoted to several environments, like prod-eu and prod-us,
+// and be promoted to from several.
+type PromotionEdge struct {
+	FromEnvironmentId int32 `json:"from_environment_id"`
+	ToEnvironmentId   int32 `json:"to_environment_id"`
+}
+
+// ApplicationPromotions tracks the versions of an application through the promotion graph.
+type ApplicationPromotions struct {
+	ApplicationId int32 `json:"application_id"`
+	// Versions are the versions deployed to any environment, the most recently deployed first.
+	Versions []VersionPromotion `json:"versions"`
+	// Pending are the versions awaiting promotion, ordered by when they entered the environment they are promoted from.
+	Pending []PendingPromotion `json:"pending"`
+}
+
+// VersionPromotion is how a version of an application moved through the environments.
+type VersionPromotion struct {
+	Version string `json:"version"`
+	// Stages are the environments the version was deployed to, in the order it entered them.
+	Stages []StageEntry `json:"stages"`
+	// Skipped are the stages the version was deployed past without first being deployed to them.
+	Skipped []SkippedStage `json:"skipped"`
+}
+
+// StageEntry is when a version entered an environment, the first time it was deployed to an instance there.
+type StageEntry struct {
+	EnvironmentId int32     `json:"environment_id"`
+	EnteredAt     time.Time `json:"entered_at"`
+}
+
+// SkippedStage is a stage a version skipped, it entered ToEnvironmentId without having been in EnvironmentId,
+// or in any of the other environments ToEnvironmentId is promoted from.
+type SkippedStage struct {
+	EnvironmentId   int32 `json:"environment_id"`
+	ToEnvironmentId int32 `json:"to_environment_id"`
+}
+
+// PendingPromotion is a version deployed to an environment but not yet to an environment it is promoted to.
+type PendingPromotion struct {
+	Version           string `json:"version"`
+	FromEnvironmentId int32  `json:"from_environment_id"`
+	ToEnvironmentId   int32  `json:"to_environment_id"`
+	// Deployed are the versions currently deployed to ToEnvironmentId, empty if there are none.
+	Deployed []string `json:"deployed"`
+	// Since is when the version entered FromEnvironmentId.
+	Since time.Time `json:"since"`
+}
+
+func (a *App) ListPromotionEdges(ctx context.Context) ([]PromotionEdge, error) {
+	return a.store.ListPromotions(ctx)
+}
+
+// SetPromotionSources replaces the environments versions are promoted to the environment from,
+// an empty list makes it a first stage. The promotion graph may not have cycles.
+func (a *App) SetPromotionSources(ctx context.Context, environmentId int32, sourceIds []int32) error {
+	if environmentId == 0 {
+		return invalid("environment id is required")
+	}
+
+	environments, err := a.store.ListEnvironments(ctx)
+	if err != nil {
+		return err
+	}
+
+	exists := map[int32]bool{}
+	for _, e := range environments {
+		exists[e.Id] = true
+	}
+
+	var sources []int32
+	for _, id := range append([]int32{environmentId}, sourceIds...) {
+		if !exists[id] {
+			return fmt.Errorf("environment %d: %w", id, ErrNotFound)
+		}
+		if id != environmentId && !slices.Contains(sources, id) {
+			sources = append(sources, id)
+		}
+	}
+	if slices.Contains(sourceIds, environmentId) {
+		return invalid("an environment can not be promoted from itself")
+	}
+
+	edges, err := a.store.ListPromotions(ctx)
+	if err != nil {
+		return err
+	}
+
+	next := map[int32][]int32{}
+	for _, e := range edges {
+		if e.ToEnvironmentId != environmentId {
+			next[e.FromEnvironmentId] = append(next[e.FromEnvironmentId], e.ToEnvironmentId)
+		}
+	}
+
+	// The new edges close a cycle if one of the sources is promoted to from the environment.
+	reachable := map[int32]bool{}
+	queue := []int32{environmentId}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, to := range next[id] {
+			if !reachable[to] {
+				reachable[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+	for _, id := range sources {
+		if reachable[id] {
+			return invalid("promoting environment %d from environment %d would create a cycle", environmentId, id)
+		}
+	}
+
+	return a.store.SetPromotionSources(ctx, environmentId, sources)
+}
+
+// ListPromotions tracks the versions of every application through the promotion graph.
+//
+// A version enters an environment when it is first deployed to the primary component of an instance of the
+// application there, so the stages are only as complete as the deployment history that is kept.
+// A version deployed to an environment is awaiting promotion to an environment it is promoted to, if the
+// application has an instance there which does not run the version or a later one. Versions that can not be
+// compared, like commit hashes, are later if they were deployed later.
+func (a *App) ListPromotions(ctx context.Context) ([]ApplicationPromotions, error) {
+	applications, err := a.store.ListApplications(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	edges, err := a.store.ListPromotions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := a.store.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := a.store.ListDeployments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type stageKey struct {
+		applicationId int32
+		environmentId int32
+	}
+
+	byId := map[int32]Instance{}
+	// current holds the versions currently deployed to the instances of every application in every environment.
+	current := map[stageKey][]string{}
+	for _, i := range instances {
+		byId[i.Instance.Id] = i.Instance
+		key := stageKey{i.Instance.ApplicationId, i.Instance.EnvironmentId}
+		if _, ok := current[key]; !ok {
+			current[key] = nil
+		}
+		if d := i.Deployment; d != nil && !d.Undeployed && d.Version != "" && !slices.Contains(current[key], d.Version) {
+			current[key] = append(current[key], d.Version)
+		}
+	}
+
+	// entered holds when every version of every application entered each environment.
+	entered := map[int32]map[string]map[int32]time.Time{}
+	for _, d := range deployments {
+		instance, ok := byId[d.InstanceId]
+		if !ok || d.Component != instance.PrimaryComponent || d.Undeployed || d.Version == "" {
+			continue
+		}
+
+		versions := entered[instance.ApplicationId]
+		if versions == nil {
+			versions = map[string]map[int32]time.Time{}
+			entered[instance.ApplicationId] = versions
+		}
+		stages := versions[d.Version]
+		if stages == nil {
+			stages = map[int32]time.Time{}
+			versions[d.Version] = stages
+		}
+		if at, ok := stages[instance.EnvironmentId]; !ok || d.OrderedAt().Before(at) {
+			stages[instance.EnvironmentId] = d.OrderedAt()
+		}
+	}
+
+	sources := map[int32][]int32{}
+	for _, e := range edges {
+		sources[e.ToEnvironmentId] = append(sources[e.ToEnvironmentId], e.FromEnvironmentId)
+	}
+
+	result := make([]ApplicationPromotions, 0, len(applications))
+	for _, application := range applications {
+		promotions := ApplicationPromotions{
+			ApplicationId: application.Id,
+			Versions:      []VersionPromotion{},
+			Pending:       []PendingPromotion{},
+		}
+		versions := entered[application.Id]
+
+		for version, stages := range versions {
+			v := VersionPromotion{Version: version, Stages: []StageEntry{}, Skipped: []SkippedStage{}}
+			for environmentId, at := range stages {
+				v.Stages = append(v.Stages, StageEntry{EnvironmentId: environmentId, EnteredAt: at})
+
+				var skipped []int32
+				for _, from := range sources[environmentId] {
+					if _, ok := current[stageKey{application.Id, from}]; !ok {
+						// The application is not deployed to the environment, it can't be passed through.
+						continue
+					}
+					if fromAt, ok := stages[from]; ok && !fromAt.After(at) {
+						skipped = nil
+						break
+					}
+					skipped = append(skipped, from)
+				}
+				for _, from := range skipped {
+					v.Skipped = append(v.Skipped, SkippedStage{EnvironmentId: from, ToEnvironmentId: environmentId})
+				}
+			}
+
+			slices.SortFunc(v.Stages, func(a, b StageEntry) int {
+				return cmp.Or(a.EnteredAt.Compare(b.EnteredAt), cmp.Compare(a.EnvironmentId, b.EnvironmentId))
+			})
+			slices.SortFunc(v.Skipped, func(a, b SkippedStage) int {
+				return cmp.Or(cmp.Compare(a.ToEnvironmentId, b.ToEnvironmentId), cmp.Compare(a.EnvironmentId, b.EnvironmentId))
+			})
+			promotions.Versions = append(promotions.Versions, v)
+		}
+
+		slices.SortFunc(promotions.Versions, func(a, b VersionPromotion) int {
+			return cmp.Or(lastEntered(b).Compare(lastEntered(a)), cmp.Compare(a.Version, b.Version))
+		})
+
+		for _, e := range edges {
+			deployed, ok := current[stageKey{application.Id, e.ToEnvironmentId}]
+			if !ok {
+				continue
+			}
+
+			for _, version := range current[stageKey{application.Id, e.FromEnvironmentId}] {
+				since := versions[version][e.FromEnvironmentId]
+
+				pending := !slices.Contains(deployed, version)
+				for _, d := range deployed {
+					if c, ok := compareVersions(version, d); ok {
+						pending = pending && c > 0
+					} else {
+						pending = pending && versions[d][e.ToEnvironmentId].Before(since)
+					}
+				}
+				if !pending {
+					continue
+				}
+
+				promotions.Pending = append(promotions.Pending, PendingPromotion{
+					Version:           version,
+					FromEnvironmentId: e.FromEnvironmentId,
+					ToEnvironmentId:   e.ToEnvironmentId,
+					Deployed:          append([]string{}, deployed...),
+					Since:             since,
+				})
+			}
+		}
+
+		slices.SortStableFunc(promotions.Pending, func(a, b PendingPromotion) int {
+			return a.Since.Compare(b.Since)
+		})
+		result = append(result, promotions)
+	}
+
+	return result, nil
+}
+
+// lastEntered returns when the version last entered an environment.
+func lastEntered(v VersionPromotion) time.Time {
+	if len(v.Stages) == 0 {
+		return time.Time{}
+	}
+	return v.Stages[len(v.Stages)-1].EnteredAt
+}
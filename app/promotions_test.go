@@ -0,0 +1,360 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"overseer/app"
+	"overseer/storage/memory"
+)
+
+// promotionFixture has the environments dev, staging, prod-eu and prod-us, promoted from dev to staging and
+// from staging to both prods. The application api has an instance in every environment, the application web
+// only in dev and prod-eu.
+type promotionFixture struct {
+	app          *app.App
+	environments map[string]int32
+	applications map[string]int32
+	// instances are the instances by application and environment name.
+	instances map[[2]string]int32
+}
+
+// promotionStart is when the deployments of a promotion test start, they are deployed hours after it.
+var promotionStart = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newPromotionFixture(t *testing.T) promotionFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	f := promotionFixture{
+		app:          app.New(memory.New(), app.Options{}),
+		environments: map[string]int32{},
+		applications: map[string]int32{},
+		instances:    map[[2]string]int32{},
+	}
+	for _, name := range []string{"dev", "staging", "prod-eu", "prod-us"} {
+		env, err := f.app.CreateEnvironment(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.environments[name] = env.Id
+	}
+
+	placement := map[string][]string{
+		"api": {"dev", "staging", "prod-eu", "prod-us"},
+		"web": {"dev", "prod-eu"},
+	}
+	for _, name := range []string{"api", "web"} {
+		appl, err := f.app.CreateApplication(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.applications[name] = appl.Id
+
+		for _, env := range placement[name] {
+			id, err := f.app.CreateInstance(ctx, app.CreateInstanceParameters{
+				EnvironmentId: f.environments[env],
+				ApplicationId: appl.Id,
+				Name:          name + "-" + env,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.instances[[2]string{name, env}] = id
+		}
+	}
+
+	edges := map[string][]string{"staging": {"dev"}, "prod-eu": {"staging"}, "prod-us": {"staging"}}
+	for to, from := range edges {
+		var sourceIds []int32
+		for _, name := range from {
+			sourceIds = append(sourceIds, f.environments[name])
+		}
+		if err := f.app.SetPromotionSources(ctx, f.environments[to], sourceIds); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return f
+}
+
+// environmentName returns the name of the environment with the id.
+func (f promotionFixture) environmentName(id int32) string {
+	for name, envId := range f.environments {
+		if envId == id {
+			return name
+		}
+	}
+	return fmt.Sprint(id)
+}
+
+// promotionDeployment is a deployment of a promotion test, hour is the number of hours after promotionStart.
+type promotionDeployment struct {
+	application string
+	environment string
+	version     string
+	hour        int
+	// component defaults to the primary component.
+	component string
+}
+
+// format returns the versions and the pending promotions of the application as strings, so they can be compared at a glance:
+// "1.0.0 dev@0 staging@1 skipped staging->prod-eu" and "1.0.0 staging->prod-eu since 1 deployed [0.9.0]".
+func (f promotionFixture) format(promotions app.ApplicationPromotions) (versions, pending []string) {
+	hours := func(at time.Time) int {
+		return int(at.Sub(promotionStart) / time.Hour)
+	}
+
+	versions = []string{}
+	for _, v := range promotions.Versions {
+		s := v.Version
+		for _, stage := range v.Stages {
+			s += fmt.Sprintf(" %s@%d", f.environmentName(stage.EnvironmentId), hours(stage.EnteredAt))
+		}
+		for _, skipped := range v.Skipped {
+			s += fmt.Sprintf(" skipped %s->%s", f.environmentName(skipped.EnvironmentId), f.environmentName(skipped.ToEnvironmentId))
+		}
+		versions = append(versions, s)
+	}
+
+	pending = []string{}
+	for _, p := range promotions.Pending {
+		pending = append(pending, fmt.Sprintf("%s %s->%s since %d deployed %v",
+			p.Version, f.environmentName(p.FromEnvironmentId), f.environmentName(p.ToEnvironmentId), hours(p.Since), p.Deployed))
+	}
+	return versions, pending
+}
+
+func TestListPromotions(t *testing.T) {
+	tests := []struct {
+		name        string
+		deployments []promotionDeployment
+		// application defaults to api.
+		application  string
+		wantVersions []string
+		wantPending  []string
+	}{
+		{
+			name: "stage entry times",
+			deployments: []promotionDeployment{
+				{application: "api", environment: "dev", version: "1.0.0", hour: 0},
+				{application: "api", environment: "dev", version: "1.1.0", hour: 1},
+				// Rolling back doesn't move when the version entered dev.
+				{application: "api", environment: "dev", version: "1.0.0", hour: 2},
+				{application: "api", environment: "staging", version: "1.0.0", hour: 3},
+				// Only the primary component tracks the versions.
+				{application: "api", environment: "staging", version: "9.9.9", hour: 4, component: "sidecar"},
+			},
+			wantVersions: []string{"1.0.0 dev@0 staging@3", "1.1.0 dev@1"},
+			wantPending: []string{
+				"1.0.0 staging->prod-eu since 3 deployed []",
+				"1.0.0 staging->prod-us since 3 deployed []",
+			},
+		},
+		{
+			name: "pending promotions",
+			deployments: []promotionDeployment{
+				{application: "api", environment: "dev", version: "1.0.0", hour: 0},
+				{application: "api", environment: "staging", version: "1.0.0", hour: 1},
+				{application: "api", environment: "prod-eu", version: "1.0.0", hour: 2},
+				{application: "api", environment: "prod-us", version: "1.0.0", hour: 2},
+				{application: "api", environment: "dev", version: "1.1.0", hour: 3},
+				{application: "api", environment: "staging", version: "1.1.0", hour: 4},
+				{application: "api", environment: "prod-eu", version: "1.1.0", hour: 5},
+				{application: "api", environment: "dev", version: "1.2.0", hour: 6},
+			},
+			wantVersions: []string{
+				"1.2.0 dev@6",
+				"1.1.0 dev@3 staging@4 prod-eu@5",
+				"1.0.0 dev@0 staging@1 prod-eu@2 prod-us@2",
+			},
+			wantPending: []string{
+				"1.1.0 staging->prod-us since 4 deployed [1.0.0]",
+				"1.2.0 dev->staging since 6 deployed [1.1.0]",
+			},
+		},
+		{
+			name: "branching",
+			deployments: []promotionDeployment{
+				{application: "api", environment: "dev", version: "1.0.0", hour: 0},
+				{application: "api", environment: "staging", version: "1.0.0", hour: 1},
+				{application: "api", environment: "prod-us", version: "1.0.0", hour: 2},
+				{application: "api", environment: "dev", version: "2.0.0", hour: 3},
+				{application: "api", environment: "staging", version: "2.0.0", hour: 4},
+				{application: "api", environment: "prod-eu", version: "2.0.0", hour: 5},
+			},
+			wantVersions: []string{
+				"2.0.0 dev@3 staging@4 prod-eu@5",
+				"1.0.0 dev@0 staging@1 prod-us@2",
+			},
+			// prod-eu runs the version of staging, prod-us is still on the previous one.
+			wantPending: []string{"2.0.0 staging->prod-us since 4 deployed [1.0.0]"},
+		},
+		{
+			name: "later version deployed",
+			deployments: []promotionDeployment{
+				{application: "api", environment: "dev", version: "1.0.0", hour: 0},
+				{application: "api", environment: "staging", version: "1.0.0", hour: 1},
+				{application: "api", environment: "prod-eu", version: "2.0.0", hour: 2},
+			},
+			// 1.0.0 is not pending to prod-eu, which runs a later version.
+			wantVersions: []string{
+				"2.0.0 prod-eu@2 skipped staging->prod-eu",
+				"1.0.0 dev@0 staging@1",
+			},
+			wantPending: []string{"1.0.0 staging->prod-us since 1 deployed []"},
+		},
+		{
+			name: "skipped stages",
+			deployments: []promotionDeployment{
+				{application: "api", environment: "dev", version: "1.0.0", hour: 0},
+				{application: "api", environment: "prod-us", version: "1.0.0", hour: 1},
+				{application: "api", environment: "dev", version: "2.0.0", hour: 2},
+				{application: "api", environment: "prod-eu", version: "2.0.0", hour: 3},
+				// Being deployed to staging after prod-eu doesn't make up for it.
+				{application: "api", environment: "staging", version: "2.0.0", hour: 4},
+			},
+			wantVersions: []string{
+				"2.0.0 dev@2 prod-eu@3 staging@4 skipped staging->prod-eu",
+				"1.0.0 dev@0 prod-us@1 skipped staging->prod-us",
+			},
+			wantPending: []string{"2.0.0 staging->prod-us since 4 deployed [1.0.0]"},
+		},
+		{
+			name:        "no instance in the stage",
+			application: "web",
+			deployments: []promotionDeployment{
+				{application: "web", environment: "dev", version: "1.0.0", hour: 0},
+				{application: "web", environment: "prod-eu", version: "1.0.0", hour: 1},
+				{application: "web", environment: "dev", version: "1.1.0", hour: 2},
+			},
+			// web has no instance in staging, so it can't pass through it and has nothing to promote.
+			wantVersions: []string{"1.1.0 dev@2", "1.0.0 dev@0 prod-eu@1"},
+			wantPending:  []string{},
+		},
+		{
+			name: "commit hashes",
+			deployments: []promotionDeployment{
+				{application: "api", environment: "dev", version: "4e1f0c2", hour: 0},
+				{application: "api", environment: "staging", version: "4e1f0c2", hour: 1},
+				{application: "api", environment: "dev", version: "0a9b8c7", hour: 2},
+				{application: "api", environment: "prod-eu", version: "4e1f0c2", hour: 3},
+				{application: "api", environment: "prod-us", version: "4e1f0c2", hour: 3},
+			},
+			// The versions can't be compared, the one deployed to staging before 0a9b8c7 entered dev is earlier.
+			wantVersions: []string{"4e1f0c2 dev@0 staging@1 prod-eu@3 prod-us@3", "0a9b8c7 dev@2"},
+			wantPending:  []string{"0a9b8c7 dev->staging since 2 deployed [4e1f0c2]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPromotionFixture(t)
+			ctx := context.Background()
+
+			for _, d := range tt.deployments {
+				if _, err := f.app.RegisterDeployment(ctx, app.RegisterDeploymentParams{
+					InstanceId: f.instances[[2]string{d.application, d.environment}],
+					Component:  d.component,
+					Version:    d.version,
+					DeployedAt: promotionStart.Add(time.Duration(d.hour) * time.Hour),
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			promotions, err := f.app.ListPromotions(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(promotions) != len(f.applications) {
+				t.Fatalf("expected the promotions of %d applications, got %+v", len(f.applications), promotions)
+			}
+
+			application := tt.application
+			if application == "" {
+				application = "api"
+			}
+			i := slices.IndexFunc(promotions, func(p app.ApplicationPromotions) bool {
+				return p.ApplicationId == f.applications[application]
+			})
+			if i < 0 {
+				t.Fatalf("expected the promotions of %s, got %+v", application, promotions)
+			}
+
+			versions, pending := f.format(promotions[i])
+			if !slices.Equal(versions, tt.wantVersions) {
+				t.Fatalf("expected the versions\n%s\ngot\n%s", strings.Join(tt.wantVersions, "\n"), strings.Join(versions, "\n"))
+			}
+			if !slices.Equal(pending, tt.wantPending) {
+				t.Fatalf("expected the pending promotions\n%s\ngot\n%s", strings.Join(tt.wantPending, "\n"), strings.Join(pending, "\n"))
+			}
+		})
+	}
+}
+
+func TestSetPromotionSources(t *testing.T) {
+	f := newPromotionFixture(t)
+	ctx := context.Background()
+	env := f.environments
+
+	tests := []struct {
+		name          string
+		environmentId int32
+		sourceIds     []int32
+		// err is a substring of the expected error, empty if the sources are set.
+		err    string
+		target error
+	}{
+		{name: "cycle", environmentId: env["dev"], sourceIds: []int32{env["prod-eu"]}, err: "would create a cycle"},
+		{name: "cycle through a branch", environmentId: env["staging"], sourceIds: []int32{env["dev"], env["prod-us"]}, err: "would create a cycle"},
+		{name: "direct cycle", environmentId: env["dev"], sourceIds: []int32{env["staging"]}, err: "would create a cycle"},
+		{name: "itself", environmentId: env["staging"], sourceIds: []int32{env["staging"]}, err: "promoted from itself"},
+		{name: "unknown source", environmentId: env["staging"], sourceIds: []int32{9999}, target: app.ErrNotFound},
+		{name: "unknown environment", environmentId: 9999, sourceIds: []int32{env["dev"]}, target: app.ErrNotFound},
+		{name: "no environment", sourceIds: []int32{env["dev"]}, err: "environment id is required"},
+		// Promoting between the branches is fine, as long as it doesn't go back.
+		{name: "between branches", environmentId: env["prod-us"], sourceIds: []int32{env["staging"], env["prod-eu"], env["staging"]}},
+		{name: "back between branches", environmentId: env["prod-eu"], sourceIds: []int32{env["prod-us"]}, err: "would create a cycle"},
+		// Replacing the sources of staging removes the edge closing the cycle.
+		{name: "replaced sources", environmentId: env["staging"], sourceIds: []int32{}},
+		{name: "first stage", environmentId: env["dev"], sourceIds: []int32{env["staging"]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.app.SetPromotionSources(ctx, tt.environmentId, tt.sourceIds)
+			switch {
+			case tt.target != nil:
+				if !errors.Is(err, tt.target) {
+					t.Fatalf("expected an error matching %v, got %v", tt.target, err)
+				}
+			case tt.err != "":
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+				}
+			case err != nil:
+				t.Fatal(err)
+			}
+		})
+	}
+
+	edges, err := f.app.ListPromotionEdges(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range edges {
+		got = append(got, f.environmentName(e.FromEnvironmentId)+"->"+f.environmentName(e.ToEnvironmentId))
+	}
+	// The rejected changes left the graph as it was, the duplicate source was added once.
+	want := []string{"staging->dev", "staging->prod-eu", "staging->prod-us", "prod-eu->prod-us"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected the edges %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,194 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"overseer/datasource"
+	"strings"
+	"time"
+)
+
+// Release is the latest version of an application available in its image repository.
+type Release struct {
+	ApplicationId int32 `json:"application_id"`
+	// Repository is the image repository the version was found in, like registry.example.com/team/api.
+	Repository string `json:"repository"`
+	Version    string `json:"version"`
+	// CheckedAt is when the tags of the repository were last listed.
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// InstanceRelease compares the version deployed to an instance with the latest release of its application.
+type InstanceRelease struct {
+	InstanceId    int32 `json:"instance_id"`
+	ApplicationId int32 `json:"application_id"`
+	// Deployed is the current version of the primary component, empty if nothing is deployed.
+	Deployed string `json:"deployed"`
+	// Available is the latest release of the application, empty if it is unknown.
+	Available string `json:"available"`
+	// UpdateAvailable is set if Available is a later version than Deployed.
+	// It is never set if either of them is not a version, like latest or a commit hash.
+	UpdateAvailable bool `json:"update_available"`
+}
+
+// TagLister lists the tags of an image repository, like an OCI registry.
+type TagLister interface {
+	ListTags(ctx context.Context, repository string) ([]string, error)
+}
+
+func (a *App) ListReleases(ctx context.Context) ([]Release, error) {
+	return a.store.ListReleases(ctx)
+}
+
+// ListInstanceReleases compares the version deployed to every instance with the latest release of its application.
+func (a *App) ListInstanceReleases(ctx context.Context) ([]InstanceRelease, error) {
+	instances, err := a.store.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	releases, err := a.store.ListReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	available := map[int32]string{}
+	for _, r := range releases {
+		available[r.ApplicationId] = r.Version
+	}
+
+	result := make([]InstanceRelease, 0, len(instances))
+	for _, i := range instances {
+		r := InstanceRelease{
+			InstanceId:    i.Instance.Id,
+			ApplicationId: i.Instance.ApplicationId,
+			Available:     available[i.Instance.ApplicationId],
+		}
+		if i.Deployment != nil {
+			r.Deployed = i.Deployment.Version
+		}
+		if r.Deployed != "" && r.Available != "" {
+			c, ok := compareVersions(r.Available, r.Deployed)
+			r.UpdateAvailable = ok && c > 0
+		}
+		result = append(result, r)
+	}
+
+	return result, nil
+}
+
+// DiscoverReleases lists the tags of the image repository of every application and stores its latest release.
+//
+// The repository of an application is taken from Options.Repositories, or otherwise from the image
+// of its most recently deployed instance. Applications without a repository are skipped.
+func (a *App) DiscoverReleases(ctx context.Context, lister TagLister) error {
+	repositories, err := a.repositories(ctx)
+	if err != nil {
+		return err
+	}
+
+	releases, err := a.store.ListReleases(ctx)
+	if err != nil {
+		return err
+	}
+
+	previous := map[int32]string{}
+	for _, r := range releases {
+		previous[r.ApplicationId] = r.Version
+	}
+
+	var errs []error
+	for appId, repository := range repositories {
+		tags, err := lister.ListTags(ctx, repository)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("listing the tags of %s: %w", repository, err))
+			continue
+		}
+
+		latest := latestRelease(tags)
+		if latest == "" {
+			slog.Warn("no release found in the image repository", "repository", repository, "tags", len(tags))
+			continue
+		}
+
+		release := Release{
+			ApplicationId: appId,
+			Repository:    repository,
+			Version:       latest,
+			CheckedAt:     time.Now().UTC(),
+		}
+		err = a.store.SaveRelease(ctx, release)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) { // The application was deleted in the meantime.
+				errs = append(errs, fmt.Errorf("saving the release of %s: %w", repository, err))
+			}
+			continue
+		}
+
+		if latest != previous[appId] {
+			a.publish(ctx, Event{Type: EventRelease, Release: &release}, 0, appId)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// repositories returns the image repository of every application that has one.
+func (a *App) repositories(ctx context.Context) (map[int32]string, error) {
+	apps, err := a.store.ListApplications(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := a.store.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The images of the most recent deployments of every application.
+	images := map[int32]Deployment{}
+	for _, i := range instances {
+		d := i.Deployment
+		if d == nil || d.Metadata.Image == "" {
+			continue
+		}
+		if cur, ok := images[i.Instance.ApplicationId]; !ok || d.OrderedAt().After(cur.OrderedAt()) {
+			images[i.Instance.ApplicationId] = *d
+		}
+	}
+
+	repositories := map[int32]string{}
+	for _, application := range apps {
+		if repository, ok := a.repositoryOverrides[application.Name]; ok {
+			repositories[application.Id] = repository
+		} else if d, ok := images[application.Id]; ok {
+			image, tag, _ := datasource.ParseImage(d.Metadata.Image)
+			repositories[application.Id] = strings.TrimSuffix(image, ":"+tag)
+		}
+	}
+	return repositories, nil
+}
+
+// RunReleaseDiscovery discovers the releases of the applications every interval until the context is canceled.
+func (a *App) RunReleaseDiscovery(ctx context.Context, lister TagLister, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid release discovery interval %s", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.DiscoverReleases(ctx, lister); err != nil {
+			slog.Error("discovering the releases", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
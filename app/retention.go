@@ -0,0 +1,205 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// RetentionPolicy decides which deployments in the history of an environment are pruned.
+// The current deployment of an instance is always kept.
+type RetentionPolicy struct {
+	// MaxAge is how long deployments are kept, zero keeps them forever.
+	MaxAge time.Duration
+	// Compact prunes redeployments of the version that was already deployed,
+	// keeping only the deployments where the version changed.
+	Compact bool
+}
+
+func (p RetentionPolicy) IsZero() bool {
+	return p.MaxAge == 0 && !p.Compact
+}
+
+// RetentionPolicies holds the retention policy of each environment.
+type RetentionPolicies struct {
+	// Default applies to the environments without a policy of their own.
+	Default RetentionPolicy
+	// Environments maps environment names to their policies.
+	Environments map[string]RetentionPolicy
+}
+
+func (p RetentionPolicies) For(environment string) RetentionPolicy {
+	if policy, ok := p.Environments[environment]; ok {
+		return policy
+	}
+	return p.Default
+}
+
+// IsZero reports whether the policies keep every deployment forever.
+func (p RetentionPolicies) IsZero() bool {
+	if !p.Default.IsZero() {
+		return false
+	}
+	for _, policy := range p.Environments {
+		if !policy.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	PruneReasonExpired   = "expired"
+	PruneReasonCompacted = "compacted"
+)
+
+type PrunedDeployment struct {
+	Deployment
+	Environment string `json:"environment"`
+	// Reason is either PruneReasonExpired or PruneReasonCompacted.
+	Reason string `json:"reason"`
+}
+
+type PrunePlan struct {
+	// Total is the number of deployments in the history.
+	Total int `json:"total"`
+	// Pruned are the deployments that are deleted by the policies.
+	Pruned []PrunedDeployment `json:"pruned"`
+}
+
+// PlanPrune returns the deployments that pruning the history at the given time would delete, without deleting them.
+func (a *App) PlanPrune(ctx context.Context, now time.Time) (PrunePlan, error) {
+	var plan PrunePlan
+	total, err := a.planPrune(ctx, now, func(pruned []PrunedDeployment) error {
+		plan.Pruned = append(plan.Pruned, pruned...)
+		return nil
+	})
+	plan.Total = total
+	return plan, err
+}
+
+// Prune deletes the deployments returned by PlanPrune and returns how many were deleted.
+func (a *App) Prune(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	_, err := a.planPrune(ctx, now, func(pruned []PrunedDeployment) error {
+		ids := make([]string, 0, len(pruned))
+		for _, d := range pruned {
+			ids = append(ids, d.Id)
+		}
+
+		n, err := a.store.DeleteDeployments(ctx, ids)
+		deleted += n
+		return err
+	})
+	return deleted, err
+}
+
+// planPrune walks the history one instance at a time, so that it is never loaded all at once, and calls prune with
+// the deployments of every instance the policies prune, if there are any. It returns the number of deployments in the history.
+func (a *App) planPrune(ctx context.Context, now time.Time, prune func([]PrunedDeployment) error) (int, error) {
+	envs, err := a.store.ListEnvironments(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	envNames := map[int32]string{}
+	for _, e := range envs {
+		envNames[e.Id] = e.Name
+	}
+
+	instances, err := a.store.ListInstances(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+
+	currentDeployments, err := a.store.ListCurrentDeployments(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	current := map[string]bool{}
+	for _, d := range currentDeployments {
+		current[d.Id] = true
+	}
+
+	total := 0
+	for _, instance := range instances {
+		deployments, err := a.store.ListInstanceDeployments(ctx, instance.Id)
+		if err != nil {
+			return total, err
+		}
+		total += len(deployments)
+
+		env := envNames[instance.EnvironmentId]
+		policy := a.retention.For(env)
+		if policy.IsZero() {
+			continue
+		}
+
+		// The deployments are ordered by component and time,
+		// kept is the last one of the same component that is not pruned.
+		var pruned []PrunedDeployment
+		var kept *Deployment
+		for _, d := range deployments {
+			if kept != nil && kept.Component != d.Component {
+				kept = nil
+			}
+
+			if current[d.Id] {
+				kept = &d
+				continue
+			}
+
+			var reason string
+			switch {
+			case policy.MaxAge > 0 && d.DeployedAt.Before(now.Add(-policy.MaxAge)):
+				reason = PruneReasonExpired
+			case policy.Compact && kept != nil && kept.Version == d.Version:
+				reason = PruneReasonCompacted
+			default:
+				kept = &d
+				continue
+			}
+
+			pruned = append(pruned, PrunedDeployment{
+				Deployment:  d,
+				Environment: env,
+				Reason:      reason,
+			})
+		}
+
+		if len(pruned) > 0 {
+			if err := prune(pruned); err != nil {
+				return total, err
+			}
+		}
+	}
+
+	return total, nil
+}
+
+// RunPruner prunes the deployment history every interval until the context is canceled.
+func (a *App) RunPruner(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid prune interval %s", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := a.Prune(ctx, time.Now())
+		if err != nil {
+			slog.Error("pruning the deployment history", "error", err)
+		} else if deleted > 0 {
+			slog.Info("pruned the deployment history", "deleted", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
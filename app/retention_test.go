@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// historyStore serves the deployment history one instance at a time, the whole history can't be listed.
+type historyStore struct {
+	Store
+	instances   []Instance
+	deployments map[int32][]Deployment
+	// deleted are the ids of the deployments deleted by every call of DeleteDeployments.
+	deleted [][]string
+}
+
+func (s *historyStore) ListEnvironments(ctx context.Context) ([]Environment, error) {
+	return []Environment{{Id: 1, Name: "dev"}, {Id: 2, Name: "prod"}}, nil
+}
+
+func (s *historyStore) ListInstances(ctx context.Context, name string) ([]Instance, error) {
+	return s.instances, nil
+}
+
+func (s *historyStore) ListCurrentDeployments(ctx context.Context) ([]Deployment, error) {
+	var current []Deployment
+	for _, deployments := range s.deployments {
+		current = append(current, deployments[len(deployments)-1])
+	}
+	return current, nil
+}
+
+func (s *historyStore) ListInstanceDeployments(ctx context.Context, instanceId int32) ([]Deployment, error) {
+	return s.deployments[instanceId], nil
+}
+
+func (s *historyStore) DeleteDeployments(ctx context.Context, ids []string) (int64, error) {
+	s.deleted = append(s.deleted, ids)
+	return int64(len(ids)), nil
+}
+
+func TestPruneByInstance(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	history := func(instanceId int32, ids ...string) []Deployment {
+		var deployments []Deployment
+		for i, id := range ids {
+			deployments = append(deployments, Deployment{Id: id, InstanceId: instanceId, Component: DefaultComponent, Version: id, DeployedAt: now.AddDate(0, 0, i-len(ids))})
+		}
+		return deployments
+	}
+
+	store := &historyStore{
+		instances: []Instance{{Id: 1, EnvironmentId: 1}, {Id: 2, EnvironmentId: 2}, {Id: 3, EnvironmentId: 1}},
+		deployments: map[int32][]Deployment{
+			1: history(1, "a1", "a2", "a3"),
+			2: history(2, "b1", "b2"),
+			3: history(3, "c1", "c2"),
+		},
+	}
+	a := New(store, Options{Retention: RetentionPolicies{Environments: map[string]RetentionPolicy{"dev": {MaxAge: time.Hour}}}})
+
+	plan, err := a.PlanPrune(context.Background(), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Total != 7 || len(plan.Pruned) != 3 {
+		t.Fatalf("expected 3 of the 7 deployments to be pruned, got %+v", plan)
+	}
+
+	deleted, err := a.Prune(context.Background(), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// prod has no policy, the deployments of the dev instances are deleted one instance at a time.
+	want := [][]string{{"a1", "a2"}, {"c1"}}
+	if deleted != 3 || !slices.EqualFunc(store.deleted, want, slices.Equal) {
+		t.Fatalf("expected the deletions %v, got %v (%d deleted)", want, store.deleted, deleted)
+	}
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"overseer/datasource"
+	"slices"
+	"time"
+)
+
+// Statuses of a rollout, the sources report them with the same values.
+const (
+	RolloutPending    = datasource.RolloutPending
+	RolloutRunning    = datasource.RolloutRunning
+	RolloutPaused     = datasource.RolloutPaused
+	RolloutSuccessful = datasource.RolloutSuccessful
+	RolloutFailed     = datasource.RolloutFailed
+	RolloutCancelled  = datasource.RolloutCancelled
+)
+
+var rolloutStatuses = []string{RolloutPending, RolloutRunning, RolloutPaused, RolloutSuccessful, RolloutFailed, RolloutCancelled}
+
+// Rollout is the progress of rolling out a version of an instance component.
+// The current deployment is the desired version, the rollout tells whether it is actually running.
+type Rollout struct {
+	InstanceId int32  `json:"instance_id"`
+	Component  string `json:"component"`
+	// Version is the version being rolled out.
+	Version string `json:"version"`
+	// Status is one of the Rollout statuses.
+	Status string `json:"status"`
+	// Description is a human readable explanation of the status from the source, like why it failed.
+	Description string `json:"description,omitempty"`
+	// Healthy and Desired are the number of healthy and desired replicas, both zero if unknown.
+	Healthy int32 `json:"healthy"`
+	Desired int32 `json:"desired"`
+	// RunningVersion is the last version that was successfully rolled out, empty if unknown.
+	RunningVersion string    `json:"running_version,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	// Sequence is a monotonic number supplied by the source, or zero if it has none.
+	Sequence int64 `json:"sequence,omitempty"`
+	// Source is the name of the source that reported the rollout, empty if it was reported through the API.
+	Source string `json:"source,omitempty"`
+}
+
+// Supersedes reports whether r is ordered after, or at the same position as, other.
+// Rollouts are ordered like deployments, by their sequence if both have one and they are of the same source,
+// otherwise by UpdatedAt.
+func (r Rollout) Supersedes(other Rollout) bool {
+	if r.Sequence != 0 && other.Sequence != 0 && r.Source == other.Source {
+		return r.Sequence >= other.Sequence
+	}
+	return !r.UpdatedAt.Before(other.UpdatedAt)
+}
+
+func (a *App) ListRollouts(ctx context.Context) ([]Rollout, error) {
+	return a.store.ListRollouts(ctx)
+}
+
+type UpdateRolloutParams struct {
+	InstanceId int32
+	// Component defaults to the primary component of the instance.
+	Component   string
+	Version     string
+	Status      string
+	Description string
+	Healthy     int32
+	Desired     int32
+	UpdatedAt   time.Time
+	Sequence    int64
+	Source      string
+}
+
+// UpdateRollout records the progress of a rollout, unless a rollout ordered after it was already recorded.
+// A successful rollout makes its version the running version of the component.
+func (a *App) UpdateRollout(ctx context.Context, params UpdateRolloutParams) (Rollout, error) {
+	if params.InstanceId == 0 {
+		return Rollout{}, invalid("instance id is required")
+	}
+
+	if params.Version == "" {
+		return Rollout{}, invalid("version is required")
+	}
+
+	if !slices.Contains(rolloutStatuses, params.Status) {
+		return Rollout{}, invalid("invalid rollout status %q", params.Status)
+	}
+
+	if params.Healthy < 0 || params.Desired < 0 {
+		return Rollout{}, invalid("replica counts can not be negative")
+	}
+
+	if params.Component == "" {
+		instance, err := a.store.GetInstance(ctx, params.InstanceId)
+		if err != nil {
+			return Rollout{}, err
+		}
+		params.Component = instance.PrimaryComponent
+	}
+
+	if params.UpdatedAt.IsZero() {
+		params.UpdatedAt = time.Now().UTC()
+	}
+
+	r := Rollout{
+		InstanceId:  params.InstanceId,
+		Component:   params.Component,
+		Version:     params.Version,
+		Status:      params.Status,
+		Description: params.Description,
+		Healthy:     params.Healthy,
+		Desired:     params.Desired,
+		UpdatedAt:   params.UpdatedAt,
+		Sequence:    params.Sequence,
+		Source:      params.Source,
+	}
+	if r.Status == RolloutSuccessful {
+		r.RunningVersion = r.Version
+	}
+
+	updated, err := a.store.UpdateRollout(ctx, r)
+	if err != nil {
+		return Rollout{}, err
+	}
+	if !updated {
+		slog.Warn("late rollout update, a later one was already recorded", "instance", r.InstanceId, "component", r.Component, "version", r.Version, "status", r.Status)
+	} else {
+		a.publish(ctx, Event{Type: EventRollout, Rollout: &r}, r.InstanceId, 0)
+	}
+
+	return r, nil
+}
+
+// rolloutsOf returns the rollouts of the components of every instance.
+func (a *App) rolloutsOf(ctx context.Context) (map[componentKey]Rollout, error) {
+	rollouts, err := a.store.ListRollouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byComponent := make(map[componentKey]Rollout, len(rollouts))
+	for _, r := range rollouts {
+		byComponent[componentKey{r.InstanceId, r.Component}] = r
+	}
+	return byComponent, nil
+}
+
+type componentKey struct {
+	instanceId int32
+	component  string
+}
@@ -0,0 +1,316 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SourceStatus is the state of a source of deployment events.
+type SourceStatus struct {
+	Name string `json:"name"`
+	// Type is the kind of source, like nomad or docker.
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+	// Running is set while the events of the source are streamed.
+	Running bool `json:"running"`
+	// Error is why the source last stopped, it is restarted after a delay.
+	Error string `json:"error,omitempty"`
+	// StartedAt is when the source was last started.
+	StartedAt time.Time `json:"started_at,omitzero"`
+	// Events is the number of events received since the server started.
+	Events      int64     `json:"events"`
+	LastEventAt time.Time `json:"last_event_at,omitzero"`
+}
+
+type AddSourceParams struct {
+	// Name identifies the source, the deployments it reports are registered with it.
+	Name   string
+	Type   string
+	Source EventSource
+	// Disabled sources are not started until they are enabled.
+	Disabled bool
+}
+
+// sourceRegistry runs the sources of deployment events, each on its own
+// so that a failing source doesn't stop the others.
+type sourceRegistry struct {
+	mu      sync.Mutex
+	sources []*registeredSource
+	// ctx is the context of RunSources, nil before it is called.
+	ctx context.Context
+	wg  sync.WaitGroup
+	// restartDelay is how long a failed source waits before it is started again.
+	restartDelay time.Duration
+}
+
+type registeredSource struct {
+	source EventSource
+
+	// op serializes enabling, disabling and restarting the source.
+	op sync.Mutex
+
+	// The fields below are guarded by the mutex of the registry.
+	status SourceStatus
+	// stop stops the source and waits for it to stop, nil if it wasn't started.
+	stop func()
+}
+
+// AddSource registers a source of deployment events, it is started by RunSources unless it is disabled.
+func (a *App) AddSource(params AddSourceParams) error {
+	if params.Name == "" {
+		return invalid("the source name is required")
+	}
+
+	r := a.sources
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.sources {
+		if s.status.Name == params.Name {
+			return fmt.Errorf("source %s: %w", params.Name, ErrAlreadyExists)
+		}
+	}
+
+	s := &registeredSource{
+		source: params.Source,
+		status: SourceStatus{
+			Name:    params.Name,
+			Type:    params.Type,
+			Enabled: !params.Disabled,
+		},
+	}
+	r.sources = append(r.sources, s)
+
+	if r.ctx != nil && s.status.Enabled {
+		a.startSource(s)
+	}
+	return nil
+}
+
+// RunSources streams the events of the enabled sources until the context is canceled,
+// restarting the sources that fail.
+func (a *App) RunSources(ctx context.Context) error {
+	r := a.sources
+	r.mu.Lock()
+	if r.ctx != nil {
+		r.mu.Unlock()
+		return errors.New("the sources are already running")
+	}
+	r.ctx = ctx
+
+	for _, s := range r.sources {
+		if s.status.Enabled {
+			a.startSource(s)
+		}
+	}
+	r.mu.Unlock()
+
+	<-ctx.Done()
+	r.wg.Wait()
+	return nil
+}
+
+func (a *App) ListSources(ctx context.Context) ([]SourceStatus, error) {
+	r := a.sources
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make([]SourceStatus, 0, len(r.sources))
+	for _, s := range r.sources {
+		statuses = append(statuses, s.status)
+	}
+	return statuses, nil
+}
+
+// EnableSource starts a disabled source, if the sources are running.
+func (a *App) EnableSource(ctx context.Context, name string) (SourceStatus, error) {
+	s, err := a.source(name)
+	if err != nil {
+		return SourceStatus{}, err
+	}
+
+	s.op.Lock()
+	defer s.op.Unlock()
+
+	r := a.sources
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !s.status.Enabled {
+		s.status.Enabled = true
+		if r.ctx != nil {
+			a.startSource(s)
+		}
+		slog.Info("enabled source", "source", name)
+	}
+	return s.status, nil
+}
+
+// DisableSource stops a source until it is enabled again.
+func (a *App) DisableSource(ctx context.Context, name string) (SourceStatus, error) {
+	s, err := a.source(name)
+	if err != nil {
+		return SourceStatus{}, err
+	}
+
+	s.op.Lock()
+	defer s.op.Unlock()
+
+	a.stopSource(s)
+
+	r := a.sources
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s.status.Enabled {
+		s.status.Enabled = false
+		slog.Info("disabled source", "source", name)
+	}
+	return s.status, nil
+}
+
+// RestartSource stops an enabled source and starts it again, like to reconnect to it right away.
+func (a *App) RestartSource(ctx context.Context, name string) (SourceStatus, error) {
+	s, err := a.source(name)
+	if err != nil {
+		return SourceStatus{}, err
+	}
+
+	s.op.Lock()
+	defer s.op.Unlock()
+
+	r := a.sources
+	r.mu.Lock()
+	enabled := s.status.Enabled
+	r.mu.Unlock()
+
+	if !enabled {
+		return SourceStatus{}, fmt.Errorf("source %s is disabled", name)
+	}
+
+	a.stopSource(s)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ctx != nil {
+		a.startSource(s)
+	}
+	slog.Info("restarted source", "source", name)
+	return s.status, nil
+}
+
+func (a *App) source(name string) (*registeredSource, error) {
+	r := a.sources
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.sources {
+		if s.status.Name == name {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("source %s: %w", name, ErrNotFound)
+}
+
+// startSource runs the source until it is stopped or the sources stop.
+// It must be called with the registry locked.
+func (a *App) startSource(s *registeredSource) {
+	r := a.sources
+	ctx, cancel := context.WithCancel(r.ctx)
+	done := make(chan struct{})
+
+	s.stop = func() {
+		cancel()
+		<-done
+	}
+
+	r.wg.Go(func() {
+		defer close(done)
+
+		for {
+			err := a.streamSource(ctx, s)
+			if ctx.Err() != nil {
+				return
+			}
+
+			slog.Error("source failed, restarting it", "source", s.status.Name, "error", err, "delay", r.restartDelay)
+
+			r.mu.Lock()
+			s.status.Error = err.Error()
+			r.mu.Unlock()
+
+			select {
+			case <-time.After(r.restartDelay):
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// stopSource stops the source if it was started and waits for it to stop.
+func (a *App) stopSource(s *registeredSource) {
+	r := a.sources
+	r.mu.Lock()
+	stop := s.stop
+	s.stop = nil
+	r.mu.Unlock()
+
+	if stop != nil {
+		stop()
+	}
+}
+
+// streamSource handles the events of the source until its stream ends.
+func (a *App) streamSource(ctx context.Context, s *registeredSource) (err error) {
+	r := a.sources
+
+	// The stream is stopped when this returns, even if the handling panics.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+
+		r.mu.Lock()
+		s.status.Running = false
+		r.mu.Unlock()
+	}()
+
+	stream, err := s.source.StreamEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("starting the event stream: %w", err)
+	}
+
+	r.mu.Lock()
+	s.status.Running = true
+	s.status.StartedAt = time.Now()
+	s.status.Error = ""
+	name := s.status.Name
+	r.mu.Unlock()
+
+	for event := range stream {
+		r.mu.Lock()
+		s.status.Events++
+		s.status.LastEventAt = time.Now()
+		r.mu.Unlock()
+
+		event.Source = name
+		a.handleEvent(ctx, event)
+		if event.Ack != nil {
+			event.Ack()
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return errors.New("the event stream ended")
+}
@@ -0,0 +1,352 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"overseer/app"
+	"overseer/datasource"
+)
+
+// testSource streams the events returned by stream, given the number of times the source was started.
+type testSource struct {
+	stream func(ctx context.Context, start int) (<-chan datasource.Event, error)
+
+	mu     sync.Mutex
+	starts int
+}
+
+func (s *testSource) StreamEvents(ctx context.Context) (<-chan datasource.Event, error) {
+	s.mu.Lock()
+	s.starts++
+	start := s.starts
+	s.mu.Unlock()
+
+	return s.stream(ctx, start)
+}
+
+func (s *testSource) started() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.starts
+}
+
+// streamEvents returns a stream of the events which is closed when the context is canceled.
+func streamEvents(ctx context.Context, events ...datasource.Event) <-chan datasource.Event {
+	stream := make(chan datasource.Event)
+	go func() {
+		defer close(stream)
+		for _, e := range events {
+			select {
+			case stream <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+		<-ctx.Done()
+	}()
+	return stream
+}
+
+// runSources runs the sources of the app until the test ends.
+func runSources(t *testing.T, a *app.App) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- a.RunSources(ctx) }()
+
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("running the sources: %v", err)
+		}
+	})
+}
+
+// waitForSource waits until the status of the source satisfies the condition.
+func waitForSource(t *testing.T, a *app.App, name string, condition func(app.SourceStatus) bool) app.SourceStatus {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		statuses, err := a.ListSources(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range statuses {
+			if s.Name == name && condition(s) {
+				return s
+			}
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the source %s, got %+v", name, statuses)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func running(s app.SourceStatus) bool { return s.Running }
+
+// waitForDeployment waits until an event is registered as the deployment of the primary component of the instance.
+func waitForDeployment(t *testing.T, a *app.App, instance int32) app.Deployment {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		components, err := a.ListComponents(context.Background(), instance)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(components) > 0 {
+			return components[0]
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for a deployment of the instance %d", instance)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFailingSources(t *testing.T) {
+	tests := []struct {
+		name      string
+		stream    func(ctx context.Context, start int) (<-chan datasource.Event, error)
+		wantError string
+	}{
+		{
+			name: "error",
+			stream: func(ctx context.Context, start int) (<-chan datasource.Event, error) {
+				return nil, errors.New("connection refused")
+			},
+			wantError: "starting the event stream: connection refused",
+		},
+		{
+			name: "ended stream",
+			stream: func(ctx context.Context, start int) (<-chan datasource.Event, error) {
+				stream := make(chan datasource.Event)
+				close(stream)
+				return stream, nil
+			},
+			wantError: "the event stream ended",
+		},
+		{
+			name: "panic",
+			stream: func(ctx context.Context, start int) (<-chan datasource.Event, error) {
+				panic("boom")
+			},
+			wantError: "panic: boom",
+		},
+		{
+			name: "panic handling an event",
+			stream: func(ctx context.Context, start int) (<-chan datasource.Event, error) {
+				return streamEvents(ctx, datasource.Event{Id: "1", DeploymentName: "other", Version: "1.0.0", Ack: func() { panic("boom") }}), nil
+			},
+			wantError: "panic: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, app.Options{SourceRestartDelay: 10 * time.Millisecond})
+			ctx := context.Background()
+
+			// The mock source deploys to env1-app-1.
+			appl, err := f.app.CreateApplication(ctx, "app")
+			if err != nil {
+				t.Fatal(err)
+			}
+			instance, err := f.app.CreateInstance(ctx, app.CreateInstanceParameters{EnvironmentId: f.env.Id, ApplicationId: appl.Id, Name: "env1-app-1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			failing := &testSource{stream: tt.stream}
+			if err := f.app.AddSource(app.AddSourceParams{Name: "failing", Type: "test", Source: failing}); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.app.AddSource(app.AddSourceParams{Name: "mock", Type: "mock", Source: &datasource.MockSource{}}); err != nil {
+				t.Fatal(err)
+			}
+			runSources(t, f.app)
+
+			// The failing source is restarted after every failure.
+			status := waitForSource(t, f.app, "failing", func(s app.SourceStatus) bool { return s.Error != "" && failing.started() >= 3 })
+			if status.Error != tt.wantError || status.Running || !status.Enabled {
+				t.Fatalf("expected the enabled source to have failed with %q, got %+v", tt.wantError, status)
+			}
+
+			// The other source is unaffected.
+			status = waitForSource(t, f.app, "mock", func(s app.SourceStatus) bool { return s.Events == 1 })
+			if !status.Running || status.Error != "" || status.StartedAt.IsZero() || status.LastEventAt.IsZero() {
+				t.Fatalf("expected the mock source to be running, got %+v", status)
+			}
+			if d := waitForDeployment(t, f.app, instance); d.Version != "1.0.0" || d.Source != "mock" {
+				t.Fatalf("expected the deployment of the mock source, got %+v", d)
+			}
+		})
+	}
+}
+
+func TestSourceRecovers(t *testing.T) {
+	f := newFixture(t, app.Options{SourceRestartDelay: 10 * time.Millisecond})
+
+	// The source fails twice, then streams.
+	source := &testSource{stream: func(ctx context.Context, start int) (<-chan datasource.Event, error) {
+		switch start {
+		case 1:
+			return nil, errors.New("connection refused")
+		case 2:
+			panic("boom")
+		}
+		return streamEvents(ctx, datasource.Event{Id: "1", DeploymentName: "api-prod", Version: "1.0.0", DeployedAt: time.Now()}), nil
+	}}
+	if err := f.app.AddSource(app.AddSourceParams{Name: "flaky", Type: "test", Source: source}); err != nil {
+		t.Fatal(err)
+	}
+	runSources(t, f.app)
+
+	status := waitForSource(t, f.app, "flaky", func(s app.SourceStatus) bool { return s.Events == 1 })
+	if !status.Running || status.Error != "" || source.started() != 3 {
+		t.Fatalf("expected the source to be running after 3 starts without an error, got %+v after %d starts", status, source.started())
+	}
+	if d := waitForDeployment(t, f.app, f.instance); d.Version != "1.0.0" || d.Source != "flaky" {
+		t.Fatalf("expected the deployment of the source, got %+v", d)
+	}
+}
+
+func TestDisableSource(t *testing.T) {
+	f := newFixture(t, app.Options{SourceRestartDelay: 10 * time.Millisecond})
+	ctx := context.Background()
+
+	streaming := &testSource{stream: func(ctx context.Context, start int) (<-chan datasource.Event, error) {
+		return streamEvents(ctx), nil
+	}}
+	failing := &testSource{stream: func(ctx context.Context, start int) (<-chan datasource.Event, error) {
+		return nil, errors.New("connection refused")
+	}}
+	disabled := &testSource{stream: streaming.stream}
+
+	for _, params := range []app.AddSourceParams{
+		{Name: "streaming", Source: streaming},
+		{Name: "failing", Source: failing},
+		{Name: "disabled", Source: disabled, Disabled: true},
+	} {
+		if err := f.app.AddSource(params); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runSources(t, f.app)
+
+	waitForSource(t, f.app, "streaming", running)
+	waitForSource(t, f.app, "failing", func(s app.SourceStatus) bool { return failing.started() >= 2 })
+
+	// Disabling stops the source right away, and stops restarting a failing source.
+	for _, name := range []string{"streaming", "failing"} {
+		status, err := f.app.DisableSource(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Enabled || status.Running {
+			t.Fatalf("expected %s to be disabled and stopped, got %+v", name, status)
+		}
+	}
+	starts := failing.started()
+	time.Sleep(50 * time.Millisecond)
+	if failing.started() != starts {
+		t.Fatalf("expected the disabled source not to be restarted, started %d times after %d", failing.started(), starts)
+	}
+
+	// Disabling twice does nothing, and a disabled source can't be restarted.
+	if status, err := f.app.DisableSource(ctx, "streaming"); err != nil || status.Enabled {
+		t.Fatalf("expected disabling a disabled source to succeed, got %+v and %v", status, err)
+	}
+	if _, err := f.app.RestartSource(ctx, "streaming"); err == nil {
+		t.Fatal("expected an error restarting a disabled source")
+	}
+
+	// Enabling starts the source again, and the sources added disabled.
+	for _, name := range []string{"streaming", "disabled"} {
+		if status, err := f.app.EnableSource(ctx, name); err != nil || !status.Enabled {
+			t.Fatalf("expected %s to be enabled, got %+v and %v", name, status, err)
+		}
+		waitForSource(t, f.app, name, running)
+	}
+	if streaming.started() != 2 || disabled.started() != 1 {
+		t.Fatalf("expected the sources to be started 2 and 1 times, got %d and %d", streaming.started(), disabled.started())
+	}
+}
+
+func TestRestartSource(t *testing.T) {
+	f := newFixture(t, app.Options{})
+	ctx := context.Background()
+
+	source := &testSource{stream: func(ctx context.Context, start int) (<-chan datasource.Event, error) {
+		return streamEvents(ctx), nil
+	}}
+	if err := f.app.AddSource(app.AddSourceParams{Name: "source", Source: source}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restarting before the sources run doesn't start the source.
+	if _, err := f.app.RestartSource(ctx, "source"); err != nil {
+		t.Fatal(err)
+	}
+	if source.started() != 0 {
+		t.Fatalf("expected the source not to be started before the sources run, started %d times", source.started())
+	}
+
+	runSources(t, f.app)
+	first := waitForSource(t, f.app, "source", running)
+
+	if _, err := f.app.RestartSource(ctx, "source"); err != nil {
+		t.Fatal(err)
+	}
+	status := waitForSource(t, f.app, "source", func(s app.SourceStatus) bool { return s.Running && source.started() == 2 })
+	if status.StartedAt.Before(first.StartedAt) {
+		t.Fatalf("expected the source to be started again after %s, got %s", first.StartedAt, status.StartedAt)
+	}
+
+	// Sources added while the sources run are started.
+	added := &testSource{stream: source.stream}
+	if err := f.app.AddSource(app.AddSourceParams{Name: "added", Source: added}); err != nil {
+		t.Fatal(err)
+	}
+	waitForSource(t, f.app, "added", running)
+}
+
+func TestSourceErrors(t *testing.T) {
+	f := newFixture(t, app.Options{})
+	ctx := context.Background()
+
+	if err := f.app.AddSource(app.AddSourceParams{Source: &datasource.MockSource{}}); err == nil {
+		t.Fatal("expected an error adding a source without a name")
+	}
+	if err := f.app.AddSource(app.AddSourceParams{Name: "mock", Source: &datasource.MockSource{}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.app.AddSource(app.AddSourceParams{Name: "mock", Source: &datasource.MockSource{}}); !errors.Is(err, app.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists adding a source twice, got %v", err)
+	}
+
+	for name, op := range map[string]func(context.Context, string) (app.SourceStatus, error){
+		"enable":  f.app.EnableSource,
+		"disable": f.app.DisableSource,
+		"restart": f.app.RestartSource,
+	} {
+		if _, err := op(ctx, "unknown"); !errors.Is(err, app.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound to %s an unknown source, got %v", name, err)
+		}
+	}
+
+	runSources(t, f.app)
+	waitForSource(t, f.app, "mock", running)
+	if err := f.app.RunSources(ctx); err == nil {
+		t.Fatal("expected an error running the sources twice")
+	}
+}
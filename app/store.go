@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned by a Store when the requested entity, or an entity it references, does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is returned by a Store when an entity would violate a uniqueness constraint.
+	ErrAlreadyExists = errors.New("already exists")
+)
+
+// Store is the persistence layer of the App.
+//
+// Implementations must keep names of environments, applications and instances unique,
+// only allow a single instance per environment and application,
+// and delete the instances, deployments and rollouts of deleted environments, applications and instances,
+// as well as the releases of deleted applications, the promotions of deleted environments,
+// the policies of deleted applications and instances, the policy violations of deleted deployments
+// the freeze windows of deleted environments and applications, the webhooks of deleted environments and applications
+// the deliveries of deleted webhooks and the notification routes of deleted environments and applications.
+// Names of freeze windows, webhooks and notification channels are unique too.
+// Environments and applications are listed by their sort order, then by id.
+type Store interface {
+	ListApplications(ctx context.Context) ([]Application, error)
+	GetApplication(ctx context.Context, id int32) (Application, error)
+	// CreateApplication creates an application sorted after all existing ones.
+	CreateApplication(ctx context.Context, name string) (Application, error)
+	UpdateApplication(ctx context.Context, id int32, name string) (Application, error)
+	DeleteApplication(ctx context.Context, id int32) error
+	// ReorderApplications gives the applications in ids the sort orders 1, 2, 3... in the order they are given.
+	ReorderApplications(ctx context.Context, ids []int32) error
+
+	ListEnvironments(ctx context.Context) ([]Environment, error)
+	GetEnvironment(ctx context.Context, id int32) (Environment, error)
+	// CreateEnvironment creates an environment sorted after all existing ones.
+	CreateEnvironment(ctx context.Context, name string) (Environment, error)
+	UpdateEnvironment(ctx context.Context, id int32, name string) (Environment, error)
+	DeleteEnvironment(ctx context.Context, id int32) error
+	// ReorderEnvironments gives the environments in ids the sort orders 1, 2, 3... in the order they are given.
+	ReorderEnvironments(ctx context.Context, ids []int32) error
+
+	CreateInstance(ctx context.Context, params CreateInstanceParameters) (int32, error)
+	UpdateInstance(ctx context.Context, params UpdateInstanceParameters) error
+	// ListInstances lists all instances, or only the one with the given name if it is not empty.
+	ListInstances(ctx context.Context, name string) ([]Instance, error)
+	GetInstance(ctx context.Context, id int32) (Instance, error)
+	DeleteInstance(ctx context.Context, id int32) error
+	// ListInstancesAndDeployment lists all instances together with the current deployment of their primary component.
+	ListInstancesAndDeployment(ctx context.Context) ([]InstanceAndDeploymentResult, error)
+
+	// ListDeployments lists all deployments ordered by instance, component, then by the time they were deployed.
+	ListDeployments(ctx context.Context) ([]Deployment, error)
+	// ListInstanceDeployments lists the deployments of the instance ordered by component, then by the time they were deployed.
+	ListInstanceDeployments(ctx context.Context, instanceId int32) ([]Deployment, error)
+	// ListDeployedVersions lists the distinct versions deployed to the component of the instance,
+	// ignoring the undeployments.
+	ListDeployedVersions(ctx context.Context, instanceId int32, component string) ([]string, error)
+	// ListCurrentDeployments lists the current deployment of every component of every instance,
+	// ordered by instance and component.
+	ListCurrentDeployments(ctx context.Context) ([]Deployment, error)
+	// RegisterDeployment stores the deployment and returns it with the id generated by the store.
+	// It becomes the current deployment of its instance component unless the current one supersedes it,
+	// in which case it is stored as late. See Deployment.Supersedes for how deployments are ordered.
+	// The current deployment it replaced is returned too, read in the same transaction,
+	// or a zero deployment if the component had none or the deployment is late.
+	RegisterDeployment(ctx context.Context, deployment Deployment) (registered, replaced Deployment, err error)
+	// DeleteDeployments deletes the deployments with the given ids and returns how many were deleted.
+	// The current deployment of a component is never deleted, even if its id is given.
+	DeleteDeployments(ctx context.Context, ids []string) (int64, error)
+
+	// ListRollouts lists the rollout of every component of every instance, ordered by instance and component.
+	ListRollouts(ctx context.Context) ([]Rollout, error)
+	// UpdateRollout replaces the rollout of the instance component and reports whether it did,
+	// it is not replaced if the stored rollout supersedes it. See Rollout.Supersedes for how rollouts are ordered.
+	// The stored running version is kept if the rollout has none.
+	UpdateRollout(ctx context.Context, rollout Rollout) (bool, error)
+
+	// ListReleases lists the latest release of every application that has one, ordered by application.
+	ListReleases(ctx context.Context) ([]Release, error)
+	// SaveRelease replaces the latest release of the application.
+	SaveRelease(ctx context.Context, release Release) error
+
+	// ListPromotions lists the edges of the promotion graph, ordered by the environment they promote from, then to.
+	ListPromotions(ctx context.Context) ([]PromotionEdge, error)
+	// SetPromotionSources replaces the environments that versions are promoted to the environment from.
+	SetPromotionSources(ctx context.Context, environmentId int32, sourceIds []int32) error
+
+	// ListVersionPolicies lists the policies of the applications and instances, ordered by application,
+	// with the policy of an application before the ones of its instances, which are ordered by instance.
+	ListVersionPolicies(ctx context.Context) ([]VersionPolicy, error)
+	// SaveVersionPolicy replaces the policy of the instance, or of the application if the instance id is zero.
+	// The application id of an instance policy is ignored, it is the one of the instance.
+	SaveVersionPolicy(ctx context.Context, policy VersionPolicy) error
+	// DeleteVersionPolicy deletes the policy of the instance, or of the application if the instance id is zero.
+	DeleteVersionPolicy(ctx context.Context, applicationId, instanceId int32) error
+	// ListPolicyViolations lists the recorded violations, ordered by when they were detected.
+	ListPolicyViolations(ctx context.Context) ([]PolicyViolation, error)
+	// AddPolicyViolations records the violations, a violation of a rule that is already recorded for the deployment is ignored.
+	AddPolicyViolations(ctx context.Context, violations []PolicyViolation) error
+
+	// ListFreezeWindows lists the freeze windows ordered by id, without their status.
+	ListFreezeWindows(ctx context.Context) ([]FreezeWindow, error)
+	CreateFreezeWindow(ctx context.Context, window FreezeWindow) (int32, error)
+	// UpdateFreezeWindow replaces the freeze window with the id of the given one.
+	UpdateFreezeWindow(ctx context.Context, window FreezeWindow) error
+	DeleteFreezeWindow(ctx context.Context, id int32) error
+
+	// ListWebhooks lists the webhooks ordered by id, with their secrets.
+	ListWebhooks(ctx context.Context) ([]Webhook, error)
+	CreateWebhook(ctx context.Context, webhook Webhook) (int32, error)
+	// UpdateWebhook replaces the webhook with the id of the given one, except for when it was created.
+	UpdateWebhook(ctx context.Context, webhook Webhook) error
+	DeleteWebhook(ctx context.Context, id int32) error
+	// AddWebhookDeliveries stores the deliveries with the ids they are given.
+	AddWebhookDeliveries(ctx context.Context, deliveries []WebhookDelivery) error
+	GetWebhookDelivery(ctx context.Context, id string) (WebhookDelivery, error)
+	// ListWebhookDeliveries lists at most limit deliveries of the webhook, or of all webhooks if the id is zero,
+	// the most recently created first.
+	ListWebhookDeliveries(ctx context.Context, webhookId int32, limit int32) ([]WebhookDelivery, error)
+	// ListDueWebhookDeliveries lists the pending deliveries whose next attempt is due at t, ordered by when it is due.
+	ListDueWebhookDeliveries(ctx context.Context, t time.Time) ([]WebhookDelivery, error)
+	// UpdateWebhookDelivery replaces the outcome of the delivery with the one of the given one.
+	UpdateWebhookDelivery(ctx context.Context, delivery WebhookDelivery) error
+	// DeleteWebhookDeliveries deletes the deliveries that are no longer pending and were created before t,
+	// and returns how many were deleted.
+	DeleteWebhookDeliveries(ctx context.Context, before time.Time) (int64, error)
+
+	// ListNotificationChannels lists the notification channels ordered by id, with their routes in the order they were given.
+	ListNotificationChannels(ctx context.Context) ([]NotificationChannel, error)
+	CreateNotificationChannel(ctx context.Context, channel NotificationChannel) (int32, error)
+	// UpdateNotificationChannel replaces the channel with the id of the given one, including its routes.
+	UpdateNotificationChannel(ctx context.Context, channel NotificationChannel) error
+	DeleteNotificationChannel(ctx context.Context, id int32) error
+}
@@ -0,0 +1,273 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// version is a parsed version like 1.2.3, v1.2 or 2.0.0-rc.1+build.5.
+type version struct {
+	numbers    []int
+	prerelease []string
+}
+
+// parseVersion parses a version made of dot separated numbers, optionally prefixed with a v
+// and followed by a semver prerelease and build metadata, the build metadata is ignored.
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	s, _, _ = strings.Cut(s, "+")
+
+	core, prerelease, hasPrerelease := strings.Cut(s, "-")
+
+	var v version
+	for part := range strings.SplitSeq(core, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return version{}, false
+		}
+		v.numbers = append(v.numbers, n)
+	}
+
+	if hasPrerelease {
+		if prerelease == "" {
+			return version{}, false
+		}
+		v.prerelease = strings.Split(prerelease, ".")
+	}
+
+	return v, true
+}
+
+// compare orders versions by their numbers, missing numbers counting as zero,
+// then a prerelease before its release, like semver.
+func (v version) compare(other version) int {
+	for i := range max(len(v.numbers), len(other.numbers)) {
+		var a, b int
+		if i < len(v.numbers) {
+			a = v.numbers[i]
+		}
+		if i < len(other.numbers) {
+			b = other.numbers[i]
+		}
+		if c := cmp.Compare(a, b); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+
+	for i := range min(len(v.prerelease), len(other.prerelease)) {
+		a, b := v.prerelease[i], other.prerelease[i]
+		an, aErr := strconv.Atoi(a)
+		bn, bErr := strconv.Atoi(b)
+
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = cmp.Compare(an, bn)
+		case aErr == nil:
+			c = -1 // Numeric identifiers are ordered before alphanumeric ones.
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(a, b)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(v.prerelease), len(other.prerelease))
+}
+
+// compareVersions compares two versions, the second result is false if either is not a version parseVersion understands.
+func compareVersions(a, b string) (int, bool) {
+	va, ok := parseVersion(a)
+	if !ok {
+		return 0, false
+	}
+	vb, ok := parseVersion(b)
+	if !ok {
+		return 0, false
+	}
+	return va.compare(vb), true
+}
+
+// latestRelease returns the latest version among the tags that isn't a prerelease,
+// ignoring the tags that aren't versions like latest or sha-1a2b3c. It is empty if there is none.
+func latestRelease(tags []string) string {
+	var (
+		latest    string
+		latestVer version
+	)
+	for _, tag := range tags {
+		v, ok := parseVersion(tag)
+		if !ok || len(v.prerelease) > 0 {
+			continue
+		}
+		if latest == "" || v.compare(latestVer) > 0 {
+			latest, latestVer = tag, v
+		}
+	}
+	return latest
+}
+
+// sameVersion reports whether a and b are the same version, like v1.2.0 and 1.2.0.
+func sameVersion(a, b string) bool {
+	if a == b {
+		return true
+	}
+	c, ok := compareVersions(a, b)
+	return ok && c == 0
+}
+
+// constraint is a range of versions, it allows a version if all terms of any of its groups do.
+type constraint [][]versionTerm
+
+type versionTerm struct {
+	op string
+	v  version
+}
+
+// parseConstraint parses a range of versions like ">=1.2.0, <2.0.0", "^1.2 || ^2.0", "~1.2.3", "1.x" or "!=1.3.0".
+// The terms of a group are separated by commas or spaces and groups are separated by ||.
+//
+// ^ allows the changes that don't modify the first non-zero number, ~ allows patch changes, or minor changes
+// if only the major version is given, and a wildcard allows any value for the number it replaces.
+func parseConstraint(s string) (constraint, error) {
+	var c constraint
+	for group := range strings.SplitSeq(s, "||") {
+		// Operators may be separated from their version by spaces, like ">= 1.2".
+		fields := strings.FieldsFunc(group, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		var terms []versionTerm
+		for i := 0; i < len(fields); i++ {
+			term := fields[i]
+			if strings.TrimLeft(term, "<>=!^~") == "" && i+1 < len(fields) {
+				i++
+				term += fields[i]
+			}
+
+			t, err := parseTerm(term)
+			if err != nil {
+				return nil, invalid("invalid version range %q: %w", s, err)
+			}
+			terms = append(terms, t...)
+		}
+		if len(terms) == 0 {
+			return nil, invalid("invalid version range %q: empty range", s)
+		}
+		c = append(c, terms)
+	}
+	return c, nil
+}
+
+// parseTerm parses a term of a range, ^, ~ and wildcards are expanded to a lower and upper bound.
+func parseTerm(term string) ([]versionTerm, error) {
+	op := term[:len(term)-len(strings.TrimLeft(term, "<>=!^~"))]
+	rest := term[len(op):]
+
+	if !slices.Contains([]string{"", "=", "!=", ">", ">=", "<", "<=", "^", "~"}, op) {
+		return nil, fmt.Errorf("unknown operator %q", op)
+	}
+
+	// A wildcard ends the numbers of the version, like 1.2.x or 1.*.
+	numbers, wildcard := rest, false
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(rest, "v"), "V")
+	for i, part := range strings.Split(trimmed, ".") {
+		if part == "x" || part == "X" || part == "*" {
+			numbers, wildcard = strings.Join(strings.Split(trimmed, ".")[:i], "."), true
+			break
+		}
+	}
+
+	if wildcard {
+		if op != "" && op != "=" {
+			return nil, fmt.Errorf("wildcards can not be used with %q", op)
+		}
+		if numbers == "" {
+			// Any version, a lower bound of zero allows everything but prereleases.
+			return []versionTerm{{op: ">=", v: version{numbers: []int{0}}}}, nil
+		}
+		op = "~"
+		if !strings.Contains(numbers, ".") {
+			op = "^"
+		}
+		rest = numbers
+	}
+
+	v, ok := parseVersion(rest)
+	if !ok {
+		return nil, fmt.Errorf("invalid version %q", rest)
+	}
+
+	switch op {
+	case "", "=":
+		return []versionTerm{{op: "=", v: v}}, nil
+	case "^":
+		// The upper bound bumps the first non-zero number, or the last one if all are zero.
+		i := slices.IndexFunc(v.numbers, func(n int) bool { return n != 0 })
+		if i == -1 {
+			i = len(v.numbers) - 1
+		}
+		return []versionTerm{{op: ">=", v: v}, {op: "<", v: bump(v, i)}}, nil
+	case "~":
+		i := min(1, len(v.numbers)-1)
+		return []versionTerm{{op: ">=", v: v}, {op: "<", v: bump(v, i)}}, nil
+	default:
+		return []versionTerm{{op: op, v: v}}, nil
+	}
+}
+
+// bump returns the release version after v with the number at i incremented and the later numbers removed.
+func bump(v version, i int) version {
+	numbers := slices.Clone(v.numbers[:i+1])
+	numbers[i]++
+	return version{numbers: numbers}
+}
+
+// allows reports whether the version is in the range, versions parseVersion doesn't understand never are.
+// Prereleases are only allowed by groups with a term that has a prerelease.
+func (c constraint) allows(s string) bool {
+	v, ok := parseVersion(s)
+	if !ok {
+		return false
+	}
+
+	for _, group := range c {
+		if len(v.prerelease) > 0 && !slices.ContainsFunc(group, func(t versionTerm) bool { return len(t.v.prerelease) > 0 }) {
+			continue
+		}
+		if !slices.ContainsFunc(group, func(t versionTerm) bool { return !t.allows(v) }) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t versionTerm) allows(v version) bool {
+	c := v.compare(t.v)
+	switch t.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package app
+
+import "testing"
+
+func TestLatestRelease(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want string
+	}{
+		{"no tags", nil, ""},
+		{"no versions", []string{"latest", "main", "sha-1a2b3c"}, ""},
+		{"greatest version", []string{"1.2.0", "1.10.0", "1.9.3"}, "1.10.0"},
+		{"prereleases are skipped", []string{"1.2.0", "1.3.0-rc.1", "2.0.0-beta"}, "1.2.0"},
+		{"only prereleases", []string{"1.0.0-rc.1", "1.0.0-rc.2"}, ""},
+		{"the tag is kept with its prefix", []string{"v1.2.0", "v1.1.0", "latest"}, "v1.2.0"},
+		{"build metadata is ignored", []string{"1.2.0+build.2", "1.1.0"}, "1.2.0+build.2"},
+		{"missing numbers count as zero", []string{"2", "1.9.9", "2.0.0-rc.1"}, "2"},
+		{"the first of equal versions", []string{"v2.0", "2.0.0"}, "v2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := latestRelease(tt.tags); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		want   int
+		wantOk bool
+	}{
+		{"1.2.3", "1.2.3", 0, true},
+		{"v1.2.3", "1.2.3", 0, true},
+		{"1.2", "1.2.0", 0, true},
+		{"1.2.3+build.1", "1.2.3+build.2", 0, true},
+		{"1.10.0", "1.9.0", 1, true},
+		{"1.2.3", "1.2.4", -1, true},
+		{"2.0.0-rc.1", "2.0.0", -1, true},
+		{"2.0.0-rc.2", "2.0.0-rc.10", -1, true},
+		{"2.0.0-alpha", "2.0.0-beta", -1, true},
+		{"2.0.0-1", "2.0.0-alpha", -1, true},
+		{"2.0.0-alpha", "2.0.0-alpha.1", -1, true},
+		{"latest", "1.0.0", 0, false},
+		{"1.0.0", "1.0.0-", 0, false},
+		{"1.x", "1.0.0", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			got, ok := compareVersions(tt.a, tt.b)
+			if got != tt.want || ok != tt.wantOk {
+				t.Fatalf("expected %d and %t, got %d and %t", tt.want, tt.wantOk, got, ok)
+			}
+		})
+	}
+}
+
+func TestConstraintAllows(t *testing.T) {
+	tests := []struct {
+		constraint string
+		allowed    []string
+		disallowed []string
+	}{
+		{"1.2.3", []string{"1.2.3", "v1.2.3", "1.2.3+build"}, []string{"1.2.4", "1.2.3-rc.1"}},
+		{"=1.2", []string{"1.2.0"}, []string{"1.2.1"}},
+		{"!=1.3.0", []string{"1.2.0", "1.4.0"}, []string{"1.3.0", "1.4.0-rc.1"}},
+		{">=1.2.0, <2.0.0", []string{"1.2.0", "1.9.9"}, []string{"1.1.9", "2.0.0", "1.5.0-rc.1"}},
+		{">= 1.2 < 2", []string{"1.2.0", "1.99.0"}, []string{"2.0.0", "1.1.0"}},
+		{">1.2.0 <=1.4.0", []string{"1.2.1", "1.4.0"}, []string{"1.2.0", "1.4.1"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0", "2.0.0-rc.1"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4", "0.0.2"}},
+		{"^0.0", []string{"0.0.0", "0.0.9"}, []string{"0.1.0"}},
+		{"^1", []string{"1.0.0", "1.9.9"}, []string{"2.0.0", "0.9.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"1.x", []string{"1.0.0", "1.9.9"}, []string{"2.0.0", "0.9.0", "1.2.0-rc.1"}},
+		{"1.2.*", []string{"1.2.0", "1.2.9"}, []string{"1.3.0", "1.1.9"}},
+		{"v1.2.X", []string{"1.2.5"}, []string{"1.3.0"}},
+		{"*", []string{"0.0.1", "1.0.0", "99.0.0"}, []string{"1.0.0-rc.1", "latest"}},
+		{"^1.2 || ^2.0", []string{"1.2.0", "2.5.0"}, []string{"1.1.0", "3.0.0"}},
+		{"1.x || >=3.0.0", []string{"1.5.0", "3.1.0"}, []string{"2.0.0"}},
+		{">=2.0.0-rc.1", []string{"2.0.0-rc.1", "2.0.0-rc.2", "2.0.0", "2.1.0-rc.1"}, []string{"2.0.0-beta", "1.9.0"}},
+		{"^1.2 || >=2.0.0-rc.1 <2.0.0", []string{"1.3.0", "2.0.0-rc.3"}, []string{"1.3.0-rc.1", "2.0.0"}},
+		{">=1.0.0", []string{"1.0.0"}, []string{"latest", "sha-1a2b3c", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c, err := parseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, v := range tt.allowed {
+				if !c.allows(v) {
+					t.Errorf("expected %s to be allowed", v)
+				}
+			}
+			for _, v := range tt.disallowed {
+				if c.allows(v) {
+					t.Errorf("expected %s not to be allowed", v)
+				}
+			}
+		})
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"1.0.0 ||",
+		"|| 1.0.0",
+		">=",
+		"=>1.0.0",
+		"<>1.0.0",
+		"^^1.0.0",
+		">=1.x",
+		"~1.*",
+		"latest",
+		"1.0.0-",
+		">=1.0.0, <two",
+	} {
+		t.Run(s, func(t *testing.T) {
+			if _, err := parseConstraint(s); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -1,8 +1,8 @@
 package app
 
 import (
+	"cmp"
 	"context"
-	"fmt"
 	"log/slog"
 	"overseer/datasource"
 )
@@ -11,38 +11,57 @@ type EventSource interface {
 	StreamEvents(ctx context.Context) (<-chan datasource.Event, error)
 }
 
-func (a *App) RunVersionStream(ctx context.Context, source EventSource) error {
-	s, err := source.StreamEvents(ctx)
-	if err != nil {
-		return fmt.Errorf("starting the event stream: %w", err)
-	}
+// handleEvent registers the deployment or rollout update of an event of a source.
+func (a *App) handleEvent(ctx context.Context, event datasource.Event) {
+	slog.Info("Received event", "source", event.Source, "id", event.Id, "name", event.DeploymentName, "version", event.Version, "undeployed", event.Undeployed, "deployedAt", event.DeployedAt)
 
-	for event := range s {
-		slog.Info("Received event", "id", event.Id, "name", event.DeploymentName, "version", event.Version, "deployedAt", event.DeployedAt)
+	instanceName, component := a.routeEvent(event.DeploymentName, event.Component)
 
-		instanceResp, err := a.ListInstances(ctx, ListInstancesParameters{
-			Name: event.DeploymentName,
-		})
-		if err != nil {
-			slog.Error("listing instances", "error", err)
-			continue
-		}
-		if len(instanceResp) == 0 {
-			slog.Warn("no instance found for deployment", "deployment", event.DeploymentName)
-			continue
-		}
+	instanceResp, err := a.ListInstances(ctx, ListInstancesParameters{
+		Name: instanceName,
+	})
+	if err != nil {
+		slog.Error("listing instances", "error", err)
+		return
+	}
+	if len(instanceResp) == 0 {
+		slog.Warn("no instance found for deployment", "deployment", event.DeploymentName, "instance", instanceName)
+		return
+	}
 
-		if len(instanceResp) > 1 {
-			panic("multiple instances found for deployment, this should never happen")
-		}
+	if len(instanceResp) > 1 {
+		slog.Error("multiple instances found for deployment, skipping the event", "deployment", event.DeploymentName, "instance", instanceName, "count", len(instanceResp))
+		return
+	}
 
-		if err = a.RegisterDeployment(ctx, RegisterDeploymentParams{
-			InstanceId: instanceResp[0].Id,
-			Version:    event.Version,
-			DeployedAt: event.DeployedAt,
+	if event.Rollout != nil {
+		if _, err := a.UpdateRollout(ctx, UpdateRolloutParams{
+			InstanceId:  instanceResp[0].Id,
+			Component:   cmp.Or(component, instanceResp[0].PrimaryComponent),
+			Version:     event.Version,
+			Status:      event.Rollout.Status,
+			Description: event.Rollout.Description,
+			Healthy:     event.Rollout.Healthy,
+			Desired:     event.Rollout.Desired,
+			UpdatedAt:   event.DeployedAt,
+			Sequence:    event.Sequence,
+			Source:      event.Source,
 		}); err != nil {
-			slog.Error("registering deployment", "error", err)
+			slog.Error("updating rollout", "error", err)
 		}
+		return
+	}
+
+	if _, err = a.RegisterDeployment(ctx, RegisterDeploymentParams{
+		InstanceId: instanceResp[0].Id,
+		Component:  cmp.Or(component, instanceResp[0].PrimaryComponent),
+		Version:    event.Version,
+		DeployedAt: event.DeployedAt,
+		Sequence:   event.Sequence,
+		Metadata:   DeploymentMetadata(event.Metadata),
+		Undeployed: event.Undeployed,
+		Source:     event.Source,
+	}); err != nil {
+		slog.Error("registering deployment", "error", err)
 	}
-	return nil
 }
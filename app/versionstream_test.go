@@ -0,0 +1,24 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"overseer/datasource"
+)
+
+// duplicateStore finds two instances for every name, any other call panics.
+type duplicateStore struct {
+	Store
+}
+
+func (s *duplicateStore) ListInstances(ctx context.Context, name string) ([]Instance, error) {
+	return []Instance{{Id: 1, Name: name, EnvironmentId: 1}, {Id: 2, Name: name, EnvironmentId: 2}}, nil
+}
+
+func TestHandleEventMultipleInstances(t *testing.T) {
+	a := New(&duplicateStore{}, Options{})
+
+	// The event is skipped instead of crashing the source.
+	a.handleEvent(context.Background(), datasource.Event{Source: "docker", Id: "1", DeploymentName: "api", Version: "1.0.0"})
+}
@@ -0,0 +1,622 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The types of the events that webhooks can subscribe to.
+const (
+	// EventDeployment is sent when a deployment or undeployment is registered.
+	EventDeployment = "deployment"
+	// EventRollout is sent when the rollout of an instance component progresses.
+	EventRollout = "rollout"
+	// EventPolicyViolation is sent when a deployment drifts from the version policy of its instance.
+	EventPolicyViolation = "policy_violation"
+	// EventRelease is sent when a new latest release of an application is discovered.
+	EventRelease = "release"
+)
+
+var eventTypes = []string{EventDeployment, EventRollout, EventPolicyViolation, EventRelease}
+
+// Statuses of a webhook delivery.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Headers of the webhook requests. The signature has the format of the signature of the generic webhook source,
+// sha256=<hex encoded HMAC-SHA256 of the body>, and is only sent if the webhook has a secret.
+const (
+	HeaderWebhookEvent     = "X-Overseer-Event"
+	HeaderWebhookDelivery  = "X-Overseer-Delivery"
+	HeaderWebhookSignature = "X-Overseer-Signature"
+)
+
+const (
+	// maxWebhookAttempts is how many times a delivery is attempted before it fails.
+	maxWebhookAttempts = 8
+	// webhookBackoff is the delay before the second attempt of a delivery, it doubles for every further attempt.
+	webhookBackoff = 10 * time.Second
+	// maxWebhookBackoff is the longest delay between two attempts of a delivery.
+	maxWebhookBackoff = time.Hour
+	// webhookDeliveryRetention is how long finished deliveries are kept in the delivery log.
+	webhookDeliveryRetention = 30 * 24 * time.Hour
+	// DefaultWebhookDeliveries is how many deliveries are listed if no limit is given.
+	DefaultWebhookDeliveries = 100
+	// maxConcurrentWebhooks is how many webhooks are delivered to at the same time.
+	maxConcurrentWebhooks = 8
+	// maxQueuedEvents is how many published events wait to be sent at most, the events published
+	// while the queue is full are dropped.
+	maxQueuedEvents = 10000
+)
+
+// Webhook subscribes another system to the events of Overseer, like triggering smoke tests when staging changes.
+type Webhook struct {
+	Id   int32  `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Secret signs the payloads in the X-Overseer-Signature header. It is never listed, and an empty secret
+	// keeps the current one when the webhook is updated.
+	Secret string `json:"secret,omitempty"`
+	// HasSecret is set when listing a webhook that has a secret.
+	HasSecret bool `json:"has_secret"`
+	// Events are the subscribed event types, all events are sent if it is empty.
+	Events []string `json:"events,omitempty"`
+	// EnvironmentId only sends the events of the environment if it is set.
+	// Events without an environment, like releases, are then not sent.
+	EnvironmentId int32 `json:"environment_id,omitempty"`
+	// ApplicationId only sends the events of the application if it is set.
+	ApplicationId int32 `json:"application_id,omitempty"`
+	// Disabled webhooks are not sent new events, and their pending deliveries wait until they are enabled again.
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is an event sent, or to be sent, to a webhook.
+type WebhookDelivery struct {
+	Id        string `json:"id"`
+	WebhookId int32  `json:"webhook_id"`
+	// EventId is the id of the event, which is the same for the redeliveries of a delivery.
+	EventId string `json:"event_id"`
+	Event   string `json:"event"`
+	// Payload is the JSON body that is sent.
+	Payload json.RawMessage `json:"payload"`
+	// Status is one of the Delivery statuses.
+	Status   string `json:"status"`
+	Attempts int32  `json:"attempts"`
+	// ResponseStatus is the HTTP status code of the last attempt, zero if there was no response.
+	ResponseStatus int32 `json:"response_status,omitempty"`
+	// Error describes why the last attempt failed.
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// NextAttemptAt is when a pending delivery is attempted next.
+	NextAttemptAt time.Time `json:"next_attempt_at,omitzero"`
+	LastAttemptAt time.Time `json:"last_attempt_at,omitzero"`
+}
+
+// Event is something that happened in Overseer, sent as the payload of the webhooks.
+// Only the fields of its type are set.
+type Event struct {
+	Id         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+
+	Environment *EventEntity `json:"environment,omitempty"`
+	Application *EventEntity `json:"application,omitempty"`
+	Instance    *EventEntity `json:"instance,omitempty"`
+
+	Deployment *Deployment `json:"deployment,omitempty"`
+	// PreviousVersion is the version of the component before a deployment, empty if there was none.
+	PreviousVersion string            `json:"previous_version,omitempty"`
+	Rollout         *Rollout          `json:"rollout,omitempty"`
+	Violations      []PolicyViolation `json:"violations,omitempty"`
+	Release         *Release          `json:"release,omitempty"`
+}
+
+// EventEntity names an environment, application or instance an event is about.
+type EventEntity struct {
+	Id   int32  `json:"id"`
+	Name string `json:"name"`
+}
+
+// WebhookSender sends the requests of the webhook deliveries and of the notifications.
+type WebhookSender interface {
+	// Post posts the body to the URL and returns the status code of the response.
+	Post(ctx context.Context, url string, headers map[string]string, body []byte) (int, error)
+}
+
+// ListWebhooks lists the webhooks without their secrets.
+func (a *App) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	webhooks, err := a.store.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].HasSecret = webhooks[i].Secret != ""
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (a *App) CreateWebhook(ctx context.Context, webhook Webhook) (Webhook, error) {
+	if err := webhook.validate(); err != nil {
+		return Webhook{}, err
+	}
+	webhook.CreatedAt = time.Now().UTC()
+
+	id, err := a.store.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return Webhook{}, err
+	}
+	webhook.Id = id
+
+	webhook.HasSecret = webhook.Secret != ""
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// UpdateWebhook replaces the webhook with the id of the given one, keeping its secret if the given one is empty.
+func (a *App) UpdateWebhook(ctx context.Context, webhook Webhook) (Webhook, error) {
+	if webhook.Id == 0 {
+		return Webhook{}, invalid("id is required")
+	}
+	if err := webhook.validate(); err != nil {
+		return Webhook{}, err
+	}
+
+	current, err := a.getWebhook(ctx, webhook.Id)
+	if err != nil {
+		return Webhook{}, err
+	}
+	webhook.Secret = cmp.Or(webhook.Secret, current.Secret)
+	webhook.CreatedAt = current.CreatedAt
+
+	if err := a.store.UpdateWebhook(ctx, webhook); err != nil {
+		return Webhook{}, err
+	}
+
+	webhook.HasSecret = webhook.Secret != ""
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// DeleteWebhook deletes the webhook and its delivery log.
+func (a *App) DeleteWebhook(ctx context.Context, id int32) error {
+	return a.store.DeleteWebhook(ctx, id)
+}
+
+type ListWebhookDeliveriesParams struct {
+	// WebhookId only lists the deliveries of the webhook if it is set.
+	WebhookId int32
+	// Limit is the maximum number of deliveries listed, DefaultWebhookDeliveries if it is zero.
+	Limit int32
+}
+
+// ListWebhookDeliveries lists the delivery log, the most recent deliveries first.
+func (a *App) ListWebhookDeliveries(ctx context.Context, params ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	if params.Limit < 0 {
+		return nil, invalid("limit can not be negative")
+	}
+	if params.Limit == 0 {
+		params.Limit = DefaultWebhookDeliveries
+	}
+
+	return a.store.ListWebhookDeliveries(ctx, params.WebhookId, params.Limit)
+}
+
+// RedeliverWebhook sends the payload of a delivery to its webhook again, as a new delivery of the same event.
+func (a *App) RedeliverWebhook(ctx context.Context, deliveryId string) (WebhookDelivery, error) {
+	d, err := a.store.GetWebhookDelivery(ctx, deliveryId)
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+
+	now := time.Now().UTC()
+	redelivery := WebhookDelivery{
+		Id:            uuid.NewString(),
+		WebhookId:     d.WebhookId,
+		EventId:       d.EventId,
+		Event:         d.Event,
+		Payload:       d.Payload,
+		Status:        DeliveryPending,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+	if err := a.store.AddWebhookDeliveries(ctx, []WebhookDelivery{redelivery}); err != nil {
+		return WebhookDelivery{}, err
+	}
+
+	a.wakeWebhooks()
+	return redelivery, nil
+}
+
+func (w *Webhook) validate() error {
+	w.Name = strings.TrimSpace(w.Name)
+	if w.Name == "" {
+		return invalid("name is required")
+	}
+
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return invalid("invalid webhook url %q", w.URL)
+	}
+
+	var events []string
+	for _, e := range w.Events {
+		if !slices.Contains(eventTypes, e) {
+			return invalid("unknown event type %q, expected one of %s", e, strings.Join(eventTypes, ", "))
+		}
+		if !slices.Contains(events, e) {
+			events = append(events, e)
+		}
+	}
+	w.Events = events
+	return nil
+}
+
+// matches reports whether the webhook is subscribed to the event.
+func (w Webhook) matches(e Event) bool {
+	if w.Disabled || (len(w.Events) > 0 && !slices.Contains(w.Events, e.Type)) {
+		return false
+	}
+	if w.EnvironmentId != 0 && (e.Environment == nil || e.Environment.Id != w.EnvironmentId) {
+		return false
+	}
+	if w.ApplicationId != 0 && (e.Application == nil || e.Application.Id != w.ApplicationId) {
+		return false
+	}
+	return true
+}
+
+func (a *App) getWebhook(ctx context.Context, id int32) (Webhook, error) {
+	webhooks, err := a.store.ListWebhooks(ctx)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	i := slices.IndexFunc(webhooks, func(w Webhook) bool { return w.Id == id })
+	if i == -1 {
+		return Webhook{}, fmt.Errorf("%w: webhook %d", ErrNotFound, id)
+	}
+	return webhooks[i], nil
+}
+
+// eventQueue holds the published events until they are sent, in the order they were published,
+// so that publishing them does not hold up the requests and sources that cause them.
+type eventQueue struct {
+	mu      sync.Mutex
+	pending []queuedEvent
+	// limit is the number of pending events the queue holds at most.
+	limit int
+	// sending is set while a goroutine sends the pending events, sent is signaled when it is done.
+	sending bool
+	sent    *sync.Cond
+}
+
+type queuedEvent struct {
+	ctx                       context.Context
+	event                     Event
+	instanceId, applicationId int32
+}
+
+func newEventQueue(limit int) *eventQueue {
+	q := &eventQueue{limit: limit}
+	q.sent = sync.NewCond(&q.mu)
+	return q
+}
+
+// publish queues the event to be sent to the webhooks and notification channels it is routed to. The environment, application
+// and instance of the event are looked up from the instance or application id that is given. Errors are only logged, the event already happened.
+func (a *App) publish(ctx context.Context, event Event, instanceId, applicationId int32) {
+	event.Id = uuid.NewString()
+	event.OccurredAt = time.Now().UTC()
+
+	q := a.events
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) >= q.limit {
+		// The store is too slow or down, dropping the event beats holding up the caller or running out of memory.
+		slog.Warn("the event queue is full, dropping the event", "type", event.Type, "queued", len(q.pending))
+		return
+	}
+
+	// The event is sent after the request that caused it is done.
+	q.pending = append(q.pending, queuedEvent{context.WithoutCancel(ctx), event, instanceId, applicationId})
+	if !q.sending {
+		q.sending = true
+		go a.sendEvents()
+	}
+}
+
+// sendEvents sends the queued events one at a time until there are none left.
+func (a *App) sendEvents() {
+	q := a.events
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.sending = false
+			q.sent.Broadcast()
+			q.mu.Unlock()
+			return
+		}
+		e := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		a.sendEvent(e.ctx, e.event, e.instanceId, e.applicationId)
+	}
+}
+
+// waitForEvents waits until the events published so far are sent.
+func (a *App) waitForEvents() {
+	q := a.events
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.sending {
+		q.sent.Wait()
+	}
+}
+
+func (a *App) sendEvent(ctx context.Context, event Event, instanceId, applicationId int32) {
+	if err := a.describeEvent(ctx, &event, instanceId, applicationId); err != nil {
+		slog.Error("describing the event", "type", event.Type, "error", err)
+		return
+	}
+
+	if err := a.enqueueWebhooks(ctx, event); err != nil {
+		slog.Error("queueing the webhook deliveries", "type", event.Type, "error", err)
+	}
+
+	if err := a.notify(ctx, event); err != nil {
+		slog.Error("batching the notifications", "type", event.Type, "error", err)
+	}
+}
+
+// describeEvent sets the names of what the event is about.
+func (a *App) describeEvent(ctx context.Context, event *Event, instanceId, applicationId int32) error {
+	if instanceId != 0 {
+		instance, err := a.store.GetInstance(ctx, instanceId)
+		if err != nil {
+			return err
+		}
+		event.Instance = &EventEntity{Id: instance.Id, Name: instance.Name}
+		applicationId = instance.ApplicationId
+
+		environment, err := a.store.GetEnvironment(ctx, instance.EnvironmentId)
+		if err != nil {
+			return err
+		}
+		event.Environment = &EventEntity{Id: environment.Id, Name: environment.Name}
+	}
+
+	if applicationId != 0 {
+		application, err := a.store.GetApplication(ctx, applicationId)
+		if err != nil {
+			return err
+		}
+		event.Application = &EventEntity{Id: application.Id, Name: application.Name}
+	}
+	return nil
+}
+
+// enqueueWebhooks records a pending delivery of the event for every webhook subscribed to it.
+func (a *App) enqueueWebhooks(ctx context.Context, event Event) error {
+	webhooks, err := a.store.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	var deliveries []WebhookDelivery
+	for _, w := range webhooks {
+		if !w.matches(event) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return err
+			}
+		}
+
+		deliveries = append(deliveries, WebhookDelivery{
+			Id:            uuid.NewString(),
+			WebhookId:     w.Id,
+			EventId:       event.Id,
+			Event:         event.Type,
+			Payload:       payload,
+			Status:        DeliveryPending,
+			CreatedAt:     event.OccurredAt,
+			NextAttemptAt: event.OccurredAt,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := a.store.AddWebhookDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	a.wakeWebhooks()
+	return nil
+}
+
+// wakeWebhooks makes RunWebhooks attempt the due deliveries without waiting for its next interval.
+func (a *App) wakeWebhooks() {
+	select {
+	case a.webhookWake <- struct{}{}:
+	default:
+	}
+}
+
+// RunWebhooks attempts the due webhook deliveries when events are published, and at least every interval,
+// until the context is canceled. The finished deliveries are pruned from the delivery log when they get too old.
+func (a *App) RunWebhooks(ctx context.Context, sender WebhookSender, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid webhook interval %s", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastPruned time.Time
+	for {
+		if err := a.DeliverWebhooks(ctx, sender); err != nil {
+			slog.Error("delivering the webhooks", "error", err)
+		}
+
+		if time.Since(lastPruned) > time.Hour {
+			n, err := a.store.DeleteWebhookDeliveries(ctx, time.Now().UTC().Add(-webhookDeliveryRetention))
+			if err != nil {
+				slog.Error("pruning the webhook deliveries", "error", err)
+			} else if n > 0 {
+				slog.Info("pruned the webhook deliveries", "deliveries", n)
+			}
+			lastPruned = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-a.webhookWake:
+		}
+	}
+}
+
+// DeliverWebhooks attempts the pending deliveries that are due. A delivery succeeds on a 2xx response,
+// otherwise it is retried with an exponential backoff until it runs out of attempts.
+// The deliveries of disabled webhooks stay pending until they are enabled again.
+func (a *App) DeliverWebhooks(ctx context.Context, sender WebhookSender) error {
+	deliveries, err := a.store.ListDueWebhookDeliveries(ctx, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	webhooks, err := a.store.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	byId := map[int32]Webhook{}
+	for _, w := range webhooks {
+		byId[w.Id] = w
+	}
+
+	// The deliveries of a webhook are attempted in order, and the webhooks concurrently,
+	// so that a slow or unreachable webhook doesn't hold up the others.
+	due := map[int32][]WebhookDelivery{}
+	for _, d := range deliveries {
+		if w, ok := byId[d.WebhookId]; ok && !w.Disabled {
+			due[d.WebhookId] = append(due[d.WebhookId], d)
+		}
+	}
+
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, maxConcurrentWebhooks)
+	for id, deliveries := range due {
+		slots <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-slots }()
+
+			if err := a.deliverWebhook(ctx, sender, byId[id], deliveries); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// deliverWebhook attempts the due deliveries of the webhook in order. Once the webhook can't be reached, its remaining
+// deliveries wait for the next round, so that an unreachable webhook costs at most one timeout per round.
+func (a *App) deliverWebhook(ctx context.Context, sender WebhookSender, w Webhook, deliveries []WebhookDelivery) error {
+	var errs []error
+	for _, d := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		d = attemptDelivery(ctx, sender, w, d)
+		if err := a.store.UpdateWebhookDelivery(ctx, d); err != nil && !errors.Is(err, ErrNotFound) { // The webhook was deleted in the meantime.
+			errs = append(errs, fmt.Errorf("saving the webhook delivery %s: %w", d.Id, err))
+		}
+
+		if d.Status != DeliverySucceeded && d.ResponseStatus == 0 {
+			break
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// attemptDelivery sends the delivery to the webhook and returns it with the outcome of the attempt.
+func attemptDelivery(ctx context.Context, sender WebhookSender, w Webhook, d WebhookDelivery) WebhookDelivery {
+	headers := map[string]string{
+		"Content-Type":        "application/json",
+		HeaderWebhookEvent:    d.Event,
+		HeaderWebhookDelivery: d.Id,
+	}
+	if w.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.Secret))
+		mac.Write(d.Payload)
+		headers[HeaderWebhookSignature] = fmt.Sprintf("sha256=%x", mac.Sum(nil))
+	}
+
+	status, err := sender.Post(ctx, w.URL, headers, d.Payload)
+
+	now := time.Now().UTC()
+	d.Attempts++
+	d.LastAttemptAt = now
+	d.ResponseStatus = int32(status)
+	d.Error = ""
+
+	switch {
+	case err != nil:
+		d.Error = err.Error()
+	case status < 200 || status > 299:
+		d.Error = fmt.Sprintf("unexpected response status %d", status)
+	default:
+		d.Status = DeliverySucceeded
+		d.NextAttemptAt = time.Time{}
+		return d
+	}
+
+	if d.Attempts >= maxWebhookAttempts {
+		slog.Warn("the webhook delivery failed", "webhook", w.Name, "delivery", d.Id, "attempts", d.Attempts, "error", d.Error)
+		d.Status = DeliveryFailed
+		d.NextAttemptAt = time.Time{}
+		return d
+	}
+
+	backoff := min(webhookBackoff<<(d.Attempts-1), maxWebhookBackoff)
+	d.NextAttemptAt = now.Add(backoff)
+	return d
+}
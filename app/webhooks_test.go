@@ -0,0 +1,211 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"overseer/notify"
+)
+
+// webhookReceiver answers the webhook requests with its status and records them.
+type webhookReceiver struct {
+	status   int
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.requests = append(r.requests, receivedWebhook{header: req.Header, body: body})
+	w.WriteHeader(r.status)
+}
+
+// validSignature reports whether the signature is the one of the body signed with the secret.
+func validSignature(signature, secret string, body []byte) bool {
+	sum, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(sum, mac.Sum(nil))
+}
+
+func TestAttemptDelivery(t *testing.T) {
+	payload := []byte(`{"type":"deployment"}`)
+
+	tests := []struct {
+		name       string
+		secret     string
+		status     int
+		attempts   int32
+		wantStatus string
+		wantError  string
+		// wantBackoff is the delay before the next attempt of a delivery that is still pending.
+		wantBackoff time.Duration
+	}{
+		{name: "succeeded", status: http.StatusOK, wantStatus: DeliverySucceeded},
+		{name: "signed", secret: "s3cret", status: http.StatusNoContent, wantStatus: DeliverySucceeded},
+		{name: "first failure", status: http.StatusInternalServerError, wantStatus: DeliveryPending, wantError: "unexpected response status 500", wantBackoff: webhookBackoff},
+		{name: "second failure", status: http.StatusBadGateway, attempts: 1, wantStatus: DeliveryPending, wantError: "unexpected response status 502", wantBackoff: 2 * webhookBackoff},
+		{name: "not a 2xx status", status: http.StatusNotModified, attempts: 3, wantStatus: DeliveryPending, wantError: "unexpected response status 304", wantBackoff: 8 * webhookBackoff},
+		{name: "last retry", status: http.StatusServiceUnavailable, attempts: maxWebhookAttempts - 2, wantStatus: DeliveryPending, wantError: "unexpected response status 503", wantBackoff: 64 * webhookBackoff},
+		{name: "out of attempts", status: http.StatusServiceUnavailable, attempts: maxWebhookAttempts - 1, wantStatus: DeliveryFailed, wantError: "unexpected response status 503"},
+		{name: "succeeded after failures", secret: "s3cret", status: http.StatusAccepted, attempts: 4, wantStatus: DeliverySucceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &webhookReceiver{status: tt.status}
+			server := httptest.NewServer(receiver)
+			defer server.Close()
+
+			w := Webhook{Name: "ci", URL: server.URL + "/hook", Secret: tt.secret}
+			d := WebhookDelivery{
+				Id:       "d1",
+				Event:    EventDeployment,
+				Payload:  payload,
+				Status:   DeliveryPending,
+				Attempts: tt.attempts,
+				Error:    "previous error",
+			}
+
+			got := attemptDelivery(context.Background(), notify.NewClient(time.Second), w, d)
+
+			if len(receiver.requests) != 1 {
+				t.Fatalf("expected 1 request, got %d", len(receiver.requests))
+			}
+			r := receiver.requests[0]
+			if string(r.body) != string(payload) {
+				t.Fatalf("expected the payload %s, got %s", payload, r.body)
+			}
+			if r.header.Get("Content-Type") != "application/json" || r.header.Get(HeaderWebhookEvent) != EventDeployment || r.header.Get(HeaderWebhookDelivery) != "d1" {
+				t.Fatalf("expected the event headers, got %v", r.header)
+			}
+			signature := r.header.Get(HeaderWebhookSignature)
+			if tt.secret == "" && signature != "" {
+				t.Fatalf("expected no signature without a secret, got %q", signature)
+			}
+			if tt.secret != "" && !validSignature(signature, tt.secret, payload) {
+				t.Fatalf("expected the payload to be signed with the secret, got %q", signature)
+			}
+
+			if got.Status != tt.wantStatus || got.Error != tt.wantError || got.Attempts != tt.attempts+1 || got.ResponseStatus != int32(tt.status) {
+				t.Fatalf("expected %s after attempt %d with the error %q, got %+v", tt.wantStatus, tt.attempts+1, tt.wantError, got)
+			}
+			if got.LastAttemptAt.IsZero() {
+				t.Fatal("expected the time of the attempt to be recorded")
+			}
+			if tt.wantStatus != DeliveryPending {
+				if !got.NextAttemptAt.IsZero() {
+					t.Fatalf("expected no next attempt, got %s", got.NextAttemptAt)
+				}
+				return
+			}
+			if backoff := got.NextAttemptAt.Sub(got.LastAttemptAt); backoff != tt.wantBackoff {
+				t.Fatalf("expected the next attempt after %s, got %s", tt.wantBackoff, backoff)
+			}
+		})
+	}
+}
+
+func TestAttemptDeliveryUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	d := attemptDelivery(context.Background(), notify.NewClient(time.Second), Webhook{Name: "ci", URL: url}, WebhookDelivery{Id: "d1", Status: DeliveryPending, ResponseStatus: 500})
+	if d.Status != DeliveryPending || d.Error == "" || d.ResponseStatus != 0 || d.Attempts != 1 {
+		t.Fatalf("expected a pending delivery with the connection error, got %+v", d)
+	}
+	if backoff := d.NextAttemptAt.Sub(d.LastAttemptAt); backoff != webhookBackoff {
+		t.Fatalf("expected the next attempt after %s, got %s", webhookBackoff, backoff)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	server := httptest.NewServer(&webhookReceiver{status: http.StatusInternalServerError})
+	defer server.Close()
+
+	// The delay doubles from webhookBackoff, up to maxWebhookBackoff, until the delivery runs out of attempts.
+	var want []time.Duration
+	for delay := webhookBackoff; len(want) < maxWebhookAttempts-1; delay *= 2 {
+		want = append(want, min(delay, maxWebhookBackoff))
+	}
+
+	d := WebhookDelivery{Id: "d1", Status: DeliveryPending}
+	var got []time.Duration
+	for d.Status == DeliveryPending {
+		d = attemptDelivery(context.Background(), notify.NewClient(time.Second), Webhook{Name: "ci", URL: server.URL}, d)
+		if d.Status == DeliveryPending {
+			got = append(got, d.NextAttemptAt.Sub(d.LastAttemptAt))
+		}
+	}
+
+	if d.Status != DeliveryFailed || d.Attempts != maxWebhookAttempts {
+		t.Fatalf("expected the delivery to fail after %d attempts, got %+v", maxWebhookAttempts, d)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected the delays %v, got %v", want, got)
+	}
+}
+
+// blockingStore blocks looking up instances until it is released.
+type blockingStore struct {
+	Store
+	release chan struct{}
+	lookups atomic.Int32
+}
+
+func (s *blockingStore) GetInstance(ctx context.Context, id int32) (Instance, error) {
+	s.lookups.Add(1)
+	<-s.release
+	return Instance{}, ErrNotFound
+}
+
+func TestEventQueueLimit(t *testing.T) {
+	store := &blockingStore{release: make(chan struct{})}
+	a := New(store, Options{})
+	a.events = newEventQueue(3)
+	ctx := context.Background()
+
+	// The first event is being sent, and holds up the ones published after it.
+	a.publish(ctx, Event{Type: EventDeployment}, 1, 0)
+	deadline := time.Now().Add(5 * time.Second)
+	for store.lookups.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the first event to be sent")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	for range 5 {
+		a.publish(ctx, Event{Type: EventDeployment}, 1, 0)
+	}
+	a.events.mu.Lock()
+	queued := len(a.events.pending)
+	a.events.mu.Unlock()
+	if queued != 3 {
+		t.Fatalf("expected the queue to hold 3 events, got %d", queued)
+	}
+
+	close(store.release)
+	a.waitForEvents()
+	if n := store.lookups.Load(); n != 4 {
+		t.Fatalf("expected the first event and the 3 queued ones to be sent, got %d", n)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	applicationpb "overseer/api-go/application/v1"
+)
+
+type applicationView struct {
+	Id        int32  `json:"id" yaml:"id"`
+	Name      string `json:"name" yaml:"name"`
+	SortOrder int32  `json:"sort_order" yaml:"sort_order"`
+}
+
+func applicationsCmd(ctx context.Context, c *client, out *output, args []string) error {
+	sub, args := subcommand(args, "list")
+
+	switch sub {
+	case "list", "ls":
+		resp, err := c.applications.List(ctx, &applicationpb.ListRequest{})
+		if err != nil {
+			return err
+		}
+
+		views := make([]applicationView, 0, len(resp.Applications))
+		rows := make([][]string, 0, len(resp.Applications))
+		for _, app := range resp.Applications {
+			views = append(views, applicationView{Id: app.Id, Name: app.Name, SortOrder: app.SortOrder})
+			rows = append(rows, []string{strconv.Itoa(int(app.Id)), app.Name, strconv.Itoa(int(app.SortOrder))})
+		}
+
+		return out.print(views, []string{"ID", "NAME", "ORDER"}, rows)
+
+	case "create":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: applications create <name>")
+		}
+
+		resp, err := c.applications.Create(ctx, &applicationpb.CreateRequest{Name: args[0]})
+		if err != nil {
+			return err
+		}
+
+		return out.done("created application %q with id %d", args[0], resp.Id)
+
+	case "update", "rename":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: applications update <id|name> <new-name>")
+		}
+
+		app, err := c.resolveApplication(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.applications.Update(ctx, &applicationpb.UpdateRequest{Id: app.Id, Name: &args[1]}); err != nil {
+			return err
+		}
+
+		return out.done("renamed application %q to %q", app.Name, args[1])
+
+	case "delete", "rm":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: applications delete <id|name>")
+		}
+
+		app, err := c.resolveApplication(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.applications.Delete(ctx, &applicationpb.DeleteRequest{Id: app.Id}); err != nil {
+			return err
+		}
+
+		return out.done("deleted application %q", app.Name)
+
+	case "reorder":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: applications reorder <id|name>...")
+		}
+
+		var ids []int32
+		for _, ref := range args {
+			app, err := c.resolveApplication(ctx, ref)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, app.Id)
+		}
+
+		if _, err := c.applications.SetSortOrder(ctx, &applicationpb.SetSortOrderRequest{IdsInOrder: ids}); err != nil {
+			return err
+		}
+
+		return out.done("reordered %d applications", len(ids))
+
+	default:
+		return fmt.Errorf("unknown applications command %q", sub)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	deploymentpb "overseer/api-go/deployment/v1"
+)
+
+type changelogView struct {
+	Application string `json:"application" yaml:"application"`
+	From        string `json:"from" yaml:"from"`
+	To          string `json:"to" yaml:"to"`
+	FromCommit  string `json:"from_commit" yaml:"from_commit"`
+	ToCommit    string `json:"to_commit" yaml:"to_commit"`
+	// Commits are the commits in To but not in From, newest first.
+	Commits   []commitView     `json:"commits" yaml:"commits"`
+	Truncated bool             `json:"truncated" yaml:"truncated"`
+	Files     []fileChangeView `json:"files" yaml:"files"`
+	Additions int32            `json:"additions" yaml:"additions"`
+	Deletions int32            `json:"deletions" yaml:"deletions"`
+}
+
+type commitView struct {
+	Hash        string    `json:"hash" yaml:"hash"`
+	Author      string    `json:"author" yaml:"author"`
+	AuthorEmail string    `json:"author_email" yaml:"author_email"`
+	Date        time.Time `json:"date" yaml:"date"`
+	Subject     string    `json:"subject" yaml:"subject"`
+}
+
+type fileChangeView struct {
+	Path      string `json:"path" yaml:"path"`
+	Additions int32  `json:"additions" yaml:"additions"`
+	Deletions int32  `json:"deletions" yaml:"deletions"`
+	Binary    bool   `json:"binary,omitempty" yaml:"binary,omitempty"`
+}
+
+func newChangelogView(application, from, to string, resp *deploymentpb.ChangelogResponse) changelogView {
+	v := changelogView{
+		Application: application,
+		From:        from,
+		To:          to,
+		FromCommit:  resp.FromCommit,
+		ToCommit:    resp.ToCommit,
+		Commits:     []commitView{},
+		Truncated:   resp.Truncated,
+		Files:       []fileChangeView{},
+		Additions:   resp.Additions,
+		Deletions:   resp.Deletions,
+	}
+	for _, c := range resp.Commits {
+		v.Commits = append(v.Commits, commitView{
+			Hash:        c.Hash,
+			Author:      c.Author,
+			AuthorEmail: c.AuthorEmail,
+			Date:        c.Date.AsTime(),
+			Subject:     c.Subject,
+		})
+	}
+	for _, f := range resp.Files {
+		v.Files = append(v.Files, fileChangeView{
+			Path:      f.Path,
+			Additions: f.Additions,
+			Deletions: f.Deletions,
+			Binary:    f.Binary,
+		})
+	}
+	return v
+}
+
+// deployedVersion returns the version of the application currently deployed in the environment.
+func (c *client) deployedVersion(ctx context.Context, envRef, application string) (string, error) {
+	env, err := c.resolveEnvironment(ctx, envRef)
+	if err != nil {
+		return "", err
+	}
+
+	_, matrix, err := c.versionMatrix(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, m := range matrix {
+		if m.Application != application {
+			continue
+		}
+
+		switch version := m.Versions[env.Name]; version {
+		case "":
+			return "", fmt.Errorf("application %q is not deployed in environment %q", application, env.Name)
+		case undeployedState:
+			return "", fmt.Errorf("application %q is undeployed in environment %q", application, env.Name)
+		default:
+			return version, nil
+		}
+	}
+
+	return "", fmt.Errorf("application %q not found", application)
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	applicationpb "overseer/api-go/application/v1"
+	datasourcepb "overseer/api-go/datasource/v1"
+	deploymentpb "overseer/api-go/deployment/v1"
+	environmentpb "overseer/api-go/environment/v1"
+	freezepb "overseer/api-go/freeze/v1"
+	instancepb "overseer/api-go/instance/v1"
+	notificationpb "overseer/api-go/notification/v1"
+	policypb "overseer/api-go/policy/v1"
+	promotionpb "overseer/api-go/promotion/v1"
+	releasepb "overseer/api-go/release/v1"
+	webhookpb "overseer/api-go/webhook/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type client struct {
+	conn *grpc.ClientConn
+
+	environments  environmentpb.EnvironmentServiceClient
+	applications  applicationpb.ApplicationServiceClient
+	instances     instancepb.InstanceServiceClient
+	deployments   deploymentpb.DeploymentServiceClient
+	datasources   datasourcepb.DatasourceServiceClient
+	releases      releasepb.ReleaseServiceClient
+	promotions    promotionpb.PromotionServiceClient
+	policies      policypb.PolicyServiceClient
+	freezes       freezepb.FreezeServiceClient
+	webhooks      webhookpb.WebhookServiceClient
+	notifications notificationpb.NotificationServiceClient
+}
+
+func dial(addr string) (*client, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+
+	return &client{
+		conn:          conn,
+		environments:  environmentpb.NewEnvironmentServiceClient(conn),
+		applications:  applicationpb.NewApplicationServiceClient(conn),
+		instances:     instancepb.NewInstanceServiceClient(conn),
+		deployments:   deploymentpb.NewDeploymentServiceClient(conn),
+		datasources:   datasourcepb.NewDatasourceServiceClient(conn),
+		releases:      releasepb.NewReleaseServiceClient(conn),
+		promotions:    promotionpb.NewPromotionServiceClient(conn),
+		policies:      policypb.NewPolicyServiceClient(conn),
+		freezes:       freezepb.NewFreezeServiceClient(conn),
+		webhooks:      webhookpb.NewWebhookServiceClient(conn),
+		notifications: notificationpb.NewNotificationServiceClient(conn),
+	}, nil
+}
+
+func (c *client) Close() error {
+	return c.conn.Close()
+}
+
+// resolveEnvironment returns the environment identified by ref, which is either its id or its name.
+func (c *client) resolveEnvironment(ctx context.Context, ref string) (*environmentpb.Environment, error) {
+	resp, err := c.environments.List(ctx, &environmentpb.ListRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("listing environments: %w", err)
+	}
+
+	id, isId := parseId(ref)
+	for _, env := range resp.Environments {
+		if (isId && env.Id == id) || env.Name == ref {
+			return env, nil
+		}
+	}
+
+	return nil, fmt.Errorf("environment %q not found", ref)
+}
+
+// resolveApplication returns the application identified by ref, which is either its id or its name.
+func (c *client) resolveApplication(ctx context.Context, ref string) (*applicationpb.Application, error) {
+	resp, err := c.applications.List(ctx, &applicationpb.ListRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("listing applications: %w", err)
+	}
+
+	id, isId := parseId(ref)
+	for _, app := range resp.Applications {
+		if (isId && app.Id == id) || app.Name == ref {
+			return app, nil
+		}
+	}
+
+	return nil, fmt.Errorf("application %q not found", ref)
+}
+
+// resolveInstance returns the instance identified by ref, which is either its id or its name.
+func (c *client) resolveInstance(ctx context.Context, ref string) (*instancepb.Instance, error) {
+	resp, err := c.instances.List(ctx, &instancepb.ListRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("listing instances: %w", err)
+	}
+
+	id, isId := parseId(ref)
+	for _, inst := range resp.Instances {
+		if (isId && inst.Id == id) || inst.Name == ref {
+			return inst, nil
+		}
+	}
+
+	return nil, fmt.Errorf("instance %q not found", ref)
+}
+
+// resolveInstanceFor returns the instance of the application in the environment.
+func (c *client) resolveInstanceFor(ctx context.Context, envRef, appRef string) (*instancepb.Instance, error) {
+	env, err := c.resolveEnvironment(ctx, envRef)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := c.resolveApplication(ctx, appRef)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.instances.List(ctx, &instancepb.ListRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("listing instances: %w", err)
+	}
+
+	for _, inst := range resp.Instances {
+		if inst.EnvironmentId == env.Id && inst.ApplicationId == app.Id {
+			return inst, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no instance of application %q in environment %q", app.Name, env.Name)
+}
+
+func parseId(ref string) (int32, bool) {
+	id, err := strconv.ParseInt(ref, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(id), true
+}
+
+// catalog holds the environments, applications and instances known to the server, in display order.
+type catalog struct {
+	environments []*environmentpb.Environment
+	applications []*applicationpb.Application
+	instances    []*instancepb.Instance
+}
+
+func (c *client) loadCatalog(ctx context.Context) (*catalog, error) {
+	envs, err := c.environments.List(ctx, &environmentpb.ListRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("listing environments: %w", err)
+	}
+
+	apps, err := c.applications.List(ctx, &applicationpb.ListRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("listing applications: %w", err)
+	}
+
+	insts, err := c.instances.List(ctx, &instancepb.ListRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("listing instances: %w", err)
+	}
+
+	return &catalog{
+		environments: envs.Environments,
+		applications: apps.Applications,
+		instances:    insts.Instances,
+	}, nil
+}
+
+func (c *catalog) environmentName(id int32) string {
+	for _, env := range c.environments {
+		if env.Id == id {
+			return env.Name
+		}
+	}
+	return strconv.Itoa(int(id))
+}
+
+func (c *catalog) applicationName(id int32) string {
+	for _, app := range c.applications {
+		if app.Id == id {
+			return app.Name
+		}
+	}
+	return strconv.Itoa(int(id))
+}
+
+func (c *catalog) instance(id int32) *instancepb.Instance {
+	for _, inst := range c.instances {
+		if inst.Id == id {
+			return inst
+		}
+	}
+	return nil
+}
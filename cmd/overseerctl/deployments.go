@@ -0,0 +1,401 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	deploymentpb "overseer/api-go/deployment/v1"
+	instancepb "overseer/api-go/instance/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type deploymentView struct {
+	Instance    string       `json:"instance" yaml:"instance"`
+	Environment string       `json:"environment" yaml:"environment"`
+	Application string       `json:"application" yaml:"application"`
+	Component   string       `json:"component" yaml:"component"`
+	Version     string       `json:"version" yaml:"version"`
+	DeployedAt  time.Time    `json:"deployed_at" yaml:"deployed_at"`
+	ReceivedAt  time.Time    `json:"received_at" yaml:"received_at"`
+	Sequence    int64        `json:"sequence,omitempty" yaml:"sequence,omitempty"`
+	Current     bool         `json:"current" yaml:"current"`
+	Late        bool         `json:"late" yaml:"late"`
+	ClockSkew   bool         `json:"clock_skew" yaml:"clock_skew"`
+	Undeployed  bool         `json:"undeployed" yaml:"undeployed"`
+	Source      string       `json:"source,omitempty" yaml:"source,omitempty"`
+	Freeze      string       `json:"freeze,omitempty" yaml:"freeze,omitempty"`
+	Metadata    metadataView `json:"metadata" yaml:"metadata"`
+}
+
+type metadataView struct {
+	Image       string            `json:"image,omitempty" yaml:"image,omitempty"`
+	ImageDigest string            `json:"image_digest,omitempty" yaml:"image_digest,omitempty"`
+	GitCommit   string            `json:"git_commit,omitempty" yaml:"git_commit,omitempty"`
+	BuildURL    string            `json:"build_url,omitempty" yaml:"build_url,omitempty"`
+	Deployer    string            `json:"deployer,omitempty" yaml:"deployer,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+type rolloutView struct {
+	Instance       string    `json:"instance" yaml:"instance"`
+	Environment    string    `json:"environment" yaml:"environment"`
+	Application    string    `json:"application" yaml:"application"`
+	Component      string    `json:"component" yaml:"component"`
+	Version        string    `json:"version" yaml:"version"`
+	Status         string    `json:"status" yaml:"status"`
+	Description    string    `json:"description,omitempty" yaml:"description,omitempty"`
+	Healthy        int32     `json:"healthy" yaml:"healthy"`
+	Desired        int32     `json:"desired" yaml:"desired"`
+	RunningVersion string    `json:"running_version,omitempty" yaml:"running_version,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at" yaml:"updated_at"`
+}
+
+// progress describes the healthy and desired replicas of the rollout for the table output.
+func (v rolloutView) progress() string {
+	if v.Desired == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", v.Healthy, v.Desired)
+}
+
+// shortCommit abbreviates a git commit hash for the table output.
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
+// flags describes the ordering flags of the deployment for the table output.
+func (v deploymentView) flags() string {
+	var flags []string
+	if v.Undeployed {
+		flags = append(flags, "undeployed")
+	}
+	if v.Current {
+		flags = append(flags, "current")
+	}
+	if v.Late {
+		flags = append(flags, "late")
+	}
+	if v.ClockSkew {
+		flags = append(flags, "clock-skew")
+	}
+	if v.Freeze != "" {
+		flags = append(flags, "freeze:"+v.Freeze)
+	}
+	return strings.Join(flags, ",")
+}
+
+func deploymentsCmd(ctx context.Context, c *client, out *output, args []string) error {
+	sub, args := subcommand(args, "list")
+
+	switch sub {
+	case "list", "ls":
+		fs := flag.NewFlagSet("deployments list", flag.ContinueOnError)
+		instRef := fs.String("instance", "", "only list deployments of this instance id or name")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		cat, err := c.loadCatalog(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.deployments.List(ctx, &deploymentpb.ListRequest{})
+		if err != nil {
+			return err
+		}
+
+		var instanceId int32
+		if *instRef != "" {
+			inst, err := c.resolveInstance(ctx, *instRef)
+			if err != nil {
+				return err
+			}
+			instanceId = inst.Id
+		}
+
+		deployments := resp.Deployments
+		sort.SliceStable(deployments, func(i, j int) bool {
+			return deployments[i].DeployedAt.AsTime().After(deployments[j].DeployedAt.AsTime())
+		})
+
+		views := []deploymentView{}
+		var rows [][]string
+		for _, d := range deployments {
+			if instanceId != 0 && d.InstanceId != instanceId {
+				continue
+			}
+
+			v := cat.deploymentView(d)
+			views = append(views, v)
+			rows = append(rows, []string{v.Instance, v.Environment, v.Application, v.Component, orDash(v.Version), v.DeployedAt.Local().Format(time.DateTime), orDash(shortCommit(v.Metadata.GitCommit)), orDash(v.Metadata.Deployer), orDash(v.Source), orDash(v.flags())})
+		}
+
+		return out.print(views, []string{"INSTANCE", "ENVIRONMENT", "APPLICATION", "COMPONENT", "VERSION", "DEPLOYED AT", "COMMIT", "DEPLOYER", "SOURCE", "FLAGS"}, rows)
+
+	case "register":
+		fs := flag.NewFlagSet("deployments register", flag.ContinueOnError)
+		instRef := fs.String("instance", "", "instance id or name")
+		envRef := fs.String("env", "", "environment id or name, used together with -app instead of -instance")
+		appRef := fs.String("app", "", "application id or name, used together with -env instead of -instance")
+		component := fs.String("component", "", "the deployed component of the instance, defaults to its primary component")
+		version := fs.String("version", "", "the deployed version (required)")
+		at := fs.String("at", "", "time of the deployment in RFC 3339 format, defaults to now")
+		sequence := fs.Int64("sequence", 0, "monotonic sequence number of the deployment, takes precedence over -at for ordering")
+		undeployed := fs.Bool("undeployed", false, "register that the component was stopped or removed instead of a version")
+		overrideFreeze := fs.Bool("override-freeze", false, "register the deployment even during a freeze window that rejects deployments")
+		metadata := &deploymentpb.DeploymentMetadata{}
+		fs.StringVar(&metadata.Image, "image", "", "the deployed image reference")
+		fs.StringVar(&metadata.ImageDigest, "digest", "", "the digest of the deployed image")
+		fs.StringVar(&metadata.GitCommit, "commit", "", "the git commit that was deployed")
+		fs.StringVar(&metadata.BuildUrl, "build-url", "", "link to the CI build of the deployment")
+		fs.StringVar(&metadata.Deployer, "deployer", "", "the person or pipeline making the deployment")
+		fs.Func("label", "a free-form `key=value` label, can be repeated", func(s string) error {
+			key, value, ok := strings.Cut(s, "=")
+			if !ok || key == "" {
+				return fmt.Errorf("expected key=value, got %q", s)
+			}
+			if metadata.Labels == nil {
+				metadata.Labels = map[string]string{}
+			}
+			metadata.Labels[key] = value
+			return nil
+		})
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		if (*version == "") != *undeployed || (*instRef == "") == (*envRef == "" || *appRef == "") {
+			return fmt.Errorf("usage: deployments register (-instance <instance> | -env <env> -app <app>) [-component <component>] (-version <version> | -undeployed) [-at <time>] [-sequence <n>] [-override-freeze]")
+		}
+
+		req := &deploymentpb.RegisterRequest{Version: *version, Component: *component, Sequence: *sequence, Metadata: metadata, Undeployed: *undeployed, OverrideFreeze: *overrideFreeze}
+
+		if *at != "" {
+			t, err := time.Parse(time.RFC3339, *at)
+			if err != nil {
+				return fmt.Errorf("parsing -at: %w", err)
+			}
+			req.DeployedAt = timestamppb.New(t)
+		}
+
+		var inst *instancepb.Instance
+		var err error
+		if *instRef != "" {
+			inst, err = c.resolveInstance(ctx, *instRef)
+		} else {
+			inst, err = c.resolveInstanceFor(ctx, *envRef, *appRef)
+		}
+		if err != nil {
+			return err
+		}
+		req.InstanceId = inst.Id
+
+		resp, err := c.deployments.Register(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		// Deployments during a freeze window that does not reject them are flagged with it.
+		var during string
+		if freeze := resp.Deployment.GetFreeze(); freeze != "" {
+			during = fmt.Sprintf(" during the freeze %q", freeze)
+		}
+
+		if *undeployed {
+			if resp.Deployment.GetLate() {
+				return out.done("registered the undeployment of instance %q%s as late, a later deployment is already current", inst.Name, during)
+			}
+			return out.done("registered the undeployment of instance %q%s", inst.Name, during)
+		}
+		if resp.Deployment.GetLate() {
+			return out.done("registered version %s on instance %q%s as late, a later deployment is already current", *version, inst.Name, during)
+		}
+		return out.done("registered version %s on instance %q%s", *version, inst.Name, during)
+
+	case "rollouts":
+		fs := flag.NewFlagSet("deployments rollouts", flag.ContinueOnError)
+		instRef := fs.String("instance", "", "only list the rollouts of this instance id or name")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		cat, err := c.loadCatalog(ctx)
+		if err != nil {
+			return err
+		}
+
+		var instanceId int32
+		if *instRef != "" {
+			inst, err := c.resolveInstance(ctx, *instRef)
+			if err != nil {
+				return err
+			}
+			instanceId = inst.Id
+		}
+
+		resp, err := c.deployments.ListRollouts(ctx, &deploymentpb.ListRolloutsRequest{})
+		if err != nil {
+			return err
+		}
+
+		views := []rolloutView{}
+		var rows [][]string
+		for _, r := range resp.Rollouts {
+			if instanceId != 0 && r.InstanceId != instanceId {
+				continue
+			}
+
+			v := cat.rolloutView(r)
+			views = append(views, v)
+			rows = append(rows, []string{v.Instance, v.Component, v.Version, v.Status, v.progress(), orDash(v.RunningVersion), v.UpdatedAt.Local().Format(time.DateTime), orDash(v.Description)})
+		}
+
+		return out.print(views, []string{"INSTANCE", "COMPONENT", "VERSION", "STATUS", "HEALTHY", "RUNNING", "UPDATED AT", "DESCRIPTION"}, rows)
+
+	case "changelog":
+		fs := flag.NewFlagSet("deployments changelog", flag.ContinueOnError)
+		appRef := fs.String("app", "", "application id or name (required)")
+		from := fs.String("from", "", "the version to compare from")
+		to := fs.String("to", "", "the version to compare to")
+		fromEnv := fs.String("from-env", "", "compare from the version deployed in this environment instead of -from")
+		toEnv := fs.String("to-env", "", "compare to the version deployed in this environment instead of -to")
+		maxCommits := fs.Int("max-commits", 0, "the maximum number of commits to list, defaults to the server default")
+		files := fs.Bool("files", false, "list the changed files instead of the commits")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		if *appRef == "" {
+			return fmt.Errorf("usage: deployments changelog -app <app> (-from <version> | -from-env <env>) (-to <version> | -to-env <env>) [-files]")
+		}
+
+		app, err := c.resolveApplication(ctx, *appRef)
+		if err != nil {
+			return err
+		}
+
+		if *fromEnv != "" {
+			if *from, err = c.deployedVersion(ctx, *fromEnv, app.Name); err != nil {
+				return err
+			}
+		}
+		if *toEnv != "" {
+			if *to, err = c.deployedVersion(ctx, *toEnv, app.Name); err != nil {
+				return err
+			}
+		}
+		if *from == "" || *to == "" {
+			return fmt.Errorf("both versions to compare are required")
+		}
+
+		resp, err := c.deployments.Changelog(ctx, &deploymentpb.ChangelogRequest{
+			ApplicationId: app.Id,
+			From:          *from,
+			To:            *to,
+			MaxCommits:    int32(*maxCommits),
+		})
+		if err != nil {
+			return err
+		}
+
+		v := newChangelogView(app.Name, *from, *to, resp)
+
+		var rows [][]string
+		if *files {
+			for _, f := range v.Files {
+				added, deleted := fmt.Sprint(f.Additions), fmt.Sprint(f.Deletions)
+				if f.Binary {
+					added, deleted = "-", "-"
+				}
+				rows = append(rows, []string{f.Path, added, deleted})
+			}
+			return out.print(v, []string{"PATH", "ADDED", "DELETED"}, rows)
+		}
+
+		for _, commit := range v.Commits {
+			rows = append(rows, []string{shortCommit(commit.Hash), commit.Date.Local().Format(time.DateTime), commit.Author, commit.Subject})
+		}
+		if v.Truncated {
+			rows = append(rows, []string{"...", "", "", fmt.Sprintf("more commits than the %d listed", len(v.Commits))})
+		}
+		return out.print(v, []string{"COMMIT", "DATE", "AUTHOR", "SUBJECT"}, rows)
+
+	default:
+		return fmt.Errorf("unknown deployments command %q", sub)
+	}
+}
+
+func (c *catalog) deploymentView(d *deploymentpb.Deployment) deploymentView {
+	v := deploymentView{
+		Instance:   fmt.Sprint(d.InstanceId),
+		Component:  d.Component,
+		Version:    d.Version,
+		DeployedAt: d.DeployedAt.AsTime(),
+		ReceivedAt: d.ReceivedAt.AsTime(),
+		Sequence:   d.Sequence,
+		Current:    d.Current,
+		Late:       d.Late,
+		ClockSkew:  d.ClockSkew,
+		Undeployed: d.Undeployed,
+		Source:     d.Source,
+		Freeze:     d.Freeze,
+		Metadata: metadataView{
+			Image:       d.Metadata.GetImage(),
+			ImageDigest: d.Metadata.GetImageDigest(),
+			GitCommit:   d.Metadata.GetGitCommit(),
+			BuildURL:    d.Metadata.GetBuildUrl(),
+			Deployer:    d.Metadata.GetDeployer(),
+			Labels:      d.Metadata.GetLabels(),
+		},
+	}
+
+	if inst := c.instance(d.InstanceId); inst != nil {
+		v.Instance = inst.Name
+		v.Environment = c.environmentName(inst.EnvironmentId)
+		v.Application = c.applicationName(inst.ApplicationId)
+	}
+
+	return v
+}
+
+func (c *catalog) rolloutView(r *deploymentpb.Rollout) rolloutView {
+	v := rolloutView{
+		Instance:       fmt.Sprint(r.InstanceId),
+		Component:      r.Component,
+		Version:        r.Version,
+		Status:         r.Status,
+		Description:    r.Description,
+		Healthy:        r.Healthy,
+		Desired:        r.Desired,
+		RunningVersion: r.RunningVersion,
+		UpdatedAt:      r.UpdatedAt.AsTime(),
+	}
+
+	if inst := c.instance(r.InstanceId); inst != nil {
+		v.Instance = inst.Name
+		v.Environment = c.environmentName(inst.EnvironmentId)
+		v.Application = c.applicationName(inst.ApplicationId)
+	}
+
+	return v
+}
+
+// latestDeployments returns the current deployment of the primary component of every instance, keyed by the instance id.
+func (c *catalog) latestDeployments(deployments []*deploymentpb.Deployment) map[int32]*deploymentpb.Deployment {
+	latest := make(map[int32]*deploymentpb.Deployment)
+	for _, d := range deployments {
+		if inst := c.instance(d.InstanceId); d.Current && inst != nil && d.Component == inst.PrimaryComponent {
+			latest[d.InstanceId] = d
+		}
+	}
+	return latest
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	environmentpb "overseer/api-go/environment/v1"
+)
+
+type environmentView struct {
+	Id        int32  `json:"id" yaml:"id"`
+	Name      string `json:"name" yaml:"name"`
+	SortOrder int32  `json:"sort_order" yaml:"sort_order"`
+}
+
+func environmentsCmd(ctx context.Context, c *client, out *output, args []string) error {
+	sub, args := subcommand(args, "list")
+
+	switch sub {
+	case "list", "ls":
+		resp, err := c.environments.List(ctx, &environmentpb.ListRequest{})
+		if err != nil {
+			return err
+		}
+
+		views := make([]environmentView, 0, len(resp.Environments))
+		rows := make([][]string, 0, len(resp.Environments))
+		for _, env := range resp.Environments {
+			views = append(views, environmentView{Id: env.Id, Name: env.Name, SortOrder: env.SortOrder})
+			rows = append(rows, []string{strconv.Itoa(int(env.Id)), env.Name, strconv.Itoa(int(env.SortOrder))})
+		}
+
+		return out.print(views, []string{"ID", "NAME", "ORDER"}, rows)
+
+	case "create":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: environments create <name>")
+		}
+
+		resp, err := c.environments.Create(ctx, &environmentpb.CreateRequest{Name: args[0]})
+		if err != nil {
+			return err
+		}
+
+		return out.done("created environment %q with id %d", args[0], resp.Id)
+
+	case "update", "rename":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: environments update <id|name> <new-name>")
+		}
+
+		env, err := c.resolveEnvironment(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.environments.Update(ctx, &environmentpb.UpdateRequest{Id: env.Id, Name: &args[1]}); err != nil {
+			return err
+		}
+
+		return out.done("renamed environment %q to %q", env.Name, args[1])
+
+	case "delete", "rm":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: environments delete <id|name>")
+		}
+
+		env, err := c.resolveEnvironment(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.environments.Delete(ctx, &environmentpb.DeleteRequest{Id: env.Id}); err != nil {
+			return err
+		}
+
+		return out.done("deleted environment %q", env.Name)
+
+	case "reorder":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: environments reorder <id|name>...")
+		}
+
+		var ids []int32
+		for _, ref := range args {
+			env, err := c.resolveEnvironment(ctx, ref)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, env.Id)
+		}
+
+		if _, err := c.environments.SetSortOrder(ctx, &environmentpb.SetSortOrderRequest{IdsInOrder: ids}); err != nil {
+			return err
+		}
+
+		return out.done("reordered %d environments", len(ids))
+
+	default:
+		return fmt.Errorf("unknown environments command %q", sub)
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	freezepb "overseer/api-go/freeze/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type freezeView struct {
+	Id          int32     `json:"id" yaml:"id"`
+	Name        string    `json:"name" yaml:"name"`
+	Reason      string    `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Environment string    `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Application string    `json:"application,omitempty" yaml:"application,omitempty"`
+	StartsAt    time.Time `json:"starts_at,omitzero" yaml:"starts_at,omitempty"`
+	EndsAt      time.Time `json:"ends_at,omitzero" yaml:"ends_at,omitempty"`
+	Schedule    string    `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	Duration    string    `json:"duration,omitempty" yaml:"duration,omitempty"`
+	TimeZone    string    `json:"time_zone" yaml:"time_zone"`
+	Reject      bool      `json:"reject" yaml:"reject"`
+	Active      bool      `json:"active" yaml:"active"`
+	ActiveUntil time.Time `json:"active_until,omitzero" yaml:"active_until,omitempty"`
+	NextStart   time.Time `json:"next_start,omitzero" yaml:"next_start,omitempty"`
+}
+
+// when describes when the freeze window applies for the table output.
+func (v freezeView) when() string {
+	if v.Schedule != "" {
+		return fmt.Sprintf("%q for %s (%s)", v.Schedule, v.Duration, v.TimeZone)
+	}
+	return v.StartsAt.Local().Format(time.DateTime) + " - " + v.EndsAt.Local().Format(time.DateTime)
+}
+
+// state describes whether the freeze window is active for the table output.
+func (v freezeView) state() string {
+	switch {
+	case v.Active:
+		return "active until " + v.ActiveUntil.Local().Format(time.DateTime)
+	case !v.NextStart.IsZero():
+		return "starts " + v.NextStart.Local().Format(time.DateTime)
+	default:
+		return "ended"
+	}
+}
+
+func freezesCmd(ctx context.Context, c *client, out *output, args []string) error {
+	sub, args := subcommand(args, "list")
+
+	switch sub {
+	case "list", "ls":
+		if len(args) != 0 {
+			return fmt.Errorf("usage: freezes list")
+		}
+
+		cat, err := c.loadCatalog(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.freezes.List(ctx, &freezepb.ListRequest{})
+		if err != nil {
+			return err
+		}
+
+		views := make([]freezeView, 0, len(resp.Windows))
+		rows := make([][]string, 0, len(resp.Windows))
+		for _, w := range resp.Windows {
+			v := freezeView{
+				Id:          w.Id,
+				Name:        w.Name,
+				Reason:      w.Reason,
+				Environment: cat.environmentName(w.EnvironmentId),
+				Application: cat.applicationName(w.ApplicationId),
+				Schedule:    w.Schedule,
+				TimeZone:    w.TimeZone,
+				Reject:      w.Reject,
+				Active:      w.Active,
+			}
+			if w.EnvironmentId == 0 {
+				v.Environment = ""
+			}
+			if w.ApplicationId == 0 {
+				v.Application = ""
+			}
+			if w.DurationSeconds != 0 {
+				v.Duration = (time.Duration(w.DurationSeconds) * time.Second).String()
+			}
+			if w.StartsAt != nil {
+				v.StartsAt = w.StartsAt.AsTime()
+			}
+			if w.EndsAt != nil {
+				v.EndsAt = w.EndsAt.AsTime()
+			}
+			if w.ActiveUntil != nil {
+				v.ActiveUntil = w.ActiveUntil.AsTime()
+			}
+			if w.NextStart != nil {
+				v.NextStart = w.NextStart.AsTime()
+			}
+			views = append(views, v)
+
+			action := "flag"
+			if v.Reject {
+				action = "reject"
+			}
+			rows = append(rows, []string{fmt.Sprint(v.Id), v.Name, orDash(v.Environment), orDash(v.Application), v.when(), action, v.state(), orDash(v.Reason)})
+		}
+
+		return out.print(views, []string{"ID", "NAME", "ENVIRONMENT", "APPLICATION", "WHEN", "ACTION", "STATE", "REASON"}, rows)
+
+	case "create":
+		window := &freezepb.FreezeWindow{}
+		if err := c.parseFreezeFlags(ctx, "freezes create", window, args); err != nil {
+			return err
+		}
+
+		resp, err := c.freezes.Create(ctx, &freezepb.CreateRequest{Window: window})
+		if err != nil {
+			return err
+		}
+
+		return out.done("created freeze window %q with id %d", resp.Window.Name, resp.Window.Id)
+
+	case "update":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: freezes update <id|name> [flags]")
+		}
+
+		window, err := c.resolveFreeze(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if err := c.parseFreezeFlags(ctx, "freezes update", window, args[1:]); err != nil {
+			return err
+		}
+
+		resp, err := c.freezes.Update(ctx, &freezepb.UpdateRequest{Window: window})
+		if err != nil {
+			return err
+		}
+
+		return out.done("updated freeze window %q", resp.Window.Name)
+
+	case "delete", "rm":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: freezes delete <id|name>")
+		}
+
+		window, err := c.resolveFreeze(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.freezes.Delete(ctx, &freezepb.DeleteRequest{Id: window.Id}); err != nil {
+			return err
+		}
+
+		return out.done("deleted freeze window %q", window.Name)
+
+	default:
+		return fmt.Errorf("unknown freezes command %q", sub)
+	}
+}
+
+// parseFreezeFlags parses the flags of a freeze window into it, the flags that are not given keep their values.
+// Giving the start or end of a one-off window replaces the schedule of a recurring one, and the other way around.
+func (c *client) parseFreezeFlags(ctx context.Context, name string, w *freezepb.FreezeWindow, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&w.Name, "name", w.Name, "the name of the freeze window")
+	fs.StringVar(&w.Reason, "reason", w.Reason, "why deployments are frozen")
+	envRef := fs.String("env", "", `only freeze this environment id or name, "" for all environments`)
+	appRef := fs.String("app", "", `only freeze this application id or name, "" for all applications`)
+	from := fs.String("from", "", "start of a one-off window in RFC 3339 format")
+	until := fs.String("until", "", "end of a one-off window in RFC 3339 format")
+	fs.StringVar(&w.Schedule, "schedule", w.Schedule, `cron expression for the starts of a recurring window, like "0 18 * * FRI"`)
+	duration := fs.Duration("duration", time.Duration(w.DurationSeconds)*time.Second, "how long a recurring window lasts, like 62h")
+	fs.StringVar(&w.TimeZone, "tz", w.TimeZone, "the time zone of the schedule, like Europe/Stockholm, defaults to UTC")
+	fs.BoolVar(&w.Reject, "reject", w.Reject, "reject deployments during the freeze instead of only flagging them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments %q", fs.Args())
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if set["env"] {
+		w.EnvironmentId = 0
+		if *envRef != "" {
+			env, err := c.resolveEnvironment(ctx, *envRef)
+			if err != nil {
+				return err
+			}
+			w.EnvironmentId = env.Id
+		}
+	}
+	if set["app"] {
+		w.ApplicationId = 0
+		if *appRef != "" {
+			app, err := c.resolveApplication(ctx, *appRef)
+			if err != nil {
+				return err
+			}
+			w.ApplicationId = app.Id
+		}
+	}
+
+	if set["from"] || set["until"] {
+		w.Schedule, *duration = "", 0
+	}
+	if set["schedule"] || set["duration"] {
+		w.StartsAt, w.EndsAt = nil, nil
+	}
+	w.DurationSeconds = int64(duration.Seconds())
+
+	if set["from"] {
+		t, err := time.Parse(time.RFC3339, *from)
+		if err != nil {
+			return fmt.Errorf("parsing -from: %w", err)
+		}
+		w.StartsAt = timestamppb.New(t)
+	}
+	if set["until"] {
+		t, err := time.Parse(time.RFC3339, *until)
+		if err != nil {
+			return fmt.Errorf("parsing -until: %w", err)
+		}
+		w.EndsAt = timestamppb.New(t)
+	}
+
+	return nil
+}
+
+// resolveFreeze returns the freeze window identified by ref, which is either its id or its name.
+func (c *client) resolveFreeze(ctx context.Context, ref string) (*freezepb.FreezeWindow, error) {
+	resp, err := c.freezes.List(ctx, &freezepb.ListRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	id, isId := parseId(ref)
+	for _, w := range resp.Windows {
+		if (isId && w.Id == id) || w.Name == ref {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("freeze window %q not found", ref)
+}
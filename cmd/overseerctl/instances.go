@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	deploymentpb "overseer/api-go/deployment/v1"
+	instancepb "overseer/api-go/instance/v1"
+)
+
+type instanceView struct {
+	Id          int32  `json:"id" yaml:"id"`
+	Name        string `json:"name" yaml:"name"`
+	Environment string `json:"environment" yaml:"environment"`
+	Application string `json:"application" yaml:"application"`
+	Primary     string `json:"primary_component" yaml:"primary_component"`
+}
+
+type componentView struct {
+	Component  string    `json:"component" yaml:"component"`
+	Primary    bool      `json:"primary" yaml:"primary"`
+	Version    string    `json:"version" yaml:"version"`
+	Undeployed bool      `json:"undeployed" yaml:"undeployed"`
+	DeployedAt time.Time `json:"deployed_at" yaml:"deployed_at"`
+	// Rollout is the rollout of the component, if the source reports them.
+	Rollout *rolloutView `json:"rollout,omitempty" yaml:"rollout,omitempty"`
+}
+
+func instancesCmd(ctx context.Context, c *client, out *output, args []string) error {
+	sub, args := subcommand(args, "list")
+
+	switch sub {
+	case "list", "ls":
+		fs := flag.NewFlagSet("instances list", flag.ContinueOnError)
+		name := fs.String("name", "", "only list the instance with this name")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		resp, err := c.instances.List(ctx, &instancepb.ListRequest{Name: *name})
+		if err != nil {
+			return err
+		}
+
+		cat, err := c.loadCatalog(ctx)
+		if err != nil {
+			return err
+		}
+
+		views := make([]instanceView, 0, len(resp.Instances))
+		rows := make([][]string, 0, len(resp.Instances))
+		for _, inst := range resp.Instances {
+			v := instanceView{
+				Id:          inst.Id,
+				Name:        inst.Name,
+				Environment: cat.environmentName(inst.EnvironmentId),
+				Application: cat.applicationName(inst.ApplicationId),
+				Primary:     inst.PrimaryComponent,
+			}
+			views = append(views, v)
+			rows = append(rows, []string{strconv.Itoa(int(v.Id)), v.Name, v.Environment, v.Application, v.Primary})
+		}
+
+		return out.print(views, []string{"ID", "NAME", "ENVIRONMENT", "APPLICATION", "PRIMARY"}, rows)
+
+	case "create":
+		fs := flag.NewFlagSet("instances create", flag.ContinueOnError)
+		envRef := fs.String("env", "", "environment id or name (required)")
+		appRef := fs.String("app", "", "application id or name (required)")
+		name := fs.String("name", "", "name of the instance, as reported by the datasource (required)")
+		primary := fs.String("primary", "", "the component shown in the version matrix, defaults to main")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		if *envRef == "" || *appRef == "" || *name == "" {
+			return fmt.Errorf("usage: instances create -env <env> -app <app> -name <name> [-primary <component>]")
+		}
+
+		env, err := c.resolveEnvironment(ctx, *envRef)
+		if err != nil {
+			return err
+		}
+
+		app, err := c.resolveApplication(ctx, *appRef)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.instances.Create(ctx, &instancepb.CreateRequest{
+			EnvironmentId:    env.Id,
+			ApplicationId:    app.Id,
+			Name:             *name,
+			PrimaryComponent: *primary,
+		})
+		if err != nil {
+			return err
+		}
+
+		return out.done("created instance %q with id %d", *name, resp.Id)
+
+	case "update", "rename":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: instances update <id|name> <new-name>")
+		}
+
+		inst, err := c.resolveInstance(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.instances.Update(ctx, &instancepb.UpdateRequest{Id: inst.Id, Name: args[1]}); err != nil {
+			return err
+		}
+
+		return out.done("renamed instance %q to %q", inst.Name, args[1])
+
+	case "set-primary":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: instances set-primary <id|name> <component>")
+		}
+
+		inst, err := c.resolveInstance(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.instances.Update(ctx, &instancepb.UpdateRequest{Id: inst.Id, Name: inst.Name, PrimaryComponent: args[1]}); err != nil {
+			return err
+		}
+
+		return out.done("set the primary component of instance %q to %q", inst.Name, args[1])
+
+	case "components":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: instances components <id|name>")
+		}
+
+		inst, err := c.resolveInstance(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		cat, err := c.loadCatalog(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.deployments.List(ctx, &deploymentpb.ListRequest{})
+		if err != nil {
+			return err
+		}
+
+		rolloutsResp, err := c.deployments.ListRollouts(ctx, &deploymentpb.ListRolloutsRequest{})
+		if err != nil {
+			return err
+		}
+
+		rollouts := map[string]rolloutView{}
+		for _, r := range rolloutsResp.Rollouts {
+			if r.InstanceId == inst.Id {
+				rollouts[r.Component] = cat.rolloutView(r)
+			}
+		}
+
+		views := []componentView{}
+		for _, d := range resp.Deployments {
+			if d.InstanceId == inst.Id && d.Current {
+				v := componentView{
+					Component:  d.Component,
+					Primary:    d.Component == inst.PrimaryComponent,
+					Version:    d.Version,
+					Undeployed: d.Undeployed,
+					DeployedAt: d.DeployedAt.AsTime(),
+				}
+				if r, ok := rollouts[d.Component]; ok {
+					v.Rollout = &r
+				}
+				views = append(views, v)
+			}
+		}
+		sort.Slice(views, func(i, j int) bool { return views[i].Component < views[j].Component })
+
+		rows := make([][]string, 0, len(views))
+		for _, v := range views {
+			primary := "-"
+			if v.Primary {
+				primary = "yes"
+			}
+
+			running, rollout := "-", "-"
+			if r := v.Rollout; r != nil {
+				running = orDash(r.RunningVersion)
+				rollout = r.Status + " " + r.Version
+				if r.Desired != 0 {
+					rollout += " " + r.progress()
+				}
+			}
+
+			rows = append(rows, []string{v.Component, primary, versionOrState(v.Version, v.Undeployed), running, rollout, v.DeployedAt.Local().Format(time.DateTime)})
+		}
+
+		return out.print(views, []string{"COMPONENT", "PRIMARY", "DESIRED", "RUNNING", "ROLLOUT", "DEPLOYED AT"}, rows)
+
+	case "delete", "rm":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: instances delete <id|name>")
+		}
+
+		inst, err := c.resolveInstance(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.instances.Delete(ctx, &instancepb.DeleteRequest{Id: inst.Id}); err != nil {
+			return err
+		}
+
+		return out.done("deleted instance %q", inst.Name)
+
+	default:
+		return fmt.Errorf("unknown instances command %q", sub)
+	}
+}
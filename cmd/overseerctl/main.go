@@ -0,0 +1,132 @@
+// Command overseerctl is a command-line client for the Overseer gRPC API.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const usage = `Usage: overseerctl [flags] <command> [arguments]
+
+Commands:
+  environments  list, create, update, delete and reorder environments
+  applications  list, create, update, delete and reorder applications
+  instances     list, create, update and delete instances and show their components
+  deployments   list and register deployments, show their rollouts and changelogs
+  sources       list, enable, disable and restart the sources of deployment events
+  releases      list the latest releases and the instances they are available to
+  promotions    set the promotion graph and list the versions awaiting promotion and their history
+  policies      list, set and delete version policies and list their violations
+  freezes       list, create, update and delete deployment freeze windows
+  webhooks      manage the webhooks notified of events, list their deliveries and redeliver them
+  notifications manage the chat channels notified of events and send them test messages
+  matrix        show the currently deployed version of every instance
+  diff          compare the deployed versions of two environments
+
+Flags:
+`
+
+type command func(ctx context.Context, c *client, out *output, args []string) error
+
+var commands = map[string]command{
+	"environments":  environmentsCmd,
+	"environment":   environmentsCmd,
+	"env":           environmentsCmd,
+	"applications":  applicationsCmd,
+	"application":   applicationsCmd,
+	"app":           applicationsCmd,
+	"instances":     instancesCmd,
+	"instance":      instancesCmd,
+	"inst":          instancesCmd,
+	"deployments":   deploymentsCmd,
+	"deployment":    deploymentsCmd,
+	"deploy":        deploymentsCmd,
+	"sources":       sourcesCmd,
+	"source":        sourcesCmd,
+	"releases":      releasesCmd,
+	"release":       releasesCmd,
+	"promotions":    promotionsCmd,
+	"promotion":     promotionsCmd,
+	"policies":      policiesCmd,
+	"policy":        policiesCmd,
+	"freezes":       freezesCmd,
+	"freeze":        freezesCmd,
+	"webhooks":      webhooksCmd,
+	"webhook":       webhooksCmd,
+	"notifications": notificationsCmd,
+	"notification":  notificationsCmd,
+	"matrix":        matrixCmd,
+	"diff":          diffCmd,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command line args, writing the output of the command to stdout and the usage to stderr.
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("overseerctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+
+	addr := fs.String("addr", envOr("OVERSEER_ADDR", "localhost:9090"), "address of the Overseer gRPC server (env OVERSEER_ADDR)")
+	format := fs.String("o", "table", "output format: table, json or yaml")
+	timeout := fs.Duration("timeout", 10*time.Second, "timeout for the whole command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no command given")
+	}
+
+	out, err := newOutput(stdout, *format)
+	if err != nil {
+		return err
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	c, err := dial(*addr)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	return cmd(ctx, c, out, fs.Args()[1:])
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// subcommand splits the arguments of a command into the name of the subcommand and its arguments.
+// If no subcommand is given, def is returned.
+func subcommand(args []string, def string) (string, []string) {
+	if len(args) == 0 {
+		return def, nil
+	}
+	return args[0], args[1:]
+}
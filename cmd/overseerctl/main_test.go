@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+
+	applicationpb "overseer/api-go/application/v1"
+	deploymentpb "overseer/api-go/deployment/v1"
+	environmentpb "overseer/api-go/environment/v1"
+	instancepb "overseer/api-go/instance/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// environments, applications, instances and deployments serve a fixed catalog: the environments dev and prod,
+// the applications api and web, api deployed in both environments and web undeployed in dev.
+type environments struct {
+	environmentpb.UnimplementedEnvironmentServiceServer
+
+	// created are the names of the environments created through the server.
+	created []string
+}
+
+func (s *environments) List(ctx context.Context, req *environmentpb.ListRequest) (*environmentpb.ListResponse, error) {
+	return &environmentpb.ListResponse{Environments: []*environmentpb.Environment{
+		{Id: 1, Name: "dev", SortOrder: 0},
+		{Id: 2, Name: "prod", SortOrder: 1},
+	}}, nil
+}
+
+func (s *environments) Create(ctx context.Context, req *environmentpb.CreateRequest) (*environmentpb.CreateResponse, error) {
+	if req.Name == "dev" {
+		return nil, status.Error(codes.AlreadyExists, "the environment dev already exists")
+	}
+	s.created = append(s.created, req.Name)
+	return &environmentpb.CreateResponse{Id: 3}, nil
+}
+
+type applications struct {
+	applicationpb.UnimplementedApplicationServiceServer
+}
+
+func (applications) List(ctx context.Context, req *applicationpb.ListRequest) (*applicationpb.ListResponse, error) {
+	return &applicationpb.ListResponse{Applications: []*applicationpb.Application{
+		{Id: 1, Name: "api", SortOrder: 0},
+		{Id: 2, Name: "web", SortOrder: 1},
+	}}, nil
+}
+
+type instances struct {
+	instancepb.UnimplementedInstanceServiceServer
+}
+
+func (instances) List(ctx context.Context, req *instancepb.ListRequest) (*instancepb.ListResponse, error) {
+	return &instancepb.ListResponse{Instances: []*instancepb.Instance{
+		{Id: 1, EnvironmentId: 1, ApplicationId: 1, Name: "api-dev", PrimaryComponent: "main"},
+		{Id: 2, EnvironmentId: 2, ApplicationId: 1, Name: "api-prod", PrimaryComponent: "main"},
+		{Id: 3, EnvironmentId: 1, ApplicationId: 2, Name: "web-dev", PrimaryComponent: "main"},
+	}}, nil
+}
+
+type deployments struct {
+	deploymentpb.UnimplementedDeploymentServiceServer
+}
+
+func (deployments) List(ctx context.Context, req *deploymentpb.ListRequest) (*deploymentpb.ListResponse, error) {
+	return &deploymentpb.ListResponse{Deployments: []*deploymentpb.Deployment{
+		{Id: "a", InstanceId: 1, Component: "main", Version: "1.1.0"},
+		{Id: "b", InstanceId: 1, Component: "main", Version: "1.2.0", Current: true},
+		{Id: "c", InstanceId: 1, Component: "migrator", Version: "1.3.0", Current: true},
+		{Id: "d", InstanceId: 2, Component: "main", Version: "1.1.0", Current: true},
+		{Id: "e", InstanceId: 3, Component: "main", Undeployed: true, Current: true},
+	}}, nil
+}
+
+// serve serves the catalog on a local port and returns its address.
+func serve(t *testing.T, s *environments) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	environmentpb.RegisterEnvironmentServiceServer(server, s)
+	applicationpb.RegisterApplicationServiceServer(server, applications{})
+	instancepb.RegisterInstanceServiceServer(server, instances{})
+	deploymentpb.RegisterDeploymentServiceServer(server, deployments{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestRun(t *testing.T) {
+	s := &environments{}
+	addr := serve(t, s)
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "table",
+			args: []string{"environments"},
+			want: `ID  NAME  ORDER
+1   dev   0
+2   prod  1
+`,
+		},
+		{
+			name: "alias and explicit subcommand",
+			args: []string{"env", "ls"},
+			want: `ID  NAME  ORDER
+1   dev   0
+2   prod  1
+`,
+		},
+		{
+			name: "json",
+			args: []string{"-o", "json", "applications", "list"},
+			want: `[
+  {
+    "id": 1,
+    "name": "api",
+    "sort_order": 0
+  },
+  {
+    "id": 2,
+    "name": "web",
+    "sort_order": 1
+  }
+]
+`,
+		},
+		{
+			name: "yaml",
+			args: []string{"-o", "yaml", "env"},
+			want: `- id: 1
+  name: dev
+  sort_order: 0
+- id: 2
+  name: prod
+  sort_order: 1
+`,
+		},
+		{
+			name: "done",
+			args: []string{"env", "create", "staging"},
+			want: "created environment \"staging\" with id 3\n",
+		},
+		{
+			name: "done as json",
+			args: []string{"-o", "json", "env", "create", "qa"},
+			want: `{
+  "message": "created environment \"qa\" with id 3"
+}
+`,
+		},
+		{
+			name: "matrix",
+			args: []string{"matrix"},
+			want: `APPLICATION  dev         prod
+api          1.2.0       1.1.0
+web          undeployed  -
+`,
+		},
+		{
+			name: "matrix as json",
+			args: []string{"-o", "json", "matrix"},
+			want: `[
+  {
+    "application": "api",
+    "versions": {
+      "dev": "1.2.0",
+      "prod": "1.1.0"
+    }
+  },
+  {
+    "application": "web",
+    "versions": {
+      "dev": "undeployed"
+    }
+  }
+]
+`,
+		},
+		{
+			name: "diff",
+			args: []string{"diff", "prod", "dev"},
+			want: `APPLICATION  prod   dev         STATUS
+api          1.1.0  1.2.0       differs
+web          -      undeployed  undeployed in dev
+`,
+		},
+		{
+			name: "diff by id",
+			args: []string{"diff", "-all", "2", "2"},
+			want: `APPLICATION  prod   prod   STATUS
+api          1.1.0  1.1.0  same
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if err := run(append([]string{"-addr", addr}, tt.args...), &stdout, &stderr); err != nil {
+				t.Fatal(err)
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Fatalf("expected the output\n%s\ngot\n%s", tt.want, got)
+			}
+		})
+	}
+
+	if want := []string{"staging", "qa"}; !slices.Equal(s.created, want) {
+		t.Fatalf("expected the environments %q to be created, got %q", want, s.created)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	addr := serve(t, &environments{})
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+		// usage is set if the usage is expected to be printed.
+		usage bool
+	}{
+		{name: "no command", args: nil, want: "no command given", usage: true},
+		{name: "unknown command", args: []string{"bogus"}, want: `unknown command "bogus"`, usage: true},
+		{name: "unknown flag", args: []string{"-bogus", "env"}, want: "flag provided but not defined: -bogus", usage: true},
+		{name: "unknown format", args: []string{"-o", "xml", "env"}, want: `unknown output format "xml"`},
+		{name: "unknown subcommand", args: []string{"env", "bogus"}, want: `unknown environments command "bogus"`},
+		{name: "missing argument", args: []string{"env", "create"}, want: "usage: environments create <name>"},
+		{name: "unknown subcommand flag", args: []string{"diff", "-bogus", "dev", "prod"}, want: "flag provided but not defined: -bogus"},
+		{name: "unknown environment", args: []string{"env", "rename", "staging", "qa"}, want: `environment "staging" not found`},
+		{name: "server error", args: []string{"env", "create", "dev"}, want: "the environment dev already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := run(append([]string{"-addr", addr}, tt.args...), &stdout, &stderr)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected an error containing %q, got %v", tt.want, err)
+			}
+			if stdout.Len() != 0 {
+				t.Fatalf("expected no output, got %q", stdout.String())
+			}
+			if usage := strings.Contains(stderr.String(), "Usage: overseerctl"); usage != tt.usage {
+				t.Fatalf("expected the usage to be printed: %t, got %q", tt.usage, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunFlags(t *testing.T) {
+	addr := serve(t, &environments{})
+
+	t.Run("address from the environment", func(t *testing.T) {
+		t.Setenv("OVERSEER_ADDR", addr)
+
+		var stdout bytes.Buffer
+		if err := run([]string{"env"}, &stdout, &bytes.Buffer{}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(stdout.String(), "prod") {
+			t.Fatalf("expected the environments to be listed, got %q", stdout.String())
+		}
+	})
+
+	t.Run("address flag overrides the environment", func(t *testing.T) {
+		t.Setenv("OVERSEER_ADDR", "127.0.0.1:1")
+
+		var stdout bytes.Buffer
+		if err := run([]string{"-addr", addr, "env"}, &stdout, &bytes.Buffer{}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(stdout.String(), "prod") {
+			t.Fatalf("expected the environments to be listed, got %q", stdout.String())
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		err := run([]string{"-addr", addr, "-timeout", "1ns", "env"}, &bytes.Buffer{}, &bytes.Buffer{})
+		if status.Code(err) != codes.DeadlineExceeded {
+			t.Fatalf("expected the command to time out, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	deploymentpb "overseer/api-go/deployment/v1"
+)
+
+type matrixRow struct {
+	Application string `json:"application" yaml:"application"`
+	// Versions holds undeployedState for the environments where the application was undeployed,
+	// environments where it was never seen are left out.
+	Versions map[string]string `json:"versions" yaml:"versions"`
+}
+
+// undeployedState is shown instead of a version for components that were stopped or removed.
+const undeployedState = "undeployed"
+
+func versionOrState(version string, undeployed bool) string {
+	if undeployed {
+		return undeployedState
+	}
+	return version
+}
+
+// versionMatrix returns the currently deployed version of every application, keyed by environment name.
+func (c *client) versionMatrix(ctx context.Context) (*catalog, []matrixRow, error) {
+	cat, err := c.loadCatalog(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := c.deployments.List(ctx, &deploymentpb.ListRequest{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing deployments: %w", err)
+	}
+	latest := cat.latestDeployments(resp.Deployments)
+
+	rows := make([]matrixRow, 0, len(cat.applications))
+	for _, app := range cat.applications {
+		row := matrixRow{Application: app.Name, Versions: map[string]string{}}
+		for _, inst := range cat.instances {
+			if inst.ApplicationId != app.Id {
+				continue
+			}
+			if d, ok := latest[inst.Id]; ok {
+				row.Versions[cat.environmentName(inst.EnvironmentId)] = versionOrState(d.Version, d.Undeployed)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return cat, rows, nil
+}
+
+func matrixCmd(ctx context.Context, c *client, out *output, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: matrix")
+	}
+
+	cat, matrix, err := c.versionMatrix(ctx)
+	if err != nil {
+		return err
+	}
+
+	header := []string{"APPLICATION"}
+	for _, env := range cat.environments {
+		header = append(header, env.Name)
+	}
+
+	var rows [][]string
+	for _, m := range matrix {
+		row := []string{m.Application}
+		for _, env := range cat.environments {
+			row = append(row, orDash(m.Versions[env.Name]))
+		}
+		rows = append(rows, row)
+	}
+
+	return out.print(matrix, header, rows)
+}
+
+type diffRow struct {
+	Application string `json:"application" yaml:"application"`
+	From        string `json:"from,omitempty" yaml:"from,omitempty"`
+	To          string `json:"to,omitempty" yaml:"to,omitempty"`
+	Status      string `json:"status" yaml:"status"`
+}
+
+func diffCmd(ctx context.Context, c *client, out *output, args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	all := fs.Bool("all", false, "also list applications that have the same version in both environments")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: diff [-all] <from-env> <to-env>")
+	}
+
+	from, err := c.resolveEnvironment(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	to, err := c.resolveEnvironment(ctx, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	_, matrix, err := c.versionMatrix(ctx)
+	if err != nil {
+		return err
+	}
+
+	diff := []diffRow{}
+	var rows [][]string
+	for _, m := range matrix {
+		r := diffRow{
+			Application: m.Application,
+			From:        m.Versions[from.Name],
+			To:          m.Versions[to.Name],
+		}
+
+		switch {
+		case r.From == "" && r.To == "":
+			continue
+		case r.From == r.To:
+			if !*all {
+				continue
+			}
+			r.Status = "same"
+		case r.From == undeployedState:
+			r.Status = "undeployed in " + from.Name
+		case r.To == undeployedState:
+			r.Status = "undeployed in " + to.Name
+		case r.From == "":
+			r.Status = "only in " + to.Name
+		case r.To == "":
+			r.Status = "only in " + from.Name
+		default:
+			r.Status = "differs"
+		}
+
+		diff = append(diff, r)
+		rows = append(rows, []string{r.Application, orDash(r.From), orDash(r.To), r.Status})
+	}
+
+	return out.print(diff, []string{"APPLICATION", from.Name, to.Name, "STATUS"}, rows)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	notificationpb "overseer/api-go/notification/v1"
+)
+
+type notificationChannelView struct {
+	Id       int32                   `json:"id" yaml:"id"`
+	Name     string                  `json:"name" yaml:"name"`
+	Kind     string                  `json:"kind" yaml:"kind"`
+	URL      string                  `json:"url" yaml:"url"`
+	Template string                  `json:"template,omitempty" yaml:"template,omitempty"`
+	Batch    string                  `json:"batch,omitempty" yaml:"batch,omitempty"`
+	Routes   []notificationRouteView `json:"routes" yaml:"routes"`
+	Disabled bool                    `json:"disabled" yaml:"disabled"`
+}
+
+type notificationRouteView struct {
+	Environment string   `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Application string   `json:"application,omitempty" yaml:"application,omitempty"`
+	Events      []string `json:"events,omitempty" yaml:"events,omitempty"`
+	Redeploys   bool     `json:"redeploys,omitempty" yaml:"redeploys,omitempty"`
+}
+
+// String formats the route the way it is given to the -route flag.
+func (v notificationRouteView) String() string {
+	var parts []string
+	if v.Environment != "" {
+		parts = append(parts, "env="+v.Environment)
+	}
+	if v.Application != "" {
+		parts = append(parts, "app="+v.Application)
+	}
+	if len(v.Events) > 0 {
+		parts = append(parts, "events="+strings.Join(v.Events, ","))
+	}
+	if v.Redeploys {
+		parts = append(parts, "redeploys=true")
+	}
+	if len(parts) == 0 {
+		return "all"
+	}
+	return strings.Join(parts, " ")
+}
+
+func notificationsCmd(ctx context.Context, c *client, out *output, args []string) error {
+	sub, args := subcommand(args, "list")
+
+	switch sub {
+	case "list", "ls":
+		if len(args) != 0 {
+			return fmt.Errorf("usage: notifications list")
+		}
+
+		cat, err := c.loadCatalog(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.notifications.List(ctx, &notificationpb.ListRequest{})
+		if err != nil {
+			return err
+		}
+
+		views := make([]notificationChannelView, 0, len(resp.Channels))
+		rows := make([][]string, 0, len(resp.Channels))
+		for _, ch := range resp.Channels {
+			v := notificationChannelView{
+				Id:       ch.Id,
+				Name:     ch.Name,
+				Kind:     ch.Kind,
+				URL:      ch.Url,
+				Template: ch.Template,
+				Routes:   []notificationRouteView{},
+				Disabled: ch.Disabled,
+			}
+			if ch.BatchSeconds > 0 {
+				v.Batch = (time.Duration(ch.BatchSeconds) * time.Second).String()
+			}
+			routes := make([]string, 0, len(ch.Routes))
+			for _, r := range ch.Routes {
+				rv := notificationRouteView{Events: r.Events, Redeploys: r.Redeploys}
+				if r.EnvironmentId != 0 {
+					rv.Environment = cat.environmentName(r.EnvironmentId)
+				}
+				if r.ApplicationId != 0 {
+					rv.Application = cat.applicationName(r.ApplicationId)
+				}
+				v.Routes = append(v.Routes, rv)
+				routes = append(routes, rv.String())
+			}
+			views = append(views, v)
+
+			state := "enabled"
+			if v.Disabled {
+				state = "disabled"
+			}
+			template := "default"
+			if v.Template != "" {
+				template = "custom"
+			}
+			rows = append(rows, []string{fmt.Sprint(v.Id), v.Name, v.Kind, v.URL, orDash(strings.Join(routes, "; ")), orDash(v.Batch), template, state})
+		}
+
+		return out.print(views, []string{"ID", "NAME", "KIND", "URL", "ROUTES", "BATCH", "TEMPLATE", "STATE"}, rows)
+
+	case "create":
+		channel := &notificationpb.Channel{}
+		if err := c.parseNotificationChannelFlags(ctx, "notifications create", channel, args); err != nil {
+			return err
+		}
+
+		resp, err := c.notifications.Create(ctx, &notificationpb.CreateRequest{Channel: channel})
+		if err != nil {
+			return err
+		}
+
+		return out.done("created notification channel %q with id %d", resp.Channel.Name, resp.Channel.Id)
+
+	case "update":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: notifications update <id|name> [flags]")
+		}
+
+		channel, err := c.resolveNotificationChannel(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if err := c.parseNotificationChannelFlags(ctx, "notifications update", channel, args[1:]); err != nil {
+			return err
+		}
+
+		resp, err := c.notifications.Update(ctx, &notificationpb.UpdateRequest{Channel: channel})
+		if err != nil {
+			return err
+		}
+
+		return out.done("updated notification channel %q", resp.Channel.Name)
+
+	case "delete", "rm":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: notifications delete <id|name>")
+		}
+
+		channel, err := c.resolveNotificationChannel(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.notifications.Delete(ctx, &notificationpb.DeleteRequest{Id: channel.Id}); err != nil {
+			return err
+		}
+
+		return out.done("deleted notification channel %q", channel.Name)
+
+	case "test":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: notifications test <id|name>")
+		}
+
+		channel, err := c.resolveNotificationChannel(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.notifications.Test(ctx, &notificationpb.TestRequest{Id: channel.Id})
+		if err != nil {
+			return err
+		}
+
+		return out.done("sent a test message to %q:\n%s", channel.Name, resp.Text)
+
+	default:
+		return fmt.Errorf("unknown notifications command %q", sub)
+	}
+}
+
+// parseNotificationChannelFlags parses the flags of a notification channel into it, the flags that are not given
+// keep their values. Giving any -route replaces all the routes of the channel.
+func (c *client) parseNotificationChannelFlags(ctx context.Context, name string, ch *notificationpb.Channel, args []string) error {
+	var routes []string
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&ch.Name, "name", ch.Name, "the name of the channel")
+	fs.StringVar(&ch.Kind, "kind", ch.Kind, "the kind of the channel: slack, teams or generic")
+	fs.StringVar(&ch.Url, "url", ch.Url, "the incoming webhook URL the messages are posted to")
+	fs.StringVar(&ch.Template, "template", ch.Template, `the Go text/template of the messages, rendered per event, "" for the default one`)
+	batch := fs.Duration("batch", time.Duration(ch.BatchSeconds)*time.Second, "collect the events for this long and send them as one message, 0 to send them within a second")
+	fs.Func("route", `a route of the events sent to the channel, like "env=prod app=api events=deployment,rollout redeploys=true" where every part is optional; redeploys=true also sends the deployments of the version that was already deployed; repeat it for more routes, "" removes all routes`, func(s string) error {
+		routes = append(routes, s)
+		return nil
+	})
+	fs.BoolVar(&ch.Disabled, "disabled", ch.Disabled, "stop sending messages to the channel, -disabled=false enables it again")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments %q", fs.Args())
+	}
+
+	if *batch%time.Second != 0 {
+		return fmt.Errorf("the batch duration %s is not whole seconds", *batch)
+	}
+	ch.BatchSeconds = int32(*batch / time.Second)
+
+	if routes != nil {
+		ch.Routes = nil
+		for _, s := range routes {
+			if strings.TrimSpace(s) == "" {
+				continue
+			}
+			route, err := c.parseNotificationRoute(ctx, s)
+			if err != nil {
+				return err
+			}
+			ch.Routes = append(ch.Routes, route)
+		}
+	}
+
+	return nil
+}
+
+// parseNotificationRoute parses a route given as space separated env=, app=, events= and redeploys= parts.
+func (c *client) parseNotificationRoute(ctx context.Context, s string) (*notificationpb.Route, error) {
+	route := &notificationpb.Route{}
+	for part := range strings.FieldsSeq(s) {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid route part %q, expected env=, app=, events= or redeploys= with a value", part)
+		}
+
+		switch key {
+		case "env":
+			env, err := c.resolveEnvironment(ctx, value)
+			if err != nil {
+				return nil, err
+			}
+			route.EnvironmentId = env.Id
+		case "app":
+			app, err := c.resolveApplication(ctx, value)
+			if err != nil {
+				return nil, err
+			}
+			route.ApplicationId = app.Id
+		case "events":
+			for e := range strings.SplitSeq(value, ",") {
+				if e = strings.TrimSpace(e); e != "" {
+					route.Events = append(route.Events, e)
+				}
+			}
+		case "redeploys":
+			redeploys, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid route part %q, expected redeploys=true or redeploys=false", part)
+			}
+			route.Redeploys = redeploys
+		default:
+			return nil, fmt.Errorf("invalid route part %q, expected env=, app=, events= or redeploys=", part)
+		}
+	}
+	return route, nil
+}
+
+// resolveNotificationChannel returns the notification channel identified by ref, which is either its id or its name.
+func (c *client) resolveNotificationChannel(ctx context.Context, ref string) (*notificationpb.Channel, error) {
+	resp, err := c.notifications.List(ctx, &notificationpb.ListRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	id, isId := parseId(ref)
+	for _, ch := range resp.Channels {
+		if (isId && ch.Id == id) || ch.Name == ref {
+			return ch, nil
+		}
+	}
+	return nil, fmt.Errorf("notification channel %q not found", ref)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+type output struct {
+	w      io.Writer
+	format string
+}
+
+func newOutput(w io.Writer, format string) (*output, error) {
+	switch format {
+	case "table", "json", "yaml":
+		return &output{w: w, format: format}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, must be one of table, json or yaml", format)
+	}
+}
+
+// print writes v as JSON or YAML, or the header and rows as a table, depending on the output format.
+func (o *output) print(v any, header []string, rows [][]string) error {
+	switch o.format {
+	case "json":
+		enc := json.NewEncoder(o.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "yaml":
+		enc := yaml.NewEncoder(o.w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// done reports the outcome of a command that does not return any data.
+func (o *output) done(format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if o.format == "table" {
+		_, err := fmt.Fprintln(o.w, msg)
+		return err
+	}
+	return o.print(struct {
+		Message string `json:"message" yaml:"message"`
+	}{msg}, nil, nil)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	policypb "overseer/api-go/policy/v1"
+)
+
+type policyView struct {
+	Application        string   `json:"application" yaml:"application"`
+	Instance           string   `json:"instance,omitempty" yaml:"instance,omitempty"`
+	Pinned             string   `json:"pinned,omitempty" yaml:"pinned,omitempty"`
+	Allowed            string   `json:"allowed,omitempty" yaml:"allowed,omitempty"`
+	Forbidden          []string `json:"forbidden,omitempty" yaml:"forbidden,omitempty"`
+	NotAheadOfUpstream bool     `json:"not_ahead_of_upstream" yaml:"not_ahead_of_upstream"`
+}
+
+type violationView struct {
+	Instance     string    `json:"instance" yaml:"instance"`
+	Environment  string    `json:"environment" yaml:"environment"`
+	Application  string    `json:"application" yaml:"application"`
+	Version      string    `json:"version" yaml:"version"`
+	Rule         string    `json:"rule" yaml:"rule"`
+	Message      string    `json:"message" yaml:"message"`
+	DetectedAt   time.Time `json:"detected_at" yaml:"detected_at"`
+	Current      bool      `json:"current" yaml:"current"`
+	DeploymentId string    `json:"deployment_id" yaml:"deployment_id"`
+}
+
+func policiesCmd(ctx context.Context, c *client, out *output, args []string) error {
+	sub, args := subcommand(args, "list")
+
+	switch sub {
+	case "list", "ls":
+		if len(args) != 0 {
+			return fmt.Errorf("usage: policies list")
+		}
+
+		cat, err := c.loadCatalog(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.policies.List(ctx, &policypb.ListRequest{})
+		if err != nil {
+			return err
+		}
+
+		views := make([]policyView, 0, len(resp.Policies))
+		rows := make([][]string, 0, len(resp.Policies))
+		for _, p := range resp.Policies {
+			v := policyView{
+				Application:        cat.applicationName(p.ApplicationId),
+				Pinned:             p.Pinned,
+				Allowed:            p.Allowed,
+				Forbidden:          p.Forbidden,
+				NotAheadOfUpstream: p.NotAheadOfUpstream,
+			}
+			if inst := cat.instance(p.InstanceId); inst != nil {
+				v.Instance = inst.Name
+			}
+			views = append(views, v)
+
+			upstream := "-"
+			if v.NotAheadOfUpstream {
+				upstream = "not ahead"
+			}
+			rows = append(rows, []string{v.Application, orDash(v.Instance), orDash(v.Pinned), orDash(v.Allowed), orDash(strings.Join(v.Forbidden, ", ")), upstream})
+		}
+
+		return out.print(views, []string{"APPLICATION", "INSTANCE", "PINNED", "ALLOWED", "FORBIDDEN", "UPSTREAM"}, rows)
+
+	case "set":
+		fs := flag.NewFlagSet("policies set", flag.ContinueOnError)
+		appRef := fs.String("app", "", "set the policy of all instances of this application id or name")
+		instRef := fs.String("instance", "", "set the policy of this instance id or name")
+		pinned := fs.String("pin", "", "the version that is expected to be running")
+		allowed := fs.String("allow", "", `the allowed range of versions, like ">=1.2.0, <2.0.0" or "^1.2"`)
+		forbidden := fs.String("forbid", "", "a comma separated list of forbidden versions")
+		notAhead := fs.Bool("not-ahead", false, "forbid versions that have not been deployed to an upstream environment")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		policy := &policypb.VersionPolicy{
+			Pinned:             *pinned,
+			Allowed:            *allowed,
+			NotAheadOfUpstream: *notAhead,
+		}
+		for v := range strings.SplitSeq(*forbidden, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				policy.Forbidden = append(policy.Forbidden, v)
+			}
+		}
+
+		name, err := c.resolvePolicyScope(ctx, *appRef, *instRef, &policy.ApplicationId, &policy.InstanceId)
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.policies.Set(ctx, &policypb.SetRequest{Policy: policy}); err != nil {
+			return err
+		}
+
+		return out.done("set the version policy of %s", name)
+
+	case "delete", "rm":
+		fs := flag.NewFlagSet("policies delete", flag.ContinueOnError)
+		appRef := fs.String("app", "", "delete the policy of this application id or name")
+		instRef := fs.String("instance", "", "delete the policy of this instance id or name")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		req := &policypb.DeleteRequest{}
+		name, err := c.resolvePolicyScope(ctx, *appRef, *instRef, &req.ApplicationId, &req.InstanceId)
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.policies.Delete(ctx, req); err != nil {
+			return err
+		}
+
+		return out.done("deleted the version policy of %s", name)
+
+	case "violations":
+		fs := flag.NewFlagSet("policies violations", flag.ContinueOnError)
+		instRef := fs.String("instance", "", "only list the violations of this instance id or name")
+		current := fs.Bool("current", false, "only list the violations of the current deployments")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		cat, err := c.loadCatalog(ctx)
+		if err != nil {
+			return err
+		}
+
+		req := &policypb.ListViolationsRequest{CurrentOnly: *current}
+		if *instRef != "" {
+			inst, err := c.resolveInstance(ctx, *instRef)
+			if err != nil {
+				return err
+			}
+			req.InstanceId = inst.Id
+		}
+
+		resp, err := c.policies.ListViolations(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		views := make([]violationView, 0, len(resp.Violations))
+		rows := make([][]string, 0, len(resp.Violations))
+		for _, v := range resp.Violations {
+			view := violationView{
+				Version:      v.Version,
+				Rule:         v.Rule,
+				Message:      v.Message,
+				DetectedAt:   v.DetectedAt.AsTime(),
+				Current:      v.Current,
+				DeploymentId: v.DeploymentId,
+			}
+			if inst := cat.instance(v.InstanceId); inst != nil {
+				view.Instance = inst.Name
+				view.Environment = cat.environmentName(inst.EnvironmentId)
+				view.Application = cat.applicationName(inst.ApplicationId)
+			}
+			views = append(views, view)
+
+			state := "past"
+			if view.Current {
+				state = "current"
+			}
+			rows = append(rows, []string{view.Instance, view.Environment, view.Application, view.Version, view.Rule, view.Message, state, view.DetectedAt.Local().Format(time.DateTime)})
+		}
+
+		return out.print(views, []string{"INSTANCE", "ENVIRONMENT", "APPLICATION", "VERSION", "RULE", "MESSAGE", "DEPLOYMENT", "DETECTED AT"}, rows)
+
+	default:
+		return fmt.Errorf("unknown policies command %q", sub)
+	}
+}
+
+// resolvePolicyScope resolves the application or instance a policy is for, exactly one of them must be given.
+// It returns a description of the scope for messages.
+func (c *client) resolvePolicyScope(ctx context.Context, appRef, instRef string, applicationId, instanceId *int32) (string, error) {
+	if (appRef == "") == (instRef == "") {
+		return "", errors.New("either -app or -instance is required")
+	}
+
+	if instRef != "" {
+		inst, err := c.resolveInstance(ctx, instRef)
+		if err != nil {
+			return "", err
+		}
+		*instanceId = inst.Id
+		return fmt.Sprintf("instance %q", inst.Name), nil
+	}
+
+	app, err := c.resolveApplication(ctx, appRef)
+	if err != nil {
+		return "", err
+	}
+	*applicationId = app.Id
+	return fmt.Sprintf("application %q", app.Name), nil
+}
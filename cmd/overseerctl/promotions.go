@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	promotionpb "overseer/api-go/promotion/v1"
+)
+
+type promotionEdgeView struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+type pendingPromotionView struct {
+	Application string    `json:"application" yaml:"application"`
+	Version     string    `json:"version" yaml:"version"`
+	From        string    `json:"from" yaml:"from"`
+	To          string    `json:"to" yaml:"to"`
+	Deployed    []string  `json:"deployed" yaml:"deployed"`
+	Since       time.Time `json:"since" yaml:"since"`
+}
+
+type stageEntryView struct {
+	Environment string    `json:"environment" yaml:"environment"`
+	EnteredAt   time.Time `json:"entered_at" yaml:"entered_at"`
+}
+
+type versionPromotionView struct {
+	Application string           `json:"application" yaml:"application"`
+	Version     string           `json:"version" yaml:"version"`
+	Stages      []stageEntryView `json:"stages" yaml:"stages"`
+	// Skipped lists the skipped stages as from->to.
+	Skipped []string `json:"skipped" yaml:"skipped"`
+}
+
+func promotionsCmd(ctx context.Context, c *client, out *output, args []string) error {
+	sub, args := subcommand(args, "pending")
+
+	switch sub {
+	case "graph", "edges":
+		if len(args) != 0 {
+			return fmt.Errorf("usage: promotions graph")
+		}
+
+		cat, err := c.loadCatalog(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.promotions.ListEdges(ctx, &promotionpb.ListEdgesRequest{})
+		if err != nil {
+			return err
+		}
+
+		views := make([]promotionEdgeView, 0, len(resp.Edges))
+		rows := make([][]string, 0, len(resp.Edges))
+		for _, e := range resp.Edges {
+			v := promotionEdgeView{
+				From: cat.environmentName(e.FromEnvironmentId),
+				To:   cat.environmentName(e.ToEnvironmentId),
+			}
+			views = append(views, v)
+			rows = append(rows, []string{v.From, v.To})
+		}
+
+		return out.print(views, []string{"FROM", "TO"}, rows)
+
+	case "set":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: promotions set <environment> [<source-environment>...]")
+		}
+
+		env, err := c.resolveEnvironment(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		var (
+			sourceIds []int32
+			names     []string
+		)
+		for _, ref := range args[1:] {
+			source, err := c.resolveEnvironment(ctx, ref)
+			if err != nil {
+				return err
+			}
+			sourceIds = append(sourceIds, source.Id)
+			names = append(names, source.Name)
+		}
+
+		if _, err := c.promotions.SetSources(ctx, &promotionpb.SetSourcesRequest{
+			EnvironmentId:        env.Id,
+			SourceEnvironmentIds: sourceIds,
+		}); err != nil {
+			return err
+		}
+
+		if len(names) == 0 {
+			return out.done("environment %q is now a first stage", env.Name)
+		}
+		return out.done("environment %q is now promoted from %s", env.Name, strings.Join(names, ", "))
+
+	case "pending", "ls":
+		fs := flag.NewFlagSet("promotions pending", flag.ContinueOnError)
+		appRef := fs.String("app", "", "only list the versions of this application id or name")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		cat, applicationId, resp, err := c.listPromotions(ctx, *appRef)
+		if err != nil {
+			return err
+		}
+
+		views := []pendingPromotionView{}
+		var rows [][]string
+		for _, a := range resp.Applications {
+			if applicationId != 0 && a.ApplicationId != applicationId {
+				continue
+			}
+
+			for _, p := range a.Pending {
+				v := pendingPromotionView{
+					Application: cat.applicationName(a.ApplicationId),
+					Version:     p.Version,
+					From:        cat.environmentName(p.FromEnvironmentId),
+					To:          cat.environmentName(p.ToEnvironmentId),
+					Deployed:    p.Deployed,
+					Since:       p.Since.AsTime(),
+				}
+				if v.Deployed == nil {
+					v.Deployed = []string{}
+				}
+				views = append(views, v)
+				rows = append(rows, []string{v.Application, v.Version, v.From, v.To, orDash(strings.Join(v.Deployed, ", ")), v.Since.Local().Format(time.DateTime)})
+			}
+		}
+
+		return out.print(views, []string{"APPLICATION", "VERSION", "FROM", "TO", "DEPLOYED THERE", "SINCE"}, rows)
+
+	case "history":
+		fs := flag.NewFlagSet("promotions history", flag.ContinueOnError)
+		appRef := fs.String("app", "", "only list the versions of this application id or name")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		cat, applicationId, resp, err := c.listPromotions(ctx, *appRef)
+		if err != nil {
+			return err
+		}
+
+		views := []versionPromotionView{}
+		var rows [][]string
+		for _, a := range resp.Applications {
+			if applicationId != 0 && a.ApplicationId != applicationId {
+				continue
+			}
+
+			for _, p := range a.Versions {
+				v := versionPromotionView{
+					Application: cat.applicationName(a.ApplicationId),
+					Version:     p.Version,
+					Stages:      []stageEntryView{},
+					Skipped:     []string{},
+				}
+
+				var stages []string
+				for _, s := range p.Stages {
+					stage := stageEntryView{
+						Environment: cat.environmentName(s.EnvironmentId),
+						EnteredAt:   s.EnteredAt.AsTime(),
+					}
+					v.Stages = append(v.Stages, stage)
+					stages = append(stages, stage.Environment+" "+stage.EnteredAt.Local().Format(time.DateTime))
+				}
+				for _, s := range p.Skipped {
+					v.Skipped = append(v.Skipped, cat.environmentName(s.EnvironmentId)+"->"+cat.environmentName(s.ToEnvironmentId))
+				}
+
+				views = append(views, v)
+				rows = append(rows, []string{v.Application, v.Version, strings.Join(stages, ", "), orDash(strings.Join(v.Skipped, ", "))})
+			}
+		}
+
+		return out.print(views, []string{"APPLICATION", "VERSION", "STAGES", "SKIPPED"}, rows)
+
+	default:
+		return fmt.Errorf("unknown promotions command %q", sub)
+	}
+}
+
+// listPromotions lists the promotions of the applications and resolves the application to filter them by, if any.
+func (c *client) listPromotions(ctx context.Context, appRef string) (*catalog, int32, *promotionpb.ListResponse, error) {
+	cat, err := c.loadCatalog(ctx)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	var applicationId int32
+	if appRef != "" {
+		app, err := c.resolveApplication(ctx, appRef)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		applicationId = app.Id
+	}
+
+	resp, err := c.promotions.List(ctx, &promotionpb.ListRequest{})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	return cat, applicationId, resp, nil
+}
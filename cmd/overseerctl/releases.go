@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	releasepb "overseer/api-go/release/v1"
+)
+
+type releaseView struct {
+	Application string    `json:"application" yaml:"application"`
+	Repository  string    `json:"repository" yaml:"repository"`
+	Version     string    `json:"version" yaml:"version"`
+	CheckedAt   time.Time `json:"checked_at" yaml:"checked_at"`
+}
+
+type instanceReleaseView struct {
+	Instance        string `json:"instance" yaml:"instance"`
+	Environment     string `json:"environment" yaml:"environment"`
+	Application     string `json:"application" yaml:"application"`
+	Deployed        string `json:"deployed,omitempty" yaml:"deployed,omitempty"`
+	Available       string `json:"available,omitempty" yaml:"available,omitempty"`
+	UpdateAvailable bool   `json:"update_available" yaml:"update_available"`
+}
+
+func releasesCmd(ctx context.Context, c *client, out *output, args []string) error {
+	sub, args := subcommand(args, "list")
+
+	switch sub {
+	case "list", "ls":
+		if len(args) != 0 {
+			return fmt.Errorf("usage: releases list")
+		}
+
+		cat, err := c.loadCatalog(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.releases.List(ctx, &releasepb.ListRequest{})
+		if err != nil {
+			return err
+		}
+
+		views := make([]releaseView, 0, len(resp.Releases))
+		rows := make([][]string, 0, len(resp.Releases))
+		for _, r := range resp.Releases {
+			v := releaseView{
+				Application: cat.applicationName(r.ApplicationId),
+				Repository:  r.Repository,
+				Version:     r.Version,
+				CheckedAt:   r.CheckedAt.AsTime(),
+			}
+			views = append(views, v)
+			rows = append(rows, []string{v.Application, v.Version, v.Repository, v.CheckedAt.Local().Format(time.DateTime)})
+		}
+
+		return out.print(views, []string{"APPLICATION", "LATEST", "REPOSITORY", "CHECKED AT"}, rows)
+
+	case "instances":
+		if len(args) != 0 {
+			return fmt.Errorf("usage: releases instances")
+		}
+
+		cat, err := c.loadCatalog(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.releases.ListInstances(ctx, &releasepb.ListInstancesRequest{})
+		if err != nil {
+			return err
+		}
+
+		views := make([]instanceReleaseView, 0, len(resp.Instances))
+		rows := make([][]string, 0, len(resp.Instances))
+		for _, r := range resp.Instances {
+			v := instanceReleaseView{
+				Application:     cat.applicationName(r.ApplicationId),
+				Deployed:        r.Deployed,
+				Available:       r.Available,
+				UpdateAvailable: r.UpdateAvailable,
+			}
+			if inst := cat.instance(r.InstanceId); inst != nil {
+				v.Instance = inst.Name
+				v.Environment = cat.environmentName(inst.EnvironmentId)
+			}
+			views = append(views, v)
+
+			update := ""
+			if v.UpdateAvailable {
+				update = "update available"
+			}
+			rows = append(rows, []string{v.Instance, v.Environment, v.Application, orDash(v.Deployed), orDash(v.Available), orDash(update)})
+		}
+
+		return out.print(views, []string{"INSTANCE", "ENVIRONMENT", "APPLICATION", "DEPLOYED", "AVAILABLE", "STATUS"}, rows)
+
+	default:
+		return fmt.Errorf("unknown releases command %q", sub)
+	}
+}
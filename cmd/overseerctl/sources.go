@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	datasourcepb "overseer/api-go/datasource/v1"
+)
+
+type sourceView struct {
+	Name        string    `json:"name" yaml:"name"`
+	Type        string    `json:"type" yaml:"type"`
+	Enabled     bool      `json:"enabled" yaml:"enabled"`
+	Running     bool      `json:"running" yaml:"running"`
+	Error       string    `json:"error,omitempty" yaml:"error,omitempty"`
+	StartedAt   time.Time `json:"started_at,omitzero" yaml:"started_at,omitempty"`
+	Events      int64     `json:"events" yaml:"events"`
+	LastEventAt time.Time `json:"last_event_at,omitzero" yaml:"last_event_at,omitempty"`
+}
+
+func newSourceView(s *datasourcepb.Datasource) sourceView {
+	v := sourceView{
+		Name:    s.Name,
+		Type:    s.Type,
+		Enabled: s.Enabled,
+		Running: s.Running,
+		Error:   s.Error,
+		Events:  s.Events,
+	}
+	if s.StartedAt != nil {
+		v.StartedAt = s.StartedAt.AsTime()
+	}
+	if s.LastEventAt != nil {
+		v.LastEventAt = s.LastEventAt.AsTime()
+	}
+	return v
+}
+
+func (v sourceView) state() string {
+	switch {
+	case !v.Enabled:
+		return "disabled"
+	case v.Running:
+		return "running"
+	case v.Error != "":
+		return "failing"
+	default:
+		return "starting"
+	}
+}
+
+func sourcesCmd(ctx context.Context, c *client, out *output, args []string) error {
+	sub, args := subcommand(args, "list")
+
+	switch sub {
+	case "list", "ls":
+		resp, err := c.datasources.List(ctx, &datasourcepb.ListRequest{})
+		if err != nil {
+			return err
+		}
+
+		views := make([]sourceView, 0, len(resp.Datasources))
+		rows := make([][]string, 0, len(resp.Datasources))
+		for _, s := range resp.Datasources {
+			v := newSourceView(s)
+			views = append(views, v)
+
+			lastEvent := "-"
+			if !v.LastEventAt.IsZero() {
+				lastEvent = v.LastEventAt.Local().Format(time.DateTime)
+			}
+			rows = append(rows, []string{v.Name, v.Type, v.state(), strconv.FormatInt(v.Events, 10), lastEvent, orDash(v.Error)})
+		}
+
+		return out.print(views, []string{"NAME", "TYPE", "STATE", "EVENTS", "LAST EVENT", "ERROR"}, rows)
+
+	case "enable":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: sources enable <name>")
+		}
+
+		if _, err := c.datasources.Enable(ctx, &datasourcepb.EnableRequest{Name: args[0]}); err != nil {
+			return err
+		}
+
+		return out.done("enabled source %q", args[0])
+
+	case "disable":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: sources disable <name>")
+		}
+
+		if _, err := c.datasources.Disable(ctx, &datasourcepb.DisableRequest{Name: args[0]}); err != nil {
+			return err
+		}
+
+		return out.done("disabled source %q", args[0])
+
+	case "restart":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: sources restart <name>")
+		}
+
+		if _, err := c.datasources.Restart(ctx, &datasourcepb.RestartRequest{Name: args[0]}); err != nil {
+			return err
+		}
+
+		return out.done("restarted source %q", args[0])
+
+	default:
+		return fmt.Errorf("unknown sources command %q", sub)
+	}
+}
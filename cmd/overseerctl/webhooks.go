@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	webhookpb "overseer/api-go/webhook/v1"
+)
+
+type webhookView struct {
+	Id          int32     `json:"id" yaml:"id"`
+	Name        string    `json:"name" yaml:"name"`
+	URL         string    `json:"url" yaml:"url"`
+	HasSecret   bool      `json:"has_secret" yaml:"has_secret"`
+	Events      []string  `json:"events,omitempty" yaml:"events,omitempty"`
+	Environment string    `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Application string    `json:"application,omitempty" yaml:"application,omitempty"`
+	Disabled    bool      `json:"disabled" yaml:"disabled"`
+	CreatedAt   time.Time `json:"created_at" yaml:"created_at"`
+}
+
+type deliveryView struct {
+	Id             string    `json:"id" yaml:"id"`
+	Webhook        string    `json:"webhook" yaml:"webhook"`
+	EventId        string    `json:"event_id" yaml:"event_id"`
+	Event          string    `json:"event" yaml:"event"`
+	Status         string    `json:"status" yaml:"status"`
+	Attempts       int32     `json:"attempts" yaml:"attempts"`
+	ResponseStatus int32     `json:"response_status,omitempty" yaml:"response_status,omitempty"`
+	Error          string    `json:"error,omitempty" yaml:"error,omitempty"`
+	CreatedAt      time.Time `json:"created_at" yaml:"created_at"`
+	NextAttemptAt  time.Time `json:"next_attempt_at,omitzero" yaml:"next_attempt_at,omitempty"`
+	LastAttemptAt  time.Time `json:"last_attempt_at,omitzero" yaml:"last_attempt_at,omitempty"`
+	Payload        string    `json:"payload" yaml:"payload"`
+}
+
+// state describes the outcome of the delivery for the table output.
+func (v deliveryView) state() string {
+	switch {
+	case v.Status == "pending" && !v.NextAttemptAt.IsZero():
+		return "pending, next attempt " + v.NextAttemptAt.Local().Format(time.DateTime)
+	case v.ResponseStatus != 0:
+		return fmt.Sprintf("%s (%d)", v.Status, v.ResponseStatus)
+	default:
+		return v.Status
+	}
+}
+
+func webhooksCmd(ctx context.Context, c *client, out *output, args []string) error {
+	sub, args := subcommand(args, "list")
+
+	switch sub {
+	case "list", "ls":
+		if len(args) != 0 {
+			return fmt.Errorf("usage: webhooks list")
+		}
+
+		cat, err := c.loadCatalog(ctx)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.webhooks.List(ctx, &webhookpb.ListRequest{})
+		if err != nil {
+			return err
+		}
+
+		views := make([]webhookView, 0, len(resp.Webhooks))
+		rows := make([][]string, 0, len(resp.Webhooks))
+		for _, w := range resp.Webhooks {
+			v := webhookView{
+				Id:        w.Id,
+				Name:      w.Name,
+				URL:       w.Url,
+				HasSecret: w.HasSecret,
+				Events:    w.Events,
+				Disabled:  w.Disabled,
+				CreatedAt: w.CreatedAt.AsTime(),
+			}
+			if w.EnvironmentId != 0 {
+				v.Environment = cat.environmentName(w.EnvironmentId)
+			}
+			if w.ApplicationId != 0 {
+				v.Application = cat.applicationName(w.ApplicationId)
+			}
+			views = append(views, v)
+
+			events := "all"
+			if len(v.Events) > 0 {
+				events = strings.Join(v.Events, ",")
+			}
+			state := "enabled"
+			if v.Disabled {
+				state = "disabled"
+			}
+			signed := "no"
+			if v.HasSecret {
+				signed = "yes"
+			}
+			rows = append(rows, []string{fmt.Sprint(v.Id), v.Name, v.URL, events, orDash(v.Environment), orDash(v.Application), signed, state})
+		}
+
+		return out.print(views, []string{"ID", "NAME", "URL", "EVENTS", "ENVIRONMENT", "APPLICATION", "SIGNED", "STATE"}, rows)
+
+	case "create":
+		webhook := &webhookpb.Webhook{}
+		if err := c.parseWebhookFlags(ctx, "webhooks create", webhook, args); err != nil {
+			return err
+		}
+
+		resp, err := c.webhooks.Create(ctx, &webhookpb.CreateRequest{Webhook: webhook})
+		if err != nil {
+			return err
+		}
+
+		return out.done("created webhook %q with id %d", resp.Webhook.Name, resp.Webhook.Id)
+
+	case "update":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: webhooks update <id|name> [flags]")
+		}
+
+		webhook, err := c.resolveWebhook(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if err := c.parseWebhookFlags(ctx, "webhooks update", webhook, args[1:]); err != nil {
+			return err
+		}
+
+		resp, err := c.webhooks.Update(ctx, &webhookpb.UpdateRequest{Webhook: webhook})
+		if err != nil {
+			return err
+		}
+
+		return out.done("updated webhook %q", resp.Webhook.Name)
+
+	case "delete", "rm":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: webhooks delete <id|name>")
+		}
+
+		webhook, err := c.resolveWebhook(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.webhooks.Delete(ctx, &webhookpb.DeleteRequest{Id: webhook.Id}); err != nil {
+			return err
+		}
+
+		return out.done("deleted webhook %q", webhook.Name)
+
+	case "deliveries":
+		fs := flag.NewFlagSet("webhooks deliveries", flag.ContinueOnError)
+		webhookRef := fs.String("webhook", "", "only list the deliveries of this webhook id or name")
+		limit := fs.Int("limit", 0, "the maximum number of deliveries, defaults to 100")
+		payload := fs.Bool("payload", false, "show the payloads in the table output")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() != 0 {
+			return fmt.Errorf("unexpected arguments %q", fs.Args())
+		}
+
+		list, err := c.webhooks.List(ctx, &webhookpb.ListRequest{})
+		if err != nil {
+			return err
+		}
+		names := map[int32]string{}
+		for _, w := range list.Webhooks {
+			names[w.Id] = w.Name
+		}
+
+		req := &webhookpb.ListDeliveriesRequest{Limit: int32(*limit)}
+		if *webhookRef != "" {
+			webhook, err := c.resolveWebhook(ctx, *webhookRef)
+			if err != nil {
+				return err
+			}
+			req.WebhookId = webhook.Id
+		}
+
+		resp, err := c.webhooks.ListDeliveries(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		views := make([]deliveryView, 0, len(resp.Deliveries))
+		rows := make([][]string, 0, len(resp.Deliveries))
+		for _, d := range resp.Deliveries {
+			v := deliveryView{
+				Id:             d.Id,
+				Webhook:        names[d.WebhookId],
+				EventId:        d.EventId,
+				Event:          d.Event,
+				Status:         d.Status,
+				Attempts:       d.Attempts,
+				ResponseStatus: d.ResponseStatus,
+				Error:          d.Error,
+				CreatedAt:      d.CreatedAt.AsTime(),
+				Payload:        d.Payload,
+			}
+			if d.NextAttemptAt != nil {
+				v.NextAttemptAt = d.NextAttemptAt.AsTime()
+			}
+			if d.LastAttemptAt != nil {
+				v.LastAttemptAt = d.LastAttemptAt.AsTime()
+			}
+			views = append(views, v)
+
+			row := []string{v.Id, v.Webhook, v.Event, v.CreatedAt.Local().Format(time.DateTime), v.state(), fmt.Sprint(v.Attempts), orDash(v.Error)}
+			if *payload {
+				row = append(row, v.Payload)
+			}
+			rows = append(rows, row)
+		}
+
+		headers := []string{"ID", "WEBHOOK", "EVENT", "CREATED", "STATUS", "ATTEMPTS", "ERROR"}
+		if *payload {
+			headers = append(headers, "PAYLOAD")
+		}
+		return out.print(views, headers, rows)
+
+	case "redeliver":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: webhooks redeliver <delivery id>")
+		}
+
+		resp, err := c.webhooks.Redeliver(ctx, &webhookpb.RedeliverRequest{DeliveryId: args[0]})
+		if err != nil {
+			return err
+		}
+
+		return out.done("queued the redelivery %s of the %s event %s", resp.Delivery.Id, resp.Delivery.Event, resp.Delivery.EventId)
+
+	default:
+		return fmt.Errorf("unknown webhooks command %q", sub)
+	}
+}
+
+// parseWebhookFlags parses the flags of a webhook into it, the flags that are not given keep their values.
+func (c *client) parseWebhookFlags(ctx context.Context, name string, w *webhookpb.Webhook, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&w.Name, "name", w.Name, "the name of the webhook")
+	fs.StringVar(&w.Url, "url", w.Url, "the URL the events are posted to")
+	fs.StringVar(&w.Secret, "secret", "", "the secret the payloads are signed with, the current one is kept if it is not given")
+	events := fs.String("events", strings.Join(w.Events, ","), `comma separated event types: deployment, rollout, policy_violation and release, "" for all events`)
+	envRef := fs.String("env", "", `only send the events of this environment id or name, "" for all environments`)
+	appRef := fs.String("app", "", `only send the events of this application id or name, "" for all applications`)
+	fs.BoolVar(&w.Disabled, "disabled", w.Disabled, "stop sending events to the webhook, -disabled=false enables it again")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments %q", fs.Args())
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	w.Events = nil
+	for e := range strings.SplitSeq(*events, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			w.Events = append(w.Events, e)
+		}
+	}
+
+	if set["env"] {
+		w.EnvironmentId = 0
+		if *envRef != "" {
+			env, err := c.resolveEnvironment(ctx, *envRef)
+			if err != nil {
+				return err
+			}
+			w.EnvironmentId = env.Id
+		}
+	}
+	if set["app"] {
+		w.ApplicationId = 0
+		if *appRef != "" {
+			app, err := c.resolveApplication(ctx, *appRef)
+			if err != nil {
+				return err
+			}
+			w.ApplicationId = app.Id
+		}
+	}
+
+	return nil
+}
+
+// resolveWebhook returns the webhook identified by ref, which is either its id or its name.
+func (c *client) resolveWebhook(ctx context.Context, ref string) (*webhookpb.Webhook, error) {
+	resp, err := c.webhooks.List(ctx, &webhookpb.ListRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	id, isId := parseId(ref)
+	for _, w := range resp.Webhooks {
+		if (isId && w.Id == id) || w.Name == ref {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("webhook %q not found", ref)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"overseer/app"
+	"regexp"
+	"strings"
+)
+
+// componentRulesFlag collects the -component-rule flags.
+//
+// Each flag has the form <regexp>=<instance>:<component>, where the instance and component
+// may refer to the submatches of the regexp like $1 or ${name}.
+// An empty instance keeps the deployment name of the event, for example `\.envoy$=:sidecar`.
+type componentRulesFlag struct {
+	rules *[]app.ComponentRule
+}
+
+func (f componentRulesFlag) String() string {
+	if f.rules == nil {
+		return ""
+	}
+
+	var parts []string
+	for _, r := range *f.rules {
+		parts = append(parts, r.Match.String()+"="+r.Instance+":"+r.Component)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (f componentRulesFlag) Set(value string) error {
+	idx := strings.LastIndex(value, "=")
+	if idx == -1 {
+		return fmt.Errorf("expected <regexp>=<instance>:<component>, got %q", value)
+	}
+
+	instance, component, ok := strings.Cut(value[idx+1:], ":")
+	if !ok || component == "" {
+		return fmt.Errorf("expected <regexp>=<instance>:<component>, got %q", value)
+	}
+
+	match, err := regexp.Compile(value[:idx])
+	if err != nil {
+		return err
+	}
+
+	*f.rules = append(*f.rules, app.ComponentRule{
+		Match:     match,
+		Instance:  instance,
+		Component: component,
+	})
+	return nil
+}
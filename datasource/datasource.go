@@ -1,12 +1,77 @@
 package datasource
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Event struct {
-	Id             string // Unique identifier for the event
+	Id string // Unique identifier for the event
+	// Source is the name of the source the event came from, set by the app when it receives it.
+	Source         string
 	DeploymentName string
-	DeployedAt     time.Time
-	Version        string
+	// Component is the component of the instance that was deployed, empty for the primary component.
+	// The component rules of the App take precedence.
+	Component  string
+	DeployedAt time.Time
+	Version    string
+	// Sequence is a monotonic number used to order the events of a source when their timestamps can't be trusted,
+	// zero if the source has none.
+	Sequence int64
+	Metadata Metadata
+	// Undeployed is set if the deployment was stopped or removed, the version is then empty.
+	Undeployed bool
+	// Rollout is set if the event reports the progress of rolling out Version instead of a new deployment,
+	// DeployedAt is then the time of the progress update.
+	Rollout *Rollout
+	// Ack is called once the event is handled, if it is set, whether or not it could be registered.
+	Ack func()
+}
+
+// Statuses of a rollout.
+const (
+	RolloutPending    = "pending"
+	RolloutRunning    = "running"
+	RolloutPaused     = "paused"
+	RolloutSuccessful = "successful"
+	RolloutFailed     = "failed"
+	RolloutCancelled  = "cancelled"
+)
+
+// Rollout is the progress of rolling out a deployed version.
+type Rollout struct {
+	// Status is one of the Rollout statuses, like running, successful or failed.
+	Status      string
+	Description string
+	// Healthy and Desired are the number of healthy and desired replicas, both zero if unknown.
+	Healthy int32
+	Desired int32
+}
+
+// Metadata describes what was deployed and who deployed it, sources fill in what they know.
+type Metadata struct {
+	// Image is the reference of the deployed container image, without the digest.
+	Image       string
+	ImageDigest string
+	GitCommit   string
+	BuildURL    string
+	// Deployer is the person or pipeline that made the deployment.
+	Deployer string
+	Labels   map[string]string
 }
 
 type EventStream <-chan Event
+
+// ParseImage splits a container image reference like registry:5000/app:1.2.3@sha256:abc
+// into the image without the digest, the tag and the digest.
+func ParseImage(ref string) (image, tag, digest string) {
+	image, digest, _ = strings.Cut(ref, "@")
+
+	// A colon before the last slash separates the port of the registry, not the tag.
+	lastColon := strings.LastIndex(image, ":")
+	if lastColon > strings.LastIndex(image, "/") {
+		tag = image[lastColon+1:]
+	}
+
+	return image, tag, digest
+}
@@ -0,0 +1,369 @@
+package docker
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"overseer/datasource"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHost is the Docker Engine API endpoint used when Config.Host is empty.
+const DefaultHost = "unix:///var/run/docker.sock"
+
+// Labels set by Docker Compose, the deployment of a compose service is named <project>.<service>.
+const (
+	LabelComposeProject = "com.docker.compose.project"
+	LabelComposeService = "com.docker.compose.service"
+)
+
+// LabelRevision is the OCI image label recorded as the git commit of a deployment.
+const LabelRevision = "org.opencontainers.image.revision"
+
+// reconnectDelay is how long to wait before connecting to the event stream again.
+const reconnectDelay = 5 * time.Second
+
+type Config struct {
+	// Host is the Docker Engine API endpoint, like unix:///var/run/docker.sock or tcp://edge-1:2375.
+	// Defaults to DefaultHost.
+	Host string
+	// NamePrefix is prepended to the deployment names with a dot, like the name of the host,
+	// to tell the containers of several hosts apart.
+	NamePrefix string
+}
+
+type Source struct {
+	baseURL    string
+	client     *http.Client
+	namePrefix string
+	logger     *slog.Logger
+	// reconnectDelay is reconnectDelay, except in tests.
+	reconnectDelay time.Duration
+
+	mu sync.Mutex
+	// running holds the deployment name of the containers that were reported as running,
+	// so that stopping and then removing a container is only reported as undeployed once.
+	// It is rebuilt from the listing of the containers on every connect.
+	running map[string]string
+}
+
+type container struct {
+	ID    string
+	Name  string
+	Image string // The id of the image
+	State struct {
+		Running   bool
+		StartedAt time.Time
+	}
+	Config struct {
+		Image  string // The image reference the container was created from
+		Labels map[string]string
+	}
+}
+
+type image struct {
+	RepoDigests []string
+}
+
+type event struct {
+	Type   string
+	Action string
+	Actor  struct {
+		ID string
+	}
+	TimeNano int64
+}
+
+func NewSource(config Config, logger *slog.Logger) (*Source, error) {
+	host := cmp.Or(config.Host, DefaultHost)
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the Docker host: %w", err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	baseURL := "http://" + u.Host
+
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+		// The host is ignored when dialing the socket, but it has to be a valid one.
+		baseURL = "http://docker"
+	case "tcp", "http":
+	default:
+		return nil, fmt.Errorf("unsupported Docker host %q, expected unix:// or tcp://", host)
+	}
+
+	return &Source{
+		baseURL:    baseURL,
+		client:     &http.Client{Transport: transport},
+		namePrefix: config.NamePrefix,
+		logger:     logger,
+		running:    map[string]string{},
+
+		reconnectDelay: reconnectDelay,
+	}, nil
+}
+
+func (d *Source) StreamEvents(ctx context.Context) (<-chan datasource.Event, error) {
+	stream := make(chan datasource.Event, 10)
+
+	go func() {
+		defer close(stream)
+
+		for {
+			err := d.runStream(ctx, stream)
+			if ctx.Err() != nil {
+				return
+			}
+
+			d.logger.Error("stream error", "error", err)
+
+			select {
+			case <-time.After(d.reconnectDelay):
+			case <-ctx.Done():
+				return
+			}
+
+			d.logger.Info("reconnecting to the Docker event stream...")
+		}
+	}()
+
+	return stream, nil
+}
+
+// runStream reports the running containers and the ones that stopped while disconnected,
+// then follows the container events.
+func (d *Source) runStream(ctx context.Context, stream chan datasource.Event) error {
+	// Events from before the listing are streamed again so that none are missed in between,
+	// reporting a container twice is harmless.
+	since := time.Now()
+
+	var containers []struct {
+		ID string `json:"Id"`
+	}
+	if err := d.get(ctx, "/containers/json", &containers); err != nil {
+		return fmt.Errorf("listing the containers: %w", err)
+	}
+
+	listed := map[string]bool{}
+	for _, c := range containers {
+		listed[c.ID] = true
+		if err := d.reportStarted(ctx, c.ID, stream); err != nil {
+			return err
+		}
+	}
+
+	// The containers that were running before, but not anymore, stopped while the stream was down.
+	d.mu.Lock()
+	var stopped []string
+	for id := range d.running {
+		if !listed[id] {
+			stopped = append(stopped, id)
+		}
+	}
+	d.mu.Unlock()
+
+	for _, id := range stopped {
+		d.reportStopped(id, since, stream)
+	}
+
+	filters, err := json.Marshal(map[string][]string{
+		"type":  {"container"},
+		"event": {"start", "stop", "destroy"},
+	})
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{
+		"since":   {fmt.Sprint(since.Unix())},
+		"filters": {string(filters)},
+	}
+
+	resp, err := d.do(ctx, "/events?"+query.Encode())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+
+	for {
+		var e event
+		if err := decoder.Decode(&e); err != nil {
+			return err
+		}
+
+		switch e.Action {
+		case "start":
+			err = d.reportStarted(ctx, e.Actor.ID, stream)
+		case "stop", "destroy":
+			d.reportStopped(e.Actor.ID, time.Unix(0, e.TimeNano), stream)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// reportStarted reports the version of a running container.
+func (d *Source) reportStarted(ctx context.Context, id string, stream chan datasource.Event) error {
+	var c container
+	if err := d.get(ctx, "/containers/"+url.PathEscape(id)+"/json", &c); err != nil {
+		var notFound errNotFound
+		if errors.As(err, &notFound) {
+			// Removed since it was started.
+			return nil
+		}
+		return fmt.Errorf("inspecting container %s: %w", id, err)
+	}
+
+	if !c.State.Running {
+		return nil
+	}
+
+	name := d.deploymentName(strings.TrimPrefix(c.Name, "/"), c.Config.Labels)
+
+	imageRef, version, digest := datasource.ParseImage(c.Config.Image)
+	if version == "" || version == "latest" {
+		d.logger.Warn("could not determine image version", "container", name, "image", c.Config.Image)
+		return nil
+	}
+
+	if digest == "" {
+		digest = d.imageDigest(ctx, c.Image, strings.TrimSuffix(imageRef, ":"+version))
+	}
+
+	d.mu.Lock()
+	d.running[c.ID] = name
+	d.mu.Unlock()
+
+	stream <- datasource.Event{
+		Id:             c.ID + "@" + c.State.StartedAt.Format(time.RFC3339Nano),
+		DeploymentName: name,
+		Version:        version,
+		DeployedAt:     c.State.StartedAt,
+		Metadata: datasource.Metadata{
+			Image:       imageRef,
+			ImageDigest: digest,
+			GitCommit:   c.Config.Labels[LabelRevision],
+		},
+	}
+
+	return nil
+}
+
+// reportStopped reports a container stopped or removed at the given time as undeployed, if it was reported as running
+// and no other replica of the same deployment is still running.
+func (d *Source) reportStopped(id string, at time.Time, stream chan datasource.Event) {
+	d.mu.Lock()
+	name, ok := d.running[id]
+	delete(d.running, id)
+	replicaRunning := slices.Contains(slices.Collect(maps.Values(d.running)), name)
+	d.mu.Unlock()
+
+	if !ok || replicaRunning {
+		return
+	}
+
+	stream <- datasource.Event{
+		Id:             id + "@" + at.Format(time.RFC3339Nano),
+		DeploymentName: name,
+		DeployedAt:     at,
+		Undeployed:     true,
+	}
+}
+
+// deploymentName names a container after its compose project and service, or else its container name.
+func (d *Source) deploymentName(containerName string, labels map[string]string) string {
+	name := containerName
+	if project, service := labels[LabelComposeProject], labels[LabelComposeService]; project != "" && service != "" {
+		name = project + "." + service
+	}
+
+	if d.namePrefix != "" {
+		return d.namePrefix + "." + name
+	}
+	return name
+}
+
+// imageDigest returns the digest of the image in the repository it was pulled from,
+// or empty if it was built locally or can't be looked up.
+func (d *Source) imageDigest(ctx context.Context, imageId, repository string) string {
+	var img image
+	if err := d.get(ctx, "/images/"+url.PathEscape(imageId)+"/json", &img); err != nil {
+		d.logger.Warn("inspecting image", "image", repository, "error", err)
+		return ""
+	}
+
+	for _, repoDigest := range img.RepoDigests {
+		if repo, digest, ok := strings.Cut(repoDigest, "@"); ok && repo == repository {
+			return digest
+		}
+	}
+	return ""
+}
+
+type errNotFound struct {
+	path string
+}
+
+func (e errNotFound) Error() string {
+	return fmt.Sprintf("%s not found", e.path)
+}
+
+// get decodes the JSON response of a Docker Engine API request into v.
+func (d *Source) get(ctx context.Context, path string, v any) error {
+	resp, err := d.do(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding the response of %s: %w", path, err)
+	}
+	return nil
+}
+
+// do sends a GET request and returns the response if it has status OK.
+func (d *Source) do(ctx context.Context, pathAndQuery string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", d.baseURL+pathAndQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating the request: %w", err)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to Docker: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, errNotFound{path: pathAndQuery}
+		}
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("bad status from Docker: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,199 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"overseer/datasource"
+)
+
+// fakeDocker serves the parts of the Docker Engine API the source uses. Every connection to the event stream
+// lists the containers of the next entry of listings, and streams the events of the same entry of events.
+type fakeDocker struct {
+	containers map[string]string
+	listings   [][]string
+	events     [][]string
+
+	mu          sync.Mutex
+	connections int
+}
+
+func (f *fakeDocker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	connection := f.connections
+	f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/containers/json":
+		var list []map[string]string
+		for _, id := range f.listings[connection] {
+			list = append(list, map[string]string{"Id": id})
+		}
+		json.NewEncoder(w).Encode(list)
+
+	case strings.HasPrefix(r.URL.Path, "/containers/"):
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/containers/"), "/json")
+		c, ok := f.containers[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, c)
+
+	case strings.HasPrefix(r.URL.Path, "/images/"):
+		fmt.Fprint(w, `{"RepoDigests": ["other/web@sha256:other", "shop/web@sha256:web"]}`)
+
+	case r.URL.Path == "/events":
+		f.mu.Lock()
+		f.connections++
+		f.mu.Unlock()
+
+		for _, e := range f.events[connection] {
+			fmt.Fprintln(w, e)
+		}
+		w.(http.Flusher).Flush()
+
+		// The last connection stays open, the others end and are reconnected.
+		if connection == len(f.listings)-1 {
+			<-r.Context().Done()
+		}
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func containerJSON(id, name, image string, labels map[string]string) string {
+	c := map[string]any{
+		"Id":    id,
+		"Name":  "/" + name,
+		"Image": "sha256:" + id,
+		"State": map[string]any{"Running": true, "StartedAt": "2025-06-01T12:00:00Z"},
+		"Config": map[string]any{
+			"Image":  image,
+			"Labels": labels,
+		},
+	}
+	b, _ := json.Marshal(c)
+	return string(b)
+}
+
+func TestStreamEvents(t *testing.T) {
+	compose := func(service string) map[string]string {
+		return map[string]string{LabelComposeProject: "shop", LabelComposeService: service, LabelRevision: "abc123"}
+	}
+
+	fake := &fakeDocker{
+		containers: map[string]string{
+			"a": containerJSON("a", "api-1", "registry:5000/api:1.0.0@sha256:api", nil),
+			"b": containerJSON("b", "shop-web-1", "shop/web:2.0.0", compose("web")),
+			"c": containerJSON("c", "shop-worker-1", "shop/worker:3.0.0@sha256:worker", compose("worker")),
+			"d": containerJSON("d", "shop-worker-2", "shop/worker:3.0.0@sha256:worker", compose("worker")),
+			"e": containerJSON("e", "latest-1", "shop/latest:latest", nil),
+		},
+		listings: [][]string{
+			{"a", "b", "e"},
+			// a stopped while the stream was down.
+			{"b", "c", "d"},
+		},
+		events: [][]string{
+			{},
+			{
+				`{"Type": "container", "Action": "stop", "Actor": {"ID": "c"}, "TimeNano": 1748779260000000000}`,
+				`{"Type": "container", "Action": "destroy", "Actor": {"ID": "c"}, "TimeNano": 1748779270000000000}`,
+				`{"Type": "container", "Action": "stop", "Actor": {"ID": "d"}, "TimeNano": 1748779320000000000}`,
+			},
+		},
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	source, err := NewSource(Config{Host: "tcp://" + strings.TrimPrefix(server.URL, "http://")}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source.reconnectDelay = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := source.StreamEvents(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		name, version string
+		undeployed    bool
+	}
+	wants := []want{
+		{"api-1", "1.0.0", false},
+		{"shop.web", "2.0.0", false},
+		{"shop.web", "2.0.0", false},
+		{"shop.worker", "3.0.0", false},
+		{"shop.worker", "3.0.0", false},
+		{"api-1", "", true},
+		{"shop.worker", "", true},
+	}
+
+	var events []datasource.Event
+	for range wants {
+		select {
+		case e := <-stream:
+			events = append(events, e)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out after the events %+v", events)
+		}
+	}
+
+	for i, w := range wants {
+		e := events[i]
+		if e.DeploymentName != w.name || e.Version != w.version || e.Undeployed != w.undeployed {
+			t.Fatalf("event %d: expected %+v, got %+v", i, w, e)
+		}
+	}
+
+	if m := events[1].Metadata; m.Image != "shop/web:2.0.0" || m.ImageDigest != "sha256:web" || m.GitCommit != "abc123" {
+		t.Fatalf("expected the metadata of the image, got %+v", m)
+	}
+	if m := events[0].Metadata; m.Image != "registry:5000/api:1.0.0" || m.ImageDigest != "sha256:api" {
+		t.Fatalf("expected the digest of the image reference, got %+v", m)
+	}
+	if at := time.Unix(0, 1748779320000000000); !events[6].DeployedAt.Equal(at) {
+		t.Fatalf("expected the undeployment at %s, got %s", at, events[6].DeployedAt)
+	}
+
+	cancel()
+	for range stream {
+	}
+}
+
+func TestDeploymentName(t *testing.T) {
+	tests := []struct {
+		prefix    string
+		container string
+		labels    map[string]string
+		want      string
+	}{
+		{"", "api-1", nil, "api-1"},
+		{"edge-1", "api-1", nil, "edge-1.api-1"},
+		{"", "shop-web-1", map[string]string{LabelComposeProject: "shop", LabelComposeService: "web"}, "shop.web"},
+		{"edge-1", "shop-web-1", map[string]string{LabelComposeProject: "shop", LabelComposeService: "web"}, "edge-1.shop.web"},
+		{"", "shop-web-1", map[string]string{LabelComposeProject: "shop"}, "shop-web-1"},
+	}
+
+	for _, tt := range tests {
+		s := &Source{namePrefix: tt.prefix}
+		if got := s.deploymentName(tt.container, tt.labels); got != tt.want {
+			t.Errorf("deploymentName(%q, %v) with the prefix %q: expected %q, got %q", tt.container, tt.labels, tt.prefix, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Package httppoll reads the deployed versions of services from their own version endpoints,
+// like /version or /actuator/info, for services that aren't deployed through anything else Overseer watches.
+package httppoll
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"overseer/datasource"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults of the Config.
+const (
+	DefaultInterval       = time.Minute
+	DefaultTimeout        = 10 * time.Second
+	DefaultMaxConcurrency = 4
+)
+
+// maxBodySize limits the size of the responses of the version endpoints.
+const maxBodySize = 1 << 20
+
+// Target is a version endpoint of a deployment.
+type Target struct {
+	DeploymentName string
+	// Component is the component of the instance the version is reported for, empty for the primary component.
+	Component string
+	URL       string
+	// JSONPath selects the version in a JSON response, like $.build.version.
+	// Only child members and array indices are supported.
+	JSONPath string
+	// Regexp extracts the version from the response, or from the value selected by JSONPath.
+	// The first submatch is the version if the regexp has one, else the whole match.
+	// The whole response, without surrounding whitespace, is the version if neither is set.
+	Regexp *regexp.Regexp
+	// Interval and Timeout override the defaults of the Config.
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+type Config struct {
+	Targets []Target
+	// Interval is how often the targets are polled, defaults to DefaultInterval.
+	Interval time.Duration
+	// Timeout limits each request, defaults to DefaultTimeout.
+	Timeout time.Duration
+	// MaxConcurrency limits the number of requests in flight, defaults to DefaultMaxConcurrency.
+	MaxConcurrency int
+}
+
+type target struct {
+	Target
+	jsonPath jsonPath
+}
+
+type Source struct {
+	targets []target
+	client  *http.Client
+	logger  *slog.Logger
+	// slots holds a value for every request in flight.
+	slots chan struct{}
+}
+
+func NewSource(config Config, logger *slog.Logger) (*Source, error) {
+	if len(config.Targets) == 0 {
+		return nil, errors.New("no targets to poll")
+	}
+
+	var targets []target
+	for _, t := range config.Targets {
+		if t.DeploymentName == "" {
+			return nil, fmt.Errorf("the deployment name of %s is required", t.URL)
+		}
+
+		u, err := url.Parse(t.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid URL %q of %s", t.URL, t.DeploymentName)
+		}
+
+		if t.Interval < 0 || t.Timeout < 0 {
+			return nil, fmt.Errorf("negative interval or timeout of %s", t.DeploymentName)
+		}
+		t.Interval = cmp.Or(t.Interval, config.Interval, DefaultInterval)
+		t.Timeout = cmp.Or(t.Timeout, config.Timeout, DefaultTimeout)
+
+		var path jsonPath
+		if t.JSONPath != "" {
+			if path, err = parseJSONPath(t.JSONPath); err != nil {
+				return nil, fmt.Errorf("the JSONPath of %s: %w", t.DeploymentName, err)
+			}
+		}
+
+		targets = append(targets, target{Target: t, jsonPath: path})
+	}
+
+	if config.MaxConcurrency < 0 {
+		return nil, errors.New("negative max concurrency")
+	}
+
+	return &Source{
+		targets: targets,
+		client:  &http.Client{},
+		logger:  logger,
+		slots:   make(chan struct{}, cmp.Or(config.MaxConcurrency, DefaultMaxConcurrency)),
+	}, nil
+}
+
+// StreamEvents polls every target until the context is canceled,
+// reporting the version of a target when it is first read and whenever it changes.
+func (s *Source) StreamEvents(ctx context.Context) (<-chan datasource.Event, error) {
+	stream := make(chan datasource.Event, 10)
+
+	var wg sync.WaitGroup
+	for _, t := range s.targets {
+		wg.Go(func() {
+			s.pollTarget(ctx, t, stream)
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(stream)
+	}()
+
+	return stream, nil
+}
+
+func (s *Source) pollTarget(ctx context.Context, t target, stream chan datasource.Event) {
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+
+	var last string
+	for {
+		version, err := s.poll(ctx, t)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return
+			}
+			s.logger.Warn("polling the version", "deployment", t.DeploymentName, "url", t.URL, "error", err)
+
+		case version != last:
+			last = version
+			now := time.Now()
+
+			select {
+			case stream <- datasource.Event{
+				Id:             t.DeploymentName + "@" + now.Format(time.RFC3339Nano),
+				DeploymentName: t.DeploymentName,
+				Component:      t.Component,
+				Version:        version,
+				// The version was deployed some time since the previous poll, this is when it was noticed.
+				DeployedAt: now,
+			}:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll reads the version of the target, waiting for a free slot first.
+func (s *Source) poll(ctx context.Context, t target) (string, error) {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", t.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating the request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return "", fmt.Errorf("reading the response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	return t.extract(body)
+}
+
+// extract returns the version in the response body.
+func (t target) extract(body []byte) (string, error) {
+	version := strings.TrimSpace(string(body))
+
+	if t.jsonPath != nil {
+		var err error
+		if version, err = t.jsonPath.extract(body); err != nil {
+			return "", fmt.Errorf("extracting %s: %w", t.JSONPath, err)
+		}
+	}
+
+	if t.Regexp != nil {
+		match := t.Regexp.FindStringSubmatch(version)
+		if match == nil {
+			return "", fmt.Errorf("no match of %s", t.Regexp)
+		}
+		version = match[min(1, len(match)-1)]
+	}
+
+	if version == "" {
+		return "", errors.New("empty version")
+	}
+	return version, nil
+}
@@ -0,0 +1,137 @@
+package httppoll
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		jsonPath string
+		regexp   string
+		body     string
+		want     string
+		wantErr  bool
+	}{
+		{name: "raw body", body: " 1.2.3\n", want: "1.2.3"},
+		{name: "empty body", body: " \n", wantErr: true},
+		{name: "JSONPath", jsonPath: "$.build.version", body: `{"build": {"version": "1.2.3"}}`, want: "1.2.3"},
+		{name: "JSONPath of the whole document", jsonPath: "$", body: `"1.2.3"`, want: "1.2.3"},
+		{name: "JSONPath of an empty string", jsonPath: "$.version", body: `{"version": ""}`, wantErr: true},
+		{name: "JSONPath not found", jsonPath: "$.version", body: `{"build": "1.2.3"}`, wantErr: true},
+		{name: "regexp submatch", regexp: `version: (\S+)`, body: "name: api\nversion: 1.2.3\n", want: "1.2.3"},
+		{name: "regexp whole match", regexp: `\d+\.\d+\.\d+`, body: "api v1.2.3 (abc)", want: "1.2.3"},
+		{name: "regexp without a match", regexp: `version: (\S+)`, body: "name: api", wantErr: true},
+		{name: "regexp with an empty submatch", regexp: `version: (\d*)`, body: "version: x", wantErr: true},
+		{name: "regexp of the JSONPath", jsonPath: "$.git.describe", regexp: `^v?([^-]+)`, body: `{"git": {"describe": "v1.2.3-4-gabc"}}`, want: "1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := Target{DeploymentName: "api.prod", URL: "http://api/version", JSONPath: tt.jsonPath}
+			if tt.regexp != "" {
+				target.Regexp = regexp.MustCompile(tt.regexp)
+			}
+			source, err := NewSource(Config{Targets: []Target{target}}, slog.New(slog.DiscardHandler))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := source.targets[0].extract([]byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected an error %t, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewSource(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"no targets", Config{}},
+		{"no deployment name", Config{Targets: []Target{{URL: "http://api/version"}}}},
+		{"invalid URL", Config{Targets: []Target{{DeploymentName: "api", URL: "api/version"}}}},
+		{"invalid JSONPath", Config{Targets: []Target{{DeploymentName: "api", URL: "http://api/version", JSONPath: "version"}}}},
+		{"negative interval", Config{Targets: []Target{{DeploymentName: "api", URL: "http://api/version", Interval: -time.Second}}}},
+		{"negative max concurrency", Config{Targets: []Target{{DeploymentName: "api", URL: "http://api/version"}}, MaxConcurrency: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSource(tt.config, logger); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	// The endpoint serves the next version of the list on every request, then fails.
+	versions := []string{"1.0.0", "1.0.0", "bad", "1.0.0", "1.1.0"}
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if requests >= len(versions) {
+			http.Error(w, "gone", http.StatusInternalServerError)
+			return
+		}
+		version := versions[requests]
+		requests++
+		if version == "bad" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"build": {"version": %q}}`, version)
+	}))
+	defer server.Close()
+
+	source, err := NewSource(Config{
+		Targets: []Target{{DeploymentName: "api.prod", Component: "web", URL: server.URL, JSONPath: "$.build.version", Interval: 10 * time.Millisecond}},
+	}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := source.StreamEvents(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A version is reported once when it changes, failed polls don't change it.
+	for _, want := range []string{"1.0.0", "1.1.0"} {
+		select {
+		case e := <-stream:
+			if e.DeploymentName != "api.prod" || e.Component != "web" || e.Version != want {
+				t.Fatalf("expected the version %s of api.prod web, got %+v", want, e)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for the version %s", want)
+		}
+	}
+
+	cancel()
+	for e := range stream {
+		t.Fatalf("expected no more events, got %+v", e)
+	}
+}
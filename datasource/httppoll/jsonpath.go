@@ -0,0 +1,103 @@
+package httppoll
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a parsed JSONPath limited to child members and array indices,
+// like $.build.version, $['build.info'].version or $.versions[0].
+type jsonPath []any // string for a member, int for an index
+
+func parseJSONPath(s string) (jsonPath, error) {
+	rest, ok := strings.CutPrefix(s, "$")
+	if !ok {
+		return nil, fmt.Errorf("JSONPath %q must start with $", s)
+	}
+
+	// The path is never nil, so that $ selects the whole document rather than the raw response.
+	path := jsonPath{}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty member in JSONPath %q", s)
+			}
+			path = append(path, rest[:end])
+			rest = rest[end:]
+
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed [ in JSONPath %q", s)
+			}
+			selector := rest[1:end]
+			rest = rest[end+1:]
+
+			if len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0] {
+				path = append(path, selector[1:len(selector)-1])
+				continue
+			}
+
+			index, err := strconv.Atoi(selector)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid selector [%s] in JSONPath %q, expected a quoted member or an index", selector, s)
+			}
+			path = append(path, index)
+
+		default:
+			return nil, fmt.Errorf("unexpected %q in JSONPath %q", rest[0], s)
+		}
+	}
+
+	return path, nil
+}
+
+// extract returns the string or number the path points to in the JSON document.
+func (p jsonPath) extract(body []byte) (string, error) {
+	var v any
+	decoder := json.NewDecoder(strings.NewReader(string(body)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return "", fmt.Errorf("decoding the response: %w", err)
+	}
+
+	for _, selector := range p {
+		switch selector := selector.(type) {
+		case string:
+			object, ok := v.(map[string]any)
+			if !ok {
+				return "", fmt.Errorf("member %q of a non-object", selector)
+			}
+			if v, ok = object[selector]; !ok {
+				return "", fmt.Errorf("member %q not found", selector)
+			}
+
+		case int:
+			array, ok := v.([]any)
+			if !ok {
+				return "", fmt.Errorf("index %d of a non-array", selector)
+			}
+			if selector >= len(array) {
+				return "", fmt.Errorf("index %d out of range", selector)
+			}
+			v = array[selector]
+		}
+	}
+
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		return "", fmt.Errorf("the version must be a string or a number, got %T", v)
+	}
+}
@@ -0,0 +1,88 @@
+package httppoll
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    jsonPath
+		wantErr bool
+	}{
+		{path: "$", want: jsonPath{}},
+		{path: "$.version", want: jsonPath{"version"}},
+		{path: "$.build.version", want: jsonPath{"build", "version"}},
+		{path: "$['build.info'].version", want: jsonPath{"build.info", "version"}},
+		{path: `$["build"]["version"]`, want: jsonPath{"build", "version"}},
+		{path: "$.versions[0]", want: jsonPath{"versions", 0}},
+		{path: "$[1][2]", want: jsonPath{1, 2}},
+		{path: "", wantErr: true},
+		{path: "version", wantErr: true},
+		{path: "$.", wantErr: true},
+		{path: "$..version", wantErr: true},
+		{path: "$.versions[0", wantErr: true},
+		{path: "$.versions[-1]", wantErr: true},
+		{path: "$.versions[*]", wantErr: true},
+		{path: "$['version]", wantErr: true},
+		{path: "$version", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := parseJSONPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected an error %t, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONPathExtract(t *testing.T) {
+	body := []byte(`{"build": {"version": "1.2.3", "number": 42, "big": 12345678901234567890, "tags": ["a"]},
+		"build.info": {"version": "2.0.0"}, "versions": ["3.0.0", "2.0.0"], "nothing": null}`)
+
+	tests := []struct {
+		path    string
+		body    []byte
+		want    string
+		wantErr bool
+	}{
+		{path: "$.build.version", body: body, want: "1.2.3"},
+		{path: "$['build.info'].version", body: body, want: "2.0.0"},
+		{path: "$.versions[1]", body: body, want: "2.0.0"},
+		{path: "$.build.number", body: body, want: "42"},
+		{path: "$.build.big", body: body, want: "12345678901234567890"},
+		{path: "$", body: []byte(`"4.0.0"`), want: "4.0.0"},
+		{path: "$", body: []byte(" 5 \n"), want: "5"},
+		{path: "$", body: body, wantErr: true},
+		{path: "$.build", body: body, wantErr: true},
+		{path: "$.build.tags", body: body, wantErr: true},
+		{path: "$.nothing", body: body, wantErr: true},
+		{path: "$.missing", body: body, wantErr: true},
+		{path: "$.versions[2]", body: body, wantErr: true},
+		{path: "$.versions.first", body: body, wantErr: true},
+		{path: "$.build[0]", body: body, wantErr: true},
+		{path: "$.version", body: []byte(`version: 1.0.0`), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+" of "+string(tt.body), func(t *testing.T) {
+			path, err := parseJSONPath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := path.extract(tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected an error %t, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package nomad
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// AllNamespaces subscribes to the events of every namespace the token can read.
+const AllNamespaces = "*"
+
+// Config configures the connection to a Nomad cluster.
+type Config struct {
+	// Address is the HTTP API address of a Nomad agent, like https://nomad.example.com:4646.
+	Address string
+	// Token is the ACL token sent with every request, TokenFile takes precedence.
+	Token string
+	// TokenFile is a file holding the ACL token. It is read again before every request,
+	// so a rotated token is used without restarting.
+	TokenFile string
+	// Namespaces are the namespaces whose events are streamed, AllNamespaces streams all of them.
+	// Defaults to the default namespace.
+	Namespaces []string
+	// Regions are the regions whose events are streamed, defaults to the region of the agent.
+	// If more than one region is given the deployment names are prefixed with the region.
+	Regions []string
+
+	// CACert is a PEM file with the certificates of the CAs the server certificate is verified with,
+	// instead of the system pool.
+	CACert string
+	// ClientCert and ClientKey are PEM files with the client certificate and key used for mTLS.
+	// They are read again for every new connection so renewed certificates are used without restarting.
+	ClientCert string
+	ClientKey  string
+	// TLSServerName overrides the name the server certificate is verified against, like server.global.nomad.
+	TLSServerName string
+	// InsecureSkipVerify disables the verification of the server certificate.
+	InsecureSkipVerify bool
+}
+
+func (c Config) validate() error {
+	if c.Address == "" {
+		return errors.New("the Nomad address is required")
+	}
+
+	if (c.ClientCert == "") != (c.ClientKey == "") {
+		return errors.New("the client certificate and key must be given together")
+	}
+
+	for _, ns := range c.Namespaces {
+		if ns == "" {
+			return errors.New("empty Nomad namespace")
+		}
+	}
+
+	for _, region := range c.Regions {
+		if region == "" {
+			return errors.New("empty Nomad region")
+		}
+	}
+
+	return nil
+}
+
+// httpClient returns a client using the TLS settings of the config.
+func (c Config) httpClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         c.TLSServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec // Opt-in for test clusters with self-signed certificates
+	}
+
+	if c.CACert != "" {
+		pem, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("reading the CA certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.ClientCert != "" {
+		// Fail early on a bad certificate rather than on the first connection.
+		if _, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey); err != nil {
+			return nil, fmt.Errorf("loading the client certificate: %w", err)
+		}
+
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+			if err != nil {
+				return nil, fmt.Errorf("loading the client certificate: %w", err)
+			}
+			return &cert, nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// token returns the ACL token, reading it from TokenFile if it is set.
+func (c Config) token() (string, error) {
+	if c.TokenFile == "" {
+		return c.Token, nil
+	}
+
+	b, err := os.ReadFile(c.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("reading the Nomad token: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
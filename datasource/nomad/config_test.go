@@ -0,0 +1,396 @@
+package nomad
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"overseer/datasource"
+)
+
+// recorder records the ACL token and the name of the client certificate of the requests to the fake.
+type recorder struct {
+	fake *fakeNomad
+
+	mu      sync.Mutex
+	tokens  []string
+	clients []string
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	r.tokens = append(r.tokens, req.Header.Get("X-Nomad-Token"))
+	if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+		r.clients = append(r.clients, req.TLS.PeerCertificates[0].Subject.CommonName)
+	}
+	r.mu.Unlock()
+
+	r.fake.ServeHTTP(w, req)
+}
+
+// certificate is a certificate and its key, and the PEM files they are written to.
+type certificate struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+	keyFile  string
+}
+
+// newCertificate creates a certificate signed by the parent, or a self-signed CA if the parent is nil,
+// and writes it to PEM files named after the common name in dir.
+func newCertificate(t *testing.T, dir, name string, parent *certificate) certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := certificate{
+		cert:     cert,
+		key:      key,
+		certFile: filepath.Join(dir, name+".pem"),
+		keyFile:  filepath.Join(dir, name+"-key.pem"),
+	}
+	writeFile(t, c.certFile, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	writeFile(t, c.keyFile, string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})))
+	return c
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lookupVersions looks up the versions of the job shop through the source.
+func lookupVersions(source *Source) error {
+	var resp struct {
+		Versions []job
+	}
+	return source.get(context.Background(), "", "default", "/v1/job/shop/versions", &resp)
+}
+
+func TestConfigTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCertificate(t, dir, "clients", nil)
+	client := newCertificate(t, dir, "overseer", &ca)
+	renewed := newCertificate(t, dir, "overseer-renewed", &ca)
+	other := newCertificate(t, dir, "other", nil)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	rec := &recorder{fake: &fakeNomad{versions: map[string][]job{"shop": {testJob("default", "shop", 1)}}}}
+	server := httptest.NewUnstartedServer(rec)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	serverCA := filepath.Join(dir, "server-ca.pem")
+	writeFile(t, serverCA, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})))
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name:    "system CAs",
+			config:  Config{ClientCert: client.certFile, ClientKey: client.keyFile},
+			wantErr: true,
+		},
+		{
+			name:    "other CA",
+			config:  Config{CACert: other.certFile, ClientCert: client.certFile, ClientKey: client.keyFile},
+			wantErr: true,
+		},
+		{
+			name:    "no client certificate",
+			config:  Config{CACert: serverCA},
+			wantErr: true,
+		},
+		{
+			name:    "client certificate of another CA",
+			config:  Config{CACert: serverCA, ClientCert: other.certFile, ClientKey: other.keyFile},
+			wantErr: true,
+		},
+		{
+			name:   "mTLS",
+			config: Config{CACert: serverCA, ClientCert: client.certFile, ClientKey: client.keyFile},
+		},
+		{
+			// The certificate of httptest servers is valid for example.com.
+			name:   "server name",
+			config: Config{CACert: serverCA, ClientCert: client.certFile, ClientKey: client.keyFile, TLSServerName: "example.com"},
+		},
+		{
+			name:    "wrong server name",
+			config:  Config{CACert: serverCA, ClientCert: client.certFile, ClientKey: client.keyFile, TLSServerName: "server.global.nomad"},
+			wantErr: true,
+		},
+		{
+			name:   "skip verify",
+			config: Config{InsecureSkipVerify: true, ClientCert: client.certFile, ClientKey: client.keyFile},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Address = server.URL
+			source, err := NewSource(tt.config, slog.New(slog.DiscardHandler))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = lookupVersions(source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected an error=%t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("renewed client certificate", func(t *testing.T) {
+		certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+		copyFile := func(from, to string) {
+			b, err := os.ReadFile(from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			writeFile(t, to, string(b))
+		}
+		copyFile(client.certFile, certFile)
+		copyFile(client.keyFile, keyFile)
+
+		source, err := NewSource(Config{Address: server.URL, CACert: serverCA, ClientCert: certFile, ClientKey: keyFile}, slog.New(slog.DiscardHandler))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec.mu.Lock()
+		rec.clients = nil
+		rec.mu.Unlock()
+
+		if err := lookupVersions(source); err != nil {
+			t.Fatal(err)
+		}
+
+		// The renewed certificate is used for the next connection.
+		copyFile(renewed.certFile, certFile)
+		copyFile(renewed.keyFile, keyFile)
+		source.client.CloseIdleConnections()
+		if err := lookupVersions(source); err != nil {
+			t.Fatal(err)
+		}
+
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		if want := []string{"overseer", "overseer-renewed"}; !slices.Equal(rec.clients, want) {
+			t.Fatalf("expected the client certificates %q, got %q", want, rec.clients)
+		}
+	})
+}
+
+func TestConfigToken(t *testing.T) {
+	rec := &recorder{fake: &fakeNomad{versions: map[string][]job{"shop": {testJob("default", "shop", 1)}}}}
+	server := httptest.NewTLSServer(rec)
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	writeFile(t, tokenFile, "first-token\n")
+
+	// The token file takes precedence over the token.
+	source, err := NewSource(Config{Address: server.URL, Token: "static-token", TokenFile: tokenFile, InsecureSkipVerify: true}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lookupVersions(source); err != nil {
+		t.Fatal(err)
+	}
+
+	// A rotated token is used from the next request.
+	writeFile(t, tokenFile, "second-token")
+	if err := lookupVersions(source); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Remove(tokenFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := lookupVersions(source); err == nil {
+		t.Fatal("expected an error without the token file")
+	}
+
+	static, err := NewSource(Config{Address: server.URL, Token: "static-token", InsecureSkipVerify: true}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lookupVersions(static); err != nil {
+		t.Fatal(err)
+	}
+
+	anonymous, err := NewSource(Config{Address: server.URL, InsecureSkipVerify: true}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lookupVersions(anonymous); err != nil {
+		t.Fatal(err)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if want := []string{"first-token", "second-token", "static-token", ""}; !slices.Equal(rec.tokens, want) {
+		t.Fatalf("expected the tokens %q, got %q", want, rec.tokens)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"no address", Config{}},
+		{"client certificate without key", Config{Address: "https://nomad:4646", ClientCert: "client.pem"}},
+		{"client key without certificate", Config{Address: "https://nomad:4646", ClientKey: "client-key.pem"}},
+		{"empty namespace", Config{Address: "https://nomad:4646", Namespaces: []string{"web", ""}}},
+		{"empty region", Config{Address: "https://nomad:4646", Regions: []string{""}}},
+		{"missing CA certificate", Config{Address: "https://nomad:4646", CACert: filepath.Join(t.TempDir(), "ca.pem")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSource(tt.config, slog.New(slog.DiscardHandler)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+// receive returns the next n events of the stream.
+func receive(t *testing.T, stream <-chan datasource.Event, n int) []datasource.Event {
+	t.Helper()
+
+	var events []datasource.Event
+	for len(events) < n {
+		select {
+		case e := <-stream:
+			events = append(events, e)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out after the events %+v", events)
+		}
+	}
+	return events
+}
+
+func TestNamespacesAndRegions(t *testing.T) {
+	registered := func(namespace string) []streamItem {
+		j := testJob(namespace, "shop", 1, dockerTask("web", "shop/web:1.0.0"))
+		return []streamItem{{Index: 10, Events: []event{jobEvent("JobRegistered", 10, j)}}}
+	}
+
+	tests := []struct {
+		name    string
+		config  Config
+		streams map[string][]streamItem
+		want    []string
+	}{
+		{
+			name:    "default namespace",
+			streams: map[string][]streamItem{"/default": registered("default")},
+			want:    []string{"shop.app.web"},
+		},
+		{
+			name:   "namespaces",
+			config: Config{Namespaces: []string{"web", "default"}},
+			streams: map[string][]streamItem{
+				"/default": registered("default"),
+				"/web":     registered("web"),
+			},
+			want: []string{"shop.app.web", "web.shop.app.web"},
+		},
+		{
+			// The other namespaces are not streamed again.
+			name:    "all namespaces",
+			config:  Config{Namespaces: []string{"web", AllNamespaces}},
+			streams: map[string][]streamItem{"/*": append(registered("web"), registered("default")...)},
+			want:    []string{"shop.app.web", "web.shop.app.web"},
+		},
+		{
+			name:    "one region",
+			config:  Config{Regions: []string{"eu"}},
+			streams: map[string][]streamItem{"eu/default": registered("default")},
+			want:    []string{"shop.app.web"},
+		},
+		{
+			name:   "regions",
+			config: Config{Namespaces: []string{"default", "web"}, Regions: []string{"eu", "us"}},
+			streams: map[string][]streamItem{
+				"eu/default": registered("default"),
+				"eu/web":     registered("web"),
+				"us/default": registered("default"),
+				"us/web":     registered("web"),
+			},
+			want: []string{"eu.shop.app.web", "eu.web.shop.app.web", "us.shop.app.web", "us.web.shop.app.web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stream := startSource(t, &fakeNomad{streams: tt.streams}, tt.config)
+
+			var names []string
+			for _, e := range receive(t, stream, len(tt.want)) {
+				names = append(names, e.DeploymentName)
+			}
+			slices.Sort(names)
+			if !slices.Equal(names, tt.want) {
+				t.Fatalf("expected the deployments %q, got %q", tt.want, names)
+			}
+		})
+	}
+}
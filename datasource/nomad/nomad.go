@@ -1,24 +1,55 @@
 package nomad
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net/http"
+	"net/url"
 	"overseer/datasource"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
+// reconnectDelay is how long to wait before connecting to an event stream again.
+const reconnectDelay = 5 * time.Second
+
 type Source struct {
-	address string
-	token   string
-	logger  *slog.Logger
+	config Config
+	client *http.Client
+	logger *slog.Logger
+	// reconnectDelay is reconnectDelay, except in tests.
+	reconnectDelay time.Duration
+
+	mu sync.Mutex
+	// jobs caches the latest versions of every job, to look up the images run by deployments and allocations.
+	jobs map[jobKey][]job
+	// allocStatus is the last client status of the allocations that are not yet terminal.
+	allocStatus map[string]string
+}
+
+// target is one of the event streams of the Source, the events of a namespace in a region.
+type target struct {
+	// region is empty for the region of the agent.
+	region    string
+	namespace string
 }
 
+type jobKey struct {
+	region    string
+	namespace string
+	id        string
+}
+
+// jobVersionsCached is how many versions of every job are cached.
+const jobVersionsCached = 10
+
 type streamItem struct {
 	Index  int64
 	Events []event
@@ -38,20 +69,63 @@ type job struct {
 	Namespace  string
 	ID         string
 	Name       string // Whats the difference to ID?
+	Version    uint64
 	TaskGroups []taskGroup
 	SubmitTime int64 // Unix timestamp in nanoseconds
-	// Meta       map[string]any
+	Meta       map[string]string
+	Stop       bool // Set when the job was stopped but not yet purged
 }
 
 type taskGroup struct {
 	Name  string
 	Tasks []task
+	Meta  map[string]string
 }
 
 type task struct {
 	Name   string
 	Driver string
 	Config json.RawMessage
+	Meta   map[string]string
+}
+
+// Well-known meta keys of jobs, groups and tasks that are recorded as deployment metadata.
+// The other meta keys are recorded as labels, meta of tasks overrides the group which overrides the job.
+const (
+	MetaGitCommit   = "git_commit"
+	MetaBuildURL    = "build_url"
+	MetaDeployer    = "deployer"
+	MetaImageDigest = "image_digest"
+)
+
+// deployment is a Nomad deployment, the rollout of a version of a job.
+type deployment struct {
+	ID                string
+	Namespace         string
+	JobID             string
+	JobVersion        uint64
+	Status            string
+	StatusDescription string
+	TaskGroups        map[string]deploymentState
+	ModifyTime        int64 // Unix timestamp in nanoseconds, zero before Nomad 1.7
+}
+
+type deploymentState struct {
+	DesiredTotal    int32
+	PlacedAllocs    int32
+	HealthyAllocs   int32
+	UnhealthyAllocs int32
+}
+
+// allocation is a Nomad allocation as sent on the event stream, without its job.
+type allocation struct {
+	ID           string
+	Namespace    string
+	JobID        string
+	TaskGroup    string
+	ClientStatus string
+	DeploymentID string
+	ModifyTime   int64 // Unix timestamp in nanoseconds
 }
 
 type dockerConfig struct {
@@ -59,42 +133,90 @@ type dockerConfig struct {
 }
 
 // TODO: Should handle the initial sync of existing jobs.
-func NewSource(address, token string, logger *slog.Logger) *Source {
-	address = address + "/v1/event/stream?topic=Job"
+func NewSource(config Config, logger *slog.Logger) (*Source, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := config.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	config.Address = strings.TrimSuffix(config.Address, "/")
+	switch {
+	case len(config.Namespaces) == 0:
+		config.Namespaces = []string{"default"}
+	case slices.Contains(config.Namespaces, AllNamespaces):
+		// The other namespaces would only stream their events twice.
+		config.Namespaces = []string{AllNamespaces}
+	}
 
 	return &Source{
-		address: address,
-		token:   token,
-		logger:  logger,
+		config:      config,
+		client:      client,
+		logger:      logger,
+		jobs:        map[jobKey][]job{},
+		allocStatus: map[string]string{},
+
+		reconnectDelay: reconnectDelay,
+	}, nil
+}
+
+// targets returns an event stream for every configured namespace in every configured region.
+func (n *Source) targets() []target {
+	regions := n.config.Regions
+	if len(regions) == 0 {
+		regions = []string{""}
+	}
+
+	var targets []target
+	for _, region := range regions {
+		for _, namespace := range n.config.Namespaces {
+			targets = append(targets, target{region: region, namespace: namespace})
+		}
 	}
+	return targets
 }
 
 func (n *Source) StreamEvents(ctx context.Context) (<-chan datasource.Event, error) {
 	stream := make(chan datasource.Event, 10)
 
-	go func() {
-		defer close(stream)
+	var wg sync.WaitGroup
+	for _, t := range n.targets() {
+		logger := n.logger.With("region", t.region, "namespace", t.namespace)
 
-		for {
-			err := n.runStream(ctx, stream)
-			if errors.Is(err, context.Canceled) {
-				return
-			}
+		wg.Go(func() {
+			for {
+				err := n.runStream(ctx, t, stream)
+				if ctx.Err() != nil {
+					return
+				}
 
-			n.logger.Error("stream error", "error", err)
+				logger.Error("stream error", "error", err)
 
-			// TODO: Sleep with backoff?
-			time.Sleep(5 * time.Second)
+				// TODO: Sleep with backoff?
+				select {
+				case <-time.After(n.reconnectDelay):
+				case <-ctx.Done():
+					return
+				}
 
-			n.logger.Info("reconnecting to Nomad event stream...")
-		}
+				logger.Info("reconnecting to Nomad event stream...")
+			}
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(stream)
 	}()
 
 	return stream, nil
 }
 
-func (n *Source) runStream(ctx context.Context, stream chan datasource.Event) error {
-	b, err := n.getNomadConnection(ctx)
+func (n *Source) runStream(ctx context.Context, t target, stream chan datasource.Event) error {
+	b, err := n.getNomadConnection(ctx, t)
 	if err != nil {
 		return err
 	}
@@ -109,79 +231,463 @@ func (n *Source) runStream(ctx context.Context, stream chan datasource.Event) er
 		}
 
 		for _, event := range streamItem.Events {
-			if event.Type != "JobRegistered" { // TODO: For now, only handle JobRegistered
+			var err error
+			switch event.Topic {
+			case "Job":
+				err = n.handleJobEvent(ctx, t, event, stream)
+			case "Deployment":
+				err = n.handleDeploymentEvent(ctx, t, event, stream)
+			case "Allocation":
+				err = n.handleAllocationEvent(ctx, t, event, stream)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (n *Source) handleJobEvent(ctx context.Context, t target, event event, stream chan datasource.Event) error {
+	if event.Type != "JobRegistered" && event.Type != "JobDeregistered" {
+		return nil
+	}
+
+	var jp struct {
+		Job *job
+	}
+	if err := json.Unmarshal(event.Payload, &jp); err != nil {
+		return fmt.Errorf("parsing job payload: %w", err)
+	}
+	if jp.Job == nil {
+		n.logger.Warn("job event without a job", "type", event.Type, "key", event.Key)
+		return nil
+	}
+	j := *jp.Job
+	n.cacheJob(t, j)
+
+	// Stopping a job deregisters it with Stop set, purging it deregisters it as it was last registered,
+	// and a job can also be registered as stopped.
+	undeployed := event.Type == "JobDeregistered" || j.Stop
+
+	at := time.Unix(0, j.SubmitTime)
+	if event.Type == "JobDeregistered" && !j.Stop {
+		// A purged job is the last registered version, its submit time is not when it was purged.
+		at = time.Now()
+	}
+
+	for _, tg := range j.TaskGroups {
+		for _, task := range tg.Tasks {
+			if task.Driver != "docker" {
 				continue
 			}
 
-			var jp struct {
-				Job job
+			if undeployed {
+				e := datasource.Event{
+					Id:             event.Key,
+					DeploymentName: n.deploymentName(t, j.Namespace, j.Name, tg.Name, task.Name),
+					DeployedAt:     at,
+					Sequence:       int64(event.Index),
+					Undeployed:     true,
+				}
+				select {
+				case stream <- e:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				continue
 			}
-			if err := json.Unmarshal(event.Payload, &jp); err != nil {
-				return fmt.Errorf("parsing job payload: %w", err)
+
+			image, imageVersion, digest, ok := n.taskImage(task)
+			if !ok {
+				continue
 			}
-			j := jp.Job
 
-			for _, tg := range j.TaskGroups {
-				for _, task := range tg.Tasks {
-					if task.Driver != "docker" {
-						continue
-					}
+			e := datasource.Event{
+				Id:             event.Key,
+				DeploymentName: n.deploymentName(t, j.Namespace, j.Name, tg.Name, task.Name),
+				Version:        imageVersion,
+				DeployedAt:     at,
+				Sequence:       int64(event.Index),
+				Metadata:       buildMetadata(image, digest, j.Meta, tg.Meta, task.Meta),
+			}
+			select {
+			case stream <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
 
-					var config dockerConfig
-					if err := json.Unmarshal(task.Config, &config); err != nil {
-						n.logger.Error("parsing docker config", "error", err)
-						continue
-					}
+	return nil
+}
 
-					imageVersion := extractVersionFromImage(config.Image)
+// handleDeploymentEvent reports the progress of rolling out the version of the job the deployment is for.
+func (n *Source) handleDeploymentEvent(ctx context.Context, t target, event event, stream chan datasource.Event) error {
+	var dp struct {
+		Deployment *deployment
+	}
+	if err := json.Unmarshal(event.Payload, &dp); err != nil {
+		return fmt.Errorf("parsing deployment payload: %w", err)
+	}
+	if dp.Deployment == nil {
+		n.logger.Warn("deployment event without a deployment", "type", event.Type, "key", event.Key)
+		return nil
+	}
+	d := *dp.Deployment
 
-					if imageVersion == "" || imageVersion == "latest" {
-						n.logger.Warn("could not determine image version", "image", config.Image)
-						continue
-					}
+	status, ok := rolloutStatus(d.Status)
+	if !ok {
+		n.logger.Warn("unknown deployment status", "deployment", d.ID, "status", d.Status)
+		return nil
+	}
 
-					stream <- datasource.Event{
-						Id:             event.Key,
-						DeploymentName: buildDeploymentName(j.Namespace, j.Name, tg.Name, task.Name),
-						Version:        imageVersion,
-						DeployedAt:     time.Unix(0, j.SubmitTime),
-					}
-				}
+	j, err := n.jobVersion(ctx, t, d.Namespace, d.JobID, d.JobVersion)
+	if err != nil {
+		n.logger.Error("looking up the job of a deployment", "deployment", d.ID, "error", err)
+		return nil
+	}
+
+	at := time.Now()
+	if d.ModifyTime != 0 {
+		at = time.Unix(0, d.ModifyTime)
+	}
+
+	for _, tg := range j.TaskGroups {
+		state, ok := d.TaskGroups[tg.Name]
+		if !ok {
+			continue
+		}
+
+		for _, task := range tg.Tasks {
+			_, imageVersion, _, ok := n.taskImage(task)
+			if !ok {
+				continue
+			}
+
+			e := datasource.Event{
+				Id:             event.Key,
+				DeploymentName: n.deploymentName(t, j.Namespace, j.Name, tg.Name, task.Name),
+				Version:        imageVersion,
+				DeployedAt:     at,
+				Sequence:       int64(event.Index),
+				Rollout: &datasource.Rollout{
+					Status:      status,
+					Description: d.StatusDescription,
+					Healthy:     state.HealthyAllocs,
+					Desired:     state.DesiredTotal,
+				},
+			}
+			select {
+			case stream <- e:
+			case <-ctx.Done():
+				return ctx.Err()
 			}
 		}
 	}
+
+	return nil
 }
 
-func buildDeploymentName(namespace, jobName, taskGroupName, taskName string) string {
-	if namespace == "default" {
-		return strings.Join([]string{jobName, taskGroupName, taskName}, ".")
+// handleAllocationEvent reports the version of an allocation as running or failed when its client status changes.
+// Only allocations that are not part of a deployment are handled, the rollout of the others is reported by their deployment.
+func (n *Source) handleAllocationEvent(ctx context.Context, t target, event event, stream chan datasource.Event) error {
+	var ap struct {
+		Allocation *allocation
+	}
+	if err := json.Unmarshal(event.Payload, &ap); err != nil {
+		return fmt.Errorf("parsing allocation payload: %w", err)
+	}
+	if ap.Allocation == nil {
+		n.logger.Warn("allocation event without an allocation", "type", event.Type, "key", event.Key)
+		return nil
+	}
+	a := *ap.Allocation
+
+	// Terminal allocations are forgotten, so later updates of them, like garbage collection, are ignored.
+	n.mu.Lock()
+	previous, seen := n.allocStatus[a.ID]
+	terminal := a.ClientStatus == "complete" || a.ClientStatus == "failed" || a.ClientStatus == "lost"
+	if terminal {
+		delete(n.allocStatus, a.ID)
+	} else {
+		n.allocStatus[a.ID] = a.ClientStatus
+	}
+	n.mu.Unlock()
+
+	if a.DeploymentID != "" || previous == a.ClientStatus || (terminal && !seen) {
+		return nil
+	}
+
+	var status, description string
+	switch a.ClientStatus {
+	case "running":
+		status = datasource.RolloutSuccessful
+	case "failed", "lost":
+		status = datasource.RolloutFailed
+		description = fmt.Sprintf("allocation %s is %s", a.ID, a.ClientStatus)
+	default:
+		return nil
 	}
-	return strings.Join([]string{namespace, jobName, taskGroupName, taskName}, ".")
+
+	j, err := n.allocationJob(ctx, t, a.Namespace, a.ID)
+	if err != nil {
+		n.logger.Error("looking up the job of an allocation", "allocation", a.ID, "error", err)
+		return nil
+	}
+	n.cacheJob(t, j)
+
+	for _, tg := range j.TaskGroups {
+		if tg.Name != a.TaskGroup {
+			continue
+		}
+
+		for _, task := range tg.Tasks {
+			_, imageVersion, _, ok := n.taskImage(task)
+			if !ok {
+				continue
+			}
+
+			e := datasource.Event{
+				Id:             event.Key,
+				DeploymentName: n.deploymentName(t, j.Namespace, j.Name, tg.Name, task.Name),
+				Version:        imageVersion,
+				DeployedAt:     time.Unix(0, a.ModifyTime),
+				Sequence:       int64(event.Index),
+				Rollout: &datasource.Rollout{
+					Status:      status,
+					Description: description,
+				},
+			}
+			select {
+			case stream <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	return nil
+}
+
+// rolloutStatus translates the status of a Nomad deployment to a rollout status.
+func rolloutStatus(status string) (string, bool) {
+	switch status {
+	case "pending", "initializing":
+		return datasource.RolloutPending, true
+	case "running", "unblocking":
+		return datasource.RolloutRunning, true
+	case "paused", "blocked":
+		return datasource.RolloutPaused, true
+	case "successful":
+		return datasource.RolloutSuccessful, true
+	case "failed":
+		return datasource.RolloutFailed, true
+	case "cancelled":
+		return datasource.RolloutCancelled, true
+	}
+	return "", false
+}
+
+// taskImage returns the image, version and digest run by a docker task, ok is false for other tasks
+// and for images without a usable version.
+func (n *Source) taskImage(t task) (image, version, digest string, ok bool) {
+	if t.Driver != "docker" {
+		return "", "", "", false
+	}
+
+	var config dockerConfig
+	if err := json.Unmarshal(t.Config, &config); err != nil {
+		n.logger.Error("parsing docker config", "error", err)
+		return "", "", "", false
+	}
+
+	image, version, digest = datasource.ParseImage(config.Image)
+	if version == "" || version == "latest" {
+		n.logger.Warn("could not determine image version", "image", config.Image)
+		return "", "", "", false
+	}
+
+	return image, version, digest, true
+}
+
+// cacheJob caches the version of the job, keeping only the latest jobVersionsCached versions.
+func (n *Source) cacheJob(t target, j job) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	key := jobKey{t.region, j.Namespace, j.ID}
+	versions := slices.DeleteFunc(n.jobs[key], func(cached job) bool { return cached.Version == j.Version })
+	versions = append(versions, j)
+	slices.SortFunc(versions, func(a, b job) int { return cmp.Compare(b.Version, a.Version) })
+	n.jobs[key] = versions[:min(len(versions), jobVersionsCached)]
+}
+
+// jobVersion returns the given version of a job, from the cache or else from Nomad.
+func (n *Source) jobVersion(ctx context.Context, t target, namespace, id string, version uint64) (job, error) {
+	find := func() (job, bool) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		versions := n.jobs[jobKey{t.region, namespace, id}]
+		i := slices.IndexFunc(versions, func(j job) bool { return j.Version == version })
+		if i < 0 {
+			return job{}, false
+		}
+		return versions[i], true
+	}
+
+	if j, ok := find(); ok {
+		return j, nil
+	}
+
+	var resp struct {
+		Versions []job
+	}
+	if err := n.get(ctx, t.region, namespace, "/v1/job/"+url.PathEscape(id)+"/versions", &resp); err != nil {
+		return job{}, err
+	}
+	for _, j := range resp.Versions {
+		n.cacheJob(t, j)
+	}
+
+	if j, ok := find(); ok {
+		return j, nil
+	}
+	return job{}, fmt.Errorf("version %d of job %q not found", version, id)
 }
 
-func extractVersionFromImage(image string) string {
-	lastColon := strings.LastIndex(image, ":")
-	if lastColon != -1 && lastColon < len(image)-1 {
-		return image[lastColon+1:]
+// allocationJob returns the job the allocation runs, the allocations on the event stream don't include it.
+func (n *Source) allocationJob(ctx context.Context, t target, namespace, id string) (job, error) {
+	var resp struct {
+		Job *job
 	}
-	return ""
+	if err := n.get(ctx, t.region, namespace, "/v1/allocation/"+url.PathEscape(id), &resp); err != nil {
+		return job{}, err
+	}
+	if resp.Job == nil {
+		return job{}, fmt.Errorf("allocation %q has no job", id)
+	}
+	return *resp.Job, nil
+}
+
+// deploymentName names the deployments of a task, the region is only included when streaming from multiple regions.
+func (n *Source) deploymentName(t target, namespace, jobName, taskGroupName, taskName string) string {
+	var region string
+	if len(n.config.Regions) > 1 {
+		region = t.region
+	}
+	return buildDeploymentName(region, namespace, jobName, taskGroupName, taskName)
 }
 
-func (n *Source) getNomadConnection(ctx context.Context) (io.ReadCloser, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", n.address, nil)
+// buildDeploymentName joins the parts of the name with dots, leaving out an empty region and the default namespace.
+func buildDeploymentName(region, namespace, jobName, taskGroupName, taskName string) string {
+	parts := []string{jobName, taskGroupName, taskName}
+	if namespace != "default" {
+		parts = append([]string{namespace}, parts...)
+	}
+	if region != "" {
+		parts = append([]string{region}, parts...)
+	}
+	return strings.Join(parts, ".")
+}
+
+// buildMetadata merges the meta of the job, group and task, where later ones take precedence,
+// and records the well-known keys as deployment metadata and the rest as labels.
+func buildMetadata(image, digest string, metas ...map[string]string) datasource.Metadata {
+	merged := map[string]string{}
+	for _, meta := range metas {
+		maps.Copy(merged, meta)
+	}
+
+	m := datasource.Metadata{
+		Image:       image,
+		ImageDigest: digest,
+	}
+
+	for key, value := range merged {
+		switch key {
+		case MetaGitCommit:
+			m.GitCommit = value
+		case MetaBuildURL:
+			m.BuildURL = value
+		case MetaDeployer:
+			m.Deployer = value
+		case MetaImageDigest:
+			// The digest pinned in the image reference is what is actually deployed.
+			if m.ImageDigest == "" {
+				m.ImageDigest = value
+			}
+		default:
+			if m.Labels == nil {
+				m.Labels = map[string]string{}
+			}
+			m.Labels[key] = value
+		}
+	}
+
+	return m
+}
+
+// get decodes the JSON response of a Nomad API request into v, an empty region uses the region of the agent.
+func (n *Source) get(ctx context.Context, region, namespace, path string, v any) error {
+	query := url.Values{"namespace": {namespace}}
+	if region != "" {
+		query.Set("region", region)
+	}
+
+	resp, err := n.do(ctx, path+"?"+query.Encode())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding the response of %s: %w", path, err)
+	}
+	return nil
+}
+
+// do sends a GET request with the current token and returns the response if it has status OK.
+func (n *Source) do(ctx context.Context, pathAndQuery string) (*http.Response, error) {
+	token, err := n.config.token()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", n.config.Address+pathAndQuery, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating the request: %w", err)
 	}
-	req.Header.Set("X-Nomad-Token", n.token)
+	if token != "" {
+		req.Header.Set("X-Nomad-Token", token)
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := n.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("connecting to Nomad: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
 		return nil, fmt.Errorf("bad status from Nomad: %s", resp.Status)
 	}
 
+	return resp, nil
+}
+
+func (n *Source) getNomadConnection(ctx context.Context, t target) (io.ReadCloser, error) {
+	query := url.Values{
+		"topic":     {"Job", "Deployment", "Allocation"},
+		"namespace": {t.namespace},
+	}
+	if t.region != "" {
+		query.Set("region", t.region)
+	}
+
+	resp, err := n.do(ctx, "/v1/event/stream?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
 	return resp.Body, nil
 }
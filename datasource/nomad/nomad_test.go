@@ -0,0 +1,534 @@
+package nomad
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"overseer/datasource"
+)
+
+// fakeNomad serves the parts of the Nomad HTTP API the source uses. Every connection to the event stream
+// gets the items of its region and namespace, keyed by "region/namespace", and then stays open.
+type fakeNomad struct {
+	streams map[string][]streamItem
+	// versions are the versions of the jobs by id, allocations the jobs of the allocations by id.
+	versions    map[string][]job
+	allocations map[string]job
+
+	mu sync.Mutex
+	// lookups are the paths of the requests other than the event stream.
+	lookups []string
+}
+
+func (f *fakeNomad) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/event/stream" {
+		f.mu.Lock()
+		f.lookups = append(f.lookups, r.URL.Path)
+		f.mu.Unlock()
+	}
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/job/") && strings.HasSuffix(r.URL.Path, "/versions"):
+		versions, ok := f.versions[strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/job/"), "/versions")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"Versions": versions})
+
+	case strings.HasPrefix(r.URL.Path, "/v1/allocation/"):
+		j, ok := f.allocations[strings.TrimPrefix(r.URL.Path, "/v1/allocation/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"Job": j})
+
+	case r.URL.Path == "/v1/event/stream":
+		query := r.URL.Query()
+		for _, item := range f.streams[query.Get("region")+"/"+query.Get("namespace")] {
+			json.NewEncoder(w).Encode(item)
+		}
+		w.(http.Flusher).Flush()
+
+		<-r.Context().Done()
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// requested returns the paths of the lookups so far.
+func (f *fakeNomad) requested() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.lookups)
+}
+
+// startSource streams the events of a source connected to the fake, until the test ends.
+func startSource(t *testing.T, fake *fakeNomad, config Config) (*Source, <-chan datasource.Event) {
+	t.Helper()
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	if config.Address == "" {
+		config.Address = server.URL
+	}
+	source, err := NewSource(config, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source.reconnectDelay = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := source.StreamEvents(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		for range stream {
+		}
+	})
+
+	return source, stream
+}
+
+// markerKey is the key of the event that ends the events of a test, see collect.
+const markerKey = "marker"
+
+// marker is an event registering a job, streamed after the events of a test.
+func marker(index uint64) event {
+	j := testJob("default", "marker", 1, dockerTask("web", "marker/web:1.0.0"))
+	e := jobEvent("JobRegistered", index, j)
+	e.Key = markerKey
+	return e
+}
+
+// collect returns the events streamed before the event of the marker.
+func collect(t *testing.T, stream <-chan datasource.Event) []datasource.Event {
+	t.Helper()
+
+	var events []datasource.Event
+	for {
+		select {
+		case e := <-stream:
+			if e.Id == markerKey {
+				return events
+			}
+			events = append(events, e)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out after the events %+v", events)
+		}
+	}
+}
+
+func testJob(namespace, id string, version uint64, tasks ...task) job {
+	return job{
+		Namespace:  namespace,
+		ID:         id,
+		Name:       id,
+		Version:    version,
+		SubmitTime: time.Date(2025, 6, 1, 12, 0, int(version), 0, time.UTC).UnixNano(),
+		TaskGroups: []taskGroup{{Name: "app", Tasks: tasks}},
+	}
+}
+
+func dockerTask(name, image string) task {
+	return task{Name: name, Driver: "docker", Config: json.RawMessage(fmt.Sprintf(`{"image": %q}`, image))}
+}
+
+func jobEvent(typ string, index uint64, j job) event {
+	payload, _ := json.Marshal(map[string]any{"Job": j})
+	return event{Topic: "Job", Type: typ, Key: j.ID, Namespace: j.Namespace, Index: index, Payload: payload}
+}
+
+func deploymentEvent(index uint64, d deployment) event {
+	payload, _ := json.Marshal(map[string]any{"Deployment": d})
+	return event{Topic: "Deployment", Type: "DeploymentStatusUpdate", Key: d.ID, Namespace: d.Namespace, Index: index, Payload: payload}
+}
+
+func allocationEvent(index uint64, a allocation) event {
+	payload, _ := json.Marshal(map[string]any{"Allocation": a})
+	return event{Topic: "Allocation", Type: "AllocationUpdated", Key: a.ID, Namespace: a.Namespace, Index: index, Payload: payload}
+}
+
+func TestJobEvents(t *testing.T) {
+	shop := testJob("default", "shop", 3,
+		dockerTask("web", "shop/web:1.2.0"),
+		dockerTask("worker", "registry:5000/shop/worker:1.2.0@sha256:worker"),
+		dockerTask("latest", "shop/latest:latest"),
+		task{Name: "logs", Driver: "exec"},
+	)
+	submitted := time.Unix(0, shop.SubmitTime)
+
+	stopped := shop
+	stopped.Stop = true
+
+	type want struct {
+		name, version string
+		undeployed    bool
+	}
+	tests := []struct {
+		name  string
+		event event
+		want  []want
+		// purged is set when the events happen when they are received rather than when the job was submitted.
+		purged bool
+	}{
+		{
+			name:  "registered",
+			event: jobEvent("JobRegistered", 10, shop),
+			want:  []want{{"shop.app.web", "1.2.0", false}, {"shop.app.worker", "1.2.0", false}},
+		},
+		{
+			name:  "registered as stopped",
+			event: jobEvent("JobRegistered", 10, stopped),
+			want:  []want{{"shop.app.web", "", true}, {"shop.app.worker", "", true}, {"shop.app.latest", "", true}},
+		},
+		{
+			name:  "stopped",
+			event: jobEvent("JobDeregistered", 10, stopped),
+			want:  []want{{"shop.app.web", "", true}, {"shop.app.worker", "", true}, {"shop.app.latest", "", true}},
+		},
+		{
+			name:   "purged",
+			event:  jobEvent("JobDeregistered", 10, shop),
+			want:   []want{{"shop.app.web", "", true}, {"shop.app.worker", "", true}, {"shop.app.latest", "", true}},
+			purged: true,
+		},
+		{
+			name:  "other event",
+			event: jobEvent("JobBatchDeregistered", 10, shop),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeNomad{streams: map[string][]streamItem{
+				"/default": {{Index: 11, Events: []event{tt.event, marker(11)}}},
+			}}
+			start := time.Now()
+			_, stream := startSource(t, fake, Config{})
+
+			events := collect(t, stream)
+
+			if len(events) != len(tt.want) {
+				t.Fatalf("expected %d events, got %+v", len(tt.want), events)
+			}
+			for i, w := range tt.want {
+				e := events[i]
+				if e.DeploymentName != w.name || e.Version != w.version || e.Undeployed != w.undeployed {
+					t.Fatalf("event %d: expected %+v, got %+v", i, w, e)
+				}
+				if e.Id != "shop" || e.Sequence != 10 {
+					t.Fatalf("event %d: expected the key and index of the Nomad event, got %+v", i, e)
+				}
+				if tt.purged && e.DeployedAt.Before(start) {
+					t.Fatalf("event %d: expected a purge to happen when it is received, got %s", i, e.DeployedAt)
+				}
+				if !tt.purged && !e.DeployedAt.Equal(submitted) {
+					t.Fatalf("event %d: expected the submit time %s, got %s", i, submitted, e.DeployedAt)
+				}
+			}
+		})
+	}
+}
+
+func TestJobMetadata(t *testing.T) {
+	j := testJob("default", "shop", 3, dockerTask("web", "registry:5000/shop/web:1.2.0@sha256:web"))
+	j.Meta = map[string]string{MetaGitCommit: "abc123", MetaDeployer: "ci", "team": "shop", "tier": "frontend"}
+	j.TaskGroups[0].Meta = map[string]string{"team": "web", MetaImageDigest: "sha256:meta"}
+	j.TaskGroups[0].Tasks[0].Meta = map[string]string{MetaBuildURL: "https://ci.example.com/1"}
+
+	fake := &fakeNomad{streams: map[string][]streamItem{
+		"/default": {{Index: 11, Events: []event{jobEvent("JobRegistered", 10, j), marker(11)}}},
+	}}
+	_, stream := startSource(t, fake, Config{})
+
+	events := collect(t, stream)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %+v", events)
+	}
+
+	m := events[0].Metadata
+	if m.Image != "registry:5000/shop/web:1.2.0" || m.ImageDigest != "sha256:web" {
+		t.Fatalf("expected the image and the digest of the image reference, got %+v", m)
+	}
+	if m.GitCommit != "abc123" || m.Deployer != "ci" || m.BuildURL != "https://ci.example.com/1" {
+		t.Fatalf("expected the well-known meta of the job, group and task, got %+v", m)
+	}
+	if len(m.Labels) != 2 || m.Labels["team"] != "web" || m.Labels["tier"] != "frontend" {
+		t.Fatalf("expected the other meta as labels, the group overriding the job, got %v", m.Labels)
+	}
+}
+
+func TestDeploymentEvents(t *testing.T) {
+	v2 := testJob("default", "shop", 2, dockerTask("web", "shop/web:1.1.0"), task{Name: "logs", Driver: "exec"})
+	v3 := testJob("default", "shop", 3, dockerTask("web", "shop/web:1.2.0"), task{Name: "logs", Driver: "exec"})
+	modified := time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC)
+
+	shop := func(version uint64, status, description string, healthy, desired int32) deployment {
+		return deployment{
+			ID:                "d1",
+			Namespace:         "default",
+			JobID:             "shop",
+			JobVersion:        version,
+			Status:            status,
+			StatusDescription: description,
+			TaskGroups:        map[string]deploymentState{"app": {DesiredTotal: desired, PlacedAllocs: desired, HealthyAllocs: healthy}},
+			ModifyTime:        modified.UnixNano(),
+		}
+	}
+
+	type want struct {
+		version, status, description string
+		healthy, desired             int32
+	}
+	tests := []struct {
+		name       string
+		deployment deployment
+		want       []want
+	}{
+		{
+			name:       "running",
+			deployment: shop(3, "running", "Deployment is running", 1, 3),
+			want:       []want{{"1.2.0", datasource.RolloutRunning, "Deployment is running", 1, 3}},
+		},
+		{
+			name:       "successful",
+			deployment: shop(3, "successful", "Deployment completed successfully", 3, 3),
+			want:       []want{{"1.2.0", datasource.RolloutSuccessful, "Deployment completed successfully", 3, 3}},
+		},
+		{
+			// The version of a deployment is the one being rolled out, not the latest or the one running.
+			name:       "older version",
+			deployment: shop(2, "pending", "Deployment is pending", 0, 2),
+			want:       []want{{"1.1.0", datasource.RolloutPending, "Deployment is pending", 0, 2}},
+		},
+		{
+			name:       "paused",
+			deployment: shop(3, "paused", "Deployment is paused", 1, 3),
+			want:       []want{{"1.2.0", datasource.RolloutPaused, "Deployment is paused", 1, 3}},
+		},
+		{
+			name:       "failed",
+			deployment: shop(3, "failed", "Failed due to unhealthy allocations", 1, 3),
+			want:       []want{{"1.2.0", datasource.RolloutFailed, "Failed due to unhealthy allocations", 1, 3}},
+		},
+		{
+			name:       "rolled back",
+			deployment: shop(3, "failed", "Failed due to progress deadline - rolling back to job version 2", 0, 3),
+			want:       []want{{"1.2.0", datasource.RolloutFailed, "Failed due to progress deadline - rolling back to job version 2", 0, 3}},
+		},
+		{
+			name:       "cancelled",
+			deployment: shop(3, "cancelled", "Cancelled due to newer version of job", 2, 3),
+			want:       []want{{"1.2.0", datasource.RolloutCancelled, "Cancelled due to newer version of job", 2, 3}},
+		},
+		{
+			name:       "unknown status",
+			deployment: shop(3, "exploded", "", 0, 3),
+		},
+		{
+			name: "other task group",
+			deployment: func() deployment {
+				d := shop(3, "running", "", 0, 1)
+				d.TaskGroups = map[string]deploymentState{"cache": {DesiredTotal: 1}}
+				return d
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeNomad{streams: map[string][]streamItem{
+				"/default": {
+					{Index: 10, Events: []event{jobEvent("JobRegistered", 8, v2), jobEvent("JobRegistered", 10, v3)}},
+					{Index: 12, Events: []event{deploymentEvent(11, tt.deployment), marker(12)}},
+				},
+			}}
+			_, stream := startSource(t, fake, Config{})
+
+			events := slices.DeleteFunc(collect(t, stream), func(e datasource.Event) bool { return e.Rollout == nil })
+			if len(events) != len(tt.want) {
+				t.Fatalf("expected %d rollout events, got %+v", len(tt.want), events)
+			}
+			for i, w := range tt.want {
+				e := events[i]
+				got := want{e.Version, e.Rollout.Status, e.Rollout.Description, e.Rollout.Healthy, e.Rollout.Desired}
+				if got != w || e.DeploymentName != "shop.app.web" {
+					t.Fatalf("event %d: expected the rollout %+v of shop.app.web, got %s %+v", i, w, e.DeploymentName, got)
+				}
+				if e.Id != "d1" || e.Sequence != 11 || !e.DeployedAt.Equal(modified) {
+					t.Fatalf("event %d: expected the key, index and modify time of the deployment, got %+v", i, e)
+				}
+			}
+			if lookups := fake.requested(); len(lookups) != 0 {
+				t.Fatalf("expected the registered versions to be cached, got the lookups %q", lookups)
+			}
+		})
+	}
+}
+
+func TestDeploymentJobVersions(t *testing.T) {
+	v1 := testJob("default", "shop", 1, dockerTask("web", "shop/web:1.0.0"))
+	v2 := testJob("default", "shop", 2, dockerTask("web", "shop/web:1.1.0"))
+	deployed := func(index, version uint64) event {
+		return deploymentEvent(index, deployment{
+			ID:         fmt.Sprintf("d%d", version),
+			Namespace:  "default",
+			JobID:      "shop",
+			JobVersion: version,
+			Status:     "running",
+			TaskGroups: map[string]deploymentState{"app": {DesiredTotal: 1}},
+		})
+	}
+
+	fake := &fakeNomad{
+		streams: map[string][]streamItem{
+			"/default": {{Index: 14, Events: []event{
+				// The versions are not cached yet and are looked up once.
+				deployed(10, 1),
+				deployed(11, 2),
+				// Nomad doesn't know the version.
+				deployed(12, 5),
+				deployed(13, 2),
+				marker(14),
+			}}},
+		},
+		versions: map[string][]job{"shop": {v2, v1}},
+	}
+	_, stream := startSource(t, fake, Config{})
+
+	var versions []string
+	for _, e := range collect(t, stream) {
+		versions = append(versions, e.Version)
+	}
+	if want := []string{"1.0.0", "1.1.0", "1.1.0"}; !slices.Equal(versions, want) {
+		t.Fatalf("expected the versions %q, got %q", want, versions)
+	}
+
+	// The unknown version is looked up again, in case it was registered since.
+	if want := []string{"/v1/job/shop/versions", "/v1/job/shop/versions"}; !slices.Equal(fake.requested(), want) {
+		t.Fatalf("expected the lookups %q, got %q", want, fake.requested())
+	}
+}
+
+func TestCacheJob(t *testing.T) {
+	s := &Source{jobs: map[jobKey][]job{}}
+	eu := target{region: "eu"}
+
+	for version := range uint64(jobVersionsCached + 2) {
+		s.cacheJob(eu, testJob("default", "shop", version))
+	}
+	// A version registered again replaces the cached one.
+	again := testJob("default", "shop", 5)
+	again.Stop = true
+	s.cacheJob(eu, again)
+	s.cacheJob(target{region: "us"}, testJob("default", "shop", 1))
+
+	cached := s.jobs[jobKey{"eu", "default", "shop"}]
+	var versions []uint64
+	for _, j := range cached {
+		versions = append(versions, j.Version)
+	}
+	if want := []uint64{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}; !slices.Equal(versions, want) {
+		t.Fatalf("expected the latest versions first %v, got %v", want, versions)
+	}
+	if !cached[6].Stop {
+		t.Fatalf("expected the version registered again to replace the cached one, got %+v", cached[6])
+	}
+	if us := s.jobs[jobKey{"us", "default", "shop"}]; len(us) != 1 {
+		t.Fatalf("expected the versions to be cached per region, got %+v", us)
+	}
+}
+
+func TestAllocationEvents(t *testing.T) {
+	shop := testJob("default", "shop", 3, dockerTask("web", "shop/web:1.2.0"), task{Name: "logs", Driver: "exec"})
+	modified := time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC)
+
+	alloc := func(index uint64, id, status, deploymentID string) event {
+		return allocationEvent(index, allocation{
+			ID:           id,
+			Namespace:    "default",
+			JobID:        "shop",
+			TaskGroup:    "app",
+			ClientStatus: status,
+			DeploymentID: deploymentID,
+			ModifyTime:   modified.Add(time.Duration(index) * time.Second).UnixNano(),
+		})
+	}
+
+	fake := &fakeNomad{
+		streams: map[string][]streamItem{
+			"/default": {{Index: 30, Events: []event{
+				alloc(10, "a1", "pending", ""),
+				alloc(11, "a1", "running", ""),
+				// The status didn't change.
+				alloc(12, "a1", "running", ""),
+				// The rollout of allocations of a deployment is reported by the deployment.
+				alloc(13, "a2", "pending", "d1"),
+				alloc(14, "a2", "running", "d1"),
+				alloc(15, "a2", "failed", "d1"),
+				alloc(16, "a1", "failed", ""),
+				// a1 is terminal and forgotten, like allocations that terminated before the stream started.
+				alloc(17, "a1", "complete", ""),
+				alloc(18, "a3", "lost", ""),
+				alloc(19, "a4", "pending", ""),
+				alloc(20, "a4", "lost", ""),
+				alloc(21, "a5", "running", ""),
+				alloc(22, "a5", "complete", ""),
+				marker(30),
+			}}},
+		},
+		allocations: map[string]job{"a1": shop, "a4": shop, "a5": shop},
+	}
+	source, stream := startSource(t, fake, Config{})
+
+	type want struct {
+		id, status, description string
+		sequence                int64
+	}
+	wants := []want{
+		{"a1", datasource.RolloutSuccessful, "", 11},
+		{"a1", datasource.RolloutFailed, "allocation a1 is failed", 16},
+		{"a4", datasource.RolloutFailed, "allocation a4 is lost", 20},
+		{"a5", datasource.RolloutSuccessful, "", 21},
+	}
+
+	events := collect(t, stream)
+	if len(events) != len(wants) {
+		t.Fatalf("expected %d events, got %+v", len(wants), events)
+	}
+	for i, w := range wants {
+		e := events[i]
+		if e.Rollout == nil {
+			t.Fatalf("event %d: expected a rollout, got %+v", i, e)
+		}
+		got := want{e.Id, e.Rollout.Status, e.Rollout.Description, e.Sequence}
+		if got != w || e.DeploymentName != "shop.app.web" || e.Version != "1.2.0" {
+			t.Fatalf("event %d: expected %+v of shop.app.web 1.2.0, got %s %s %+v", i, w, e.DeploymentName, e.Version, got)
+		}
+		if at := modified.Add(time.Duration(w.sequence) * time.Second); !e.DeployedAt.Equal(at) {
+			t.Fatalf("event %d: expected the modify time %s, got %s", i, at, e.DeployedAt)
+		}
+	}
+
+	// The jobs of the allocations are cached for the deployments.
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	if versions := source.jobs[jobKey{"", "default", "shop"}]; len(versions) != 1 || versions[0].Version != 3 {
+		t.Fatalf("expected the job of the allocations to be cached, got %+v", versions)
+	}
+}
@@ -0,0 +1,176 @@
+package plugin
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	pluginpb "overseer/api-go/plugin/v1"
+	"overseer/datasource"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Hub is the PublishService that the sources which connect to Overseer publish their events to.
+type Hub struct {
+	pluginpb.UnimplementedPublishServiceServer
+
+	mu      sync.Mutex
+	sources map[string]*Source
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		sources: map[string]*Source{},
+	}
+}
+
+func (h *Hub) add(s *Source) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.sources[s.name]; ok {
+		return fmt.Errorf("a plugin named %s is already accepted", s.name)
+	}
+	h.sources[s.name] = s
+	return nil
+}
+
+// Publish receives the events of a source until it disconnects or the events of the source stop being streamed.
+func (h *Hub) Publish(stream pluginpb.PublishService_PublishServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	hello := req.GetHello()
+	if hello == nil {
+		return status.Error(codes.InvalidArgument, "the first message must be a hello")
+	}
+
+	h.mu.Lock()
+	s, ok := h.sources[hello.Source]
+	h.mu.Unlock()
+
+	if !ok {
+		return status.Errorf(codes.NotFound, "no plugin named %s is accepted", hello.Source)
+	}
+	if s.token != "" && subtle.ConstantTimeCompare([]byte(hello.Token), []byte(s.token)) != 1 {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	streamCtx, err := s.connect()
+	if err != nil {
+		return err
+	}
+	defer s.disconnect()
+
+	s.logger.Info("plugin connected")
+	defer s.logger.Info("plugin disconnected")
+
+	// Acks are sent while the events are received, but not concurrently with each other.
+	var sendMu sync.Mutex
+
+	err = stream.Send(&pluginpb.PublishResponse{
+		Message: &pluginpb.PublishResponse_Subscribe{Subscribe: &pluginpb.Subscribe{Cursor: s.cursors.resume()}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// The events are received on their own so that the source is disconnected
+	// as soon as its events stop being streamed, like when it is restarted.
+	received := make(chan *pluginpb.Event)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+
+			select {
+			case received <- req.GetEvent():
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		var e *pluginpb.Event
+		select {
+		case e = <-received:
+		case err := <-recvErr:
+			return err
+		case <-streamCtx.Done():
+			return status.Error(codes.Unavailable, "the source was stopped")
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+
+		if e == nil || !s.cursors.receive(e.Cursor) {
+			continue
+		}
+
+		cursor := e.Cursor
+		event := toEvent(e, func() {
+			s.cursors.ack(cursor)
+
+			sendMu.Lock()
+			defer sendMu.Unlock()
+
+			// The event is sent again after a reconnect if the ack is lost, which is harmless.
+			_ = stream.Send(&pluginpb.PublishResponse{
+				Message: &pluginpb.PublishResponse_Ack{Ack: &pluginpb.Ack{Cursor: cursor}},
+			})
+		})
+
+		if err := s.publish(stream.Context(), event); err != nil {
+			return err
+		}
+	}
+}
+
+// connect marks the source as connected, returning the context of the stream of its events.
+func (s *Source) connect() (context.Context, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stream == nil {
+		return nil, status.Errorf(codes.Unavailable, "the plugin %s is not running", s.name)
+	}
+	if s.connected {
+		return nil, status.Errorf(codes.AlreadyExists, "the plugin %s is already connected", s.name)
+	}
+
+	s.connected = true
+	return s.streamCtx, nil
+}
+
+func (s *Source) disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connected = false
+}
+
+// publish passes on an event of the connected source.
+func (s *Source) publish(ctx context.Context, event datasource.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stream == nil {
+		return status.Error(codes.Unavailable, "the source was stopped")
+	}
+
+	select {
+	case s.stream <- event:
+		return nil
+	case <-s.streamCtx.Done():
+		return status.Error(codes.Unavailable, "the source was stopped")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,168 @@
+// Package plugin runs the sources of deployment events that speak the plugin protocol of proto/plugin/v1,
+// so they can be written in any language and run outside of Overseer, like in a sidecar.
+//
+// Overseer either connects to the SourceService of a source, see Config.Address,
+// or the source connects to the PublishService of Overseer, see Config.Accept and Hub.
+package plugin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	pluginpb "overseer/api-go/plugin/v1"
+	"overseer/datasource"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// reconnectDelay is how long to wait before connecting to a source again.
+const reconnectDelay = 5 * time.Second
+
+// Config configures a source speaking the plugin protocol, either Address or Accept must be set.
+type Config struct {
+	// Address is the address of the SourceService of the source that Overseer connects to, like localhost:7070.
+	Address string
+	// TLS connects to the source with TLS, verifying its certificate with the system pool or CACert.
+	TLS bool
+	// CACert is a PEM file with the certificates of the CAs the certificate of the source is verified with.
+	CACert string
+
+	// Accept waits for the source to connect to the PublishService of Overseer instead,
+	// with the name of the source in Overseer.
+	Accept bool
+	// Token must be sent by the source when it connects, if it is set. TokenFile takes precedence.
+	Token string
+	// TokenFile is a file holding the token.
+	TokenFile string
+}
+
+func (c Config) validate() error {
+	if (c.Address == "") == !c.Accept {
+		return errors.New("either the address of the source or accepting its connections must be configured")
+	}
+	if c.Accept && (c.TLS || c.CACert != "") {
+		return errors.New("TLS is configured on the gRPC server for the sources that connect to Overseer")
+	}
+	if !c.Accept && (c.Token != "" || c.TokenFile != "") {
+		return errors.New("a token is only used by the sources that connect to Overseer")
+	}
+	return nil
+}
+
+// token returns the token the source must send when it connects.
+func (c Config) token() (string, error) {
+	if c.TokenFile == "" {
+		return c.Token, nil
+	}
+
+	b, err := os.ReadFile(c.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("reading the token: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// transportCredentials returns the TLS credentials of the connection to the source, nil without TLS.
+func (c Config) transportCredentials() (credentials.TransportCredentials, error) {
+	if !c.TLS && c.CACert == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if c.CACert != "" {
+		pem, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("reading the CA certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// cursors tracks the cursors of the events of a source across its connections.
+type cursors struct {
+	mu sync.Mutex
+	// received is the cursor of the last event passed on, the next connection resumes after it.
+	received int64
+	// acked is the cursor of the last handled event.
+	acked int64
+}
+
+// restart resumes after the last handled event, as the events that were passed on
+// but not handled are lost when the events are streamed again.
+func (c *cursors) restart() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.received = c.acked
+}
+
+func (c *cursors) resume() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.received
+}
+
+// receive reports whether the event with the cursor is new, and records it as received if it is.
+func (c *cursors) receive(cursor int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cursor <= c.received {
+		return false
+	}
+	c.received = cursor
+	return true
+}
+
+func (c *cursors) ack(cursor int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.acked = max(c.acked, cursor)
+}
+
+// toEvent converts an event of the protocol, calling ack once it is handled.
+func toEvent(e *pluginpb.Event, ack func()) datasource.Event {
+	event := datasource.Event{
+		Id:             e.Id,
+		DeploymentName: e.DeploymentName,
+		Component:      e.Component,
+		Version:        e.Version,
+		Sequence:       e.Sequence,
+		Undeployed:     e.Undeployed,
+		Metadata: datasource.Metadata{
+			Image:       e.Metadata.GetImage(),
+			ImageDigest: e.Metadata.GetImageDigest(),
+			GitCommit:   e.Metadata.GetGitCommit(),
+			BuildURL:    e.Metadata.GetBuildUrl(),
+			Deployer:    e.Metadata.GetDeployer(),
+			Labels:      e.Metadata.GetLabels(),
+		},
+		Ack: ack,
+	}
+
+	if e.DeployedAt != nil {
+		event.DeployedAt = e.DeployedAt.AsTime()
+	}
+
+	if e.Rollout != nil {
+		event.Rollout = &datasource.Rollout{
+			Status:      e.Rollout.Status,
+			Description: e.Rollout.Description,
+			Healthy:     e.Rollout.Healthy,
+			Desired:     e.Rollout.Desired,
+		}
+	}
+
+	return event
+}
@@ -0,0 +1,462 @@
+package plugin
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	pluginpb "overseer/api-go/plugin/v1"
+	"overseer/datasource"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// serve serves the services registered by register on an in-memory listener and returns a connection to them.
+func serve(t *testing.T, register func(*grpc.Server)) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// streamEvents streams the events of the source until the test ends or the returned function is called.
+func streamEvents(t *testing.T, s *Source) (<-chan datasource.Event, func()) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := s.StreamEvents(ctx)
+	if err != nil {
+		cancel()
+		t.Fatal(err)
+	}
+
+	stop := func() {
+		cancel()
+		for range stream {
+		}
+	}
+	t.Cleanup(stop)
+	return stream, stop
+}
+
+// receive returns the ids of the next n events of the stream, acking the events with the ids in ack.
+func receive(t *testing.T, stream <-chan datasource.Event, n int, ack ...string) []string {
+	t.Helper()
+
+	var ids []string
+	for len(ids) < n {
+		select {
+		case e, ok := <-stream:
+			if !ok {
+				t.Fatalf("the stream ended after the events %q", ids)
+			}
+			ids = append(ids, e.Id)
+			if slices.Contains(ack, e.Id) {
+				e.Ack()
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %d events, got %q", n, ids)
+		}
+	}
+	return ids
+}
+
+// expectNoEvent fails if the stream passes on an event.
+func expectNoEvent(t *testing.T, stream <-chan datasource.Event) {
+	t.Helper()
+
+	select {
+	case e := <-stream:
+		t.Fatalf("expected no more events, got %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// events returns the events of the protocol with the cursors, with the ids e<cursor>.
+func events(cursors ...int64) []*pluginpb.Event {
+	var list []*pluginpb.Event
+	for _, c := range cursors {
+		list = append(list, &pluginpb.Event{Cursor: c, Id: "e" + string(rune('0'+c)), DeploymentName: "api", Version: "1.0.0"})
+	}
+	return list
+}
+
+// sourceService is a SourceService whose streams are driven by the test.
+type sourceService struct {
+	pluginpb.UnimplementedSourceServiceServer
+	streams chan *serviceStream
+}
+
+// serviceStream is a stream of the events of the source, it ends when end is closed.
+type serviceStream struct {
+	subscribe int64
+	events    chan *pluginpb.Event
+	acks      chan int64
+	end       chan struct{}
+}
+
+func (s *sourceService) StreamEvents(stream pluginpb.SourceService_StreamEventsServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	ss := &serviceStream{
+		subscribe: req.GetSubscribe().GetCursor(),
+		events:    make(chan *pluginpb.Event),
+		acks:      make(chan int64, 100),
+		end:       make(chan struct{}),
+	}
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			ss.acks <- req.GetAck().GetCursor()
+		}
+	}()
+	s.streams <- ss
+
+	for {
+		select {
+		case e := <-ss.events:
+			if err := stream.Send(&pluginpb.StreamEventsResponse{Event: e}); err != nil {
+				return err
+			}
+		case <-ss.end:
+			return status.Error(codes.Unavailable, "the source restarts")
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// next waits for the source to open a stream.
+func (s *sourceService) next(t *testing.T) *serviceStream {
+	t.Helper()
+
+	select {
+	case ss := <-s.streams:
+		return ss
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the source to subscribe")
+		return nil
+	}
+}
+
+func (ss *serviceStream) send(t *testing.T, events ...*pluginpb.Event) {
+	t.Helper()
+
+	for _, e := range events {
+		select {
+		case ss.events <- e:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out sending the event %d", e.Cursor)
+		}
+	}
+}
+
+// expectAcks waits for the acks of the cursors, in order.
+func expectAcks(t *testing.T, acks <-chan int64, want ...int64) {
+	t.Helper()
+
+	var got []int64
+	for len(got) < len(want) {
+		select {
+		case c := <-acks:
+			got = append(got, c)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for the acks %v, got %v", want, got)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected the acks %v, got %v", want, got)
+	}
+}
+
+func TestSourceService(t *testing.T) {
+	service := &sourceService{streams: make(chan *serviceStream)}
+	conn := serve(t, func(s *grpc.Server) { pluginpb.RegisterSourceServiceServer(s, service) })
+
+	source, err := NewSource("sidecar", Config{Address: "localhost:7070"}, nil, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source.conn.Close()
+	source.conn = conn
+	source.reconnectDelay = 10 * time.Millisecond
+
+	stream, stop := streamEvents(t, source)
+
+	first := service.next(t)
+	if first.subscribe != 0 {
+		t.Fatalf("expected the first stream to start from the beginning, got the cursor %d", first.subscribe)
+	}
+
+	// The duplicates and the events older than the last one are dropped.
+	first.send(t, events(1, 2, 2, 1, 3)...)
+	if ids := receive(t, stream, 3, "e1", "e2"); !slices.Equal(ids, []string{"e1", "e2", "e3"}) {
+		t.Fatalf("expected the events e1, e2 and e3, got %q", ids)
+	}
+	expectAcks(t, first.acks, 1, 2)
+
+	// After a reconnect, the stream resumes after the last event passed on.
+	close(first.end)
+	second := service.next(t)
+	if second.subscribe != 3 {
+		t.Fatalf("expected the reconnect to resume after the cursor 3, got %d", second.subscribe)
+	}
+	second.send(t, events(3, 4)...)
+	if ids := receive(t, stream, 1); !slices.Equal(ids, []string{"e4"}) {
+		t.Fatalf("expected the event e4, got %q", ids)
+	}
+	expectNoEvent(t, stream)
+
+	// When the events are streamed again, the ones that were passed on but not handled are streamed again.
+	stop()
+	stream, _ = streamEvents(t, source)
+	third := service.next(t)
+	if third.subscribe != 2 {
+		t.Fatalf("expected the restart to resume after the acked cursor 2, got %d", third.subscribe)
+	}
+	third.send(t, events(3, 4)...)
+	if ids := receive(t, stream, 2, "e3", "e4"); !slices.Equal(ids, []string{"e3", "e4"}) {
+		t.Fatalf("expected the events e3 and e4 again, got %q", ids)
+	}
+	expectAcks(t, third.acks, 3, 4)
+}
+
+// publisher is a source connected to the PublishService of the hub.
+type publisher struct {
+	stream pluginpb.PublishService_PublishClient
+	cancel context.CancelFunc
+	// subscribe is the cursor the source is asked to resume after.
+	subscribe int64
+}
+
+// publish connects to the hub with the hello and returns the error of the hub if it refuses the connection.
+func publish(t *testing.T, conn *grpc.ClientConn, hello *pluginpb.Hello) (*publisher, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	stream, err := pluginpb.NewPublishServiceClient(conn).Publish(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&pluginpb.PublishRequest{Message: &pluginpb.PublishRequest_Hello{Hello: hello}}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &publisher{stream: stream, cancel: cancel, subscribe: resp.GetSubscribe().GetCursor()}, nil
+}
+
+func (p *publisher) send(t *testing.T, events ...*pluginpb.Event) {
+	t.Helper()
+
+	for _, e := range events {
+		if err := p.stream.Send(&pluginpb.PublishRequest{Message: &pluginpb.PublishRequest_Event{Event: e}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// acks returns the cursors the hub acks until the stream ends.
+func (p *publisher) acks() <-chan int64 {
+	acks := make(chan int64, 100)
+	go func() {
+		for {
+			resp, err := p.stream.Recv()
+			if err != nil {
+				return
+			}
+			acks <- resp.GetAck().GetCursor()
+		}
+	}()
+	return acks
+}
+
+// disconnect disconnects the publisher and waits until the hub notices.
+func (p *publisher) disconnect(t *testing.T, s *Source) {
+	t.Helper()
+
+	p.cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.mu.Lock()
+		connected := s.connected
+		s.mu.Unlock()
+		if !connected {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the publisher to disconnect")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestHubTokens(t *testing.T) {
+	hub := NewHub()
+	conn := serve(t, func(s *grpc.Server) { pluginpb.RegisterPublishServiceServer(s, hub) })
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(slog.DiscardHandler)
+	for name, config := range map[string]Config{
+		"sidecar":   {Accept: true, TokenFile: tokenFile},
+		"anonymous": {Accept: true},
+		"stopped":   {Accept: true},
+	} {
+		source, err := NewSource(name, config, hub, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != "stopped" {
+			streamEvents(t, source)
+		}
+	}
+
+	if _, err := NewSource("sidecar", Config{Accept: true}, hub, logger); err == nil {
+		t.Fatal("expected a second source with the same name to be refused")
+	}
+
+	tests := []struct {
+		name  string
+		hello *pluginpb.Hello
+		want  codes.Code
+	}{
+		{"token", &pluginpb.Hello{Source: "sidecar", Token: "s3cret"}, codes.OK},
+		{"wrong token", &pluginpb.Hello{Source: "sidecar", Token: "guess"}, codes.Unauthenticated},
+		{"no token", &pluginpb.Hello{Source: "sidecar"}, codes.Unauthenticated},
+		{"source without a token", &pluginpb.Hello{Source: "anonymous", Token: "anything"}, codes.OK},
+		{"unknown source", &pluginpb.Hello{Source: "unknown", Token: "s3cret"}, codes.NotFound},
+		{"source not running", &pluginpb.Hello{Source: "stopped"}, codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := publish(t, conn, tt.hello)
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("expected the code %s, got %s (%v)", tt.want, got, err)
+			}
+			if p != nil {
+				p.cancel()
+			}
+		})
+	}
+
+	t.Run("no hello", func(t *testing.T) {
+		stream, err := pluginpb.NewPublishServiceClient(conn).Publish(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := stream.Send(&pluginpb.PublishRequest{Message: &pluginpb.PublishRequest_Event{Event: events(1)[0]}}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument, got %v", err)
+		}
+	})
+}
+
+func TestHubPublish(t *testing.T) {
+	hub := NewHub()
+	conn := serve(t, func(s *grpc.Server) { pluginpb.RegisterPublishServiceServer(s, hub) })
+
+	source, err := NewSource("sidecar", Config{Accept: true}, hub, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream, stop := streamEvents(t, source)
+
+	first, err := publish(t, conn, &pluginpb.Hello{Source: "sidecar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.subscribe != 0 {
+		t.Fatalf("expected the first connection to start from the beginning, got the cursor %d", first.subscribe)
+	}
+
+	// Only one connection of the source is accepted at a time.
+	if _, err := publish(t, conn, &pluginpb.Hello{Source: "sidecar"}); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected a second connection to be refused with AlreadyExists, got %v", err)
+	}
+
+	// The duplicates and the events older than the last one are dropped.
+	acks := first.acks()
+	first.send(t, events(1, 2, 2, 1, 3)...)
+	if ids := receive(t, stream, 3, "e1", "e2"); !slices.Equal(ids, []string{"e1", "e2", "e3"}) {
+		t.Fatalf("expected the events e1, e2 and e3, got %q", ids)
+	}
+	expectAcks(t, acks, 1, 2)
+
+	// After a reconnect, the source resumes after the last event passed on.
+	first.disconnect(t, source)
+	second, err := publish(t, conn, &pluginpb.Hello{Source: "sidecar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.subscribe != 3 {
+		t.Fatalf("expected the reconnect to resume after the cursor 3, got %d", second.subscribe)
+	}
+	second.send(t, events(3, 4)...)
+	if ids := receive(t, stream, 1); !slices.Equal(ids, []string{"e4"}) {
+		t.Fatalf("expected the event e4, got %q", ids)
+	}
+	expectNoEvent(t, stream)
+
+	// Stopping the events disconnects the source.
+	stop()
+	if _, err := second.stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected the source to be disconnected with Unavailable, got %v", err)
+	}
+
+	// When the events are streamed again, the ones that were passed on but not handled are published again.
+	stream, _ = streamEvents(t, source)
+	third, err := publish(t, conn, &pluginpb.Hello{Source: "sidecar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.subscribe != 2 {
+		t.Fatalf("expected the restart to resume after the acked cursor 2, got %d", third.subscribe)
+	}
+	acks = third.acks()
+	third.send(t, events(3, 4)...)
+	if ids := receive(t, stream, 2, "e3", "e4"); !slices.Equal(ids, []string{"e3", "e4"}) {
+		t.Fatalf("expected the events e3 and e4 again, got %q", ids)
+	}
+	expectAcks(t, acks, 3, 4)
+}
@@ -0,0 +1,196 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	pluginpb "overseer/api-go/plugin/v1"
+	"overseer/datasource"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Source streams the events of a source speaking the plugin protocol.
+type Source struct {
+	name   string
+	logger *slog.Logger
+
+	// conn is the connection to the SourceService of the source, nil if the source connects to Overseer.
+	conn *grpc.ClientConn
+	// token must be sent by the source when it connects to Overseer, if it is set.
+	token string
+	// reconnectDelay is reconnectDelay, except in tests.
+	reconnectDelay time.Duration
+
+	cursors cursors
+
+	// The fields below are used while the source connects to Overseer.
+	mu sync.Mutex
+	// stream receives the events while they are streamed, nil otherwise.
+	stream chan datasource.Event
+	// streamCtx is the context of the current stream.
+	streamCtx context.Context
+	// connected is set while the source is connected.
+	connected bool
+}
+
+// NewSource returns a source that connects to the SourceService at config.Address,
+// or that accepts the connections of the source named name through the hub if config.Accept is set.
+func NewSource(name string, config Config, hub *Hub, logger *slog.Logger) (*Source, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	s := &Source{
+		name:           name,
+		logger:         logger,
+		reconnectDelay: reconnectDelay,
+	}
+
+	if config.Accept {
+		token, err := config.token()
+		if err != nil {
+			return nil, err
+		}
+		s.token = token
+
+		if err := hub.add(s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	creds, err := config.transportCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+
+	// The connection is established when the events are streamed.
+	s.conn, err = grpc.NewClient(config.Address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("creating the client of %s: %w", config.Address, err)
+	}
+	return s, nil
+}
+
+func (s *Source) StreamEvents(ctx context.Context) (<-chan datasource.Event, error) {
+	s.cursors.restart()
+
+	if s.conn == nil {
+		return s.accept(ctx)
+	}
+
+	stream := make(chan datasource.Event, 10)
+
+	go func() {
+		defer close(stream)
+
+		for {
+			err := s.runStream(ctx, stream)
+			if ctx.Err() != nil {
+				return
+			}
+
+			s.logger.Error("stream error", "error", err)
+
+			// TODO: Sleep with backoff?
+			select {
+			case <-time.After(s.reconnectDelay):
+			case <-ctx.Done():
+				return
+			}
+
+			s.logger.Info("reconnecting to the plugin...")
+		}
+	}()
+
+	return stream, nil
+}
+
+// runStream subscribes to the events of the SourceService after the last received one,
+// and passes them on until the stream fails.
+func (s *Source) runStream(ctx context.Context, stream chan datasource.Event) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, err := pluginpb.NewSourceServiceClient(s.conn).StreamEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("connecting: %w", err)
+	}
+
+	// Acks are sent while the events are received, but not concurrently with each other.
+	var sendMu sync.Mutex
+
+	err = events.Send(&pluginpb.StreamEventsRequest{
+		Message: &pluginpb.StreamEventsRequest_Subscribe{Subscribe: &pluginpb.Subscribe{Cursor: s.cursors.resume()}},
+	})
+	if err != nil {
+		return fmt.Errorf("subscribing: %w", err)
+	}
+
+	for {
+		resp, err := events.Recv()
+		if err != nil {
+			return err
+		}
+
+		e := resp.GetEvent()
+		if e == nil || !s.cursors.receive(e.Cursor) {
+			continue
+		}
+
+		cursor := e.Cursor
+		event := toEvent(e, func() {
+			s.cursors.ack(cursor)
+
+			sendMu.Lock()
+			defer sendMu.Unlock()
+
+			// The event is sent again after a reconnect if the ack is lost, which is harmless.
+			_ = events.Send(&pluginpb.StreamEventsRequest{
+				Message: &pluginpb.StreamEventsRequest_Ack{Ack: &pluginpb.Ack{Cursor: cursor}},
+			})
+		})
+
+		select {
+		case stream <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// accept streams the events of the source while it is connected to the hub, until the context is canceled.
+func (s *Source) accept(ctx context.Context) (<-chan datasource.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stream != nil {
+		return nil, errors.New("the events of the source are already streamed")
+	}
+
+	stream := make(chan datasource.Event, 10)
+	s.stream = stream
+	s.streamCtx = ctx
+
+	go func() {
+		<-ctx.Done()
+
+		// The events are passed on with the lock held, so the stream isn't closed while they are.
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		close(stream)
+		s.stream = nil
+		s.streamCtx = nil
+	}()
+
+	return stream, nil
+}
@@ -0,0 +1,418 @@
+// Package prometheus reads the deployed versions of services from their build info metrics,
+// like go_build_info or app_build_info{version="1.2.3"}, scraped from the services themselves
+// or queried from a Prometheus server.
+//
+// Every series of a deployment is counted as a replica, so a deployment whose series report several versions
+// is reported as rolling out the new version until all of them report it.
+package prometheus
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"overseer/datasource"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults of the Config.
+const (
+	DefaultInterval      = time.Minute
+	DefaultTimeout       = 10 * time.Second
+	DefaultMetric        = "_build_info$"
+	DefaultQuery         = `{__name__=~".+_build_info"}`
+	DefaultVersionLabel  = "version"
+	DefaultRevisionLabel = "revision"
+)
+
+// DefaultRule names the deployments after the job label when no rules are configured.
+var DefaultRule = Rule{DeploymentName: "${job}"}
+
+// maxBodySize limits the size of the scraped responses.
+const maxBodySize = 10 << 20
+
+// ScrapeTarget is a metrics endpoint in the Prometheus text format.
+type ScrapeTarget struct {
+	URL string
+	// Labels are added to every series of the target, like job=api and env=prod, for the rules to use.
+	Labels map[string]string
+}
+
+// Rule maps the label sets of series to deployments.
+type Rule struct {
+	// Match holds regexps the labels must match entirely, a missing label matches like an empty one.
+	Match map[string]string
+	// DeploymentName and Component are expanded with the labels of the series, like ${job}.${namespace}.
+	// Series missing a label used by them are skipped. An empty Component is the primary component.
+	DeploymentName string
+	Component      string
+}
+
+type Config struct {
+	// Targets are scraped directly.
+	Targets []ScrapeTarget
+	// PrometheusURL is the address of a Prometheus server whose HTTP API is queried with Query.
+	PrometheusURL string
+	// Query is the instant query returning the build info series, defaults to DefaultQuery.
+	Query string
+	// Metric is a regexp of the names of the scraped metrics holding the versions, defaults to DefaultMetric.
+	Metric string
+	// VersionLabel and RevisionLabel are the labels holding the version and the git commit,
+	// default to DefaultVersionLabel and DefaultRevisionLabel.
+	VersionLabel  string
+	RevisionLabel string
+	// Rules map the series to deployments, the first matching rule is used.
+	// Series matching no rule are ignored, DefaultRule is used if there are none.
+	Rules []Rule
+	// Interval is how often the versions are read, defaults to DefaultInterval.
+	Interval time.Duration
+	// Timeout limits each request, defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+type rule struct {
+	Rule
+	match map[string]*regexp.Regexp
+}
+
+type Source struct {
+	config Config
+	rules  []rule
+	metric *regexp.Regexp
+	client *http.Client
+	logger *slog.Logger
+
+	// last holds the series of the last successful read of every target, keyed by URL,
+	// which are used in place of those of a failed read.
+	last map[string][]series
+	// deployments holds what was reported of each deployment.
+	deployments map[deploymentKey]*deploymentState
+}
+
+type deploymentKey struct {
+	name      string
+	component string
+}
+
+type deploymentState struct {
+	// deployed is the version last reported as deployed.
+	deployed string
+	// rolling is the version being rolled out while the series report several versions.
+	rolling string
+	// healthy and desired are the last reported progress of the rollout.
+	healthy int32
+	desired int32
+}
+
+func NewSource(config Config, logger *slog.Logger) (*Source, error) {
+	if len(config.Targets) == 0 && config.PrometheusURL == "" {
+		return nil, errors.New("no scrape targets or Prometheus server configured")
+	}
+
+	config.Query = cmp.Or(config.Query, DefaultQuery)
+	config.VersionLabel = cmp.Or(config.VersionLabel, DefaultVersionLabel)
+	config.RevisionLabel = cmp.Or(config.RevisionLabel, DefaultRevisionLabel)
+	config.Interval = cmp.Or(config.Interval, DefaultInterval)
+	config.Timeout = cmp.Or(config.Timeout, DefaultTimeout)
+	if len(config.Rules) == 0 {
+		config.Rules = []Rule{DefaultRule}
+	}
+
+	metric, err := regexp.Compile(cmp.Or(config.Metric, DefaultMetric))
+	if err != nil {
+		return nil, fmt.Errorf("invalid metric regexp: %w", err)
+	}
+
+	for _, t := range config.Targets {
+		if t.URL == "" {
+			return nil, errors.New("empty scrape target")
+		}
+	}
+
+	var rules []rule
+	for _, r := range config.Rules {
+		if r.DeploymentName == "" {
+			return nil, errors.New("the deployment name of a rule is required")
+		}
+
+		compiled := rule{Rule: r, match: map[string]*regexp.Regexp{}}
+		for label, expr := range r.Match {
+			re, err := regexp.Compile("^(?:" + expr + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid regexp of label %s: %w", label, err)
+			}
+			compiled.match[label] = re
+		}
+		rules = append(rules, compiled)
+	}
+
+	return &Source{
+		config:      config,
+		rules:       rules,
+		metric:      metric,
+		client:      &http.Client{},
+		logger:      logger,
+		last:        map[string][]series{},
+		deployments: map[deploymentKey]*deploymentState{},
+	}, nil
+}
+
+func (s *Source) StreamEvents(ctx context.Context) (<-chan datasource.Event, error) {
+	stream := make(chan datasource.Event, 10)
+
+	go func() {
+		defer close(stream)
+
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			for _, e := range s.poll(ctx) {
+				select {
+				case stream <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return stream, nil
+}
+
+// poll reads the series of every target and returns the events of the deployments whose versions changed.
+func (s *Source) poll(ctx context.Context) []datasource.Event {
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		all []series
+	)
+
+	read := func(key string, f func() ([]series, error)) {
+		result, err := f()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Warn("reading the build info", "target", key, "error", err)
+			}
+			result = s.last[key]
+		}
+		s.last[key] = result
+		all = append(all, result...)
+	}
+
+	for _, t := range s.config.Targets {
+		wg.Go(func() {
+			read(t.URL, func() ([]series, error) { return s.scrape(ctx, t) })
+		})
+	}
+	if s.config.PrometheusURL != "" {
+		wg.Go(func() {
+			read(s.config.PrometheusURL, func() ([]series, error) { return s.query(ctx) })
+		})
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	// The number of series reporting each version, and the revision of the version, of every deployment.
+	versions := map[deploymentKey]map[string]int32{}
+	revisions := map[deploymentKey]map[string]string{}
+	for _, labels := range all {
+		version := labels[s.config.VersionLabel]
+		if version == "" {
+			continue
+		}
+
+		key, ok := s.deploymentOf(labels)
+		if !ok {
+			continue
+		}
+
+		if versions[key] == nil {
+			versions[key] = map[string]int32{}
+			revisions[key] = map[string]string{}
+		}
+		versions[key][version]++
+		if revision := labels[s.config.RevisionLabel]; revision != "" {
+			revisions[key][version] = revision
+		}
+	}
+
+	now := time.Now()
+
+	var events []datasource.Event
+	for _, key := range slices.SortedFunc(maps.Keys(versions), func(a, b deploymentKey) int {
+		return cmp.Or(strings.Compare(a.name, b.name), strings.Compare(a.component, b.component))
+	}) {
+		state, ok := s.deployments[key]
+		if !ok {
+			state = &deploymentState{}
+			s.deployments[key] = state
+		}
+
+		for _, e := range state.update(versions[key]) {
+			// A poll can report a deployment and the progress of rollouts of the same deployment,
+			// the component, kind and version of the event tell them apart.
+			kind := "deployed"
+			if e.Rollout != nil {
+				kind = e.Rollout.Status
+			}
+			e.Id = key.name + "/" + key.component + "@" + now.Format(time.RFC3339Nano) + "/" + kind + "/" + e.Version
+			e.DeploymentName = key.name
+			e.Component = key.component
+			// The versions changed some time since the previous poll, this is when it was noticed.
+			e.DeployedAt = now
+			if e.Rollout == nil {
+				e.Metadata.GitCommit = revisions[key][e.Version]
+			}
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+// deploymentOf returns the deployment of the first rule matching the labels.
+func (s *Source) deploymentOf(labels series) (deploymentKey, bool) {
+	for _, r := range s.rules {
+		matches := true
+		for label, re := range r.match {
+			if !re.MatchString(labels[label]) {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+
+		name, ok := expand(r.DeploymentName, labels)
+		if !ok || name == "" {
+			return deploymentKey{}, false
+		}
+		component, ok := expand(r.Component, labels)
+		if !ok {
+			return deploymentKey{}, false
+		}
+		return deploymentKey{name: name, component: component}, true
+	}
+	return deploymentKey{}, false
+}
+
+// expand replaces the ${label} and $label references of the template, ok is false if a label is missing.
+func expand(template string, labels series) (string, bool) {
+	ok := true
+	expanded := os.Expand(template, func(label string) string {
+		v, found := labels[label]
+		if !found {
+			ok = false
+		}
+		return v
+	})
+	return expanded, ok
+}
+
+// update returns the events reporting the change from the previous versions of the deployment,
+// given the number of series reporting each version.
+//
+// A single version is the deployed version. With several versions, the version most series report
+// other than the deployed one is deployed and rolling out until it's the only one left,
+// or fails if it disappears.
+func (d *deploymentState) update(versions map[string]int32) []datasource.Event {
+	var total int32
+	for _, n := range versions {
+		total += n
+	}
+
+	var events []datasource.Event
+	failRollout := func(description string) {
+		events = append(events, datasource.Event{
+			Version: d.rolling,
+			Rollout: &datasource.Rollout{Status: datasource.RolloutFailed, Description: description, Desired: total},
+		})
+		d.rolling = ""
+	}
+
+	if len(versions) == 1 {
+		version := slices.Collect(maps.Keys(versions))[0]
+
+		if d.rolling != "" && d.rolling != version {
+			failRollout("rolled back to " + version)
+		}
+		if version != d.deployed {
+			events = append(events, datasource.Event{Version: version})
+			d.deployed = version
+		}
+		if d.rolling == version {
+			events = append(events, datasource.Event{
+				Version: version,
+				Rollout: &datasource.Rollout{Status: datasource.RolloutSuccessful, Healthy: total, Desired: total},
+			})
+			d.rolling = ""
+		}
+		return events
+	}
+
+	if versions[d.rolling] == 0 {
+		if d.rolling != "" {
+			failRollout("no longer running")
+		}
+
+		d.rolling = newestVersion(versions, d.deployed)
+		d.healthy, d.desired = 0, 0
+
+		if d.rolling != d.deployed {
+			events = append(events, datasource.Event{Version: d.rolling})
+			d.deployed = d.rolling
+		}
+	}
+
+	if healthy := versions[d.rolling]; healthy != d.healthy || total != d.desired {
+		d.healthy, d.desired = healthy, total
+		events = append(events, datasource.Event{
+			Version: d.rolling,
+			Rollout: &datasource.Rollout{
+				Status:      datasource.RolloutRunning,
+				Description: fmt.Sprintf("%d of %d replicas run %s", healthy, total, d.rolling),
+				Healthy:     healthy,
+				Desired:     total,
+			},
+		})
+	}
+
+	return events
+}
+
+// newestVersion guesses the version being rolled out as the one most series report other than the deployed one.
+func newestVersion(versions map[string]int32, deployed string) string {
+	var newest string
+	for v, n := range versions {
+		if v == deployed {
+			continue
+		}
+		if newest == "" || n > versions[newest] || (n == versions[newest] && v > newest) {
+			newest = v
+		}
+	}
+	return newest
+}
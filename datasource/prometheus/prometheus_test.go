@@ -0,0 +1,190 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"overseer/datasource"
+)
+
+// describe summarizes an event of deploymentState.update, like 1.1.0 or running 1.1.0 1/3.
+func describe(e datasource.Event) string {
+	if e.Rollout == nil {
+		return e.Version
+	}
+	s := fmt.Sprintf("%s %s %d/%d", e.Rollout.Status, e.Version, e.Rollout.Healthy, e.Rollout.Desired)
+	if e.Rollout.Status == datasource.RolloutFailed {
+		s += ": " + e.Rollout.Description
+	}
+	return s
+}
+
+func TestDeploymentStateUpdate(t *testing.T) {
+	type step struct {
+		versions map[string]int32
+		want     []string
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "rollout",
+			steps: []step{
+				{map[string]int32{"1.0.0": 3}, []string{"1.0.0"}},
+				{map[string]int32{"1.0.0": 3}, nil},
+				{map[string]int32{"1.0.0": 2, "1.1.0": 1}, []string{"1.1.0", "running 1.1.0 1/3"}},
+				{map[string]int32{"1.0.0": 2, "1.1.0": 1}, nil},
+				{map[string]int32{"1.0.0": 1, "1.1.0": 2}, []string{"running 1.1.0 2/3"}},
+				{map[string]int32{"1.1.0": 2}, []string{"successful 1.1.0 2/2"}},
+				{map[string]int32{"1.1.0": 3}, nil},
+			},
+		},
+		{
+			name: "scaling during a rollout",
+			steps: []step{
+				{map[string]int32{"1.0.0": 2}, []string{"1.0.0"}},
+				{map[string]int32{"1.0.0": 2, "1.1.0": 1}, []string{"1.1.0", "running 1.1.0 1/3"}},
+				{map[string]int32{"1.0.0": 3, "1.1.0": 1}, []string{"running 1.1.0 1/4"}},
+			},
+		},
+		{
+			name: "rollback",
+			steps: []step{
+				{map[string]int32{"1.0.0": 2}, []string{"1.0.0"}},
+				{map[string]int32{"1.0.0": 1, "1.1.0": 1}, []string{"1.1.0", "running 1.1.0 1/2"}},
+				{map[string]int32{"1.0.0": 2}, []string{"failed 1.1.0 0/2: rolled back to 1.0.0", "1.0.0"}},
+			},
+		},
+		{
+			name: "rolling version replaced",
+			steps: []step{
+				{map[string]int32{"1.0.0": 2}, []string{"1.0.0"}},
+				{map[string]int32{"1.0.0": 1, "1.1.0": 1}, []string{"1.1.0", "running 1.1.0 1/2"}},
+				{map[string]int32{"1.0.0": 1, "1.2.0": 2}, []string{"failed 1.1.0 0/3: no longer running", "1.2.0", "running 1.2.0 2/3"}},
+				{map[string]int32{"1.2.0": 3}, []string{"successful 1.2.0 3/3"}},
+			},
+		},
+		{
+			name: "deployment without a rollout",
+			steps: []step{
+				{map[string]int32{"1.0.0": 2}, []string{"1.0.0"}},
+				{map[string]int32{"1.1.0": 2}, []string{"1.1.0"}},
+			},
+		},
+		{
+			name: "first read during a rollout",
+			steps: []step{
+				{map[string]int32{"1.0.0": 1, "1.1.0": 2}, []string{"1.1.0", "running 1.1.0 2/3"}},
+				{map[string]int32{"1.1.0": 3}, []string{"successful 1.1.0 3/3"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d deploymentState
+			for i, s := range tt.steps {
+				var got []string
+				for _, e := range d.update(s.versions) {
+					got = append(got, describe(e))
+				}
+				if fmt.Sprint(got) != fmt.Sprint(s.want) {
+					t.Fatalf("step %d: expected %q, got %q", i, s.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNewestVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions map[string]int32
+		deployed string
+		want     string
+	}{
+		{"other than the deployed version", map[string]int32{"1.0.0": 2, "1.1.0": 1}, "1.0.0", "1.1.0"},
+		{"most series", map[string]int32{"1.0.0": 1, "1.1.0": 2, "1.2.0": 1}, "1.0.0", "1.1.0"},
+		{"ties broken by the greatest version", map[string]int32{"1.0.0": 1, "1.1.0": 1, "1.2.0": 1}, "1.0.0", "1.2.0"},
+		{"nothing deployed", map[string]int32{"1.0.0": 1, "1.1.0": 2}, "", "1.1.0"},
+		{"only the deployed version", map[string]int32{"1.0.0": 3}, "1.0.0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newestVersion(tt.versions, tt.deployed); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPoll(t *testing.T) {
+	// Every scrape serves the next body, the last one once they run out.
+	bodies := []string{
+		`# TYPE app_build_info gauge
+app_build_info{container="web",version="1.0.0",revision="aaa"} 1
+app_build_info{container="web",version="1.0.0",revision="aaa"} 1
+app_build_info{container="worker",version="2.0.0"} 1
+go_build_info{container="web",version="go1.25"} 1
+up 1`,
+		`app_build_info{container="web",version="1.0.0",revision="aaa"} 1
+app_build_info{container="web",version="1.1.0",revision="bbb"} 1
+app_build_info{container="worker",version="2.1.0"} 1`,
+	}
+	var (
+		mu      sync.Mutex
+		scrapes int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintln(w, bodies[min(scrapes, len(bodies)-1)])
+		scrapes++
+	}))
+	defer server.Close()
+
+	source, err := NewSource(Config{
+		Targets: []ScrapeTarget{{URL: server.URL, Labels: map[string]string{"job": "api"}}},
+		Metric:  "^app_build_info$",
+		Rules:   []Rule{{Match: map[string]string{"container": "web"}, DeploymentName: "${job}"}, {DeploymentName: "${job}", Component: "${container}"}},
+	}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		component, event, commit string
+	}
+	polls := [][]want{
+		{{"", "1.0.0", "aaa"}, {"worker", "2.0.0", ""}},
+		{{"", "1.1.0", "bbb"}, {"", "running 1.1.0 1/2", ""}, {"worker", "2.1.0", ""}},
+		nil,
+	}
+
+	ids := map[string]bool{}
+	for i, wants := range polls {
+		events := source.poll(context.Background())
+		if len(events) != len(wants) {
+			t.Fatalf("poll %d: expected %d events, got %+v", i, len(wants), events)
+		}
+
+		for j, w := range wants {
+			e := events[j]
+			if e.DeploymentName != "api" || e.Component != w.component || describe(e) != w.event || e.Metadata.GitCommit != w.commit {
+				t.Fatalf("poll %d: expected %+v of api, got %+v", i, w, e)
+			}
+			if ids[e.Id] {
+				t.Fatalf("poll %d: duplicate id %s", i, e.Id)
+			}
+			ids[e.Id] = true
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package prometheus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// series is the label set of a build info series, including the metric name as __name__.
+type series map[string]string
+
+// scrape reads the build info series exposed by a target in the Prometheus text format,
+// adding the labels of the target to every series.
+func (s *Source) scrape(ctx context.Context, t ScrapeTarget) ([]series, error) {
+	body, err := s.get(ctx, t.URL, "text/plain;version=0.0.4;q=1,application/openmetrics-text;q=0.5")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []series
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		labels, err := parseSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		if !s.metric.MatchString(labels["__name__"]) {
+			continue
+		}
+
+		maps.Copy(labels, t.Labels)
+		result = append(result, labels)
+	}
+
+	return result, scanner.Err()
+}
+
+// parseSample returns the labels of a sample like build_info{version="1.2.3"} 1, ignoring the value.
+func parseSample(line string) (series, error) {
+	end := strings.IndexAny(line, "{ \t")
+	if end <= 0 {
+		return nil, errors.New("missing metric name")
+	}
+
+	labels := series{"__name__": line[:end]}
+	rest := line[end:]
+	if rest[0] != '{' {
+		return labels, nil
+	}
+	rest = rest[1:]
+
+	for {
+		rest = strings.TrimLeft(rest, " \t,")
+		if rest == "" {
+			return nil, errors.New("unclosed label set")
+		}
+		if rest[0] == '}' {
+			return labels, nil
+		}
+
+		name, value, ok := strings.Cut(rest, "=")
+		if !ok || len(value) == 0 || value[0] != '"' {
+			return nil, errors.New("expected <label>=\"<value>\"")
+		}
+
+		var b strings.Builder
+		i := 1
+		for ; i < len(value) && value[i] != '"'; i++ {
+			if value[i] == '\\' && i+1 < len(value) {
+				i++
+				switch value[i] {
+				case 'n':
+					b.WriteByte('\n')
+				default:
+					b.WriteByte(value[i])
+				}
+				continue
+			}
+			b.WriteByte(value[i])
+		}
+		if i == len(value) {
+			return nil, errors.New("unterminated label value")
+		}
+
+		labels[strings.TrimSpace(name)] = b.String()
+		rest = value[i+1:]
+	}
+}
+
+// query reads the build info series from the HTTP API of a Prometheus server.
+func (s *Source) query(ctx context.Context) ([]series, error) {
+	query := url.Values{"query": {s.config.Query}}
+	body, err := s.get(ctx, strings.TrimSuffix(s.config.PrometheusURL, "/")+"/api/v1/query?"+query.Encode(), "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Metric series `json:"metric"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decoding the query response: %w", err)
+	}
+
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("query failed: %s", resp.Error)
+	}
+	if resp.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("the query returned a %s, expected an instant vector", resp.Data.ResultType)
+	}
+
+	var result []series
+	for _, r := range resp.Data.Result {
+		result = append(result, r.Metric)
+	}
+	return result, nil
+}
+
+// get returns the body of a successful GET request.
+func (s *Source) get(ctx context.Context, u, accept string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating the request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("reading the response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status: %s: %s", resp.Status, strings.TrimSpace(string(body[:min(len(body), 1024)])))
+	}
+
+	return body, nil
+}
@@ -0,0 +1,42 @@
+package prometheus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSample(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    series
+		wantErr bool
+	}{
+		{line: "up 1", want: series{"__name__": "up"}},
+		{line: "up\t1", want: series{"__name__": "up"}},
+		{line: `app_build_info{version="1.2.3"} 1`, want: series{"__name__": "app_build_info", "version": "1.2.3"}},
+		{line: `app_build_info{version="1.2.3",revision="abc"} 1`, want: series{"__name__": "app_build_info", "version": "1.2.3", "revision": "abc"}},
+		{line: `app_build_info{ version="1.2.3" , revision="abc", } 1 1748779200000`, want: series{"__name__": "app_build_info", "version": "1.2.3", "revision": "abc"}},
+		{line: `app_build_info{} 1`, want: series{"__name__": "app_build_info"}},
+		{line: `app_build_info{version=""} 1`, want: series{"__name__": "app_build_info", "version": ""}},
+		{line: `app_build_info{path="C:\\app",quote="say \"hi\"",lines="a\nb"} 1`, want: series{"__name__": "app_build_info", "path": `C:\app`, "quote": `say "hi"`, "lines": "a\nb"}},
+		{line: `app_build_info{tags="a,b}"} 1`, want: series{"__name__": "app_build_info", "tags": "a,b}"}},
+		{line: `{version="1.2.3"} 1`, wantErr: true},
+		{line: ` app_build_info 1`, wantErr: true},
+		{line: `app_build_info{version="1.2.3" 1`, wantErr: true},
+		{line: `app_build_info{version="1.2.3`, wantErr: true},
+		{line: `app_build_info{version=1.2.3} 1`, wantErr: true},
+		{line: `app_build_info{version} 1`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := parseSample(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected an error %t, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Package registry lists the tags of image repositories through the OCI distribution API,
+// which is served by Docker Hub, GHCR, Harbor, ECR, the distribution registry and most others.
+package registry
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const DefaultTimeout = 30 * time.Second
+
+// maxPages bounds the pages of tags listed from a repository, in case a registry keeps linking to the next page.
+const maxPages = 100
+
+// Docker Hub is the registry of the repositories without a registry host, like library/nginx.
+const (
+	dockerHubHost     = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+)
+
+type Config struct {
+	// PlainHTTP connects to the registries over HTTP instead of HTTPS, like for a local registry.
+	PlainHTTP bool
+	// Username and Password authenticate with the registries, or their token services, if they are set.
+	Username string
+	Password string
+	// Timeout of the requests, defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Client lists the tags of image repositories.
+type Client struct {
+	config Config
+	client *http.Client
+}
+
+func NewClient(config Config) *Client {
+	return &Client{
+		config: config,
+		client: &http.Client{Timeout: cmp.Or(config.Timeout, DefaultTimeout)},
+	}
+}
+
+// ParseRepository splits an image repository like registry.example.com:5000/team/api into the
+// host of its registry and its name. Repositories without a host are on Docker Hub, like nginx or team/api.
+func ParseRepository(repository string) (host, name string, err error) {
+	if repository == "" || strings.ContainsAny(repository, "@ ") {
+		return "", "", fmt.Errorf("invalid image repository %q", repository)
+	}
+
+	first, rest, hasHost := strings.Cut(repository, "/")
+	if hasHost && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		host, name = first, rest
+	} else {
+		host, name = dockerHubHost, repository
+	}
+
+	if host == dockerHubHost && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	return host, name, nil
+}
+
+// ListTags lists all tags of the repository, following the pages of the tag list.
+func (c *Client) ListTags(ctx context.Context, repository string) ([]string, error) {
+	host, name, err := ParseRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	if host == dockerHubHost {
+		host = dockerHubRegistry
+	}
+
+	scheme := "https"
+	if c.config.PlainHTTP {
+		scheme = "http"
+	}
+
+	next := &url.URL{Scheme: scheme, Host: host, Path: "/v2/" + name + "/tags/list"}
+	var (
+		tags          []string
+		authorization string
+	)
+
+	for range maxPages {
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+
+		resp, err := c.get(ctx, next.String(), authorization)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && authorization == "" {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+
+			authorization, err = c.authorize(ctx, challenge)
+			if err != nil {
+				return nil, fmt.Errorf("authenticating with %s: %w", host, err)
+			}
+			continue
+		}
+
+		link, err := decodeResponse(resp, &page)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, page.Tags...)
+
+		if link == "" {
+			return tags, nil
+		}
+		if next, err = next.Parse(link); err != nil {
+			return nil, fmt.Errorf("invalid link to the next page %q: %w", link, err)
+		}
+	}
+
+	return nil, fmt.Errorf("more than %d pages of tags", maxPages)
+}
+
+func (c *Client) get(ctx context.Context, url, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	return c.client.Do(req)
+}
+
+// decodeResponse decodes the JSON body of a successful response and returns the link to its next page, if any.
+func decodeResponse(resp *http.Response, v any) (string, error) {
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("decoding the response: %w", err)
+	}
+
+	return nextLink(resp.Header.Get("Link")), nil
+}
+
+// nextLink returns the target of the next link of a Link header like `</v2/api/tags/list?last=1.2&n=100>; rel="next"`.
+func nextLink(header string) string {
+	for link := range strings.SplitSeq(header, ",") {
+		target, params, _ := strings.Cut(link, ";")
+		target = strings.TrimSpace(target)
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		for param := range strings.SplitSeq(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if key == "rel" && strings.Trim(value, `"`) == "next" {
+				return target[1 : len(target)-1]
+			}
+		}
+	}
+	return ""
+}
+
+// authorize answers the challenge of a registry with the value of the Authorization header,
+// getting a token from the token service of the registry for a Bearer challenge.
+func (c *Client) authorize(ctx context.Context, challenge string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if c.config.Username == "" {
+			return "", errors.New("the registry requires credentials")
+		}
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+		return req.Header.Get("Authorization"), nil
+
+	case "bearer":
+		realm, err := url.Parse(params["realm"])
+		if err != nil || realm.Host == "" {
+			return "", fmt.Errorf("invalid token realm %q", params["realm"])
+		}
+
+		query := realm.Query()
+		for _, key := range []string{"service", "scope"} {
+			if params[key] != "" {
+				query.Set(key, params[key])
+			}
+		}
+		realm.RawQuery = query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if c.config.Username != "" {
+			req.SetBasicAuth(c.config.Username, c.config.Password)
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("requesting a token: %w", err)
+		}
+
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if _, err := decodeResponse(resp, &token); err != nil {
+			return "", fmt.Errorf("requesting a token: %w", err)
+		}
+
+		t := cmp.Or(token.Token, token.AccessToken)
+		if t == "" {
+			return "", errors.New("the token service returned no token")
+		}
+		return "Bearer " + t, nil
+
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+}
+
+// parseChallenge parses a WWW-Authenticate header like
+// `Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"`.
+func parseChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := map[string]string{}
+
+	for rest = strings.TrimSpace(rest); rest != ""; {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if strings.HasPrefix(value, `"`) {
+			// Quoted values may contain commas, like the scopes of several repositories.
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				params[key] = value[1:]
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = value[end+2:]
+		} else {
+			params[key], rest, _ = strings.Cut(value, ",")
+		}
+		rest = strings.TrimLeft(rest, ", ")
+	}
+
+	return scheme, params
+}
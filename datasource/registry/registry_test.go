@@ -0,0 +1,280 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRepository(t *testing.T) {
+	tests := []struct {
+		repository string
+		wantHost   string
+		wantName   string
+		wantErr    bool
+	}{
+		{repository: "nginx", wantHost: "docker.io", wantName: "library/nginx"},
+		{repository: "team/api", wantHost: "docker.io", wantName: "team/api"},
+		{repository: "docker.io/nginx", wantHost: "docker.io", wantName: "library/nginx"},
+		{repository: "docker.io/team/api", wantHost: "docker.io", wantName: "team/api"},
+		{repository: "ghcr.io/team/api", wantHost: "ghcr.io", wantName: "team/api"},
+		{repository: "registry.example.com:5000/team/sub/api", wantHost: "registry.example.com:5000", wantName: "team/sub/api"},
+		{repository: "localhost/api", wantHost: "localhost", wantName: "api"},
+		{repository: "localhost:5000/api", wantHost: "localhost:5000", wantName: "api"},
+		{repository: "", wantErr: true},
+		{repository: "team/api@sha256:abc", wantErr: true},
+		{repository: "team/ api", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repository, func(t *testing.T) {
+			host, name, err := ParseRepository(tt.repository)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected an error %t, got %v", tt.wantErr, err)
+			}
+			if host != tt.wantHost || name != tt.wantName {
+				t.Fatalf("expected %q and %q, got %q and %q", tt.wantHost, tt.wantName, host, name)
+			}
+		})
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{``, ``},
+		{`</v2/api/tags/list?last=1.2&n=100>; rel="next"`, `/v2/api/tags/list?last=1.2&n=100`},
+		{`</v2/api/tags/list?last=1.2>;rel=next`, `/v2/api/tags/list?last=1.2`},
+		{`<https://other/v2/api/tags/list?last=1.2>; type="application/json"; rel="next"`, `https://other/v2/api/tags/list?last=1.2`},
+		{`</v2/api/tags/list?n=100>; rel="prev", </v2/api/tags/list?last=1.2>; rel="next"`, `/v2/api/tags/list?last=1.2`},
+		{`</v2/api/tags/list?n=100>; rel="prev"`, ``},
+		{`/v2/api/tags/list?last=1.2; rel="next"`, ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := nextLink(tt.header); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	tests := []struct {
+		header     string
+		wantScheme string
+		wantParams map[string]string
+	}{
+		{
+			header:     `Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"`,
+			wantScheme: "Bearer",
+			wantParams: map[string]string{"realm": "https://auth.docker.io/token", "service": "registry.docker.io", "scope": "repository:library/nginx:pull"},
+		},
+		{
+			header:     `Bearer realm="https://ghcr.io/token", scope="repository:team/api:pull,push", service=ghcr.io`,
+			wantScheme: "Bearer",
+			wantParams: map[string]string{"realm": "https://ghcr.io/token", "scope": "repository:team/api:pull,push", "service": "ghcr.io"},
+		},
+		{
+			header:     `Basic realm="Registry Realm"`,
+			wantScheme: "Basic",
+			wantParams: map[string]string{"realm": "Registry Realm"},
+		},
+		{
+			header:     `Bearer Realm=https://auth/token,Service=registry`,
+			wantScheme: "Bearer",
+			wantParams: map[string]string{"realm": "https://auth/token", "service": "registry"},
+		},
+		{
+			header:     `Bearer realm="https://auth/token`,
+			wantScheme: "Bearer",
+			wantParams: map[string]string{"realm": "https://auth/token"},
+		},
+		{
+			header:     `Basic`,
+			wantScheme: "Basic",
+			wantParams: map[string]string{},
+		},
+		{
+			header:     ``,
+			wantScheme: "",
+			wantParams: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			scheme, params := parseChallenge(tt.header)
+			if scheme != tt.wantScheme || !reflect.DeepEqual(params, tt.wantParams) {
+				t.Fatalf("expected %q and %v, got %q and %v", tt.wantScheme, tt.wantParams, scheme, params)
+			}
+		})
+	}
+}
+
+// fakeRegistry serves the pages of tags of the repository team/api, linking every page to the next one.
+type fakeRegistry struct {
+	pages [][]string
+	// auth is the scheme the registry requires, basic or bearer, or empty for anonymous access.
+	auth string
+	// absoluteLinks links to the next pages with absolute URLs instead of paths.
+	absoluteLinks bool
+	// endless links the last page to the first one.
+	endless bool
+
+	// tokenRequests holds the query and the basic credentials of the requests to the token service.
+	tokenRequests []string
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		user, password, _ := r.BasicAuth()
+		f.tokenRequests = append(f.tokenRequests, r.URL.RawQuery+" "+user+":"+password)
+		if user != "" && password != "secret" {
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "t0ken"})
+		return
+	}
+
+	if r.URL.Path != "/v2/team/api/tags/list" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch f.auth {
+	case "basic":
+		if user, password, ok := r.BasicAuth(); !ok || user != "ci" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="Registry Realm"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	case "bearer":
+		if r.Header.Get("Authorization") != "Bearer t0ken" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="registry",scope="repository:team/api:pull"`, r.Host))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	page := 0
+	fmt.Sscan(r.URL.Query().Get("page"), &page)
+	if page < len(f.pages)-1 || f.endless {
+		link := fmt.Sprintf("/v2/team/api/tags/list?page=%d", (page+1)%len(f.pages))
+		if f.absoluteLinks {
+			link = "http://" + r.Host + link
+		}
+		w.Header().Set("Link", "<"+link+`>; rel="next"`)
+	}
+	json.NewEncoder(w).Encode(map[string]any{"name": "team/api", "tags": f.pages[page]})
+}
+
+func TestListTags(t *testing.T) {
+	pages := [][]string{{"1.0.0", "1.1.0"}, {"1.2.0"}, {"latest"}}
+
+	tests := []struct {
+		name              string
+		registry          *fakeRegistry
+		config            Config
+		want              []string
+		wantTokenRequests []string
+		wantErr           string
+	}{
+		{
+			name:     "anonymous",
+			registry: &fakeRegistry{pages: pages},
+			want:     []string{"1.0.0", "1.1.0", "1.2.0", "latest"},
+		},
+		{
+			name:     "absolute links",
+			registry: &fakeRegistry{pages: pages, absoluteLinks: true},
+			want:     []string{"1.0.0", "1.1.0", "1.2.0", "latest"},
+		},
+		{
+			name:     "basic",
+			registry: &fakeRegistry{pages: pages, auth: "basic"},
+			config:   Config{Username: "ci", Password: "secret"},
+			want:     []string{"1.0.0", "1.1.0", "1.2.0", "latest"},
+		},
+		{
+			name:     "basic without credentials",
+			registry: &fakeRegistry{pages: pages, auth: "basic"},
+			wantErr:  "the registry requires credentials",
+		},
+		{
+			name:     "basic with invalid credentials",
+			registry: &fakeRegistry{pages: pages, auth: "basic"},
+			config:   Config{Username: "ci", Password: "wrong"},
+			wantErr:  "401 Unauthorized",
+		},
+		{
+			name:              "anonymous token",
+			registry:          &fakeRegistry{pages: pages, auth: "bearer"},
+			want:              []string{"1.0.0", "1.1.0", "1.2.0", "latest"},
+			wantTokenRequests: []string{"scope=repository%3Ateam%2Fapi%3Apull&service=registry :"},
+		},
+		{
+			name:              "token with credentials",
+			registry:          &fakeRegistry{pages: pages, auth: "bearer"},
+			config:            Config{Username: "ci", Password: "secret"},
+			want:              []string{"1.0.0", "1.1.0", "1.2.0", "latest"},
+			wantTokenRequests: []string{"scope=repository%3Ateam%2Fapi%3Apull&service=registry ci:secret"},
+		},
+		{
+			name:              "token with invalid credentials",
+			registry:          &fakeRegistry{pages: pages, auth: "bearer"},
+			config:            Config{Username: "ci", Password: "wrong"},
+			wantTokenRequests: []string{"scope=repository%3Ateam%2Fapi%3Apull&service=registry ci:wrong"},
+			wantErr:           "requesting a token: unexpected status 401 Unauthorized",
+		},
+		{
+			name:     "endless pages",
+			registry: &fakeRegistry{pages: pages, endless: true},
+			wantErr:  "more than 100 pages of tags",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.registry)
+			defer server.Close()
+
+			tt.config.PlainHTTP = true
+			client := NewClient(tt.config)
+
+			tags, err := client.ListTags(context.Background(), strings.TrimPrefix(server.URL, "http://")+"/team/api")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tags, tt.want) {
+				t.Fatalf("expected the tags %v, got %v", tt.want, tags)
+			}
+
+			// The token is requested once for all the pages.
+			if !reflect.DeepEqual(tt.registry.tokenRequests, tt.wantTokenRequests) {
+				t.Fatalf("expected the token requests %q, got %q", tt.wantTokenRequests, tt.registry.tokenRequests)
+			}
+		})
+	}
+
+	// Unknown repositories fail with the status of the registry.
+	server := httptest.NewServer(&fakeRegistry{pages: pages})
+	defer server.Close()
+	_, err := NewClient(Config{PlainHTTP: true}).ListTags(context.Background(), strings.TrimPrefix(server.URL, "http://")+"/team/web")
+	if err == nil || !strings.Contains(err.Error(), "404 Not Found") {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
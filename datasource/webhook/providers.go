@@ -0,0 +1,277 @@
+package webhook
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"overseer/datasource"
+	"path"
+	"strconv"
+	"time"
+)
+
+// githubPayload is the part of a GitHub deployment_status event that is used.
+type githubPayload struct {
+	DeploymentStatus struct {
+		Id          int64     `json:"id"`
+		State       string    `json:"state"`
+		Description string    `json:"description"`
+		Environment string    `json:"environment"`
+		TargetURL   string    `json:"target_url"`
+		LogURL      string    `json:"log_url"`
+		CreatedAt   time.Time `json:"created_at"`
+	} `json:"deployment_status"`
+	Deployment struct {
+		Sha         string          `json:"sha"`
+		Ref         string          `json:"ref"`
+		Environment string          `json:"environment"`
+		Payload     json.RawMessage `json:"payload"`
+		Creator     struct {
+			Login string `json:"login"`
+		} `json:"creator"`
+	} `json:"deployment"`
+	Repository struct {
+		Name string `json:"name"`
+	} `json:"repository"`
+}
+
+// GitHubDeploymentPayload are the fields of the payload of a GitHub deployment that are used,
+// set with the payload input of the deployment, like {"version": "1.2.3"}.
+type GitHubDeploymentPayload struct {
+	// DeploymentName overrides the default name of <repository>.<environment>.
+	DeploymentName string `json:"deployment_name"`
+	Component      string `json:"component"`
+	// Version overrides the ref of the deployment.
+	Version string `json:"version"`
+	Image   string `json:"image"`
+}
+
+// parseGitHub maps a successful deployment status to a deployment and the other states to the rollout of it.
+func parseGitHub(header http.Header, body []byte) ([]datasource.Event, error) {
+	switch event := header.Get(HeaderGitHubEvent); event {
+	case "ping":
+		return nil, nil
+	case "deployment_status":
+	default:
+		return nil, fmt.Errorf("unsupported GitHub event %q, only deployment_status is handled", event)
+	}
+
+	var p githubPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("decoding the deployment status: %w", err)
+	}
+
+	var status string
+	switch p.DeploymentStatus.State {
+	case "success":
+	case "queued", "pending":
+		status = datasource.RolloutPending
+	case "in_progress":
+		status = datasource.RolloutRunning
+	case "failure", "error":
+		status = datasource.RolloutFailed
+	default:
+		// Inactive deployments were replaced by a later one, which is reported by itself.
+		return nil, nil
+	}
+
+	var deploymentPayload GitHubDeploymentPayload
+	if raw := p.Deployment.Payload; len(raw) > 0 && string(raw) != `""` {
+		// The payload is an object, or a string holding one if it was given as a string.
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			raw = json.RawMessage(s)
+		}
+		if err := json.Unmarshal(raw, &deploymentPayload); err != nil {
+			return nil, fmt.Errorf("decoding the deployment payload: %w", err)
+		}
+	}
+
+	environment := cmp.Or(p.DeploymentStatus.Environment, p.Deployment.Environment)
+	e := datasource.Event{
+		Id:             "github:" + strconv.FormatInt(p.DeploymentStatus.Id, 10),
+		DeploymentName: cmp.Or(deploymentPayload.DeploymentName, p.Repository.Name+"."+environment),
+		Component:      deploymentPayload.Component,
+		Version:        cmp.Or(deploymentPayload.Version, p.Deployment.Ref),
+		DeployedAt:     p.DeploymentStatus.CreatedAt,
+		Metadata: datasource.Metadata{
+			Image:     deploymentPayload.Image,
+			GitCommit: p.Deployment.Sha,
+			BuildURL:  cmp.Or(p.DeploymentStatus.LogURL, p.DeploymentStatus.TargetURL),
+			Deployer:  p.Deployment.Creator.Login,
+		},
+	}
+
+	if status != "" {
+		e.Rollout = &datasource.Rollout{Status: status, Description: p.DeploymentStatus.Description}
+	}
+
+	if err := validate(e); err != nil {
+		return nil, err
+	}
+	return []datasource.Event{e}, nil
+}
+
+// gitlabTimeFormat is the format of the times in GitLab webhooks.
+const gitlabTimeFormat = "2006-01-02 15:04:05 -0700"
+
+// gitlabPayload is the part of a GitLab deployment event that is used.
+type gitlabPayload struct {
+	ObjectKind      string `json:"object_kind"`
+	Status          string `json:"status"`
+	StatusChangedAt string `json:"status_changed_at"`
+	DeploymentId    int64  `json:"deployment_id"`
+	DeployableURL   string `json:"deployable_url"`
+	Environment     string `json:"environment"`
+	Project         struct {
+		Name string `json:"name"`
+	} `json:"project"`
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	CommitURL string `json:"commit_url"`
+	Ref       string `json:"ref"`
+}
+
+// parseGitLab maps a successful deployment to a deployment and the other statuses to the rollout of it.
+// The deployment is named <project>.<environment> and the ref is used as the version.
+func parseGitLab(_ http.Header, body []byte) ([]datasource.Event, error) {
+	var p gitlabPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("decoding the deployment event: %w", err)
+	}
+
+	if p.ObjectKind != "deployment" {
+		return nil, fmt.Errorf("unsupported GitLab event %q, only deployment events are handled", p.ObjectKind)
+	}
+
+	var status string
+	switch p.Status {
+	case "success":
+	case "created":
+		status = datasource.RolloutPending
+	case "running":
+		status = datasource.RolloutRunning
+	case "failed":
+		status = datasource.RolloutFailed
+	case "canceled":
+		status = datasource.RolloutCancelled
+	default:
+		return nil, nil
+	}
+
+	changedAt, err := time.Parse(gitlabTimeFormat, p.StatusChangedAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status_changed_at: %w", err)
+	}
+
+	e := datasource.Event{
+		Id:             "gitlab:" + strconv.FormatInt(p.DeploymentId, 10) + ":" + p.Status,
+		DeploymentName: p.Project.Name + "." + p.Environment,
+		Version:        p.Ref,
+		DeployedAt:     changedAt,
+		Metadata: datasource.Metadata{
+			// The commit URL ends with the commit sha.
+			GitCommit: path.Base(p.CommitURL),
+			BuildURL:  p.DeployableURL,
+			Deployer:  p.User.Username,
+		},
+	}
+
+	if status != "" {
+		e.Rollout = &datasource.Rollout{Status: status}
+	}
+
+	if err := validate(e); err != nil {
+		return nil, err
+	}
+	return []datasource.Event{e}, nil
+}
+
+// GenericPayload is the body of the generic webhook, for CI/CD systems without a webhook of their own.
+type GenericPayload struct {
+	// Id identifies the event in the logs, defaults to the deployment name and time.
+	Id             string `json:"id"`
+	DeploymentName string `json:"deployment_name"`
+	Component      string `json:"component"`
+	// Version is required unless the deployment is undeployed.
+	Version string `json:"version"`
+	// DeployedAt defaults to the time the webhook is received.
+	DeployedAt time.Time `json:"deployed_at"`
+	Undeployed bool      `json:"undeployed"`
+	// Rollout reports the progress of rolling out the version instead of a new deployment.
+	Rollout  *GenericRollout `json:"rollout"`
+	Metadata struct {
+		Image       string            `json:"image"`
+		ImageDigest string            `json:"image_digest"`
+		GitCommit   string            `json:"git_commit"`
+		BuildURL    string            `json:"build_url"`
+		Deployer    string            `json:"deployer"`
+		Labels      map[string]string `json:"labels"`
+	} `json:"metadata"`
+}
+
+type GenericRollout struct {
+	// Status is one of pending, running, paused, successful, failed or cancelled.
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	Healthy     int32  `json:"healthy"`
+	Desired     int32  `json:"desired"`
+}
+
+func parseGeneric(_ http.Header, body []byte) ([]datasource.Event, error) {
+	var p GenericPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("decoding the deployment: %w", err)
+	}
+
+	if p.DeployedAt.IsZero() {
+		p.DeployedAt = time.Now()
+	}
+
+	e := datasource.Event{
+		Id:             cmp.Or(p.Id, p.DeploymentName+"@"+p.DeployedAt.Format(time.RFC3339Nano)),
+		DeploymentName: p.DeploymentName,
+		Component:      p.Component,
+		Version:        p.Version,
+		DeployedAt:     p.DeployedAt,
+		Undeployed:     p.Undeployed,
+		Metadata:       datasource.Metadata(p.Metadata),
+	}
+
+	if p.Rollout != nil {
+		if p.Undeployed {
+			return nil, errors.New("an undeployment has no rollout")
+		}
+		e.Rollout = &datasource.Rollout{
+			Status:      p.Rollout.Status,
+			Description: p.Rollout.Description,
+			Healthy:     p.Rollout.Healthy,
+			Desired:     p.Rollout.Desired,
+		}
+	}
+
+	if err := validate(e); err != nil {
+		return nil, err
+	}
+	return []datasource.Event{e}, nil
+}
+
+// validate rejects events the app would, so that the sender gets the error instead of the logs.
+func validate(e datasource.Event) error {
+	if e.DeploymentName == "" {
+		return errors.New("the deployment name is required")
+	}
+	if e.Undeployed {
+		if e.Version != "" {
+			return errors.New("an undeployment has no version")
+		}
+		return nil
+	}
+	if e.Version == "" {
+		return errors.New("the version is required")
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package webhook
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"overseer/datasource"
+)
+
+func TestParseGitHub(t *testing.T) {
+	status := func(state, payload string) string {
+		return `{
+			"deployment_status": {"id": 42, "state": "` + state + `", "description": "rolling", "environment": "prod",
+				"target_url": "https://ci/target", "log_url": "https://ci/log", "created_at": "2025-06-01T12:00:00Z"},
+			"deployment": {"sha": "abc123", "ref": "v1.2.3", "environment": "staging", "payload": ` + payload + `, "creator": {"login": "octocat"}},
+			"repository": {"name": "api"}
+		}`
+	}
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	deployment := datasource.Event{
+		Id:             "github:42",
+		DeploymentName: "api.prod",
+		Version:        "v1.2.3",
+		DeployedAt:     at,
+		Metadata:       datasource.Metadata{GitCommit: "abc123", BuildURL: "https://ci/log", Deployer: "octocat"},
+	}
+	with := func(f func(e *datasource.Event)) *datasource.Event {
+		e := deployment
+		f(&e)
+		return &e
+	}
+
+	tests := []struct {
+		name    string
+		event   string
+		body    string
+		want    *datasource.Event
+		wantErr bool
+	}{
+		{"ping", "ping", `{}`, nil, false},
+		{"unsupported event", "push", `{}`, nil, true},
+		{"invalid body", "deployment_status", `{`, nil, true},
+		{"success", "deployment_status", status("success", `{}`), &deployment, false},
+		{"inactive", "deployment_status", status("inactive", `{}`), nil, false},
+		{"queued", "deployment_status", status("queued", `{}`), with(func(e *datasource.Event) {
+			e.Rollout = &datasource.Rollout{Status: datasource.RolloutPending, Description: "rolling"}
+		}), false},
+		{"in progress", "deployment_status", status("in_progress", `{}`), with(func(e *datasource.Event) {
+			e.Rollout = &datasource.Rollout{Status: datasource.RolloutRunning, Description: "rolling"}
+		}), false},
+		{"failure", "deployment_status", status("failure", `{}`), with(func(e *datasource.Event) {
+			e.Rollout = &datasource.Rollout{Status: datasource.RolloutFailed, Description: "rolling"}
+		}), false},
+		{"payload object", "deployment_status", status("success", `{"version": "1.2.3", "deployment_name": "api-prod", "component": "web", "image": "api:1.2.3"}`), with(func(e *datasource.Event) {
+			e.Version = "1.2.3"
+			e.DeploymentName = "api-prod"
+			e.Component = "web"
+			e.Metadata.Image = "api:1.2.3"
+		}), false},
+		{"payload string", "deployment_status", status("success", `"{\"version\": \"1.2.3\"}"`), with(func(e *datasource.Event) {
+			e.Version = "1.2.3"
+		}), false},
+		{"empty payload string", "deployment_status", status("success", `""`), &deployment, false},
+		{"invalid payload", "deployment_status", status("success", `"not json"`), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set(HeaderGitHubEvent, tt.event)
+			events, err := parseGitHub(header, []byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected an error %t, got %v", tt.wantErr, err)
+			}
+			expectEvent(t, events, tt.want)
+		})
+	}
+}
+
+func TestParseGitLab(t *testing.T) {
+	event := func(kind, status, changedAt string) string {
+		return `{
+			"object_kind": "` + kind + `", "status": "` + status + `", "status_changed_at": "` + changedAt + `",
+			"deployment_id": 7, "deployable_url": "https://gitlab/jobs/1", "environment": "prod",
+			"project": {"name": "api"}, "user": {"username": "dev"},
+			"commit_url": "https://gitlab/api/-/commit/abc123", "ref": "v2.0.0"
+		}`
+	}
+	deployment := func(status string, rollout *datasource.Rollout) *datasource.Event {
+		return &datasource.Event{
+			Id:             "gitlab:7:" + status,
+			DeploymentName: "api.prod",
+			Version:        "v2.0.0",
+			DeployedAt:     time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC),
+			Metadata:       datasource.Metadata{GitCommit: "abc123", BuildURL: "https://gitlab/jobs/1", Deployer: "dev"},
+			Rollout:        rollout,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		want    *datasource.Event
+		wantErr bool
+	}{
+		{"success", event("deployment", "success", "2025-06-01 12:00:00 +0200"), deployment("success", nil), false},
+		{"created", event("deployment", "created", "2025-06-01 12:00:00 +0200"), deployment("created", &datasource.Rollout{Status: datasource.RolloutPending}), false},
+		{"running", event("deployment", "running", "2025-06-01 12:00:00 +0200"), deployment("running", &datasource.Rollout{Status: datasource.RolloutRunning}), false},
+		{"failed", event("deployment", "failed", "2025-06-01 12:00:00 +0200"), deployment("failed", &datasource.Rollout{Status: datasource.RolloutFailed}), false},
+		{"canceled", event("deployment", "canceled", "2025-06-01 12:00:00 +0200"), deployment("canceled", &datasource.Rollout{Status: datasource.RolloutCancelled}), false},
+		{"unknown status", event("deployment", "blocked", "2025-06-01 12:00:00 +0200"), nil, false},
+		{"not a deployment", event("push", "success", "2025-06-01 12:00:00 +0200"), nil, true},
+		{"invalid time", event("deployment", "success", "2025-06-01T12:00:00Z"), nil, true},
+		{"invalid body", `[]`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := parseGitLab(http.Header{}, []byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected an error %t, got %v", tt.wantErr, err)
+			}
+			expectEvent(t, events, tt.want)
+		})
+	}
+}
+
+func TestParseGeneric(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		body    string
+		want    *datasource.Event
+		wantErr bool
+	}{
+		{
+			name: "deployment",
+			body: `{"id": "build-1", "deployment_name": "api.prod", "component": "web", "version": "1.0.0", "deployed_at": "2025-06-01T12:00:00Z",
+				"metadata": {"image": "api:1.0.0", "git_commit": "abc", "deployer": "ci", "labels": {"team": "core"}}}`,
+			want: &datasource.Event{
+				Id:             "build-1",
+				DeploymentName: "api.prod",
+				Component:      "web",
+				Version:        "1.0.0",
+				DeployedAt:     at,
+				Metadata:       datasource.Metadata{Image: "api:1.0.0", GitCommit: "abc", Deployer: "ci", Labels: map[string]string{"team": "core"}},
+			},
+		},
+		{
+			name: "default id",
+			body: `{"deployment_name": "api.prod", "version": "1.0.0", "deployed_at": "2025-06-01T12:00:00Z"}`,
+			want: &datasource.Event{Id: "api.prod@2025-06-01T12:00:00Z", DeploymentName: "api.prod", Version: "1.0.0", DeployedAt: at},
+		},
+		{
+			name: "undeployment",
+			body: `{"id": "1", "deployment_name": "api.prod", "undeployed": true, "deployed_at": "2025-06-01T12:00:00Z"}`,
+			want: &datasource.Event{Id: "1", DeploymentName: "api.prod", Undeployed: true, DeployedAt: at},
+		},
+		{
+			name: "rollout",
+			body: `{"id": "1", "deployment_name": "api.prod", "version": "1.0.0", "deployed_at": "2025-06-01T12:00:00Z",
+				"rollout": {"status": "running", "description": "2 of 3", "healthy": 2, "desired": 3}}`,
+			want: &datasource.Event{
+				Id:             "1",
+				DeploymentName: "api.prod",
+				Version:        "1.0.0",
+				DeployedAt:     at,
+				Rollout:        &datasource.Rollout{Status: datasource.RolloutRunning, Description: "2 of 3", Healthy: 2, Desired: 3},
+			},
+		},
+		{name: "no deployment name", body: `{"version": "1.0.0"}`, wantErr: true},
+		{name: "no version", body: `{"deployment_name": "api.prod"}`, wantErr: true},
+		{name: "undeployment with a version", body: `{"deployment_name": "api.prod", "version": "1.0.0", "undeployed": true}`, wantErr: true},
+		{name: "undeployment with a rollout", body: `{"deployment_name": "api.prod", "undeployed": true, "rollout": {"status": "running"}}`, wantErr: true},
+		{name: "invalid body", body: `{"deployment_name": 1}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := parseGeneric(http.Header{}, []byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected an error %t, got %v", tt.wantErr, err)
+			}
+			expectEvent(t, events, tt.want)
+		})
+	}
+
+	// The deployment time defaults to when the webhook is received.
+	before := time.Now()
+	events, err := parseGeneric(http.Header{}, []byte(`{"deployment_name": "api.prod", "version": "1.0.0"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].DeployedAt.Before(before) || events[0].DeployedAt.After(time.Now()) {
+		t.Fatalf("expected the deployment time to default to now, got %+v", events)
+	}
+}
+
+// expectEvent fails the test unless events holds only want, or is empty if want is nil.
+func expectEvent(t *testing.T, events []datasource.Event, want *datasource.Event) {
+	t.Helper()
+
+	if want == nil {
+		if len(events) != 0 {
+			t.Fatalf("expected no events, got %+v", events)
+		}
+		return
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected one event, got %+v", events)
+	}
+
+	got := events[0]
+	if !got.DeployedAt.Equal(want.DeployedAt) {
+		t.Fatalf("expected the time %s, got %s", want.DeployedAt, got.DeployedAt)
+	}
+	got.DeployedAt = want.DeployedAt
+	if !reflect.DeepEqual(got, *want) {
+		t.Fatalf("expected %+v, got %+v", *want, got)
+	}
+}
@@ -0,0 +1,243 @@
+// Package webhook receives deployments pushed by CI/CD systems over HTTP.
+//
+// It accepts GitHub deployment_status events, GitLab deployment events and a generic JSON schema,
+// see GenericPayload, on the REST server under /webhooks.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"overseer/datasource"
+	"strings"
+	"sync"
+)
+
+// maxBodySize limits the size of the webhook requests.
+const maxBodySize = 1 << 20
+
+// Headers carrying the signatures or tokens of the requests.
+const (
+	HeaderGitHubSignature  = "X-Hub-Signature-256"
+	HeaderGitHubEvent      = "X-GitHub-Event"
+	HeaderGitLabToken      = "X-Gitlab-Token"
+	HeaderGenericSignature = "X-Overseer-Signature"
+)
+
+// Config holds the secrets of the webhooks, a webhook is only enabled if it has a secret.
+// Several secrets can be given to rotate them, a request signed with any of them is accepted.
+type Config struct {
+	// GitHubSecrets verify the sha256 HMAC signature GitHub sends in the X-Hub-Signature-256 header.
+	GitHubSecrets []string
+	// GitLabTokens are compared with the secret token GitLab sends in the X-Gitlab-Token header,
+	// GitLab doesn't sign its webhooks.
+	GitLabTokens []string
+	// GenericSecrets verify the signature of the generic webhook in the X-Overseer-Signature header,
+	// formatted like GitHub's as sha256=<hex encoded HMAC-SHA256 of the body>.
+	GenericSecrets []string
+}
+
+func (c Config) IsZero() bool {
+	return len(c.GitHubSecrets) == 0 && len(c.GitLabTokens) == 0 && len(c.GenericSecrets) == 0
+}
+
+type Source struct {
+	config Config
+	logger *slog.Logger
+
+	mu sync.Mutex
+	// stream is nil while the events are not streamed.
+	stream *eventStream
+}
+
+// eventStream is a stream returned by StreamEvents. The requests send on it without holding the lock of the source,
+// it is closed once it is stopped and they are done.
+type eventStream struct {
+	events chan datasource.Event
+	// done is closed when the stream is stopped, the requests waiting for room then give up.
+	done    chan struct{}
+	senders sync.WaitGroup
+}
+
+func NewSource(config Config, logger *slog.Logger) (*Source, error) {
+	if config.IsZero() {
+		return nil, errors.New("no webhook secrets configured")
+	}
+
+	for _, secrets := range [][]string{config.GitHubSecrets, config.GitLabTokens, config.GenericSecrets} {
+		for _, s := range secrets {
+			if s == "" {
+				return nil, errors.New("empty webhook secret")
+			}
+		}
+	}
+
+	return &Source{
+		config: config,
+		logger: logger,
+	}, nil
+}
+
+// StreamEvents returns the deployments received by the webhooks until the context is canceled.
+// The webhooks respond with 503 Service Unavailable while the events are not streamed.
+func (s *Source) StreamEvents(ctx context.Context) (<-chan datasource.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stream != nil {
+		return nil, errors.New("the webhook events are already streamed")
+	}
+
+	stream := &eventStream{
+		events: make(chan datasource.Event, 10),
+		done:   make(chan struct{}),
+	}
+	s.stream = stream
+
+	go func() {
+		<-ctx.Done()
+
+		s.mu.Lock()
+		s.stream = nil
+		s.mu.Unlock()
+
+		close(stream.done)
+		stream.senders.Wait()
+		close(stream.events)
+	}()
+
+	return stream.events, nil
+}
+
+// RegisterHandlers mounts the enabled webhooks on the mux.
+func (s *Source) RegisterHandlers(mux *http.ServeMux) {
+	if len(s.config.GitHubSecrets) > 0 {
+		mux.HandleFunc("POST /webhooks/github", s.handle(s.verifyGitHub, parseGitHub))
+	}
+	if len(s.config.GitLabTokens) > 0 {
+		mux.HandleFunc("POST /webhooks/gitlab", s.handle(s.verifyGitLab, parseGitLab))
+	}
+	if len(s.config.GenericSecrets) > 0 {
+		mux.HandleFunc("POST /webhooks/generic", s.handle(s.verifyGeneric, parseGeneric))
+	}
+}
+
+// parser returns the events of a verified request, none if the request is valid but not about a deployment.
+type parser func(header http.Header, body []byte) ([]datasource.Event, error)
+
+func (s *Source) handle(verify func(header http.Header, body []byte) bool, parse parser) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if !verify(r.Header, body) {
+			s.logger.Warn("rejected webhook with an invalid signature", "path", r.URL.Path, "remote", r.RemoteAddr)
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		events, err := parse(r.Header, body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := s.send(r.Context(), events); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// errNotStreamed is returned for the requests received while the events are not streamed.
+var errNotStreamed = errors.New("webhook events are not being processed")
+
+// send queues the events on the stream, waiting for room until the request is canceled or the stream is stopped.
+func (s *Source) send(ctx context.Context, events []datasource.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	stream := s.stream
+	if stream != nil {
+		stream.senders.Add(1)
+	}
+	s.mu.Unlock()
+
+	if stream == nil {
+		return errNotStreamed
+	}
+	defer stream.senders.Done()
+
+	for _, e := range events {
+		select {
+		case stream.events <- e:
+		case <-stream.done:
+			return errNotStreamed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (s *Source) verifyGitHub(header http.Header, body []byte) bool {
+	return verifySignature(s.config.GitHubSecrets, header.Get(HeaderGitHubSignature), body)
+}
+
+func (s *Source) verifyGitLab(header http.Header, _ []byte) bool {
+	token := header.Get(HeaderGitLabToken)
+	for _, t := range s.config.GitLabTokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Source) verifyGeneric(header http.Header, body []byte) bool {
+	return verifySignature(s.config.GenericSecrets, header.Get(HeaderGenericSignature), body)
+}
+
+// verifySignature checks a signature formatted as sha256=<hex encoded HMAC-SHA256 of the body>
+// against every secret.
+func verifySignature(secrets []string, signature string, body []byte) bool {
+	hexSum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+
+	sum, err := hex.DecodeString(hexSum)
+	if err != nil {
+		return false
+	}
+
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		if hmac.Equal(sum, mac.Sum(nil)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign returns the signature of the body in the format of the X-Overseer-Signature header.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return fmt.Sprintf("sha256=%x", mac.Sum(nil))
+}
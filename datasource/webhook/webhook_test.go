@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"deployment_name":"api.prod","version":"1.0.0"}`)
+	secrets := []string{"old", "new"}
+
+	tests := []struct {
+		name      string
+		signature string
+		want      bool
+	}{
+		{"first secret", Sign("old", body), true},
+		{"rotated secret", Sign("new", body), true},
+		{"unknown secret", Sign("other", body), false},
+		{"signature of another body", Sign("new", []byte(`{}`)), false},
+		{"without the prefix", strings.TrimPrefix(Sign("new", body), "sha256="), false},
+		{"another algorithm", "sha1=" + strings.TrimPrefix(Sign("new", body), "sha256="), false},
+		{"not hex", "sha256=zz", false},
+		{"truncated", Sign("new", body)[:20], false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifySignature(secrets, tt.signature, body); got != tt.want {
+				t.Fatalf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewSource(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	if _, err := NewSource(Config{}, logger); err == nil {
+		t.Fatal("expected an error without secrets")
+	}
+	if _, err := NewSource(Config{GenericSecrets: []string{"s", ""}}, logger); err == nil {
+		t.Fatal("expected an error with an empty secret")
+	}
+}
+
+// newServer returns a server with the webhooks of a source with the secrets github, gitlab and generic.
+func newServer(t *testing.T) (*Source, *httptest.Server) {
+	t.Helper()
+
+	source, err := NewSource(Config{
+		GitHubSecrets:  []string{"github"},
+		GitLabTokens:   []string{"gitlab"},
+		GenericSecrets: []string{"generic"},
+	}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	source.RegisterHandlers(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return source, server
+}
+
+func post(t *testing.T, server *httptest.Server, path string, header http.Header, body string) int {
+	t.Helper()
+
+	req, err := http.NewRequest("POST", server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header = header
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestHandlers(t *testing.T) {
+	source, server := newServer(t)
+
+	generic := `{"deployment_name":"api.prod","version":"1.0.0"}`
+	github := `{"deployment_status":{"id":1,"state":"success","environment":"prod","created_at":"2025-06-01T12:00:00Z"},"deployment":{"ref":"v1.0.0","sha":"abc"},"repository":{"name":"api"}}`
+	gitlab := `{"object_kind":"deployment","status":"success","status_changed_at":"2025-06-01 12:00:00 +0200","deployment_id":2,"environment":"prod","project":{"name":"api"},"ref":"v1.0.0"}`
+
+	// The requests are answered with 503 until the events are streamed, unless they have no events.
+	if status := post(t, server, "/webhooks/generic", http.Header{HeaderGenericSignature: {Sign("generic", []byte(generic))}}, generic); status != http.StatusServiceUnavailable {
+		t.Fatalf("expected the status 503 before the events are streamed, got %d", status)
+	}
+	if status := post(t, server, "/webhooks/github", http.Header{HeaderGitHubEvent: {"ping"}, HeaderGitHubSignature: {Sign("github", []byte(`{}`))}}, `{}`); status != http.StatusAccepted {
+		t.Fatalf("expected a ping to be accepted before the events are streamed, got %d", status)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := source.StreamEvents(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.StreamEvents(ctx); err == nil {
+		t.Fatal("expected an error streaming the events twice")
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		header     http.Header
+		body       string
+		wantStatus int
+		wantEvent  bool
+	}{
+		{"signed generic", "/webhooks/generic", http.Header{HeaderGenericSignature: {Sign("generic", []byte(generic))}}, generic, http.StatusAccepted, true},
+		{"unsigned generic", "/webhooks/generic", http.Header{}, generic, http.StatusUnauthorized, false},
+		{"generic signed with another secret", "/webhooks/generic", http.Header{HeaderGenericSignature: {Sign("github", []byte(generic))}}, generic, http.StatusUnauthorized, false},
+		{"invalid generic", "/webhooks/generic", http.Header{HeaderGenericSignature: {Sign("generic", []byte(`{"version":"1"}`))}}, `{"version":"1"}`, http.StatusBadRequest, false},
+		{"signed GitHub", "/webhooks/github", http.Header{HeaderGitHubEvent: {"deployment_status"}, HeaderGitHubSignature: {Sign("github", []byte(github))}}, github, http.StatusAccepted, true},
+		{"unsigned GitHub", "/webhooks/github", http.Header{HeaderGitHubEvent: {"deployment_status"}}, github, http.StatusUnauthorized, false},
+		{"unsupported GitHub event", "/webhooks/github", http.Header{HeaderGitHubEvent: {"push"}, HeaderGitHubSignature: {Sign("github", []byte(github))}}, github, http.StatusBadRequest, false},
+		{"GitLab with the token", "/webhooks/gitlab", http.Header{HeaderGitLabToken: {"gitlab"}}, gitlab, http.StatusAccepted, true},
+		{"GitLab with another token", "/webhooks/gitlab", http.Header{HeaderGitLabToken: {"github"}}, gitlab, http.StatusUnauthorized, false},
+		{"GitLab without a token", "/webhooks/gitlab", http.Header{}, gitlab, http.StatusUnauthorized, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := post(t, server, tt.path, tt.header, tt.body); status != tt.wantStatus {
+				t.Fatalf("expected the status %d, got %d", tt.wantStatus, status)
+			}
+
+			select {
+			case e := <-stream:
+				if !tt.wantEvent {
+					t.Fatalf("expected no event, got %+v", e)
+				}
+				if e.DeploymentName != "api.prod" || e.Version == "" {
+					t.Fatalf("expected a deployment of api.prod, got %+v", e)
+				}
+			default:
+				if tt.wantEvent {
+					t.Fatal("expected an event")
+				}
+			}
+		})
+	}
+}
+
+func TestStopWhileSending(t *testing.T) {
+	source, server := newServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := source.StreamEvents(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nobody reads the stream, the requests after the buffer is full wait for room.
+	statuses := make(chan int, cap(stream)+2)
+	for i := 0; i < cap(stream)+2; i++ {
+		go func() {
+			body := `{"deployment_name":"api.prod","version":"1.0.0"}`
+			statuses <- post(t, server, "/webhooks/generic", http.Header{HeaderGenericSignature: {Sign("generic", []byte(body))}}, body)
+		}()
+	}
+
+	var accepted int
+	for range cap(stream) {
+		if status := <-statuses; status == http.StatusAccepted {
+			accepted++
+		}
+	}
+	if accepted != cap(stream) {
+		t.Fatalf("expected %d accepted requests, got %d", cap(stream), accepted)
+	}
+
+	// Stopping the stream doesn't wait for the requests waiting for room, which give up.
+	cancel()
+	for range 2 {
+		select {
+		case status := <-statuses:
+			if status != http.StatusServiceUnavailable {
+				t.Fatalf("expected the waiting requests to get 503, got %d", status)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("the waiting requests did not give up")
+		}
+	}
+
+	// The stream is closed after the buffered events.
+	var events int
+	for range stream {
+		events++
+	}
+	if events != cap(stream) {
+		t.Fatalf("expected the %d buffered events, got %d", cap(stream), events)
+	}
+}
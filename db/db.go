@@ -0,0 +1,31 @@
+// Package db holds the SQL schemas and queries of the Postgres and SQLite databases.
+package db
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+//go:embed sqlite/migrations/*.sql
+var sqliteMigrations embed.FS
+
+// Migrations returns the versioned schema migrations, named <version>_<name>.up.sql.
+func Migrations() fs.FS {
+	sub, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		panic(err) // Can only fail if the embed directive is broken
+	}
+	return sub
+}
+
+// SQLiteMigrations returns the versioned schema migrations of the SQLite database, named like the Postgres ones.
+func SQLiteMigrations() fs.FS {
+	sub, err := fs.Sub(sqliteMigrations, "sqlite/migrations")
+	if err != nil {
+		panic(err) // Can only fail if the embed directive is broken
+	}
+	return sub
+}
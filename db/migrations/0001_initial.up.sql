@@ -1,18 +1,20 @@
-CREATE TABLE
+-- The tables use IF NOT EXISTS so that databases created from the old schema.sql
+-- can be adopted by the migrator without having to be recreated.
+CREATE TABLE IF NOT EXISTS
   environments (
     id integer GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
     name text NOT NULL UNIQUE,
     sort_order integer NOT NULL DEFAULT 0
   );
 
-CREATE TABLE
+CREATE TABLE IF NOT EXISTS
   applications (
     id integer GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
     name text NOT NULL UNIQUE,
     sort_order integer NOT NULL DEFAULT 0
   );
 
-CREATE TABLE
+CREATE TABLE IF NOT EXISTS
   instances (
     id integer GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
     name text NOT NULL UNIQUE,
@@ -21,10 +23,10 @@ CREATE TABLE
     UNIQUE (environment_id, application_id)
   );
 
-CREATE TABLE
+CREATE TABLE IF NOT EXISTS
   deployments (
     id UUID PRIMARY KEY,
     instance_id integer NOT NULL REFERENCES instances (id) ON DELETE CASCADE,
     version text NOT NULL,
     deployed_at timestamptz NOT NULL
-  );
\ No newline at end of file
+  );
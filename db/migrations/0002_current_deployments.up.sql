@@ -0,0 +1,15 @@
+CREATE INDEX deployments_instance_id_deployed_at_idx ON deployments (instance_id, deployed_at);
+
+-- The current deployment of every instance, maintained by RegisterDeployment so that
+-- the version matrix doesn't have to search the whole deployment history.
+CREATE TABLE
+  current_deployments (
+    instance_id integer PRIMARY KEY REFERENCES instances (id) ON DELETE CASCADE,
+    deployment_id UUID NOT NULL REFERENCES deployments (id) ON DELETE CASCADE,
+    deployed_at timestamptz NOT NULL
+  );
+
+INSERT INTO current_deployments (instance_id, deployment_id, deployed_at)
+SELECT DISTINCT ON (instance_id) instance_id, id, deployed_at
+FROM deployments
+ORDER BY instance_id, deployed_at DESC;
@@ -0,0 +1,16 @@
+-- received_at is when Overseer ingested the deployment, deployed_at stays the time reported by the source.
+-- sequence is an optional monotonic number supplied by the source, like the Nomad raft index.
+ALTER TABLE deployments
+  ADD COLUMN received_at timestamptz,
+  ADD COLUMN sequence bigint,
+  ADD COLUMN late boolean NOT NULL DEFAULT false,
+  ADD COLUMN clock_skew boolean NOT NULL DEFAULT false;
+
+UPDATE deployments SET received_at = deployed_at;
+
+ALTER TABLE deployments ALTER COLUMN received_at SET NOT NULL;
+
+-- The current deployment is ordered by sequence when both deployments have one,
+-- otherwise by ordered_at, the deployment time capped at the time it was received.
+ALTER TABLE current_deployments RENAME COLUMN deployed_at TO ordered_at;
+ALTER TABLE current_deployments ADD COLUMN sequence bigint;
@@ -0,0 +1,8 @@
+-- Metadata describing what was deployed and by whom, labels holds free-form key-value pairs as a JSON object.
+ALTER TABLE deployments
+  ADD COLUMN image text NOT NULL DEFAULT '',
+  ADD COLUMN image_digest text NOT NULL DEFAULT '',
+  ADD COLUMN git_commit text NOT NULL DEFAULT '',
+  ADD COLUMN build_url text NOT NULL DEFAULT '',
+  ADD COLUMN deployer text NOT NULL DEFAULT '',
+  ADD COLUMN labels jsonb NOT NULL DEFAULT '{}';
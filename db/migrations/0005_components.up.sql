@@ -0,0 +1,12 @@
+-- Instances consist of named components, like main, sidecar or migrator, each with its own version history.
+-- The primary component is the one shown in the version matrix.
+ALTER TABLE instances ADD COLUMN primary_component text NOT NULL DEFAULT 'main';
+
+ALTER TABLE deployments ADD COLUMN component text NOT NULL DEFAULT 'main';
+
+DROP INDEX deployments_instance_id_deployed_at_idx;
+CREATE INDEX deployments_instance_id_component_deployed_at_idx ON deployments (instance_id, component, deployed_at);
+
+ALTER TABLE current_deployments ADD COLUMN component text NOT NULL DEFAULT 'main';
+ALTER TABLE current_deployments DROP CONSTRAINT current_deployments_pkey;
+ALTER TABLE current_deployments ADD PRIMARY KEY (instance_id, component);
@@ -0,0 +1,16 @@
+-- The progress of rolling out the desired version of every instance component,
+-- and the last version that was successfully rolled out.
+CREATE TABLE
+  rollouts (
+    instance_id integer NOT NULL REFERENCES instances (id) ON DELETE CASCADE,
+    component text NOT NULL,
+    version text NOT NULL,
+    status text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    healthy integer NOT NULL DEFAULT 0,
+    desired integer NOT NULL DEFAULT 0,
+    running_version text NOT NULL DEFAULT '',
+    updated_at timestamptz NOT NULL,
+    sequence bigint,
+    PRIMARY KEY (instance_id, component)
+  );
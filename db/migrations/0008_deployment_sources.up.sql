@@ -0,0 +1,2 @@
+-- The name of the source that reported a deployment, empty if it was registered through the API.
+ALTER TABLE deployments ADD COLUMN source text NOT NULL DEFAULT '';
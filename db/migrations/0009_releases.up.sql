@@ -0,0 +1,8 @@
+-- The latest version of every application available in its image repository.
+CREATE TABLE
+  releases (
+    application_id integer PRIMARY KEY REFERENCES applications (id) ON DELETE CASCADE,
+    repository text NOT NULL,
+    version text NOT NULL,
+    checked_at timestamptz NOT NULL
+  );
@@ -0,0 +1,8 @@
+-- The promotion graph of the environments, versions are promoted along its edges like dev to staging to prod.
+CREATE TABLE
+  promotions (
+    from_environment_id integer NOT NULL REFERENCES environments (id) ON DELETE CASCADE,
+    to_environment_id integer NOT NULL REFERENCES environments (id) ON DELETE CASCADE,
+    PRIMARY KEY (from_environment_id, to_environment_id),
+    CHECK (from_environment_id <> to_environment_id)
+  );
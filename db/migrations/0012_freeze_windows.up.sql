@@ -0,0 +1,20 @@
+-- Periods in which deployments are frozen, scoped to an environment and/or an application, or everything if neither is set.
+-- A window is either one-off, from starts_at to ends_at, or recurring, starting at the times matching the cron
+-- schedule in time_zone and lasting duration_seconds.
+CREATE TABLE
+  freeze_windows (
+    id integer GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    reason text NOT NULL DEFAULT '',
+    environment_id integer REFERENCES environments (id) ON DELETE CASCADE,
+    application_id integer REFERENCES applications (id) ON DELETE CASCADE,
+    starts_at timestamptz,
+    ends_at timestamptz,
+    schedule text NOT NULL DEFAULT '',
+    duration_seconds bigint NOT NULL DEFAULT 0,
+    time_zone text NOT NULL DEFAULT 'UTC',
+    reject boolean NOT NULL DEFAULT false
+  );
+
+-- The name of the freeze window a deployment was made in, empty if there was none.
+ALTER TABLE deployments ADD COLUMN freeze_window text NOT NULL DEFAULT '';
@@ -0,0 +1,27 @@
+-- Chat channels, like Slack or Microsoft Teams incoming webhooks, that are sent messages about the events of Overseer.
+-- Kind decides the format of the messages, template overrides the default message of the events if it is not empty,
+-- and the events that arrive within batch_seconds of each other are sent as a single message.
+CREATE TABLE
+  notification_channels (
+    id integer GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    kind text NOT NULL,
+    url text NOT NULL,
+    template text NOT NULL DEFAULT '',
+    batch_seconds integer NOT NULL DEFAULT 0,
+    enabled boolean NOT NULL DEFAULT true
+  );
+
+-- The routing rules of the channels, a channel is only sent the events matching one of its routes.
+-- A route matches the events of its environment and application, or of all of them if they are not set,
+-- and of the event types in events, a JSON array, or of all types if it is empty.
+CREATE TABLE
+  notification_routes (
+    id integer GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    channel_id integer NOT NULL REFERENCES notification_channels (id) ON DELETE CASCADE,
+    environment_id integer REFERENCES environments (id) ON DELETE CASCADE,
+    application_id integer REFERENCES applications (id) ON DELETE CASCADE,
+    events jsonb NOT NULL DEFAULT '[]'
+  );
+
+CREATE INDEX notification_routes_channel_id_idx ON notification_routes (channel_id);
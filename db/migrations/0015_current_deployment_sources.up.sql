@@ -0,0 +1,8 @@
+-- The sequences of deployments are only comparable between deployments of the same source,
+-- so the source of the current deployment is kept next to its sequence.
+ALTER TABLE current_deployments ADD COLUMN source text NOT NULL DEFAULT '';
+
+UPDATE current_deployments c
+SET source = d.source
+FROM deployments d
+WHERE d.id = c.deployment_id;
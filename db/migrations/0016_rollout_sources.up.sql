@@ -0,0 +1,3 @@
+-- The sequences of rollouts are only comparable between rollouts of the same source,
+-- so the source of the rollout is kept next to its sequence.
+ALTER TABLE rollouts ADD COLUMN source text NOT NULL DEFAULT '';
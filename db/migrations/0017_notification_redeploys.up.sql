@@ -0,0 +1,2 @@
+-- Whether a route also matches the deployments of the version that was already deployed, like restarts.
+ALTER TABLE notification_routes ADD COLUMN redeploys boolean NOT NULL DEFAULT false;
@@ -1,12 +1,90 @@
 -- Register a deployment
 -- name: RegisterDeployment :exec
-INSERT INTO deployments (id, instance_id, version, deployed_at)
-VALUES ($1, $2, $3, $4);
+INSERT INTO deployments (
+  id, instance_id, version, deployed_at, received_at, sequence, clock_skew,
+  image, image_digest, git_commit, build_url, deployer, labels, component, undeployed, source, freeze_window
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7,
+  $8, $9, $10, $11, $12, $13, $14, $15, $16, $17);
 
 -- name: ListDeployments :many
 SELECT
+  id,
   instance_id,
   version,
-  deployed_at
-FROM deployments;
+  deployed_at,
+  received_at,
+  sequence,
+  late,
+  clock_skew,
+  image,
+  image_digest,
+  git_commit,
+  build_url,
+  deployer,
+  labels,
+  component,
+  undeployed,
+  source,
+  freeze_window
+FROM deployments
+ORDER BY instance_id, component, deployed_at;
 
+-- The history of an instance, a page of the deployment history small enough to be pruned at once.
+-- name: ListInstanceDeployments :many
+SELECT *
+FROM deployments
+WHERE instance_id = $1
+ORDER BY component, deployed_at;
+
+-- The versions deployed to a component of an instance, without the undeployments.
+-- name: ListDeployedVersions :many
+SELECT DISTINCT version
+FROM deployments
+WHERE instance_id = $1 AND component = $2 AND NOT undeployed
+ORDER BY version;
+
+-- The current deployment of every component of every instance.
+-- name: ListCurrentDeployments :many
+SELECT d.*
+FROM current_deployments c
+JOIN deployments d ON d.id = c.deployment_id
+ORDER BY c.instance_id, c.component;
+
+-- The current deployment of the instance component, locked until the end of the transaction so that it is the one
+-- UpdateCurrentDeployment replaces.
+-- name: GetCurrentDeployment :one
+SELECT d.*
+FROM current_deployments c
+JOIN deployments d ON d.id = c.deployment_id
+WHERE c.instance_id = $1 AND c.component = $2
+FOR UPDATE OF c;
+
+-- Make the deployment the current one of its instance component, unless a later one is already current.
+-- Deployments are ordered by their sequence if both have one and are of the same source, otherwise by ordered_at.
+-- No row is affected if the deployment is not made current.
+-- name: UpdateCurrentDeployment :execrows
+INSERT INTO current_deployments (instance_id, component, deployment_id, ordered_at, sequence, source)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (instance_id, component) DO UPDATE
+SET deployment_id = EXCLUDED.deployment_id,
+    ordered_at = EXCLUDED.ordered_at,
+    sequence = EXCLUDED.sequence,
+    source = EXCLUDED.source
+WHERE CASE
+  WHEN current_deployments.sequence IS NOT NULL AND EXCLUDED.sequence IS NOT NULL
+    AND current_deployments.source = EXCLUDED.source
+    THEN current_deployments.sequence <= EXCLUDED.sequence
+  ELSE current_deployments.ordered_at <= EXCLUDED.ordered_at
+END;
+
+-- name: MarkDeploymentLate :exec
+UPDATE deployments
+SET late = true
+WHERE id = $1;
+
+-- Delete the given deployments, except those that are the current deployment of their instance.
+-- name: DeleteDeployments :execrows
+DELETE FROM deployments
+WHERE id = ANY(@ids::uuid[])
+  AND id NOT IN (SELECT deployment_id FROM current_deployments);
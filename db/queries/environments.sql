@@ -17,8 +17,7 @@ RETURNING id, name, sort_order;
 
 -- name: UpdateEnvironment :one
 UPDATE environments
-SET name = $2,
-    sort_order = $3
+SET name = $2
 WHERE id = $1
 RETURNING id, name, sort_order;
 
@@ -0,0 +1,29 @@
+-- name: ListFreezeWindows :many
+SELECT *
+FROM freeze_windows
+ORDER BY id;
+
+-- name: CreateFreezeWindow :one
+INSERT INTO freeze_windows (
+  name, reason, environment_id, application_id, starts_at, ends_at, schedule, duration_seconds, time_zone, reject
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id;
+
+-- name: UpdateFreezeWindow :execrows
+UPDATE freeze_windows
+SET name = $2,
+    reason = $3,
+    environment_id = $4,
+    application_id = $5,
+    starts_at = $6,
+    ends_at = $7,
+    schedule = $8,
+    duration_seconds = $9,
+    time_zone = $10,
+    reject = $11
+WHERE id = $1;
+
+-- name: DeleteFreezeWindow :exec
+DELETE FROM freeze_windows
+WHERE id = $1;
@@ -1,12 +1,13 @@
 -- Create an instance
 -- name: CreateInstance :one
-INSERT INTO instances (environment_id, application_id, name)
-VALUES ($1, $2, $3)
+INSERT INTO instances (environment_id, application_id, name, primary_component)
+VALUES ($1, $2, $3, $4)
 RETURNING id;
 
--- name: UpdateInstance :exec
+-- name: UpdateInstance :execrows
 UPDATE instances
-SET name = $2
+SET name = $2,
+    primary_component = $3
 WHERE id = $1;
 
 -- name: ListInstances :many
@@ -14,22 +15,39 @@ SELECT
   id,
   environment_id,
   application_id,
-  name
+  name,
+  primary_component
 FROM instances
 WHERE name = $1 OR $1=''; -- filter by name if provided
 
--- List instances along with their latest deployments
+-- List instances along with the current deployment of their primary component
 -- name: ListInstancesAndDeployment :many
 SELECT
   i.id,
   i.environment_id,
   i.application_id,
   i.name,
+  i.primary_component,
+  d.id AS deployment_id,
   d.version,
-  d.deployed_at
+  d.deployed_at,
+  d.received_at,
+  d.sequence,
+  d.late,
+  d.clock_skew,
+  d.image,
+  d.image_digest,
+  d.git_commit,
+  d.build_url,
+  d.deployer,
+  d.labels,
+  d.undeployed,
+  d.source,
+  d.freeze_window
 FROM instances i
-LEFT JOIN deployments d ON i.id = d.instance_id
-  AND d.deployed_at = (SELECT MAX(deployed_at) FROM deployments WHERE instance_id = i.id);
+LEFT JOIN current_deployments c ON c.instance_id = i.id AND c.component = i.primary_component
+LEFT JOIN deployments d ON d.id = c.deployment_id
+ORDER BY i.id;
 
 -- name: GetInstance :one
 -- SELECT
@@ -45,7 +63,8 @@ SELECT
   i.id,
   i.environment_id,
   i.application_id,
-  i.name
+  i.name,
+  i.primary_component
 FROM instances i
 WHERE i.id = $1;
 
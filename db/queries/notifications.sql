@@ -0,0 +1,36 @@
+-- name: ListNotificationChannels :many
+SELECT *
+FROM notification_channels
+ORDER BY id;
+
+-- name: CreateNotificationChannel :one
+INSERT INTO notification_channels (name, kind, url, template, batch_seconds, enabled)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id;
+
+-- name: UpdateNotificationChannel :execrows
+UPDATE notification_channels
+SET name = $2,
+    kind = $3,
+    url = $4,
+    template = $5,
+    batch_seconds = $6,
+    enabled = $7
+WHERE id = $1;
+
+-- name: DeleteNotificationChannel :exec
+DELETE FROM notification_channels
+WHERE id = $1;
+
+-- name: ListNotificationRoutes :many
+SELECT *
+FROM notification_routes
+ORDER BY channel_id, id;
+
+-- name: AddNotificationRoute :exec
+INSERT INTO notification_routes (channel_id, environment_id, application_id, events, redeploys)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: DeleteNotificationRoutes :exec
+DELETE FROM notification_routes
+WHERE channel_id = $1;
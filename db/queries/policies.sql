@@ -0,0 +1,46 @@
+-- name: ListApplicationPolicies :many
+SELECT *
+FROM application_policies
+ORDER BY application_id;
+
+-- name: ListInstancePolicies :many
+SELECT p.*, i.application_id
+FROM instance_policies p
+JOIN instances i ON i.id = p.instance_id
+ORDER BY i.application_id, p.instance_id;
+
+-- name: SaveApplicationPolicy :exec
+INSERT INTO application_policies (application_id, pinned, allowed, forbidden, not_ahead_of_upstream)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (application_id) DO UPDATE
+SET pinned = EXCLUDED.pinned,
+    allowed = EXCLUDED.allowed,
+    forbidden = EXCLUDED.forbidden,
+    not_ahead_of_upstream = EXCLUDED.not_ahead_of_upstream;
+
+-- name: SaveInstancePolicy :exec
+INSERT INTO instance_policies (instance_id, pinned, allowed, forbidden, not_ahead_of_upstream)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (instance_id) DO UPDATE
+SET pinned = EXCLUDED.pinned,
+    allowed = EXCLUDED.allowed,
+    forbidden = EXCLUDED.forbidden,
+    not_ahead_of_upstream = EXCLUDED.not_ahead_of_upstream;
+
+-- name: DeleteApplicationPolicy :exec
+DELETE FROM application_policies
+WHERE application_id = $1;
+
+-- name: DeleteInstancePolicy :exec
+DELETE FROM instance_policies
+WHERE instance_id = $1;
+
+-- name: ListPolicyViolations :many
+SELECT *
+FROM policy_violations
+ORDER BY detected_at, deployment_id, rule;
+
+-- name: AddPolicyViolation :exec
+INSERT INTO policy_violations (deployment_id, instance_id, version, rule, message, detected_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,63 @@
+-- name: ListWebhooks :many
+SELECT *
+FROM webhooks
+ORDER BY id;
+
+-- name: CreateWebhook :one
+INSERT INTO webhooks (name, url, secret, events, environment_id, application_id, enabled, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id;
+
+-- name: UpdateWebhook :execrows
+UPDATE webhooks
+SET name = $2,
+    url = $3,
+    secret = $4,
+    events = $5,
+    environment_id = $6,
+    application_id = $7,
+    enabled = $8
+WHERE id = $1;
+
+-- name: DeleteWebhook :exec
+DELETE FROM webhooks
+WHERE id = $1;
+
+-- name: AddWebhookDelivery :exec
+INSERT INTO webhook_deliveries (id, webhook_id, event_id, event, payload, status, created_at, next_attempt_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetWebhookDelivery :one
+SELECT *
+FROM webhook_deliveries
+WHERE id = $1;
+
+-- The delivery log of a webhook, or of all webhooks if the webhook id is 0, the most recent first.
+-- name: ListWebhookDeliveries :many
+SELECT *
+FROM webhook_deliveries
+WHERE @webhook_id::integer = 0 OR webhook_id = @webhook_id
+ORDER BY created_at DESC, id
+LIMIT @max_deliveries;
+
+-- The pending deliveries that are due to be attempted.
+-- name: ListDueWebhookDeliveries :many
+SELECT *
+FROM webhook_deliveries
+WHERE status = 'pending' AND next_attempt_at <= $1
+ORDER BY next_attempt_at, created_at;
+
+-- name: UpdateWebhookDelivery :execrows
+UPDATE webhook_deliveries
+SET status = $2,
+    attempts = $3,
+    response_status = $4,
+    error = $5,
+    next_attempt_at = $6,
+    last_attempt_at = $7
+WHERE id = $1;
+
+-- Delete the finished deliveries created before the given time.
+-- name: DeleteWebhookDeliveries :execrows
+DELETE FROM webhook_deliveries
+WHERE status <> 'pending' AND created_at < $1;
@@ -0,0 +1,31 @@
+-- Timestamps are stored as fixed width UTC RFC 3339 text, so that they sort correctly as strings.
+CREATE TABLE
+  environments (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL UNIQUE,
+    sort_order integer NOT NULL DEFAULT 0
+  );
+
+CREATE TABLE
+  applications (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL UNIQUE,
+    sort_order integer NOT NULL DEFAULT 0
+  );
+
+CREATE TABLE
+  instances (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL UNIQUE,
+    environment_id integer NOT NULL REFERENCES environments (id) ON DELETE CASCADE,
+    application_id integer NOT NULL REFERENCES applications (id) ON DELETE CASCADE,
+    UNIQUE (environment_id, application_id)
+  );
+
+CREATE TABLE
+  deployments (
+    id text PRIMARY KEY,
+    instance_id integer NOT NULL REFERENCES instances (id) ON DELETE CASCADE,
+    version text NOT NULL,
+    deployed_at text NOT NULL
+  );
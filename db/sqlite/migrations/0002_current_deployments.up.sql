@@ -0,0 +1,19 @@
+CREATE INDEX deployments_instance_id_deployed_at_idx ON deployments (instance_id, deployed_at);
+
+-- The current deployment of every instance, maintained by RegisterDeployment so that
+-- the version matrix doesn't have to search the whole deployment history.
+CREATE TABLE
+  current_deployments (
+    instance_id integer PRIMARY KEY REFERENCES instances (id) ON DELETE CASCADE,
+    deployment_id text NOT NULL REFERENCES deployments (id) ON DELETE CASCADE,
+    deployed_at text NOT NULL
+  );
+
+INSERT INTO current_deployments (instance_id, deployment_id, deployed_at)
+SELECT instance_id, id, deployed_at
+FROM (
+  SELECT instance_id, id, deployed_at,
+    ROW_NUMBER() OVER (PARTITION BY instance_id ORDER BY deployed_at DESC, rowid DESC) AS n
+  FROM deployments
+)
+WHERE n = 1;
@@ -0,0 +1,7 @@
+-- Metadata describing what was deployed and by whom, labels holds free-form key-value pairs as a JSON object.
+ALTER TABLE deployments ADD COLUMN image text NOT NULL DEFAULT '';
+ALTER TABLE deployments ADD COLUMN image_digest text NOT NULL DEFAULT '';
+ALTER TABLE deployments ADD COLUMN git_commit text NOT NULL DEFAULT '';
+ALTER TABLE deployments ADD COLUMN build_url text NOT NULL DEFAULT '';
+ALTER TABLE deployments ADD COLUMN deployer text NOT NULL DEFAULT '';
+ALTER TABLE deployments ADD COLUMN labels text NOT NULL DEFAULT '{}';
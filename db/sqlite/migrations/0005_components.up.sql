@@ -0,0 +1,27 @@
+-- Instances consist of named components, like main, sidecar or migrator, each with its own version history.
+-- The primary component is the one shown in the version matrix.
+ALTER TABLE instances ADD COLUMN primary_component text NOT NULL DEFAULT 'main';
+
+ALTER TABLE deployments ADD COLUMN component text NOT NULL DEFAULT 'main';
+
+DROP INDEX deployments_instance_id_deployed_at_idx;
+CREATE INDEX deployments_instance_id_component_deployed_at_idx ON deployments (instance_id, component, deployed_at);
+
+-- SQLite can't change the primary key of a table, so current_deployments is recreated.
+CREATE TABLE
+  current_deployments_new (
+    instance_id integer NOT NULL REFERENCES instances (id) ON DELETE CASCADE,
+    component text NOT NULL,
+    deployment_id text NOT NULL REFERENCES deployments (id) ON DELETE CASCADE,
+    ordered_at text NOT NULL,
+    sequence integer,
+    PRIMARY KEY (instance_id, component)
+  );
+
+INSERT INTO current_deployments_new (instance_id, component, deployment_id, ordered_at, sequence)
+SELECT instance_id, 'main', deployment_id, ordered_at, sequence
+FROM current_deployments;
+
+DROP TABLE current_deployments;
+
+ALTER TABLE current_deployments_new RENAME TO current_deployments;
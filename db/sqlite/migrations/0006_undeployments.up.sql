@@ -0,0 +1,2 @@
+-- An undeployment records that a component was stopped or removed, it has no version.
+ALTER TABLE deployments ADD COLUMN undeployed boolean NOT NULL DEFAULT false;
@@ -0,0 +1,33 @@
+-- The versions that should be running, for every instance of an application and for single instances.
+-- Forbidden holds the forbidden versions as a JSON array.
+CREATE TABLE
+  application_policies (
+    application_id integer PRIMARY KEY REFERENCES applications (id) ON DELETE CASCADE,
+    pinned text NOT NULL DEFAULT '',
+    allowed text NOT NULL DEFAULT '',
+    forbidden text NOT NULL DEFAULT '[]',
+    not_ahead_of_upstream boolean NOT NULL DEFAULT false
+  );
+
+CREATE TABLE
+  instance_policies (
+    instance_id integer PRIMARY KEY REFERENCES instances (id) ON DELETE CASCADE,
+    pinned text NOT NULL DEFAULT '',
+    allowed text NOT NULL DEFAULT '',
+    forbidden text NOT NULL DEFAULT '[]',
+    not_ahead_of_upstream boolean NOT NULL DEFAULT false
+  );
+
+-- The policy rules broken by deployments, recorded when the deployments are registered.
+CREATE TABLE
+  policy_violations (
+    deployment_id text NOT NULL REFERENCES deployments (id) ON DELETE CASCADE,
+    instance_id integer NOT NULL REFERENCES instances (id) ON DELETE CASCADE,
+    version text NOT NULL,
+    rule text NOT NULL,
+    message text NOT NULL,
+    detected_at text NOT NULL,
+    PRIMARY KEY (deployment_id, rule)
+  );
+
+CREATE INDEX policy_violations_instance_id_idx ON policy_violations (instance_id);
@@ -0,0 +1,37 @@
+-- Subscriptions of other systems to the events of Overseer, like deployments.
+-- Events holds the subscribed event types as a JSON array, all events are sent if it is empty.
+-- The environment and application filter the events, events of all environments or applications are sent if they are not set.
+CREATE TABLE
+  webhooks (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL UNIQUE,
+    url text NOT NULL,
+    secret text NOT NULL DEFAULT '',
+    events text NOT NULL DEFAULT '[]',
+    environment_id integer REFERENCES environments (id) ON DELETE CASCADE,
+    application_id integer REFERENCES applications (id) ON DELETE CASCADE,
+    enabled boolean NOT NULL DEFAULT true,
+    created_at text NOT NULL
+  );
+
+-- The delivery log of the webhooks. The payload is kept as the exact body that is signed and sent,
+-- so that a redelivery sends the same bytes. A delivery is pending until it succeeds or runs out of attempts.
+CREATE TABLE
+  webhook_deliveries (
+    id text PRIMARY KEY,
+    webhook_id integer NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_id text NOT NULL,
+    event text NOT NULL,
+    payload text NOT NULL,
+    status text NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    response_status integer NOT NULL DEFAULT 0,
+    error text NOT NULL DEFAULT '',
+    created_at text NOT NULL,
+    next_attempt_at text,
+    last_attempt_at text
+  );
+
+CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, created_at);
+
+CREATE INDEX webhook_deliveries_status_idx ON webhook_deliveries (status, next_attempt_at);
@@ -0,0 +1,6 @@
+-- The sequences of deployments are only comparable between deployments of the same source,
+-- so the source of the current deployment is kept next to its sequence.
+ALTER TABLE current_deployments ADD COLUMN source text NOT NULL DEFAULT '';
+
+UPDATE current_deployments
+SET source = (SELECT d.source FROM deployments d WHERE d.id = current_deployments.deployment_id);
@@ -0,0 +1,31 @@
+-- name: ListApplications :many
+SELECT id, name, sort_order
+FROM applications
+ORDER BY sort_order, id;
+
+-- name: GetApplication :one
+SELECT id, name, sort_order
+FROM applications
+WHERE id = ?1;
+
+-- name: CreateApplication :one
+INSERT INTO applications (name, sort_order)
+VALUES (?1,
+        COALESCE((SELECT MAX(sort_order) + 1 FROM applications), 1))
+RETURNING id, name, sort_order;
+
+-- name: UpdateApplication :one
+UPDATE applications
+SET name = ?2
+WHERE id = ?1
+RETURNING id, name, sort_order;
+
+-- SQLite has no UNNEST, the store reorders by running this for every id in a transaction.
+-- name: SetApplicationSortOrder :exec
+UPDATE applications
+SET sort_order = ?2
+WHERE id = ?1;
+
+-- name: DeleteApplication :exec
+DELETE FROM applications
+WHERE id = ?1;
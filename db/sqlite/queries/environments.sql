@@ -0,0 +1,31 @@
+-- name: ListEnvironments :many
+SELECT id, name, sort_order
+FROM environments
+ORDER BY sort_order, id;
+
+-- name: GetEnvironment :one
+SELECT id, name, sort_order
+FROM environments
+WHERE id = ?1;
+
+-- name: CreateEnvironment :one
+INSERT INTO environments (name, sort_order)
+VALUES (?1,
+        COALESCE((SELECT MAX(sort_order) + 1 FROM environments), 1))
+RETURNING id, name, sort_order;
+
+-- name: UpdateEnvironment :one
+UPDATE environments
+SET name = ?2
+WHERE id = ?1
+RETURNING id, name, sort_order;
+
+-- SQLite has no UNNEST, the store reorders by running this for every id in a transaction.
+-- name: SetEnvironmentSortOrder :exec
+UPDATE environments
+SET sort_order = ?2
+WHERE id = ?1;
+
+-- name: DeleteEnvironment :exec
+DELETE FROM environments
+WHERE id = ?1;
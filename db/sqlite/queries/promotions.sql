@@ -0,0 +1,13 @@
+-- name: ListPromotions :many
+SELECT *
+FROM promotions
+ORDER BY from_environment_id, to_environment_id;
+
+-- name: DeletePromotionsTo :exec
+DELETE FROM promotions
+WHERE to_environment_id = ?;
+
+-- name: AddPromotion :exec
+INSERT INTO promotions (from_environment_id, to_environment_id)
+VALUES (?, ?)
+ON CONFLICT DO NOTHING;
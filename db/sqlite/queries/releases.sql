@@ -0,0 +1,12 @@
+-- name: ListReleases :many
+SELECT *
+FROM releases
+ORDER BY application_id;
+
+-- name: SaveRelease :exec
+INSERT INTO releases (application_id, repository, version, checked_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (application_id) DO UPDATE
+SET repository = EXCLUDED.repository,
+    version = EXCLUDED.version,
+    checked_at = EXCLUDED.checked_at;
@@ -0,0 +1,33 @@
+-- name: ListRollouts :many
+SELECT *
+FROM rollouts
+ORDER BY instance_id, component;
+
+-- Replace the rollout of the instance component, unless a later one is already stored.
+-- Rollouts are ordered by their sequence if both have one and are of the same source, otherwise by updated_at.
+-- No row is affected if the rollout is not replaced.
+-- name: UpdateRollout :execrows
+INSERT INTO rollouts (
+  instance_id, component, version, status, description,
+  healthy, desired, running_version, updated_at, sequence, source
+)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11)
+ON CONFLICT (instance_id, component) DO UPDATE
+SET version = excluded.version,
+    status = excluded.status,
+    description = excluded.description,
+    healthy = excluded.healthy,
+    desired = excluded.desired,
+    running_version = CASE
+      WHEN excluded.running_version <> '' THEN excluded.running_version
+      ELSE rollouts.running_version
+    END,
+    updated_at = excluded.updated_at,
+    sequence = excluded.sequence,
+    source = excluded.source
+WHERE CASE
+  WHEN rollouts.sequence IS NOT NULL AND excluded.sequence IS NOT NULL
+    AND rollouts.source = excluded.source
+    THEN rollouts.sequence <= excluded.sequence
+  ELSE rollouts.updated_at <= excluded.updated_at
+END;
@@ -78,3 +78,11 @@ func (e *ApplicationServer) Update(ctx context.Context, req *applicationpb.Updat
 
 	return &applicationpb.UpdateResponse{}, nil
 }
+
+func (e *ApplicationServer) Delete(ctx context.Context, req *applicationpb.DeleteRequest) (*applicationpb.DeleteResponse, error) {
+	if err := e.app.DeleteApplication(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &applicationpb.DeleteResponse{}, nil
+}
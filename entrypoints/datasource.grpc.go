@@ -0,0 +1,86 @@
+package entrypoints
+
+import (
+	"context"
+	datasourcepb "overseer/api-go/datasource/v1"
+	"overseer/app"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type DatasourceServer struct {
+	app *app.App
+}
+
+func NewDatasourceServer(app *app.App) datasourcepb.DatasourceServiceServer {
+	return &DatasourceServer{
+		app: app,
+	}
+}
+
+func (d *DatasourceServer) List(ctx context.Context, req *datasourcepb.ListRequest) (*datasourcepb.ListResponse, error) {
+	sources, err := d.app.ListSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbSources []*datasourcepb.Datasource
+	for _, s := range sources {
+		pbSources = append(pbSources, datasourceToPb(s))
+	}
+
+	return &datasourcepb.ListResponse{
+		Datasources: pbSources,
+	}, nil
+}
+
+func (d *DatasourceServer) Enable(ctx context.Context, req *datasourcepb.EnableRequest) (*datasourcepb.EnableResponse, error) {
+	s, err := d.app.EnableSource(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &datasourcepb.EnableResponse{
+		Datasource: datasourceToPb(s),
+	}, nil
+}
+
+func (d *DatasourceServer) Disable(ctx context.Context, req *datasourcepb.DisableRequest) (*datasourcepb.DisableResponse, error) {
+	s, err := d.app.DisableSource(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &datasourcepb.DisableResponse{
+		Datasource: datasourceToPb(s),
+	}, nil
+}
+
+func (d *DatasourceServer) Restart(ctx context.Context, req *datasourcepb.RestartRequest) (*datasourcepb.RestartResponse, error) {
+	s, err := d.app.RestartSource(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &datasourcepb.RestartResponse{
+		Datasource: datasourceToPb(s),
+	}, nil
+}
+
+func datasourceToPb(s app.SourceStatus) *datasourcepb.Datasource {
+	pb := &datasourcepb.Datasource{
+		Name:    s.Name,
+		Type:    s.Type,
+		Enabled: s.Enabled,
+		Running: s.Running,
+		Error:   s.Error,
+		Events:  s.Events,
+	}
+	if !s.StartedAt.IsZero() {
+		pb.StartedAt = timestamppb.New(s.StartedAt)
+	}
+	if !s.LastEventAt.IsZero() {
+		pb.LastEventAt = timestamppb.New(s.LastEventAt)
+	}
+	return pb
+}
@@ -25,13 +25,21 @@ func (d *DeploymentServer) List(ctx context.Context, req *deploymentpb.ListReque
 		return nil, err
 	}
 
+	currentDeployments, err := d.app.ListCurrentDeployments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]bool{}
+	for _, dep := range currentDeployments {
+		current[dep.Id] = true
+	}
+
 	var pbDeployments []*deploymentpb.Deployment
 	for _, dep := range resp {
-		pbDeployments = append(pbDeployments, &deploymentpb.Deployment{
-			InstanceId: dep.InstanceId,
-			Version:    dep.Version,
-			DeployedAt: timestamppb.New(dep.DeployedAt),
-		})
+		pb := deploymentToPb(dep)
+		pb.Current = current[dep.Id]
+		pbDeployments = append(pbDeployments, pb)
 	}
 
 	return &deploymentpb.ListResponse{
@@ -44,17 +52,128 @@ func (d *DeploymentServer) List(ctx context.Context, req *deploymentpb.ListReque
 
 func (d *DeploymentServer) Register(ctx context.Context, req *deploymentpb.RegisterRequest) (*deploymentpb.RegisterResponse, error) {
 	params := app.RegisterDeploymentParams{
-		InstanceId: req.InstanceId,
-		Version:    req.Version,
+		InstanceId:     req.InstanceId,
+		Component:      req.Component,
+		Version:        req.Version,
+		Sequence:       req.Sequence,
+		Undeployed:     req.Undeployed,
+		OverrideFreeze: req.OverrideFreeze,
+	}
+
+	if m := req.Metadata; m != nil {
+		params.Metadata = app.DeploymentMetadata{
+			Image:       m.Image,
+			ImageDigest: m.ImageDigest,
+			GitCommit:   m.GitCommit,
+			BuildURL:    m.BuildUrl,
+			Deployer:    m.Deployer,
+			Labels:      m.Labels,
+		}
 	}
 
 	if req.DeployedAt != nil {
 		params.DeployedAt = req.DeployedAt.AsTime()
 	}
 
-	if err := d.app.RegisterDeployment(ctx, params); err != nil {
+	dep, err := d.app.RegisterDeployment(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	pb := deploymentToPb(dep)
+	pb.Current = !dep.Late
+
+	return &deploymentpb.RegisterResponse{
+		Deployment: pb,
+	}, nil
+}
+
+func (d *DeploymentServer) ListRollouts(ctx context.Context, req *deploymentpb.ListRolloutsRequest) (*deploymentpb.ListRolloutsResponse, error) {
+	rollouts, err := d.app.ListRollouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbRollouts []*deploymentpb.Rollout
+	for _, r := range rollouts {
+		pbRollouts = append(pbRollouts, &deploymentpb.Rollout{
+			InstanceId:     r.InstanceId,
+			Component:      r.Component,
+			Version:        r.Version,
+			Status:         r.Status,
+			Description:    r.Description,
+			Healthy:        r.Healthy,
+			Desired:        r.Desired,
+			RunningVersion: r.RunningVersion,
+			UpdatedAt:      timestamppb.New(r.UpdatedAt),
+		})
+	}
+
+	return &deploymentpb.ListRolloutsResponse{
+		Rollouts: pbRollouts,
+	}, nil
+}
+
+func (d *DeploymentServer) Changelog(ctx context.Context, req *deploymentpb.ChangelogRequest) (*deploymentpb.ChangelogResponse, error) {
+	changelog, err := d.app.Changelog(ctx, app.ChangelogParams{
+		ApplicationId: req.ApplicationId,
+		From:          req.From,
+		To:            req.To,
+		MaxCommits:    int(req.MaxCommits),
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &deploymentpb.RegisterResponse{}, nil
+	resp := &deploymentpb.ChangelogResponse{
+		FromCommit: changelog.FromCommit,
+		ToCommit:   changelog.ToCommit,
+		Truncated:  changelog.Truncated,
+		Additions:  int32(changelog.Additions),
+		Deletions:  int32(changelog.Deletions),
+	}
+	for _, c := range changelog.Commits {
+		resp.Commits = append(resp.Commits, &deploymentpb.Commit{
+			Hash:        c.Hash,
+			Author:      c.Author,
+			AuthorEmail: c.AuthorEmail,
+			Date:        timestamppb.New(c.Date),
+			Subject:     c.Subject,
+		})
+	}
+	for _, f := range changelog.Files {
+		resp.Files = append(resp.Files, &deploymentpb.FileChange{
+			Path:      f.Path,
+			Additions: int32(f.Additions),
+			Deletions: int32(f.Deletions),
+			Binary:    f.Binary,
+		})
+	}
+
+	return resp, nil
+}
+
+func deploymentToPb(d app.Deployment) *deploymentpb.Deployment {
+	return &deploymentpb.Deployment{
+		Id:         d.Id,
+		InstanceId: d.InstanceId,
+		Component:  d.Component,
+		Version:    d.Version,
+		DeployedAt: timestamppb.New(d.DeployedAt),
+		ReceivedAt: timestamppb.New(d.ReceivedAt),
+		Sequence:   d.Sequence,
+		Late:       d.Late,
+		ClockSkew:  d.ClockSkew,
+		Undeployed: d.Undeployed,
+		Source:     d.Source,
+		Freeze:     d.Freeze,
+		Metadata: &deploymentpb.DeploymentMetadata{
+			Image:       d.Metadata.Image,
+			ImageDigest: d.Metadata.ImageDigest,
+			GitCommit:   d.Metadata.GitCommit,
+			BuildUrl:    d.Metadata.BuildURL,
+			Deployer:    d.Metadata.Deployer,
+			Labels:      d.Metadata.Labels,
+		},
+	}
 }
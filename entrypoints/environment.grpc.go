@@ -78,3 +78,11 @@ func (e *EnvironmentServer) Update(ctx context.Context, req *environmentpb.Updat
 
 	return &environmentpb.UpdateResponse{}, nil
 }
+
+func (e *EnvironmentServer) Delete(ctx context.Context, req *environmentpb.DeleteRequest) (*environmentpb.DeleteResponse, error) {
+	if err := e.app.DeleteEnvironment(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &environmentpb.DeleteResponse{}, nil
+}
@@ -0,0 +1,51 @@
+package entrypoints
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"overseer/app"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorInterceptor translates the errors of the app package into gRPC status codes.
+func ErrorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err == nil {
+		return resp, nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return resp, err
+	}
+
+	switch {
+	case errors.Is(err, app.ErrNotFound):
+		return resp, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, app.ErrAlreadyExists):
+		return resp, status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, app.ErrFrozen):
+		return resp, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, app.ErrInvalid):
+		return resp, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return resp, err
+}
+
+// errorStatus returns the HTTP status of an error of the app package, like ErrorInterceptor does for gRPC.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, app.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrAlreadyExists), errors.Is(err, app.ErrFrozen):
+		return http.StatusConflict
+	case errors.Is(err, app.ErrInvalid):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,85 @@
+package entrypoints
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"overseer/app"
+	"overseer/storage/memory"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorInterceptor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"no error", nil, codes.OK},
+		{"not found", fmt.Errorf("instance 1: %w", app.ErrNotFound), codes.NotFound},
+		{"already exists", fmt.Errorf("freeze window x: %w", app.ErrAlreadyExists), codes.AlreadyExists},
+		{"frozen", fmt.Errorf("%w by holidays", app.ErrFrozen), codes.FailedPrecondition},
+		{"invalid", fmt.Errorf("invalid webhook url: %w", app.ErrInvalid), codes.InvalidArgument},
+		{"invalid argument of the app", invalidArgument(t), codes.InvalidArgument},
+		{"status", status.Error(codes.InvalidArgument, "bad"), codes.InvalidArgument},
+		{"other error", errors.New("connection reset"), codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(ctx context.Context, req any) (any, error) {
+				return "response", tt.err
+			}
+
+			resp, err := ErrorInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("expected the code %s, got %s (%v)", tt.want, got, err)
+			}
+			if tt.err == nil && resp != "response" {
+				t.Fatalf("expected the response of the handler, got %v", resp)
+			}
+			if want, got := status.Convert(tt.err).Message(), status.Convert(err).Message(); got != want {
+				t.Fatalf("expected the message %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+// invalidArgument returns the error of the app for an invalid argument.
+func invalidArgument(t *testing.T) error {
+	t.Helper()
+
+	_, err := app.New(memory.New(), app.Options{}).CreateWebhook(context.Background(), app.Webhook{Name: "ci", URL: "ftp://example.com"})
+	if err == nil {
+		t.Fatal("expected the webhook url to be rejected")
+	}
+	return err
+}
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not found", fmt.Errorf("instance 1: %w", app.ErrNotFound), http.StatusNotFound},
+		{"already exists", fmt.Errorf("freeze window x: %w", app.ErrAlreadyExists), http.StatusConflict},
+		{"frozen", fmt.Errorf("%w by holidays", app.ErrFrozen), http.StatusConflict},
+		{"invalid", invalidArgument(t), http.StatusBadRequest},
+		{"other error", errors.New("connection reset"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorStatus(tt.err); got != tt.want {
+				t.Fatalf("expected the status %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
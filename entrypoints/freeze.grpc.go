@@ -0,0 +1,123 @@
+package entrypoints
+
+import (
+	"context"
+	"errors"
+	freezepb "overseer/api-go/freeze/v1"
+	"overseer/app"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type FreezeServer struct {
+	app *app.App
+}
+
+func NewFreezeServer(app *app.App) freezepb.FreezeServiceServer {
+	return &FreezeServer{
+		app: app,
+	}
+}
+
+func (f *FreezeServer) List(ctx context.Context, req *freezepb.ListRequest) (*freezepb.ListResponse, error) {
+	windows, err := f.app.ListFreezeWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbWindows []*freezepb.FreezeWindow
+	for _, w := range windows {
+		pbWindows = append(pbWindows, freezeWindowToPb(w))
+	}
+
+	return &freezepb.ListResponse{
+		Windows: pbWindows,
+	}, nil
+}
+
+func (f *FreezeServer) Create(ctx context.Context, req *freezepb.CreateRequest) (*freezepb.CreateResponse, error) {
+	if req.Window == nil {
+		return nil, errors.New("window is required")
+	}
+
+	w, err := f.app.CreateFreezeWindow(ctx, freezeWindowFromPb(req.Window))
+	if err != nil {
+		return nil, err
+	}
+
+	return &freezepb.CreateResponse{
+		Window: freezeWindowToPb(w),
+	}, nil
+}
+
+func (f *FreezeServer) Update(ctx context.Context, req *freezepb.UpdateRequest) (*freezepb.UpdateResponse, error) {
+	if req.Window == nil {
+		return nil, errors.New("window is required")
+	}
+
+	w, err := f.app.UpdateFreezeWindow(ctx, freezeWindowFromPb(req.Window))
+	if err != nil {
+		return nil, err
+	}
+
+	return &freezepb.UpdateResponse{
+		Window: freezeWindowToPb(w),
+	}, nil
+}
+
+func (f *FreezeServer) Delete(ctx context.Context, req *freezepb.DeleteRequest) (*freezepb.DeleteResponse, error) {
+	if err := f.app.DeleteFreezeWindow(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &freezepb.DeleteResponse{}, nil
+}
+
+func freezeWindowToPb(w app.FreezeWindow) *freezepb.FreezeWindow {
+	pb := &freezepb.FreezeWindow{
+		Id:              w.Id,
+		Name:            w.Name,
+		Reason:          w.Reason,
+		EnvironmentId:   w.EnvironmentId,
+		ApplicationId:   w.ApplicationId,
+		Schedule:        w.Schedule,
+		DurationSeconds: w.DurationSeconds,
+		TimeZone:        w.TimeZone,
+		Reject:          w.Reject,
+		Active:          w.Active,
+	}
+	if !w.StartsAt.IsZero() {
+		pb.StartsAt = timestamppb.New(w.StartsAt)
+	}
+	if !w.EndsAt.IsZero() {
+		pb.EndsAt = timestamppb.New(w.EndsAt)
+	}
+	if !w.ActiveUntil.IsZero() {
+		pb.ActiveUntil = timestamppb.New(w.ActiveUntil)
+	}
+	if !w.NextStart.IsZero() {
+		pb.NextStart = timestamppb.New(w.NextStart)
+	}
+	return pb
+}
+
+func freezeWindowFromPb(pb *freezepb.FreezeWindow) app.FreezeWindow {
+	w := app.FreezeWindow{
+		Id:              pb.Id,
+		Name:            pb.Name,
+		Reason:          pb.Reason,
+		EnvironmentId:   pb.EnvironmentId,
+		ApplicationId:   pb.ApplicationId,
+		Schedule:        pb.Schedule,
+		DurationSeconds: pb.DurationSeconds,
+		TimeZone:        pb.TimeZone,
+		Reject:          pb.Reject,
+	}
+	if pb.StartsAt != nil {
+		w.StartsAt = pb.StartsAt.AsTime()
+	}
+	if pb.EndsAt != nil {
+		w.EndsAt = pb.EndsAt.AsTime()
+	}
+	return w
+}
@@ -18,9 +18,10 @@ func NewInstanceServer(app *app.App) instancepb.InstanceServiceServer {
 
 func (d *InstanceServer) Create(ctx context.Context, req *instancepb.CreateRequest) (*instancepb.CreateResponse, error) {
 	resp, err := d.app.CreateInstance(ctx, app.CreateInstanceParameters{
-		Name:          req.Name,
-		EnvironmentId: req.EnvironmentId,
-		ApplicationId: req.ApplicationId,
+		Name:             req.Name,
+		EnvironmentId:    req.EnvironmentId,
+		ApplicationId:    req.ApplicationId,
+		PrimaryComponent: req.PrimaryComponent,
 	})
 	if err != nil {
 		return nil, err
@@ -33,8 +34,9 @@ func (d *InstanceServer) Create(ctx context.Context, req *instancepb.CreateReque
 
 func (d *InstanceServer) Update(ctx context.Context, req *instancepb.UpdateRequest) (*instancepb.UpdateResponse, error) {
 	if err := d.app.UpdateInstance(ctx, app.UpdateInstanceParameters{
-		Id:   req.Id,
-		Name: req.Name,
+		Id:               req.Id,
+		Name:             req.Name,
+		PrimaryComponent: req.PrimaryComponent,
 	}); err != nil {
 		return nil, err
 	}
@@ -53,10 +55,11 @@ func (d *InstanceServer) List(ctx context.Context, req *instancepb.ListRequest)
 	var pbInstances []*instancepb.Instance
 	for _, dep := range resp {
 		pbInstances = append(pbInstances, &instancepb.Instance{
-			Id:            int32(dep.Id),
-			EnvironmentId: int32(dep.EnvironmentId),
-			ApplicationId: int32(dep.ApplicationId),
-			Name:          dep.Name,
+			Id:               int32(dep.Id),
+			EnvironmentId:    int32(dep.EnvironmentId),
+			ApplicationId:    int32(dep.ApplicationId),
+			Name:             dep.Name,
+			PrimaryComponent: dep.PrimaryComponent,
 		})
 	}
 
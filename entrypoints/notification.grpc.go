@@ -0,0 +1,125 @@
+package entrypoints
+
+import (
+	"context"
+	"errors"
+	notificationpb "overseer/api-go/notification/v1"
+	"overseer/app"
+)
+
+type NotificationServer struct {
+	app *app.App
+}
+
+func NewNotificationServer(app *app.App) notificationpb.NotificationServiceServer {
+	return &NotificationServer{
+		app: app,
+	}
+}
+
+func (n *NotificationServer) List(ctx context.Context, req *notificationpb.ListRequest) (*notificationpb.ListResponse, error) {
+	channels, err := n.app.ListNotificationChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbChannels []*notificationpb.Channel
+	for _, c := range channels {
+		pbChannels = append(pbChannels, notificationChannelToPb(c))
+	}
+
+	return &notificationpb.ListResponse{
+		Channels: pbChannels,
+	}, nil
+}
+
+func (n *NotificationServer) Create(ctx context.Context, req *notificationpb.CreateRequest) (*notificationpb.CreateResponse, error) {
+	if req.Channel == nil {
+		return nil, errors.New("channel is required")
+	}
+
+	c, err := n.app.CreateNotificationChannel(ctx, notificationChannelFromPb(req.Channel))
+	if err != nil {
+		return nil, err
+	}
+
+	return &notificationpb.CreateResponse{
+		Channel: notificationChannelToPb(c),
+	}, nil
+}
+
+func (n *NotificationServer) Update(ctx context.Context, req *notificationpb.UpdateRequest) (*notificationpb.UpdateResponse, error) {
+	if req.Channel == nil {
+		return nil, errors.New("channel is required")
+	}
+
+	c, err := n.app.UpdateNotificationChannel(ctx, notificationChannelFromPb(req.Channel))
+	if err != nil {
+		return nil, err
+	}
+
+	return &notificationpb.UpdateResponse{
+		Channel: notificationChannelToPb(c),
+	}, nil
+}
+
+func (n *NotificationServer) Delete(ctx context.Context, req *notificationpb.DeleteRequest) (*notificationpb.DeleteResponse, error) {
+	if err := n.app.DeleteNotificationChannel(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &notificationpb.DeleteResponse{}, nil
+}
+
+func (n *NotificationServer) Test(ctx context.Context, req *notificationpb.TestRequest) (*notificationpb.TestResponse, error) {
+	notification, err := n.app.TestNotificationChannel(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &notificationpb.TestResponse{
+		Text: notification.Text,
+	}, nil
+}
+
+func notificationChannelToPb(c app.NotificationChannel) *notificationpb.Channel {
+	pb := &notificationpb.Channel{
+		Id:           c.Id,
+		Name:         c.Name,
+		Kind:         c.Kind,
+		Url:          c.URL,
+		Template:     c.Template,
+		BatchSeconds: c.BatchSeconds,
+		Disabled:     c.Disabled,
+	}
+	for _, r := range c.Routes {
+		pb.Routes = append(pb.Routes, &notificationpb.Route{
+			EnvironmentId: r.EnvironmentId,
+			ApplicationId: r.ApplicationId,
+			Events:        r.Events,
+			Redeploys:     r.Redeploys,
+		})
+	}
+	return pb
+}
+
+func notificationChannelFromPb(pb *notificationpb.Channel) app.NotificationChannel {
+	c := app.NotificationChannel{
+		Id:           pb.Id,
+		Name:         pb.Name,
+		Kind:         pb.Kind,
+		URL:          pb.Url,
+		Template:     pb.Template,
+		BatchSeconds: pb.BatchSeconds,
+		Disabled:     pb.Disabled,
+	}
+	for _, r := range pb.Routes {
+		c.Routes = append(c.Routes, app.NotificationRoute{
+			EnvironmentId: r.EnvironmentId,
+			ApplicationId: r.ApplicationId,
+			Events:        r.Events,
+			Redeploys:     r.Redeploys,
+		})
+	}
+	return c
+}
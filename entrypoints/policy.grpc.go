@@ -0,0 +1,97 @@
+package entrypoints
+
+import (
+	"context"
+	"errors"
+	policypb "overseer/api-go/policy/v1"
+	"overseer/app"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type PolicyServer struct {
+	app *app.App
+}
+
+func NewPolicyServer(app *app.App) policypb.PolicyServiceServer {
+	return &PolicyServer{
+		app: app,
+	}
+}
+
+func (d *PolicyServer) List(ctx context.Context, req *policypb.ListRequest) (*policypb.ListResponse, error) {
+	policies, err := d.app.ListVersionPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbPolicies []*policypb.VersionPolicy
+	for _, p := range policies {
+		pbPolicies = append(pbPolicies, &policypb.VersionPolicy{
+			ApplicationId:      p.ApplicationId,
+			InstanceId:         p.InstanceId,
+			Pinned:             p.Pinned,
+			Allowed:            p.Allowed,
+			Forbidden:          p.Forbidden,
+			NotAheadOfUpstream: p.NotAheadOfUpstream,
+		})
+	}
+
+	return &policypb.ListResponse{
+		Policies: pbPolicies,
+	}, nil
+}
+
+func (d *PolicyServer) Set(ctx context.Context, req *policypb.SetRequest) (*policypb.SetResponse, error) {
+	if req.Policy == nil {
+		return nil, errors.New("policy is required")
+	}
+
+	if err := d.app.SetVersionPolicy(ctx, app.VersionPolicy{
+		ApplicationId:      req.Policy.ApplicationId,
+		InstanceId:         req.Policy.InstanceId,
+		Pinned:             req.Policy.Pinned,
+		Allowed:            req.Policy.Allowed,
+		Forbidden:          req.Policy.Forbidden,
+		NotAheadOfUpstream: req.Policy.NotAheadOfUpstream,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &policypb.SetResponse{}, nil
+}
+
+func (d *PolicyServer) Delete(ctx context.Context, req *policypb.DeleteRequest) (*policypb.DeleteResponse, error) {
+	if err := d.app.DeleteVersionPolicy(ctx, req.ApplicationId, req.InstanceId); err != nil {
+		return nil, err
+	}
+
+	return &policypb.DeleteResponse{}, nil
+}
+
+func (d *PolicyServer) ListViolations(ctx context.Context, req *policypb.ListViolationsRequest) (*policypb.ListViolationsResponse, error) {
+	violations, err := d.app.ListPolicyViolations(ctx, app.ListPolicyViolationsParams{
+		InstanceId:  req.InstanceId,
+		CurrentOnly: req.CurrentOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pbViolations []*policypb.Violation
+	for _, v := range violations {
+		pbViolations = append(pbViolations, &policypb.Violation{
+			DeploymentId: v.DeploymentId,
+			InstanceId:   v.InstanceId,
+			Version:      v.Version,
+			Rule:         v.Rule,
+			Message:      v.Message,
+			DetectedAt:   timestamppb.New(v.DetectedAt),
+			Current:      v.Current,
+		})
+	}
+
+	return &policypb.ListViolationsResponse{
+		Violations: pbViolations,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package entrypoints
+
+import (
+	"context"
+	promotionpb "overseer/api-go/promotion/v1"
+	"overseer/app"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type PromotionServer struct {
+	app *app.App
+}
+
+func NewPromotionServer(app *app.App) promotionpb.PromotionServiceServer {
+	return &PromotionServer{
+		app: app,
+	}
+}
+
+func (d *PromotionServer) ListEdges(ctx context.Context, req *promotionpb.ListEdgesRequest) (*promotionpb.ListEdgesResponse, error) {
+	edges, err := d.app.ListPromotionEdges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbEdges []*promotionpb.Edge
+	for _, e := range edges {
+		pbEdges = append(pbEdges, &promotionpb.Edge{
+			FromEnvironmentId: e.FromEnvironmentId,
+			ToEnvironmentId:   e.ToEnvironmentId,
+		})
+	}
+
+	return &promotionpb.ListEdgesResponse{
+		Edges: pbEdges,
+	}, nil
+}
+
+func (d *PromotionServer) SetSources(ctx context.Context, req *promotionpb.SetSourcesRequest) (*promotionpb.SetSourcesResponse, error) {
+	if err := d.app.SetPromotionSources(ctx, req.EnvironmentId, req.SourceEnvironmentIds); err != nil {
+		return nil, err
+	}
+
+	return &promotionpb.SetSourcesResponse{}, nil
+}
+
+func (d *PromotionServer) List(ctx context.Context, req *promotionpb.ListRequest) (*promotionpb.ListResponse, error) {
+	promotions, err := d.app.ListPromotions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbApplications []*promotionpb.ApplicationPromotions
+	for _, p := range promotions {
+		pbApplication := &promotionpb.ApplicationPromotions{
+			ApplicationId: p.ApplicationId,
+		}
+
+		for _, v := range p.Versions {
+			pbVersion := &promotionpb.VersionPromotion{
+				Version: v.Version,
+			}
+			for _, s := range v.Stages {
+				pbVersion.Stages = append(pbVersion.Stages, &promotionpb.StageEntry{
+					EnvironmentId: s.EnvironmentId,
+					EnteredAt:     timestamppb.New(s.EnteredAt),
+				})
+			}
+			for _, s := range v.Skipped {
+				pbVersion.Skipped = append(pbVersion.Skipped, &promotionpb.SkippedStage{
+					EnvironmentId:   s.EnvironmentId,
+					ToEnvironmentId: s.ToEnvironmentId,
+				})
+			}
+			pbApplication.Versions = append(pbApplication.Versions, pbVersion)
+		}
+
+		for _, pending := range p.Pending {
+			pbApplication.Pending = append(pbApplication.Pending, &promotionpb.PendingPromotion{
+				Version:           pending.Version,
+				FromEnvironmentId: pending.FromEnvironmentId,
+				ToEnvironmentId:   pending.ToEnvironmentId,
+				Deployed:          pending.Deployed,
+				Since:             timestamppb.New(pending.Since),
+			})
+		}
+
+		pbApplications = append(pbApplications, pbApplication)
+	}
+
+	return &promotionpb.ListResponse{
+		Applications: pbApplications,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package entrypoints
+
+import (
+	"context"
+	releasepb "overseer/api-go/release/v1"
+	"overseer/app"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ReleaseServer struct {
+	app *app.App
+}
+
+func NewReleaseServer(app *app.App) releasepb.ReleaseServiceServer {
+	return &ReleaseServer{
+		app: app,
+	}
+}
+
+func (d *ReleaseServer) List(ctx context.Context, req *releasepb.ListRequest) (*releasepb.ListResponse, error) {
+	releases, err := d.app.ListReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbReleases []*releasepb.Release
+	for _, r := range releases {
+		pbReleases = append(pbReleases, &releasepb.Release{
+			ApplicationId: r.ApplicationId,
+			Repository:    r.Repository,
+			Version:       r.Version,
+			CheckedAt:     timestamppb.New(r.CheckedAt),
+		})
+	}
+
+	return &releasepb.ListResponse{
+		Releases: pbReleases,
+	}, nil
+}
+
+func (d *ReleaseServer) ListInstances(ctx context.Context, req *releasepb.ListInstancesRequest) (*releasepb.ListInstancesResponse, error) {
+	instances, err := d.app.ListInstanceReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbInstances []*releasepb.InstanceRelease
+	for _, i := range instances {
+		pbInstances = append(pbInstances, &releasepb.InstanceRelease{
+			InstanceId:      i.InstanceId,
+			ApplicationId:   i.ApplicationId,
+			Deployed:        i.Deployed,
+			Available:       i.Available,
+			UpdateAvailable: i.UpdateAvailable,
+		})
+	}
+
+	return &releasepb.ListInstancesResponse{
+		Instances: pbInstances,
+	}, nil
+}
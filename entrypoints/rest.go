@@ -1,10 +1,13 @@
 package entrypoints
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"overseer/app"
 	"strconv"
+	"time"
 )
 
 // TODO: Error handling
@@ -12,7 +15,7 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 	mux.HandleFunc("/applications", func(w http.ResponseWriter, r *http.Request) {
 		apps, err := a.ListApplications(r.Context())
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -34,7 +37,7 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 
 		createdApp, err := a.CreateApplication(r.Context(), newApp.Name)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -64,7 +67,7 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 
 		updatedAppResult, err := a.UpdateApplication(r.Context(), int32(id), updatedApp.Name)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -85,7 +88,7 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 		}
 
 		if err := a.ReorderApplications(r.Context(), newOrder); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -101,17 +104,52 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 		}
 
 		if err := a.DeleteApplication(r.Context(), int32(id)); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
 		w.WriteHeader(http.StatusNoContent)
 	})
 
+	mux.HandleFunc("GET /applications/{id}/changelog", func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.PathValue("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		params := app.ChangelogParams{
+			ApplicationId: int32(id),
+			From:          r.URL.Query().Get("from"),
+			To:            r.URL.Query().Get("to"),
+		}
+		if maxCommits := r.URL.Query().Get("max_commits"); maxCommits != "" {
+			if params.MaxCommits, err = strconv.Atoi(maxCommits); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		changelog, err := a.Changelog(r.Context(), params)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(changelog)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
 	mux.HandleFunc("GET /environments", func(w http.ResponseWriter, r *http.Request) {
 		envs, err := a.ListEnvironments(r.Context())
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -133,7 +171,7 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 
 		createdEnv, err := a.CreateEnvironment(r.Context(), newEnv.Name)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -163,7 +201,7 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 
 		updatedEnvResult, err := a.UpdateEnvironment(r.Context(), int32(id), updatedEnv.Name)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -184,7 +222,7 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 		}
 
 		if err := a.ReorderEnvironments(r.Context(), newOrder); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -200,7 +238,7 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 		}
 
 		if err := a.DeleteEnvironment(r.Context(), int32(id)); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -210,7 +248,7 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 	mux.HandleFunc("GET /versions", func(w http.ResponseWriter, r *http.Request) {
 		instances, err := a.ListInstancesAndDeployment(r.Context())
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -223,6 +261,38 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 		w.Write(jsonData)
 	})
 
+	mux.HandleFunc("GET /deployments", func(w http.ResponseWriter, r *http.Request) {
+		deployments, err := a.ListDeployments(r.Context(), app.ListDeploymentsParameters{})
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(deployments)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("GET /rollouts", func(w http.ResponseWriter, r *http.Request) {
+		rollouts, err := a.ListRollouts(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(rollouts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
 	mux.HandleFunc("GET /instances/{id}", func(w http.ResponseWriter, r *http.Request) {
 		idStr := r.PathValue("id")
 		id, err := strconv.Atoi(idStr)
@@ -248,6 +318,29 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 		w.Write(jsonData)
 	})
 
+	mux.HandleFunc("GET /instances/{id}/components", func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.PathValue("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		components, err := a.ListComponents(r.Context(), int32(id))
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(components)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
 	mux.HandleFunc("POST /instances", func(w http.ResponseWriter, r *http.Request) {
 		var newInstance app.Instance
 		if err := json.NewDecoder(r.Body).Decode(&newInstance); err != nil {
@@ -256,9 +349,10 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 		}
 
 		createdInstance, err := a.CreateInstance(r.Context(), app.CreateInstanceParameters{
-			EnvironmentId: newInstance.EnvironmentId,
-			ApplicationId: newInstance.ApplicationId,
-			Name:          newInstance.Name,
+			EnvironmentId:    newInstance.EnvironmentId,
+			ApplicationId:    newInstance.ApplicationId,
+			Name:             newInstance.Name,
+			PrimaryComponent: newInstance.PrimaryComponent,
 		})
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -290,8 +384,9 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 		}
 
 		if err := a.UpdateInstance(r.Context(), app.UpdateInstanceParameters{
-			Id:   int32(id),
-			Name: updatedInstance.Name,
+			Id:               int32(id),
+			Name:             updatedInstance.Name,
+			PrimaryComponent: updatedInstance.PrimaryComponent,
 		}); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -309,10 +404,623 @@ func RegisterRestHandlers(mux *http.ServeMux, a *app.App) {
 		}
 
 		if err := a.DeleteInstance(r.Context(), int32(id)); err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// Preview what pruning the deployment history would delete, at the time given by the "at" query parameter or now.
+	mux.HandleFunc("GET /retention/preview", func(w http.ResponseWriter, r *http.Request) {
+		at := time.Now()
+		if atStr := r.URL.Query().Get("at"); atStr != "" {
+			var err error
+			at, err = time.Parse(time.RFC3339, atStr)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		plan, err := a.PlanPrune(r.Context(), at)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(plan)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("POST /retention/prune", func(w http.ResponseWriter, r *http.Request) {
+		deleted, err := a.Prune(r.Context(), time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(map[string]int64{"deleted": deleted})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("GET /datasources", func(w http.ResponseWriter, r *http.Request) {
+		sources, err := a.ListSources(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(sources)
+		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		w.Write(jsonData)
+	})
+
+	for action, f := range map[string]func(ctx context.Context, name string) (app.SourceStatus, error){
+		"enable":  a.EnableSource,
+		"disable": a.DisableSource,
+		"restart": a.RestartSource,
+	} {
+		mux.HandleFunc("POST /datasources/{name}/"+action, func(w http.ResponseWriter, r *http.Request) {
+			source, err := f(r.Context(), r.PathValue("name"))
+			if errors.Is(err, app.ErrNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			jsonData, err := json.Marshal(source)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Write(jsonData)
+		})
+	}
+
+	mux.HandleFunc("GET /releases", func(w http.ResponseWriter, r *http.Request) {
+		releases, err := a.ListReleases(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(releases)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("GET /releases/instances", func(w http.ResponseWriter, r *http.Request) {
+		instances, err := a.ListInstanceReleases(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(instances)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("GET /promotions", func(w http.ResponseWriter, r *http.Request) {
+		promotions, err := a.ListPromotions(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(promotions)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("GET /promotions/edges", func(w http.ResponseWriter, r *http.Request) {
+		edges, err := a.ListPromotionEdges(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		if edges == nil {
+			edges = []app.PromotionEdge{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(edges)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	// Replaces the environments versions are promoted to the environment from, the body is a list of their ids.
+	mux.HandleFunc("PUT /environments/{id}/promotions", func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.PathValue("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var sources []int32
+		if err := json.NewDecoder(r.Body).Decode(&sources); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := a.SetPromotionSources(r.Context(), int32(id), sources); err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
 
 		w.WriteHeader(http.StatusNoContent)
 	})
+
+	mux.HandleFunc("GET /policies", func(w http.ResponseWriter, r *http.Request) {
+		policies, err := a.ListVersionPolicies(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		if policies == nil {
+			policies = []app.VersionPolicy{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(policies)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("GET /policies/violations", func(w http.ResponseWriter, r *http.Request) {
+		params := app.ListPolicyViolationsParams{
+			CurrentOnly: r.URL.Query().Get("current") == "true",
+		}
+		if instance := r.URL.Query().Get("instance"); instance != "" {
+			id, err := strconv.Atoi(instance)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			params.InstanceId = int32(id)
+		}
+
+		violations, err := a.ListPolicyViolations(r.Context(), params)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(violations)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	// The policies of the applications and of the instances are set and deleted the same way,
+	// the id in the path decides which one is meant.
+	for resource, scope := range map[string]func(p *app.VersionPolicy, id int32){
+		"applications": func(p *app.VersionPolicy, id int32) { p.ApplicationId, p.InstanceId = id, 0 },
+		"instances":    func(p *app.VersionPolicy, id int32) { p.ApplicationId, p.InstanceId = 0, id },
+	} {
+		mux.HandleFunc("PUT /"+resource+"/{id}/policy", func(w http.ResponseWriter, r *http.Request) {
+			id, err := strconv.Atoi(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			var policy app.VersionPolicy
+			if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			scope(&policy, int32(id))
+
+			if err := a.SetVersionPolicy(r.Context(), policy); err != nil {
+				http.Error(w, err.Error(), errorStatus(err))
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		mux.HandleFunc("DELETE /"+resource+"/{id}/policy", func(w http.ResponseWriter, r *http.Request) {
+			id, err := strconv.Atoi(r.PathValue("id"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			var policy app.VersionPolicy
+			scope(&policy, int32(id))
+
+			if err := a.DeleteVersionPolicy(r.Context(), policy.ApplicationId, policy.InstanceId); err != nil {
+				http.Error(w, err.Error(), errorStatus(err))
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+
+	mux.HandleFunc("GET /freezes", func(w http.ResponseWriter, r *http.Request) {
+		windows, err := a.ListFreezeWindows(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		if windows == nil {
+			windows = []app.FreezeWindow{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(windows)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("POST /freezes", func(w http.ResponseWriter, r *http.Request) {
+		var window app.FreezeWindow
+		if err := json.NewDecoder(r.Body).Decode(&window); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		created, err := a.CreateFreezeWindow(r.Context(), window)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(created)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("PUT /freezes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var window app.FreezeWindow
+		if err := json.NewDecoder(r.Body).Decode(&window); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		window.Id = int32(id)
+
+		updated, err := a.UpdateFreezeWindow(r.Context(), window)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(updated)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("DELETE /freezes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := a.DeleteFreezeWindow(r.Context(), int32(id)); err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("GET /webhooks", func(w http.ResponseWriter, r *http.Request) {
+		webhooks, err := a.ListWebhooks(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		if webhooks == nil {
+			webhooks = []app.Webhook{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(webhooks)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("POST /webhooks", func(w http.ResponseWriter, r *http.Request) {
+		var webhook app.Webhook
+		if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		created, err := a.CreateWebhook(r.Context(), webhook)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(created)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("PUT /webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var webhook app.Webhook
+		if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		webhook.Id = int32(id)
+
+		updated, err := a.UpdateWebhook(r.Context(), webhook)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(updated)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("DELETE /webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := a.DeleteWebhook(r.Context(), int32(id)); err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// The delivery log of all webhooks, or of the one given by ?webhook=<id>, with at most ?limit=<n> deliveries.
+	mux.HandleFunc("GET /webhooks/deliveries", func(w http.ResponseWriter, r *http.Request) {
+		var params app.ListWebhookDeliveriesParams
+		if webhook := r.URL.Query().Get("webhook"); webhook != "" {
+			id, err := strconv.Atoi(webhook)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			params.WebhookId = int32(id)
+		}
+		if limit := r.URL.Query().Get("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			params.Limit = int32(n)
+		}
+
+		deliveries, err := a.ListWebhookDeliveries(r.Context(), params)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if deliveries == nil {
+			deliveries = []app.WebhookDelivery{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(deliveries)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("POST /webhooks/deliveries/{id}/redeliver", func(w http.ResponseWriter, r *http.Request) {
+		delivery, err := a.RedeliverWebhook(r.Context(), r.PathValue("id"))
+		if err != nil {
+			if errors.Is(err, app.ErrNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(delivery)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("GET /notifications", func(w http.ResponseWriter, r *http.Request) {
+		channels, err := a.ListNotificationChannels(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		if channels == nil {
+			channels = []app.NotificationChannel{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(channels)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("POST /notifications", func(w http.ResponseWriter, r *http.Request) {
+		var channel app.NotificationChannel
+		if err := json.NewDecoder(r.Body).Decode(&channel); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		created, err := a.CreateNotificationChannel(r.Context(), channel)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(created)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("PUT /notifications/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var channel app.NotificationChannel
+		if err := json.NewDecoder(r.Body).Decode(&channel); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		channel.Id = int32(id)
+
+		updated, err := a.UpdateNotificationChannel(r.Context(), channel)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(updated)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
+
+	mux.HandleFunc("DELETE /notifications/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := a.DeleteNotificationChannel(r.Context(), int32(id)); err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// Sends a sample message to the channel right away, responding with the message that was sent.
+	mux.HandleFunc("POST /notifications/{id}/test", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		notification, err := a.TestNotificationChannel(r.Context(), int32(id))
+		if err != nil {
+			if errors.Is(err, app.ErrNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		jsonData, err := json.Marshal(notification)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(jsonData)
+	})
 }
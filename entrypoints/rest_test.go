@@ -0,0 +1,124 @@
+package entrypoints_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"overseer/app"
+	"overseer/entrypoints"
+	"overseer/storage/memory"
+)
+
+// newServer returns a REST server of an App on an in-memory store.
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	entrypoints.RegisterRestHandlers(mux, app.New(memory.New(), app.Options{}))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func do(t *testing.T, server *httptest.Server, method, path, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestRestStatus(t *testing.T) {
+	server := newServer(t)
+
+	// The requests are made in order against the same server.
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"create environment", "POST", "/environments", `{"name":"prod"}`, http.StatusCreated},
+		{"create application", "POST", "/applications", `{"name":"api"}`, http.StatusCreated},
+		{"create instance", "POST", "/instances", `{"environment_id":1,"application_id":1,"name":"api-prod"}`, http.StatusCreated},
+		{"environment without a name", "POST", "/environments", `{"name":""}`, http.StatusBadRequest},
+		{"environment promoted from itself", "PUT", "/environments/1/promotions", `[1]`, http.StatusBadRequest},
+		{"invalid version range", "PUT", "/applications/1/policy", `{"allowed":">=1.x"}`, http.StatusBadRequest},
+		{"changelog without versions", "GET", "/applications/1/changelog", "", http.StatusBadRequest},
+		{"invalid id", "GET", "/instances/abc", "", http.StatusBadRequest},
+		{"invalid body", "POST", "/freezes", `{`, http.StatusBadRequest},
+		{"invalid freeze window", "POST", "/freezes", `{"name":"holidays"}`, http.StatusBadRequest},
+		{"create freeze window", "POST", "/freezes", `{"name":"holidays","starts_at":"2025-12-20T00:00:00Z","ends_at":"2026-01-02T00:00:00Z"}`, http.StatusCreated},
+		{"freeze window name taken", "POST", "/freezes", `{"name":"holidays","starts_at":"2025-12-20T00:00:00Z","ends_at":"2026-01-02T00:00:00Z"}`, http.StatusConflict},
+		{"freeze window of an unknown environment", "POST", "/freezes", `{"name":"launch","environment_id":99,"starts_at":"2025-12-20T00:00:00Z","ends_at":"2026-01-02T00:00:00Z"}`, http.StatusNotFound},
+		{"update unknown freeze window", "PUT", "/freezes/99", `{"name":"launch","starts_at":"2025-12-20T00:00:00Z","ends_at":"2026-01-02T00:00:00Z"}`, http.StatusNotFound},
+		{"delete freeze window", "DELETE", "/freezes/1", "", http.StatusNoContent},
+		{"create webhook", "POST", "/webhooks", `{"name":"ci","url":"http://127.0.0.1:1/hook"}`, http.StatusCreated},
+		{"webhook name taken", "POST", "/webhooks", `{"name":"ci","url":"http://127.0.0.1:1/hook"}`, http.StatusConflict},
+		{"redeliver unknown delivery", "POST", "/webhooks/deliveries/nope/redeliver", "", http.StatusNotFound},
+		{"changelog of an unknown application", "GET", "/applications/99/changelog?from=1.0.0&to=1.1.0", "", http.StatusNotFound},
+		{"create notification channel", "POST", "/notifications", `{"name":"oncall","kind":"slack","url":"http://127.0.0.1:1/hook"}`, http.StatusCreated},
+		{"invalid notification channel", "POST", "/notifications", `{"name":"irc","kind":"irc","url":"http://127.0.0.1:1/hook"}`, http.StatusBadRequest},
+		{"update unknown notification channel", "PUT", "/notifications/99", `{"name":"x","kind":"slack","url":"http://127.0.0.1:1/hook"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := do(t, server, tt.method, tt.path, tt.body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("expected the status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRestInstances(t *testing.T) {
+	server := newServer(t)
+
+	for _, req := range []struct{ path, body string }{
+		{"/environments", `{"name":"prod"}`},
+		{"/applications", `{"name":"api"}`},
+		{"/instances", `{"environment_id":1,"application_id":1,"name":"api-prod"}`},
+	} {
+		if resp := do(t, server, "POST", req.path, req.body); resp.StatusCode != http.StatusCreated {
+			t.Fatalf("creating %s: status %d", req.path, resp.StatusCode)
+		}
+	}
+
+	resp := do(t, server, "GET", "/instances/1", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the status 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected a JSON response, got %q", ct)
+	}
+
+	var instance app.Instance
+	if err := json.NewDecoder(resp.Body).Decode(&instance); err != nil {
+		t.Fatal(err)
+	}
+	want := app.Instance{Id: 1, EnvironmentId: 1, ApplicationId: 1, Name: "api-prod", PrimaryComponent: app.DefaultComponent}
+	if instance != want {
+		t.Fatalf("expected %+v, got %+v", want, instance)
+	}
+
+	resp = do(t, server, "GET", "/versions", "")
+	var versions []app.InstanceAndDeploymentResult
+	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Instance != want || versions[0].Deployment != nil {
+		t.Fatalf("expected the instance without a deployment, got %+v", versions)
+	}
+}
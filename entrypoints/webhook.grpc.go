@@ -0,0 +1,157 @@
+package entrypoints
+
+import (
+	"context"
+	"errors"
+	webhookpb "overseer/api-go/webhook/v1"
+	"overseer/app"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type WebhookServer struct {
+	app *app.App
+}
+
+func NewWebhookServer(app *app.App) webhookpb.WebhookServiceServer {
+	return &WebhookServer{
+		app: app,
+	}
+}
+
+func (w *WebhookServer) List(ctx context.Context, req *webhookpb.ListRequest) (*webhookpb.ListResponse, error) {
+	webhooks, err := w.app.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbWebhooks []*webhookpb.Webhook
+	for _, h := range webhooks {
+		pbWebhooks = append(pbWebhooks, webhookToPb(h))
+	}
+
+	return &webhookpb.ListResponse{
+		Webhooks: pbWebhooks,
+	}, nil
+}
+
+func (w *WebhookServer) Create(ctx context.Context, req *webhookpb.CreateRequest) (*webhookpb.CreateResponse, error) {
+	if req.Webhook == nil {
+		return nil, errors.New("webhook is required")
+	}
+
+	h, err := w.app.CreateWebhook(ctx, webhookFromPb(req.Webhook))
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookpb.CreateResponse{
+		Webhook: webhookToPb(h),
+	}, nil
+}
+
+func (w *WebhookServer) Update(ctx context.Context, req *webhookpb.UpdateRequest) (*webhookpb.UpdateResponse, error) {
+	if req.Webhook == nil {
+		return nil, errors.New("webhook is required")
+	}
+
+	h, err := w.app.UpdateWebhook(ctx, webhookFromPb(req.Webhook))
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookpb.UpdateResponse{
+		Webhook: webhookToPb(h),
+	}, nil
+}
+
+func (w *WebhookServer) Delete(ctx context.Context, req *webhookpb.DeleteRequest) (*webhookpb.DeleteResponse, error) {
+	if err := w.app.DeleteWebhook(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &webhookpb.DeleteResponse{}, nil
+}
+
+func (w *WebhookServer) ListDeliveries(ctx context.Context, req *webhookpb.ListDeliveriesRequest) (*webhookpb.ListDeliveriesResponse, error) {
+	deliveries, err := w.app.ListWebhookDeliveries(ctx, app.ListWebhookDeliveriesParams{
+		WebhookId: req.WebhookId,
+		Limit:     req.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pbDeliveries []*webhookpb.Delivery
+	for _, d := range deliveries {
+		pbDeliveries = append(pbDeliveries, webhookDeliveryToPb(d))
+	}
+
+	return &webhookpb.ListDeliveriesResponse{
+		Deliveries: pbDeliveries,
+	}, nil
+}
+
+func (w *WebhookServer) Redeliver(ctx context.Context, req *webhookpb.RedeliverRequest) (*webhookpb.RedeliverResponse, error) {
+	if req.DeliveryId == "" {
+		return nil, errors.New("delivery id is required")
+	}
+
+	d, err := w.app.RedeliverWebhook(ctx, req.DeliveryId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookpb.RedeliverResponse{
+		Delivery: webhookDeliveryToPb(d),
+	}, nil
+}
+
+func webhookToPb(w app.Webhook) *webhookpb.Webhook {
+	return &webhookpb.Webhook{
+		Id:            w.Id,
+		Name:          w.Name,
+		Url:           w.URL,
+		HasSecret:     w.HasSecret,
+		Events:        w.Events,
+		EnvironmentId: w.EnvironmentId,
+		ApplicationId: w.ApplicationId,
+		Disabled:      w.Disabled,
+		CreatedAt:     timestamppb.New(w.CreatedAt),
+	}
+}
+
+func webhookFromPb(pb *webhookpb.Webhook) app.Webhook {
+	return app.Webhook{
+		Id:            pb.Id,
+		Name:          pb.Name,
+		URL:           pb.Url,
+		Secret:        pb.Secret,
+		Events:        pb.Events,
+		EnvironmentId: pb.EnvironmentId,
+		ApplicationId: pb.ApplicationId,
+		Disabled:      pb.Disabled,
+	}
+}
+
+func webhookDeliveryToPb(d app.WebhookDelivery) *webhookpb.Delivery {
+	pb := &webhookpb.Delivery{
+		Id:             d.Id,
+		WebhookId:      d.WebhookId,
+		EventId:        d.EventId,
+		Event:          d.Event,
+		Payload:        string(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		Error:          d.Error,
+		CreatedAt:      timestamppb.New(d.CreatedAt),
+	}
+	if !d.NextAttemptAt.IsZero() {
+		pb.NextAttemptAt = timestamppb.New(d.NextAttemptAt)
+	}
+	if !d.LastAttemptAt.IsZero() {
+		pb.LastAttemptAt = timestamppb.New(d.LastAttemptAt)
+	}
+	return pb
+}
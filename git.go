@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// gitRepositoryFlag collects the -git-repo flags.
+type gitRepositoryFlag struct {
+	repositories *map[string]string
+}
+
+func (f gitRepositoryFlag) String() string {
+	if f.repositories == nil {
+		return ""
+	}
+
+	var parts []string
+	for application, path := range *f.repositories {
+		parts = append(parts, application+"="+path)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (f gitRepositoryFlag) Set(value string) error {
+	application, path, ok := strings.Cut(value, "=")
+	if !ok || application == "" || path == "" {
+		return fmt.Errorf("expected <application>=<path>, got %q", value)
+	}
+
+	if *f.repositories == nil {
+		*f.repositories = map[string]string{}
+	}
+	(*f.repositories)[application] = path
+	return nil
+}
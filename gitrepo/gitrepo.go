@@ -0,0 +1,142 @@
+// Package gitrepo reads the tags and history of local clones or mirrors of git repositories with the git command.
+//
+// The repositories are only read, keeping them up to date, like with a periodic git fetch, is left to the deployment.
+package gitrepo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"overseer/app"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reader reads git repositories with the git command.
+type Reader struct {
+	// binary is the git command.
+	binary string
+}
+
+func NewReader() *Reader {
+	return &Reader{binary: "git"}
+}
+
+var _ app.GitReader = (*Reader)(nil)
+
+// Check verifies that the directory is a git repository.
+func (r *Reader) Check(ctx context.Context, repository string) error {
+	_, err := r.run(ctx, repository, "rev-parse", "--git-dir")
+	return err
+}
+
+func (r *Reader) Resolve(ctx context.Context, repository, revision string) (string, bool, error) {
+	out, err := r.run(ctx, repository, "rev-parse", "--verify", "--quiet", "--end-of-options", revision+"^{commit}")
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return strings.TrimSpace(string(out)), true, nil
+}
+
+// The fields of a commit in the log are separated by the unit separator, the commits by the record separator.
+const logFormat = "--format=%H%x1f%an%x1f%ae%x1f%aI%x1f%s%x1e"
+
+func (r *Reader) Log(ctx context.Context, repository, from, to string, limit int) ([]app.Commit, error) {
+	out, err := r.run(ctx, repository, "log", logFormat, "--max-count="+strconv.Itoa(limit), "--end-of-options", from+".."+to, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []app.Commit
+	for record := range strings.SplitSeq(string(out), "\x1e") {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+
+		fields := strings.Split(record, "\x1f")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("unexpected log record %q", record)
+		}
+
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("parsing the date of commit %s: %w", fields[0], err)
+		}
+
+		commits = append(commits, app.Commit{
+			Hash:        fields[0],
+			Author:      fields[1],
+			AuthorEmail: fields[2],
+			Date:        date,
+			Subject:     fields[4],
+		})
+	}
+
+	return commits, nil
+}
+
+func (r *Reader) Diff(ctx context.Context, repository, from, to string) ([]app.FileChange, error) {
+	// Renames are listed as a deletion and an addition, so that every entry has a single path.
+	out, err := r.run(ctx, repository, "diff", "--numstat", "-z", "--no-renames", "--end-of-options", from, to, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []app.FileChange
+	for entry := range strings.SplitSeq(string(out), "\x00") {
+		if entry == "" {
+			continue
+		}
+
+		added, rest, _ := strings.Cut(entry, "\t")
+		deleted, path, ok := strings.Cut(rest, "\t")
+		if !ok {
+			return nil, fmt.Errorf("unexpected diff entry %q", entry)
+		}
+
+		change := app.FileChange{Path: path}
+		if added == "-" && deleted == "-" {
+			change.Binary = true
+		} else {
+			if change.Additions, err = strconv.Atoi(added); err != nil {
+				return nil, fmt.Errorf("unexpected diff entry %q", entry)
+			}
+			if change.Deletions, err = strconv.Atoi(deleted); err != nil {
+				return nil, fmt.Errorf("unexpected diff entry %q", entry)
+			}
+		}
+		files = append(files, change)
+	}
+
+	return files, nil
+}
+
+// run runs the git command in the repository and returns its output, the error includes what it printed to stderr.
+func (r *Reader) run(ctx context.Context, repository string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, r.binary, append([]string{"-C", repository}, args...)...)
+	// The repositories are local, git must never ask for credentials.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
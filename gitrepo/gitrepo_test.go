@@ -0,0 +1,240 @@
+package gitrepo
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"overseer/app"
+)
+
+// testRepo is a git repository in a temporary directory, committing at fixed times so the dates are known.
+type testRepo struct {
+	t   *testing.T
+	dir string
+	// commits is the number of commits so far, the nth commit is made n hours after the first.
+	commits int
+}
+
+// firstCommit is when the first commit of a testRepo is made.
+var firstCommit = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	r := &testRepo{t: t, dir: t.TempDir()}
+	r.git("init", "--quiet", "--initial-branch=main")
+	return r
+}
+
+// git runs the git command in the repository and returns its trimmed output.
+func (r *testRepo) git(args ...string) string {
+	r.t.Helper()
+
+	at := firstCommit.Add(time.Duration(r.commits) * time.Hour).Format(time.RFC3339)
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=Ada Lovelace",
+		"GIT_AUTHOR_EMAIL=ada@example.com",
+		"GIT_COMMITTER_NAME=Ada Lovelace",
+		"GIT_COMMITTER_EMAIL=ada@example.com",
+		"GIT_AUTHOR_DATE="+at,
+		"GIT_COMMITTER_DATE="+at,
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// write writes the files, a nil content removes the file.
+func (r *testRepo) write(files map[string][]byte) {
+	r.t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(r.dir, name)
+		if content == nil {
+			r.git("rm", "--quiet", "--", name)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			r.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+}
+
+// commit commits all changes with the subject and returns the hash of the commit.
+func (r *testRepo) commit(subject string) string {
+	r.t.Helper()
+
+	r.git("add", "--all")
+	r.git("commit", "--quiet", "--allow-empty", "--message", subject)
+	r.commits++
+	return r.git("rev-parse", "HEAD")
+}
+
+// lines returns the content of a file with n lines.
+func lines(n int) []byte {
+	var b strings.Builder
+	for i := range n {
+		b.WriteString("line " + string(rune('a'+i%26)) + "\n")
+	}
+	return []byte(b.String())
+}
+
+func TestResolve(t *testing.T) {
+	repo := newTestRepo(t)
+	first := repo.commit("Initial commit")
+	repo.git("tag", "1.0.0")
+	repo.git("tag", "--annotate", "--message", "Release 1.1.0", "v1.1.0")
+	second := repo.commit("Second commit")
+	repo.git("branch", "release")
+
+	r := NewReader()
+	ctx := context.Background()
+
+	tests := []struct {
+		revision string
+		want     string
+	}{
+		{"refs/tags/1.0.0", first},
+		// An annotated tag resolves to the commit it points to, not to the tag object.
+		{"refs/tags/v1.1.0", first},
+		{"release", second},
+		{first, first},
+		{first[:7], first},
+		{"refs/tags/2.0.0", ""},
+		{"0123456789abcdef0123456789abcdef01234567", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.revision, func(t *testing.T) {
+			got, ok, err := r.Resolve(ctx, repo.dir, tt.revision)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != (tt.want != "") || got != tt.want {
+				t.Fatalf("expected %q, got %q (found=%t)", tt.want, got, ok)
+			}
+		})
+	}
+
+	if err := r.Check(ctx, repo.dir); err != nil {
+		t.Fatalf("expected the repository to be valid, got %v", err)
+	}
+	if err := r.Check(ctx, t.TempDir()); err == nil {
+		t.Fatal("expected an error for a directory that is not a repository")
+	}
+	if _, _, err := r.Resolve(ctx, filepath.Join(t.TempDir(), "missing"), "main"); err == nil {
+		t.Fatal("expected an error for a missing repository")
+	}
+}
+
+func TestLog(t *testing.T) {
+	repo := newTestRepo(t)
+	first := repo.commit("Initial commit")
+	second := repo.commit("Add the API\n\nThe body is not part of the log.")
+	// The separators of the log format can't be mistaken for the ones in the subject.
+	third := repo.commit("Fix: handle \"quotes\", tabs\tand | pipes")
+
+	r := NewReader()
+	ctx := context.Background()
+
+	commits, err := r.Log(ctx, repo.dir, first, third, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []app.Commit{
+		{Hash: third, Author: "Ada Lovelace", AuthorEmail: "ada@example.com", Date: firstCommit.Add(2 * time.Hour), Subject: "Fix: handle \"quotes\", tabs\tand | pipes"},
+		{Hash: second, Author: "Ada Lovelace", AuthorEmail: "ada@example.com", Date: firstCommit.Add(time.Hour), Subject: "Add the API"},
+	}
+	if len(commits) != len(want) {
+		t.Fatalf("expected %d commits, got %+v", len(want), commits)
+	}
+	for i := range want {
+		if commits[i].Hash != want[i].Hash || commits[i].Author != want[i].Author || commits[i].AuthorEmail != want[i].AuthorEmail ||
+			!commits[i].Date.Equal(want[i].Date) || commits[i].Subject != want[i].Subject {
+			t.Fatalf("expected the commit %+v, got %+v", want[i], commits[i])
+		}
+	}
+
+	limited, err := r.Log(ctx, repo.dir, first, third, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(limited) != 1 || limited[0].Hash != third {
+		t.Fatalf("expected only the newest commit, got %+v", limited)
+	}
+
+	// The commits of from that are not in to are not listed.
+	none, err := r.Log(ctx, repo.dir, third, first, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(none) != 0 {
+		t.Fatalf("expected no commits, got %+v", none)
+	}
+
+	if _, err := r.Log(ctx, repo.dir, first, "unknown", 10); err == nil || !strings.Contains(err.Error(), "git log") {
+		t.Fatalf("expected the error of git log, got %v", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.write(map[string][]byte{
+		"README.md":       lines(3),
+		"api/server.go":   lines(10),
+		"docs/old.md":     lines(4),
+		"assets/logo.png": {0x89, 'P', 'N', 'G', 0, 0, 1},
+	})
+	from := repo.commit("Initial commit")
+
+	// The last two of the lines are replaced.
+	server := lines(10)
+	server = append(server[:len(server)-2*len("line a\n")], "changed\nadded\n"...)
+	repo.write(map[string][]byte{
+		"README.md":               nil,
+		"api/server.go":           server,
+		"docs/new name.md":        lines(4),
+		"docs/old.md":             nil,
+		"assets/logo.png":         {0x89, 'P', 'N', 'G', 0, 0, 2},
+		"tabs\tand\nnewlines.txt": lines(1),
+		"api/handlers/deploy.go":  lines(2),
+	})
+	to := repo.commit("Change everything")
+
+	files, err := NewReader().Diff(context.Background(), repo.dir, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slices.SortFunc(files, func(a, b app.FileChange) int { return strings.Compare(a.Path, b.Path) })
+	want := []app.FileChange{
+		{Path: "README.md", Deletions: 3},
+		{Path: "api/handlers/deploy.go", Additions: 2},
+		{Path: "api/server.go", Additions: 2, Deletions: 2},
+		{Path: "assets/logo.png", Binary: true},
+		// A rename is listed as a deletion and an addition.
+		{Path: "docs/new name.md", Additions: 4},
+		{Path: "docs/old.md", Deletions: 4},
+		{Path: "tabs\tand\nnewlines.txt", Additions: 1},
+	}
+	if !slices.Equal(files, want) {
+		t.Fatalf("expected the changes %+v, got %+v", want, files)
+	}
+}
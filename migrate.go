@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"overseer/db"
+	"overseer/migrate"
+	"overseer/runner"
+	"overseer/storage/sqlite"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const migrateUsage = "usage: overseer migrate up|status"
+
+// migrator applies the schema migrations of the configured storage.
+type migrator interface {
+	Up(ctx context.Context) ([]migrate.Migration, error)
+	Status(ctx context.Context) ([]migrate.Status, error)
+	Latest() int64
+}
+
+// openMigrator opens the migrator of the configured storage and returns a function closing it.
+func openMigrator(ctx context.Context, config *runner.Config) (migrator, func(), error) {
+	switch config.Storage {
+	case runner.StorageSQLite:
+		migrator, err := sqlite.OpenMigrator(config.SQLitePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open the SQLite database: %w", err)
+		}
+		return migrator, func() { migrator.Close() }, nil
+
+	case runner.StoragePostgres, "":
+		pool, err := pgxpool.New(ctx, config.DbConnString)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		migrator, err := migrate.New(pool, db.Migrations())
+		if err != nil {
+			pool.Close()
+			return nil, nil, fmt.Errorf("loading migrations: %w", err)
+		}
+		return migrator, pool.Close, nil
+
+	default:
+		return nil, nil, fmt.Errorf("the %s storage has no schema to migrate", config.Storage)
+	}
+}
+
+func migrateCmd(ctx context.Context, config *runner.Config, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	migrator, closeMigrator, err := openMigrator(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer closeMigrator()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Fprintf(out, "applied %d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(applied) == 0 {
+			fmt.Fprintf(out, "database is up to date at version %d\n", migrator.Latest())
+		}
+		return nil
+
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range status {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Local().Format(time.DateTime)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return tw.Flush()
+
+	default:
+		return errors.New(migrateUsage)
+	}
+}
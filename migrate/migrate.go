@@ -0,0 +1,282 @@
+// Package migrate applies the versioned schema migrations embedded in the binary to the database.
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// advisoryLockKey is the key of the Postgres advisory lock held while migrating,
+// so that several Overseer instances starting at the same time don't race each other.
+const advisoryLockKey int64 = 0x6f76657273656572 // "overseer"
+
+var ErrSchemaTooNew = errors.New("the database schema is newer than this binary supports")
+
+type Migration struct {
+	Version int64
+	Name    string
+	SQL     string
+}
+
+type Status struct {
+	Migration
+	// AppliedAt is nil if the migration has not been applied.
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// New creates a migrator applying the migrations found in fsys.
+// The files must be named <version>_<name>.up.sql, all other files are ignored.
+func New(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		pool:       pool,
+		migrations: migrations,
+	}, nil
+}
+
+// Load reads the migrations in fsys, sorted by version.
+// The files must be named <version>_<name>.up.sql, all other files are ignored.
+func Load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, file := range files {
+		base := strings.TrimSuffix(file, ".up.sql")
+
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s is not named <version>_<name>.up.sql", file)
+		}
+
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version: %w", file, err)
+		}
+
+		sql, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    name,
+			SQL:     string(sql),
+		})
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d", migrations[i].Version)
+		}
+	}
+
+	return migrations, nil
+}
+
+// Latest returns the version of the newest migration known to the binary.
+func (m *Migrator) Latest() int64 {
+	return Latest(m.migrations)
+}
+
+// Latest returns the version of the newest of the sorted migrations.
+func Latest(migrations []Migration) int64 {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// Up applies all pending migrations, each in its own transaction, and returns the ones that were applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquiring a connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
+		return nil, fmt.Errorf("taking the migration lock: %w", err)
+	}
+	defer func() {
+		// Use a fresh context so the lock is released even if ctx is cancelled.
+		_, _ = conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockKey)
+	}()
+
+	if err := createVersionTable(ctx, conn.Conn()); err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedVersions(ctx, conn.Conn())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.checkNotNewer(applied); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		if err := apply(ctx, conn.Conn(), migration); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+func apply(ctx context.Context, conn *pgx.Conn, migration Migration) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // The rollback is a no-op after a successful commit
+
+	if _, err := tx.Exec(ctx, migration.SQL); err != nil {
+		return fmt.Errorf("applying migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name); err != nil {
+		return fmt.Errorf("recording migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Status returns every migration known to the binary and when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquiring a connection: %w", err)
+	}
+	defer conn.Release()
+
+	applied, err := appliedVersions(ctx, conn.Conn())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.checkNotNewer(applied); err != nil {
+		return nil, err
+	}
+
+	var result []Status
+	for _, migration := range m.migrations {
+		s := Status{Migration: migration}
+		if at, ok := applied[migration.Version]; ok {
+			s.AppliedAt = &at
+		}
+		result = append(result, s)
+	}
+
+	return result, nil
+}
+
+// Check verifies that the database can be used by this binary.
+// It returns ErrSchemaTooNew if the database has migrations applied that the binary does not know about,
+// and the number of migrations that are still pending.
+func (m *Migrator) Check(ctx context.Context) (int, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, s := range status {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+func (m *Migrator) checkNotNewer(applied map[int64]time.Time) error {
+	for version := range applied {
+		if err := CheckNotNewer(m.migrations, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckNotNewer returns ErrSchemaTooNew if the applied version is newer than all the migrations.
+func CheckNotNewer(migrations []Migration, applied int64) error {
+	if latest := Latest(migrations); applied > latest {
+		return fmt.Errorf("%w: database is at version %d, binary knows up to %d", ErrSchemaTooNew, applied, latest)
+	}
+	return nil
+}
+
+func createVersionTable(ctx context.Context, conn *pgx.Conn) error {
+	_, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version bigint PRIMARY KEY,
+    name text NOT NULL,
+    applied_at timestamptz NOT NULL DEFAULT now()
+)`)
+	if err != nil {
+		return fmt.Errorf("creating the schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+func appliedVersions(ctx context.Context, conn *pgx.Conn) (map[int64]time.Time, error) {
+	var exists bool
+	if err := conn.QueryRow(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return nil, fmt.Errorf("looking for the schema_migrations table: %w", err)
+	}
+
+	applied := make(map[int64]time.Time)
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := conn.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("listing applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+
+	return applied, rows.Err()
+}
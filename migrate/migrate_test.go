@@ -0,0 +1,124 @@
+package migrate_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"overseer/db"
+	"overseer/migrate"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"10_tenth.up.sql":          {Data: []byte("SELECT 10;")},
+		"0002_second.up.sql":       {Data: []byte("SELECT 2;")},
+		"1_first_with_name.up.sql": {Data: []byte("SELECT 1;")},
+		"0003_third.up.sql":        {Data: []byte("SELECT 3;")},
+		// Only the up migrations are loaded.
+		"0003_third.down.sql": {Data: []byte("SELECT -3;")},
+		"README.md":           {Data: []byte("# Migrations")},
+	}
+
+	migrations, err := migrate.Load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The versions are sorted as numbers, not as file names.
+	var got []string
+	for _, m := range migrations {
+		got = append(got, m.Name)
+	}
+	want := []string{"first_with_name", "second", "third", "tenth"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected the migrations %q, got %q", want, got)
+	}
+	if m := migrations[3]; m.Version != 10 || m.SQL != "SELECT 10;" {
+		t.Fatalf("expected the version and SQL of the file, got %+v", m)
+	}
+	if latest := migrate.Latest(migrations); latest != 10 {
+		t.Fatalf("expected the latest version 10, got %d", latest)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		err   string
+	}{
+		{"no name", []string{"0001.up.sql"}, "is not named"},
+		{"invalid version", []string{"first_initial.up.sql"}, "invalid version"},
+		{"duplicate version", []string{"0001_initial.up.sql", "1_again.up.sql"}, "duplicate migration version 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for _, name := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+			}
+
+			if _, err := migrate.Load(fsys); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestCheckNotNewer(t *testing.T) {
+	migrations := []migrate.Migration{{Version: 1, Name: "initial"}, {Version: 2, Name: "second"}}
+
+	tests := []struct {
+		name       string
+		migrations []migrate.Migration
+		applied    int64
+		tooNew     bool
+	}{
+		{"nothing applied", migrations, 0, false},
+		{"older", migrations, 1, false},
+		{"latest", migrations, 2, false},
+		{"newer", migrations, 3, true},
+		{"no migrations", nil, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := migrate.CheckNotNewer(tt.migrations, tt.applied)
+			if errors.Is(err, migrate.ErrSchemaTooNew) != tt.tooNew || (err != nil && !tt.tooNew) {
+				t.Fatalf("expected too new to be %t, got %v", tt.tooNew, err)
+			}
+		})
+	}
+}
+
+// TestEmbeddedMigrations checks that the migrations of both databases load and stay in step.
+func TestEmbeddedMigrations(t *testing.T) {
+	postgres, err := migrate.Load(db.Migrations())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlite, err := migrate.Load(db.SQLiteMigrations())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(migrations []migrate.Migration) []string {
+		var list []string
+		for _, m := range migrations {
+			list = append(list, m.Name)
+		}
+		return list
+	}
+	if len(postgres) == 0 || !slices.Equal(names(postgres), names(sqlite)) {
+		t.Fatalf("expected the same migrations for Postgres and SQLite, got %q and %q", names(postgres), names(sqlite))
+	}
+	for i, m := range postgres {
+		if m.Version != int64(i+1) {
+			t.Fatalf("expected the versions to have no gaps, got %d at position %d", m.Version, i)
+		}
+	}
+}
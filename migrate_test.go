@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"overseer/runner"
+)
+
+func TestMigrateCmdSQLite(t *testing.T) {
+	ctx := context.Background()
+	config := &runner.Config{Storage: runner.StorageSQLite, SQLitePath: filepath.Join(t.TempDir(), "overseer.db")}
+
+	run := func(args ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		if err := migrateCmd(ctx, config, args, &out); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+
+	status := run("status")
+	if !strings.HasPrefix(status, "VERSION") || !strings.Contains(status, "initial") || strings.Count(status, "pending") != strings.Count(status, "\n")-1 {
+		t.Fatalf("expected every migration to be pending, got\n%s", status)
+	}
+
+	up := run("up")
+	if !strings.HasPrefix(up, "applied 1_initial\n") {
+		t.Fatalf("expected the migrations to be applied, got\n%s", up)
+	}
+	if status := run("status"); strings.Contains(status, "pending") {
+		t.Fatalf("expected no pending migrations, got\n%s", status)
+	}
+	if up := run("up"); !strings.HasPrefix(up, "database is up to date at version ") {
+		t.Fatalf("expected the database to be up to date, got\n%s", up)
+	}
+}
+
+func TestMigrateCmdErrors(t *testing.T) {
+	sqlite := &runner.Config{Storage: runner.StorageSQLite, SQLitePath: filepath.Join(t.TempDir(), "overseer.db")}
+
+	tests := []struct {
+		name   string
+		config *runner.Config
+		args   []string
+		err    string
+	}{
+		{"no command", sqlite, nil, migrateUsage},
+		{"unknown command", sqlite, []string{"down"}, migrateUsage},
+		{"memory storage", &runner.Config{Storage: runner.StorageMemory}, []string{"up"}, "no schema to migrate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := migrateCmd(context.Background(), tt.config, tt.args, &out); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestAutoMigrateEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"", true},
+		{"false", false},
+		{"0", false},
+		{"true", true},
+		{"not a boolean", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("OVERSEER_AUTO_MIGRATE", tt.value)
+			if got := envBoolOr("OVERSEER_AUTO_MIGRATE", true); got != tt.want {
+				t.Fatalf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
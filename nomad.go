@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"overseer/datasource/nomad"
+	"strconv"
+	"strings"
+)
+
+// nomadFlags registers the flags configuring the Nomad source.
+// They default to the environment variables of the Nomad CLI, see nomadEnvDefaults.
+func nomadFlags(fs *flag.FlagSet, config *nomad.Config) {
+	fs.StringVar(&config.Address, "nomad-addr", os.Getenv("NOMAD_ADDR"), "address of the Nomad HTTP API to stream deployments from (env NOMAD_ADDR), the demo source is used if empty")
+	fs.StringVar(&config.TokenFile, "nomad-token-file", "", "file holding the Nomad ACL token, read again on every request so it can be rotated, instead of NOMAD_TOKEN")
+	fs.Var(listFlag{&config.Namespaces}, "nomad-namespace", "Nomad namespace to stream, * for all (env NOMAD_NAMESPACE, defaults to the default namespace), can be repeated or comma separated")
+	fs.Var(listFlag{&config.Regions}, "nomad-region", "Nomad region to stream (env NOMAD_REGION, default the region of the agent), can be repeated or comma separated; the deployment names are prefixed with the region if more than one is given")
+	fs.StringVar(&config.CACert, "nomad-ca-cert", os.Getenv("NOMAD_CACERT"), "PEM file with the CA certificates used to verify Nomad (env NOMAD_CACERT)")
+	fs.StringVar(&config.ClientCert, "nomad-client-cert", os.Getenv("NOMAD_CLIENT_CERT"), "PEM file with the client certificate for mTLS (env NOMAD_CLIENT_CERT)")
+	fs.StringVar(&config.ClientKey, "nomad-client-key", os.Getenv("NOMAD_CLIENT_KEY"), "PEM file with the client key for mTLS (env NOMAD_CLIENT_KEY)")
+	fs.StringVar(&config.TLSServerName, "nomad-tls-server-name", os.Getenv("NOMAD_TLS_SERVER_NAME"), "server name to verify the Nomad certificate against, like server.global.nomad (env NOMAD_TLS_SERVER_NAME)")
+	fs.BoolVar(&config.InsecureSkipVerify, "nomad-skip-verify", envBool("NOMAD_SKIP_VERIFY"), "don't verify the Nomad certificate (env NOMAD_SKIP_VERIFY)")
+}
+
+// listFlag collects a flag that can be repeated or given as a comma separated list.
+// Setting it replaces the default taken from the environment.
+type listFlag struct {
+	values *[]string
+}
+
+func (f listFlag) String() string {
+	if f.values == nil {
+		return ""
+	}
+	return strings.Join(*f.values, ",")
+}
+
+func (f listFlag) Set(value string) error {
+	*f.values = append(*f.values, splitList(value)...)
+	return nil
+}
+
+// nomadEnvDefaults fills in what the flags left empty from the environment, after the flags are parsed.
+// The token is only read from NOMAD_TOKEN or a file so that it doesn't show up in the process list.
+func nomadEnvDefaults(config *nomad.Config) {
+	config.Token = os.Getenv("NOMAD_TOKEN")
+	if len(config.Namespaces) == 0 {
+		config.Namespaces = splitList(os.Getenv("NOMAD_NAMESPACE"))
+	}
+	if len(config.Regions) == 0 {
+		config.Regions = splitList(os.Getenv("NOMAD_REGION"))
+	}
+}
+
+func splitList(s string) []string {
+	var values []string
+	for v := range strings.SplitSeq(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func envBool(name string) bool {
+	return envBoolOr(name, false)
+}
+
+// envBoolOr returns the boolean in the environment variable, or fallback if it is unset or not a boolean.
+func envBoolOr(name string, fallback bool) bool {
+	b, err := strconv.ParseBool(os.Getenv(name))
+	if err != nil {
+		return fallback
+	}
+	return b
+}
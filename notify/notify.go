@@ -0,0 +1,49 @@
+// Package notify posts the events of Overseer to other systems over HTTP.
+package notify
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+const DefaultTimeout = 10 * time.Second
+
+// maxDrain bounds how much of a response body is read before the connection is reused.
+const maxDrain = 64 << 10
+
+// Client posts requests to the URLs of the webhooks.
+type Client struct {
+	client *http.Client
+}
+
+// NewClient returns a client whose requests time out after the timeout, DefaultTimeout if it is zero.
+func NewClient(timeout time.Duration) *Client {
+	return &Client{
+		client: &http.Client{Timeout: cmp.Or(timeout, DefaultTimeout)},
+	}
+}
+
+// Post posts the body to the URL and returns the status code of the response.
+func (c *Client) Post(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("User-Agent", "overseer")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrain))
+	return resp.StatusCode, nil
+}
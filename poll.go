@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"overseer/datasource/httppoll"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// pollFlags registers the flags configuring the version endpoints that are polled.
+func pollFlags(fs *flag.FlagSet, config *httppoll.Config) {
+	fs.Var(pollFlag{&config.Targets}, "poll", "poll the version of a deployment from its endpoint as '<deployment>=<url> [jsonpath=<path>] [regexp=<regexp>] [component=<component>] [interval=<duration>] [timeout=<duration>]', e.g. 'api.prod=https://api/actuator/info jsonpath=$.build.version', can be repeated")
+	fs.DurationVar(&config.Interval, "poll-interval", httppoll.DefaultInterval, "how often the version endpoints are polled")
+	fs.DurationVar(&config.Timeout, "poll-timeout", httppoll.DefaultTimeout, "timeout of a version endpoint request")
+	fs.IntVar(&config.MaxConcurrency, "poll-concurrency", httppoll.DefaultMaxConcurrency, "maximum number of version endpoint requests in flight")
+}
+
+// pollFlag collects the -poll flags.
+//
+// Each flag has the form <deployment>=<url> followed by space separated options,
+// so neither the URL nor the options may contain spaces.
+type pollFlag struct {
+	targets *[]httppoll.Target
+}
+
+func (f pollFlag) String() string {
+	if f.targets == nil {
+		return ""
+	}
+
+	var parts []string
+	for _, t := range *f.targets {
+		parts = append(parts, t.DeploymentName+"="+t.URL)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (f pollFlag) Set(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return fmt.Errorf("expected <deployment>=<url>, got %q", value)
+	}
+
+	name, url, ok := strings.Cut(fields[0], "=")
+	if !ok || name == "" || url == "" {
+		return fmt.Errorf("expected <deployment>=<url>, got %q", fields[0])
+	}
+
+	target := httppoll.Target{DeploymentName: name, URL: url}
+
+	for _, option := range fields[1:] {
+		key, v, ok := strings.Cut(option, "=")
+		if !ok {
+			return fmt.Errorf("expected <option>=<value>, got %q", option)
+		}
+
+		var err error
+		switch key {
+		case "jsonpath":
+			target.JSONPath = v
+		case "regexp":
+			target.Regexp, err = regexp.Compile(v)
+		case "component":
+			target.Component = v
+		case "interval":
+			target.Interval, err = time.ParseDuration(v)
+		case "timeout":
+			target.Timeout, err = time.ParseDuration(v)
+		default:
+			return fmt.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+
+	*f.targets = append(*f.targets, target)
+	return nil
+}
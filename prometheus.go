@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"overseer/datasource/prometheus"
+	"strings"
+)
+
+// prometheusFlags registers the flags configuring the Prometheus build info source.
+func prometheusFlags(fs *flag.FlagSet, config *prometheus.Config) {
+	fs.Var(scrapeTargetFlag{&config.Targets}, "prom-scrape", "scrape the build info metrics of '<url> [<label>=<value>...]', adding the labels to its series, e.g. 'http://api:9100/metrics job=api env=prod', can be repeated")
+	fs.StringVar(&config.PrometheusURL, "prom-url", "", "address of a Prometheus server to query the build info metrics from")
+	fs.StringVar(&config.Query, "prom-query", prometheus.DefaultQuery, "Prometheus query returning the build info series")
+	fs.StringVar(&config.Metric, "prom-metric", prometheus.DefaultMetric, "regexp of the names of the scraped build info metrics")
+	fs.StringVar(&config.VersionLabel, "prom-version-label", prometheus.DefaultVersionLabel, "label of the build info metrics holding the version")
+	fs.Var(prometheusRuleFlag{&config.Rules}, "prom-rule", "map build info series to a deployment as '<deployment>[:<component>] [<label>=<regexp>...]', expanding ${label}, e.g. '${job}.${env} env=prod|staging'; the first matching rule is used, defaults to '${job}', can be repeated")
+	fs.DurationVar(&config.Interval, "prom-interval", prometheus.DefaultInterval, "how often the build info metrics are read")
+	fs.DurationVar(&config.Timeout, "prom-timeout", prometheus.DefaultTimeout, "timeout of a scrape or query")
+}
+
+// scrapeTargetFlag collects the -prom-scrape flags.
+type scrapeTargetFlag struct {
+	targets *[]prometheus.ScrapeTarget
+}
+
+func (f scrapeTargetFlag) String() string {
+	if f.targets == nil {
+		return ""
+	}
+
+	var parts []string
+	for _, t := range *f.targets {
+		parts = append(parts, t.URL)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (f scrapeTargetFlag) Set(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return fmt.Errorf("expected <url> [<label>=<value>...], got %q", value)
+	}
+
+	labels, err := parseLabelPairs(fields[1:])
+	if err != nil {
+		return err
+	}
+
+	*f.targets = append(*f.targets, prometheus.ScrapeTarget{URL: fields[0], Labels: labels})
+	return nil
+}
+
+// prometheusRuleFlag collects the -prom-rule flags.
+type prometheusRuleFlag struct {
+	rules *[]prometheus.Rule
+}
+
+func (f prometheusRuleFlag) String() string {
+	if f.rules == nil {
+		return ""
+	}
+
+	var parts []string
+	for _, r := range *f.rules {
+		parts = append(parts, r.DeploymentName)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (f prometheusRuleFlag) Set(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return fmt.Errorf("expected <deployment>[:<component>] [<label>=<regexp>...], got %q", value)
+	}
+
+	name, component, _ := strings.Cut(fields[0], ":")
+
+	match, err := parseLabelPairs(fields[1:])
+	if err != nil {
+		return err
+	}
+
+	*f.rules = append(*f.rules, prometheus.Rule{
+		Match:          match,
+		DeploymentName: name,
+		Component:      component,
+	})
+	return nil
+}
+
+func parseLabelPairs(pairs []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, pair := range pairs {
+		label, value, ok := strings.Cut(pair, "=")
+		if !ok || label == "" {
+			return nil, fmt.Errorf("expected <label>=<value>, got %q", pair)
+		}
+		labels[label] = value
+	}
+	return labels, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"overseer/datasource/registry"
+	"overseer/runner"
+	"strings"
+	"time"
+)
+
+// registryFlags holds the flags configuring the discovery of the releases of the applications in their image registries.
+type registryFlags struct {
+	enabled      bool
+	config       registry.Config
+	interval     time.Duration
+	repositories map[string]string
+}
+
+func (f *registryFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.enabled, "registry", false, "discover the latest releases of the applications in the image repositories of their deployed images")
+	fs.Var(repositoryFlag{&f.repositories}, "registry-repo", "image repository of an application as <application>=<repository>, e.g. api=ghcr.io/acme/api, implies -registry, can be repeated")
+	fs.DurationVar(&f.interval, "registry-interval", runner.DefaultReleaseInterval, "how often the tags of the image repositories are listed")
+	fs.BoolVar(&f.config.PlainHTTP, "registry-plain-http", false, "connect to the registries over HTTP instead of HTTPS, like for a local registry")
+	fs.StringVar(&f.config.Username, "registry-username", "", "username for the registries, the password is read from OVERSEER_REGISTRY_PASSWORD")
+}
+
+// apply enables the release discovery of the config if it is configured.
+func (f *registryFlags) apply(config *runner.Config) {
+	if !f.enabled && len(f.repositories) == 0 {
+		return
+	}
+
+	// The password is only read from the environment to keep it out of the process list.
+	f.config.Password = os.Getenv("OVERSEER_REGISTRY_PASSWORD")
+
+	config.Registry = &f.config
+	config.ReleaseInterval = f.interval
+	config.Repositories = f.repositories
+}
+
+// repositoryFlag collects the -registry-repo flags.
+type repositoryFlag struct {
+	repositories *map[string]string
+}
+
+func (f repositoryFlag) String() string {
+	if f.repositories == nil {
+		return ""
+	}
+
+	var parts []string
+	for application, repository := range *f.repositories {
+		parts = append(parts, application+"="+repository)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (f repositoryFlag) Set(value string) error {
+	application, repository, ok := strings.Cut(value, "=")
+	if !ok || application == "" {
+		return fmt.Errorf("expected <application>=<repository>, got %q", value)
+	}
+	if _, _, err := registry.ParseRepository(repository); err != nil {
+		return err
+	}
+
+	if *f.repositories == nil {
+		*f.repositories = map[string]string{}
+	}
+	(*f.repositories)[application] = repository
+	return nil
+}
@@ -11,30 +11,242 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const deleteDeployments = `-- name: DeleteDeployments :execrows
+DELETE FROM deployments
+WHERE id = ANY($1::uuid[])
+  AND id NOT IN (SELECT deployment_id FROM current_deployments)
+`
+
+// Delete the given deployments, except those that are the current deployment of their instance.
+func (q *Queries) DeleteDeployments(ctx context.Context, ids []pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteDeployments, ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getCurrentDeployment = `-- name: GetCurrentDeployment :one
+SELECT d.id, d.instance_id, d.version, d.deployed_at, d.received_at, d.sequence, d.late, d.clock_skew, d.image, d.image_digest, d.git_commit, d.build_url, d.deployer, d.labels, d.component, d.undeployed, d.source, d.freeze_window
+FROM current_deployments c
+JOIN deployments d ON d.id = c.deployment_id
+WHERE c.instance_id = $1 AND c.component = $2
+FOR UPDATE OF c
+`
+
+type GetCurrentDeploymentParams struct {
+	InstanceID int32  `json:"instance_id"`
+	Component  string `json:"component"`
+}
+
+// The current deployment of the instance component, locked until the end of the transaction so that it is the one
+// UpdateCurrentDeployment replaces.
+func (q *Queries) GetCurrentDeployment(ctx context.Context, arg GetCurrentDeploymentParams) (Deployment, error) {
+	row := q.db.QueryRow(ctx, getCurrentDeployment, arg.InstanceID, arg.Component)
+	var i Deployment
+	err := row.Scan(
+		&i.ID,
+		&i.InstanceID,
+		&i.Version,
+		&i.DeployedAt,
+		&i.ReceivedAt,
+		&i.Sequence,
+		&i.Late,
+		&i.ClockSkew,
+		&i.Image,
+		&i.ImageDigest,
+		&i.GitCommit,
+		&i.BuildUrl,
+		&i.Deployer,
+		&i.Labels,
+		&i.Component,
+		&i.Undeployed,
+		&i.Source,
+		&i.FreezeWindow,
+	)
+	return i, err
+}
+
+const listCurrentDeployments = `-- name: ListCurrentDeployments :many
+SELECT d.id, d.instance_id, d.version, d.deployed_at, d.received_at, d.sequence, d.late, d.clock_skew, d.image, d.image_digest, d.git_commit, d.build_url, d.deployer, d.labels, d.component, d.undeployed, d.source, d.freeze_window
+FROM current_deployments c
+JOIN deployments d ON d.id = c.deployment_id
+ORDER BY c.instance_id, c.component
+`
+
+// The current deployment of every component of every instance.
+func (q *Queries) ListCurrentDeployments(ctx context.Context) ([]Deployment, error) {
+	rows, err := q.db.Query(ctx, listCurrentDeployments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Deployment
+	for rows.Next() {
+		var i Deployment
+		if err := rows.Scan(
+			&i.ID,
+			&i.InstanceID,
+			&i.Version,
+			&i.DeployedAt,
+			&i.ReceivedAt,
+			&i.Sequence,
+			&i.Late,
+			&i.ClockSkew,
+			&i.Image,
+			&i.ImageDigest,
+			&i.GitCommit,
+			&i.BuildUrl,
+			&i.Deployer,
+			&i.Labels,
+			&i.Component,
+			&i.Undeployed,
+			&i.Source,
+			&i.FreezeWindow,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeployedVersions = `-- name: ListDeployedVersions :many
+SELECT DISTINCT version
+FROM deployments
+WHERE instance_id = $1 AND component = $2 AND NOT undeployed
+ORDER BY version
+`
+
+type ListDeployedVersionsParams struct {
+	InstanceID int32  `json:"instance_id"`
+	Component  string `json:"component"`
+}
+
+// The versions deployed to a component of an instance, without the undeployments.
+func (q *Queries) ListDeployedVersions(ctx context.Context, arg ListDeployedVersionsParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listDeployedVersions, arg.InstanceID, arg.Component)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		items = append(items, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listDeployments = `-- name: ListDeployments :many
 SELECT
+  id,
   instance_id,
   version,
-  deployed_at
+  deployed_at,
+  received_at,
+  sequence,
+  late,
+  clock_skew,
+  image,
+  image_digest,
+  git_commit,
+  build_url,
+  deployer,
+  labels,
+  component,
+  undeployed,
+  source,
+  freeze_window
 FROM deployments
+ORDER BY instance_id, component, deployed_at
 `
 
-type ListDeploymentsRow struct {
-	InstanceID int32              `json:"instance_id"`
-	Version    string             `json:"version"`
-	DeployedAt pgtype.Timestamptz `json:"deployed_at"`
+func (q *Queries) ListDeployments(ctx context.Context) ([]Deployment, error) {
+	rows, err := q.db.Query(ctx, listDeployments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Deployment
+	for rows.Next() {
+		var i Deployment
+		if err := rows.Scan(
+			&i.ID,
+			&i.InstanceID,
+			&i.Version,
+			&i.DeployedAt,
+			&i.ReceivedAt,
+			&i.Sequence,
+			&i.Late,
+			&i.ClockSkew,
+			&i.Image,
+			&i.ImageDigest,
+			&i.GitCommit,
+			&i.BuildUrl,
+			&i.Deployer,
+			&i.Labels,
+			&i.Component,
+			&i.Undeployed,
+			&i.Source,
+			&i.FreezeWindow,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) ListDeployments(ctx context.Context) ([]ListDeploymentsRow, error) {
-	rows, err := q.db.Query(ctx, listDeployments)
+const listInstanceDeployments = `-- name: ListInstanceDeployments :many
+SELECT id, instance_id, version, deployed_at, received_at, sequence, late, clock_skew, image, image_digest, git_commit, build_url, deployer, labels, component, undeployed, source, freeze_window
+FROM deployments
+WHERE instance_id = $1
+ORDER BY component, deployed_at
+`
+
+// The history of an instance, a page of the deployment history small enough to be pruned at once.
+func (q *Queries) ListInstanceDeployments(ctx context.Context, instanceID int32) ([]Deployment, error) {
+	rows, err := q.db.Query(ctx, listInstanceDeployments, instanceID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListDeploymentsRow
+	var items []Deployment
 	for rows.Next() {
-		var i ListDeploymentsRow
-		if err := rows.Scan(&i.InstanceID, &i.Version, &i.DeployedAt); err != nil {
+		var i Deployment
+		if err := rows.Scan(
+			&i.ID,
+			&i.InstanceID,
+			&i.Version,
+			&i.DeployedAt,
+			&i.ReceivedAt,
+			&i.Sequence,
+			&i.Late,
+			&i.ClockSkew,
+			&i.Image,
+			&i.ImageDigest,
+			&i.GitCommit,
+			&i.BuildUrl,
+			&i.Deployer,
+			&i.Labels,
+			&i.Component,
+			&i.Undeployed,
+			&i.Source,
+			&i.FreezeWindow,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -45,16 +257,44 @@ func (q *Queries) ListDeployments(ctx context.Context) ([]ListDeploymentsRow, er
 	return items, nil
 }
 
+const markDeploymentLate = `-- name: MarkDeploymentLate :exec
+UPDATE deployments
+SET late = true
+WHERE id = $1
+`
+
+func (q *Queries) MarkDeploymentLate(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markDeploymentLate, id)
+	return err
+}
+
 const registerDeployment = `-- name: RegisterDeployment :exec
-INSERT INTO deployments (id, instance_id, version, deployed_at)
-VALUES ($1, $2, $3, $4)
+INSERT INTO deployments (
+  id, instance_id, version, deployed_at, received_at, sequence, clock_skew,
+  image, image_digest, git_commit, build_url, deployer, labels, component, undeployed, source, freeze_window
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7,
+  $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 `
 
 type RegisterDeploymentParams struct {
-	ID         pgtype.UUID        `json:"id"`
-	InstanceID int32              `json:"instance_id"`
-	Version    string             `json:"version"`
-	DeployedAt pgtype.Timestamptz `json:"deployed_at"`
+	ID           pgtype.UUID        `json:"id"`
+	InstanceID   int32              `json:"instance_id"`
+	Version      string             `json:"version"`
+	DeployedAt   pgtype.Timestamptz `json:"deployed_at"`
+	ReceivedAt   pgtype.Timestamptz `json:"received_at"`
+	Sequence     pgtype.Int8        `json:"sequence"`
+	ClockSkew    bool               `json:"clock_skew"`
+	Image        string             `json:"image"`
+	ImageDigest  string             `json:"image_digest"`
+	GitCommit    string             `json:"git_commit"`
+	BuildUrl     string             `json:"build_url"`
+	Deployer     string             `json:"deployer"`
+	Labels       []byte             `json:"labels"`
+	Component    string             `json:"component"`
+	Undeployed   bool               `json:"undeployed"`
+	Source       string             `json:"source"`
+	FreezeWindow string             `json:"freeze_window"`
 }
 
 // Register a deployment
@@ -64,6 +304,62 @@ func (q *Queries) RegisterDeployment(ctx context.Context, arg RegisterDeployment
 		arg.InstanceID,
 		arg.Version,
 		arg.DeployedAt,
+		arg.ReceivedAt,
+		arg.Sequence,
+		arg.ClockSkew,
+		arg.Image,
+		arg.ImageDigest,
+		arg.GitCommit,
+		arg.BuildUrl,
+		arg.Deployer,
+		arg.Labels,
+		arg.Component,
+		arg.Undeployed,
+		arg.Source,
+		arg.FreezeWindow,
 	)
 	return err
 }
+
+const updateCurrentDeployment = `-- name: UpdateCurrentDeployment :execrows
+INSERT INTO current_deployments (instance_id, component, deployment_id, ordered_at, sequence, source)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (instance_id, component) DO UPDATE
+SET deployment_id = EXCLUDED.deployment_id,
+    ordered_at = EXCLUDED.ordered_at,
+    sequence = EXCLUDED.sequence,
+    source = EXCLUDED.source
+WHERE CASE
+  WHEN current_deployments.sequence IS NOT NULL AND EXCLUDED.sequence IS NOT NULL
+    AND current_deployments.source = EXCLUDED.source
+    THEN current_deployments.sequence <= EXCLUDED.sequence
+  ELSE current_deployments.ordered_at <= EXCLUDED.ordered_at
+END
+`
+
+type UpdateCurrentDeploymentParams struct {
+	InstanceID   int32              `json:"instance_id"`
+	Component    string             `json:"component"`
+	DeploymentID pgtype.UUID        `json:"deployment_id"`
+	OrderedAt    pgtype.Timestamptz `json:"ordered_at"`
+	Sequence     pgtype.Int8        `json:"sequence"`
+	Source       string             `json:"source"`
+}
+
+// Make the deployment the current one of its instance component, unless a later one is already current.
+// Deployments are ordered by their sequence if both have one and are of the same source, otherwise by ordered_at.
+// No row is affected if the deployment is not made current.
+func (q *Queries) UpdateCurrentDeployment(ctx context.Context, arg UpdateCurrentDeploymentParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateCurrentDeployment,
+		arg.InstanceID,
+		arg.Component,
+		arg.DeploymentID,
+		arg.OrderedAt,
+		arg.Sequence,
+		arg.Source,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -88,20 +88,18 @@ func (q *Queries) ReorderEnvironments(ctx context.Context, dollar_1 []int32) err
 
 const updateEnvironment = `-- name: UpdateEnvironment :one
 UPDATE environments
-SET name = $2,
-    sort_order = $3
+SET name = $2
 WHERE id = $1
 RETURNING id, name, sort_order
 `
 
 type UpdateEnvironmentParams struct {
-	ID        int32  `json:"id"`
-	Name      string `json:"name"`
-	SortOrder int32  `json:"sort_order"`
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
 }
 
 func (q *Queries) UpdateEnvironment(ctx context.Context, arg UpdateEnvironmentParams) (Environment, error) {
-	row := q.db.QueryRow(ctx, updateEnvironment, arg.ID, arg.Name, arg.SortOrder)
+	row := q.db.QueryRow(ctx, updateEnvironment, arg.ID, arg.Name)
 	var i Environment
 	err := row.Scan(&i.ID, &i.Name, &i.SortOrder)
 	return i, err
@@ -12,20 +12,26 @@ import (
 )
 
 const createInstance = `-- name: CreateInstance :one
-INSERT INTO instances (environment_id, application_id, name)
-VALUES ($1, $2, $3)
+INSERT INTO instances (environment_id, application_id, name, primary_component)
+VALUES ($1, $2, $3, $4)
 RETURNING id
 `
 
 type CreateInstanceParams struct {
-	EnvironmentID int32  `json:"environment_id"`
-	ApplicationID int32  `json:"application_id"`
-	Name          string `json:"name"`
+	EnvironmentID    int32  `json:"environment_id"`
+	ApplicationID    int32  `json:"application_id"`
+	Name             string `json:"name"`
+	PrimaryComponent string `json:"primary_component"`
 }
 
 // Create an instance
 func (q *Queries) CreateInstance(ctx context.Context, arg CreateInstanceParams) (int32, error) {
-	row := q.db.QueryRow(ctx, createInstance, arg.EnvironmentID, arg.ApplicationID, arg.Name)
+	row := q.db.QueryRow(ctx, createInstance,
+		arg.EnvironmentID,
+		arg.ApplicationID,
+		arg.Name,
+		arg.PrimaryComponent,
+	)
 	var id int32
 	err := row.Scan(&id)
 	return id, err
@@ -46,16 +52,18 @@ SELECT
   i.id,
   i.environment_id,
   i.application_id,
-  i.name
+  i.name,
+  i.primary_component
 FROM instances i
 WHERE i.id = $1
 `
 
 type GetInstanceRow struct {
-	ID            int32  `json:"id"`
-	EnvironmentID int32  `json:"environment_id"`
-	ApplicationID int32  `json:"application_id"`
-	Name          string `json:"name"`
+	ID               int32  `json:"id"`
+	EnvironmentID    int32  `json:"environment_id"`
+	ApplicationID    int32  `json:"application_id"`
+	Name             string `json:"name"`
+	PrimaryComponent string `json:"primary_component"`
 }
 
 // SELECT
@@ -79,6 +87,7 @@ func (q *Queries) GetInstance(ctx context.Context, id int32) (GetInstanceRow, er
 		&i.EnvironmentID,
 		&i.ApplicationID,
 		&i.Name,
+		&i.PrimaryComponent,
 	)
 	return i, err
 }
@@ -88,16 +97,18 @@ SELECT
   id,
   environment_id,
   application_id,
-  name
+  name,
+  primary_component
 FROM instances
 WHERE name = $1 OR $1=''
 `
 
 type ListInstancesRow struct {
-	ID            int32  `json:"id"`
-	EnvironmentID int32  `json:"environment_id"`
-	ApplicationID int32  `json:"application_id"`
-	Name          string `json:"name"`
+	ID               int32  `json:"id"`
+	EnvironmentID    int32  `json:"environment_id"`
+	ApplicationID    int32  `json:"application_id"`
+	Name             string `json:"name"`
+	PrimaryComponent string `json:"primary_component"`
 }
 
 func (q *Queries) ListInstances(ctx context.Context, name string) ([]ListInstancesRow, error) {
@@ -114,6 +125,7 @@ func (q *Queries) ListInstances(ctx context.Context, name string) ([]ListInstanc
 			&i.EnvironmentID,
 			&i.ApplicationID,
 			&i.Name,
+			&i.PrimaryComponent,
 		); err != nil {
 			return nil, err
 		}
@@ -132,24 +144,55 @@ SELECT
   i.environment_id,
   i.application_id,
   i.name,
+  i.primary_component,
+  d.id AS deployment_id,
   d.version,
-  d.deployed_at
+  d.deployed_at,
+  d.received_at,
+  d.sequence,
+  d.late,
+  d.clock_skew,
+  d.image,
+  d.image_digest,
+  d.git_commit,
+  d.build_url,
+  d.deployer,
+  d.labels,
+  d.undeployed,
+  d.source,
+  d.freeze_window
 FROM instances i
-LEFT JOIN deployments d ON i.id = d.instance_id
-  AND d.deployed_at = (SELECT MAX(deployed_at) FROM deployments WHERE instance_id = i.id)
+LEFT JOIN current_deployments c ON c.instance_id = i.id AND c.component = i.primary_component
+LEFT JOIN deployments d ON d.id = c.deployment_id
+ORDER BY i.id
 `
 
 type ListInstancesAndDeploymentRow struct {
-	ID            int32              `json:"id"`
-	EnvironmentID int32              `json:"environment_id"`
-	ApplicationID int32              `json:"application_id"`
-	Name          string             `json:"name"`
-	Version       pgtype.Text        `json:"version"`
-	DeployedAt    pgtype.Timestamptz `json:"deployed_at"`
+	ID               int32              `json:"id"`
+	EnvironmentID    int32              `json:"environment_id"`
+	ApplicationID    int32              `json:"application_id"`
+	Name             string             `json:"name"`
+	PrimaryComponent string             `json:"primary_component"`
+	DeploymentID     pgtype.UUID        `json:"deployment_id"`
+	Version          pgtype.Text        `json:"version"`
+	DeployedAt       pgtype.Timestamptz `json:"deployed_at"`
+	ReceivedAt       pgtype.Timestamptz `json:"received_at"`
+	Sequence         pgtype.Int8        `json:"sequence"`
+	Late             pgtype.Bool        `json:"late"`
+	ClockSkew        pgtype.Bool        `json:"clock_skew"`
+	Image            pgtype.Text        `json:"image"`
+	ImageDigest      pgtype.Text        `json:"image_digest"`
+	GitCommit        pgtype.Text        `json:"git_commit"`
+	BuildUrl         pgtype.Text        `json:"build_url"`
+	Deployer         pgtype.Text        `json:"deployer"`
+	Labels           []byte             `json:"labels"`
+	Undeployed       pgtype.Bool        `json:"undeployed"`
+	Source           pgtype.Text        `json:"source"`
+	FreezeWindow     pgtype.Text        `json:"freeze_window"`
 }
 
 // filter by name if provided
-// List instances along with their latest deployments
+// List instances along with the current deployment of their primary component
 func (q *Queries) ListInstancesAndDeployment(ctx context.Context) ([]ListInstancesAndDeploymentRow, error) {
 	rows, err := q.db.Query(ctx, listInstancesAndDeployment)
 	if err != nil {
@@ -164,8 +207,23 @@ func (q *Queries) ListInstancesAndDeployment(ctx context.Context) ([]ListInstanc
 			&i.EnvironmentID,
 			&i.ApplicationID,
 			&i.Name,
+			&i.PrimaryComponent,
+			&i.DeploymentID,
 			&i.Version,
 			&i.DeployedAt,
+			&i.ReceivedAt,
+			&i.Sequence,
+			&i.Late,
+			&i.ClockSkew,
+			&i.Image,
+			&i.ImageDigest,
+			&i.GitCommit,
+			&i.BuildUrl,
+			&i.Deployer,
+			&i.Labels,
+			&i.Undeployed,
+			&i.Source,
+			&i.FreezeWindow,
 		); err != nil {
 			return nil, err
 		}
@@ -177,18 +235,23 @@ func (q *Queries) ListInstancesAndDeployment(ctx context.Context) ([]ListInstanc
 	return items, nil
 }
 
-const updateInstance = `-- name: UpdateInstance :exec
+const updateInstance = `-- name: UpdateInstance :execrows
 UPDATE instances
-SET name = $2
+SET name = $2,
+    primary_component = $3
 WHERE id = $1
 `
 
 type UpdateInstanceParams struct {
-	ID   int32  `json:"id"`
-	Name string `json:"name"`
+	ID               int32  `json:"id"`
+	Name             string `json:"name"`
+	PrimaryComponent string `json:"primary_component"`
 }
 
-func (q *Queries) UpdateInstance(ctx context.Context, arg UpdateInstanceParams) error {
-	_, err := q.db.Exec(ctx, updateInstance, arg.ID, arg.Name)
-	return err
+func (q *Queries) UpdateInstance(ctx context.Context, arg UpdateInstanceParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateInstance, arg.ID, arg.Name, arg.PrimaryComponent)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
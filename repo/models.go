@@ -14,11 +14,42 @@ type Application struct {
 	SortOrder int32  `json:"sort_order"`
 }
 
+type ApplicationPolicy struct {
+	ApplicationID      int32  `json:"application_id"`
+	Pinned             string `json:"pinned"`
+	Allowed            string `json:"allowed"`
+	Forbidden          []byte `json:"forbidden"`
+	NotAheadOfUpstream bool   `json:"not_ahead_of_upstream"`
+}
+
+type CurrentDeployment struct {
+	InstanceID   int32              `json:"instance_id"`
+	DeploymentID pgtype.UUID        `json:"deployment_id"`
+	OrderedAt    pgtype.Timestamptz `json:"ordered_at"`
+	Sequence     pgtype.Int8        `json:"sequence"`
+	Component    string             `json:"component"`
+	Source       string             `json:"source"`
+}
+
 type Deployment struct {
-	ID         pgtype.UUID        `json:"id"`
-	InstanceID int32              `json:"instance_id"`
-	Version    string             `json:"version"`
-	DeployedAt pgtype.Timestamptz `json:"deployed_at"`
+	ID           pgtype.UUID        `json:"id"`
+	InstanceID   int32              `json:"instance_id"`
+	Version      string             `json:"version"`
+	DeployedAt   pgtype.Timestamptz `json:"deployed_at"`
+	ReceivedAt   pgtype.Timestamptz `json:"received_at"`
+	Sequence     pgtype.Int8        `json:"sequence"`
+	Late         bool               `json:"late"`
+	ClockSkew    bool               `json:"clock_skew"`
+	Image        string             `json:"image"`
+	ImageDigest  string             `json:"image_digest"`
+	GitCommit    string             `json:"git_commit"`
+	BuildUrl     string             `json:"build_url"`
+	Deployer     string             `json:"deployer"`
+	Labels       []byte             `json:"labels"`
+	Component    string             `json:"component"`
+	Undeployed   bool               `json:"undeployed"`
+	Source       string             `json:"source"`
+	FreezeWindow string             `json:"freeze_window"`
 }
 
 type Environment struct {
@@ -27,9 +58,113 @@ type Environment struct {
 	SortOrder int32  `json:"sort_order"`
 }
 
+type FreezeWindow struct {
+	ID              int32              `json:"id"`
+	Name            string             `json:"name"`
+	Reason          string             `json:"reason"`
+	EnvironmentID   pgtype.Int4        `json:"environment_id"`
+	ApplicationID   pgtype.Int4        `json:"application_id"`
+	StartsAt        pgtype.Timestamptz `json:"starts_at"`
+	EndsAt          pgtype.Timestamptz `json:"ends_at"`
+	Schedule        string             `json:"schedule"`
+	DurationSeconds int64              `json:"duration_seconds"`
+	TimeZone        string             `json:"time_zone"`
+	Reject          bool               `json:"reject"`
+}
+
 type Instance struct {
-	ID            int32  `json:"id"`
-	Name          string `json:"name"`
-	EnvironmentID int32  `json:"environment_id"`
-	ApplicationID int32  `json:"application_id"`
+	ID               int32  `json:"id"`
+	Name             string `json:"name"`
+	EnvironmentID    int32  `json:"environment_id"`
+	ApplicationID    int32  `json:"application_id"`
+	PrimaryComponent string `json:"primary_component"`
+}
+
+type InstancePolicy struct {
+	InstanceID         int32  `json:"instance_id"`
+	Pinned             string `json:"pinned"`
+	Allowed            string `json:"allowed"`
+	Forbidden          []byte `json:"forbidden"`
+	NotAheadOfUpstream bool   `json:"not_ahead_of_upstream"`
+}
+
+type NotificationChannel struct {
+	ID           int32  `json:"id"`
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	Url          string `json:"url"`
+	Template     string `json:"template"`
+	BatchSeconds int32  `json:"batch_seconds"`
+	Enabled      bool   `json:"enabled"`
+}
+
+type NotificationRoute struct {
+	ID            int32       `json:"id"`
+	ChannelID     int32       `json:"channel_id"`
+	EnvironmentID pgtype.Int4 `json:"environment_id"`
+	ApplicationID pgtype.Int4 `json:"application_id"`
+	Events        []byte      `json:"events"`
+	Redeploys     bool        `json:"redeploys"`
+}
+
+type PolicyViolation struct {
+	DeploymentID pgtype.UUID        `json:"deployment_id"`
+	InstanceID   int32              `json:"instance_id"`
+	Version      string             `json:"version"`
+	Rule         string             `json:"rule"`
+	Message      string             `json:"message"`
+	DetectedAt   pgtype.Timestamptz `json:"detected_at"`
+}
+
+type Promotion struct {
+	FromEnvironmentID int32 `json:"from_environment_id"`
+	ToEnvironmentID   int32 `json:"to_environment_id"`
+}
+
+type Release struct {
+	ApplicationID int32              `json:"application_id"`
+	Repository    string             `json:"repository"`
+	Version       string             `json:"version"`
+	CheckedAt     pgtype.Timestamptz `json:"checked_at"`
+}
+
+type Rollout struct {
+	InstanceID     int32              `json:"instance_id"`
+	Component      string             `json:"component"`
+	Version        string             `json:"version"`
+	Status         string             `json:"status"`
+	Description    string             `json:"description"`
+	Healthy        int32              `json:"healthy"`
+	Desired        int32              `json:"desired"`
+	RunningVersion string             `json:"running_version"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	Sequence       pgtype.Int8        `json:"sequence"`
+	Source         string             `json:"source"`
+}
+
+type Webhook struct {
+	ID            int32              `json:"id"`
+	Name          string             `json:"name"`
+	Url           string             `json:"url"`
+	Secret        string             `json:"secret"`
+	Events        []byte             `json:"events"`
+	EnvironmentID pgtype.Int4        `json:"environment_id"`
+	ApplicationID pgtype.Int4        `json:"application_id"`
+	Enabled       bool               `json:"enabled"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             pgtype.UUID        `json:"id"`
+	WebhookID      int32              `json:"webhook_id"`
+	EventID        pgtype.UUID        `json:"event_id"`
+	Event          string             `json:"event"`
+	Payload        string             `json:"payload"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	ResponseStatus int32              `json:"response_status"`
+	Error          string             `json:"error"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	NextAttemptAt  pgtype.Timestamptz `json:"next_attempt_at"`
+	LastAttemptAt  pgtype.Timestamptz `json:"last_attempt_at"`
 }
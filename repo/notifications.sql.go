@@ -0,0 +1,189 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: notifications.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addNotificationRoute = `-- name: AddNotificationRoute :exec
+INSERT INTO notification_routes (channel_id, environment_id, application_id, events, redeploys)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type AddNotificationRouteParams struct {
+	ChannelID     int32       `json:"channel_id"`
+	EnvironmentID pgtype.Int4 `json:"environment_id"`
+	ApplicationID pgtype.Int4 `json:"application_id"`
+	Events        []byte      `json:"events"`
+	Redeploys     bool        `json:"redeploys"`
+}
+
+func (q *Queries) AddNotificationRoute(ctx context.Context, arg AddNotificationRouteParams) error {
+	_, err := q.db.Exec(ctx, addNotificationRoute,
+		arg.ChannelID,
+		arg.EnvironmentID,
+		arg.ApplicationID,
+		arg.Events,
+		arg.Redeploys,
+	)
+	return err
+}
+
+const createNotificationChannel = `-- name: CreateNotificationChannel :one
+INSERT INTO notification_channels (name, kind, url, template, batch_seconds, enabled)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id
+`
+
+type CreateNotificationChannelParams struct {
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	Url          string `json:"url"`
+	Template     string `json:"template"`
+	BatchSeconds int32  `json:"batch_seconds"`
+	Enabled      bool   `json:"enabled"`
+}
+
+func (q *Queries) CreateNotificationChannel(ctx context.Context, arg CreateNotificationChannelParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createNotificationChannel,
+		arg.Name,
+		arg.Kind,
+		arg.Url,
+		arg.Template,
+		arg.BatchSeconds,
+		arg.Enabled,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteNotificationChannel = `-- name: DeleteNotificationChannel :exec
+DELETE FROM notification_channels
+WHERE id = $1
+`
+
+func (q *Queries) DeleteNotificationChannel(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, deleteNotificationChannel, id)
+	return err
+}
+
+const deleteNotificationRoutes = `-- name: DeleteNotificationRoutes :exec
+DELETE FROM notification_routes
+WHERE channel_id = $1
+`
+
+func (q *Queries) DeleteNotificationRoutes(ctx context.Context, channelID int32) error {
+	_, err := q.db.Exec(ctx, deleteNotificationRoutes, channelID)
+	return err
+}
+
+const listNotificationChannels = `-- name: ListNotificationChannels :many
+SELECT id, name, kind, url, template, batch_seconds, enabled
+FROM notification_channels
+ORDER BY id
+`
+
+func (q *Queries) ListNotificationChannels(ctx context.Context) ([]NotificationChannel, error) {
+	rows, err := q.db.Query(ctx, listNotificationChannels)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationChannel
+	for rows.Next() {
+		var i NotificationChannel
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Kind,
+			&i.Url,
+			&i.Template,
+			&i.BatchSeconds,
+			&i.Enabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationRoutes = `-- name: ListNotificationRoutes :many
+SELECT id, channel_id, environment_id, application_id, events, redeploys
+FROM notification_routes
+ORDER BY channel_id, id
+`
+
+func (q *Queries) ListNotificationRoutes(ctx context.Context) ([]NotificationRoute, error) {
+	rows, err := q.db.Query(ctx, listNotificationRoutes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationRoute
+	for rows.Next() {
+		var i NotificationRoute
+		if err := rows.Scan(
+			&i.ID,
+			&i.ChannelID,
+			&i.EnvironmentID,
+			&i.ApplicationID,
+			&i.Events,
+			&i.Redeploys,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateNotificationChannel = `-- name: UpdateNotificationChannel :execrows
+UPDATE notification_channels
+SET name = $2,
+    kind = $3,
+    url = $4,
+    template = $5,
+    batch_seconds = $6,
+    enabled = $7
+WHERE id = $1
+`
+
+type UpdateNotificationChannelParams struct {
+	ID           int32  `json:"id"`
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	Url          string `json:"url"`
+	Template     string `json:"template"`
+	BatchSeconds int32  `json:"batch_seconds"`
+	Enabled      bool   `json:"enabled"`
+}
+
+func (q *Queries) UpdateNotificationChannel(ctx context.Context, arg UpdateNotificationChannelParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateNotificationChannel,
+		arg.ID,
+		arg.Name,
+		arg.Kind,
+		arg.Url,
+		arg.Template,
+		arg.BatchSeconds,
+		arg.Enabled,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,62 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: promotions.sql
+
+package repo
+
+import (
+	"context"
+)
+
+const addPromotion = `-- name: AddPromotion :exec
+INSERT INTO promotions (from_environment_id, to_environment_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type AddPromotionParams struct {
+	FromEnvironmentID int32 `json:"from_environment_id"`
+	ToEnvironmentID   int32 `json:"to_environment_id"`
+}
+
+func (q *Queries) AddPromotion(ctx context.Context, arg AddPromotionParams) error {
+	_, err := q.db.Exec(ctx, addPromotion, arg.FromEnvironmentID, arg.ToEnvironmentID)
+	return err
+}
+
+const deletePromotionsTo = `-- name: DeletePromotionsTo :exec
+DELETE FROM promotions
+WHERE to_environment_id = $1
+`
+
+func (q *Queries) DeletePromotionsTo(ctx context.Context, toEnvironmentID int32) error {
+	_, err := q.db.Exec(ctx, deletePromotionsTo, toEnvironmentID)
+	return err
+}
+
+const listPromotions = `-- name: ListPromotions :many
+SELECT from_environment_id, to_environment_id
+FROM promotions
+ORDER BY from_environment_id, to_environment_id
+`
+
+func (q *Queries) ListPromotions(ctx context.Context) ([]Promotion, error) {
+	rows, err := q.db.Query(ctx, listPromotions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Promotion
+	for rows.Next() {
+		var i Promotion
+		if err := rows.Scan(&i.FromEnvironmentID, &i.ToEnvironmentID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,69 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: releases.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const listReleases = `-- name: ListReleases :many
+SELECT application_id, repository, version, checked_at
+FROM releases
+ORDER BY application_id
+`
+
+func (q *Queries) ListReleases(ctx context.Context) ([]Release, error) {
+	rows, err := q.db.Query(ctx, listReleases)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Release
+	for rows.Next() {
+		var i Release
+		if err := rows.Scan(
+			&i.ApplicationID,
+			&i.Repository,
+			&i.Version,
+			&i.CheckedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const saveRelease = `-- name: SaveRelease :exec
+INSERT INTO releases (application_id, repository, version, checked_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (application_id) DO UPDATE
+SET repository = EXCLUDED.repository,
+    version = EXCLUDED.version,
+    checked_at = EXCLUDED.checked_at
+`
+
+type SaveReleaseParams struct {
+	ApplicationID int32              `json:"application_id"`
+	Repository    string             `json:"repository"`
+	Version       string             `json:"version"`
+	CheckedAt     pgtype.Timestamptz `json:"checked_at"`
+}
+
+func (q *Queries) SaveRelease(ctx context.Context, arg SaveReleaseParams) error {
+	_, err := q.db.Exec(ctx, saveRelease,
+		arg.ApplicationID,
+		arg.Repository,
+		arg.Version,
+		arg.CheckedAt,
+	)
+	return err
+}
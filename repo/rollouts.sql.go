@@ -0,0 +1,114 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: rollouts.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const listRollouts = `-- name: ListRollouts :many
+SELECT instance_id, component, version, status, description, healthy, desired, running_version, updated_at, sequence, source
+FROM rollouts
+ORDER BY instance_id, component
+`
+
+func (q *Queries) ListRollouts(ctx context.Context) ([]Rollout, error) {
+	rows, err := q.db.Query(ctx, listRollouts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Rollout
+	for rows.Next() {
+		var i Rollout
+		if err := rows.Scan(
+			&i.InstanceID,
+			&i.Component,
+			&i.Version,
+			&i.Status,
+			&i.Description,
+			&i.Healthy,
+			&i.Desired,
+			&i.RunningVersion,
+			&i.UpdatedAt,
+			&i.Sequence,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateRollout = `-- name: UpdateRollout :execrows
+INSERT INTO rollouts (
+  instance_id, component, version, status, description,
+  healthy, desired, running_version, updated_at, sequence, source
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (instance_id, component) DO UPDATE
+SET version = EXCLUDED.version,
+    status = EXCLUDED.status,
+    description = EXCLUDED.description,
+    healthy = EXCLUDED.healthy,
+    desired = EXCLUDED.desired,
+    running_version = CASE
+      WHEN EXCLUDED.running_version <> '' THEN EXCLUDED.running_version
+      ELSE rollouts.running_version
+    END,
+    updated_at = EXCLUDED.updated_at,
+    sequence = EXCLUDED.sequence,
+    source = EXCLUDED.source
+WHERE CASE
+  WHEN rollouts.sequence IS NOT NULL AND EXCLUDED.sequence IS NOT NULL
+    AND rollouts.source = EXCLUDED.source
+    THEN rollouts.sequence <= EXCLUDED.sequence
+  ELSE rollouts.updated_at <= EXCLUDED.updated_at
+END
+`
+
+type UpdateRolloutParams struct {
+	InstanceID     int32              `json:"instance_id"`
+	Component      string             `json:"component"`
+	Version        string             `json:"version"`
+	Status         string             `json:"status"`
+	Description    string             `json:"description"`
+	Healthy        int32              `json:"healthy"`
+	Desired        int32              `json:"desired"`
+	RunningVersion string             `json:"running_version"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	Sequence       pgtype.Int8        `json:"sequence"`
+	Source         string             `json:"source"`
+}
+
+// Replace the rollout of the instance component, unless a later one is already stored.
+// Rollouts are ordered by their sequence if both have one and are of the same source, otherwise by updated_at.
+// No row is affected if the rollout is not replaced.
+func (q *Queries) UpdateRollout(ctx context.Context, arg UpdateRolloutParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateRollout,
+		arg.InstanceID,
+		arg.Component,
+		arg.Version,
+		arg.Status,
+		arg.Description,
+		arg.Healthy,
+		arg.Desired,
+		arg.RunningVersion,
+		arg.UpdatedAt,
+		arg.Sequence,
+		arg.Source,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
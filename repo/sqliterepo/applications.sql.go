@@ -0,0 +1,112 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: applications.sql
+
+package sqliterepo
+
+import (
+	"context"
+)
+
+const createApplication = `-- name: CreateApplication :one
+INSERT INTO applications (name, sort_order)
+VALUES (?1,
+        COALESCE((SELECT MAX(sort_order) + 1 FROM applications), 1))
+RETURNING id, name, sort_order
+`
+
+func (q *Queries) CreateApplication(ctx context.Context, name string) (Application, error) {
+	row := q.db.QueryRowContext(ctx, createApplication, name)
+	var i Application
+	err := row.Scan(&i.ID, &i.Name, &i.SortOrder)
+	return i, err
+}
+
+const deleteApplication = `-- name: DeleteApplication :exec
+DELETE FROM applications
+WHERE id = ?1
+`
+
+func (q *Queries) DeleteApplication(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteApplication, id)
+	return err
+}
+
+const getApplication = `-- name: GetApplication :one
+SELECT id, name, sort_order
+FROM applications
+WHERE id = ?1
+`
+
+func (q *Queries) GetApplication(ctx context.Context, id int64) (Application, error) {
+	row := q.db.QueryRowContext(ctx, getApplication, id)
+	var i Application
+	err := row.Scan(&i.ID, &i.Name, &i.SortOrder)
+	return i, err
+}
+
+const listApplications = `-- name: ListApplications :many
+SELECT id, name, sort_order
+FROM applications
+ORDER BY sort_order, id
+`
+
+func (q *Queries) ListApplications(ctx context.Context) ([]Application, error) {
+	rows, err := q.db.QueryContext(ctx, listApplications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Application
+	for rows.Next() {
+		var i Application
+		if err := rows.Scan(&i.ID, &i.Name, &i.SortOrder); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setApplicationSortOrder = `-- name: SetApplicationSortOrder :exec
+UPDATE applications
+SET sort_order = ?2
+WHERE id = ?1
+`
+
+type SetApplicationSortOrderParams struct {
+	ID        int64 `json:"id"`
+	SortOrder int64 `json:"sort_order"`
+}
+
+// SQLite has no UNNEST, the store reorders by running this for every id in a transaction.
+func (q *Queries) SetApplicationSortOrder(ctx context.Context, arg SetApplicationSortOrderParams) error {
+	_, err := q.db.ExecContext(ctx, setApplicationSortOrder, arg.ID, arg.SortOrder)
+	return err
+}
+
+const updateApplication = `-- name: UpdateApplication :one
+UPDATE applications
+SET name = ?2
+WHERE id = ?1
+RETURNING id, name, sort_order
+`
+
+type UpdateApplicationParams struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func (q *Queries) UpdateApplication(ctx context.Context, arg UpdateApplicationParams) (Application, error) {
+	row := q.db.QueryRowContext(ctx, updateApplication, arg.ID, arg.Name)
+	var i Application
+	err := row.Scan(&i.ID, &i.Name, &i.SortOrder)
+	return i, err
+}
@@ -0,0 +1,112 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: environments.sql
+
+package sqliterepo
+
+import (
+	"context"
+)
+
+const createEnvironment = `-- name: CreateEnvironment :one
+INSERT INTO environments (name, sort_order)
+VALUES (?1,
+        COALESCE((SELECT MAX(sort_order) + 1 FROM environments), 1))
+RETURNING id, name, sort_order
+`
+
+func (q *Queries) CreateEnvironment(ctx context.Context, name string) (Environment, error) {
+	row := q.db.QueryRowContext(ctx, createEnvironment, name)
+	var i Environment
+	err := row.Scan(&i.ID, &i.Name, &i.SortOrder)
+	return i, err
+}
+
+const deleteEnvironment = `-- name: DeleteEnvironment :exec
+DELETE FROM environments
+WHERE id = ?1
+`
+
+func (q *Queries) DeleteEnvironment(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteEnvironment, id)
+	return err
+}
+
+const getEnvironment = `-- name: GetEnvironment :one
+SELECT id, name, sort_order
+FROM environments
+WHERE id = ?1
+`
+
+func (q *Queries) GetEnvironment(ctx context.Context, id int64) (Environment, error) {
+	row := q.db.QueryRowContext(ctx, getEnvironment, id)
+	var i Environment
+	err := row.Scan(&i.ID, &i.Name, &i.SortOrder)
+	return i, err
+}
+
+const listEnvironments = `-- name: ListEnvironments :many
+SELECT id, name, sort_order
+FROM environments
+ORDER BY sort_order, id
+`
+
+func (q *Queries) ListEnvironments(ctx context.Context) ([]Environment, error) {
+	rows, err := q.db.QueryContext(ctx, listEnvironments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Environment
+	for rows.Next() {
+		var i Environment
+		if err := rows.Scan(&i.ID, &i.Name, &i.SortOrder); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setEnvironmentSortOrder = `-- name: SetEnvironmentSortOrder :exec
+UPDATE environments
+SET sort_order = ?2
+WHERE id = ?1
+`
+
+type SetEnvironmentSortOrderParams struct {
+	ID        int64 `json:"id"`
+	SortOrder int64 `json:"sort_order"`
+}
+
+// SQLite has no UNNEST, the store reorders by running this for every id in a transaction.
+func (q *Queries) SetEnvironmentSortOrder(ctx context.Context, arg SetEnvironmentSortOrderParams) error {
+	_, err := q.db.ExecContext(ctx, setEnvironmentSortOrder, arg.ID, arg.SortOrder)
+	return err
+}
+
+const updateEnvironment = `-- name: UpdateEnvironment :one
+UPDATE environments
+SET name = ?2
+WHERE id = ?1
+RETURNING id, name, sort_order
+`
+
+type UpdateEnvironmentParams struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func (q *Queries) UpdateEnvironment(ctx context.Context, arg UpdateEnvironmentParams) (Environment, error) {
+	row := q.db.QueryRowContext(ctx, updateEnvironment, arg.ID, arg.Name)
+	var i Environment
+	err := row.Scan(&i.ID, &i.Name, &i.SortOrder)
+	return i, err
+}
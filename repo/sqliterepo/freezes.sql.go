@@ -0,0 +1,150 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: freezes.sql
+
+package sqliterepo
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createFreezeWindow = `-- name: CreateFreezeWindow :one
+INSERT INTO freeze_windows (
+  name, reason, environment_id, application_id, starts_at, ends_at, schedule, duration_seconds, time_zone, reject
+)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10)
+RETURNING id
+`
+
+type CreateFreezeWindowParams struct {
+	Name            string         `json:"name"`
+	Reason          string         `json:"reason"`
+	EnvironmentID   sql.NullInt64  `json:"environment_id"`
+	ApplicationID   sql.NullInt64  `json:"application_id"`
+	StartsAt        sql.NullString `json:"starts_at"`
+	EndsAt          sql.NullString `json:"ends_at"`
+	Schedule        string         `json:"schedule"`
+	DurationSeconds int64          `json:"duration_seconds"`
+	TimeZone        string         `json:"time_zone"`
+	Reject          bool           `json:"reject"`
+}
+
+func (q *Queries) CreateFreezeWindow(ctx context.Context, arg CreateFreezeWindowParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createFreezeWindow,
+		arg.Name,
+		arg.Reason,
+		arg.EnvironmentID,
+		arg.ApplicationID,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.Schedule,
+		arg.DurationSeconds,
+		arg.TimeZone,
+		arg.Reject,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteFreezeWindow = `-- name: DeleteFreezeWindow :exec
+DELETE FROM freeze_windows
+WHERE id = ?1
+`
+
+func (q *Queries) DeleteFreezeWindow(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteFreezeWindow, id)
+	return err
+}
+
+const listFreezeWindows = `-- name: ListFreezeWindows :many
+SELECT id, name, reason, environment_id, application_id, starts_at, ends_at, schedule, duration_seconds, time_zone, reject
+FROM freeze_windows
+ORDER BY id
+`
+
+func (q *Queries) ListFreezeWindows(ctx context.Context) ([]FreezeWindow, error) {
+	rows, err := q.db.QueryContext(ctx, listFreezeWindows)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FreezeWindow
+	for rows.Next() {
+		var i FreezeWindow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Reason,
+			&i.EnvironmentID,
+			&i.ApplicationID,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Schedule,
+			&i.DurationSeconds,
+			&i.TimeZone,
+			&i.Reject,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateFreezeWindow = `-- name: UpdateFreezeWindow :execrows
+UPDATE freeze_windows
+SET name = ?2,
+    reason = ?3,
+    environment_id = ?4,
+    application_id = ?5,
+    starts_at = ?6,
+    ends_at = ?7,
+    schedule = ?8,
+    duration_seconds = ?9,
+    time_zone = ?10,
+    reject = ?11
+WHERE id = ?1
+`
+
+type UpdateFreezeWindowParams struct {
+	ID              int64          `json:"id"`
+	Name            string         `json:"name"`
+	Reason          string         `json:"reason"`
+	EnvironmentID   sql.NullInt64  `json:"environment_id"`
+	ApplicationID   sql.NullInt64  `json:"application_id"`
+	StartsAt        sql.NullString `json:"starts_at"`
+	EndsAt          sql.NullString `json:"ends_at"`
+	Schedule        string         `json:"schedule"`
+	DurationSeconds int64          `json:"duration_seconds"`
+	TimeZone        string         `json:"time_zone"`
+	Reject          bool           `json:"reject"`
+}
+
+func (q *Queries) UpdateFreezeWindow(ctx context.Context, arg UpdateFreezeWindowParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateFreezeWindow,
+		arg.ID,
+		arg.Name,
+		arg.Reason,
+		arg.EnvironmentID,
+		arg.ApplicationID,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.Schedule,
+		arg.DurationSeconds,
+		arg.TimeZone,
+		arg.Reject,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,246 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: instance.sql
+
+package sqliterepo
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createInstance = `-- name: CreateInstance :one
+INSERT INTO instances (environment_id, application_id, name, primary_component)
+VALUES (?1, ?2, ?3, ?4)
+RETURNING id
+`
+
+type CreateInstanceParams struct {
+	EnvironmentID    int64  `json:"environment_id"`
+	ApplicationID    int64  `json:"application_id"`
+	Name             string `json:"name"`
+	PrimaryComponent string `json:"primary_component"`
+}
+
+func (q *Queries) CreateInstance(ctx context.Context, arg CreateInstanceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createInstance,
+		arg.EnvironmentID,
+		arg.ApplicationID,
+		arg.Name,
+		arg.PrimaryComponent,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteInstance = `-- name: DeleteInstance :exec
+DELETE FROM instances
+WHERE id = ?1
+`
+
+func (q *Queries) DeleteInstance(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteInstance, id)
+	return err
+}
+
+const getInstance = `-- name: GetInstance :one
+SELECT
+  i.id,
+  i.environment_id,
+  i.application_id,
+  i.name,
+  i.primary_component
+FROM instances i
+WHERE i.id = ?1
+`
+
+type GetInstanceRow struct {
+	ID               int64  `json:"id"`
+	EnvironmentID    int64  `json:"environment_id"`
+	ApplicationID    int64  `json:"application_id"`
+	Name             string `json:"name"`
+	PrimaryComponent string `json:"primary_component"`
+}
+
+func (q *Queries) GetInstance(ctx context.Context, id int64) (GetInstanceRow, error) {
+	row := q.db.QueryRowContext(ctx, getInstance, id)
+	var i GetInstanceRow
+	err := row.Scan(
+		&i.ID,
+		&i.EnvironmentID,
+		&i.ApplicationID,
+		&i.Name,
+		&i.PrimaryComponent,
+	)
+	return i, err
+}
+
+const listInstances = `-- name: ListInstances :many
+SELECT
+  id,
+  environment_id,
+  application_id,
+  name,
+  primary_component
+FROM instances
+WHERE name = ?1 OR ?1 = ''
+`
+
+type ListInstancesRow struct {
+	ID               int64  `json:"id"`
+	EnvironmentID    int64  `json:"environment_id"`
+	ApplicationID    int64  `json:"application_id"`
+	Name             string `json:"name"`
+	PrimaryComponent string `json:"primary_component"`
+}
+
+func (q *Queries) ListInstances(ctx context.Context, name string) ([]ListInstancesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInstances, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesRow
+	for rows.Next() {
+		var i ListInstancesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EnvironmentID,
+			&i.ApplicationID,
+			&i.Name,
+			&i.PrimaryComponent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesAndDeployment = `-- name: ListInstancesAndDeployment :many
+SELECT
+  i.id,
+  i.environment_id,
+  i.application_id,
+  i.name,
+  i.primary_component,
+  d.id AS deployment_id,
+  d.version,
+  d.deployed_at,
+  d.received_at,
+  d.sequence,
+  d.late,
+  d.clock_skew,
+  d.image,
+  d.image_digest,
+  d.git_commit,
+  d.build_url,
+  d.deployer,
+  d.labels,
+  d.undeployed,
+  d.source,
+  d.freeze_window
+FROM instances i
+LEFT JOIN current_deployments c ON c.instance_id = i.id AND c.component = i.primary_component
+LEFT JOIN deployments d ON d.id = c.deployment_id
+ORDER BY i.id
+`
+
+type ListInstancesAndDeploymentRow struct {
+	ID               int64          `json:"id"`
+	EnvironmentID    int64          `json:"environment_id"`
+	ApplicationID    int64          `json:"application_id"`
+	Name             string         `json:"name"`
+	PrimaryComponent string         `json:"primary_component"`
+	DeploymentID     sql.NullString `json:"deployment_id"`
+	Version          sql.NullString `json:"version"`
+	DeployedAt       sql.NullString `json:"deployed_at"`
+	ReceivedAt       sql.NullString `json:"received_at"`
+	Sequence         sql.NullInt64  `json:"sequence"`
+	Late             sql.NullBool   `json:"late"`
+	ClockSkew        sql.NullBool   `json:"clock_skew"`
+	Image            sql.NullString `json:"image"`
+	ImageDigest      sql.NullString `json:"image_digest"`
+	GitCommit        sql.NullString `json:"git_commit"`
+	BuildUrl         sql.NullString `json:"build_url"`
+	Deployer         sql.NullString `json:"deployer"`
+	Labels           sql.NullString `json:"labels"`
+	Undeployed       sql.NullBool   `json:"undeployed"`
+	Source           sql.NullString `json:"source"`
+	FreezeWindow     sql.NullString `json:"freeze_window"`
+}
+
+// List instances along with the current deployment of their primary component
+func (q *Queries) ListInstancesAndDeployment(ctx context.Context) ([]ListInstancesAndDeploymentRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInstancesAndDeployment)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesAndDeploymentRow
+	for rows.Next() {
+		var i ListInstancesAndDeploymentRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EnvironmentID,
+			&i.ApplicationID,
+			&i.Name,
+			&i.PrimaryComponent,
+			&i.DeploymentID,
+			&i.Version,
+			&i.DeployedAt,
+			&i.ReceivedAt,
+			&i.Sequence,
+			&i.Late,
+			&i.ClockSkew,
+			&i.Image,
+			&i.ImageDigest,
+			&i.GitCommit,
+			&i.BuildUrl,
+			&i.Deployer,
+			&i.Labels,
+			&i.Undeployed,
+			&i.Source,
+			&i.FreezeWindow,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateInstance = `-- name: UpdateInstance :execrows
+UPDATE instances
+SET name = ?2,
+    primary_component = ?3
+WHERE id = ?1
+`
+
+type UpdateInstanceParams struct {
+	ID               int64  `json:"id"`
+	Name             string `json:"name"`
+	PrimaryComponent string `json:"primary_component"`
+}
+
+func (q *Queries) UpdateInstance(ctx context.Context, arg UpdateInstanceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateInstance, arg.ID, arg.Name, arg.PrimaryComponent)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,170 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+
+package sqliterepo
+
+import (
+	"database/sql"
+)
+
+type Application struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	SortOrder int64  `json:"sort_order"`
+}
+
+type ApplicationPolicy struct {
+	ApplicationID      int64  `json:"application_id"`
+	Pinned             string `json:"pinned"`
+	Allowed            string `json:"allowed"`
+	Forbidden          string `json:"forbidden"`
+	NotAheadOfUpstream bool   `json:"not_ahead_of_upstream"`
+}
+
+type CurrentDeployment struct {
+	InstanceID   int64         `json:"instance_id"`
+	Component    string        `json:"component"`
+	DeploymentID string        `json:"deployment_id"`
+	OrderedAt    string        `json:"ordered_at"`
+	Sequence     sql.NullInt64 `json:"sequence"`
+	Source       string        `json:"source"`
+}
+
+type Deployment struct {
+	ID           string        `json:"id"`
+	InstanceID   int64         `json:"instance_id"`
+	Version      string        `json:"version"`
+	DeployedAt   string        `json:"deployed_at"`
+	ReceivedAt   string        `json:"received_at"`
+	Sequence     sql.NullInt64 `json:"sequence"`
+	Late         bool          `json:"late"`
+	ClockSkew    bool          `json:"clock_skew"`
+	Image        string        `json:"image"`
+	ImageDigest  string        `json:"image_digest"`
+	GitCommit    string        `json:"git_commit"`
+	BuildUrl     string        `json:"build_url"`
+	Deployer     string        `json:"deployer"`
+	Labels       string        `json:"labels"`
+	Component    string        `json:"component"`
+	Undeployed   bool          `json:"undeployed"`
+	Source       string        `json:"source"`
+	FreezeWindow string        `json:"freeze_window"`
+}
+
+type Environment struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	SortOrder int64  `json:"sort_order"`
+}
+
+type FreezeWindow struct {
+	ID              int64          `json:"id"`
+	Name            string         `json:"name"`
+	Reason          string         `json:"reason"`
+	EnvironmentID   sql.NullInt64  `json:"environment_id"`
+	ApplicationID   sql.NullInt64  `json:"application_id"`
+	StartsAt        sql.NullString `json:"starts_at"`
+	EndsAt          sql.NullString `json:"ends_at"`
+	Schedule        string         `json:"schedule"`
+	DurationSeconds int64          `json:"duration_seconds"`
+	TimeZone        string         `json:"time_zone"`
+	Reject          bool           `json:"reject"`
+}
+
+type Instance struct {
+	ID               int64  `json:"id"`
+	Name             string `json:"name"`
+	EnvironmentID    int64  `json:"environment_id"`
+	ApplicationID    int64  `json:"application_id"`
+	PrimaryComponent string `json:"primary_component"`
+}
+
+type InstancePolicy struct {
+	InstanceID         int64  `json:"instance_id"`
+	Pinned             string `json:"pinned"`
+	Allowed            string `json:"allowed"`
+	Forbidden          string `json:"forbidden"`
+	NotAheadOfUpstream bool   `json:"not_ahead_of_upstream"`
+}
+
+type NotificationChannel struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	Url          string `json:"url"`
+	Template     string `json:"template"`
+	BatchSeconds int64  `json:"batch_seconds"`
+	Enabled      bool   `json:"enabled"`
+}
+
+type NotificationRoute struct {
+	ID            int64         `json:"id"`
+	ChannelID     int64         `json:"channel_id"`
+	EnvironmentID sql.NullInt64 `json:"environment_id"`
+	ApplicationID sql.NullInt64 `json:"application_id"`
+	Events        string        `json:"events"`
+	Redeploys     bool          `json:"redeploys"`
+}
+
+type PolicyViolation struct {
+	DeploymentID string `json:"deployment_id"`
+	InstanceID   int64  `json:"instance_id"`
+	Version      string `json:"version"`
+	Rule         string `json:"rule"`
+	Message      string `json:"message"`
+	DetectedAt   string `json:"detected_at"`
+}
+
+type Promotion struct {
+	FromEnvironmentID int64 `json:"from_environment_id"`
+	ToEnvironmentID   int64 `json:"to_environment_id"`
+}
+
+type Release struct {
+	ApplicationID int64  `json:"application_id"`
+	Repository    string `json:"repository"`
+	Version       string `json:"version"`
+	CheckedAt     string `json:"checked_at"`
+}
+
+type Rollout struct {
+	InstanceID     int64         `json:"instance_id"`
+	Component      string        `json:"component"`
+	Version        string        `json:"version"`
+	Status         string        `json:"status"`
+	Description    string        `json:"description"`
+	Healthy        int64         `json:"healthy"`
+	Desired        int64         `json:"desired"`
+	RunningVersion string        `json:"running_version"`
+	UpdatedAt      string        `json:"updated_at"`
+	Sequence       sql.NullInt64 `json:"sequence"`
+	Source         string        `json:"source"`
+}
+
+type Webhook struct {
+	ID            int64         `json:"id"`
+	Name          string        `json:"name"`
+	Url           string        `json:"url"`
+	Secret        string        `json:"secret"`
+	Events        string        `json:"events"`
+	EnvironmentID sql.NullInt64 `json:"environment_id"`
+	ApplicationID sql.NullInt64 `json:"application_id"`
+	Enabled       bool          `json:"enabled"`
+	CreatedAt     string        `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             string         `json:"id"`
+	WebhookID      int64          `json:"webhook_id"`
+	EventID        string         `json:"event_id"`
+	Event          string         `json:"event"`
+	Payload        string         `json:"payload"`
+	Status         string         `json:"status"`
+	Attempts       int64          `json:"attempts"`
+	ResponseStatus int64          `json:"response_status"`
+	Error          string         `json:"error"`
+	CreatedAt      string         `json:"created_at"`
+	NextAttemptAt  sql.NullString `json:"next_attempt_at"`
+	LastAttemptAt  sql.NullString `json:"last_attempt_at"`
+}
@@ -0,0 +1,194 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: notifications.sql
+
+package sqliterepo
+
+import (
+	"context"
+	"database/sql"
+)
+
+const addNotificationRoute = `-- name: AddNotificationRoute :exec
+INSERT INTO notification_routes (channel_id, environment_id, application_id, events, redeploys)
+VALUES (?1, ?2, ?3, ?4, ?5)
+`
+
+type AddNotificationRouteParams struct {
+	ChannelID     int64         `json:"channel_id"`
+	EnvironmentID sql.NullInt64 `json:"environment_id"`
+	ApplicationID sql.NullInt64 `json:"application_id"`
+	Events        string        `json:"events"`
+	Redeploys     bool          `json:"redeploys"`
+}
+
+func (q *Queries) AddNotificationRoute(ctx context.Context, arg AddNotificationRouteParams) error {
+	_, err := q.db.ExecContext(ctx, addNotificationRoute,
+		arg.ChannelID,
+		arg.EnvironmentID,
+		arg.ApplicationID,
+		arg.Events,
+		arg.Redeploys,
+	)
+	return err
+}
+
+const createNotificationChannel = `-- name: CreateNotificationChannel :one
+INSERT INTO notification_channels (name, kind, url, template, batch_seconds, enabled)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+RETURNING id
+`
+
+type CreateNotificationChannelParams struct {
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	Url          string `json:"url"`
+	Template     string `json:"template"`
+	BatchSeconds int64  `json:"batch_seconds"`
+	Enabled      bool   `json:"enabled"`
+}
+
+func (q *Queries) CreateNotificationChannel(ctx context.Context, arg CreateNotificationChannelParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createNotificationChannel,
+		arg.Name,
+		arg.Kind,
+		arg.Url,
+		arg.Template,
+		arg.BatchSeconds,
+		arg.Enabled,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteNotificationChannel = `-- name: DeleteNotificationChannel :exec
+DELETE FROM notification_channels
+WHERE id = ?1
+`
+
+func (q *Queries) DeleteNotificationChannel(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteNotificationChannel, id)
+	return err
+}
+
+const deleteNotificationRoutes = `-- name: DeleteNotificationRoutes :exec
+DELETE FROM notification_routes
+WHERE channel_id = ?1
+`
+
+func (q *Queries) DeleteNotificationRoutes(ctx context.Context, channelID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteNotificationRoutes, channelID)
+	return err
+}
+
+const listNotificationChannels = `-- name: ListNotificationChannels :many
+SELECT id, name, kind, url, template, batch_seconds, enabled
+FROM notification_channels
+ORDER BY id
+`
+
+func (q *Queries) ListNotificationChannels(ctx context.Context) ([]NotificationChannel, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationChannels)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationChannel
+	for rows.Next() {
+		var i NotificationChannel
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Kind,
+			&i.Url,
+			&i.Template,
+			&i.BatchSeconds,
+			&i.Enabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationRoutes = `-- name: ListNotificationRoutes :many
+SELECT id, channel_id, environment_id, application_id, events, redeploys
+FROM notification_routes
+ORDER BY channel_id, id
+`
+
+func (q *Queries) ListNotificationRoutes(ctx context.Context) ([]NotificationRoute, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationRoutes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationRoute
+	for rows.Next() {
+		var i NotificationRoute
+		if err := rows.Scan(
+			&i.ID,
+			&i.ChannelID,
+			&i.EnvironmentID,
+			&i.ApplicationID,
+			&i.Events,
+			&i.Redeploys,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateNotificationChannel = `-- name: UpdateNotificationChannel :execrows
+UPDATE notification_channels
+SET name = ?2,
+    kind = ?3,
+    url = ?4,
+    template = ?5,
+    batch_seconds = ?6,
+    enabled = ?7
+WHERE id = ?1
+`
+
+type UpdateNotificationChannelParams struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	Url          string `json:"url"`
+	Template     string `json:"template"`
+	BatchSeconds int64  `json:"batch_seconds"`
+	Enabled      bool   `json:"enabled"`
+}
+
+func (q *Queries) UpdateNotificationChannel(ctx context.Context, arg UpdateNotificationChannelParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateNotificationChannel,
+		arg.ID,
+		arg.Name,
+		arg.Kind,
+		arg.Url,
+		arg.Template,
+		arg.BatchSeconds,
+		arg.Enabled,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
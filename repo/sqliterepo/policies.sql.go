@@ -0,0 +1,232 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: policies.sql
+
+package sqliterepo
+
+import (
+	"context"
+)
+
+const addPolicyViolation = `-- name: AddPolicyViolation :exec
+INSERT INTO policy_violations (deployment_id, instance_id, version, rule, message, detected_at)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT DO NOTHING
+`
+
+type AddPolicyViolationParams struct {
+	DeploymentID string `json:"deployment_id"`
+	InstanceID   int64  `json:"instance_id"`
+	Version      string `json:"version"`
+	Rule         string `json:"rule"`
+	Message      string `json:"message"`
+	DetectedAt   string `json:"detected_at"`
+}
+
+func (q *Queries) AddPolicyViolation(ctx context.Context, arg AddPolicyViolationParams) error {
+	_, err := q.db.ExecContext(ctx, addPolicyViolation,
+		arg.DeploymentID,
+		arg.InstanceID,
+		arg.Version,
+		arg.Rule,
+		arg.Message,
+		arg.DetectedAt,
+	)
+	return err
+}
+
+const deleteApplicationPolicy = `-- name: DeleteApplicationPolicy :exec
+DELETE FROM application_policies
+WHERE application_id = ?
+`
+
+func (q *Queries) DeleteApplicationPolicy(ctx context.Context, applicationID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteApplicationPolicy, applicationID)
+	return err
+}
+
+const deleteInstancePolicy = `-- name: DeleteInstancePolicy :exec
+DELETE FROM instance_policies
+WHERE instance_id = ?
+`
+
+func (q *Queries) DeleteInstancePolicy(ctx context.Context, instanceID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteInstancePolicy, instanceID)
+	return err
+}
+
+const listApplicationPolicies = `-- name: ListApplicationPolicies :many
+SELECT application_id, pinned, allowed, forbidden, not_ahead_of_upstream
+FROM application_policies
+ORDER BY application_id
+`
+
+func (q *Queries) ListApplicationPolicies(ctx context.Context) ([]ApplicationPolicy, error) {
+	rows, err := q.db.QueryContext(ctx, listApplicationPolicies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApplicationPolicy
+	for rows.Next() {
+		var i ApplicationPolicy
+		if err := rows.Scan(
+			&i.ApplicationID,
+			&i.Pinned,
+			&i.Allowed,
+			&i.Forbidden,
+			&i.NotAheadOfUpstream,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancePolicies = `-- name: ListInstancePolicies :many
+SELECT p.instance_id, p.pinned, p.allowed, p.forbidden, p.not_ahead_of_upstream, i.application_id
+FROM instance_policies p
+JOIN instances i ON i.id = p.instance_id
+ORDER BY i.application_id, p.instance_id
+`
+
+type ListInstancePoliciesRow struct {
+	InstanceID         int64  `json:"instance_id"`
+	Pinned             string `json:"pinned"`
+	Allowed            string `json:"allowed"`
+	Forbidden          string `json:"forbidden"`
+	NotAheadOfUpstream bool   `json:"not_ahead_of_upstream"`
+	ApplicationID      int64  `json:"application_id"`
+}
+
+func (q *Queries) ListInstancePolicies(ctx context.Context) ([]ListInstancePoliciesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInstancePolicies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancePoliciesRow
+	for rows.Next() {
+		var i ListInstancePoliciesRow
+		if err := rows.Scan(
+			&i.InstanceID,
+			&i.Pinned,
+			&i.Allowed,
+			&i.Forbidden,
+			&i.NotAheadOfUpstream,
+			&i.ApplicationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPolicyViolations = `-- name: ListPolicyViolations :many
+SELECT deployment_id, instance_id, version, rule, message, detected_at
+FROM policy_violations
+ORDER BY detected_at, deployment_id, rule
+`
+
+func (q *Queries) ListPolicyViolations(ctx context.Context) ([]PolicyViolation, error) {
+	rows, err := q.db.QueryContext(ctx, listPolicyViolations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PolicyViolation
+	for rows.Next() {
+		var i PolicyViolation
+		if err := rows.Scan(
+			&i.DeploymentID,
+			&i.InstanceID,
+			&i.Version,
+			&i.Rule,
+			&i.Message,
+			&i.DetectedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const saveApplicationPolicy = `-- name: SaveApplicationPolicy :exec
+INSERT INTO application_policies (application_id, pinned, allowed, forbidden, not_ahead_of_upstream)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (application_id) DO UPDATE
+SET pinned = EXCLUDED.pinned,
+    allowed = EXCLUDED.allowed,
+    forbidden = EXCLUDED.forbidden,
+    not_ahead_of_upstream = EXCLUDED.not_ahead_of_upstream
+`
+
+type SaveApplicationPolicyParams struct {
+	ApplicationID      int64  `json:"application_id"`
+	Pinned             string `json:"pinned"`
+	Allowed            string `json:"allowed"`
+	Forbidden          string `json:"forbidden"`
+	NotAheadOfUpstream bool   `json:"not_ahead_of_upstream"`
+}
+
+func (q *Queries) SaveApplicationPolicy(ctx context.Context, arg SaveApplicationPolicyParams) error {
+	_, err := q.db.ExecContext(ctx, saveApplicationPolicy,
+		arg.ApplicationID,
+		arg.Pinned,
+		arg.Allowed,
+		arg.Forbidden,
+		arg.NotAheadOfUpstream,
+	)
+	return err
+}
+
+const saveInstancePolicy = `-- name: SaveInstancePolicy :exec
+INSERT INTO instance_policies (instance_id, pinned, allowed, forbidden, not_ahead_of_upstream)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (instance_id) DO UPDATE
+SET pinned = EXCLUDED.pinned,
+    allowed = EXCLUDED.allowed,
+    forbidden = EXCLUDED.forbidden,
+    not_ahead_of_upstream = EXCLUDED.not_ahead_of_upstream
+`
+
+type SaveInstancePolicyParams struct {
+	InstanceID         int64  `json:"instance_id"`
+	Pinned             string `json:"pinned"`
+	Allowed            string `json:"allowed"`
+	Forbidden          string `json:"forbidden"`
+	NotAheadOfUpstream bool   `json:"not_ahead_of_upstream"`
+}
+
+func (q *Queries) SaveInstancePolicy(ctx context.Context, arg SaveInstancePolicyParams) error {
+	_, err := q.db.ExecContext(ctx, saveInstancePolicy,
+		arg.InstanceID,
+		arg.Pinned,
+		arg.Allowed,
+		arg.Forbidden,
+		arg.NotAheadOfUpstream,
+	)
+	return err
+}
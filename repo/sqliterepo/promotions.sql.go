@@ -0,0 +1,65 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: promotions.sql
+
+package sqliterepo
+
+import (
+	"context"
+)
+
+const addPromotion = `-- name: AddPromotion :exec
+INSERT INTO promotions (from_environment_id, to_environment_id)
+VALUES (?, ?)
+ON CONFLICT DO NOTHING
+`
+
+type AddPromotionParams struct {
+	FromEnvironmentID int64 `json:"from_environment_id"`
+	ToEnvironmentID   int64 `json:"to_environment_id"`
+}
+
+func (q *Queries) AddPromotion(ctx context.Context, arg AddPromotionParams) error {
+	_, err := q.db.ExecContext(ctx, addPromotion, arg.FromEnvironmentID, arg.ToEnvironmentID)
+	return err
+}
+
+const deletePromotionsTo = `-- name: DeletePromotionsTo :exec
+DELETE FROM promotions
+WHERE to_environment_id = ?
+`
+
+func (q *Queries) DeletePromotionsTo(ctx context.Context, toEnvironmentID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePromotionsTo, toEnvironmentID)
+	return err
+}
+
+const listPromotions = `-- name: ListPromotions :many
+SELECT from_environment_id, to_environment_id
+FROM promotions
+ORDER BY from_environment_id, to_environment_id
+`
+
+func (q *Queries) ListPromotions(ctx context.Context) ([]Promotion, error) {
+	rows, err := q.db.QueryContext(ctx, listPromotions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Promotion
+	for rows.Next() {
+		var i Promotion
+		if err := rows.Scan(&i.FromEnvironmentID, &i.ToEnvironmentID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,326 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webhooks.sql
+
+package repo
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addWebhookDelivery = `-- name: AddWebhookDelivery :exec
+INSERT INTO webhook_deliveries (id, webhook_id, event_id, event, payload, status, created_at, next_attempt_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type AddWebhookDeliveryParams struct {
+	ID            pgtype.UUID        `json:"id"`
+	WebhookID     int32              `json:"webhook_id"`
+	EventID       pgtype.UUID        `json:"event_id"`
+	Event         string             `json:"event"`
+	Payload       string             `json:"payload"`
+	Status        string             `json:"status"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+}
+
+func (q *Queries) AddWebhookDelivery(ctx context.Context, arg AddWebhookDeliveryParams) error {
+	_, err := q.db.Exec(ctx, addWebhookDelivery,
+		arg.ID,
+		arg.WebhookID,
+		arg.EventID,
+		arg.Event,
+		arg.Payload,
+		arg.Status,
+		arg.CreatedAt,
+		arg.NextAttemptAt,
+	)
+	return err
+}
+
+const createWebhook = `-- name: CreateWebhook :one
+INSERT INTO webhooks (name, url, secret, events, environment_id, application_id, enabled, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id
+`
+
+type CreateWebhookParams struct {
+	Name          string             `json:"name"`
+	Url           string             `json:"url"`
+	Secret        string             `json:"secret"`
+	Events        []byte             `json:"events"`
+	EnvironmentID pgtype.Int4        `json:"environment_id"`
+	ApplicationID pgtype.Int4        `json:"application_id"`
+	Enabled       bool               `json:"enabled"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createWebhook,
+		arg.Name,
+		arg.Url,
+		arg.Secret,
+		arg.Events,
+		arg.EnvironmentID,
+		arg.ApplicationID,
+		arg.Enabled,
+		arg.CreatedAt,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteWebhook = `-- name: DeleteWebhook :exec
+DELETE FROM webhooks
+WHERE id = $1
+`
+
+func (q *Queries) DeleteWebhook(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, deleteWebhook, id)
+	return err
+}
+
+const deleteWebhookDeliveries = `-- name: DeleteWebhookDeliveries :execrows
+DELETE FROM webhook_deliveries
+WHERE status <> 'pending' AND created_at < $1
+`
+
+// Delete the finished deliveries created before the given time.
+func (q *Queries) DeleteWebhookDeliveries(ctx context.Context, createdAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWebhookDeliveries, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getWebhookDelivery = `-- name: GetWebhookDelivery :one
+SELECT id, webhook_id, event_id, event, payload, status, attempts, response_status, error, created_at, next_attempt_at, last_attempt_at
+FROM webhook_deliveries
+WHERE id = $1
+`
+
+func (q *Queries) GetWebhookDelivery(ctx context.Context, id pgtype.UUID) (WebhookDelivery, error) {
+	row := q.db.QueryRow(ctx, getWebhookDelivery, id)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.EventID,
+		&i.Event,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.ResponseStatus,
+		&i.Error,
+		&i.CreatedAt,
+		&i.NextAttemptAt,
+		&i.LastAttemptAt,
+	)
+	return i, err
+}
+
+const listDueWebhookDeliveries = `-- name: ListDueWebhookDeliveries :many
+SELECT id, webhook_id, event_id, event, payload, status, attempts, response_status, error, created_at, next_attempt_at, last_attempt_at
+FROM webhook_deliveries
+WHERE status = 'pending' AND next_attempt_at <= $1
+ORDER BY next_attempt_at, created_at
+`
+
+// The pending deliveries that are due to be attempted.
+func (q *Queries) ListDueWebhookDeliveries(ctx context.Context, nextAttemptAt pgtype.Timestamptz) ([]WebhookDelivery, error) {
+	rows, err := q.db.Query(ctx, listDueWebhookDeliveries, nextAttemptAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventID,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.ResponseStatus,
+			&i.Error,
+			&i.CreatedAt,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT id, webhook_id, event_id, event, payload, status, attempts, response_status, error, created_at, next_attempt_at, last_attempt_at
+FROM webhook_deliveries
+WHERE $1::integer = 0 OR webhook_id = $1
+ORDER BY created_at DESC, id
+LIMIT $2
+`
+
+type ListWebhookDeliveriesParams struct {
+	WebhookID     int32 `json:"webhook_id"`
+	MaxDeliveries int32 `json:"max_deliveries"`
+}
+
+// The delivery log of a webhook, or of all webhooks if the webhook id is 0, the most recent first.
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.Query(ctx, listWebhookDeliveries, arg.WebhookID, arg.MaxDeliveries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventID,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.ResponseStatus,
+			&i.Error,
+			&i.CreatedAt,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhooks = `-- name: ListWebhooks :many
+SELECT id, name, url, secret, events, environment_id, application_id, enabled, created_at
+FROM webhooks
+ORDER BY id
+`
+
+func (q *Queries) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	rows, err := q.db.Query(ctx, listWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.Secret,
+			&i.Events,
+			&i.EnvironmentID,
+			&i.ApplicationID,
+			&i.Enabled,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWebhook = `-- name: UpdateWebhook :execrows
+UPDATE webhooks
+SET name = $2,
+    url = $3,
+    secret = $4,
+    events = $5,
+    environment_id = $6,
+    application_id = $7,
+    enabled = $8
+WHERE id = $1
+`
+
+type UpdateWebhookParams struct {
+	ID            int32       `json:"id"`
+	Name          string      `json:"name"`
+	Url           string      `json:"url"`
+	Secret        string      `json:"secret"`
+	Events        []byte      `json:"events"`
+	EnvironmentID pgtype.Int4 `json:"environment_id"`
+	ApplicationID pgtype.Int4 `json:"application_id"`
+	Enabled       bool        `json:"enabled"`
+}
+
+func (q *Queries) UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateWebhook,
+		arg.ID,
+		arg.Name,
+		arg.Url,
+		arg.Secret,
+		arg.Events,
+		arg.EnvironmentID,
+		arg.ApplicationID,
+		arg.Enabled,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateWebhookDelivery = `-- name: UpdateWebhookDelivery :execrows
+UPDATE webhook_deliveries
+SET status = $2,
+    attempts = $3,
+    response_status = $4,
+    error = $5,
+    next_attempt_at = $6,
+    last_attempt_at = $7
+WHERE id = $1
+`
+
+type UpdateWebhookDeliveryParams struct {
+	ID             pgtype.UUID        `json:"id"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	ResponseStatus int32              `json:"response_status"`
+	Error          string             `json:"error"`
+	NextAttemptAt  pgtype.Timestamptz `json:"next_attempt_at"`
+	LastAttemptAt  pgtype.Timestamptz `json:"last_attempt_at"`
+}
+
+func (q *Queries) UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateWebhookDelivery,
+		arg.ID,
+		arg.Status,
+		arg.Attempts,
+		arg.ResponseStatus,
+		arg.Error,
+		arg.NextAttemptAt,
+		arg.LastAttemptAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
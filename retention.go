@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"overseer/app"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retentionFlag collects the -retention flags into retention policies.
+//
+// Each flag has the form <environment>=<rule>[,<rule>], where a rule is a maximum age such as 90d or 720h,
+// "compact" to only keep the deployments that changed the version, or "forever" to keep everything.
+// The environment "*" sets the policy of all environments without one of their own.
+type retentionFlag struct {
+	policies *app.RetentionPolicies
+}
+
+func (f retentionFlag) String() string {
+	if f.policies == nil {
+		return ""
+	}
+
+	var parts []string
+	for env, policy := range f.policies.Environments {
+		parts = append(parts, env+"="+formatRetentionPolicy(policy))
+	}
+	if !f.policies.Default.IsZero() {
+		parts = append(parts, "*="+formatRetentionPolicy(f.policies.Default))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (f retentionFlag) Set(value string) error {
+	env, rules, ok := strings.Cut(value, "=")
+	if !ok || env == "" {
+		return fmt.Errorf("expected <environment>=<rules>, got %q", value)
+	}
+
+	var policy app.RetentionPolicy
+	for rule := range strings.SplitSeq(rules, ",") {
+		switch rule {
+		case "forever":
+			continue
+		case "compact":
+			policy.Compact = true
+			continue
+		}
+
+		maxAge, err := parseMaxAge(rule)
+		if err != nil {
+			return err
+		}
+		policy.MaxAge = maxAge
+	}
+
+	if env == "*" {
+		f.policies.Default = policy
+		return nil
+	}
+
+	if f.policies.Environments == nil {
+		f.policies.Environments = map[string]app.RetentionPolicy{}
+	}
+	f.policies.Environments[env] = policy
+	return nil
+}
+
+// parseMaxAge parses a duration, which in addition to the units of time.ParseDuration may be a number of days like 90d.
+func parseMaxAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid number of days %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid retention rule %q, expected a duration like 90d, \"compact\" or \"forever\"", s)
+	}
+	return d, nil
+}
+
+func formatRetentionPolicy(p app.RetentionPolicy) string {
+	var rules []string
+	if p.MaxAge > 0 {
+		rules = append(rules, p.MaxAge.String())
+	}
+	if p.Compact {
+		rules = append(rules, "compact")
+	}
+	if len(rules) == 0 {
+		return "forever"
+	}
+	return strings.Join(rules, ",")
+}
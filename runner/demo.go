@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"overseer/app"
+)
+
+// seedDemo fills an empty store with a few environments, applications and instances,
+// matching the deployment names emitted by the mock datasource.
+func seedDemo(ctx context.Context, a *app.App) error {
+	var envs []app.Environment
+	for _, name := range []string{"env1", "env2"} {
+		env, err := a.CreateEnvironment(ctx, name)
+		if err != nil {
+			return err
+		}
+		envs = append(envs, env)
+	}
+
+	var apps []app.Application
+	for _, name := range []string{"app-1", "app-2"} {
+		application, err := a.CreateApplication(ctx, name)
+		if err != nil {
+			return err
+		}
+		apps = append(apps, application)
+	}
+
+	for _, env := range envs {
+		for _, application := range apps {
+			if _, err := a.CreateInstance(ctx, app.CreateInstanceParameters{
+				EnvironmentId: env.Id,
+				ApplicationId: application.Id,
+				Name:          fmt.Sprintf("%s-%s", env.Name, application.Name),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
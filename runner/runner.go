@@ -13,13 +13,28 @@ import (
 	"os"
 	"os/signal"
 	applicationpb "overseer/api-go/application/v1"
+	datasourcepb "overseer/api-go/datasource/v1"
 	deploymentpb "overseer/api-go/deployment/v1"
 	environmentpb "overseer/api-go/environment/v1"
+	freezepb "overseer/api-go/freeze/v1"
 	instancepb "overseer/api-go/instance/v1"
+	notificationpb "overseer/api-go/notification/v1"
+	pluginpb "overseer/api-go/plugin/v1"
+	policypb "overseer/api-go/policy/v1"
+	promotionpb "overseer/api-go/promotion/v1"
+	releasepb "overseer/api-go/release/v1"
+	webhookpb "overseer/api-go/webhook/v1"
 	"overseer/app"
-	"overseer/datasource"
+	"overseer/datasource/plugin"
+	"overseer/datasource/registry"
+	"overseer/db"
 	"overseer/entrypoints"
-	"overseer/repo"
+	"overseer/gitrepo"
+	"overseer/migrate"
+	"overseer/notify"
+	"overseer/storage/memory"
+	"overseer/storage/postgres"
+	"overseer/storage/sqlite"
 	"sync"
 	"time"
 
@@ -28,10 +43,58 @@ import (
 	"google.golang.org/grpc/reflection"
 )
 
+const (
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+	// StorageMemory keeps everything in memory and seeds it with demo data.
+	StorageMemory = "memory"
+)
+
 type Config struct {
+	// Storage is the backend used to store the data, defaults to StoragePostgres.
+	Storage      string
 	DbConnString string
+	// SQLitePath is the database file used by StorageSQLite.
+	SQLitePath string
+	// AutoMigrate applies pending schema migrations at startup.
+	// If it is disabled, the server refuses to start on an outdated schema.
+	AutoMigrate bool
+	// Retention decides how long the deployment history of each environment is kept,
+	// the history is kept forever if no policy is set.
+	Retention app.RetentionPolicies
+	// PruneInterval is how often the deployment history is pruned, defaults to DefaultPruneInterval.
+	PruneInterval time.Duration
+	// MaxClockSkew is how far ahead of the time they are received deployment times may be
+	// before the deployments are flagged as clock skewed, defaults to app.DefaultMaxClockSkew.
+	MaxClockSkew time.Duration
+	// ComponentRules route the events of the datasource to instance components, the first matching rule is used.
+	ComponentRules []app.ComponentRule
+	// Sources are the sources of deployment events, which all run at the same time.
+	// A demo source is used if none is configured.
+	Sources []SourceConfig
+	// Registry enables discovering the latest releases of the applications in their image repositories.
+	Registry *registry.Config
+	// ReleaseInterval is how often the releases are discovered, defaults to DefaultReleaseInterval.
+	ReleaseInterval time.Duration
+	// Repositories maps application names to the image repositories their releases are discovered in,
+	// the other applications use the repository of their deployed images.
+	Repositories map[string]string
+	// GitRepositories maps application names to local clones or mirrors of their git repositories,
+	// enabling the changelogs between their versions.
+	GitRepositories map[string]string
+	// WebhookInterval is how often the webhook deliveries that are due to be retried are attempted,
+	// defaults to DefaultWebhookInterval. New events are delivered right away.
+	WebhookInterval time.Duration
+	// WebhookTimeout is the timeout of the webhook and notification requests, defaults to notify.DefaultTimeout.
+	WebhookTimeout time.Duration
 }
 
+const (
+	DefaultPruneInterval   = time.Hour
+	DefaultReleaseInterval = 15 * time.Minute
+	DefaultWebhookInterval = 10 * time.Second
+)
+
 type Runner struct {
 	config *Config
 }
@@ -73,34 +136,164 @@ func removeTrailingNewline(s string) string {
 	return s
 }
 
+// openStore opens the configured storage backend and returns a function closing it.
+func (r *Runner) openStore(ctx context.Context) (app.Store, func(), error) {
+	switch r.config.Storage {
+	case StorageMemory:
+		slog.Warn("using in-memory storage, all data will be lost on shutdown")
+		return memory.New(), func() {}, nil
+
+	case StorageSQLite:
+		migrator, err := sqlite.OpenMigrator(r.config.SQLitePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open the SQLite database: %w", err)
+		}
+		err = r.migrate(ctx, migrator)
+		migrator.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		store, err := sqlite.Open(ctx, r.config.SQLitePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open the SQLite database: %w", err)
+		}
+
+		return store, func() { store.Close() }, nil
+
+	case StoragePostgres, "":
+		dbpool, err := pgxpool.New(ctx, r.config.DbConnString)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		migrator, err := migrate.New(dbpool, db.Migrations())
+		if err != nil {
+			dbpool.Close()
+			return nil, nil, fmt.Errorf("loading migrations: %w", err)
+		}
+		if err := r.migrate(ctx, migrator); err != nil {
+			dbpool.Close()
+			return nil, nil, err
+		}
+
+		return postgres.New(dbpool), dbpool.Close, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown storage %q", r.config.Storage)
+	}
+}
+
+// migrator applies the schema migrations of a database, implemented by migrate.Migrator and sqlite.Migrator.
+type migrator interface {
+	Up(ctx context.Context) ([]migrate.Migration, error)
+	Check(ctx context.Context) (int, error)
+}
+
+// migrate applies the pending migrations if AutoMigrate is enabled, and otherwise fails if any are pending.
+func (r *Runner) migrate(ctx context.Context, migrator migrator) error {
+	if r.config.AutoMigrate {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("migrating the database: %w", err)
+		}
+
+		for _, m := range applied {
+			slog.Info("applied migration", "version", m.Version, "name", m.Name)
+		}
+		return nil
+	}
+
+	pending, err := migrator.Check(ctx)
+	if err != nil {
+		return fmt.Errorf("checking the database schema: %w", err)
+	}
+
+	if pending > 0 {
+		return fmt.Errorf("the database has %d pending migrations, run the migrate command or enable AutoMigrate", pending)
+	}
+
+	return nil
+}
+
 func (r *Runner) Run(ctx context.Context) error {
 	termChan := make(chan os.Signal, 1)
 	errChan := make(chan error, 1)
 	signal.Notify(termChan, os.Interrupt)
 
-	dbpool, err := pgxpool.New(ctx, r.config.DbConnString)
+	store, closeStore, err := r.openStore(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+		return err
 	}
-	defer dbpool.Close()
+	defer closeStore()
 
-	queries := repo.New(dbpool)
+	// The plugins that connect to Overseer publish their events to the hub.
+	pluginHub := plugin.NewHub()
 
-	app := app.New(queries)
+	dataSources, err := r.dataSources(pluginHub)
+	if err != nil {
+		return err
+	}
+
+	gitReader := gitrepo.NewReader()
+	for application, repository := range r.config.GitRepositories {
+		// A repository that can not be read is only reported, as it may still be being cloned.
+		if err := gitReader.Check(ctx, repository); err != nil {
+			slog.Warn("the git repository of the application can not be read", "application", application, "repository", repository, "error", err)
+		}
+	}
+
+	// The webhooks and the notification channels share one HTTP client.
+	sender := notify.NewClient(r.config.WebhookTimeout)
+
+	app := app.New(store, app.Options{
+		Retention:       r.config.Retention,
+		MaxClockSkew:    r.config.MaxClockSkew,
+		ComponentRules:  r.config.ComponentRules,
+		Repositories:    r.config.Repositories,
+		GitRepositories: r.config.GitRepositories,
+		Git:             gitReader,
+		Notifier:        sender,
+	})
+
+	for _, source := range dataSources {
+		if err := app.AddSource(source); err != nil {
+			return err
+		}
+	}
+
+	if r.config.Storage == StorageMemory {
+		if err := seedDemo(ctx, app); err != nil {
+			return fmt.Errorf("seeding the demo data: %w", err)
+		}
+	}
 
 	mux := http.NewServeMux()
 	entrypoints.RegisterRestHandlers(mux, app)
+	for _, dataSource := range dataSources {
+		// Sources receiving their events over HTTP, like the webhooks, share the REST server.
+		if h, ok := dataSource.Source.(interface{ RegisterHandlers(*http.ServeMux) }); ok {
+			h.RegisterHandlers(mux)
+		}
+	}
 	server := &http.Server{Addr: ":8080", Handler: LoggerMiddleware(mux)}
 
 	applicationGrpc := entrypoints.NewApplicationServer(app)
 	environmentGrpc := entrypoints.NewEnvironmentServer(app)
 	deploymentGrpc := entrypoints.NewDeploymentServer(app)
 	instanceGrpc := entrypoints.NewInstanceServer(app)
+	datasourceGrpc := entrypoints.NewDatasourceServer(app)
+	releaseGrpc := entrypoints.NewReleaseServer(app)
+	promotionGrpc := entrypoints.NewPromotionServer(app)
+	policyGrpc := entrypoints.NewPolicyServer(app)
+	freezeGrpc := entrypoints.NewFreezeServer(app)
+	webhookGrpc := entrypoints.NewWebhookServer(app)
+	notificationGrpc := entrypoints.NewNotificationServer(app)
 
 	grpcServer := grpc.NewServer(
-	// grpc.MaxRecvMsgSize(mb256),
-	// grpc.MaxSendMsgSize(mb256),
-	// grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		// grpc.MaxRecvMsgSize(mb256),
+		// grpc.MaxSendMsgSize(mb256),
+		grpc.ChainUnaryInterceptor(entrypoints.ErrorInterceptor),
 	)
 
 	reflection.Register(grpcServer)
@@ -109,23 +302,79 @@ func (r *Runner) Run(ctx context.Context) error {
 	environmentpb.RegisterEnvironmentServiceServer(grpcServer, environmentGrpc)
 	deploymentpb.RegisterDeploymentServiceServer(grpcServer, deploymentGrpc)
 	instancepb.RegisterInstanceServiceServer(grpcServer, instanceGrpc)
+	datasourcepb.RegisterDatasourceServiceServer(grpcServer, datasourceGrpc)
+	releasepb.RegisterReleaseServiceServer(grpcServer, releaseGrpc)
+	promotionpb.RegisterPromotionServiceServer(grpcServer, promotionGrpc)
+	policypb.RegisterPolicyServiceServer(grpcServer, policyGrpc)
+	freezepb.RegisterFreezeServiceServer(grpcServer, freezeGrpc)
+	webhookpb.RegisterWebhookServiceServer(grpcServer, webhookGrpc)
+	notificationpb.RegisterNotificationServiceServer(grpcServer, notificationGrpc)
+	pluginpb.RegisterPublishServiceServer(grpcServer, pluginHub)
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// dataSource := nomad.NewSource("http://rock-srv-1.local:4646", "your-nomad-token", slog.Default())
-	dataSource := &datasource.MockSource{}
-
 	wg := sync.WaitGroup{}
 
 	wg.Go(func() {
-		if err := app.RunVersionStream(ctx, dataSource); err != nil {
+		if err := app.RunSources(ctx); err != nil {
 			errChan <- fmt.Errorf("version stream error: %w", err)
 		}
 
 		slog.Info("Event stream processing stopped.")
 	})
 
+	if !r.config.Retention.IsZero() {
+		wg.Go(func() {
+			defer slog.Info("Deployment history pruning stopped.")
+
+			interval := r.config.PruneInterval
+			if interval == 0 {
+				interval = DefaultPruneInterval
+			}
+
+			if err := app.RunPruner(ctx, interval); err != nil {
+				errChan <- fmt.Errorf("pruner error: %w", err)
+			}
+		})
+	}
+
+	if r.config.Registry != nil {
+		wg.Go(func() {
+			defer slog.Info("Release discovery stopped.")
+
+			interval := r.config.ReleaseInterval
+			if interval == 0 {
+				interval = DefaultReleaseInterval
+			}
+
+			if err := app.RunReleaseDiscovery(ctx, registry.NewClient(*r.config.Registry), interval); err != nil {
+				errChan <- fmt.Errorf("release discovery error: %w", err)
+			}
+		})
+	}
+
+	wg.Go(func() {
+		defer slog.Info("Webhook delivery stopped.")
+
+		interval := r.config.WebhookInterval
+		if interval == 0 {
+			interval = DefaultWebhookInterval
+		}
+
+		if err := app.RunWebhooks(ctx, sender, interval); err != nil {
+			errChan <- fmt.Errorf("webhook delivery error: %w", err)
+		}
+	})
+
+	wg.Go(func() {
+		defer slog.Info("Notifications stopped.")
+
+		if err := app.RunNotifications(ctx); err != nil {
+			errChan <- fmt.Errorf("notification error: %w", err)
+		}
+	})
+
 	wg.Go(func() {
 		defer slog.Info("gRPC server stopped")
 
@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"overseer/app"
+	"overseer/datasource"
+	"overseer/datasource/docker"
+	"overseer/datasource/httppoll"
+	"overseer/datasource/nomad"
+	"overseer/datasource/plugin"
+	"overseer/datasource/prometheus"
+	"overseer/datasource/webhook"
+)
+
+// SourceConfig configures a source of deployment events, exactly one of the configs of the source types must be set.
+type SourceConfig struct {
+	// Name identifies the source and is recorded with the deployments it reports, defaults to its type.
+	Name string
+	// Disabled sources are not started until they are enabled through the datasource API.
+	Disabled bool
+
+	// Nomad configures a Nomad cluster deployments are streamed from.
+	Nomad *nomad.Config
+	// Docker configures a Docker Engine deployments are streamed from.
+	Docker *docker.Config
+	// Webhook configures the webhooks CI/CD systems push deployments to, it can only be configured once.
+	Webhook *webhook.Config
+	// Poll configures version endpoints the deployed versions are polled from.
+	Poll *httppoll.Config
+	// Prometheus configures build info metrics the deployed versions are read from.
+	Prometheus *prometheus.Config
+	// Plugin configures an external source speaking the plugin protocol.
+	Plugin *plugin.Config
+}
+
+// newSource creates the source and returns its type, the plugins that connect to Overseer are accepted by the hub.
+func (c SourceConfig) newSource(hub *plugin.Hub) (string, app.EventSource, error) {
+	var (
+		typ    string
+		source app.EventSource
+		err    error
+		types  int
+	)
+
+	if c.Nomad != nil {
+		typ, types = "nomad", types+1
+		source, err = nomad.NewSource(*c.Nomad, slog.Default().With("source", cmp.Or(c.Name, typ)))
+	}
+	if c.Docker != nil {
+		typ, types = "docker", types+1
+		source, err = docker.NewSource(*c.Docker, slog.Default().With("source", cmp.Or(c.Name, typ)))
+	}
+	if c.Webhook != nil {
+		typ, types = "webhook", types+1
+		source, err = webhook.NewSource(*c.Webhook, slog.Default().With("source", cmp.Or(c.Name, typ)))
+	}
+	if c.Poll != nil {
+		typ, types = "httppoll", types+1
+		source, err = httppoll.NewSource(*c.Poll, slog.Default().With("source", cmp.Or(c.Name, typ)))
+	}
+	if c.Prometheus != nil {
+		typ, types = "prometheus", types+1
+		source, err = prometheus.NewSource(*c.Prometheus, slog.Default().With("source", cmp.Or(c.Name, typ)))
+	}
+	if c.Plugin != nil {
+		typ, types = "plugin", types+1
+		source, err = plugin.NewSource(cmp.Or(c.Name, typ), *c.Plugin, hub, slog.Default().With("source", cmp.Or(c.Name, typ)))
+	}
+
+	if types != 1 {
+		return "", nil, fmt.Errorf("source %q must configure exactly one type of source, got %d", c.Name, types)
+	}
+	return typ, source, err
+}
+
+// dataSources creates the configured sources of deployment events.
+func (r *Runner) dataSources(hub *plugin.Hub) ([]app.AddSourceParams, error) {
+	var sources []app.AddSourceParams
+	webhooks := 0
+
+	for _, c := range r.config.Sources {
+		typ, source, err := c.newSource(hub)
+		if err != nil {
+			return nil, fmt.Errorf("configuring the source %s: %w", cmp.Or(c.Name, typ), err)
+		}
+
+		if c.Webhook != nil {
+			// The webhooks of every source would be mounted on the same paths.
+			if webhooks++; webhooks > 1 {
+				return nil, errors.New("the webhooks can only be configured once")
+			}
+		}
+
+		sources = append(sources, app.AddSourceParams{
+			Name:     cmp.Or(c.Name, typ),
+			Type:     typ,
+			Source:   source,
+			Disabled: c.Disabled,
+		})
+	}
+
+	if len(sources) == 0 {
+		sources = append(sources, app.AddSourceParams{Name: "demo", Type: "demo", Source: &datasource.MockSource{}})
+	}
+
+	return sources, nil
+}
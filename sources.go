@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"overseer/datasource/docker"
+	"overseer/datasource/httppoll"
+	"overseer/datasource/nomad"
+	"overseer/datasource/plugin"
+	"overseer/datasource/prometheus"
+	"overseer/runner"
+	"strings"
+)
+
+// sourceFlags holds the configs set by the flags of the source types.
+type sourceFlags struct {
+	nomad      nomad.Config
+	docker     docker.Config
+	poll       httppoll.Config
+	prometheus prometheus.Config
+	plugin     plugin.Config
+}
+
+func (c *sourceFlags) register(fs *flag.FlagSet) {
+	nomadFlags(fs, &c.nomad)
+	fs.StringVar(&c.docker.Host, "docker-host", "", "Docker Engine API to stream container deployments from, like unix:///var/run/docker.sock or tcp://edge-1:2375")
+	fs.StringVar(&c.docker.NamePrefix, "docker-name-prefix", "", "prefix of the deployment names of the Docker containers, like the name of the host")
+	pollFlags(fs, &c.poll)
+	prometheusFlags(fs, &c.prometheus)
+	fs.StringVar(&c.plugin.Address, "plugin-addr", "", "address of the SourceService of an external source to stream deployments from, like localhost:7070")
+	fs.BoolVar(&c.plugin.TLS, "plugin-tls", false, "connect to the external source with TLS")
+	fs.StringVar(&c.plugin.CACert, "plugin-ca-cert", "", "PEM file with the CA certificates the certificate of the external source is verified with, implies -plugin-tls")
+	fs.BoolVar(&c.plugin.Accept, "plugin-accept", false, "accept an external source that publishes its deployments to the PublishService of Overseer, with the name of the source")
+	fs.StringVar(&c.plugin.TokenFile, "plugin-token-file", "", "file holding the token the accepted external source must send")
+}
+
+// sources returns a source for every type that is configured, named after the type.
+func (c *sourceFlags) sources() []runner.SourceConfig {
+	var sources []runner.SourceConfig
+	if c.nomad.Address != "" {
+		sources = append(sources, runner.SourceConfig{Nomad: &c.nomad})
+	}
+	if c.docker.Host != "" {
+		sources = append(sources, runner.SourceConfig{Docker: &c.docker})
+	}
+	if len(c.poll.Targets) > 0 {
+		sources = append(sources, runner.SourceConfig{Poll: &c.poll})
+	}
+	if len(c.prometheus.Targets) > 0 || c.prometheus.PrometheusURL != "" {
+		sources = append(sources, runner.SourceConfig{Prometheus: &c.prometheus})
+	}
+	if c.plugin.Address != "" || c.plugin.Accept {
+		sources = append(sources, runner.SourceConfig{Plugin: &c.plugin})
+	}
+	return sources
+}
+
+// sourceFlag collects the -source flags, which configure additional named sources,
+// like a second Nomad cluster.
+//
+// Each flag has the form '<name> [-disabled] <flags>', where the flags are those of a single source type,
+// like '-nomad-addr https://eu:4646 -nomad-token-file /etc/nomad-eu.token'. They are split like shell words.
+// The flags default like the top level ones, including the Nomad settings left empty, see nomadEnvDefaults.
+type sourceFlag struct {
+	sources *[]runner.SourceConfig
+}
+
+func (f sourceFlag) String() string {
+	if f.sources == nil {
+		return ""
+	}
+
+	var names []string
+	for _, s := range *f.sources {
+		names = append(names, s.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+func (f sourceFlag) Set(value string) error {
+	args, err := splitWords(value)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("expected <name> <flags>, got %q", value)
+	}
+
+	name := args[0]
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var c sourceFlags
+	c.register(fs)
+	disabled := fs.Bool("disabled", false, "")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("source %s: %w", name, err)
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("source %s: unexpected argument %q", name, fs.Arg(0))
+	}
+
+	// The type is decided by the flags that are given, as the defaults may come from the environment.
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		prefix, _, _ := strings.Cut(f.Name, "-")
+		given[prefix] = true
+	})
+
+	source := runner.SourceConfig{Name: name, Disabled: *disabled}
+	types := 0
+	if given["nomad"] {
+		nomadEnvDefaults(&c.nomad)
+		source.Nomad, types = &c.nomad, types+1
+	}
+	if given["docker"] {
+		source.Docker, types = &c.docker, types+1
+	}
+	if given["poll"] {
+		source.Poll, types = &c.poll, types+1
+	}
+	if given["prom"] {
+		source.Prometheus, types = &c.prometheus, types+1
+	}
+	if given["plugin"] {
+		source.Plugin, types = &c.plugin, types+1
+	}
+	if types != 1 {
+		return fmt.Errorf("source %s: expected the flags of exactly one source type", name)
+	}
+
+	*f.sources = append(*f.sources, source)
+	return nil
+}
+
+// splitWords splits s into words like a shell, supporting single and double quotes and backslash escapes.
+func splitWords(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"overseer/runner"
+)
+
+func TestSourceFlagNomadEnv(t *testing.T) {
+	t.Setenv("NOMAD_TOKEN", "env-token")
+	t.Setenv("NOMAD_NAMESPACE", "web,api")
+	t.Setenv("NOMAD_REGION", "eu")
+
+	var sources []runner.SourceConfig
+	f := sourceFlag{&sources}
+	if err := f.Set("nomad-eu -nomad-addr https://eu:4646"); err != nil {
+		t.Fatal(err)
+	}
+	// The flags take precedence over the environment.
+	if err := f.Set("nomad-us -nomad-addr https://us:4646 -nomad-namespace '*' -nomad-region us-east,us-west"); err != nil {
+		t.Fatal(err)
+	}
+
+	eu, us := sources[0].Nomad, sources[1].Nomad
+	if eu.Token != "env-token" || !slices.Equal(eu.Namespaces, []string{"web", "api"}) || !slices.Equal(eu.Regions, []string{"eu"}) {
+		t.Fatalf("expected the token, namespaces and regions of the environment, got %+v", eu)
+	}
+	if !slices.Equal(us.Namespaces, []string{"*"}) || !slices.Equal(us.Regions, []string{"us-east", "us-west"}) {
+		t.Fatalf("expected the namespaces and regions of the flags, got %+v", us)
+	}
+}
@@ -0,0 +1,933 @@
+// Package memory implements app.Store in memory, for tests and for running Overseer without a database.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"overseer/app"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Store struct {
+	mu sync.Mutex
+
+	environments []app.Environment
+	applications []app.Application
+	instances    []app.Instance
+	deployments  []app.Deployment
+	// current holds the current deployment of every component of every instance.
+	current map[componentKey]app.Deployment
+	// rollouts holds the rollout of every component of every instance.
+	rollouts map[componentKey]app.Rollout
+	// releases holds the latest release of every application.
+	releases map[int32]app.Release
+	// promotions holds the edges of the promotion graph.
+	promotions []app.PromotionEdge
+	// policies holds the version policies of the applications and instances.
+	policies   []app.VersionPolicy
+	violations []app.PolicyViolation
+	freezes    []app.FreezeWindow
+	webhooks   []app.Webhook
+	deliveries []app.WebhookDelivery
+	channels   []app.NotificationChannel
+
+	lastEnvironmentId int32
+	lastApplicationId int32
+	lastInstanceId    int32
+	lastFreezeId      int32
+	lastWebhookId     int32
+	lastChannelId     int32
+}
+
+var _ app.Store = (*Store)(nil)
+
+type componentKey struct {
+	instanceId int32
+	component  string
+}
+
+func New() *Store {
+	return &Store{
+		current:  map[componentKey]app.Deployment{},
+		rollouts: map[componentKey]app.Rollout{},
+		releases: map[int32]app.Release{},
+	}
+}
+
+func (s *Store) ListApplications(ctx context.Context) ([]app.Application, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := slices.Clone(s.applications)
+	slices.SortFunc(result, func(a, b app.Application) int {
+		return cmp.Or(cmp.Compare(a.Order, b.Order), cmp.Compare(a.Id, b.Id))
+	})
+	return result, nil
+}
+
+func (s *Store) GetApplication(ctx context.Context, id int32) (app.Application, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.applications, func(a app.Application) bool { return a.Id == id })
+	if idx == -1 {
+		return app.Application{}, app.ErrNotFound
+	}
+	return s.applications[idx], nil
+}
+
+func (s *Store) CreateApplication(ctx context.Context, name string) (app.Application, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var order int32
+	for _, a := range s.applications {
+		if a.Name == name {
+			return app.Application{}, fmt.Errorf("%w: application %q", app.ErrAlreadyExists, name)
+		}
+		order = max(order, a.Order)
+	}
+
+	s.lastApplicationId++
+	a := app.Application{
+		Id:    s.lastApplicationId,
+		Name:  name,
+		Order: order + 1,
+	}
+	s.applications = append(s.applications, a)
+	return a, nil
+}
+
+func (s *Store) UpdateApplication(ctx context.Context, id int32, name string) (app.Application, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.applications, func(a app.Application) bool { return a.Id == id })
+	if idx == -1 {
+		return app.Application{}, app.ErrNotFound
+	}
+
+	if slices.ContainsFunc(s.applications, func(a app.Application) bool { return a.Name == name && a.Id != id }) {
+		return app.Application{}, fmt.Errorf("%w: application %q", app.ErrAlreadyExists, name)
+	}
+
+	s.applications[idx].Name = name
+	return s.applications[idx], nil
+}
+
+func (s *Store) DeleteApplication(ctx context.Context, id int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.applications = slices.DeleteFunc(s.applications, func(a app.Application) bool { return a.Id == id })
+	s.deleteInstancesLocked(func(i app.Instance) bool { return i.ApplicationId == id })
+	delete(s.releases, id)
+	s.policies = slices.DeleteFunc(s.policies, func(p app.VersionPolicy) bool { return p.ApplicationId == id })
+	s.freezes = slices.DeleteFunc(s.freezes, func(w app.FreezeWindow) bool { return w.ApplicationId == id })
+	s.deleteWebhooksLocked(func(w app.Webhook) bool { return w.ApplicationId == id })
+	s.deleteNotificationRoutesLocked(func(r app.NotificationRoute) bool { return r.ApplicationId == id })
+	return nil
+}
+
+func (s *Store) ReorderApplications(ctx context.Context, ids []int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.applications {
+		if idx := slices.Index(ids, s.applications[i].Id); idx != -1 {
+			s.applications[i].Order = int32(idx + 1)
+		}
+	}
+	return nil
+}
+
+func (s *Store) ListEnvironments(ctx context.Context) ([]app.Environment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := slices.Clone(s.environments)
+	slices.SortFunc(result, func(a, b app.Environment) int {
+		return cmp.Or(cmp.Compare(a.Order, b.Order), cmp.Compare(a.Id, b.Id))
+	})
+	return result, nil
+}
+
+func (s *Store) GetEnvironment(ctx context.Context, id int32) (app.Environment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.environments, func(e app.Environment) bool { return e.Id == id })
+	if idx == -1 {
+		return app.Environment{}, app.ErrNotFound
+	}
+	return s.environments[idx], nil
+}
+
+func (s *Store) CreateEnvironment(ctx context.Context, name string) (app.Environment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var order int32
+	for _, e := range s.environments {
+		if e.Name == name {
+			return app.Environment{}, fmt.Errorf("%w: environment %q", app.ErrAlreadyExists, name)
+		}
+		order = max(order, e.Order)
+	}
+
+	s.lastEnvironmentId++
+	e := app.Environment{
+		Id:    s.lastEnvironmentId,
+		Name:  name,
+		Order: order + 1,
+	}
+	s.environments = append(s.environments, e)
+	return e, nil
+}
+
+func (s *Store) UpdateEnvironment(ctx context.Context, id int32, name string) (app.Environment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.environments, func(e app.Environment) bool { return e.Id == id })
+	if idx == -1 {
+		return app.Environment{}, app.ErrNotFound
+	}
+
+	if slices.ContainsFunc(s.environments, func(e app.Environment) bool { return e.Name == name && e.Id != id }) {
+		return app.Environment{}, fmt.Errorf("%w: environment %q", app.ErrAlreadyExists, name)
+	}
+
+	s.environments[idx].Name = name
+	return s.environments[idx], nil
+}
+
+func (s *Store) DeleteEnvironment(ctx context.Context, id int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.environments = slices.DeleteFunc(s.environments, func(e app.Environment) bool { return e.Id == id })
+	s.deleteInstancesLocked(func(i app.Instance) bool { return i.EnvironmentId == id })
+	s.promotions = slices.DeleteFunc(s.promotions, func(p app.PromotionEdge) bool {
+		return p.FromEnvironmentId == id || p.ToEnvironmentId == id
+	})
+	s.freezes = slices.DeleteFunc(s.freezes, func(w app.FreezeWindow) bool { return w.EnvironmentId == id })
+	s.deleteWebhooksLocked(func(w app.Webhook) bool { return w.EnvironmentId == id })
+	s.deleteNotificationRoutesLocked(func(r app.NotificationRoute) bool { return r.EnvironmentId == id })
+	return nil
+}
+
+func (s *Store) ReorderEnvironments(ctx context.Context, ids []int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.environments {
+		if idx := slices.Index(ids, s.environments[i].Id); idx != -1 {
+			s.environments[i].Order = int32(idx + 1)
+		}
+	}
+	return nil
+}
+
+func (s *Store) CreateInstance(ctx context.Context, params app.CreateInstanceParameters) (int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !slices.ContainsFunc(s.environments, func(e app.Environment) bool { return e.Id == params.EnvironmentId }) {
+		return 0, fmt.Errorf("%w: environment %d", app.ErrNotFound, params.EnvironmentId)
+	}
+
+	if !slices.ContainsFunc(s.applications, func(a app.Application) bool { return a.Id == params.ApplicationId }) {
+		return 0, fmt.Errorf("%w: application %d", app.ErrNotFound, params.ApplicationId)
+	}
+
+	for _, i := range s.instances {
+		if i.Name == params.Name {
+			return 0, fmt.Errorf("%w: instance %q", app.ErrAlreadyExists, params.Name)
+		}
+		if i.EnvironmentId == params.EnvironmentId && i.ApplicationId == params.ApplicationId {
+			return 0, fmt.Errorf("%w: instance of application %d in environment %d", app.ErrAlreadyExists, params.ApplicationId, params.EnvironmentId)
+		}
+	}
+
+	s.lastInstanceId++
+	s.instances = append(s.instances, app.Instance{
+		Id:               s.lastInstanceId,
+		EnvironmentId:    params.EnvironmentId,
+		ApplicationId:    params.ApplicationId,
+		Name:             params.Name,
+		PrimaryComponent: params.PrimaryComponent,
+	})
+	return s.lastInstanceId, nil
+}
+
+func (s *Store) UpdateInstance(ctx context.Context, params app.UpdateInstanceParameters) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.instances, func(i app.Instance) bool { return i.Id == params.Id })
+	if idx == -1 {
+		return app.ErrNotFound
+	}
+
+	if slices.ContainsFunc(s.instances, func(i app.Instance) bool { return i.Name == params.Name && i.Id != params.Id }) {
+		return fmt.Errorf("%w: instance %q", app.ErrAlreadyExists, params.Name)
+	}
+
+	s.instances[idx].Name = params.Name
+	s.instances[idx].PrimaryComponent = params.PrimaryComponent
+	return nil
+}
+
+func (s *Store) ListInstances(ctx context.Context, name string) ([]app.Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []app.Instance
+	for _, i := range s.instances {
+		if name == "" || i.Name == name {
+			result = append(result, i)
+		}
+	}
+	return result, nil
+}
+
+func (s *Store) GetInstance(ctx context.Context, id int32) (app.Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.instances, func(i app.Instance) bool { return i.Id == id })
+	if idx == -1 {
+		return app.Instance{}, app.ErrNotFound
+	}
+	return s.instances[idx], nil
+}
+
+func (s *Store) DeleteInstance(ctx context.Context, id int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteInstancesLocked(func(i app.Instance) bool { return i.Id == id })
+	return nil
+}
+
+// deleteInstancesLocked deletes the matching instances and their deployments and rollouts.
+func (s *Store) deleteInstancesLocked(match func(app.Instance) bool) {
+	deleted := map[int32]bool{}
+	s.instances = slices.DeleteFunc(s.instances, func(i app.Instance) bool {
+		if match(i) {
+			deleted[i.Id] = true
+			return true
+		}
+		return false
+	})
+
+	s.deployments = slices.DeleteFunc(s.deployments, func(d app.Deployment) bool { return deleted[d.InstanceId] })
+	maps.DeleteFunc(s.current, func(k componentKey, _ app.Deployment) bool { return deleted[k.instanceId] })
+	maps.DeleteFunc(s.rollouts, func(k componentKey, _ app.Rollout) bool { return deleted[k.instanceId] })
+	s.policies = slices.DeleteFunc(s.policies, func(p app.VersionPolicy) bool { return deleted[p.InstanceId] })
+	s.violations = slices.DeleteFunc(s.violations, func(v app.PolicyViolation) bool { return deleted[v.InstanceId] })
+}
+
+func (s *Store) ListInstancesAndDeployment(ctx context.Context) ([]app.InstanceAndDeploymentResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []app.InstanceAndDeploymentResult
+	for _, i := range s.instances {
+		r := app.InstanceAndDeploymentResult{Instance: i}
+		if d, ok := s.current[componentKey{i.Id, i.PrimaryComponent}]; ok {
+			r.Deployment = &d
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func (s *Store) ListDeployments(ctx context.Context) ([]app.Deployment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := slices.Clone(s.deployments)
+	slices.SortStableFunc(result, func(a, b app.Deployment) int {
+		return cmp.Or(cmp.Compare(a.InstanceId, b.InstanceId), cmp.Compare(a.Component, b.Component), a.DeployedAt.Compare(b.DeployedAt))
+	})
+	return result, nil
+}
+
+func (s *Store) ListInstanceDeployments(ctx context.Context, instanceId int32) ([]app.Deployment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []app.Deployment
+	for _, d := range s.deployments {
+		if d.InstanceId == instanceId {
+			result = append(result, d)
+		}
+	}
+	slices.SortStableFunc(result, func(a, b app.Deployment) int {
+		return cmp.Or(cmp.Compare(a.Component, b.Component), a.DeployedAt.Compare(b.DeployedAt))
+	})
+	return result, nil
+}
+
+func (s *Store) ListDeployedVersions(ctx context.Context, instanceId int32, component string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var versions []string
+	for _, d := range s.deployments {
+		if d.InstanceId == instanceId && d.Component == component && !d.Undeployed && !slices.Contains(versions, d.Version) {
+			versions = append(versions, d.Version)
+		}
+	}
+	slices.Sort(versions)
+	return versions, nil
+}
+
+func (s *Store) ListCurrentDeployments(ctx context.Context) ([]app.Deployment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := slices.Collect(maps.Values(s.current))
+	slices.SortFunc(result, func(a, b app.Deployment) int {
+		return cmp.Or(cmp.Compare(a.InstanceId, b.InstanceId), cmp.Compare(a.Component, b.Component))
+	})
+	return result, nil
+}
+
+func (s *Store) RegisterDeployment(ctx context.Context, d app.Deployment) (app.Deployment, app.Deployment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !slices.ContainsFunc(s.instances, func(i app.Instance) bool { return i.Id == d.InstanceId }) {
+		return app.Deployment{}, app.Deployment{}, fmt.Errorf("%w: instance %d", app.ErrNotFound, d.InstanceId)
+	}
+
+	d.Id = uuid.NewString()
+	d.Metadata.Labels = maps.Clone(d.Metadata.Labels)
+	key := componentKey{d.InstanceId, d.Component}
+	cur, ok := s.current[key]
+	if ok && !d.Supersedes(cur) {
+		d.Late = true
+		cur = app.Deployment{}
+	} else {
+		s.current[key] = d
+	}
+	s.deployments = append(s.deployments, d)
+	return d, cur, nil
+}
+
+func (s *Store) DeleteDeployments(ctx context.Context, ids []string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	remove := map[string]bool{}
+	for _, id := range ids {
+		remove[id] = true
+	}
+
+	var deleted int64
+	s.deployments = slices.DeleteFunc(s.deployments, func(d app.Deployment) bool {
+		if !remove[d.Id] || s.current[componentKey{d.InstanceId, d.Component}].Id == d.Id {
+			return false
+		}
+		deleted++
+		return true
+	})
+	s.violations = slices.DeleteFunc(s.violations, func(v app.PolicyViolation) bool {
+		return !slices.ContainsFunc(s.deployments, func(d app.Deployment) bool { return d.Id == v.DeploymentId })
+	})
+	return deleted, nil
+}
+
+func (s *Store) ListRollouts(ctx context.Context) ([]app.Rollout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := slices.Collect(maps.Values(s.rollouts))
+	slices.SortFunc(result, func(a, b app.Rollout) int {
+		return cmp.Or(cmp.Compare(a.InstanceId, b.InstanceId), cmp.Compare(a.Component, b.Component))
+	})
+	return result, nil
+}
+
+func (s *Store) UpdateRollout(ctx context.Context, r app.Rollout) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !slices.ContainsFunc(s.instances, func(i app.Instance) bool { return i.Id == r.InstanceId }) {
+		return false, fmt.Errorf("%w: instance %d", app.ErrNotFound, r.InstanceId)
+	}
+
+	key := componentKey{r.InstanceId, r.Component}
+	cur, ok := s.rollouts[key]
+	if ok && !r.Supersedes(cur) {
+		return false, nil
+	}
+	if r.RunningVersion == "" {
+		r.RunningVersion = cur.RunningVersion
+	}
+	s.rollouts[key] = r
+	return true, nil
+}
+
+func (s *Store) ListReleases(ctx context.Context) ([]app.Release, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := slices.Collect(maps.Values(s.releases))
+	slices.SortFunc(result, func(a, b app.Release) int {
+		return cmp.Compare(a.ApplicationId, b.ApplicationId)
+	})
+	return result, nil
+}
+
+func (s *Store) SaveRelease(ctx context.Context, r app.Release) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !slices.ContainsFunc(s.applications, func(a app.Application) bool { return a.Id == r.ApplicationId }) {
+		return fmt.Errorf("%w: application %d", app.ErrNotFound, r.ApplicationId)
+	}
+
+	s.releases[r.ApplicationId] = r
+	return nil
+}
+
+func (s *Store) ListPromotions(ctx context.Context) ([]app.PromotionEdge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := slices.Clone(s.promotions)
+	slices.SortFunc(result, func(a, b app.PromotionEdge) int {
+		return cmp.Or(cmp.Compare(a.FromEnvironmentId, b.FromEnvironmentId), cmp.Compare(a.ToEnvironmentId, b.ToEnvironmentId))
+	})
+	return result, nil
+}
+
+func (s *Store) SetPromotionSources(ctx context.Context, environmentId int32, sourceIds []int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range append([]int32{environmentId}, sourceIds...) {
+		if !slices.ContainsFunc(s.environments, func(e app.Environment) bool { return e.Id == id }) {
+			return fmt.Errorf("%w: environment %d", app.ErrNotFound, id)
+		}
+	}
+
+	s.promotions = slices.DeleteFunc(s.promotions, func(p app.PromotionEdge) bool { return p.ToEnvironmentId == environmentId })
+	for _, id := range sourceIds {
+		edge := app.PromotionEdge{FromEnvironmentId: id, ToEnvironmentId: environmentId}
+		if !slices.Contains(s.promotions, edge) {
+			s.promotions = append(s.promotions, edge)
+		}
+	}
+	return nil
+}
+
+func (s *Store) ListVersionPolicies(ctx context.Context) ([]app.VersionPolicy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := slices.Clone(s.policies)
+	slices.SortFunc(result, func(a, b app.VersionPolicy) int {
+		return cmp.Or(cmp.Compare(a.ApplicationId, b.ApplicationId), cmp.Compare(a.InstanceId, b.InstanceId))
+	})
+	return result, nil
+}
+
+func (s *Store) SaveVersionPolicy(ctx context.Context, p app.VersionPolicy) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p.InstanceId != 0 {
+		idx := slices.IndexFunc(s.instances, func(i app.Instance) bool { return i.Id == p.InstanceId })
+		if idx == -1 {
+			return fmt.Errorf("%w: instance %d", app.ErrNotFound, p.InstanceId)
+		}
+		p.ApplicationId = s.instances[idx].ApplicationId
+	} else if !slices.ContainsFunc(s.applications, func(a app.Application) bool { return a.Id == p.ApplicationId }) {
+		return fmt.Errorf("%w: application %d", app.ErrNotFound, p.ApplicationId)
+	}
+
+	p.Forbidden = slices.Clone(p.Forbidden)
+	s.policies = slices.DeleteFunc(s.policies, func(existing app.VersionPolicy) bool {
+		return existing.ApplicationId == p.ApplicationId && existing.InstanceId == p.InstanceId
+	})
+	s.policies = append(s.policies, p)
+	return nil
+}
+
+func (s *Store) DeleteVersionPolicy(ctx context.Context, applicationId, instanceId int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.policies = slices.DeleteFunc(s.policies, func(p app.VersionPolicy) bool {
+		if instanceId != 0 {
+			return p.InstanceId == instanceId
+		}
+		return p.InstanceId == 0 && p.ApplicationId == applicationId
+	})
+	return nil
+}
+
+func (s *Store) ListPolicyViolations(ctx context.Context) ([]app.PolicyViolation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := slices.Clone(s.violations)
+	slices.SortStableFunc(result, func(a, b app.PolicyViolation) int {
+		return cmp.Or(a.DetectedAt.Compare(b.DetectedAt), cmp.Compare(a.DeploymentId, b.DeploymentId), cmp.Compare(a.Rule, b.Rule))
+	})
+	return result, nil
+}
+
+func (s *Store) AddPolicyViolations(ctx context.Context, violations []app.PolicyViolation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range violations {
+		if !slices.ContainsFunc(s.deployments, func(d app.Deployment) bool { return d.Id == v.DeploymentId && d.InstanceId == v.InstanceId }) {
+			return fmt.Errorf("%w: deployment %s", app.ErrNotFound, v.DeploymentId)
+		}
+	}
+
+	for _, v := range violations {
+		if !slices.ContainsFunc(s.violations, func(existing app.PolicyViolation) bool {
+			return existing.DeploymentId == v.DeploymentId && existing.Rule == v.Rule
+		}) {
+			s.violations = append(s.violations, v)
+		}
+	}
+	return nil
+}
+
+func (s *Store) ListFreezeWindows(ctx context.Context) ([]app.FreezeWindow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.freezes), nil
+}
+
+func (s *Store) CreateFreezeWindow(ctx context.Context, w app.FreezeWindow) (int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkFreezeWindowLocked(w); err != nil {
+		return 0, err
+	}
+
+	s.lastFreezeId++
+	w.Id = s.lastFreezeId
+	s.freezes = append(s.freezes, w)
+	return w.Id, nil
+}
+
+func (s *Store) UpdateFreezeWindow(ctx context.Context, w app.FreezeWindow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.freezes, func(f app.FreezeWindow) bool { return f.Id == w.Id })
+	if idx == -1 {
+		return fmt.Errorf("%w: freeze window %d", app.ErrNotFound, w.Id)
+	}
+
+	if err := s.checkFreezeWindowLocked(w); err != nil {
+		return err
+	}
+
+	s.freezes[idx] = w
+	return nil
+}
+
+// checkFreezeWindowLocked checks that the name of the window is unique and that its environment and application exist.
+func (s *Store) checkFreezeWindowLocked(w app.FreezeWindow) error {
+	if slices.ContainsFunc(s.freezes, func(f app.FreezeWindow) bool { return f.Name == w.Name && f.Id != w.Id }) {
+		return fmt.Errorf("%w: freeze window %q", app.ErrAlreadyExists, w.Name)
+	}
+	if w.EnvironmentId != 0 && !slices.ContainsFunc(s.environments, func(e app.Environment) bool { return e.Id == w.EnvironmentId }) {
+		return fmt.Errorf("%w: environment %d", app.ErrNotFound, w.EnvironmentId)
+	}
+	if w.ApplicationId != 0 && !slices.ContainsFunc(s.applications, func(a app.Application) bool { return a.Id == w.ApplicationId }) {
+		return fmt.Errorf("%w: application %d", app.ErrNotFound, w.ApplicationId)
+	}
+	return nil
+}
+
+func (s *Store) DeleteFreezeWindow(ctx context.Context, id int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.freezes = slices.DeleteFunc(s.freezes, func(w app.FreezeWindow) bool { return w.Id == id })
+	return nil
+}
+
+func (s *Store) ListWebhooks(ctx context.Context) ([]app.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := slices.Clone(s.webhooks)
+	for i := range result {
+		result[i].Events = slices.Clone(result[i].Events)
+	}
+	return result, nil
+}
+
+func (s *Store) CreateWebhook(ctx context.Context, w app.Webhook) (int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkWebhookLocked(w); err != nil {
+		return 0, err
+	}
+
+	s.lastWebhookId++
+	w.Id = s.lastWebhookId
+	w.Events = slices.Clone(w.Events)
+	s.webhooks = append(s.webhooks, w)
+	return w.Id, nil
+}
+
+func (s *Store) UpdateWebhook(ctx context.Context, w app.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.webhooks, func(h app.Webhook) bool { return h.Id == w.Id })
+	if idx == -1 {
+		return fmt.Errorf("%w: webhook %d", app.ErrNotFound, w.Id)
+	}
+
+	if err := s.checkWebhookLocked(w); err != nil {
+		return err
+	}
+
+	w.CreatedAt = s.webhooks[idx].CreatedAt
+	w.Events = slices.Clone(w.Events)
+	s.webhooks[idx] = w
+	return nil
+}
+
+// checkWebhookLocked checks that the name of the webhook is unique and that its environment and application exist.
+func (s *Store) checkWebhookLocked(w app.Webhook) error {
+	if slices.ContainsFunc(s.webhooks, func(h app.Webhook) bool { return h.Name == w.Name && h.Id != w.Id }) {
+		return fmt.Errorf("%w: webhook %q", app.ErrAlreadyExists, w.Name)
+	}
+	if w.EnvironmentId != 0 && !slices.ContainsFunc(s.environments, func(e app.Environment) bool { return e.Id == w.EnvironmentId }) {
+		return fmt.Errorf("%w: environment %d", app.ErrNotFound, w.EnvironmentId)
+	}
+	if w.ApplicationId != 0 && !slices.ContainsFunc(s.applications, func(a app.Application) bool { return a.Id == w.ApplicationId }) {
+		return fmt.Errorf("%w: application %d", app.ErrNotFound, w.ApplicationId)
+	}
+	return nil
+}
+
+func (s *Store) DeleteWebhook(ctx context.Context, id int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteWebhooksLocked(func(w app.Webhook) bool { return w.Id == id })
+	return nil
+}
+
+// deleteWebhooksLocked deletes the webhooks matching del together with their deliveries.
+func (s *Store) deleteWebhooksLocked(del func(app.Webhook) bool) {
+	deleted := map[int32]bool{}
+	s.webhooks = slices.DeleteFunc(s.webhooks, func(w app.Webhook) bool {
+		if del(w) {
+			deleted[w.Id] = true
+			return true
+		}
+		return false
+	})
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(d app.WebhookDelivery) bool { return deleted[d.WebhookId] })
+}
+
+func (s *Store) AddWebhookDeliveries(ctx context.Context, deliveries []app.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, d := range deliveries {
+		if !slices.ContainsFunc(s.webhooks, func(w app.Webhook) bool { return w.Id == d.WebhookId }) {
+			return fmt.Errorf("%w: webhook %d", app.ErrNotFound, d.WebhookId)
+		}
+		if slices.ContainsFunc(s.deliveries, func(existing app.WebhookDelivery) bool { return existing.Id == d.Id }) {
+			return fmt.Errorf("%w: webhook delivery %q", app.ErrAlreadyExists, d.Id)
+		}
+	}
+
+	for _, d := range deliveries {
+		d.Payload = slices.Clone(d.Payload)
+		s.deliveries = append(s.deliveries, d)
+	}
+	return nil
+}
+
+func (s *Store) GetWebhookDelivery(ctx context.Context, id string) (app.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.deliveries, func(d app.WebhookDelivery) bool { return d.Id == id })
+	if idx == -1 {
+		return app.WebhookDelivery{}, fmt.Errorf("%w: webhook delivery %q", app.ErrNotFound, id)
+	}
+	return s.deliveries[idx], nil
+}
+
+func (s *Store) ListWebhookDeliveries(ctx context.Context, webhookId int32, limit int32) ([]app.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []app.WebhookDelivery
+	for _, d := range s.deliveries {
+		if webhookId == 0 || d.WebhookId == webhookId {
+			result = append(result, d)
+		}
+	}
+	slices.SortFunc(result, func(a, b app.WebhookDelivery) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.Id, b.Id))
+	})
+	if len(result) > int(limit) {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (s *Store) ListDueWebhookDeliveries(ctx context.Context, t time.Time) ([]app.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []app.WebhookDelivery
+	for _, d := range s.deliveries {
+		if d.Status == app.DeliveryPending && !d.NextAttemptAt.After(t) {
+			result = append(result, d)
+		}
+	}
+	slices.SortFunc(result, func(a, b app.WebhookDelivery) int {
+		return cmp.Or(a.NextAttemptAt.Compare(b.NextAttemptAt), a.CreatedAt.Compare(b.CreatedAt))
+	})
+	return result, nil
+}
+
+func (s *Store) UpdateWebhookDelivery(ctx context.Context, d app.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.deliveries, func(existing app.WebhookDelivery) bool { return existing.Id == d.Id })
+	if idx == -1 {
+		return fmt.Errorf("%w: webhook delivery %q", app.ErrNotFound, d.Id)
+	}
+
+	existing := &s.deliveries[idx]
+	existing.Status = d.Status
+	existing.Attempts = d.Attempts
+	existing.ResponseStatus = d.ResponseStatus
+	existing.Error = d.Error
+	existing.NextAttemptAt = d.NextAttemptAt
+	existing.LastAttemptAt = d.LastAttemptAt
+	return nil
+}
+
+func (s *Store) DeleteWebhookDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.deliveries)
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(d app.WebhookDelivery) bool {
+		return d.Status != app.DeliveryPending && d.CreatedAt.Before(before)
+	})
+	return int64(n - len(s.deliveries)), nil
+}
+
+func (s *Store) ListNotificationChannels(ctx context.Context) ([]app.NotificationChannel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []app.NotificationChannel
+	for _, c := range s.channels {
+		result = append(result, cloneNotificationChannel(c))
+	}
+	return result, nil
+}
+
+func (s *Store) CreateNotificationChannel(ctx context.Context, c app.NotificationChannel) (int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkNotificationChannelLocked(c); err != nil {
+		return 0, err
+	}
+
+	s.lastChannelId++
+	c.Id = s.lastChannelId
+	s.channels = append(s.channels, cloneNotificationChannel(c))
+	return c.Id, nil
+}
+
+func (s *Store) UpdateNotificationChannel(ctx context.Context, c app.NotificationChannel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.channels, func(existing app.NotificationChannel) bool { return existing.Id == c.Id })
+	if idx == -1 {
+		return fmt.Errorf("%w: notification channel %d", app.ErrNotFound, c.Id)
+	}
+
+	if err := s.checkNotificationChannelLocked(c); err != nil {
+		return err
+	}
+
+	s.channels[idx] = cloneNotificationChannel(c)
+	return nil
+}
+
+// checkNotificationChannelLocked checks that the name of the channel is unique
+// and that the environments and applications of its routes exist.
+func (s *Store) checkNotificationChannelLocked(c app.NotificationChannel) error {
+	if slices.ContainsFunc(s.channels, func(existing app.NotificationChannel) bool { return existing.Name == c.Name && existing.Id != c.Id }) {
+		return fmt.Errorf("%w: notification channel %q", app.ErrAlreadyExists, c.Name)
+	}
+	for _, r := range c.Routes {
+		if r.EnvironmentId != 0 && !slices.ContainsFunc(s.environments, func(e app.Environment) bool { return e.Id == r.EnvironmentId }) {
+			return fmt.Errorf("%w: environment %d", app.ErrNotFound, r.EnvironmentId)
+		}
+		if r.ApplicationId != 0 && !slices.ContainsFunc(s.applications, func(a app.Application) bool { return a.Id == r.ApplicationId }) {
+			return fmt.Errorf("%w: application %d", app.ErrNotFound, r.ApplicationId)
+		}
+	}
+	return nil
+}
+
+func (s *Store) DeleteNotificationChannel(ctx context.Context, id int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.channels = slices.DeleteFunc(s.channels, func(c app.NotificationChannel) bool { return c.Id == id })
+	return nil
+}
+
+// deleteNotificationRoutesLocked deletes the routes matching del from all channels.
+func (s *Store) deleteNotificationRoutesLocked(del func(app.NotificationRoute) bool) {
+	for i := range s.channels {
+		s.channels[i].Routes = slices.DeleteFunc(s.channels[i].Routes, del)
+		if len(s.channels[i].Routes) == 0 {
+			s.channels[i].Routes = nil
+		}
+	}
+}
+
+func cloneNotificationChannel(c app.NotificationChannel) app.NotificationChannel {
+	routes := c.Routes
+	c.Routes = nil
+	for _, r := range routes {
+		r.Events = slices.Clone(r.Events)
+		c.Routes = append(c.Routes, r)
+	}
+	return c
+}
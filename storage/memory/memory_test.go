@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"overseer/app"
+	"overseer/storage/memory"
+	"overseer/storage/storetest"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) app.Store {
+		return memory.New()
+	})
+}
@@ -0,0 +1,1090 @@
+// Package postgres implements app.Store on top of the sqlc generated queries in the repo package.
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"overseer/app"
+	"overseer/repo"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Store struct {
+	pool *pgxpool.Pool
+	q    *repo.Queries
+}
+
+var _ app.Store = (*Store)(nil)
+
+func New(pool *pgxpool.Pool) *Store {
+	return &Store{
+		pool: pool,
+		q:    repo.New(pool),
+	}
+}
+
+// encodeLabels encodes deployment labels as the JSON object stored in the labels column.
+func encodeLabels(labels map[string]string) ([]byte, error) {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	return json.Marshal(labels)
+}
+
+// decodeLabels decodes the labels column, returning nil if there are no labels.
+func decodeLabels(data []byte) map[string]string {
+	var labels map[string]string
+	if err := json.Unmarshal(data, &labels); err != nil || len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// encodeStrings encodes a list, like the forbidden versions of a policy or the events of a webhook, as a JSON array.
+func encodeStrings(list []string) ([]byte, error) {
+	if list == nil {
+		list = []string{}
+	}
+	return json.Marshal(list)
+}
+
+// decodeStrings decodes a list encoded by encodeStrings, returning nil if it is empty.
+func decodeStrings(data []byte) []string {
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil || len(list) == 0 {
+		return nil
+	}
+	return list
+}
+
+// mapError translates database errors into the errors defined by the app package.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return app.ErrNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			return fmt.Errorf("%w: %s", app.ErrAlreadyExists, pgErr.Detail)
+		case "23503": // foreign_key_violation
+			return fmt.Errorf("%w: %s", app.ErrNotFound, pgErr.Detail)
+		}
+	}
+
+	return err
+}
+
+func (s *Store) ListApplications(ctx context.Context) ([]app.Application, error) {
+	apps, err := s.q.ListApplications(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Application
+	for _, a := range apps {
+		result = append(result, app.Application{
+			Id:    a.ID,
+			Name:  a.Name,
+			Order: a.SortOrder,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) GetApplication(ctx context.Context, id int32) (app.Application, error) {
+	a, err := s.q.GetApplication(ctx, id)
+	if err != nil {
+		return app.Application{}, mapError(err)
+	}
+	return app.Application{Id: a.ID, Name: a.Name, Order: a.SortOrder}, nil
+}
+
+func (s *Store) CreateApplication(ctx context.Context, name string) (app.Application, error) {
+	a, err := s.q.CreateApplication(ctx, name)
+	if err != nil {
+		return app.Application{}, mapError(err)
+	}
+	return app.Application{
+		Id:    a.ID,
+		Name:  a.Name,
+		Order: a.SortOrder,
+	}, nil
+}
+
+func (s *Store) UpdateApplication(ctx context.Context, id int32, name string) (app.Application, error) {
+	a, err := s.q.UpdateApplication(ctx, repo.UpdateApplicationParams{
+		ID:   id,
+		Name: name,
+	})
+	if err != nil {
+		return app.Application{}, mapError(err)
+	}
+	return app.Application{
+		Id:    a.ID,
+		Name:  a.Name,
+		Order: a.SortOrder,
+	}, nil
+}
+
+func (s *Store) DeleteApplication(ctx context.Context, id int32) error {
+	return mapError(s.q.DeleteApplication(ctx, id))
+}
+
+func (s *Store) ReorderApplications(ctx context.Context, ids []int32) error {
+	return mapError(s.q.ReorderApplications(ctx, ids))
+}
+
+func (s *Store) ListEnvironments(ctx context.Context) ([]app.Environment, error) {
+	envs, err := s.q.ListEnvironments(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Environment
+	for _, e := range envs {
+		result = append(result, app.Environment{
+			Id:    e.ID,
+			Name:  e.Name,
+			Order: e.SortOrder,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) GetEnvironment(ctx context.Context, id int32) (app.Environment, error) {
+	e, err := s.q.GetEnvironment(ctx, id)
+	if err != nil {
+		return app.Environment{}, mapError(err)
+	}
+	return app.Environment{Id: e.ID, Name: e.Name, Order: e.SortOrder}, nil
+}
+
+func (s *Store) CreateEnvironment(ctx context.Context, name string) (app.Environment, error) {
+	e, err := s.q.CreateEnvironment(ctx, name)
+	if err != nil {
+		return app.Environment{}, mapError(err)
+	}
+	return app.Environment{
+		Id:    e.ID,
+		Name:  e.Name,
+		Order: e.SortOrder,
+	}, nil
+}
+
+func (s *Store) UpdateEnvironment(ctx context.Context, id int32, name string) (app.Environment, error) {
+	e, err := s.q.UpdateEnvironment(ctx, repo.UpdateEnvironmentParams{
+		ID:   id,
+		Name: name,
+	})
+	if err != nil {
+		return app.Environment{}, mapError(err)
+	}
+	return app.Environment{
+		Id:    e.ID,
+		Name:  e.Name,
+		Order: e.SortOrder,
+	}, nil
+}
+
+func (s *Store) DeleteEnvironment(ctx context.Context, id int32) error {
+	return mapError(s.q.DeleteEnvironment(ctx, id))
+}
+
+func (s *Store) ReorderEnvironments(ctx context.Context, ids []int32) error {
+	return mapError(s.q.ReorderEnvironments(ctx, ids))
+}
+
+func (s *Store) CreateInstance(ctx context.Context, params app.CreateInstanceParameters) (int32, error) {
+	id, err := s.q.CreateInstance(ctx, repo.CreateInstanceParams{
+		EnvironmentID:    params.EnvironmentId,
+		ApplicationID:    params.ApplicationId,
+		Name:             params.Name,
+		PrimaryComponent: params.PrimaryComponent,
+	})
+	return id, mapError(err)
+}
+
+func (s *Store) UpdateInstance(ctx context.Context, params app.UpdateInstanceParameters) error {
+	n, err := s.q.UpdateInstance(ctx, repo.UpdateInstanceParams{
+		ID:               params.Id,
+		Name:             params.Name,
+		PrimaryComponent: params.PrimaryComponent,
+	})
+	if err != nil {
+		return mapError(err)
+	}
+	if n == 0 {
+		return app.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Store) ListInstances(ctx context.Context, name string) ([]app.Instance, error) {
+	instances, err := s.q.ListInstances(ctx, name)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Instance
+	for _, i := range instances {
+		result = append(result, app.Instance{
+			Id:               i.ID,
+			EnvironmentId:    i.EnvironmentID,
+			ApplicationId:    i.ApplicationID,
+			Name:             i.Name,
+			PrimaryComponent: i.PrimaryComponent,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) GetInstance(ctx context.Context, id int32) (app.Instance, error) {
+	i, err := s.q.GetInstance(ctx, id)
+	if err != nil {
+		return app.Instance{}, mapError(err)
+	}
+
+	return app.Instance{
+		Id:               i.ID,
+		EnvironmentId:    i.EnvironmentID,
+		ApplicationId:    i.ApplicationID,
+		Name:             i.Name,
+		PrimaryComponent: i.PrimaryComponent,
+	}, nil
+}
+
+func (s *Store) DeleteInstance(ctx context.Context, id int32) error {
+	return mapError(s.q.DeleteInstance(ctx, id))
+}
+
+func (s *Store) ListInstancesAndDeployment(ctx context.Context) ([]app.InstanceAndDeploymentResult, error) {
+	rows, err := s.q.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.InstanceAndDeploymentResult
+	for _, r := range rows {
+		result = append(result, app.InstanceAndDeploymentResult{
+			Instance: app.Instance{
+				Id:               r.ID,
+				EnvironmentId:    r.EnvironmentID,
+				ApplicationId:    r.ApplicationID,
+				Name:             r.Name,
+				PrimaryComponent: r.PrimaryComponent,
+			},
+			Deployment: func() *app.Deployment {
+				if r.DeployedAt.Valid {
+					return &app.Deployment{
+						Id:         uuid.UUID(r.DeploymentID.Bytes).String(),
+						InstanceId: r.ID,
+						Component:  r.PrimaryComponent,
+						Version:    r.Version.String,
+						DeployedAt: r.DeployedAt.Time,
+						ReceivedAt: r.ReceivedAt.Time,
+						Sequence:   r.Sequence.Int64,
+						Late:       r.Late.Bool,
+						ClockSkew:  r.ClockSkew.Bool,
+						Undeployed: r.Undeployed.Bool,
+						Source:     r.Source.String,
+						Freeze:     r.FreezeWindow.String,
+						Metadata: app.DeploymentMetadata{
+							Image:       r.Image.String,
+							ImageDigest: r.ImageDigest.String,
+							GitCommit:   r.GitCommit.String,
+							BuildURL:    r.BuildUrl.String,
+							Deployer:    r.Deployer.String,
+							Labels:      decodeLabels(r.Labels),
+						},
+					}
+				}
+				return nil
+			}(),
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) ListDeployments(ctx context.Context) ([]app.Deployment, error) {
+	deployments, err := s.q.ListDeployments(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Deployment
+	for _, d := range deployments {
+		result = append(result, toDeployment(d))
+	}
+
+	return result, nil
+}
+
+func (s *Store) ListInstanceDeployments(ctx context.Context, instanceId int32) ([]app.Deployment, error) {
+	deployments, err := s.q.ListInstanceDeployments(ctx, instanceId)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Deployment
+	for _, d := range deployments {
+		result = append(result, toDeployment(d))
+	}
+
+	return result, nil
+}
+
+func (s *Store) ListDeployedVersions(ctx context.Context, instanceId int32, component string) ([]string, error) {
+	versions, err := s.q.ListDeployedVersions(ctx, repo.ListDeployedVersionsParams{InstanceID: instanceId, Component: component})
+	return versions, mapError(err)
+}
+
+func (s *Store) ListCurrentDeployments(ctx context.Context) ([]app.Deployment, error) {
+	deployments, err := s.q.ListCurrentDeployments(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Deployment
+	for _, d := range deployments {
+		result = append(result, toDeployment(d))
+	}
+
+	return result, nil
+}
+
+func toDeployment(d repo.Deployment) app.Deployment {
+	return app.Deployment{
+		Id:         uuid.UUID(d.ID.Bytes).String(),
+		InstanceId: d.InstanceID,
+		Component:  d.Component,
+		Version:    d.Version,
+		DeployedAt: d.DeployedAt.Time,
+		ReceivedAt: d.ReceivedAt.Time,
+		Sequence:   d.Sequence.Int64,
+		Late:       d.Late,
+		ClockSkew:  d.ClockSkew,
+		Undeployed: d.Undeployed,
+		Source:     d.Source,
+		Freeze:     d.FreezeWindow,
+		Metadata: app.DeploymentMetadata{
+			Image:       d.Image,
+			ImageDigest: d.ImageDigest,
+			GitCommit:   d.GitCommit,
+			BuildURL:    d.BuildUrl,
+			Deployer:    d.Deployer,
+			Labels:      decodeLabels(d.Labels),
+		},
+	}
+}
+
+// RegisterDeployment stores the deployment and, in the same transaction, makes it the current deployment of its component.
+// If the current deployment supersedes it, it is marked as late instead.
+func (s *Store) RegisterDeployment(ctx context.Context, d app.Deployment) (app.Deployment, app.Deployment, error) {
+	id := uuid.New()
+
+	labels, err := encodeLabels(d.Metadata.Labels)
+	if err != nil {
+		return app.Deployment{}, app.Deployment{}, err
+	}
+
+	var replaced app.Deployment
+	err = pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		q := s.q.WithTx(tx)
+
+		current, err := q.GetCurrentDeployment(ctx, repo.GetCurrentDeploymentParams{InstanceID: d.InstanceId, Component: d.Component})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		if err := q.RegisterDeployment(ctx, repo.RegisterDeploymentParams{
+			ID:           pgtype.UUID{Bytes: id, Valid: true},
+			InstanceID:   d.InstanceId,
+			Version:      d.Version,
+			DeployedAt:   pgtype.Timestamptz{Time: d.DeployedAt, Valid: true},
+			ReceivedAt:   pgtype.Timestamptz{Time: d.ReceivedAt, Valid: true},
+			Sequence:     pgtype.Int8{Int64: d.Sequence, Valid: d.Sequence != 0},
+			ClockSkew:    d.ClockSkew,
+			Image:        d.Metadata.Image,
+			ImageDigest:  d.Metadata.ImageDigest,
+			GitCommit:    d.Metadata.GitCommit,
+			BuildUrl:     d.Metadata.BuildURL,
+			Deployer:     d.Metadata.Deployer,
+			Labels:       labels,
+			Component:    d.Component,
+			Undeployed:   d.Undeployed,
+			Source:       d.Source,
+			FreezeWindow: d.Freeze,
+		}); err != nil {
+			return err
+		}
+
+		n, err := q.UpdateCurrentDeployment(ctx, repo.UpdateCurrentDeploymentParams{
+			InstanceID:   d.InstanceId,
+			Component:    d.Component,
+			DeploymentID: pgtype.UUID{Bytes: id, Valid: true},
+			OrderedAt:    pgtype.Timestamptz{Time: d.OrderedAt(), Valid: true},
+			Sequence:     pgtype.Int8{Int64: d.Sequence, Valid: d.Sequence != 0},
+			Source:       d.Source,
+		})
+		if err != nil {
+			return err
+		}
+
+		d.Late = n == 0
+		if d.Late {
+			return q.MarkDeploymentLate(ctx, pgtype.UUID{Bytes: id, Valid: true})
+		}
+		if current.ID.Valid {
+			replaced = toDeployment(current)
+		}
+		return nil
+	})
+	if err != nil {
+		return app.Deployment{}, app.Deployment{}, mapError(err)
+	}
+
+	d.Id = id.String()
+	return d, replaced, nil
+}
+
+func (s *Store) DeleteDeployments(ctx context.Context, ids []string) (int64, error) {
+	uuids := make([]pgtype.UUID, 0, len(ids))
+	for _, id := range ids {
+		u, err := uuid.Parse(id)
+		if err != nil {
+			return 0, fmt.Errorf("%w: deployment %q", app.ErrNotFound, id)
+		}
+		uuids = append(uuids, pgtype.UUID{Bytes: u, Valid: true})
+	}
+
+	n, err := s.q.DeleteDeployments(ctx, uuids)
+	return n, mapError(err)
+}
+
+func (s *Store) ListRollouts(ctx context.Context) ([]app.Rollout, error) {
+	rollouts, err := s.q.ListRollouts(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Rollout
+	for _, r := range rollouts {
+		result = append(result, app.Rollout{
+			InstanceId:     r.InstanceID,
+			Component:      r.Component,
+			Version:        r.Version,
+			Status:         r.Status,
+			Description:    r.Description,
+			Healthy:        r.Healthy,
+			Desired:        r.Desired,
+			RunningVersion: r.RunningVersion,
+			UpdatedAt:      r.UpdatedAt.Time,
+			Sequence:       r.Sequence.Int64,
+			Source:         r.Source,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) UpdateRollout(ctx context.Context, r app.Rollout) (bool, error) {
+	n, err := s.q.UpdateRollout(ctx, repo.UpdateRolloutParams{
+		InstanceID:     r.InstanceId,
+		Component:      r.Component,
+		Version:        r.Version,
+		Status:         r.Status,
+		Description:    r.Description,
+		Healthy:        r.Healthy,
+		Desired:        r.Desired,
+		RunningVersion: r.RunningVersion,
+		UpdatedAt:      pgtype.Timestamptz{Time: r.UpdatedAt, Valid: true},
+		Sequence:       pgtype.Int8{Int64: r.Sequence, Valid: r.Sequence != 0},
+		Source:         r.Source,
+	})
+	if err != nil {
+		return false, mapError(err)
+	}
+	return n > 0, nil
+}
+
+func (s *Store) ListReleases(ctx context.Context) ([]app.Release, error) {
+	releases, err := s.q.ListReleases(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Release
+	for _, r := range releases {
+		result = append(result, app.Release{
+			ApplicationId: r.ApplicationID,
+			Repository:    r.Repository,
+			Version:       r.Version,
+			CheckedAt:     r.CheckedAt.Time,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) SaveRelease(ctx context.Context, r app.Release) error {
+	return mapError(s.q.SaveRelease(ctx, repo.SaveReleaseParams{
+		ApplicationID: r.ApplicationId,
+		Repository:    r.Repository,
+		Version:       r.Version,
+		CheckedAt:     pgtype.Timestamptz{Time: r.CheckedAt, Valid: true},
+	}))
+}
+
+func (s *Store) ListPromotions(ctx context.Context) ([]app.PromotionEdge, error) {
+	promotions, err := s.q.ListPromotions(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.PromotionEdge
+	for _, p := range promotions {
+		result = append(result, app.PromotionEdge{
+			FromEnvironmentId: p.FromEnvironmentID,
+			ToEnvironmentId:   p.ToEnvironmentID,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) SetPromotionSources(ctx context.Context, environmentId int32, sourceIds []int32) error {
+	return mapError(pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		q := s.q.WithTx(tx)
+
+		if err := q.DeletePromotionsTo(ctx, environmentId); err != nil {
+			return err
+		}
+
+		for _, id := range sourceIds {
+			if err := q.AddPromotion(ctx, repo.AddPromotionParams{
+				FromEnvironmentID: id,
+				ToEnvironmentID:   environmentId,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+func (s *Store) ListVersionPolicies(ctx context.Context) ([]app.VersionPolicy, error) {
+	applicationPolicies, err := s.q.ListApplicationPolicies(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	instancePolicies, err := s.q.ListInstancePolicies(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.VersionPolicy
+	for _, p := range applicationPolicies {
+		result = append(result, app.VersionPolicy{
+			ApplicationId:      p.ApplicationID,
+			Pinned:             p.Pinned,
+			Allowed:            p.Allowed,
+			Forbidden:          decodeStrings(p.Forbidden),
+			NotAheadOfUpstream: p.NotAheadOfUpstream,
+		})
+	}
+	for _, p := range instancePolicies {
+		result = append(result, app.VersionPolicy{
+			ApplicationId:      p.ApplicationID,
+			InstanceId:         p.InstanceID,
+			Pinned:             p.Pinned,
+			Allowed:            p.Allowed,
+			Forbidden:          decodeStrings(p.Forbidden),
+			NotAheadOfUpstream: p.NotAheadOfUpstream,
+		})
+	}
+
+	// The policy of an application has no instance and is sorted before the ones of its instances.
+	slices.SortStableFunc(result, func(a, b app.VersionPolicy) int {
+		return cmp.Or(cmp.Compare(a.ApplicationId, b.ApplicationId), cmp.Compare(a.InstanceId, b.InstanceId))
+	})
+	return result, nil
+}
+
+func (s *Store) SaveVersionPolicy(ctx context.Context, p app.VersionPolicy) error {
+	forbidden, err := encodeStrings(p.Forbidden)
+	if err != nil {
+		return err
+	}
+
+	if p.InstanceId != 0 {
+		return mapError(s.q.SaveInstancePolicy(ctx, repo.SaveInstancePolicyParams{
+			InstanceID:         p.InstanceId,
+			Pinned:             p.Pinned,
+			Allowed:            p.Allowed,
+			Forbidden:          forbidden,
+			NotAheadOfUpstream: p.NotAheadOfUpstream,
+		}))
+	}
+
+	return mapError(s.q.SaveApplicationPolicy(ctx, repo.SaveApplicationPolicyParams{
+		ApplicationID:      p.ApplicationId,
+		Pinned:             p.Pinned,
+		Allowed:            p.Allowed,
+		Forbidden:          forbidden,
+		NotAheadOfUpstream: p.NotAheadOfUpstream,
+	}))
+}
+
+func (s *Store) DeleteVersionPolicy(ctx context.Context, applicationId, instanceId int32) error {
+	if instanceId != 0 {
+		return mapError(s.q.DeleteInstancePolicy(ctx, instanceId))
+	}
+	return mapError(s.q.DeleteApplicationPolicy(ctx, applicationId))
+}
+
+func (s *Store) ListPolicyViolations(ctx context.Context) ([]app.PolicyViolation, error) {
+	violations, err := s.q.ListPolicyViolations(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.PolicyViolation
+	for _, v := range violations {
+		result = append(result, app.PolicyViolation{
+			DeploymentId: uuid.UUID(v.DeploymentID.Bytes).String(),
+			InstanceId:   v.InstanceID,
+			Version:      v.Version,
+			Rule:         v.Rule,
+			Message:      v.Message,
+			DetectedAt:   v.DetectedAt.Time,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) AddPolicyViolations(ctx context.Context, violations []app.PolicyViolation) error {
+	return mapError(pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		q := s.q.WithTx(tx)
+
+		for _, v := range violations {
+			id, err := uuid.Parse(v.DeploymentId)
+			if err != nil {
+				return fmt.Errorf("%w: deployment %q", app.ErrNotFound, v.DeploymentId)
+			}
+
+			if err := q.AddPolicyViolation(ctx, repo.AddPolicyViolationParams{
+				DeploymentID: pgtype.UUID{Bytes: id, Valid: true},
+				InstanceID:   v.InstanceId,
+				Version:      v.Version,
+				Rule:         v.Rule,
+				Message:      v.Message,
+				DetectedAt:   pgtype.Timestamptz{Time: v.DetectedAt, Valid: true},
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+func (s *Store) ListFreezeWindows(ctx context.Context) ([]app.FreezeWindow, error) {
+	windows, err := s.q.ListFreezeWindows(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.FreezeWindow
+	for _, w := range windows {
+		result = append(result, app.FreezeWindow{
+			Id:              w.ID,
+			Name:            w.Name,
+			Reason:          w.Reason,
+			EnvironmentId:   w.EnvironmentID.Int32,
+			ApplicationId:   w.ApplicationID.Int32,
+			StartsAt:        w.StartsAt.Time,
+			EndsAt:          w.EndsAt.Time,
+			Schedule:        w.Schedule,
+			DurationSeconds: w.DurationSeconds,
+			TimeZone:        w.TimeZone,
+			Reject:          w.Reject,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) CreateFreezeWindow(ctx context.Context, w app.FreezeWindow) (int32, error) {
+	id, err := s.q.CreateFreezeWindow(ctx, repo.CreateFreezeWindowParams{
+		Name:            w.Name,
+		Reason:          w.Reason,
+		EnvironmentID:   pgtype.Int4{Int32: w.EnvironmentId, Valid: w.EnvironmentId != 0},
+		ApplicationID:   pgtype.Int4{Int32: w.ApplicationId, Valid: w.ApplicationId != 0},
+		StartsAt:        pgtype.Timestamptz{Time: w.StartsAt, Valid: !w.StartsAt.IsZero()},
+		EndsAt:          pgtype.Timestamptz{Time: w.EndsAt, Valid: !w.EndsAt.IsZero()},
+		Schedule:        w.Schedule,
+		DurationSeconds: w.DurationSeconds,
+		TimeZone:        w.TimeZone,
+		Reject:          w.Reject,
+	})
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return id, nil
+}
+
+func (s *Store) UpdateFreezeWindow(ctx context.Context, w app.FreezeWindow) error {
+	n, err := s.q.UpdateFreezeWindow(ctx, repo.UpdateFreezeWindowParams{
+		ID:              w.Id,
+		Name:            w.Name,
+		Reason:          w.Reason,
+		EnvironmentID:   pgtype.Int4{Int32: w.EnvironmentId, Valid: w.EnvironmentId != 0},
+		ApplicationID:   pgtype.Int4{Int32: w.ApplicationId, Valid: w.ApplicationId != 0},
+		StartsAt:        pgtype.Timestamptz{Time: w.StartsAt, Valid: !w.StartsAt.IsZero()},
+		EndsAt:          pgtype.Timestamptz{Time: w.EndsAt, Valid: !w.EndsAt.IsZero()},
+		Schedule:        w.Schedule,
+		DurationSeconds: w.DurationSeconds,
+		TimeZone:        w.TimeZone,
+		Reject:          w.Reject,
+	})
+	if err != nil {
+		return mapError(err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: freeze window %d", app.ErrNotFound, w.Id)
+	}
+	return nil
+}
+
+func (s *Store) DeleteFreezeWindow(ctx context.Context, id int32) error {
+	return mapError(s.q.DeleteFreezeWindow(ctx, id))
+}
+
+func (s *Store) ListWebhooks(ctx context.Context) ([]app.Webhook, error) {
+	webhooks, err := s.q.ListWebhooks(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Webhook
+	for _, w := range webhooks {
+		result = append(result, app.Webhook{
+			Id:            w.ID,
+			Name:          w.Name,
+			URL:           w.Url,
+			Secret:        w.Secret,
+			Events:        decodeStrings(w.Events),
+			EnvironmentId: w.EnvironmentID.Int32,
+			ApplicationId: w.ApplicationID.Int32,
+			Disabled:      !w.Enabled,
+			CreatedAt:     w.CreatedAt.Time,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) CreateWebhook(ctx context.Context, w app.Webhook) (int32, error) {
+	events, err := encodeStrings(w.Events)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.q.CreateWebhook(ctx, repo.CreateWebhookParams{
+		Name:          w.Name,
+		Url:           w.URL,
+		Secret:        w.Secret,
+		Events:        events,
+		EnvironmentID: pgtype.Int4{Int32: w.EnvironmentId, Valid: w.EnvironmentId != 0},
+		ApplicationID: pgtype.Int4{Int32: w.ApplicationId, Valid: w.ApplicationId != 0},
+		Enabled:       !w.Disabled,
+		CreatedAt:     pgtype.Timestamptz{Time: w.CreatedAt, Valid: true},
+	})
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return id, nil
+}
+
+func (s *Store) UpdateWebhook(ctx context.Context, w app.Webhook) error {
+	events, err := encodeStrings(w.Events)
+	if err != nil {
+		return err
+	}
+
+	n, err := s.q.UpdateWebhook(ctx, repo.UpdateWebhookParams{
+		ID:            w.Id,
+		Name:          w.Name,
+		Url:           w.URL,
+		Secret:        w.Secret,
+		Events:        events,
+		EnvironmentID: pgtype.Int4{Int32: w.EnvironmentId, Valid: w.EnvironmentId != 0},
+		ApplicationID: pgtype.Int4{Int32: w.ApplicationId, Valid: w.ApplicationId != 0},
+		Enabled:       !w.Disabled,
+	})
+	if err != nil {
+		return mapError(err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: webhook %d", app.ErrNotFound, w.Id)
+	}
+	return nil
+}
+
+func (s *Store) DeleteWebhook(ctx context.Context, id int32) error {
+	return mapError(s.q.DeleteWebhook(ctx, id))
+}
+
+func (s *Store) AddWebhookDeliveries(ctx context.Context, deliveries []app.WebhookDelivery) error {
+	return mapError(pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		q := s.q.WithTx(tx)
+		for _, d := range deliveries {
+			id, err := uuid.Parse(d.Id)
+			if err != nil {
+				return fmt.Errorf("invalid delivery id %q: %w", d.Id, err)
+			}
+			eventId, err := uuid.Parse(d.EventId)
+			if err != nil {
+				return fmt.Errorf("invalid event id %q: %w", d.EventId, err)
+			}
+
+			if err := q.AddWebhookDelivery(ctx, repo.AddWebhookDeliveryParams{
+				ID:            pgtype.UUID{Bytes: id, Valid: true},
+				WebhookID:     d.WebhookId,
+				EventID:       pgtype.UUID{Bytes: eventId, Valid: true},
+				Event:         d.Event,
+				Payload:       string(d.Payload),
+				Status:        d.Status,
+				CreatedAt:     pgtype.Timestamptz{Time: d.CreatedAt, Valid: true},
+				NextAttemptAt: pgtype.Timestamptz{Time: d.NextAttemptAt, Valid: !d.NextAttemptAt.IsZero()},
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+func (s *Store) GetWebhookDelivery(ctx context.Context, id string) (app.WebhookDelivery, error) {
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return app.WebhookDelivery{}, fmt.Errorf("%w: webhook delivery %q", app.ErrNotFound, id)
+	}
+
+	d, err := s.q.GetWebhookDelivery(ctx, pgtype.UUID{Bytes: u, Valid: true})
+	if err != nil {
+		return app.WebhookDelivery{}, mapError(err)
+	}
+	return toWebhookDelivery(d), nil
+}
+
+func (s *Store) ListWebhookDeliveries(ctx context.Context, webhookId int32, limit int32) ([]app.WebhookDelivery, error) {
+	deliveries, err := s.q.ListWebhookDeliveries(ctx, repo.ListWebhookDeliveriesParams{
+		WebhookID:     webhookId,
+		MaxDeliveries: limit,
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.WebhookDelivery
+	for _, d := range deliveries {
+		result = append(result, toWebhookDelivery(d))
+	}
+	return result, nil
+}
+
+func (s *Store) ListDueWebhookDeliveries(ctx context.Context, t time.Time) ([]app.WebhookDelivery, error) {
+	deliveries, err := s.q.ListDueWebhookDeliveries(ctx, pgtype.Timestamptz{Time: t, Valid: true})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.WebhookDelivery
+	for _, d := range deliveries {
+		result = append(result, toWebhookDelivery(d))
+	}
+	return result, nil
+}
+
+func (s *Store) UpdateWebhookDelivery(ctx context.Context, d app.WebhookDelivery) error {
+	id, err := uuid.Parse(d.Id)
+	if err != nil {
+		return fmt.Errorf("%w: webhook delivery %q", app.ErrNotFound, d.Id)
+	}
+
+	n, err := s.q.UpdateWebhookDelivery(ctx, repo.UpdateWebhookDeliveryParams{
+		ID:             pgtype.UUID{Bytes: id, Valid: true},
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		Error:          d.Error,
+		NextAttemptAt:  pgtype.Timestamptz{Time: d.NextAttemptAt, Valid: !d.NextAttemptAt.IsZero()},
+		LastAttemptAt:  pgtype.Timestamptz{Time: d.LastAttemptAt, Valid: !d.LastAttemptAt.IsZero()},
+	})
+	if err != nil {
+		return mapError(err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: webhook delivery %q", app.ErrNotFound, d.Id)
+	}
+	return nil
+}
+
+func (s *Store) DeleteWebhookDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	n, err := s.q.DeleteWebhookDeliveries(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+	return n, mapError(err)
+}
+
+func toWebhookDelivery(d repo.WebhookDelivery) app.WebhookDelivery {
+	return app.WebhookDelivery{
+		Id:             uuid.UUID(d.ID.Bytes).String(),
+		WebhookId:      d.WebhookID,
+		EventId:        uuid.UUID(d.EventID.Bytes).String(),
+		Event:          d.Event,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		Error:          d.Error,
+		CreatedAt:      d.CreatedAt.Time,
+		NextAttemptAt:  d.NextAttemptAt.Time,
+		LastAttemptAt:  d.LastAttemptAt.Time,
+	}
+}
+
+func (s *Store) ListNotificationChannels(ctx context.Context) ([]app.NotificationChannel, error) {
+	channels, err := s.q.ListNotificationChannels(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	routes, err := s.q.ListNotificationRoutes(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	routesOf := map[int32][]app.NotificationRoute{}
+	for _, r := range routes {
+		routesOf[r.ChannelID] = append(routesOf[r.ChannelID], app.NotificationRoute{
+			EnvironmentId: r.EnvironmentID.Int32,
+			ApplicationId: r.ApplicationID.Int32,
+			Events:        decodeStrings(r.Events),
+			Redeploys:     r.Redeploys,
+		})
+	}
+
+	var result []app.NotificationChannel
+	for _, c := range channels {
+		result = append(result, app.NotificationChannel{
+			Id:           c.ID,
+			Name:         c.Name,
+			Kind:         c.Kind,
+			URL:          c.Url,
+			Template:     c.Template,
+			BatchSeconds: c.BatchSeconds,
+			Routes:       routesOf[c.ID],
+			Disabled:     !c.Enabled,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) CreateNotificationChannel(ctx context.Context, c app.NotificationChannel) (int32, error) {
+	var id int32
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		q := s.q.WithTx(tx)
+
+		var err error
+		id, err = q.CreateNotificationChannel(ctx, repo.CreateNotificationChannelParams{
+			Name:         c.Name,
+			Kind:         c.Kind,
+			Url:          c.URL,
+			Template:     c.Template,
+			BatchSeconds: c.BatchSeconds,
+			Enabled:      !c.Disabled,
+		})
+		if err != nil {
+			return err
+		}
+
+		return addNotificationRoutes(ctx, q, id, c.Routes)
+	})
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return id, nil
+}
+
+func (s *Store) UpdateNotificationChannel(ctx context.Context, c app.NotificationChannel) error {
+	return mapError(pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		q := s.q.WithTx(tx)
+
+		n, err := q.UpdateNotificationChannel(ctx, repo.UpdateNotificationChannelParams{
+			ID:           c.Id,
+			Name:         c.Name,
+			Kind:         c.Kind,
+			Url:          c.URL,
+			Template:     c.Template,
+			BatchSeconds: c.BatchSeconds,
+			Enabled:      !c.Disabled,
+		})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("%w: notification channel %d", app.ErrNotFound, c.Id)
+		}
+
+		if err := q.DeleteNotificationRoutes(ctx, c.Id); err != nil {
+			return err
+		}
+		return addNotificationRoutes(ctx, q, c.Id, c.Routes)
+	}))
+}
+
+func addNotificationRoutes(ctx context.Context, q *repo.Queries, channelId int32, routes []app.NotificationRoute) error {
+	for _, r := range routes {
+		events, err := encodeStrings(r.Events)
+		if err != nil {
+			return err
+		}
+
+		if err := q.AddNotificationRoute(ctx, repo.AddNotificationRouteParams{
+			ChannelID:     channelId,
+			EnvironmentID: pgtype.Int4{Int32: r.EnvironmentId, Valid: r.EnvironmentId != 0},
+			ApplicationID: pgtype.Int4{Int32: r.ApplicationId, Valid: r.ApplicationId != 0},
+			Events:        events,
+			Redeploys:     r.Redeploys,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) DeleteNotificationChannel(ctx context.Context, id int32) error {
+	return mapError(s.q.DeleteNotificationChannel(ctx, id))
+}
@@ -0,0 +1,70 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"overseer/app"
+	"overseer/db"
+	"overseer/migrate"
+	"overseer/storage/postgres"
+	"overseer/storage/storetest"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TestStore runs against the database in OVERSEER_TEST_POSTGRES, which is emptied before every test.
+func TestStore(t *testing.T) {
+	connString := os.Getenv("OVERSEER_TEST_POSTGRES")
+	if connString == "" {
+		t.Skip("OVERSEER_TEST_POSTGRES is not set")
+	}
+
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, connString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	migrator, err := migrate.New(pool, db.Migrations())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every table is emptied, a table added by a migration has to be added here too.
+	tables := []string{
+		"environments", "applications", "instances", "deployments", "current_deployments", "rollouts",
+		"releases", "promotions", "application_policies", "instance_policies", "policy_violations",
+		"freeze_windows", "webhooks", "webhook_deliveries", "notification_channels", "notification_routes",
+	}
+
+	rows, err := pool.Query(ctx, "SELECT tablename FROM pg_tables WHERE schemaname = current_schema() AND tablename <> 'schema_migrations'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range existing {
+		if !slices.Contains(tables, table) {
+			t.Fatalf("the table %s is not emptied between the tests", table)
+		}
+	}
+
+	storetest.Run(t, func(t *testing.T) app.Store {
+		if _, err := pool.Exec(ctx, "TRUNCATE "+strings.Join(tables, ", ")+" RESTART IDENTITY"); err != nil {
+			t.Fatal(err)
+		}
+		return postgres.New(pool)
+	})
+}
@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"overseer/db"
+	"overseer/migrate"
+	"time"
+)
+
+// Migrator applies the SQLite schema migrations, like migrate.Migrator does for Postgres.
+type Migrator struct {
+	db         *sql.DB
+	migrations []migrate.Migration
+}
+
+// OpenMigrator opens the database at path to migrate it, without migrating it yet.
+// The migrator must be closed after use.
+func OpenMigrator(path string) (*Migrator, error) {
+	conn, err := openDB(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := newMigrator(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+func newMigrator(conn *sql.DB) (*Migrator, error) {
+	migrations, err := migrate.Load(db.SQLiteMigrations())
+	if err != nil {
+		return nil, fmt.Errorf("loading migrations: %w", err)
+	}
+
+	return &Migrator{db: conn, migrations: migrations}, nil
+}
+
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// Latest returns the version of the newest migration known to the binary.
+func (m *Migrator) Latest() int64 {
+	return migrate.Latest(m.migrations)
+}
+
+// Up applies all pending migrations in a single transaction and returns the ones that were applied.
+func (m *Migrator) Up(ctx context.Context) ([]migrate.Migration, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck // The rollback is a no-op after a successful commit
+
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version integer PRIMARY KEY,
+    name text NOT NULL,
+    applied_at text NOT NULL
+)`); err != nil {
+		return nil, fmt.Errorf("creating the schema_migrations table: %w", err)
+	}
+
+	applied, err := appliedVersions(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.checkNotNewer(applied); err != nil {
+		return nil, err
+	}
+
+	var done []migrate.Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+			return nil, fmt.Errorf("applying migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+			migration.Version, migration.Name, formatTime(time.Now())); err != nil {
+			return nil, fmt.Errorf("recording migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return done, nil
+}
+
+// Status returns every migration known to the binary and when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]migrate.Status, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck // The transaction only reads
+
+	applied, err := appliedVersions(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.checkNotNewer(applied); err != nil {
+		return nil, err
+	}
+
+	var result []migrate.Status
+	for _, migration := range m.migrations {
+		s := migrate.Status{Migration: migration}
+		if at, ok := applied[migration.Version]; ok {
+			s.AppliedAt = &at
+		}
+		result = append(result, s)
+	}
+
+	return result, nil
+}
+
+// Check returns the number of migrations that are still pending,
+// or migrate.ErrSchemaTooNew if the database has migrations applied that the binary does not know about.
+func (m *Migrator) Check(ctx context.Context) (int, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, s := range status {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+func (m *Migrator) checkNotNewer(applied map[int64]time.Time) error {
+	for version := range applied {
+		if err := migrate.CheckNotNewer(m.migrations, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appliedVersions(ctx context.Context, tx *sql.Tx) (map[int64]time.Time, error) {
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')").Scan(&exists); err != nil {
+		return nil, fmt.Errorf("looking for the schema_migrations table: %w", err)
+	}
+
+	applied := make(map[int64]time.Time)
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("listing applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		var appliedAt string
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		at, err := parseTime(appliedAt)
+		if err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+
+	return applied, rows.Err()
+}
@@ -0,0 +1,1243 @@
+// Package sqlite implements app.Store on top of an SQLite database file,
+// for running Overseer as a single binary without a database server.
+package sqlite
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"overseer/app"
+	"overseer/repo/sqliterepo"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// encodeLabels encodes deployment labels as the JSON object stored in the labels column.
+func encodeLabels(labels map[string]string) (string, error) {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	data, err := json.Marshal(labels)
+	return string(data), err
+}
+
+// decodeLabels decodes the labels column, returning nil if there are no labels.
+func decodeLabels(data string) map[string]string {
+	var labels map[string]string
+	if err := json.Unmarshal([]byte(data), &labels); err != nil || len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// encodeStrings encodes a list, like the forbidden versions of a policy or the events of a webhook, as a JSON array.
+func encodeStrings(list []string) (string, error) {
+	if list == nil {
+		list = []string{}
+	}
+	data, err := json.Marshal(list)
+	return string(data), err
+}
+
+// decodeStrings decodes a list encoded by encodeStrings, returning nil if it is empty.
+func decodeStrings(data string) []string {
+	var list []string
+	if err := json.Unmarshal([]byte(data), &list); err != nil || len(list) == 0 {
+		return nil
+	}
+	return list
+}
+
+// timeFormat is a fixed width format, so that the timestamps stored as text sort chronologically.
+const timeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+type Store struct {
+	db *sql.DB
+	q  *sqliterepo.Queries
+}
+
+var _ app.Store = (*Store)(nil)
+
+// Open opens, and creates if needed, the database at path and migrates it to the latest schema.
+// The path ":memory:" opens a database that only lives as long as the store.
+func Open(ctx context.Context, path string) (*Store, error) {
+	conn, err := openDB(path)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := newMigrator(conn)
+	if err == nil {
+		_, err = migrator.Up(ctx)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Store{
+		db: conn,
+		q:  sqliterepo.New(conn),
+	}, nil
+}
+
+func openDB(path string) (*sql.DB, error) {
+	dsn := "file:" + path + "?" + url.Values{
+		"_pragma": {"foreign_keys(1)", "busy_timeout(5000)", "journal_mode(WAL)"},
+		"_txlock": {"immediate"},
+	}.Encode()
+
+	conn, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening the database: %w", err)
+	}
+
+	// SQLite only allows a single writer, serializing everything over one connection
+	// avoids busy errors and keeps ":memory:" databases alive.
+	conn.SetMaxOpenConns(1)
+	return conn, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// mapError translates database errors into the errors defined by the app package.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return app.ErrNotFound
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return fmt.Errorf("%w: %s", app.ErrAlreadyExists, sqliteErr.Error())
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return fmt.Errorf("%w: %s", app.ErrNotFound, sqliteErr.Error())
+		}
+	}
+
+	return err
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+func parseTime(s string) (time.Time, error) {
+	return time.Parse(timeFormat, s)
+}
+
+// formatNullTime formats an optional time, the zero time is stored as NULL.
+func formatNullTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: formatTime(t), Valid: true}
+}
+
+func parseNullTime(s sql.NullString) (time.Time, error) {
+	if !s.Valid {
+		return time.Time{}, nil
+	}
+	return parseTime(s.String)
+}
+
+// withTx runs f in a transaction, which is committed if f succeeds.
+func (s *Store) withTx(ctx context.Context, f func(q *sqliterepo.Queries) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // The rollback is a no-op after a successful commit
+
+	if err := f(s.q.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) ListApplications(ctx context.Context) ([]app.Application, error) {
+	apps, err := s.q.ListApplications(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Application
+	for _, a := range apps {
+		result = append(result, app.Application{
+			Id:    int32(a.ID),
+			Name:  a.Name,
+			Order: int32(a.SortOrder),
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) GetApplication(ctx context.Context, id int32) (app.Application, error) {
+	a, err := s.q.GetApplication(ctx, int64(id))
+	if err != nil {
+		return app.Application{}, mapError(err)
+	}
+	return app.Application{Id: int32(a.ID), Name: a.Name, Order: int32(a.SortOrder)}, nil
+}
+
+func (s *Store) CreateApplication(ctx context.Context, name string) (app.Application, error) {
+	a, err := s.q.CreateApplication(ctx, name)
+	if err != nil {
+		return app.Application{}, mapError(err)
+	}
+	return app.Application{
+		Id:    int32(a.ID),
+		Name:  a.Name,
+		Order: int32(a.SortOrder),
+	}, nil
+}
+
+func (s *Store) UpdateApplication(ctx context.Context, id int32, name string) (app.Application, error) {
+	a, err := s.q.UpdateApplication(ctx, sqliterepo.UpdateApplicationParams{
+		ID:   int64(id),
+		Name: name,
+	})
+	if err != nil {
+		return app.Application{}, mapError(err)
+	}
+	return app.Application{
+		Id:    int32(a.ID),
+		Name:  a.Name,
+		Order: int32(a.SortOrder),
+	}, nil
+}
+
+func (s *Store) DeleteApplication(ctx context.Context, id int32) error {
+	return mapError(s.q.DeleteApplication(ctx, int64(id)))
+}
+
+func (s *Store) ReorderApplications(ctx context.Context, ids []int32) error {
+	return mapError(s.withTx(ctx, func(q *sqliterepo.Queries) error {
+		for i, id := range ids {
+			if err := q.SetApplicationSortOrder(ctx, sqliterepo.SetApplicationSortOrderParams{
+				ID:        int64(id),
+				SortOrder: int64(i + 1),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+func (s *Store) ListEnvironments(ctx context.Context) ([]app.Environment, error) {
+	envs, err := s.q.ListEnvironments(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Environment
+	for _, e := range envs {
+		result = append(result, app.Environment{
+			Id:    int32(e.ID),
+			Name:  e.Name,
+			Order: int32(e.SortOrder),
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) GetEnvironment(ctx context.Context, id int32) (app.Environment, error) {
+	e, err := s.q.GetEnvironment(ctx, int64(id))
+	if err != nil {
+		return app.Environment{}, mapError(err)
+	}
+	return app.Environment{Id: int32(e.ID), Name: e.Name, Order: int32(e.SortOrder)}, nil
+}
+
+func (s *Store) CreateEnvironment(ctx context.Context, name string) (app.Environment, error) {
+	e, err := s.q.CreateEnvironment(ctx, name)
+	if err != nil {
+		return app.Environment{}, mapError(err)
+	}
+	return app.Environment{
+		Id:    int32(e.ID),
+		Name:  e.Name,
+		Order: int32(e.SortOrder),
+	}, nil
+}
+
+func (s *Store) UpdateEnvironment(ctx context.Context, id int32, name string) (app.Environment, error) {
+	e, err := s.q.UpdateEnvironment(ctx, sqliterepo.UpdateEnvironmentParams{
+		ID:   int64(id),
+		Name: name,
+	})
+	if err != nil {
+		return app.Environment{}, mapError(err)
+	}
+	return app.Environment{
+		Id:    int32(e.ID),
+		Name:  e.Name,
+		Order: int32(e.SortOrder),
+	}, nil
+}
+
+func (s *Store) DeleteEnvironment(ctx context.Context, id int32) error {
+	return mapError(s.q.DeleteEnvironment(ctx, int64(id)))
+}
+
+func (s *Store) ReorderEnvironments(ctx context.Context, ids []int32) error {
+	return mapError(s.withTx(ctx, func(q *sqliterepo.Queries) error {
+		for i, id := range ids {
+			if err := q.SetEnvironmentSortOrder(ctx, sqliterepo.SetEnvironmentSortOrderParams{
+				ID:        int64(id),
+				SortOrder: int64(i + 1),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+func (s *Store) CreateInstance(ctx context.Context, params app.CreateInstanceParameters) (int32, error) {
+	id, err := s.q.CreateInstance(ctx, sqliterepo.CreateInstanceParams{
+		EnvironmentID:    int64(params.EnvironmentId),
+		ApplicationID:    int64(params.ApplicationId),
+		Name:             params.Name,
+		PrimaryComponent: params.PrimaryComponent,
+	})
+	return int32(id), mapError(err)
+}
+
+func (s *Store) UpdateInstance(ctx context.Context, params app.UpdateInstanceParameters) error {
+	n, err := s.q.UpdateInstance(ctx, sqliterepo.UpdateInstanceParams{
+		ID:               int64(params.Id),
+		Name:             params.Name,
+		PrimaryComponent: params.PrimaryComponent,
+	})
+	if err != nil {
+		return mapError(err)
+	}
+	if n == 0 {
+		return app.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Store) ListInstances(ctx context.Context, name string) ([]app.Instance, error) {
+	instances, err := s.q.ListInstances(ctx, name)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Instance
+	for _, i := range instances {
+		result = append(result, app.Instance{
+			Id:               int32(i.ID),
+			EnvironmentId:    int32(i.EnvironmentID),
+			ApplicationId:    int32(i.ApplicationID),
+			Name:             i.Name,
+			PrimaryComponent: i.PrimaryComponent,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) GetInstance(ctx context.Context, id int32) (app.Instance, error) {
+	i, err := s.q.GetInstance(ctx, int64(id))
+	if err != nil {
+		return app.Instance{}, mapError(err)
+	}
+
+	return app.Instance{
+		Id:               int32(i.ID),
+		EnvironmentId:    int32(i.EnvironmentID),
+		ApplicationId:    int32(i.ApplicationID),
+		Name:             i.Name,
+		PrimaryComponent: i.PrimaryComponent,
+	}, nil
+}
+
+func (s *Store) DeleteInstance(ctx context.Context, id int32) error {
+	return mapError(s.q.DeleteInstance(ctx, int64(id)))
+}
+
+func (s *Store) ListInstancesAndDeployment(ctx context.Context) ([]app.InstanceAndDeploymentResult, error) {
+	rows, err := s.q.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.InstanceAndDeploymentResult
+	for _, r := range rows {
+		res := app.InstanceAndDeploymentResult{
+			Instance: app.Instance{
+				Id:               int32(r.ID),
+				EnvironmentId:    int32(r.EnvironmentID),
+				ApplicationId:    int32(r.ApplicationID),
+				Name:             r.Name,
+				PrimaryComponent: r.PrimaryComponent,
+			},
+		}
+
+		if r.DeployedAt.Valid {
+			deployedAt, err := parseTime(r.DeployedAt.String)
+			if err != nil {
+				return nil, err
+			}
+
+			receivedAt, err := parseTime(r.ReceivedAt.String)
+			if err != nil {
+				return nil, err
+			}
+
+			res.Deployment = &app.Deployment{
+				Id:         r.DeploymentID.String,
+				InstanceId: int32(r.ID),
+				Component:  r.PrimaryComponent,
+				Version:    r.Version.String,
+				DeployedAt: deployedAt,
+				ReceivedAt: receivedAt,
+				Sequence:   r.Sequence.Int64,
+				Late:       r.Late.Bool,
+				ClockSkew:  r.ClockSkew.Bool,
+				Undeployed: r.Undeployed.Bool,
+				Source:     r.Source.String,
+				Freeze:     r.FreezeWindow.String,
+				Metadata: app.DeploymentMetadata{
+					Image:       r.Image.String,
+					ImageDigest: r.ImageDigest.String,
+					GitCommit:   r.GitCommit.String,
+					BuildURL:    r.BuildUrl.String,
+					Deployer:    r.Deployer.String,
+					Labels:      decodeLabels(r.Labels.String),
+				},
+			}
+		}
+
+		result = append(result, res)
+	}
+
+	return result, nil
+}
+
+func (s *Store) ListDeployments(ctx context.Context) ([]app.Deployment, error) {
+	deployments, err := s.q.ListDeployments(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Deployment
+	for _, d := range deployments {
+		deployment, err := toDeployment(d)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, deployment)
+	}
+
+	return result, nil
+}
+
+func (s *Store) ListInstanceDeployments(ctx context.Context, instanceId int32) ([]app.Deployment, error) {
+	deployments, err := s.q.ListInstanceDeployments(ctx, int64(instanceId))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Deployment
+	for _, d := range deployments {
+		deployment, err := toDeployment(d)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, deployment)
+	}
+
+	return result, nil
+}
+
+func (s *Store) ListDeployedVersions(ctx context.Context, instanceId int32, component string) ([]string, error) {
+	versions, err := s.q.ListDeployedVersions(ctx, sqliterepo.ListDeployedVersionsParams{InstanceID: int64(instanceId), Component: component})
+	return versions, mapError(err)
+}
+
+func (s *Store) ListCurrentDeployments(ctx context.Context) ([]app.Deployment, error) {
+	deployments, err := s.q.ListCurrentDeployments(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Deployment
+	for _, d := range deployments {
+		deployment, err := toDeployment(d)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, deployment)
+	}
+
+	return result, nil
+}
+
+func toDeployment(d sqliterepo.Deployment) (app.Deployment, error) {
+	deployedAt, err := parseTime(d.DeployedAt)
+	if err != nil {
+		return app.Deployment{}, err
+	}
+
+	receivedAt, err := parseTime(d.ReceivedAt)
+	if err != nil {
+		return app.Deployment{}, err
+	}
+
+	return app.Deployment{
+		Id:         d.ID,
+		InstanceId: int32(d.InstanceID),
+		Component:  d.Component,
+		Version:    d.Version,
+		DeployedAt: deployedAt,
+		ReceivedAt: receivedAt,
+		Sequence:   d.Sequence.Int64,
+		Late:       d.Late,
+		ClockSkew:  d.ClockSkew,
+		Undeployed: d.Undeployed,
+		Source:     d.Source,
+		Freeze:     d.FreezeWindow,
+		Metadata: app.DeploymentMetadata{
+			Image:       d.Image,
+			ImageDigest: d.ImageDigest,
+			GitCommit:   d.GitCommit,
+			BuildURL:    d.BuildUrl,
+			Deployer:    d.Deployer,
+			Labels:      decodeLabels(d.Labels),
+		},
+	}, nil
+}
+
+// RegisterDeployment stores the deployment and, in the same transaction, makes it the current deployment of its component.
+// If the current deployment supersedes it, it is marked as late instead.
+func (s *Store) RegisterDeployment(ctx context.Context, d app.Deployment) (app.Deployment, app.Deployment, error) {
+	id := uuid.NewString()
+	sequence := sql.NullInt64{Int64: d.Sequence, Valid: d.Sequence != 0}
+
+	labels, err := encodeLabels(d.Metadata.Labels)
+	if err != nil {
+		return app.Deployment{}, app.Deployment{}, err
+	}
+
+	var replaced app.Deployment
+	err = s.withTx(ctx, func(q *sqliterepo.Queries) error {
+		current, err := q.GetCurrentDeployment(ctx, sqliterepo.GetCurrentDeploymentParams{InstanceID: int64(d.InstanceId), Component: d.Component})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if err := q.RegisterDeployment(ctx, sqliterepo.RegisterDeploymentParams{
+			ID:           id,
+			InstanceID:   int64(d.InstanceId),
+			Version:      d.Version,
+			DeployedAt:   formatTime(d.DeployedAt),
+			ReceivedAt:   formatTime(d.ReceivedAt),
+			Sequence:     sequence,
+			ClockSkew:    d.ClockSkew,
+			Image:        d.Metadata.Image,
+			ImageDigest:  d.Metadata.ImageDigest,
+			GitCommit:    d.Metadata.GitCommit,
+			BuildUrl:     d.Metadata.BuildURL,
+			Deployer:     d.Metadata.Deployer,
+			Labels:       labels,
+			Component:    d.Component,
+			Undeployed:   d.Undeployed,
+			Source:       d.Source,
+			FreezeWindow: d.Freeze,
+		}); err != nil {
+			return err
+		}
+
+		n, err := q.UpdateCurrentDeployment(ctx, sqliterepo.UpdateCurrentDeploymentParams{
+			InstanceID:   int64(d.InstanceId),
+			Component:    d.Component,
+			DeploymentID: id,
+			OrderedAt:    formatTime(d.OrderedAt()),
+			Sequence:     sequence,
+			Source:       d.Source,
+		})
+		if err != nil {
+			return err
+		}
+
+		d.Late = n == 0
+		if d.Late {
+			return q.MarkDeploymentLate(ctx, id)
+		}
+		if current.ID != "" {
+			replaced, err = toDeployment(current)
+		}
+		return err
+	})
+	if err != nil {
+		return app.Deployment{}, app.Deployment{}, mapError(err)
+	}
+
+	d.Id = id
+	return d, replaced, nil
+}
+
+// deleteBatchSize keeps DeleteDeployments below the limit of bound parameters in a statement.
+const deleteBatchSize = 500
+
+func (s *Store) DeleteDeployments(ctx context.Context, ids []string) (int64, error) {
+	var deleted int64
+	err := s.withTx(ctx, func(q *sqliterepo.Queries) error {
+		for batch := range slices.Chunk(ids, deleteBatchSize) {
+			n, err := q.DeleteDeployments(ctx, batch)
+			if err != nil {
+				return err
+			}
+			deleted += n
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return deleted, nil
+}
+
+func (s *Store) ListRollouts(ctx context.Context) ([]app.Rollout, error) {
+	rollouts, err := s.q.ListRollouts(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Rollout
+	for _, r := range rollouts {
+		updatedAt, err := parseTime(r.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, app.Rollout{
+			InstanceId:     int32(r.InstanceID),
+			Component:      r.Component,
+			Version:        r.Version,
+			Status:         r.Status,
+			Description:    r.Description,
+			Healthy:        int32(r.Healthy),
+			Desired:        int32(r.Desired),
+			RunningVersion: r.RunningVersion,
+			UpdatedAt:      updatedAt,
+			Sequence:       r.Sequence.Int64,
+			Source:         r.Source,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) UpdateRollout(ctx context.Context, r app.Rollout) (bool, error) {
+	n, err := s.q.UpdateRollout(ctx, sqliterepo.UpdateRolloutParams{
+		InstanceID:     int64(r.InstanceId),
+		Component:      r.Component,
+		Version:        r.Version,
+		Status:         r.Status,
+		Description:    r.Description,
+		Healthy:        int64(r.Healthy),
+		Desired:        int64(r.Desired),
+		RunningVersion: r.RunningVersion,
+		UpdatedAt:      formatTime(r.UpdatedAt),
+		Sequence:       sql.NullInt64{Int64: r.Sequence, Valid: r.Sequence != 0},
+		Source:         r.Source,
+	})
+	if err != nil {
+		return false, mapError(err)
+	}
+	return n > 0, nil
+}
+
+func (s *Store) ListReleases(ctx context.Context) ([]app.Release, error) {
+	releases, err := s.q.ListReleases(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Release
+	for _, r := range releases {
+		checkedAt, err := parseTime(r.CheckedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, app.Release{
+			ApplicationId: int32(r.ApplicationID),
+			Repository:    r.Repository,
+			Version:       r.Version,
+			CheckedAt:     checkedAt,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) SaveRelease(ctx context.Context, r app.Release) error {
+	return mapError(s.q.SaveRelease(ctx, sqliterepo.SaveReleaseParams{
+		ApplicationID: int64(r.ApplicationId),
+		Repository:    r.Repository,
+		Version:       r.Version,
+		CheckedAt:     formatTime(r.CheckedAt),
+	}))
+}
+
+func (s *Store) ListPromotions(ctx context.Context) ([]app.PromotionEdge, error) {
+	promotions, err := s.q.ListPromotions(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.PromotionEdge
+	for _, p := range promotions {
+		result = append(result, app.PromotionEdge{
+			FromEnvironmentId: int32(p.FromEnvironmentID),
+			ToEnvironmentId:   int32(p.ToEnvironmentID),
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) SetPromotionSources(ctx context.Context, environmentId int32, sourceIds []int32) error {
+	return mapError(s.withTx(ctx, func(q *sqliterepo.Queries) error {
+		if err := q.DeletePromotionsTo(ctx, int64(environmentId)); err != nil {
+			return err
+		}
+
+		for _, id := range sourceIds {
+			if err := q.AddPromotion(ctx, sqliterepo.AddPromotionParams{
+				FromEnvironmentID: int64(id),
+				ToEnvironmentID:   int64(environmentId),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+func (s *Store) ListVersionPolicies(ctx context.Context) ([]app.VersionPolicy, error) {
+	applicationPolicies, err := s.q.ListApplicationPolicies(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	instancePolicies, err := s.q.ListInstancePolicies(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.VersionPolicy
+	for _, p := range applicationPolicies {
+		result = append(result, app.VersionPolicy{
+			ApplicationId:      int32(p.ApplicationID),
+			Pinned:             p.Pinned,
+			Allowed:            p.Allowed,
+			Forbidden:          decodeStrings(p.Forbidden),
+			NotAheadOfUpstream: p.NotAheadOfUpstream,
+		})
+	}
+	for _, p := range instancePolicies {
+		result = append(result, app.VersionPolicy{
+			ApplicationId:      int32(p.ApplicationID),
+			InstanceId:         int32(p.InstanceID),
+			Pinned:             p.Pinned,
+			Allowed:            p.Allowed,
+			Forbidden:          decodeStrings(p.Forbidden),
+			NotAheadOfUpstream: p.NotAheadOfUpstream,
+		})
+	}
+
+	// The policy of an application has no instance and is sorted before the ones of its instances.
+	slices.SortStableFunc(result, func(a, b app.VersionPolicy) int {
+		return cmp.Or(cmp.Compare(a.ApplicationId, b.ApplicationId), cmp.Compare(a.InstanceId, b.InstanceId))
+	})
+	return result, nil
+}
+
+func (s *Store) SaveVersionPolicy(ctx context.Context, p app.VersionPolicy) error {
+	forbidden, err := encodeStrings(p.Forbidden)
+	if err != nil {
+		return err
+	}
+
+	if p.InstanceId != 0 {
+		return mapError(s.q.SaveInstancePolicy(ctx, sqliterepo.SaveInstancePolicyParams{
+			InstanceID:         int64(p.InstanceId),
+			Pinned:             p.Pinned,
+			Allowed:            p.Allowed,
+			Forbidden:          forbidden,
+			NotAheadOfUpstream: p.NotAheadOfUpstream,
+		}))
+	}
+
+	return mapError(s.q.SaveApplicationPolicy(ctx, sqliterepo.SaveApplicationPolicyParams{
+		ApplicationID:      int64(p.ApplicationId),
+		Pinned:             p.Pinned,
+		Allowed:            p.Allowed,
+		Forbidden:          forbidden,
+		NotAheadOfUpstream: p.NotAheadOfUpstream,
+	}))
+}
+
+func (s *Store) DeleteVersionPolicy(ctx context.Context, applicationId, instanceId int32) error {
+	if instanceId != 0 {
+		return mapError(s.q.DeleteInstancePolicy(ctx, int64(instanceId)))
+	}
+	return mapError(s.q.DeleteApplicationPolicy(ctx, int64(applicationId)))
+}
+
+func (s *Store) ListPolicyViolations(ctx context.Context) ([]app.PolicyViolation, error) {
+	violations, err := s.q.ListPolicyViolations(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.PolicyViolation
+	for _, v := range violations {
+		detectedAt, err := parseTime(v.DetectedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, app.PolicyViolation{
+			DeploymentId: v.DeploymentID,
+			InstanceId:   int32(v.InstanceID),
+			Version:      v.Version,
+			Rule:         v.Rule,
+			Message:      v.Message,
+			DetectedAt:   detectedAt,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) AddPolicyViolations(ctx context.Context, violations []app.PolicyViolation) error {
+	return mapError(s.withTx(ctx, func(q *sqliterepo.Queries) error {
+		for _, v := range violations {
+			if err := q.AddPolicyViolation(ctx, sqliterepo.AddPolicyViolationParams{
+				DeploymentID: v.DeploymentId,
+				InstanceID:   int64(v.InstanceId),
+				Version:      v.Version,
+				Rule:         v.Rule,
+				Message:      v.Message,
+				DetectedAt:   formatTime(v.DetectedAt),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+func (s *Store) ListFreezeWindows(ctx context.Context) ([]app.FreezeWindow, error) {
+	windows, err := s.q.ListFreezeWindows(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.FreezeWindow
+	for _, w := range windows {
+		startsAt, err := parseNullTime(w.StartsAt)
+		if err != nil {
+			return nil, err
+		}
+
+		endsAt, err := parseNullTime(w.EndsAt)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, app.FreezeWindow{
+			Id:              int32(w.ID),
+			Name:            w.Name,
+			Reason:          w.Reason,
+			EnvironmentId:   int32(w.EnvironmentID.Int64),
+			ApplicationId:   int32(w.ApplicationID.Int64),
+			StartsAt:        startsAt,
+			EndsAt:          endsAt,
+			Schedule:        w.Schedule,
+			DurationSeconds: w.DurationSeconds,
+			TimeZone:        w.TimeZone,
+			Reject:          w.Reject,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) CreateFreezeWindow(ctx context.Context, w app.FreezeWindow) (int32, error) {
+	id, err := s.q.CreateFreezeWindow(ctx, sqliterepo.CreateFreezeWindowParams{
+		Name:            w.Name,
+		Reason:          w.Reason,
+		EnvironmentID:   sql.NullInt64{Int64: int64(w.EnvironmentId), Valid: w.EnvironmentId != 0},
+		ApplicationID:   sql.NullInt64{Int64: int64(w.ApplicationId), Valid: w.ApplicationId != 0},
+		StartsAt:        formatNullTime(w.StartsAt),
+		EndsAt:          formatNullTime(w.EndsAt),
+		Schedule:        w.Schedule,
+		DurationSeconds: w.DurationSeconds,
+		TimeZone:        w.TimeZone,
+		Reject:          w.Reject,
+	})
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return int32(id), nil
+}
+
+func (s *Store) UpdateFreezeWindow(ctx context.Context, w app.FreezeWindow) error {
+	n, err := s.q.UpdateFreezeWindow(ctx, sqliterepo.UpdateFreezeWindowParams{
+		ID:              int64(w.Id),
+		Name:            w.Name,
+		Reason:          w.Reason,
+		EnvironmentID:   sql.NullInt64{Int64: int64(w.EnvironmentId), Valid: w.EnvironmentId != 0},
+		ApplicationID:   sql.NullInt64{Int64: int64(w.ApplicationId), Valid: w.ApplicationId != 0},
+		StartsAt:        formatNullTime(w.StartsAt),
+		EndsAt:          formatNullTime(w.EndsAt),
+		Schedule:        w.Schedule,
+		DurationSeconds: w.DurationSeconds,
+		TimeZone:        w.TimeZone,
+		Reject:          w.Reject,
+	})
+	if err != nil {
+		return mapError(err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: freeze window %d", app.ErrNotFound, w.Id)
+	}
+	return nil
+}
+
+func (s *Store) DeleteFreezeWindow(ctx context.Context, id int32) error {
+	return mapError(s.q.DeleteFreezeWindow(ctx, int64(id)))
+}
+
+func (s *Store) ListWebhooks(ctx context.Context) ([]app.Webhook, error) {
+	webhooks, err := s.q.ListWebhooks(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	var result []app.Webhook
+	for _, w := range webhooks {
+		createdAt, err := parseTime(w.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, app.Webhook{
+			Id:            int32(w.ID),
+			Name:          w.Name,
+			URL:           w.Url,
+			Secret:        w.Secret,
+			Events:        decodeStrings(w.Events),
+			EnvironmentId: int32(w.EnvironmentID.Int64),
+			ApplicationId: int32(w.ApplicationID.Int64),
+			Disabled:      !w.Enabled,
+			CreatedAt:     createdAt,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) CreateWebhook(ctx context.Context, w app.Webhook) (int32, error) {
+	events, err := encodeStrings(w.Events)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.q.CreateWebhook(ctx, sqliterepo.CreateWebhookParams{
+		Name:          w.Name,
+		Url:           w.URL,
+		Secret:        w.Secret,
+		Events:        events,
+		EnvironmentID: sql.NullInt64{Int64: int64(w.EnvironmentId), Valid: w.EnvironmentId != 0},
+		ApplicationID: sql.NullInt64{Int64: int64(w.ApplicationId), Valid: w.ApplicationId != 0},
+		Enabled:       !w.Disabled,
+		CreatedAt:     formatTime(w.CreatedAt),
+	})
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return int32(id), nil
+}
+
+func (s *Store) UpdateWebhook(ctx context.Context, w app.Webhook) error {
+	events, err := encodeStrings(w.Events)
+	if err != nil {
+		return err
+	}
+
+	n, err := s.q.UpdateWebhook(ctx, sqliterepo.UpdateWebhookParams{
+		ID:            int64(w.Id),
+		Name:          w.Name,
+		Url:           w.URL,
+		Secret:        w.Secret,
+		Events:        events,
+		EnvironmentID: sql.NullInt64{Int64: int64(w.EnvironmentId), Valid: w.EnvironmentId != 0},
+		ApplicationID: sql.NullInt64{Int64: int64(w.ApplicationId), Valid: w.ApplicationId != 0},
+		Enabled:       !w.Disabled,
+	})
+	if err != nil {
+		return mapError(err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: webhook %d", app.ErrNotFound, w.Id)
+	}
+	return nil
+}
+
+func (s *Store) DeleteWebhook(ctx context.Context, id int32) error {
+	return mapError(s.q.DeleteWebhook(ctx, int64(id)))
+}
+
+func (s *Store) AddWebhookDeliveries(ctx context.Context, deliveries []app.WebhookDelivery) error {
+	return mapError(s.withTx(ctx, func(q *sqliterepo.Queries) error {
+		for _, d := range deliveries {
+			if err := q.AddWebhookDelivery(ctx, sqliterepo.AddWebhookDeliveryParams{
+				ID:            d.Id,
+				WebhookID:     int64(d.WebhookId),
+				EventID:       d.EventId,
+				Event:         d.Event,
+				Payload:       string(d.Payload),
+				Status:        d.Status,
+				CreatedAt:     formatTime(d.CreatedAt),
+				NextAttemptAt: formatNullTime(d.NextAttemptAt),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+func (s *Store) GetWebhookDelivery(ctx context.Context, id string) (app.WebhookDelivery, error) {
+	d, err := s.q.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return app.WebhookDelivery{}, mapError(err)
+	}
+	return toWebhookDelivery(d)
+}
+
+func (s *Store) ListWebhookDeliveries(ctx context.Context, webhookId int32, limit int32) ([]app.WebhookDelivery, error) {
+	deliveries, err := s.q.ListWebhookDeliveries(ctx, sqliterepo.ListWebhookDeliveriesParams{
+		WebhookID:     int64(webhookId),
+		MaxDeliveries: int64(limit),
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return toWebhookDeliveries(deliveries)
+}
+
+func (s *Store) ListDueWebhookDeliveries(ctx context.Context, t time.Time) ([]app.WebhookDelivery, error) {
+	deliveries, err := s.q.ListDueWebhookDeliveries(ctx, formatNullTime(t))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return toWebhookDeliveries(deliveries)
+}
+
+func (s *Store) UpdateWebhookDelivery(ctx context.Context, d app.WebhookDelivery) error {
+	n, err := s.q.UpdateWebhookDelivery(ctx, sqliterepo.UpdateWebhookDeliveryParams{
+		ID:             d.Id,
+		Status:         d.Status,
+		Attempts:       int64(d.Attempts),
+		ResponseStatus: int64(d.ResponseStatus),
+		Error:          d.Error,
+		NextAttemptAt:  formatNullTime(d.NextAttemptAt),
+		LastAttemptAt:  formatNullTime(d.LastAttemptAt),
+	})
+	if err != nil {
+		return mapError(err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: webhook delivery %q", app.ErrNotFound, d.Id)
+	}
+	return nil
+}
+
+func (s *Store) DeleteWebhookDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	n, err := s.q.DeleteWebhookDeliveries(ctx, formatTime(before))
+	return n, mapError(err)
+}
+
+func toWebhookDeliveries(deliveries []sqliterepo.WebhookDelivery) ([]app.WebhookDelivery, error) {
+	var result []app.WebhookDelivery
+	for _, d := range deliveries {
+		delivery, err := toWebhookDelivery(d)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, delivery)
+	}
+	return result, nil
+}
+
+func toWebhookDelivery(d sqliterepo.WebhookDelivery) (app.WebhookDelivery, error) {
+	createdAt, err := parseTime(d.CreatedAt)
+	if err != nil {
+		return app.WebhookDelivery{}, err
+	}
+
+	nextAttemptAt, err := parseNullTime(d.NextAttemptAt)
+	if err != nil {
+		return app.WebhookDelivery{}, err
+	}
+
+	lastAttemptAt, err := parseNullTime(d.LastAttemptAt)
+	if err != nil {
+		return app.WebhookDelivery{}, err
+	}
+
+	return app.WebhookDelivery{
+		Id:             d.ID,
+		WebhookId:      int32(d.WebhookID),
+		EventId:        d.EventID,
+		Event:          d.Event,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       int32(d.Attempts),
+		ResponseStatus: int32(d.ResponseStatus),
+		Error:          d.Error,
+		CreatedAt:      createdAt,
+		NextAttemptAt:  nextAttemptAt,
+		LastAttemptAt:  lastAttemptAt,
+	}, nil
+}
+
+func (s *Store) ListNotificationChannels(ctx context.Context) ([]app.NotificationChannel, error) {
+	channels, err := s.q.ListNotificationChannels(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	routes, err := s.q.ListNotificationRoutes(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	routesOf := map[int64][]app.NotificationRoute{}
+	for _, r := range routes {
+		routesOf[r.ChannelID] = append(routesOf[r.ChannelID], app.NotificationRoute{
+			EnvironmentId: int32(r.EnvironmentID.Int64),
+			ApplicationId: int32(r.ApplicationID.Int64),
+			Events:        decodeStrings(r.Events),
+			Redeploys:     r.Redeploys,
+		})
+	}
+
+	var result []app.NotificationChannel
+	for _, c := range channels {
+		result = append(result, app.NotificationChannel{
+			Id:           int32(c.ID),
+			Name:         c.Name,
+			Kind:         c.Kind,
+			URL:          c.Url,
+			Template:     c.Template,
+			BatchSeconds: int32(c.BatchSeconds),
+			Routes:       routesOf[c.ID],
+			Disabled:     !c.Enabled,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Store) CreateNotificationChannel(ctx context.Context, c app.NotificationChannel) (int32, error) {
+	var id int64
+	err := s.withTx(ctx, func(q *sqliterepo.Queries) error {
+		var err error
+		id, err = q.CreateNotificationChannel(ctx, sqliterepo.CreateNotificationChannelParams{
+			Name:         c.Name,
+			Kind:         c.Kind,
+			Url:          c.URL,
+			Template:     c.Template,
+			BatchSeconds: int64(c.BatchSeconds),
+			Enabled:      !c.Disabled,
+		})
+		if err != nil {
+			return err
+		}
+
+		return addNotificationRoutes(ctx, q, id, c.Routes)
+	})
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return int32(id), nil
+}
+
+func (s *Store) UpdateNotificationChannel(ctx context.Context, c app.NotificationChannel) error {
+	return mapError(s.withTx(ctx, func(q *sqliterepo.Queries) error {
+		n, err := q.UpdateNotificationChannel(ctx, sqliterepo.UpdateNotificationChannelParams{
+			ID:           int64(c.Id),
+			Name:         c.Name,
+			Kind:         c.Kind,
+			Url:          c.URL,
+			Template:     c.Template,
+			BatchSeconds: int64(c.BatchSeconds),
+			Enabled:      !c.Disabled,
+		})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("%w: notification channel %d", app.ErrNotFound, c.Id)
+		}
+
+		if err := q.DeleteNotificationRoutes(ctx, int64(c.Id)); err != nil {
+			return err
+		}
+		return addNotificationRoutes(ctx, q, int64(c.Id), c.Routes)
+	}))
+}
+
+func addNotificationRoutes(ctx context.Context, q *sqliterepo.Queries, channelId int64, routes []app.NotificationRoute) error {
+	for _, r := range routes {
+		events, err := encodeStrings(r.Events)
+		if err != nil {
+			return err
+		}
+
+		if err := q.AddNotificationRoute(ctx, sqliterepo.AddNotificationRouteParams{
+			ChannelID:     channelId,
+			EnvironmentID: sql.NullInt64{Int64: int64(r.EnvironmentId), Valid: r.EnvironmentId != 0},
+			ApplicationID: sql.NullInt64{Int64: int64(r.ApplicationId), Valid: r.ApplicationId != 0},
+			Events:        events,
+			Redeploys:     r.Redeploys,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) DeleteNotificationChannel(ctx context.Context, id int32) error {
+	return mapError(s.q.DeleteNotificationChannel(ctx, int64(id)))
+}
@@ -0,0 +1,142 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"overseer/app"
+	"overseer/migrate"
+	"overseer/storage/sqlite"
+	"overseer/storage/storetest"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) app.Store {
+		s, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "overseer.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	})
+}
+
+func TestReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "overseer.db")
+
+	s, err := sqlite.Open(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateEnvironment(ctx, "prod"); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = sqlite.Open(ctx, path)
+	if err != nil {
+		t.Fatalf("reopening a migrated database: %v", err)
+	}
+	defer s.Close()
+
+	envs, err := s.ListEnvironments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envs) != 1 || envs[0].Name != "prod" {
+		t.Fatalf("expected the environment to survive a reopen, got %+v", envs)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "overseer.db")
+
+	m, err := sqlite.OpenMigrator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	// Opening the migrator doesn't migrate the database.
+	pending, err := m.Check(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending == 0 || int64(pending) != m.Latest() {
+		t.Fatalf("expected all %d migrations to be pending, got %d", m.Latest(), pending)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != pending || applied[0].Version != 1 || applied[len(applied)-1].Version != m.Latest() {
+		t.Fatalf("expected the %d migrations to be applied in order, got %+v", pending, applied)
+	}
+
+	status, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range status {
+		if s.AppliedAt == nil {
+			t.Fatalf("expected migration %d_%s to be applied", s.Version, s.Name)
+		}
+	}
+
+	if applied, err := m.Up(ctx); err != nil || len(applied) != 0 {
+		t.Fatalf("expected no migrations to be applied twice, got %+v (%v)", applied, err)
+	}
+
+	// The store opens the migrated database without migrating it again.
+	s, err := sqlite.Open(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+}
+
+func TestMigratorSchemaTooNew(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "overseer.db")
+
+	s, err := sqlite.Open(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	m, err := sqlite.OpenMigrator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	// A newer binary applied a migration this one doesn't know about.
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = conn.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, 'from_the_future', ?)",
+		m.Latest()+1, time.Now().UTC().Format(time.RFC3339Nano))
+	conn.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Check(ctx); !errors.Is(err, migrate.ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew from the check, got %v", err)
+	}
+	if _, err := m.Up(ctx); !errors.Is(err, migrate.ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew from the migration, got %v", err)
+	}
+	if _, err := sqlite.Open(ctx, path); !errors.Is(err, migrate.ErrSchemaTooNew) {
+		t.Fatalf("expected the store to refuse the newer schema, got %v", err)
+	}
+}
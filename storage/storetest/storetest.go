@@ -0,0 +1,1709 @@
+// Package storetest contains a test suite verifying that an app.Store implementation behaves like the others.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"overseer/app"
+
+	"github.com/google/uuid"
+)
+
+// Run runs the test suite against the stores created by newStore.
+// Every call to newStore must return a new and empty store.
+func Run(t *testing.T, newStore func(t *testing.T) app.Store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s app.Store)
+	}{
+		{"Applications", testApplications},
+		{"ReorderApplications", testReorderApplications},
+		{"Environments", testEnvironments},
+		{"ReorderEnvironments", testReorderEnvironments},
+		{"Instances", testInstances},
+		{"DeleteCascades", testDeleteCascades},
+		{"Deployments", testDeployments},
+		{"LatestDeployment", testLatestDeployment},
+		{"LatestDeploymentTie", testLatestDeploymentTie},
+		{"DeploymentOrdering", testDeploymentOrdering},
+		{"Components", testComponents},
+		{"Undeployments", testUndeployments},
+		{"DeploymentSources", testDeploymentSources},
+		{"DeployedVersions", testDeployedVersions},
+		{"Rollouts", testRollouts},
+		{"Releases", testReleases},
+		{"Promotions", testPromotions},
+		{"VersionPolicies", testVersionPolicies},
+		{"PolicyViolations", testPolicyViolations},
+		{"FreezeWindows", testFreezeWindows},
+		{"Webhooks", testWebhooks},
+		{"WebhookDeliveries", testWebhookDeliveries},
+		{"NotificationChannels", testNotificationChannels},
+		{"DeleteDeployments", testDeleteDeployments},
+		{"InstanceDeployments", testInstanceDeployments},
+		{"Prune", testPrune},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStore(t))
+		})
+	}
+}
+
+func expectErr(t *testing.T, err, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Fatalf("expected error %v, got %v", target, err)
+	}
+}
+
+func testApplications(t *testing.T, s app.Store) {
+	ctx := context.Background()
+
+	a, err := s.CreateApplication(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.CreateApplication(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Order != 1 || b.Order != 2 {
+		t.Fatalf("expected the sort orders 1 and 2, got %d and %d", a.Order, b.Order)
+	}
+
+	_, err = s.CreateApplication(ctx, "a")
+	expectErr(t, err, app.ErrAlreadyExists)
+
+	renamed, err := s.UpdateApplication(ctx, a.Id, "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Name != "c" || renamed.Order != a.Order {
+		t.Fatalf("expected the renamed application to keep its order, got %+v", renamed)
+	}
+
+	_, err = s.UpdateApplication(ctx, a.Id, "b")
+	expectErr(t, err, app.ErrAlreadyExists)
+
+	_, err = s.UpdateApplication(ctx, 9999, "d")
+	expectErr(t, err, app.ErrNotFound)
+
+	got, err := s.GetApplication(ctx, a.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != renamed {
+		t.Fatalf("expected %+v, got %+v", renamed, got)
+	}
+
+	if err := s.DeleteApplication(ctx, b.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.GetApplication(ctx, b.Id)
+	expectErr(t, err, app.ErrNotFound)
+
+	apps, err := s.ListApplications(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 1 || apps[0] != renamed {
+		t.Fatalf("expected only %+v to be left, got %+v", renamed, apps)
+	}
+}
+
+func testReorderApplications(t *testing.T, s app.Store) {
+	ctx := context.Background()
+
+	a, err := s.CreateApplication(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.CreateApplication(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := s.CreateApplication(ctx, "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ReorderApplications(ctx, []int32{c.Id, a.Id}); err != nil {
+		t.Fatal(err)
+	}
+
+	// c=1, a=2, b keeps 2 and sorts after a on its id.
+	apps, err := s.ListApplications(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, a := range apps {
+		names = append(names, a.Name)
+	}
+	if !slices.Equal(names, []string{"c", "a", "b"}) {
+		t.Fatalf("expected the order c, a, b, got %v", names)
+	}
+
+	d, err := s.CreateApplication(ctx, "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Order != b.Order+1 {
+		t.Fatalf("expected a new application to be sorted last, got order %d", d.Order)
+	}
+}
+
+func testEnvironments(t *testing.T, s app.Store) {
+	ctx := context.Background()
+
+	a, err := s.CreateEnvironment(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.CreateEnvironment(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Order != 1 || b.Order != 2 {
+		t.Fatalf("expected the sort orders 1 and 2, got %d and %d", a.Order, b.Order)
+	}
+
+	_, err = s.CreateEnvironment(ctx, "a")
+	expectErr(t, err, app.ErrAlreadyExists)
+
+	renamed, err := s.UpdateEnvironment(ctx, a.Id, "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Name != "c" || renamed.Order != a.Order {
+		t.Fatalf("expected the renamed environment to keep its order, got %+v", renamed)
+	}
+
+	_, err = s.UpdateEnvironment(ctx, a.Id, "b")
+	expectErr(t, err, app.ErrAlreadyExists)
+
+	_, err = s.UpdateEnvironment(ctx, 9999, "d")
+	expectErr(t, err, app.ErrNotFound)
+
+	got, err := s.GetEnvironment(ctx, a.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != renamed {
+		t.Fatalf("expected %+v, got %+v", renamed, got)
+	}
+
+	if err := s.DeleteEnvironment(ctx, b.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.GetEnvironment(ctx, b.Id)
+	expectErr(t, err, app.ErrNotFound)
+
+	envs, err := s.ListEnvironments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envs) != 1 || envs[0] != renamed {
+		t.Fatalf("expected only %+v to be left, got %+v", renamed, envs)
+	}
+}
+
+func testReorderEnvironments(t *testing.T, s app.Store) {
+	ctx := context.Background()
+
+	a, err := s.CreateEnvironment(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.CreateEnvironment(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := s.CreateEnvironment(ctx, "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ReorderEnvironments(ctx, []int32{b.Id, c.Id, a.Id}); err != nil {
+		t.Fatal(err)
+	}
+
+	envs, err := s.ListEnvironments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range envs {
+		names = append(names, e.Name)
+	}
+	if !slices.Equal(names, []string{"b", "c", "a"}) {
+		t.Fatalf("expected the order b, c, a, got %v", names)
+	}
+}
+
+// fixture creates two environments, two applications and an instance of every application in every environment.
+type fixture struct {
+	envs      []app.Environment
+	apps      []app.Application
+	instances map[[2]int32]int32 // [env, app] -> instance
+}
+
+func newFixture(t *testing.T, s app.Store) fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	f := fixture{instances: map[[2]int32]int32{}}
+	for _, name := range []string{"dev", "prod"} {
+		env, err := s.CreateEnvironment(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.envs = append(f.envs, env)
+	}
+	for _, name := range []string{"api", "web"} {
+		a, err := s.CreateApplication(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.apps = append(f.apps, a)
+	}
+
+	for _, env := range f.envs {
+		for _, a := range f.apps {
+			id, err := s.CreateInstance(ctx, app.CreateInstanceParameters{
+				EnvironmentId: env.Id,
+				ApplicationId: a.Id,
+				Name:          env.Name + "-" + a.Name,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.instances[[2]int32{env.Id, a.Id}] = id
+		}
+	}
+
+	return f
+}
+
+func testInstances(t *testing.T, s app.Store) {
+	ctx := context.Background()
+
+	env, err := s.CreateEnvironment(ctx, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := s.CreateApplication(ctx, "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.CreateInstance(ctx, app.CreateInstanceParameters{EnvironmentId: 9999, ApplicationId: a.Id, Name: "x"})
+	expectErr(t, err, app.ErrNotFound)
+
+	_, err = s.CreateInstance(ctx, app.CreateInstanceParameters{EnvironmentId: env.Id, ApplicationId: 9999, Name: "x"})
+	expectErr(t, err, app.ErrNotFound)
+
+	id, err := s.CreateInstance(ctx, app.CreateInstanceParameters{EnvironmentId: env.Id, ApplicationId: a.Id, Name: "dev-api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.CreateInstance(ctx, app.CreateInstanceParameters{EnvironmentId: env.Id, ApplicationId: a.Id, Name: "other"})
+	expectErr(t, err, app.ErrAlreadyExists)
+
+	b, err := s.CreateApplication(ctx, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.CreateInstance(ctx, app.CreateInstanceParameters{EnvironmentId: env.Id, ApplicationId: b.Id, Name: "dev-api"})
+	expectErr(t, err, app.ErrAlreadyExists)
+
+	other, err := s.CreateInstance(ctx, app.CreateInstanceParameters{EnvironmentId: env.Id, ApplicationId: b.Id, Name: "dev-web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectErr(t, s.UpdateInstance(ctx, app.UpdateInstanceParameters{Id: id, Name: "dev-web"}), app.ErrAlreadyExists)
+
+	expectErr(t, s.UpdateInstance(ctx, app.UpdateInstanceParameters{Id: 9999, Name: "y"}), app.ErrNotFound)
+
+	if err := s.UpdateInstance(ctx, app.UpdateInstanceParameters{Id: id, Name: "renamed"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := app.Instance{Id: id, EnvironmentId: env.Id, ApplicationId: a.Id, Name: "renamed"}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	_, err = s.GetInstance(ctx, 9999)
+	expectErr(t, err, app.ErrNotFound)
+
+	all, err := s.ListInstances(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 instances, got %+v", all)
+	}
+
+	named, err := s.ListInstances(ctx, "renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(named) != 1 || named[0] != want {
+		t.Fatalf("expected only %+v, got %+v", want, named)
+	}
+
+	if err := s.DeleteInstance(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.GetInstance(ctx, other); !errors.Is(err, app.ErrNotFound) {
+		t.Fatalf("expected the deleted instance to be gone, got %v", err)
+	}
+}
+
+func testDeleteCascades(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	for _, id := range f.instances {
+		if _, _, err := s.RegisterDeployment(ctx, app.Deployment{InstanceId: id, Version: "1.0.0", DeployedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dev, api := f.envs[0], f.apps[0]
+
+	if err := s.DeleteEnvironment(ctx, dev.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteApplication(ctx, api.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only prod-web survives.
+	left := f.instances[[2]int32{f.envs[1].Id, f.apps[1].Id}]
+
+	instances, err := s.ListInstances(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 1 || instances[0].Id != left {
+		t.Fatalf("expected only instance %d to be left, got %+v", left, instances)
+	}
+
+	deployments, err := s.ListDeployments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deployments) != 1 || deployments[0].InstanceId != left {
+		t.Fatalf("expected only the deployment of instance %d to be left, got %+v", left, deployments)
+	}
+}
+
+func testDeployments(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	_, _, err := s.RegisterDeployment(ctx, app.Deployment{InstanceId: 9999, Version: "1.0.0", DeployedAt: time.Now()})
+	expectErr(t, err, app.ErrNotFound)
+
+	id := f.instances[[2]int32{f.envs[0].Id, f.apps[0].Id}]
+	deployedAt := time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.FixedZone("CEST", 2*60*60))
+
+	want := app.Deployment{
+		InstanceId: id,
+		Version:    "1.2.3",
+		DeployedAt: deployedAt,
+		Metadata: app.DeploymentMetadata{
+			Image:       "ghcr.io/acme/api:1.2.3",
+			ImageDigest: "sha256:4a5b",
+			GitCommit:   "0c1d2e3f",
+			BuildURL:    "https://ci.example.com/builds/42",
+			Deployer:    "alice",
+			Labels:      map[string]string{"team": "platform"},
+		},
+	}
+	if _, _, err := s.RegisterDeployment(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.ListDeployments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].InstanceId != id || got[0].Version != want.Version || !got[0].DeployedAt.Equal(deployedAt) ||
+		!reflect.DeepEqual(got[0].Metadata, want.Metadata) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	rows, err := s.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rows {
+		if r.Instance.Id == id && (r.Deployment == nil || !reflect.DeepEqual(r.Deployment.Metadata, want.Metadata)) {
+			t.Fatalf("expected the current deployment to have metadata %+v, got %+v", want.Metadata, r.Deployment)
+		}
+	}
+}
+
+func testLatestDeployment(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	id := f.instances[[2]int32{f.envs[0].Id, f.apps[0].Id}]
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// Registered out of order, the newest deployment must win regardless.
+	for _, d := range []app.Deployment{
+		{InstanceId: id, Version: "1.0.0", DeployedAt: base},
+		{InstanceId: id, Version: "1.2.0", DeployedAt: base.Add(2 * time.Hour)},
+		{InstanceId: id, Version: "1.1.0", DeployedAt: base.Add(time.Hour)},
+	} {
+		if _, _, err := s.RegisterDeployment(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := s.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(f.instances) {
+		t.Fatalf("expected a row for each of the %d instances, got %d", len(f.instances), len(rows))
+	}
+
+	for _, r := range rows {
+		if r.Instance.Id != id {
+			if r.Deployment != nil {
+				t.Fatalf("expected instance %d to have no deployment, got %+v", r.Instance.Id, r.Deployment)
+			}
+			continue
+		}
+
+		if r.Deployment == nil || r.Deployment.Version != "1.2.0" || !r.Deployment.DeployedAt.Equal(base.Add(2*time.Hour)) {
+			t.Fatalf("expected version 1.2.0 to be the latest, got %+v", r.Deployment)
+		}
+	}
+}
+
+func testLatestDeploymentTie(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	id := f.instances[[2]int32{f.envs[0].Id, f.apps[0].Id}]
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// Deployments sharing a timestamp must not duplicate the instance, the last registered one wins.
+	for _, v := range []string{"1.0.0", "2.0.0"} {
+		if _, _, err := s.RegisterDeployment(ctx, app.Deployment{InstanceId: id, Version: v, DeployedAt: at}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := s.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(f.instances) {
+		t.Fatalf("expected a row for each of the %d instances, got %d", len(f.instances), len(rows))
+	}
+
+	for _, r := range rows {
+		if r.Instance.Id == id && (r.Deployment == nil || r.Deployment.Version != "2.0.0") {
+			t.Fatalf("expected version 2.0.0 to be current, got %+v", r.Deployment)
+		}
+	}
+}
+
+func testDeleteDeployments(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	id := f.instances[[2]int32{f.envs[0].Id, f.apps[0].Id}]
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, v := range []string{"1.0.0", "2.0.0"} {
+		if _, _, err := s.RegisterDeployment(ctx, app.Deployment{InstanceId: id, Version: v, DeployedAt: base.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deployments, err := s.ListDeployments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deployments) != 2 || deployments[0].Id == "" || deployments[0].Version != "1.0.0" {
+		t.Fatalf("expected the deployments with ids in chronological order, got %+v", deployments)
+	}
+
+	// The current deployment survives being deleted.
+	n, err := s.DeleteDeployments(ctx, []string{deployments[0].Id, deployments[1].Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 deleted deployment, got %d", n)
+	}
+
+	deployments, err = s.ListDeployments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deployments) != 1 || deployments[0].Version != "2.0.0" {
+		t.Fatalf("expected only the current deployment to remain, got %+v", deployments)
+	}
+}
+
+func testInstanceDeployments(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	id := f.instances[[2]int32{f.envs[0].Id, f.apps[0].Id}]
+	other := f.instances[[2]int32{f.envs[1].Id, f.apps[0].Id}]
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, d := range []app.Deployment{
+		{InstanceId: id, Component: "worker", Version: "2.0.0", DeployedAt: now.Add(time.Hour)},
+		{InstanceId: id, Component: "api", Version: "1.1.0", DeployedAt: now.Add(2 * time.Hour)},
+		{InstanceId: other, Component: "api", Version: "1.0.0", DeployedAt: now},
+		// A late deployment is listed in the order it was deployed.
+		{InstanceId: id, Component: "api", Version: "1.0.0", DeployedAt: now},
+		{InstanceId: id, Component: "worker", Version: "1.0.0", DeployedAt: now},
+	} {
+		if _, _, err := s.RegisterDeployment(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deployments, err := s.ListInstanceDeployments(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, d := range deployments {
+		if d.InstanceId != id || d.Id == "" {
+			t.Fatalf("expected only the deployments of instance %d, got %+v", id, d)
+		}
+		got = append(got, d.Component+":"+d.Version)
+	}
+	want := []string{"api:1.0.0", "api:1.1.0", "worker:1.0.0", "worker:2.0.0"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected the deployments %v, got %v", want, got)
+	}
+
+	none, err := s.ListInstanceDeployments(ctx, f.instances[[2]int32{f.envs[1].Id, f.apps[1].Id}])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(none) != 0 {
+		t.Fatalf("expected no deployments, got %+v", none)
+	}
+}
+
+func testPrune(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	a := app.New(s, app.Options{
+		Retention: app.RetentionPolicies{
+			Default: app.RetentionPolicy{MaxAge: 30 * day},
+			Environments: map[string]app.RetentionPolicy{
+				"prod": {Compact: true},
+			},
+		},
+	})
+
+	history := func(env, application int, versions ...string) {
+		t.Helper()
+		id := f.instances[[2]int32{f.envs[env].Id, f.apps[application].Id}]
+		for i, v := range versions {
+			// One deployment a day, ending 100 days ago.
+			at := now.Add(-time.Duration(100+len(versions)-i) * day)
+			if _, _, err := s.RegisterDeployment(ctx, app.Deployment{InstanceId: id, Version: v, DeployedAt: at}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// All of dev is expired, except the current deployment.
+	history(0, 0, "1", "2", "3")
+	// prod keeps the version transitions and the current deployment.
+	history(1, 0, "1", "1", "2", "2", "2")
+
+	plan, err := a.PlanPrune(ctx, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Total != 8 {
+		t.Fatalf("expected 8 deployments in total, got %d", plan.Total)
+	}
+
+	var got []string
+	for _, d := range plan.Pruned {
+		got = append(got, d.Environment+":"+d.Version+":"+d.Reason)
+	}
+	slices.Sort(got)
+	want := []string{
+		"dev:1:" + app.PruneReasonExpired,
+		"dev:2:" + app.PruneReasonExpired,
+		"prod:1:" + app.PruneReasonCompacted,
+		"prod:2:" + app.PruneReasonCompacted,
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected pruned %v, got %v", want, got)
+	}
+
+	// Previewing does not delete anything.
+	deployments, err := s.ListDeployments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deployments) != 8 {
+		t.Fatalf("expected 8 deployments after the preview, got %d", len(deployments))
+	}
+
+	deleted, err := a.Prune(ctx, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 4 {
+		t.Fatalf("expected 4 deleted deployments, got %d", deleted)
+	}
+
+	rows, err := s.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rows {
+		if r.Instance.ApplicationId == f.apps[0].Id && (r.Deployment == nil || r.Deployment.Version == "1") {
+			t.Fatalf("expected the current deployment of %s to be kept, got %+v", r.Instance.Name, r.Deployment)
+		}
+	}
+}
+
+func testDeploymentOrdering(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	byTime := f.instances[[2]int32{f.envs[0].Id, f.apps[0].Id}]
+	bySequence := f.instances[[2]int32{f.envs[0].Id, f.apps[1].Id}]
+	skewed := f.instances[[2]int32{f.envs[1].Id, f.apps[0].Id}]
+	mixed := f.instances[[2]int32{f.envs[1].Id, f.apps[1].Id}]
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// register checks whether the deployment is late, and the version of the current deployment it replaced.
+	register := func(d app.Deployment, wantLate bool, wantReplaced string) {
+		t.Helper()
+		got, replaced, err := s.RegisterDeployment(ctx, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Id == "" || got.Late != wantLate {
+			t.Fatalf("expected %s to be registered with late=%t, got %+v", d.Version, wantLate, got)
+		}
+		if replaced.Version != wantReplaced || (replaced.Id == "") != (wantReplaced == "") {
+			t.Fatalf("expected %s to replace %q, got %+v", d.Version, wantReplaced, replaced)
+		}
+	}
+
+	// An older deployment arriving after a newer one is late.
+	register(app.Deployment{InstanceId: byTime, Version: "2", DeployedAt: now.Add(-time.Hour), ReceivedAt: now}, false, "")
+	register(app.Deployment{InstanceId: byTime, Version: "1", DeployedAt: now.Add(-2 * time.Hour), ReceivedAt: now.Add(time.Minute)}, true, "")
+
+	// The sequence takes precedence over the deployment time.
+	register(app.Deployment{InstanceId: bySequence, Version: "2", DeployedAt: now, ReceivedAt: now, Sequence: 20}, false, "")
+	register(app.Deployment{InstanceId: bySequence, Version: "1", DeployedAt: now.Add(time.Hour), ReceivedAt: now.Add(time.Hour), Sequence: 10}, true, "")
+
+	// A deployment time ahead of the time it was received is capped, it can't hide the deployments after it.
+	register(app.Deployment{InstanceId: skewed, Version: "1", DeployedAt: now.Add(24 * time.Hour), ReceivedAt: now, ClockSkew: true}, false, "")
+	register(app.Deployment{InstanceId: skewed, Version: "2", DeployedAt: now.Add(time.Hour), ReceivedAt: now.Add(time.Hour)}, false, "1")
+
+	// The sequences of different sources can't be compared, their deployments are ordered by time.
+	register(app.Deployment{InstanceId: mixed, Version: "1", DeployedAt: now, ReceivedAt: now, Sequence: 500, Source: "nomad"}, false, "")
+	register(app.Deployment{InstanceId: mixed, Version: "2", DeployedAt: now.Add(time.Hour), ReceivedAt: now.Add(time.Hour), Sequence: 3, Source: "docker"}, false, "1")
+	register(app.Deployment{InstanceId: mixed, Version: "0", DeployedAt: now.Add(-time.Hour), ReceivedAt: now.Add(2 * time.Hour), Sequence: 600, Source: "nomad"}, true, "")
+
+	rows, err := s.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := map[int32]string{}
+	for _, r := range rows {
+		if r.Deployment != nil {
+			current[r.Instance.Id] = r.Deployment.Version
+		}
+	}
+	for _, id := range []int32{byTime, bySequence, skewed, mixed} {
+		if current[id] != "2" {
+			t.Fatalf("expected version 2 to be current on instance %d, got %q", id, current[id])
+		}
+	}
+
+	deployments, err := s.ListDeployments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var late, skew int
+	for _, d := range deployments {
+		if d.ReceivedAt.IsZero() {
+			t.Fatalf("expected the received time to be stored, got %+v", d)
+		}
+		if d.InstanceId == bySequence && d.Sequence == 0 {
+			t.Fatalf("expected the sequence to be stored, got %+v", d)
+		}
+		if d.Late {
+			late++
+		}
+		if d.ClockSkew {
+			skew++
+		}
+	}
+	if late != 3 || skew != 1 {
+		t.Fatalf("expected 3 late and 1 clock skewed deployments, got %d and %d", late, skew)
+	}
+}
+
+func testComponents(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	env, a := f.envs[0], f.apps[0]
+	id := f.instances[[2]int32{env.Id, a.Id}]
+
+	if err := s.UpdateInstance(ctx, app.UpdateInstanceParameters{Id: id, Name: env.Name + "-" + a.Name, PrimaryComponent: "main"}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, tt := range []struct {
+		d            app.Deployment
+		wantReplaced string
+	}{
+		{app.Deployment{Component: "main", Version: "1.0.0"}, ""},
+		{app.Deployment{Component: "sidecar", Version: "0.1.0"}, ""},
+		{app.Deployment{Component: "sidecar", Version: "0.2.0"}, "0.1.0"},
+		// Older than the latest sidecar, but the components are ordered independently.
+		{app.Deployment{Component: "main", Version: "1.1.0", DeployedAt: now.Add(30 * time.Second)}, "1.0.0"},
+	} {
+		d := tt.d
+		d.InstanceId = id
+		if d.DeployedAt.IsZero() {
+			d.DeployedAt = now.Add(time.Duration(i) * time.Minute)
+		}
+
+		got, replaced, err := s.RegisterDeployment(ctx, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Late {
+			t.Fatalf("expected %s %s not to be late", d.Component, d.Version)
+		}
+		if replaced.Version != tt.wantReplaced || (tt.wantReplaced != "" && replaced.Component != d.Component) {
+			t.Fatalf("expected %s %s to replace %q, got %+v", d.Component, d.Version, tt.wantReplaced, replaced)
+		}
+	}
+
+	current, err := s.ListCurrentDeployments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, d := range current {
+		got = append(got, fmt.Sprintf("%d/%s=%s", d.InstanceId, d.Component, d.Version))
+	}
+	want := []string{fmt.Sprintf("%d/main=1.1.0", id), fmt.Sprintf("%d/sidecar=0.2.0", id)}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected the current deployments %v, got %v", want, got)
+	}
+
+	primaryVersion := func() string {
+		t.Helper()
+		rows, err := s.ListInstancesAndDeployment(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range rows {
+			if r.Instance.Id == id && r.Deployment != nil {
+				return r.Deployment.Component + "=" + r.Deployment.Version
+			}
+		}
+		return ""
+	}
+
+	if v := primaryVersion(); v != "main=1.1.0" {
+		t.Fatalf("expected the matrix to show the main component, got %q", v)
+	}
+
+	if err := s.UpdateInstance(ctx, app.UpdateInstanceParameters{Id: id, Name: env.Name + "-" + a.Name, PrimaryComponent: "sidecar"}); err != nil {
+		t.Fatal(err)
+	}
+
+	instance, err := s.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instance.PrimaryComponent != "sidecar" {
+		t.Fatalf("expected the primary component to be updated, got %q", instance.PrimaryComponent)
+	}
+
+	if v := primaryVersion(); v != "sidecar=0.2.0" {
+		t.Fatalf("expected the matrix to show the sidecar component, got %q", v)
+	}
+}
+
+func testUndeployments(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	stopped := f.instances[[2]int32{f.envs[0].Id, f.apps[0].Id}]
+	unseen := f.instances[[2]int32{f.envs[0].Id, f.apps[1].Id}]
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, d := range []app.Deployment{
+		{InstanceId: stopped, Version: "1.0.0", DeployedAt: now, ReceivedAt: now, Sequence: 10},
+		{InstanceId: stopped, DeployedAt: now.Add(time.Hour), ReceivedAt: now.Add(time.Hour), Sequence: 20, Undeployed: true},
+	} {
+		if _, _, err := s.RegisterDeployment(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// An undeployment ordered before the current deployment is late like any other deployment.
+	late, _, err := s.RegisterDeployment(ctx, app.Deployment{InstanceId: stopped, DeployedAt: now.Add(-time.Minute), ReceivedAt: now, Sequence: 5, Undeployed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !late.Late {
+		t.Fatal("expected the undeployment ordered before the current deployment to be late")
+	}
+
+	rows, err := s.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range rows {
+		switch r.Instance.Id {
+		case stopped:
+			if r.Deployment == nil || !r.Deployment.Undeployed || r.Deployment.Version != "" || !r.Deployment.DeployedAt.Equal(now.Add(time.Hour)) {
+				t.Fatalf("expected the stopped instance to be undeployed, got %+v", r.Deployment)
+			}
+		case unseen:
+			if r.Deployment != nil {
+				t.Fatalf("expected the unseen instance to have no deployment, got %+v", r.Deployment)
+			}
+		}
+	}
+
+	deployments, err := s.ListDeployments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var history []string
+	for _, d := range deployments {
+		history = append(history, fmt.Sprintf("%s undeployed=%t", d.Version, d.Undeployed))
+	}
+	want := []string{" undeployed=true", "1.0.0 undeployed=false", " undeployed=true"}
+	if !slices.Equal(history, want) {
+		t.Fatalf("expected the history %v, got %v", want, history)
+	}
+}
+
+func testDeploymentSources(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	id := f.instances[[2]int32{f.envs[0].Id, f.apps[0].Id}]
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, d := range []app.Deployment{
+		{InstanceId: id, Version: "1.0.0", DeployedAt: now, ReceivedAt: now},
+		{InstanceId: id, Version: "1.1.0", DeployedAt: now.Add(time.Minute), ReceivedAt: now.Add(time.Minute), Source: "nomad-eu"},
+	} {
+		if _, _, err := s.RegisterDeployment(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deployments, err := s.ListDeployments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sources []string
+	for _, d := range deployments {
+		sources = append(sources, d.Version+" "+d.Source)
+	}
+	if want := []string{"1.0.0 ", "1.1.0 nomad-eu"}; !slices.Equal(sources, want) {
+		t.Fatalf("expected the sources %q, got %q", want, sources)
+	}
+
+	rows, err := s.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range rows {
+		if r.Instance.Id == id && (r.Deployment == nil || r.Deployment.Source != "nomad-eu") {
+			t.Fatalf("expected the current deployment to be from nomad-eu, got %+v", r.Deployment)
+		}
+	}
+}
+
+func testDeployedVersions(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	id := f.instances[[2]int32{f.envs[0].Id, f.apps[0].Id}]
+	other := f.instances[[2]int32{f.envs[1].Id, f.apps[0].Id}]
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, d := range []app.Deployment{
+		{InstanceId: id, Version: "1.1.0"},
+		{InstanceId: id, Version: "1.0.0"},
+		{InstanceId: id, Version: "1.1.0"},
+		{InstanceId: id, Undeployed: true},
+		{InstanceId: id, Component: "sidecar", Version: "0.1.0"},
+		{InstanceId: other, Version: "2.0.0"},
+	} {
+		d.DeployedAt = now.Add(time.Duration(i) * time.Minute)
+		if _, _, err := s.RegisterDeployment(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range []struct {
+		instance  int32
+		component string
+		want      []string
+	}{
+		{id, "", []string{"1.0.0", "1.1.0"}},
+		{id, "sidecar", []string{"0.1.0"}},
+		{other, "", []string{"2.0.0"}},
+		{other, "sidecar", nil},
+	} {
+		got, err := s.ListDeployedVersions(ctx, tt.instance, tt.component)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Fatalf("expected the versions %v of %d/%s, got %v", tt.want, tt.instance, tt.component, got)
+		}
+	}
+}
+
+func testRollouts(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	id := f.instances[[2]int32{f.envs[0].Id, f.apps[0].Id}]
+	deleted := f.instances[[2]int32{f.envs[1].Id, f.apps[0].Id}]
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	update := func(r app.Rollout, wantUpdated bool) {
+		t.Helper()
+		updated, err := s.UpdateRollout(ctx, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated != wantUpdated {
+			t.Fatalf("expected the %s rollout of %s to be updated=%t", r.Status, r.Version, wantUpdated)
+		}
+	}
+
+	update(app.Rollout{InstanceId: id, Component: "main", Version: "2", Status: app.RolloutRunning, Healthy: 1, Desired: 3, UpdatedAt: now, Sequence: 10}, true)
+	update(app.Rollout{InstanceId: id, Component: "main", Version: "2", Status: app.RolloutSuccessful, Healthy: 3, Desired: 3, RunningVersion: "2", UpdatedAt: now.Add(time.Minute), Sequence: 11}, true)
+	// The running version is kept while the next version is rolled out.
+	update(app.Rollout{InstanceId: id, Component: "main", Version: "3", Status: app.RolloutFailed, Description: "unhealthy", Desired: 3, UpdatedAt: now.Add(2 * time.Minute), Sequence: 12}, true)
+	// An update ordered before the stored one is ignored.
+	update(app.Rollout{InstanceId: id, Component: "main", Version: "1", Status: app.RolloutSuccessful, RunningVersion: "1", UpdatedAt: now.Add(time.Hour), Sequence: 5}, false)
+	update(app.Rollout{InstanceId: id, Component: "sidecar", Version: "0.1", Status: app.RolloutPending, UpdatedAt: now}, true)
+	update(app.Rollout{InstanceId: deleted, Component: "main", Version: "1", Status: app.RolloutRunning, UpdatedAt: now}, true)
+	// The sequences of different sources are not compared, the rollouts are ordered by time instead.
+	update(app.Rollout{InstanceId: id, Component: "worker", Version: "1", Status: app.RolloutRunning, UpdatedAt: now, Sequence: 100, Source: "nomad-eu"}, true)
+	update(app.Rollout{InstanceId: id, Component: "worker", Version: "2", Status: app.RolloutRunning, UpdatedAt: now.Add(time.Minute), Sequence: 5, Source: "nomad-us"}, true)
+	update(app.Rollout{InstanceId: id, Component: "worker", Version: "1", Status: app.RolloutSuccessful, UpdatedAt: now, Sequence: 200, Source: "nomad-eu"}, false)
+	update(app.Rollout{InstanceId: id, Component: "worker", Version: "2", Status: app.RolloutSuccessful, RunningVersion: "2", UpdatedAt: now, Sequence: 6, Source: "nomad-us"}, true)
+
+	if _, err := s.UpdateRollout(ctx, app.Rollout{InstanceId: 9999, Component: "main", Version: "1", Status: app.RolloutRunning, UpdatedAt: now}); !errors.Is(err, app.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown instance, got %v", err)
+	}
+
+	if err := s.DeleteInstance(ctx, deleted); err != nil {
+		t.Fatal(err)
+	}
+
+	rollouts, err := s.ListRollouts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []app.Rollout{
+		{InstanceId: id, Component: "main", Version: "3", Status: app.RolloutFailed, Description: "unhealthy", Desired: 3, RunningVersion: "2", UpdatedAt: now.Add(2 * time.Minute), Sequence: 12},
+		{InstanceId: id, Component: "sidecar", Version: "0.1", Status: app.RolloutPending, UpdatedAt: now},
+		{InstanceId: id, Component: "worker", Version: "2", Status: app.RolloutSuccessful, RunningVersion: "2", UpdatedAt: now, Sequence: 6, Source: "nomad-us"},
+	}
+	if len(rollouts) != len(want) {
+		t.Fatalf("expected %d rollouts, got %+v", len(want), rollouts)
+	}
+	for i := range want {
+		rollouts[i].UpdatedAt = rollouts[i].UpdatedAt.UTC()
+		if !reflect.DeepEqual(rollouts[i], want[i]) {
+			t.Fatalf("expected the rollout %+v, got %+v", want[i], rollouts[i])
+		}
+	}
+}
+
+func testReleases(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	api, web := f.apps[0].Id, f.apps[1].Id
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	save := func(r app.Release) {
+		t.Helper()
+		if err := s.SaveRelease(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	save(app.Release{ApplicationId: web, Repository: "registry.example.com/web", Version: "1.0.0", CheckedAt: now})
+	save(app.Release{ApplicationId: api, Repository: "registry.example.com/api", Version: "2.1.0", CheckedAt: now})
+	// A later release replaces the stored one, even from another repository.
+	save(app.Release{ApplicationId: web, Repository: "registry.example.com/team/web", Version: "1.1.0", CheckedAt: now.Add(time.Hour)})
+
+	if err := s.SaveRelease(ctx, app.Release{ApplicationId: 9999, Repository: "registry.example.com/gone", Version: "1.0.0", CheckedAt: now}); !errors.Is(err, app.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown application, got %v", err)
+	}
+
+	releases, err := s.ListReleases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []app.Release{
+		{ApplicationId: api, Repository: "registry.example.com/api", Version: "2.1.0", CheckedAt: now},
+		{ApplicationId: web, Repository: "registry.example.com/team/web", Version: "1.1.0", CheckedAt: now.Add(time.Hour)},
+	}
+	if len(releases) != len(want) {
+		t.Fatalf("expected %d releases, got %+v", len(want), releases)
+	}
+	for i := range want {
+		releases[i].CheckedAt = releases[i].CheckedAt.UTC()
+		if !reflect.DeepEqual(releases[i], want[i]) {
+			t.Fatalf("expected the release %+v, got %+v", want[i], releases[i])
+		}
+	}
+
+	// The release of a deleted application is deleted with it.
+	if err := s.DeleteApplication(ctx, api); err != nil {
+		t.Fatal(err)
+	}
+
+	releases, err = s.ListReleases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 1 || releases[0].ApplicationId != web {
+		t.Fatalf("expected only the release of web to be left, got %+v", releases)
+	}
+}
+
+func testPromotions(t *testing.T, s app.Store) {
+	ctx := context.Background()
+
+	envs := map[string]int32{}
+	for _, name := range []string{"dev", "staging", "prod-eu", "prod-us"} {
+		env, err := s.CreateEnvironment(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		envs[name] = env.Id
+	}
+
+	set := func(to string, from ...string) {
+		t.Helper()
+		var ids []int32
+		for _, name := range from {
+			ids = append(ids, envs[name])
+		}
+		if err := s.SetPromotionSources(ctx, envs[to], ids); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expect := func(want ...app.PromotionEdge) {
+		t.Helper()
+		edges, err := s.ListPromotions(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(edges, want) {
+			t.Fatalf("expected the promotions %+v, got %+v", want, edges)
+		}
+	}
+
+	set("prod-us", "staging")
+	set("prod-eu", "dev")
+	set("staging", "dev")
+	// Setting the sources again replaces them.
+	set("prod-eu", "staging")
+
+	expect(
+		app.PromotionEdge{FromEnvironmentId: envs["dev"], ToEnvironmentId: envs["staging"]},
+		app.PromotionEdge{FromEnvironmentId: envs["staging"], ToEnvironmentId: envs["prod-eu"]},
+		app.PromotionEdge{FromEnvironmentId: envs["staging"], ToEnvironmentId: envs["prod-us"]},
+	)
+
+	err := s.SetPromotionSources(ctx, envs["prod-us"], []int32{9999})
+	expectErr(t, err, app.ErrNotFound)
+
+	// The promotions of a deleted environment are deleted with it, in both directions.
+	if err := s.DeleteEnvironment(ctx, envs["staging"]); err != nil {
+		t.Fatal(err)
+	}
+	expect()
+
+	set("prod-us", "dev")
+	set("prod-us")
+	expect()
+}
+
+func testVersionPolicies(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	api, web := f.apps[0].Id, f.apps[1].Id
+	devApi := f.instances[[2]int32{f.envs[0].Id, api}]
+	prodApi := f.instances[[2]int32{f.envs[1].Id, api}]
+
+	save := func(p app.VersionPolicy) {
+		t.Helper()
+		if err := s.SaveVersionPolicy(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	save(app.VersionPolicy{InstanceId: prodApi, Pinned: "1.2.0"})
+	save(app.VersionPolicy{ApplicationId: web, Allowed: "^2"})
+	save(app.VersionPolicy{ApplicationId: api, Allowed: ">=1.0.0, <2.0.0", Forbidden: []string{"1.3.0"}})
+	save(app.VersionPolicy{InstanceId: devApi, Forbidden: []string{"1.4.0", "1.5.0"}, NotAheadOfUpstream: true})
+	// Saving a policy again replaces it.
+	save(app.VersionPolicy{ApplicationId: web, Allowed: "^3", NotAheadOfUpstream: true})
+
+	err := s.SaveVersionPolicy(ctx, app.VersionPolicy{ApplicationId: 9999, Pinned: "1.0.0"})
+	expectErr(t, err, app.ErrNotFound)
+	err = s.SaveVersionPolicy(ctx, app.VersionPolicy{InstanceId: 9999, Pinned: "1.0.0"})
+	expectErr(t, err, app.ErrNotFound)
+
+	expect := func(want ...app.VersionPolicy) {
+		t.Helper()
+		policies, err := s.ListVersionPolicies(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(policies) != len(want) {
+			t.Fatalf("expected the policies %+v, got %+v", want, policies)
+		}
+		for i := range want {
+			if !reflect.DeepEqual(policies[i], want[i]) {
+				t.Fatalf("expected the policy %+v, got %+v", want[i], policies[i])
+			}
+		}
+	}
+
+	// The policy of an application is listed before the ones of its instances, which know their application.
+	expect(
+		app.VersionPolicy{ApplicationId: api, Allowed: ">=1.0.0, <2.0.0", Forbidden: []string{"1.3.0"}},
+		app.VersionPolicy{ApplicationId: api, InstanceId: devApi, Forbidden: []string{"1.4.0", "1.5.0"}, NotAheadOfUpstream: true},
+		app.VersionPolicy{ApplicationId: api, InstanceId: prodApi, Pinned: "1.2.0"},
+		app.VersionPolicy{ApplicationId: web, Allowed: "^3", NotAheadOfUpstream: true},
+	)
+
+	// Deleting the policy of an application keeps the ones of its instances.
+	if err := s.DeleteVersionPolicy(ctx, api, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteVersionPolicy(ctx, api, prodApi); err != nil {
+		t.Fatal(err)
+	}
+	expect(
+		app.VersionPolicy{ApplicationId: api, InstanceId: devApi, Forbidden: []string{"1.4.0", "1.5.0"}, NotAheadOfUpstream: true},
+		app.VersionPolicy{ApplicationId: web, Allowed: "^3", NotAheadOfUpstream: true},
+	)
+
+	// The policies of deleted instances and applications are deleted with them.
+	if err := s.DeleteInstance(ctx, devApi); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteApplication(ctx, web); err != nil {
+		t.Fatal(err)
+	}
+	expect()
+}
+
+func testPolicyViolations(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	id := f.instances[[2]int32{f.envs[0].Id, f.apps[0].Id}]
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	var deployments []app.Deployment
+	for i, v := range []string{"1.0.0", "2.0.0"} {
+		d, _, err := s.RegisterDeployment(ctx, app.Deployment{InstanceId: id, Version: v, DeployedAt: base.Add(time.Duration(i) * time.Hour)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		deployments = append(deployments, d)
+	}
+
+	violation := func(d app.Deployment, rule string, at time.Duration) app.PolicyViolation {
+		return app.PolicyViolation{
+			DeploymentId: d.Id,
+			InstanceId:   d.InstanceId,
+			Version:      d.Version,
+			Rule:         rule,
+			Message:      rule + " " + d.Version,
+			DetectedAt:   base.Add(at),
+		}
+	}
+
+	if err := s.AddPolicyViolations(ctx, []app.PolicyViolation{
+		violation(deployments[1], app.RulePinned, time.Hour),
+		violation(deployments[0], app.RuleForbidden, 0),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// A violation of a rule that is already recorded for the deployment is ignored.
+	if err := s.AddPolicyViolations(ctx, []app.PolicyViolation{violation(deployments[1], app.RulePinned, 2*time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	unknown := violation(deployments[0], app.RuleAllowed, 0)
+	unknown.DeploymentId = uuid.NewString()
+	err := s.AddPolicyViolations(ctx, []app.PolicyViolation{unknown})
+	expectErr(t, err, app.ErrNotFound)
+
+	expect := func(want ...app.PolicyViolation) {
+		t.Helper()
+		violations, err := s.ListPolicyViolations(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(violations) != len(want) {
+			t.Fatalf("expected the violations %+v, got %+v", want, violations)
+		}
+		for i := range want {
+			violations[i].DetectedAt = violations[i].DetectedAt.UTC()
+			if !reflect.DeepEqual(violations[i], want[i]) {
+				t.Fatalf("expected the violation %+v, got %+v", want[i], violations[i])
+			}
+		}
+	}
+
+	expect(violation(deployments[0], app.RuleForbidden, 0), violation(deployments[1], app.RulePinned, time.Hour))
+
+	// The violations of deleted deployments and instances are deleted with them.
+	if _, err := s.DeleteDeployments(ctx, []string{deployments[0].Id}); err != nil {
+		t.Fatal(err)
+	}
+	expect(violation(deployments[1], app.RulePinned, time.Hour))
+
+	if err := s.DeleteInstance(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	expect()
+}
+
+func testFreezeWindows(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	starts := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	windows := []app.FreezeWindow{
+		{Name: "holidays", Reason: "Christmas", EnvironmentId: f.envs[1].Id, StartsAt: starts, EndsAt: starts.AddDate(0, 0, 14), TimeZone: "UTC", Reject: true},
+		{Name: "weekends", ApplicationId: f.apps[0].Id, Schedule: "0 18 * * FRI", DurationSeconds: 60 * 60 * 62, TimeZone: "Europe/Stockholm"},
+		{Name: "launch", StartsAt: starts, EndsAt: starts.Add(time.Hour), TimeZone: "UTC"},
+	}
+	for i, w := range windows {
+		id, err := s.CreateFreezeWindow(ctx, w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		windows[i].Id = id
+	}
+
+	expect := func(want []app.FreezeWindow) {
+		t.Helper()
+		got, err := s.ListFreezeWindows(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range got {
+			got[i].StartsAt, got[i].EndsAt = got[i].StartsAt.UTC(), got[i].EndsAt.UTC()
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("expected the freeze windows %+v, got %+v", want, got)
+		}
+	}
+	expect(windows)
+
+	_, err := s.CreateFreezeWindow(ctx, app.FreezeWindow{Name: "launch", Schedule: "* * * * *", DurationSeconds: 60, TimeZone: "UTC"})
+	expectErr(t, err, app.ErrAlreadyExists)
+
+	_, err = s.CreateFreezeWindow(ctx, app.FreezeWindow{Name: "missing", EnvironmentId: 9999, StartsAt: starts, EndsAt: starts.Add(time.Hour), TimeZone: "UTC"})
+	expectErr(t, err, app.ErrNotFound)
+
+	windows[2].Name = "big launch"
+	windows[2].EnvironmentId = f.envs[0].Id
+	windows[2].Reject = true
+	if err := s.UpdateFreezeWindow(ctx, windows[2]); err != nil {
+		t.Fatal(err)
+	}
+	expect(windows)
+
+	err = s.UpdateFreezeWindow(ctx, app.FreezeWindow{Id: 9999, Name: "missing", StartsAt: starts, EndsAt: starts.Add(time.Hour), TimeZone: "UTC"})
+	expectErr(t, err, app.ErrNotFound)
+
+	err = s.UpdateFreezeWindow(ctx, app.FreezeWindow{Id: windows[2].Id, Name: "holidays", StartsAt: starts, EndsAt: starts.Add(time.Hour), TimeZone: "UTC"})
+	expectErr(t, err, app.ErrAlreadyExists)
+
+	// The freeze window a deployment was made in is kept with it.
+	id := f.instances[[2]int32{f.envs[1].Id, f.apps[0].Id}]
+	if _, _, err := s.RegisterDeployment(ctx, app.Deployment{InstanceId: id, Version: "1.0.0", DeployedAt: starts, ReceivedAt: starts, Freeze: "holidays"}); err != nil {
+		t.Fatal(err)
+	}
+
+	deployments, err := s.ListDeployments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deployments) != 1 || deployments[0].Freeze != "holidays" {
+		t.Fatalf("expected a deployment in the holidays freeze, got %+v", deployments)
+	}
+
+	rows, err := s.ListInstancesAndDeployment(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rows {
+		if r.Instance.Id == id && (r.Deployment == nil || r.Deployment.Freeze != "holidays") {
+			t.Fatalf("expected the current deployment to be in the holidays freeze, got %+v", r.Deployment)
+		}
+	}
+
+	// The freeze windows of deleted environments and applications are deleted with them.
+	if err := s.DeleteEnvironment(ctx, f.envs[1].Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteApplication(ctx, f.apps[0].Id); err != nil {
+		t.Fatal(err)
+	}
+	expect(windows[2:])
+
+	if err := s.DeleteFreezeWindow(ctx, windows[2].Id); err != nil {
+		t.Fatal(err)
+	}
+	expect(nil)
+}
+
+func testWebhooks(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	webhooks := []app.Webhook{
+		{Name: "smoke tests", URL: "https://ci.example.com/hook", Secret: "s3cret", Events: []string{app.EventDeployment}, EnvironmentId: f.envs[1].Id, CreatedAt: created},
+		{Name: "audit", URL: "http://audit.example.com", ApplicationId: f.apps[0].Id, Disabled: true, CreatedAt: created},
+		{Name: "everything", URL: "http://example.com", CreatedAt: created},
+	}
+	for i, w := range webhooks {
+		id, err := s.CreateWebhook(ctx, w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		webhooks[i].Id = id
+	}
+
+	expect := func(want []app.Webhook) {
+		t.Helper()
+		got, err := s.ListWebhooks(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range got {
+			got[i].CreatedAt = got[i].CreatedAt.UTC()
+		}
+		if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+			t.Fatalf("expected the webhooks %+v, got %+v", want, got)
+		}
+	}
+	expect(webhooks)
+
+	_, err := s.CreateWebhook(ctx, app.Webhook{Name: "audit", URL: "http://example.com", CreatedAt: created})
+	expectErr(t, err, app.ErrAlreadyExists)
+
+	_, err = s.CreateWebhook(ctx, app.Webhook{Name: "missing", URL: "http://example.com", ApplicationId: 9999, CreatedAt: created})
+	expectErr(t, err, app.ErrNotFound)
+
+	// The time a webhook was created is kept when it is updated.
+	webhooks[2].Name = "all events"
+	webhooks[2].Events = []string{app.EventRollout, app.EventRelease}
+	webhooks[2].EnvironmentId = f.envs[0].Id
+	webhooks[2].Disabled = true
+	update := webhooks[2]
+	update.CreatedAt = created.Add(time.Hour)
+	if err := s.UpdateWebhook(ctx, update); err != nil {
+		t.Fatal(err)
+	}
+	expect(webhooks)
+
+	err = s.UpdateWebhook(ctx, app.Webhook{Id: 9999, Name: "missing", URL: "http://example.com"})
+	expectErr(t, err, app.ErrNotFound)
+
+	err = s.UpdateWebhook(ctx, app.Webhook{Id: webhooks[2].Id, Name: "audit", URL: "http://example.com"})
+	expectErr(t, err, app.ErrAlreadyExists)
+
+	// The webhooks of deleted environments and applications are deleted with them.
+	if err := s.DeleteEnvironment(ctx, f.envs[1].Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteApplication(ctx, f.apps[0].Id); err != nil {
+		t.Fatal(err)
+	}
+	expect(webhooks[2:])
+
+	if err := s.DeleteWebhook(ctx, webhooks[2].Id); err != nil {
+		t.Fatal(err)
+	}
+	expect(nil)
+}
+
+func testWebhookDeliveries(t *testing.T, s app.Store) {
+	ctx := context.Background()
+
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	var hooks []int32
+	for _, name := range []string{"first", "second"} {
+		id, err := s.CreateWebhook(ctx, app.Webhook{Name: name, URL: "http://example.com", CreatedAt: created})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hooks = append(hooks, id)
+	}
+
+	newDelivery := func(webhookId int32, createdAt time.Time) app.WebhookDelivery {
+		return app.WebhookDelivery{
+			Id:            uuid.NewString(),
+			WebhookId:     webhookId,
+			EventId:       uuid.NewString(),
+			Event:         app.EventDeployment,
+			Payload:       []byte(`{"type":"deployment"}`),
+			Status:        app.DeliveryPending,
+			CreatedAt:     createdAt,
+			NextAttemptAt: createdAt,
+		}
+	}
+	deliveries := []app.WebhookDelivery{
+		newDelivery(hooks[0], created),
+		newDelivery(hooks[1], created.Add(time.Minute)),
+		newDelivery(hooks[0], created.Add(2*time.Minute)),
+	}
+	if err := s.AddWebhookDeliveries(ctx, deliveries); err != nil {
+		t.Fatal(err)
+	}
+
+	err := s.AddWebhookDeliveries(ctx, []app.WebhookDelivery{newDelivery(9999, created)})
+	expectErr(t, err, app.ErrNotFound)
+
+	normalize := func(got []app.WebhookDelivery) []app.WebhookDelivery {
+		for i := range got {
+			got[i].CreatedAt = got[i].CreatedAt.UTC()
+			got[i].NextAttemptAt = got[i].NextAttemptAt.UTC()
+			got[i].LastAttemptAt = got[i].LastAttemptAt.UTC()
+		}
+		return got
+	}
+	expectDeliveries := func(webhookId, limit int32, want ...app.WebhookDelivery) {
+		t.Helper()
+		got, err := s.ListWebhookDeliveries(ctx, webhookId, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got = normalize(got); len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+			t.Fatalf("expected the deliveries %+v, got %+v", want, got)
+		}
+	}
+	expectDue := func(at time.Time, want ...app.WebhookDelivery) {
+		t.Helper()
+		got, err := s.ListDueWebhookDeliveries(ctx, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got = normalize(got); len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+			t.Fatalf("expected the due deliveries %+v, got %+v", want, got)
+		}
+	}
+
+	// The most recent deliveries are listed first.
+	expectDeliveries(0, 10, deliveries[2], deliveries[1], deliveries[0])
+	expectDeliveries(0, 2, deliveries[2], deliveries[1])
+	expectDeliveries(hooks[0], 10, deliveries[2], deliveries[0])
+
+	got, err := s.GetWebhookDelivery(ctx, deliveries[1].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got = normalize([]app.WebhookDelivery{got})[0]; !reflect.DeepEqual(got, deliveries[1]) {
+		t.Fatalf("expected the delivery %+v, got %+v", deliveries[1], got)
+	}
+
+	_, err = s.GetWebhookDelivery(ctx, uuid.NewString())
+	expectErr(t, err, app.ErrNotFound)
+
+	expectDue(created.Add(time.Minute), deliveries[0], deliveries[1])
+
+	// A failed attempt is retried later, a successful one is no longer due.
+	deliveries[0].Attempts = 1
+	deliveries[0].ResponseStatus = 500
+	deliveries[0].Error = "unexpected response status 500"
+	deliveries[0].LastAttemptAt = created.Add(3 * time.Minute)
+	deliveries[0].NextAttemptAt = created.Add(5 * time.Minute)
+	deliveries[1].Status = app.DeliverySucceeded
+	deliveries[1].Attempts = 1
+	deliveries[1].ResponseStatus = 204
+	deliveries[1].LastAttemptAt = created.Add(3 * time.Minute)
+	deliveries[1].NextAttemptAt = time.Time{}
+	for _, d := range deliveries[:2] {
+		if err := s.UpdateWebhookDelivery(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectDue(created.Add(4*time.Minute), deliveries[2])
+	expectDue(created.Add(5*time.Minute), deliveries[2], deliveries[0])
+
+	err = s.UpdateWebhookDelivery(ctx, newDelivery(hooks[0], created))
+	expectErr(t, err, app.ErrNotFound)
+
+	// Only the deliveries that are no longer pending are pruned.
+	n, err := s.DeleteWebhookDeliveries(ctx, created.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 pruned delivery, got %d", n)
+	}
+	expectDeliveries(0, 10, deliveries[2], deliveries[0])
+
+	// The deliveries of deleted webhooks are deleted with them.
+	if err := s.DeleteWebhook(ctx, hooks[0]); err != nil {
+		t.Fatal(err)
+	}
+	expectDeliveries(0, 10)
+}
+
+func testNotificationChannels(t *testing.T, s app.Store) {
+	ctx := context.Background()
+	f := newFixture(t, s)
+
+	channels := []app.NotificationChannel{
+		{
+			Name:         "on-call",
+			Kind:         app.ChannelSlack,
+			URL:          "https://hooks.slack.com/services/T0/B0/X",
+			BatchSeconds: 30,
+			Routes: []app.NotificationRoute{
+				{EnvironmentId: f.envs[1].Id, Events: []string{app.EventDeployment}},
+				{ApplicationId: f.apps[0].Id, Events: []string{app.EventPolicyViolation, app.EventRelease}, Redeploys: true},
+			},
+		},
+		{Name: "releases", Kind: app.ChannelTeams, URL: "https://example.webhook.office.com/x", Template: "{{.Type}}", Disabled: true},
+		{Name: "everything", Kind: app.ChannelGeneric, URL: "http://example.com", Routes: []app.NotificationRoute{{}}},
+	}
+	for i, c := range channels {
+		id, err := s.CreateNotificationChannel(ctx, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		channels[i].Id = id
+	}
+
+	expect := func(want []app.NotificationChannel) {
+		t.Helper()
+		got, err := s.ListNotificationChannels(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+			t.Fatalf("expected the notification channels %+v, got %+v", want, got)
+		}
+	}
+	expect(channels)
+
+	_, err := s.CreateNotificationChannel(ctx, app.NotificationChannel{Name: "releases", Kind: app.ChannelSlack, URL: "http://example.com"})
+	expectErr(t, err, app.ErrAlreadyExists)
+
+	_, err = s.CreateNotificationChannel(ctx, app.NotificationChannel{Name: "missing", Kind: app.ChannelSlack, URL: "http://example.com", Routes: []app.NotificationRoute{{EnvironmentId: 9999}}})
+	expectErr(t, err, app.ErrNotFound)
+
+	// The routes are replaced together with the channel.
+	channels[1].Routes = []app.NotificationRoute{{ApplicationId: f.apps[1].Id, Events: []string{app.EventRelease}}}
+	channels[1].Disabled = false
+	channels[2].Routes = nil
+	channels[2].BatchSeconds = 5
+	for _, c := range channels[1:] {
+		if err := s.UpdateNotificationChannel(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect(channels)
+
+	err = s.UpdateNotificationChannel(ctx, app.NotificationChannel{Id: 9999, Name: "missing", Kind: app.ChannelSlack, URL: "http://example.com"})
+	expectErr(t, err, app.ErrNotFound)
+
+	err = s.UpdateNotificationChannel(ctx, app.NotificationChannel{Id: channels[2].Id, Name: "on-call", Kind: app.ChannelSlack, URL: "http://example.com"})
+	expectErr(t, err, app.ErrAlreadyExists)
+
+	// The routes of deleted environments and applications are deleted with them, the channels are kept.
+	if err := s.DeleteEnvironment(ctx, f.envs[1].Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteApplication(ctx, f.apps[1].Id); err != nil {
+		t.Fatal(err)
+	}
+	channels[0].Routes = channels[0].Routes[1:]
+	channels[1].Routes = nil
+	expect(channels)
+
+	if err := s.DeleteNotificationChannel(ctx, channels[0].Id); err != nil {
+		t.Fatal(err)
+	}
+	expect(channels[1:])
+}